@@ -0,0 +1,90 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/go-juicedev/juice/sql"
+)
+
+// actionGuardAttribute is the statement attribute and setting disabling the action guard with "false".
+const actionGuardAttribute = "actionGuard"
+
+// ErrActionMismatch is returned when the rendered query of a mapped statement does not
+// match its action, like a select statement starting with DELETE.
+var ErrActionMismatch = errors.New("juice: statement action does not match its query")
+
+// actionGuard reports whether the query of statement is checked against its action.
+// It is enabled by default for the statements of the mappers, the attribute of the statement
+// takes precedence over the actionGuard setting.
+// The other statements, like the raw statements of the runners, are not checked since their
+// action is chosen by the method called, like QueryContext for an INSERT with a RETURNING clause.
+func actionGuard(statement Statement, engine *Engine) bool {
+	if _, mapped := statement.(*mappedStatement); !mapped {
+		return false
+	}
+	if attribute := statement.Attribute(actionGuardAttribute); attribute != "" {
+		return attribute != "false"
+	}
+	configuration := engine.GetConfiguration()
+	if configuration == nil {
+		return true
+	}
+	return configuration.Settings().Get(actionGuardAttribute) != "false"
+}
+
+// checkStatementAction returns ErrActionMismatch if a select statement starts with
+// INSERT, UPDATE or DELETE, or if an insert, update or delete statement starts with SELECT.
+// The other keywords, like WITH or CALL, are not checked.
+func checkStatementAction(statement Statement, query string) error {
+	keyword := strings.ToUpper(leadingKeyword(query))
+	var mismatch bool
+	switch statement.Action() {
+	case sql.Select:
+		mismatch = keyword == "INSERT" || keyword == "UPDATE" || keyword == "DELETE"
+	case sql.Insert, sql.Update, sql.Delete:
+		mismatch = keyword == "SELECT"
+	default:
+	}
+	if mismatch {
+		return fmt.Errorf("%w: %s statement %s starts with %s", ErrActionMismatch, statement.Action(), statement.Name(), keyword)
+	}
+	return nil
+}
+
+// leadingKeyword returns the first word of query, skipping the spaces, the comments and the opening parentheses.
+func leadingKeyword(query string) string {
+	for {
+		query = strings.TrimLeftFunc(query, func(r rune) bool { return unicode.IsSpace(r) || r == '(' })
+		switch {
+		case strings.HasPrefix(query, "--"):
+			_, query, _ = strings.Cut(query, "\n")
+		case strings.HasPrefix(query, "/*"):
+			_, query, _ = strings.Cut(query[2:], "*/")
+		default:
+			end := strings.IndexFunc(query, func(r rune) bool { return !unicode.IsLetter(r) })
+			if end < 0 {
+				return query
+			}
+			return query[:end]
+		}
+	}
+}
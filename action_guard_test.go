@@ -0,0 +1,74 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLeadingKeyword_action_guard_test(t *testing.T) {
+	tests := map[string]string{
+		"SELECT 1":                             "SELECT",
+		"\n  delete FROM user":                 "delete",
+		"-- find users\n/* hint */ (SELECT 1)": "SELECT",
+		"/* unterminated":                      "",
+		"":                                     "",
+	}
+	for query, expected := range tests {
+		if keyword := leadingKeyword(query); keyword != expected {
+			t.Errorf("leadingKeyword(%q) = %q, expected %q", query, keyword, expected)
+		}
+	}
+}
+
+func TestActionGuard_action_guard_test(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`
+<configuration>
+    <environments default="prod">
+        <environment id="prod"><driver>sqlite3</driver><dataSource>dsn</dataSource></environment>
+    </environments>
+    <mappers>
+        <mapper namespace="user">
+            <select id="Find">SELECT * FROM user</select>
+            <select id="Purge">DELETE FROM user</select>
+            <select id="Returning" actionGuard="false">INSERT INTO user (name) VALUES ('a') RETURNING id</select>
+            <delete id="Delete">/* copy */ select * FROM user</delete>
+            <update id="Touch">WITH stale AS (SELECT id FROM user) UPDATE user SET name = ''</update>
+        </mapper>
+    </mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := newStatementTestEngine(nil)
+
+	build := func(id string) error {
+		t.Helper()
+		statement, err := configuration.GetStatement(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _, err = buildStatementQuery(context.Background(), statement, engine, nil)
+		return err
+	}
+	for id, mismatch := range map[string]bool{
+		"user.Find":      false,
+		"user.Purge":     true,
+		"user.Returning": false,
+		"user.Delete":    true,
+		"user.Touch":     false,
+	} {
+		if err := build(id); errors.Is(err, ErrActionMismatch) != mismatch {
+			t.Errorf("%s: expected mismatch %v, got %v", id, mismatch, err)
+		}
+	}
+
+	engine.configuration.(*xmlConfiguration).settings["actionGuard"] = "false"
+	if err := build("user.Purge"); err != nil {
+		t.Fatalf("expected the actionGuard setting to disable the guard, got %v", err)
+	}
+}
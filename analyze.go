@@ -0,0 +1,40 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import "github.com/go-juicedev/juice/sql/analyzer"
+
+// AnalyzeStatements returns the tables and the columns referenced by the statements of the engine,
+// keyed by statement name, to find the statements impacted by a schema change or to invalidate
+// caches by table. The skeletons of the statements are analyzed, see Describe: every branch of
+// their dynamic elements is taken into account, but not the fragments they include.
+// The analyses of the databaseId variants of a statement are merged.
+func (e *Engine) AnalyzeStatements() (map[string]*analyzer.Analysis, error) {
+	description, err := e.Describe()
+	if err != nil {
+		return nil, err
+	}
+	analyses := make(map[string]*analyzer.Analysis, len(description.Statements))
+	for _, statement := range description.Statements {
+		analysis := analyzer.Analyze(statement.SQL)
+		if previous, ok := analyses[statement.Name]; ok {
+			analysis = previous.Merge(analysis)
+		}
+		analyses[statement.Name] = analysis
+	}
+	return analyses, nil
+}
@@ -0,0 +1,87 @@
+package juice
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestStatementAttributes_attribute_test(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>sqlite.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>
+		<mapper namespace="user" audit="users" xmlns:app="urn:app">
+			<select id="Find" audit="select" cacheTTL="60">SELECT * FROM user</select>
+			<select id="Count" app:cacheTTL="30">SELECT COUNT(*) FROM user</select>
+			<insert id="Save" batchSize="2">INSERT INTO user(id) VALUES (1)</insert>
+		</mapper>
+	</mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	statement := func(id string) Statement {
+		statement, err := configuration.GetStatement(id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return statement
+	}
+	find, count, save := statement("user.Find"), statement("user.Count"), statement("user.Save")
+
+	for _, tc := range []struct {
+		statement Statement
+		key, want string
+	}{
+		{find, "cacheTTL", "60"},
+		{find, "audit", "select"},
+		{count, "audit", "users"},
+		{count, "cacheTTL", "30"},
+		{count, "app", ""},
+		{save, "audit", "users"},
+		{save, "cacheTTL", ""},
+	} {
+		if got := tc.statement.Attribute(tc.key); got != tc.want {
+			t.Errorf("%s: expected attribute %s %q, got %q", tc.statement.ID(), tc.key, tc.want, got)
+		}
+	}
+
+	var seen []string
+	observe := shObserveMiddleware{
+		queryFn: func(ctx *StatementContext) { seen = append(seen, ctx.Statement().Attribute("audit")) },
+		execFn:  func(ctx *StatementContext) { seen = append(seen, ctx.Statement().Attribute("audit")) },
+	}
+	db := openStatementTestDB(t, &shSQLDriverState{})
+	engine := newStatementTestEngine(db, observe)
+	engine.configuration = configuration
+	ctx := context.Background()
+
+	if _, err = newQueryBuildStatementHandler(engine, db).QueryContext(ctx, find, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = newPreparedStatementHandler(db, engine).QueryContext(ctx, find, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = newBatchStatementHandler(engine, db).ExecContext(ctx, save, []int{1, 2, 3}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"select", "select", "users", "users"}
+	if len(seen) != len(want) {
+		t.Fatalf("expected middlewares to see %v, got %v", want, seen)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("expected middlewares to see %v, got %v", want, seen)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-juicedev/juice/eval"
+	"github.com/go-juicedev/juice/internal/reflectlite"
+	"github.com/go-juicedev/juice/sql"
+)
+
+// ErrAuditFieldType is returned when an audit field can not hold the audit value.
+var ErrAuditFieldType = errors.New("juice: audit field type mismatch")
+
+// Default names of the audit fields.
+// They can be overridden by the settings auditCreatedAt, auditUpdatedAt, auditCreatedBy and auditUpdatedBy.
+const (
+	DefaultAuditCreatedAt = "created_at"
+	DefaultAuditUpdatedAt = "updated_at"
+	DefaultAuditCreatedBy = "created_by"
+	DefaultAuditUpdatedBy = "updated_by"
+)
+
+type operatorKey struct{}
+
+// ContextWithOperator returns a new context carrying the identity of the current user,
+// which is written into the created_by and updated_by audit fields.
+func ContextWithOperator(ctx context.Context, operator any) context.Context {
+	return context.WithValue(ctx, operatorKey{}, operator)
+}
+
+// OperatorFromContext returns the identity of the current user carried by ctx.
+func OperatorFromContext(ctx context.Context) (any, bool) {
+	operator := ctx.Value(operatorKey{})
+	return operator, operator != nil
+}
+
+// ensure AuditFields implements ParamProcessor.
+var _ ParamProcessor = (*AuditFields)(nil)
+
+// AuditFields is a ParamProcessor that populates the audit fields of insert and update statements.
+//
+// On insert, created_at and created_by are set when they are zero, updated_at and updated_by are always set.
+// On update, updated_at and updated_by are set.
+// The *_by fields are only set when the operator is known, see ContextWithOperator.
+//
+// Only the fields declared by the parameter are populated: struct fields are matched by their
+// param tag, then by their column tag, and map keys must already exist.
+// Struct parameters must be passed by pointer to be populated, slices of them are populated item by item.
+//
+// It is enabled unless the setting or the statement attribute auditFields is "false",
+// the statement attribute takes precedence.
+//
+// Example:
+//
+//	engine.UseParamProcessor(&juice.AuditFields{})
+//	ctx = juice.ContextWithOperator(ctx, currentUserID)
+type AuditFields struct {
+	// Now returns the time written into the *_at fields, time.Now by default.
+	Now func() time.Time
+
+	// Operator returns the identity written into the *_by fields, OperatorFromContext by default.
+	Operator func(ctx context.Context) (any, bool)
+}
+
+// auditValues is the values of the audit fields of one statement execution.
+type auditValues struct {
+	names       [4]string // created_at, updated_at, created_by, updated_by
+	now         time.Time
+	operator    any
+	hasOperator bool
+	insert      bool
+}
+
+// ProcessParam implements ParamProcessor.
+func (a *AuditFields) ProcessParam(ctx context.Context, engine *Engine, statement Statement, param eval.Param) (eval.Param, error) {
+	action := statement.Action()
+	if param == nil || (action != sql.Insert && action != sql.Update) {
+		return param, nil
+	}
+	var settings SettingProvider = keyValueSettingProvider{}
+	if configuration := engine.GetConfiguration(); configuration != nil {
+		settings = configuration.Settings()
+	}
+	if enabled := statement.Attribute("auditFields"); enabled == "false" || (enabled == "" && settings.Get("auditFields") == "false") {
+		return param, nil
+	}
+	values := auditValues{
+		names: [4]string{
+			settingOr(settings, "auditCreatedAt", DefaultAuditCreatedAt),
+			settingOr(settings, "auditUpdatedAt", DefaultAuditUpdatedAt),
+			settingOr(settings, "auditCreatedBy", DefaultAuditCreatedBy),
+			settingOr(settings, "auditUpdatedBy", DefaultAuditUpdatedBy),
+		},
+		now:    time.Now(),
+		insert: action == sql.Insert,
+	}
+	if a.Now != nil {
+		values.now = a.Now()
+	}
+	if a.Operator != nil {
+		values.operator, values.hasOperator = a.Operator(ctx)
+	} else {
+		values.operator, values.hasOperator = OperatorFromContext(ctx)
+	}
+	if err := values.populate(reflect.ValueOf(param)); err != nil {
+		return nil, err
+	}
+	return param, nil
+}
+
+// populate writes the audit values into value, which is a struct pointer, a map or a slice of them.
+func (v auditValues) populate(value reflect.Value) error {
+	value = reflectlite.Unwrap(value)
+	switch value.Kind() {
+	case reflect.Struct:
+		if !value.CanAddr() {
+			return nil
+		}
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String || value.IsNil() {
+			return nil
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range value.Len() {
+			if err := v.populate(value.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return nil
+	}
+	if err := v.set(value, v.names[0], v.now, !v.insert, false); err != nil {
+		return err
+	}
+	if err := v.set(value, v.names[1], v.now, false, true); err != nil {
+		return err
+	}
+	if !v.hasOperator {
+		return nil
+	}
+	if err := v.set(value, v.names[2], v.operator, !v.insert, false); err != nil {
+		return err
+	}
+	return v.set(value, v.names[3], v.operator, false, true)
+}
+
+// set writes audit into the field or key name of target.
+// skip disables the field, and overwrite tells whether a non-zero value is replaced.
+func (v auditValues) set(target reflect.Value, name string, audit any, skip, overwrite bool) error {
+	if skip || name == "" {
+		return nil
+	}
+	if target.Kind() == reflect.Map {
+		key := reflect.ValueOf(name).Convert(target.Type().Key())
+		current := target.MapIndex(key)
+		if !current.IsValid() {
+			return nil
+		}
+		if !overwrite && !isZeroValue(current) {
+			return nil
+		}
+		value, ok := auditValueOf(audit, target.Type().Elem())
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrAuditFieldType, name)
+		}
+		target.SetMapIndex(key, value)
+		return nil
+	}
+	reflectType := reflectlite.TypeFrom(target.Type())
+	indexes, ok := reflectType.GetFieldIndexesFromTag(eval.DefaultParamKey(), name)
+	if !ok {
+		if indexes, ok = reflectType.GetFieldIndexesFromTag(sql.ColumnTagName(), name); !ok {
+			return nil
+		}
+	}
+	field, ok := reflectlite.FieldByIndex(target, indexes)
+	if !ok || !field.CanSet() {
+		return nil
+	}
+	if !overwrite && !field.IsZero() {
+		return nil
+	}
+	value, ok := auditValueOf(audit, field.Type())
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrAuditFieldType, name)
+	}
+	field.Set(value)
+	return nil
+}
+
+// auditValueOf converts audit into a value of typ, allocating a pointer if typ is a pointer.
+func auditValueOf(audit any, typ reflect.Type) (reflect.Value, bool) {
+	value := reflect.ValueOf(audit)
+	switch {
+	case typ.Kind() == reflect.String && value.Kind() != reflect.String:
+		// avoid converting integers into strings of runes.
+		return reflect.Value{}, false
+	case value.Type().AssignableTo(typ):
+		return value, true
+	case typ.Kind() == reflect.Pointer:
+		elem, ok := auditValueOf(audit, typ.Elem())
+		if !ok {
+			return reflect.Value{}, false
+		}
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, true
+	case value.Type().ConvertibleTo(typ) && value.Kind() != reflect.Struct:
+		return value.Convert(typ), true
+	default:
+		return reflect.Value{}, false
+	}
+}
+
+// isZeroValue reports whether value, after dereferencing, is nil or the zero value.
+func isZeroValue(value reflect.Value) bool {
+	value = reflectlite.Unwrap(value)
+	return !value.IsValid() || value.IsZero()
+}
+
+// settingOr returns the setting of name, or fallback if it is not set.
+func settingOr(settings SettingProvider, name, fallback string) string {
+	if value := settings.Get(name); value != "" {
+		return value.String()
+	}
+	return fallback
+}
@@ -0,0 +1,129 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	jdriver "github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	"github.com/go-juicedev/juice/node"
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+type auditUser struct {
+	ID        int64      `column:"id"`
+	CreatedAt time.Time  `param:"created_at"`
+	UpdatedAt *time.Time `column:"updated_at"`
+	CreatedBy string     `param:"created_by"`
+	UpdatedBy string     `param:"updated_by"`
+}
+
+func TestAuditFields_audit_test(t *testing.T) {
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	earlier := now.Add(-time.Hour)
+	audit := &AuditFields{Now: func() time.Time { return now }}
+	engine := newStatementTestEngine(nil)
+	ctx := ContextWithOperator(context.Background(), "alice")
+	insert := shStatement{action: jsql.Insert, attrs: map[string]string{}}
+
+	user := &auditUser{CreatedBy: "bob"}
+	if _, err := audit.ProcessParam(ctx, engine, insert, user); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !user.CreatedAt.Equal(now) || user.UpdatedAt == nil || !user.UpdatedAt.Equal(now) {
+		t.Fatalf("expected timestamps to be populated, got %+v", user)
+	}
+	if user.CreatedBy != "bob" || user.UpdatedBy != "alice" {
+		t.Fatalf("expected created_by kept and updated_by set, got %+v", user)
+	}
+
+	update := shStatement{action: jsql.Update, attrs: map[string]string{}}
+	users := []auditUser{{CreatedAt: earlier}}
+	if _, err := audit.ProcessParam(ctx, engine, update, users); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !users[0].CreatedAt.Equal(earlier) || users[0].CreatedBy != "" || !users[0].UpdatedAt.Equal(now) || users[0].UpdatedBy != "alice" {
+		t.Fatalf("expected only updated fields on update, got %+v", users[0])
+	}
+
+	// only declared map keys are populated.
+	param := H{"name": "x", "created_at": nil}
+	if _, err := audit.ProcessParam(context.Background(), engine, insert, param); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if param["created_at"] != now {
+		t.Fatalf("expected created_at in map, got %v", param["created_at"])
+	}
+	if _, ok := param["updated_at"]; ok {
+		t.Fatalf("expected undeclared key to stay absent")
+	}
+
+	// selects and opted-out statements are untouched.
+	user = &auditUser{}
+	selectStmt := shStatement{action: jsql.Select, attrs: map[string]string{}}
+	optOut := shStatement{action: jsql.Insert, attrs: map[string]string{"auditFields": "false"}}
+	for _, stmt := range []shStatement{selectStmt, optOut} {
+		if _, err := audit.ProcessParam(ctx, engine, stmt, user); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if !user.CreatedAt.IsZero() {
+		t.Fatalf("expected untouched user, got %+v", user)
+	}
+
+	engine.configuration.(*xmlConfiguration).settings["auditFields"] = "false"
+	if _, err := audit.ProcessParam(ctx, engine, insert, user); err != nil || !user.CreatedAt.IsZero() {
+		t.Fatalf("expected setting to disable audit fields, got %+v err=%v", user, err)
+	}
+	delete(engine.configuration.(*xmlConfiguration).settings, "auditFields")
+
+	engine.configuration.(*xmlConfiguration).settings["auditUpdatedBy"] = "modifier"
+	param = H{"modifier": ""}
+	if _, err := audit.ProcessParam(ctx, engine, update, param); err != nil || param["modifier"] != "alice" {
+		t.Fatalf("expected renamed audit field, got %v err=%v", param, err)
+	}
+
+	type badUser struct {
+		CreatedBy int `param:"created_by"`
+	}
+	if _, err := audit.ProcessParam(ctx, newStatementTestEngine(nil), insert, &badUser{}); !errors.Is(err, ErrAuditFieldType) {
+		t.Fatalf("expected ErrAuditFieldType, got %v", err)
+	}
+}
+
+func TestBuildStatementQueryParamProcessor_audit_test(t *testing.T) {
+	text := node.NewTextNode("INSERT INTO user (created_by) VALUES (#{created_by})")
+	stmt := shStatement{
+		action: jsql.Insert,
+		attrs:  map[string]string{},
+		buildFn: func(translator jdriver.Translator, parameter eval.Parameter) (string, []any, error) {
+			return text.Accept(translator, parameter)
+		},
+	}
+	engine := newStatementTestEngine(nil)
+	engine.UseParamProcessor(&AuditFields{})
+	var seen []eval.Param
+	engine.UseParamProcessor(ParamProcessorFunc(func(_ context.Context, _ *Engine, _ Statement, param eval.Param) (eval.Param, error) {
+		seen = append(seen, param)
+		return param, nil
+	}))
+
+	ctx := ContextWithOperator(context.Background(), "alice")
+	_, args, err := buildStatementQuery(ctx, stmt, engine, &auditUser{})
+	if err != nil || len(args) != 1 || args[0] != "alice" {
+		t.Fatalf("expected processed param to be built, got %v err=%v", args, err)
+	}
+	if len(seen) != 1 || engine.clone().paramProcessors == nil {
+		t.Fatalf("expected processors to run in order and be cloned")
+	}
+
+	failure := errors.New("failure")
+	engine.UseParamProcessor(ParamProcessorFunc(func(context.Context, *Engine, Statement, eval.Param) (eval.Param, error) {
+		return nil, failure
+	}))
+	if _, _, err = buildStatementQuery(ctx, stmt, engine, &auditUser{}); !errors.Is(err, failure) {
+		t.Fatalf("expected processor error, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-juicedev/juice/sql"
+)
+
+// BatchHook observes the batches executed for statements with a batchSize attribute.
+// It can be used to log progress, collect partial success metrics or abort the
+// remaining batches.
+//
+// A BatchHook can be configured on the engine with Engine.SetBatchHook or per call
+// with ContextWithBatchHook; the one from the context takes precedence.
+type BatchHook interface {
+	// OnBatchStart is called before the batch at index is executed.
+	// total is the number of batches of the statement.
+	OnBatchStart(ctx context.Context, statement Statement, index, total int)
+
+	// OnBatchDone is called after the batch at index has been executed with its result and error.
+	// The result is always nil for queries. Returning a non-nil error aborts the remaining batches,
+	// and the error is returned to the caller together with the results accumulated so far.
+	OnBatchDone(ctx context.Context, statement Statement, index int, result sql.Result, err error) error
+}
+
+// noopBatchHook is the BatchHook used when no hook is configured.
+type noopBatchHook struct{}
+
+// OnBatchStart implements BatchHook.
+func (noopBatchHook) OnBatchStart(context.Context, Statement, int, int) {}
+
+// OnBatchDone implements BatchHook.
+func (noopBatchHook) OnBatchDone(context.Context, Statement, int, sql.Result, error) error {
+	return nil
+}
+
+type batchHookKey struct{}
+
+// ContextWithBatchHook returns a new context with the given BatchHook.
+// It overrides the BatchHook configured on the engine.
+func ContextWithBatchHook(ctx context.Context, hook BatchHook) context.Context {
+	return context.WithValue(ctx, batchHookKey{}, hook)
+}
+
+// batchHookFromContext returns the BatchHook from the context,
+// falling back to the one of the engine.
+func batchHookFromContext(ctx context.Context, engine *Engine) BatchHook {
+	if hook, ok := ctx.Value(batchHookKey{}).(BatchHook); ok && hook != nil {
+		return hook
+	}
+	if engine != nil && engine.batchHook != nil {
+		return engine.batchHook
+	}
+	return noopBatchHook{}
+}
+
+// execBatches executes times batches through exec and accumulates their results.
+// Errors wrapping ErrBatchSkip are collected and the batch is skipped; any other error
+// stops the execution. The accumulated result is returned together with the skipped
+// errors, or with the error of the hook when it aborts the execution.
+func execBatches(
+	ctx context.Context,
+	hook BatchHook,
+	statement Statement,
+	times int,
+	exec func(index int) (sql.Result, error),
+) (sql.Result, error) {
+	var batchErrs error
+	aggregatedResult := &sql.BatchResult{}
+
+	for i := range times {
+		hook.OnBatchStart(ctx, statement, i, times)
+		result, err := exec(i)
+		if hookErr := hook.OnBatchDone(ctx, statement, i, result, err); hookErr != nil {
+			if err == nil {
+				aggregatedResult.AccumulateResult(result)
+			}
+			// the error of the batch is reported together with the abort.
+			return aggregatedResult, errors.Join(batchErrs, err, hookErr)
+		}
+		if err != nil {
+			if errors.Is(err, ErrBatchSkip) {
+				batchErrs = errors.Join(batchErrs, err)
+				continue
+			}
+			return nil, err
+		}
+		aggregatedResult.AccumulateResult(result)
+	}
+
+	if batchErrs != nil {
+		return aggregatedResult, batchErrs
+	}
+	return aggregatedResult, nil
+}
+
+// execSingleBatch executes a statement which fits in a single batch through the hook.
+// Unlike execBatches, the result of exec is returned as it is.
+func execSingleBatch(
+	ctx context.Context,
+	hook BatchHook,
+	statement Statement,
+	exec func() (sql.Result, error),
+) (sql.Result, error) {
+	hook.OnBatchStart(ctx, statement, 0, 1)
+	result, err := exec()
+	if hookErr := hook.OnBatchDone(ctx, statement, 0, result, err); hookErr != nil {
+		return result, errors.Join(err, hookErr)
+	}
+	return result, err
+}
+
+// queryBatch executes the batch query at index through the hook.
+// The rows are closed if the hook aborts the execution.
+func queryBatch(
+	ctx context.Context,
+	hook BatchHook,
+	statement Statement,
+	index, total int,
+	query func() (sql.Rows, error),
+) (sql.Rows, error) {
+	hook.OnBatchStart(ctx, statement, index, total)
+	rows, err := query()
+	if hookErr := hook.OnBatchDone(ctx, statement, index, nil, err); hookErr != nil {
+		if rows != nil {
+			_ = rows.Close()
+		}
+		return nil, errors.Join(err, hookErr)
+	}
+	return rows, err
+}
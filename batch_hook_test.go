@@ -0,0 +1,132 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	jdriver "github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+type recordBatchHook struct {
+	starts  []string
+	dones   []string
+	abortAt int
+	abort   error
+}
+
+func (h *recordBatchHook) OnBatchStart(_ context.Context, _ Statement, index, total int) {
+	h.starts = append(h.starts, fmt.Sprintf("%d/%d", index, total))
+}
+
+func (h *recordBatchHook) OnBatchDone(_ context.Context, _ Statement, index int, result jsql.Result, err error) error {
+	var affected int64
+	if result != nil {
+		affected, _ = result.RowsAffected()
+	}
+	h.dones = append(h.dones, fmt.Sprintf("%d:%d:%v", index, affected, err != nil))
+	if h.abort != nil && index == h.abortAt {
+		return h.abort
+	}
+	return nil
+}
+
+func TestBatchHook_batch_hook_test(t *testing.T) {
+	state := &shSQLDriverState{}
+	db := openStatementTestDB(t, state)
+	ctx := context.Background()
+
+	stmt := shStatement{
+		attrs: map[string]string{"batchSize": "2"},
+		buildFn: func(_ jdriver.Translator, _ eval.Parameter) (string, []any, error) {
+			return "INSERT INTO t(v) VALUES (?)", []any{1}, nil
+		},
+	}
+	values := []int{1, 2, 3, 4, 5}
+
+	engine := newStatementTestEngine(db)
+	engineHook := &recordBatchHook{}
+	engine.SetBatchHook(engineHook)
+
+	result, err := newBatchStatementHandler(engine, db).ExecContext(ctx, stmt, values)
+	if err != nil {
+		t.Fatalf("unexpected exec error: %v", err)
+	}
+	if affected, _ := result.RowsAffected(); affected != 6 {
+		t.Fatalf("expected 6 rows affected, got %d", affected)
+	}
+	if want := []string{"0/3", "1/3", "2/3"}; !reflect.DeepEqual(engineHook.starts, want) {
+		t.Fatalf("unexpected starts: %v", engineHook.starts)
+	}
+	if want := []string{"0:2:false", "1:2:false", "2:2:false"}; !reflect.DeepEqual(engineHook.dones, want) {
+		t.Fatalf("unexpected dones: %v", engineHook.dones)
+	}
+
+	if engine.clone().batchHook != engineHook {
+		t.Fatalf("expected clone to keep the batch hook")
+	}
+
+	abortErr := errors.New("too many failures")
+	contextHook := &recordBatchHook{abortAt: 1, abort: abortErr}
+	result, err = newBatchStatementHandler(engine, db).ExecContext(ContextWithBatchHook(ctx, contextHook), stmt, values)
+	if !errors.Is(err, abortErr) {
+		t.Fatalf("expected abort error, got %v", err)
+	}
+	if affected, _ := result.RowsAffected(); affected != 4 {
+		t.Fatalf("expected partial 4 rows affected, got %d", affected)
+	}
+	if len(contextHook.starts) != 2 || len(engineHook.starts) != 3 {
+		t.Fatalf("expected context hook to override engine hook")
+	}
+
+	single := &recordBatchHook{}
+	if _, err = newBatchStatementHandler(engine, db).ExecContext(ContextWithBatchHook(ctx, single), stmt, []int{1}); err != nil {
+		t.Fatalf("unexpected single batch error: %v", err)
+	}
+	if want := []string{"0/1"}; !reflect.DeepEqual(single.starts, want) {
+		t.Fatalf("unexpected single batch starts: %v", single.starts)
+	}
+
+	skipErr := fmt.Errorf("skip this batch: %w", ErrBatchSkip)
+	skipEngine := newStatementTestEngine(db, shExecErrorMiddleware{err: skipErr})
+	skipHook := &recordBatchHook{}
+	result, err = newBatchStatementHandler(skipEngine, db).ExecContext(ContextWithBatchHook(ctx, skipHook), stmt, values)
+	if !errors.Is(err, ErrBatchSkip) {
+		t.Fatalf("expected ErrBatchSkip, got %v", err)
+	}
+	if result == nil {
+		t.Fatalf("expected partial result with skipped batches")
+	}
+	if want := []string{"0:0:true", "1:0:true", "2:0:true"}; !reflect.DeepEqual(skipHook.dones, want) {
+		t.Fatalf("unexpected skip dones: %v", skipHook.dones)
+	}
+
+	batchErr := errors.New("constraint violation")
+	failEngine := newStatementTestEngine(db, shExecErrorMiddleware{err: batchErr})
+	failHook := &recordBatchHook{abortAt: 0, abort: abortErr}
+	_, err = newBatchStatementHandler(failEngine, db).ExecContext(ContextWithBatchHook(ctx, failHook), stmt, values)
+	if !errors.Is(err, abortErr) || !errors.Is(err, batchErr) {
+		t.Fatalf("expected the batch error joined with the abort error, got %v", err)
+	}
+	if want := []string{"0:0:true"}; !reflect.DeepEqual(failHook.dones, want) {
+		t.Fatalf("unexpected failing batch dones: %v", failHook.dones)
+	}
+
+	queryHook := &recordBatchHook{abortAt: 0, abort: abortErr}
+	if _, err = newBatchStatementHandler(engine, db).QueryContext(ContextWithBatchHook(ctx, queryHook), stmt, values); !errors.Is(err, abortErr) {
+		t.Fatalf("expected query abort error, got %v", err)
+	}
+
+	queryFailEngine := newStatementTestEngine(db, shQueryRowsMiddleware{queryFn: func([]any) (jsql.Rows, error) {
+		return nil, batchErr
+	}})
+	queryFailHook := &recordBatchHook{abortAt: 0, abort: abortErr}
+	_, err = newBatchStatementHandler(queryFailEngine, db).QueryContext(ContextWithBatchHook(ctx, queryFailHook), stmt, values)
+	if !errors.Is(err, abortErr) || !errors.Is(err, batchErr) {
+		t.Fatalf("expected the query error joined with the abort error, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+
+	"github.com/go-juicedev/juice/sql"
+)
+
+// defaultBatchLoaderParam is the parameter name of the keys when BatchLoader.Param is empty.
+const defaultBatchLoaderParam = "keys"
+
+// BatchLoader loads the children of many parents with one query instead of one query
+// per parent, preventing the N+1 queries of nested selects. The statement receives
+// the parent keys and selects the children of all of them, with their parent key:
+//
+//	<select id="ListByUsers">
+//	    SELECT user_id, id, amount FROM orders WHERE user_id IN
+//	    <foreach collection="keys" item="key" open="(" separator="," close=")">#{key}</foreach>
+//	</select>
+//
+//	loader := juice.BatchLoader[int64, Order]{Manager: engine, Statement: "OrderMapper.ListByUsers", KeyColumn: "user_id"}
+//	err := juice.LoadBatched(ctx, loader, users,
+//	    func(u *User) int64 { return u.ID },
+//	    func(u *User, orders []Order) { u.Orders = orders },
+//	)
+type BatchLoader[K comparable, C any] struct {
+	// Manager executes the statement.
+	Manager Manager
+
+	// Statement selects the children of the keys, resolved like with Manager.Object.
+	Statement any
+
+	// Param is the parameter name of the keys in the statement, "keys" if empty.
+	Param string
+
+	// KeyColumn is the result column holding the parent key of each child.
+	KeyColumn string
+
+	// BatchSize is the maximum number of keys of one query, to stay below the limits of
+	// the IN lists of the databases. The keys are loaded with one query if it is not positive.
+	BatchSize int
+}
+
+// Load returns the children of keys grouped by parent key, in the order of the result rows.
+// The duplicate keys are queried once, and no query is executed if there is no key.
+func (l BatchLoader[K, C]) Load(ctx context.Context, keys []K) (map[K][]C, error) {
+	unique := make([]K, 0, len(keys))
+	seen := make(map[K]struct{}, len(keys))
+	for _, key := range keys {
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			unique = append(unique, key)
+		}
+	}
+	batchSize := l.BatchSize
+	if batchSize <= 0 {
+		batchSize = max(len(unique), 1)
+	}
+	children := make(map[K][]C, len(unique))
+	for i := 0; i < len(unique); i += batchSize {
+		batch := unique[i:min(i+batchSize, len(unique))]
+		if err := l.load(ctx, batch, children); err != nil {
+			return nil, err
+		}
+	}
+	return children, nil
+}
+
+// load queries the children of the keys of one batch into children.
+func (l BatchLoader[K, C]) load(ctx context.Context, keys []K, children map[K][]C) error {
+	param := H{cmp.Or(l.Param, defaultBatchLoaderParam): keys}
+	rows, err := l.Manager.Object(l.Statement).QueryContext(ctx, param)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+	grouped, err := sql.BindGrouped[K, C](rows, l.KeyColumn)
+	if err != nil {
+		return fmt.Errorf("failed to bind batch of %d keys: %w", len(keys), err)
+	}
+	if err = afterScan(ctx, &grouped); err != nil {
+		return err
+	}
+	for key, values := range grouped {
+		children[key] = append(children[key], values...)
+	}
+	return nil
+}
+
+// LoadBatched loads the children of parents with loader and hands each parent its children,
+// which are nil if it has none. key returns the key of a parent.
+func LoadBatched[P any, K comparable, C any](ctx context.Context, loader BatchLoader[K, C], parents []P, key func(P) K, assign func(P, []C)) error {
+	keys := make([]K, len(parents))
+	for i, parent := range parents {
+		keys[i] = key(parent)
+	}
+	children, err := loader.Load(ctx, keys)
+	if err != nil {
+		return err
+	}
+	for i, parent := range parents {
+		assign(parent, children[keys[i]])
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package juice
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/go-juicedev/juice/eval"
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+type batchLoaderOrder struct {
+	UserID int64 `column:"user_id"`
+	ID     int64 `column:"id"`
+}
+
+type batchLoaderUser struct {
+	ID     int64
+	Orders []batchLoaderOrder
+}
+
+// batchLoaderExecutor returns the orders of the queried user ids.
+type batchLoaderExecutor struct {
+	*sqlRowsExecutorStub
+	orders  [][]any
+	batches [][]int64
+}
+
+func (e *batchLoaderExecutor) QueryContext(_ context.Context, param eval.Param) (jsql.Rows, error) {
+	keys := param.(H)["userIds"].([]int64)
+	e.batches = append(e.batches, keys)
+	var data [][]any
+	for _, order := range e.orders {
+		if slices.Contains(keys, order[0].(int64)) {
+			data = append(data, order)
+		}
+	}
+	return jsql.NewRowsBuffer([]string{"user_id", "id"}, data), nil
+}
+
+func TestLoadBatched_batch_loader_test(t *testing.T) {
+	executor := &batchLoaderExecutor{
+		sqlRowsExecutorStub: &sqlRowsExecutorStub{stmt: statementStub{}},
+		orders:              [][]any{{int64(1), int64(10)}, {int64(2), int64(20)}, {int64(1), int64(11)}, {int64(3), int64(30)}},
+	}
+	loader := BatchLoader[int64, batchLoaderOrder]{
+		Manager:   &managerStub{object: executor},
+		Statement: "OrderMapper.ListByUsers",
+		Param:     "userIds",
+		KeyColumn: "user_id",
+		BatchSize: 2,
+	}
+	users := []*batchLoaderUser{{ID: 1}, {ID: 2}, {ID: 1}, {ID: 3}, {ID: 4}}
+	err := LoadBatched(t.Context(), loader, users,
+		func(u *batchLoaderUser) int64 { return u.ID },
+		func(u *batchLoaderUser, orders []batchLoaderOrder) { u.Orders = orders },
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(executor.batches) != 2 || !slices.Equal(executor.batches[0], []int64{1, 2}) || !slices.Equal(executor.batches[1], []int64{3, 4}) {
+		t.Fatalf("expected the unique keys in batches of 2, got %v", executor.batches)
+	}
+	if orders := users[0].Orders; len(orders) != 2 || orders[0].ID != 10 || orders[1].ID != 11 {
+		t.Errorf("unexpected orders of user 1: %v", orders)
+	}
+	if len(users[2].Orders) != 2 || len(users[1].Orders) != 1 || len(users[3].Orders) != 1 {
+		t.Errorf("unexpected orders: %v", users)
+	}
+	if users[4].Orders != nil {
+		t.Errorf("expected no orders for user 4, got %v", users[4].Orders)
+	}
+
+	executor.batches = nil
+	if err = LoadBatched(t.Context(), loader, nil, func(u *batchLoaderUser) int64 { return u.ID }, func(*batchLoaderUser, []batchLoaderOrder) {}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(executor.batches) != 0 {
+		t.Fatalf("expected no query without parents, got %v", executor.batches)
+	}
+}
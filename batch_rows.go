@@ -0,0 +1,159 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"errors"
+
+	"github.com/go-juicedev/juice/sql"
+)
+
+// errScanWithoutNext is returned when batchRows.Scan is called before Next.
+var errScanWithoutNext = errors.New("scan called without calling next")
+
+// batchRows concatenates the rows of several batch queries into a single sql.Rows.
+// Batches are opened lazily: the next batch is queried only after the rows of the
+// current one have been exhausted, so at most one batch result is held open at a time.
+//
+// Errors wrapping ErrBatchSkip returned while opening a batch are collected and the
+// batch is skipped; they are reported by Err once iteration has finished.
+type batchRows struct {
+	// times is the total number of batches.
+	times int
+
+	// index is the index of the next batch to open.
+	index int
+
+	// open executes the batch at the given index and returns its rows.
+	open func(index int) (sql.Rows, error)
+
+	// release is called once when the rows are closed.
+	release func() error
+
+	current  sql.Rows
+	err      error
+	skipErrs error
+	closed   bool
+}
+
+// advance closes the current batch and opens the next one which is not skipped.
+// It returns false when there are no more batches or an error occurred.
+func (r *batchRows) advance() bool {
+	if r.closed || r.err != nil {
+		return false
+	}
+	if r.current != nil {
+		if err := r.current.Close(); err != nil {
+			r.err = err
+			return false
+		}
+		r.current = nil
+	}
+	for r.index < r.times {
+		rows, err := r.open(r.index)
+		r.index++
+		if err != nil {
+			if errors.Is(err, ErrBatchSkip) {
+				r.skipErrs = errors.Join(r.skipErrs, err)
+				continue
+			}
+			r.err = err
+			return false
+		}
+		r.current = rows
+		return true
+	}
+	return false
+}
+
+// Columns returns the column names of the current batch.
+func (r *batchRows) Columns() ([]string, error) {
+	if r.current == nil && !r.advance() {
+		return nil, r.Err()
+	}
+	return r.current.Columns()
+}
+
+// Next prepares the next row for reading, moving to the next batch when
+// the current one has been exhausted.
+func (r *batchRows) Next() bool {
+	for {
+		if r.current != nil {
+			if r.current.Next() {
+				return true
+			}
+			if err := r.current.Err(); err != nil {
+				r.err = err
+				return false
+			}
+		}
+		if !r.advance() {
+			return false
+		}
+	}
+}
+
+// Scan copies the columns of the current row into dest.
+func (r *batchRows) Scan(dest ...any) error {
+	if r.current == nil {
+		return errScanWithoutNext
+	}
+	return r.current.Scan(dest...)
+}
+
+// Close closes the current batch and releases the resources held by the batches.
+func (r *batchRows) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	var err error
+	if r.current != nil {
+		err = r.current.Close()
+		r.current = nil
+	}
+	if r.release != nil {
+		err = errors.Join(err, r.release())
+	}
+	return err
+}
+
+// Err returns the error encountered during iteration, joined with
+// the errors of the skipped batches.
+func (r *batchRows) Err() error {
+	return errors.Join(r.err, r.skipErrs)
+}
+
+// newBatchRows creates a batchRows for the given number of batches and opens the first one.
+// If no batch can be opened, the rows are closed and the error is returned.
+func newBatchRows(times int, open func(index int) (sql.Rows, error), release func() error) (*batchRows, error) {
+	rows := &batchRows{
+		times:   times,
+		open:    open,
+		release: release,
+	}
+	if !rows.advance() {
+		err := rows.Err()
+		_ = rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+var _ sql.Rows = (*batchRows)(nil)
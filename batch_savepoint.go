@@ -0,0 +1,80 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/session"
+	"github.com/go-juicedev/juice/sql"
+)
+
+// batchSavepointName returns the name of the savepoint created for the batch at index.
+func batchSavepointName(index int) string {
+	return fmt.Sprintf("juice_batch_%d", index)
+}
+
+// useBatchSavepoint reports whether each batch of the statement should run in its own savepoint.
+// It requires the batchSavepoint attribute, or setting, to be true and a session inside a transaction.
+func useBatchSavepoint(engine *Engine, sess session.Session, statement Statement) bool {
+	return statementOption(statement, engine, "batchSavepoint").Bool() && isInTransaction(sess)
+}
+
+// withBatchSavepoint wraps exec so that each batch runs inside a savepoint of the current transaction,
+// managed with the syntax of the dialect of the driver of engine.
+// When a batch fails, the transaction is rolled back to its savepoint, so only the failed batch is
+// discarded and later batches can continue when the error wraps ErrBatchSkip.
+// If savepoints are not enabled for the statement, exec is returned as it is.
+func withBatchSavepoint(
+	ctx context.Context,
+	engine *Engine,
+	sess session.Session,
+	statement Statement,
+	exec func(index int) (sql.Result, error),
+) func(index int) (sql.Result, error) {
+	if !useBatchSavepoint(engine, sess, statement) {
+		return exec
+	}
+	capabilities := driver.CapabilitiesOf(engine.Driver())
+	return func(index int) (sql.Result, error) {
+		name := batchSavepointName(index)
+		if _, err := sess.ExecContext(ctx, capabilities.SavepointClause(name)); err != nil {
+			return nil, fmt.Errorf("failed to create savepoint %s: %w", name, err)
+		}
+		// the savepoints of the dialects which cannot release them are released with the transaction.
+		release := capabilities.ReleaseSavepointClause(name)
+		result, err := exec(index)
+		if err != nil {
+			if _, rollbackErr := sess.ExecContext(ctx, capabilities.RollbackToSavepointClause(name)); rollbackErr != nil {
+				// the transaction is in an unknown state, the error must not be skipped.
+				return nil, fmt.Errorf("%v; failed to roll back to savepoint %s: %w", err, name, rollbackErr)
+			}
+			if release != "" {
+				_, _ = sess.ExecContext(ctx, release)
+			}
+			return nil, err
+		}
+		if release != "" {
+			if _, err = sess.ExecContext(ctx, release); err != nil {
+				return nil, fmt.Errorf("failed to release savepoint %s: %w", name, err)
+			}
+		}
+		return result, nil
+	}
+}
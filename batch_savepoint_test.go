@@ -0,0 +1,187 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	jdriver "github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+type failBatchMiddleware struct {
+	calls  *int
+	failAt int
+	err    error
+}
+
+func (m failBatchMiddleware) QueryContext(_ *StatementContext, next QueryHandler) QueryHandler {
+	return next
+}
+
+func (m failBatchMiddleware) ExecContext(_ *StatementContext, next ExecHandler) ExecHandler {
+	return func(ctx context.Context, query string, args ...any) (jsql.Result, error) {
+		*m.calls++
+		if *m.calls-1 == m.failAt {
+			return nil, m.err
+		}
+		return next(ctx, query, args...)
+	}
+}
+
+func TestBatchSavepoint_batch_savepoint_test(t *testing.T) {
+	state := &shSQLDriverState{}
+	db := openStatementTestDB(t, state)
+	ctx := context.Background()
+
+	stmt := shStatement{
+		attrs: map[string]string{"batchSize": "2", "batchSavepoint": "true"},
+		buildFn: func(_ jdriver.Translator, _ eval.Parameter) (string, []any, error) {
+			return "INSERT INTO t(v) VALUES (?)", []any{1}, nil
+		},
+	}
+
+	var calls int
+	skipErr := fmt.Errorf("duplicate key: %w", ErrBatchSkip)
+	engine := newStatementTestEngine(db, failBatchMiddleware{calls: &calls, failAt: 1, err: skipErr})
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin tx: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := newBatchStatementHandler(engine, tx).ExecContext(ctx, stmt, []int{1, 2, 3, 4, 5})
+	if !errors.Is(err, ErrBatchSkip) {
+		t.Fatalf("expected ErrBatchSkip, got %v", err)
+	}
+	if affected, _ := result.RowsAffected(); affected != 4 {
+		t.Fatalf("expected 4 rows affected, got %d", affected)
+	}
+	want := []string{
+		"SAVEPOINT juice_batch_0",
+		"RELEASE SAVEPOINT juice_batch_0",
+		"SAVEPOINT juice_batch_1",
+		"ROLLBACK TO SAVEPOINT juice_batch_1",
+		"RELEASE SAVEPOINT juice_batch_1",
+		"SAVEPOINT juice_batch_2",
+		"RELEASE SAVEPOINT juice_batch_2",
+	}
+	if !reflect.DeepEqual(state.connExecQueries, want) {
+		t.Fatalf("unexpected savepoint queries:\n got %v\nwant %v", state.connExecQueries, want)
+	}
+
+	// savepoints are not used outside a transaction.
+	state.connExecQueries = nil
+	calls = 0
+	if _, err = newBatchStatementHandler(engine, db).ExecContext(ctx, stmt, []int{1, 2, 3}); !errors.Is(err, ErrBatchSkip) {
+		t.Fatalf("expected ErrBatchSkip, got %v", err)
+	}
+	if len(state.connExecQueries) != 0 {
+		t.Fatalf("expected no savepoint queries outside transaction, got %v", state.connExecQueries)
+	}
+
+	// savepoints creation errors are reported.
+	state.execErr = errors.New("savepoint not supported")
+	if _, err = newBatchStatementHandler(engine, tx).ExecContext(ctx, stmt, []int{1}); !errors.Is(err, state.execErr) {
+		t.Fatalf("expected savepoint error, got %v", err)
+	}
+}
+
+func TestUseBatchSavepoint_batch_savepoint_test(t *testing.T) {
+	state := &shSQLDriverState{}
+	db := openStatementTestDB(t, state)
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("failed to begin tx: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	tests := []struct {
+		attribute string
+		setting   string
+		want      bool
+	}{
+		{attribute: "true", want: true},
+		{attribute: "1", want: true},
+		{attribute: "TRUE", want: true},
+		{attribute: "false", setting: "true", want: false},
+		{setting: "true", want: true},
+		{setting: "1", want: true},
+		{},
+	}
+	for _, tt := range tests {
+		engine := newStatementTestEngine(tx)
+		if tt.setting != "" {
+			engine.configuration.(*xmlConfiguration).settings["batchSavepoint"] = StringValue(tt.setting)
+		}
+		stmt := shStatement{attrs: map[string]string{}}
+		if tt.attribute != "" {
+			stmt.attrs["batchSavepoint"] = tt.attribute
+		}
+		if got := useBatchSavepoint(engine, tx, stmt); got != tt.want {
+			t.Errorf("useBatchSavepoint(attribute=%q, setting=%q) = %v, want %v", tt.attribute, tt.setting, got, tt.want)
+		}
+	}
+	// savepoints are not used outside a transaction.
+	if useBatchSavepoint(newStatementTestEngine(db), db, shStatement{attrs: map[string]string{"batchSavepoint": "true"}}) {
+		t.Error("expected no savepoint outside a transaction")
+	}
+}
+
+func TestBatchSavepointDialects_batch_savepoint_test(t *testing.T) {
+	tests := []struct {
+		driver jdriver.Driver
+		want   []string
+	}{
+		{
+			driver: jdriver.SQLServerDriver{},
+			want: []string{
+				"SAVE TRANSACTION juice_batch_0",
+				"SAVE TRANSACTION juice_batch_1",
+				"ROLLBACK TRANSACTION juice_batch_1",
+			},
+		},
+		{
+			driver: jdriver.OracleDriver{},
+			want: []string{
+				"SAVEPOINT juice_batch_0",
+				"SAVEPOINT juice_batch_1",
+				"ROLLBACK TO SAVEPOINT juice_batch_1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.driver.Name(), func(t *testing.T) {
+			state := &shSQLDriverState{}
+			db := openStatementTestDB(t, state)
+			ctx := context.Background()
+
+			stmt := shStatement{
+				attrs: map[string]string{"batchSize": "2", "batchSavepoint": "true"},
+				buildFn: func(_ jdriver.Translator, _ eval.Parameter) (string, []any, error) {
+					return "INSERT INTO t(v) VALUES (?)", []any{1}, nil
+				},
+			}
+			var calls int
+			engine := newStatementTestEngine(db, failBatchMiddleware{calls: &calls, failAt: 1, err: fmt.Errorf("duplicate key: %w", ErrBatchSkip)})
+			engine.driver = tt.driver
+
+			tx, err := db.BeginTx(ctx, nil)
+			if err != nil {
+				t.Fatalf("failed to begin tx: %v", err)
+			}
+			defer func() { _ = tx.Rollback() }()
+
+			if _, err = newBatchStatementHandler(engine, tx).ExecContext(ctx, stmt, []int{1, 2, 3, 4}); !errors.Is(err, ErrBatchSkip) {
+				t.Fatalf("expected ErrBatchSkip, got %v", err)
+			}
+			if !reflect.DeepEqual(state.connExecQueries, tt.want) {
+				t.Fatalf("unexpected savepoint queries:\n got %v\nwant %v", state.connExecQueries, tt.want)
+			}
+		})
+	}
+}
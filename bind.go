@@ -140,3 +140,56 @@ func List2[T any](rows sql.Rows) ([]*T, error) {
 func Iter[T any](rows sql.Rows) (sql.Iterator[T], error) {
 	return sql.Iter[T](rows)
 }
+
+// BindMap converts database query results to a map keyed by the value of keyColumn.
+// This function provides backward compatibility for code that imports the juice package directly.
+//
+// For new code, consider using sql.BindMap directly:
+//
+//	import "github.com/go-juicedev/juice/sql"
+//	users, err := sql.BindMap[int64, User](rows, "id")
+//
+// Returns sql.ErrDuplicateKey when two rows share the same key.
+func BindMap[K comparable, V any](rows sql.Rows, keyColumn string) (map[K]V, error) {
+	return sql.BindMap[K, V](rows, keyColumn)
+}
+
+// BindGrouped converts database query results to a map of slices grouped by the value of keyColumn.
+// This function provides backward compatibility for code that imports the juice package directly.
+//
+// For new code, consider using sql.BindGrouped directly:
+//
+//	import "github.com/go-juicedev/juice/sql"
+//	orders, err := sql.BindGrouped[int64, Order](rows, "user_id")
+//
+// Rows sharing a key keep their original order inside the group.
+func BindGrouped[K comparable, V any](rows sql.Rows, keyColumn string) (map[K][]V, error) {
+	return sql.BindGrouped[K, V](rows, keyColumn)
+}
+
+// ForEach scans database query results row by row into T and calls fn for each of them.
+// This function provides backward compatibility for code that imports the juice package directly.
+//
+// For new code, consider using sql.ForEach directly:
+//
+//	import "github.com/go-juicedev/juice/sql"
+//	err := sql.ForEach(rows, func(user User) error { return export(user) })
+//
+// Unlike List, no slice is materialized, which keeps memory flat for export jobs and batch reads.
+// Note: The caller is responsible for closing the rows.
+func ForEach[T any](rows sql.Rows, fn func(T) error) error {
+	return sql.ForEach(rows, fn)
+}
+
+// Reduce folds database query results into an accumulator of type R, starting from initial.
+// This function provides backward compatibility for code that imports the juice package directly.
+//
+// For new code, consider using sql.Reduce directly:
+//
+//	import "github.com/go-juicedev/juice/sql"
+//	total, err := sql.Reduce(rows, 0, func(sum int, o Order) (int, error) { return sum + o.Amount, nil })
+//
+// Note: The caller is responsible for closing the rows.
+func Reduce[T, R any](rows sql.Rows, initial R, fn func(R, T) (R, error)) (R, error) {
+	return sql.Reduce(rows, initial, fn)
+}
@@ -60,5 +60,43 @@ func TestBindWrappers_bind_test(t *testing.T) {
 	if mapped != "r1" {
 		t.Fatalf("unexpected BindWithResultMap value: %q", mapped)
 	}
-}
 
+	rows6 := jsql.NewRowsBuffer([]string{"id", "value"}, [][]any{{1, "m1"}, {2, "m2"}})
+	byID, err := BindMap[int, string](rows6, "id")
+	if err != nil {
+		t.Fatalf("unexpected BindMap error: %v", err)
+	}
+	if len(byID) != 2 || byID[1] != "m1" || byID[2] != "m2" {
+		t.Fatalf("unexpected BindMap result: %#v", byID)
+	}
+
+	rows7 := jsql.NewRowsBuffer([]string{"group", "value"}, [][]any{{"a", "g1"}, {"b", "g2"}, {"a", "g3"}})
+	grouped, err := BindGrouped[string, string](rows7, "group")
+	if err != nil {
+		t.Fatalf("unexpected BindGrouped error: %v", err)
+	}
+	if len(grouped["a"]) != 2 || grouped["a"][1] != "g3" || len(grouped["b"]) != 1 {
+		t.Fatalf("unexpected BindGrouped result: %#v", grouped)
+	}
+
+	rows8 := jsql.NewRowsBuffer([]string{"value"}, [][]any{{"f1"}, {"f2"}})
+	var visited []string
+	if err = ForEach(rows8, func(item string) error {
+		visited = append(visited, item)
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected ForEach error: %v", err)
+	}
+	if len(visited) != 2 || visited[1] != "f2" {
+		t.Fatalf("unexpected ForEach result: %#v", visited)
+	}
+
+	rows9 := jsql.NewRowsBuffer([]string{"value"}, [][]any{{1}, {2}, {3}})
+	sum, err := Reduce(rows9, 0, func(acc int, item int) (int, error) { return acc + item, nil })
+	if err != nil {
+		t.Fatalf("unexpected Reduce error: %v", err)
+	}
+	if sum != 6 {
+		t.Fatalf("unexpected Reduce result: %d", sum)
+	}
+}
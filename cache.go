@@ -0,0 +1,300 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-juicedev/juice/internal/container"
+	"github.com/go-juicedev/juice/internal/singleflight"
+	"github.com/go-juicedev/juice/session"
+	"github.com/go-juicedev/juice/sql"
+	"github.com/go-juicedev/juice/sql/analyzer"
+)
+
+const (
+	// useCacheAttribute is the attribute of the select statements whose results are cached by CacheMiddleware.
+	useCacheAttribute = "useCache"
+
+	// cacheTTLAttribute is the attribute of the cached select statements configuring how long their
+	// results are fresh, like cacheTTL="30s". The results never expire when it is not set.
+	cacheTTLAttribute = "cacheTTL"
+
+	// staleWhileRevalidateAttribute is the attribute of the cached select statements configuring how long
+	// their results are still returned once expired, while they are refreshed in the background.
+	staleWhileRevalidateAttribute = "staleWhileRevalidate"
+
+	// flushCacheAttribute is the attribute of the write statements configuring the tables whose cached
+	// results they invalidate: "false" disables the invalidation, a comma separated list of tables
+	// replaces the tables written by the statement, found by analyzing its query.
+	flushCacheAttribute = "flushCache"
+)
+
+// CacheEntry is the result of a select statement stored in a Cache.
+type CacheEntry struct {
+	// Columns holds the columns of the result.
+	Columns []string
+
+	// Rows holds the values of the rows of the result.
+	Rows [][]any
+
+	// Tables holds the tables read by the statement. The entry is invalidated
+	// when a statement writing one of them is executed.
+	Tables []string
+
+	// StoredAt is the time the result was read from the database.
+	StoredAt time.Time
+}
+
+// Cache stores the results of the select statements, see CacheMiddleware.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the entry of key, and false if there is none.
+	Get(ctx context.Context, key string) (*CacheEntry, bool, error)
+
+	// Set stores entry for key.
+	Set(ctx context.Context, key string, entry *CacheEntry) error
+
+	// InvalidateTables removes the entries reading any of tables, ignoring case.
+	InvalidateTables(ctx context.Context, tables ...string) error
+}
+
+// CacheMiddleware caches the results of the select statements with the useCache attribute,
+// keyed by statement name, query and arguments, and invalidates them when a statement writing
+// the tables they read is executed. The tables are found by analyzing the queries, see analyzer.Analyze,
+// unless the flushCache attribute of the write statement lists them, like flushCache="user,order".
+//
+// The results expire after the duration of the cacheTTL attribute of their statement. With the
+// staleWhileRevalidate attribute, an expired result is still returned during that duration, while
+// it is refreshed in the background, once at a time, so that the expiration of a hot result does not
+// send every concurrent caller to the database:
+//
+//	<select id="Find" useCache="true" cacheTTL="30s" staleWhileRevalidate="5s">
+//
+// The statements executed in a transaction are not cached, and their writes invalidate the cache
+// as soon as they are executed, not when the transaction is committed.
+type CacheMiddleware struct {
+	// Cache stores the results. It must not be nil.
+	Cache Cache
+
+	// refreshes coalesces the background refreshes of the stale results by key.
+	refreshes singleflight.Group[struct{}]
+}
+
+// ensure CacheMiddleware implements Middleware.
+var _ Middleware = (*CacheMiddleware)(nil) // compile time check
+
+// QueryContext implements Middleware.
+func (m *CacheMiddleware) QueryContext(ctx *StatementContext, next QueryHandler) QueryHandler {
+	statement := ctx.Statement()
+	if statement.Action() != sql.Select || statement.Attribute(useCacheAttribute) != "true" {
+		return next
+	}
+	if _, inTransaction := ctx.Session().(session.Transaction); inTransaction {
+		return next
+	}
+	ttl, err := cacheDuration(statement, cacheTTLAttribute)
+	if err != nil {
+		return func(context.Context, string, ...any) (sql.Rows, error) { return nil, err }
+	}
+	staleWhileRevalidate, err := cacheDuration(statement, staleWhileRevalidateAttribute)
+	if err != nil {
+		return func(context.Context, string, ...any) (sql.Rows, error) { return nil, err }
+	}
+	return func(c context.Context, query string, args ...any) (sql.Rows, error) {
+		key := cacheKey(statement, query, args)
+		entry, ok, err := m.Cache.Get(c, key)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			age := time.Since(entry.StoredAt)
+			if ttl <= 0 || age < ttl {
+				return sql.NewRowsBuffer(entry.Columns, entry.Rows), nil
+			}
+			if age < ttl+staleWhileRevalidate {
+				// the refresh outlives the query, but keeps the values of its context.
+				refreshCtx := context.WithoutCancel(c)
+				m.refreshes.TryGo(key, func() (struct{}, error) {
+					_, err := m.load(refreshCtx, key, next, query, args)
+					if err != nil {
+						logger.Printf("failed to refresh the cached result of %s: %v", statement.Name(), err)
+					}
+					return struct{}{}, err
+				})
+				return sql.NewRowsBuffer(entry.Columns, entry.Rows), nil
+			}
+		}
+		if entry, err = m.load(c, key, next, query, args); err != nil {
+			return nil, err
+		}
+		return sql.NewRowsBuffer(entry.Columns, entry.Rows), nil
+	}
+}
+
+// load executes the query with next and stores its result for key.
+func (m *CacheMiddleware) load(ctx context.Context, key string, next QueryHandler, query string, args []any) (*CacheEntry, error) {
+	rows, err := next(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := bufferRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	entry.Tables = analyzer.Analyze(query).Tables
+	entry.StoredAt = time.Now()
+	if err = m.Cache.Set(ctx, key, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// cacheDuration returns the duration of the attribute of statement, or zero when it is not set.
+func cacheDuration(statement Statement, attribute string) (time.Duration, error) {
+	value := statement.Attribute(attribute)
+	if value == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid %s %q of statement %s", attribute, value, statement.Name())
+	}
+	return duration, nil
+}
+
+// ExecContext implements Middleware.
+func (m *CacheMiddleware) ExecContext(ctx *StatementContext, next ExecHandler) ExecHandler {
+	flushCache := ctx.Statement().Attribute(flushCacheAttribute)
+	if flushCache == "false" {
+		return next
+	}
+	return func(c context.Context, query string, args ...any) (sql.Result, error) {
+		result, err := next(c, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		tables := analyzer.Analyze(query).WrittenTables
+		if flushCache != "" && flushCache != "true" {
+			tables = nil
+			for table := range strings.SplitSeq(flushCache, ",") {
+				if table = strings.TrimSpace(table); table != "" {
+					tables = append(tables, table)
+				}
+			}
+		}
+		if len(tables) > 0 {
+			if err = m.Cache.InvalidateTables(c, tables...); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+}
+
+// cacheKey returns the key of the result of statement for query and args.
+func cacheKey(statement Statement, query string, args []any) string {
+	hash := sha256.New()
+	_, _ = fmt.Fprintf(hash, "%s\x00%s\x00%#v", statement.Name(), query, args)
+	return statement.Name() + ":" + hex.EncodeToString(hash.Sum(nil))
+}
+
+// bufferRows reads and closes rows.
+func bufferRows(rows sql.Rows) (*CacheEntry, error) {
+	defer func() { _ = rows.Close() }()
+	buffer, err := sql.BufferRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	return &CacheEntry{Columns: buffer.ColumnsLine, Rows: buffer.Data}, nil
+}
+
+// memoryCache is the Cache returned by NewMemoryCache.
+type memoryCache struct {
+	// mu guards tables and the writes of entries.
+	mu      sync.Mutex
+	entries *container.LRU[string, *CacheEntry]
+	// tables indexes the keys of the entries by the lower cased tables they read.
+	tables map[string]map[string]struct{}
+}
+
+// NewMemoryCache returns a Cache holding at most capacity entries in memory,
+// evicting the least recently used ones. It panics if capacity is not positive.
+func NewMemoryCache(capacity int) Cache {
+	cache := &memoryCache{tables: make(map[string]map[string]struct{})}
+	// the entries are only evicted by Set, which holds mu.
+	cache.entries = container.NewLRU(capacity, cache.unindex)
+	return cache
+}
+
+// Get implements Cache.
+func (c *memoryCache) Get(_ context.Context, key string) (*CacheEntry, bool, error) {
+	entry, ok := c.entries.Get(key)
+	return entry, ok, nil
+}
+
+// Set implements Cache.
+func (c *memoryCache) Set(_ context.Context, key string, entry *CacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if previous, ok := c.entries.Get(key); ok {
+		c.unindex(key, previous)
+	}
+	for _, table := range entry.Tables {
+		table = strings.ToLower(table)
+		if c.tables[table] == nil {
+			c.tables[table] = make(map[string]struct{})
+		}
+		c.tables[table][key] = struct{}{}
+	}
+	c.entries.Set(key, entry)
+	return nil
+}
+
+// InvalidateTables implements Cache.
+func (c *memoryCache) InvalidateTables(_ context.Context, tables ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, table := range tables {
+		table = strings.ToLower(table)
+		for key := range c.tables[table] {
+			if entry, ok := c.entries.Get(key); ok {
+				c.unindex(key, entry)
+				c.entries.Delete(key)
+			}
+		}
+		delete(c.tables, table)
+	}
+	return nil
+}
+
+// unindex removes key from the index of the tables of entry. The caller must hold mu.
+func (c *memoryCache) unindex(key string, entry *CacheEntry) {
+	for _, table := range entry.Tables {
+		table = strings.ToLower(table)
+		delete(c.tables[table], key)
+		if len(c.tables[table]) == 0 {
+			delete(c.tables, table)
+		}
+	}
+}
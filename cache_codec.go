@@ -0,0 +1,305 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnsupportedCacheValue is returned by JSONCacheCodec for the values it cannot encode,
+// which are not of the types the drivers return: nil, int64, float64, bool, []byte, string and time.Time.
+var ErrUnsupportedCacheValue = errors.New("juice: unsupported cache value")
+
+var errInvalidCacheEnvelope = errors.New("juice: invalid cache envelope")
+
+// CacheCodec encodes the entries of the caches storing bytes, like the ones created by NewStoreCache.
+// Decode must return the values encoded by Encode with their types, including time.Time and nil.
+type CacheCodec interface {
+	Encode(entry *CacheEntry) ([]byte, error)
+	Decode(data []byte) (*CacheEntry, error)
+}
+
+// JSONCacheCodec is a CacheCodec encoding the entries as JSON, tagging every value with its type.
+type JSONCacheCodec struct{}
+
+// ensure JSONCacheCodec implements CacheCodec.
+var _ CacheCodec = JSONCacheCodec{} // compile time check
+
+// jsonCacheEntry is the JSON representation of a CacheEntry.
+type jsonCacheEntry struct {
+	Columns  []string           `json:"columns"`
+	Rows     [][]jsonCacheValue `json:"rows"`
+	Tables   []string           `json:"tables,omitempty"`
+	StoredAt time.Time          `json:"storedAt"`
+}
+
+// jsonCacheValue is the JSON representation of a value, tagged with its type.
+// The numbers are encoded as strings to keep their precision.
+type jsonCacheValue struct {
+	Type  string `json:"t"`
+	Value string `json:"v,omitempty"`
+	Bytes []byte `json:"b,omitempty"`
+}
+
+// Encode implements CacheCodec.
+func (JSONCacheCodec) Encode(entry *CacheEntry) ([]byte, error) {
+	encoded := jsonCacheEntry{
+		Columns:  entry.Columns,
+		Rows:     make([][]jsonCacheValue, len(entry.Rows)),
+		Tables:   entry.Tables,
+		StoredAt: entry.StoredAt,
+	}
+	for i, row := range entry.Rows {
+		encoded.Rows[i] = make([]jsonCacheValue, len(row))
+		for j, value := range row {
+			var v jsonCacheValue
+			switch value := value.(type) {
+			case nil:
+				v.Type = "null"
+			case int64:
+				v.Type, v.Value = "int64", strconv.FormatInt(value, 10)
+			case float64:
+				v.Type, v.Value = "float64", strconv.FormatFloat(value, 'g', -1, 64)
+			case bool:
+				v.Type, v.Value = "bool", strconv.FormatBool(value)
+			case []byte:
+				v.Type, v.Bytes = "bytes", value
+			case string:
+				v.Type, v.Value = "string", value
+			case time.Time:
+				v.Type, v.Value = "time", value.Format(time.RFC3339Nano)
+			default:
+				return nil, fmt.Errorf("%w: %T in column %s", ErrUnsupportedCacheValue, value, entry.Columns[j])
+			}
+			encoded.Rows[i][j] = v
+		}
+	}
+	return json.Marshal(encoded)
+}
+
+// Decode implements CacheCodec.
+func (JSONCacheCodec) Decode(data []byte) (*CacheEntry, error) {
+	var encoded jsonCacheEntry
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, err
+	}
+	entry := &CacheEntry{
+		Columns:  encoded.Columns,
+		Rows:     make([][]any, len(encoded.Rows)),
+		Tables:   encoded.Tables,
+		StoredAt: encoded.StoredAt,
+	}
+	for i, row := range encoded.Rows {
+		entry.Rows[i] = make([]any, len(row))
+		for j, v := range row {
+			var (
+				value any
+				err   error
+			)
+			switch v.Type {
+			case "null":
+			case "int64":
+				value, err = strconv.ParseInt(v.Value, 10, 64)
+			case "float64":
+				value, err = strconv.ParseFloat(v.Value, 64)
+			case "bool":
+				value, err = strconv.ParseBool(v.Value)
+			case "bytes":
+				// an empty slice is omitted, keep it distinct from NULL.
+				value = append([]byte{}, v.Bytes...)
+			case "string":
+				value = v.Value
+			case "time":
+				value, err = time.Parse(time.RFC3339Nano, v.Value)
+			default:
+				err = fmt.Errorf("%w: type %q", ErrUnsupportedCacheValue, v.Type)
+			}
+			if err != nil {
+				return nil, err
+			}
+			entry.Rows[i][j] = value
+		}
+	}
+	return entry, nil
+}
+
+// GobCacheCodec is a CacheCodec encoding the entries with encoding/gob.
+// The types of the values other than the basic types and time.Time must be registered with gob.Register.
+type GobCacheCodec struct{}
+
+// ensure GobCacheCodec implements CacheCodec.
+var _ CacheCodec = GobCacheCodec{} // compile time check
+
+func init() {
+	gob.Register(time.Time{})
+}
+
+// Encode implements CacheCodec.
+func (GobCacheCodec) Encode(entry *CacheEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entry); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode implements CacheCodec.
+func (GobCacheCodec) Decode(data []byte) (*CacheEntry, error) {
+	var entry CacheEntry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entry); err != nil {
+		return nil, err
+	}
+	// gob decodes an empty slice as nil, keep it distinct from NULL.
+	for _, row := range entry.Rows {
+		for i, value := range row {
+			if value, ok := value.([]byte); ok && value == nil {
+				row[i] = []byte{}
+			}
+		}
+	}
+	return &entry, nil
+}
+
+// CacheStore stores bytes by key, like a Redis client. Implementations must be safe for concurrent use.
+type CacheStore interface {
+	// Get returns the value of key, and false if there is none.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores value for key.
+	Set(ctx context.Context, key string, value []byte) error
+}
+
+// storeCache is the Cache returned by NewStoreCache.
+type storeCache struct {
+	store CacheStore
+	codec CacheCodec
+}
+
+// NewStoreCache returns a Cache keeping its entries in store, encoded by codec, so that they
+// can be shared by several processes. Instead of indexing the entries by table, every table has
+// a version, stored under the "juice:table:" prefix, which is changed to invalidate its entries:
+// an entry is only returned while the versions of the tables it reads are the ones it was stored with.
+func NewStoreCache(store CacheStore, codec CacheCodec) Cache {
+	return &storeCache{store: store, codec: codec}
+}
+
+// tableVersionKey returns the key of the version of table.
+func tableVersionKey(table string) string {
+	return "juice:table:" + strings.ToLower(table)
+}
+
+// versions returns the current versions of tables.
+func (c *storeCache) versions(ctx context.Context, tables []string) ([]string, error) {
+	versions := make([]string, len(tables))
+	for i, table := range tables {
+		version, _, err := c.store.Get(ctx, tableVersionKey(table))
+		if err != nil {
+			return nil, err
+		}
+		versions[i] = string(version)
+	}
+	return versions, nil
+}
+
+// Get implements Cache.
+func (c *storeCache) Get(ctx context.Context, key string) (*CacheEntry, bool, error) {
+	data, ok, err := c.store.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	stored, payload, err := decodeCacheEnvelope(data)
+	if err != nil {
+		return nil, false, err
+	}
+	entry, err := c.codec.Decode(payload)
+	if err != nil {
+		return nil, false, err
+	}
+	versions, err := c.versions(ctx, entry.Tables)
+	if err != nil {
+		return nil, false, err
+	}
+	if !slices.Equal(stored, versions) {
+		return nil, false, nil
+	}
+	return entry, true, nil
+}
+
+// Set implements Cache.
+func (c *storeCache) Set(ctx context.Context, key string, entry *CacheEntry) error {
+	versions, err := c.versions(ctx, entry.Tables)
+	if err != nil {
+		return err
+	}
+	payload, err := c.codec.Encode(entry)
+	if err != nil {
+		return err
+	}
+	return c.store.Set(ctx, key, encodeCacheEnvelope(versions, payload))
+}
+
+// InvalidateTables implements Cache.
+func (c *storeCache) InvalidateTables(ctx context.Context, tables ...string) error {
+	for _, table := range tables {
+		version := make([]byte, 16)
+		_, _ = rand.Read(version)
+		if err := c.store.Set(ctx, tableVersionKey(table), []byte(hex.EncodeToString(version))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeCacheEnvelope prefixes payload with the versions of the tables of its entry.
+func encodeCacheEnvelope(versions []string, payload []byte) []byte {
+	data := binary.AppendUvarint(nil, uint64(len(versions)))
+	for _, version := range versions {
+		data = binary.AppendUvarint(data, uint64(len(version)))
+		data = append(data, version...)
+	}
+	return append(data, payload...)
+}
+
+// decodeCacheEnvelope returns the versions and the payload encoded by encodeCacheEnvelope.
+func decodeCacheEnvelope(data []byte) ([]string, []byte, error) {
+	count, n := binary.Uvarint(data)
+	if n <= 0 || count > uint64(len(data)) {
+		return nil, nil, errInvalidCacheEnvelope
+	}
+	data = data[n:]
+	versions := make([]string, count)
+	for i := range versions {
+		size, n := binary.Uvarint(data)
+		if n <= 0 || size > uint64(len(data)-n) {
+			return nil, nil, errInvalidCacheEnvelope
+		}
+		versions[i], data = string(data[n:n+int(size)]), data[n+int(size):]
+	}
+	return versions, data, nil
+}
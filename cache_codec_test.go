@@ -0,0 +1,111 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCacheCodec_cache_codec_test(t *testing.T) {
+	at := time.Date(2024, 2, 29, 12, 30, 45, 123456789, time.FixedZone("UTC+8", 8*60*60))
+	entry := &CacheEntry{
+		Columns: []string{"id", "score", "active", "avatar", "name", "created_at", "deleted_at"},
+		Rows: [][]any{
+			{int64(math.MaxInt64), 1.5, true, []byte{0, 1, 2}, "juice", at, nil},
+			{int64(-1), math.Inf(1), false, []byte{}, "", time.Time{}, nil},
+		},
+		Tables:   []string{"user"},
+		StoredAt: at,
+	}
+	for name, codec := range map[string]CacheCodec{"json": JSONCacheCodec{}, "gob": GobCacheCodec{}} {
+		t.Run(name, func(t *testing.T) {
+			data, err := codec.Encode(entry)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			decoded, err := codec.Decode(data)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(decoded.Columns, entry.Columns) || !reflect.DeepEqual(decoded.Tables, entry.Tables) ||
+				!decoded.StoredAt.Equal(entry.StoredAt) {
+				t.Fatalf("unexpected entry: %+v", decoded)
+			}
+			for i, row := range entry.Rows {
+				for j, want := range row {
+					got := decoded.Rows[i][j]
+					if wantTime, ok := want.(time.Time); ok {
+						if gotTime, ok := got.(time.Time); !ok || !gotTime.Equal(wantTime) {
+							t.Fatalf("row %d column %s: got %#v, want %v", i, entry.Columns[j], got, want)
+						}
+						continue
+					}
+					if !reflect.DeepEqual(got, want) {
+						t.Fatalf("row %d column %s: got %#v, want %#v", i, entry.Columns[j], got, want)
+					}
+				}
+			}
+		})
+	}
+
+	_, err := JSONCacheCodec{}.Encode(&CacheEntry{Columns: []string{"id"}, Rows: [][]any{{int32(1)}}})
+	if !errors.Is(err, ErrUnsupportedCacheValue) {
+		t.Fatalf("expected ErrUnsupportedCacheValue, got %v", err)
+	}
+}
+
+type mapCacheStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func (s *mapCacheStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	return value, ok, nil
+}
+
+func (s *mapCacheStore) Set(_ context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	return nil
+}
+
+func TestStoreCache_cache_codec_test(t *testing.T) {
+	ctx := context.Background()
+	store := &mapCacheStore{values: make(map[string][]byte)}
+	cache := NewStoreCache(store, JSONCacheCodec{})
+	// another process sharing the store.
+	other := NewStoreCache(store, JSONCacheCodec{})
+
+	_ = cache.Set(ctx, "users", &CacheEntry{Columns: []string{"id"}, Rows: [][]any{{int64(1)}}, Tables: []string{"user"}})
+	_ = cache.Set(ctx, "orders", &CacheEntry{Columns: []string{"id"}, Rows: [][]any{{int64(2)}}, Tables: []string{"orders"}})
+	if entry, ok, err := other.Get(ctx, "users"); err != nil || !ok || entry.Rows[0][0] != int64(1) {
+		t.Fatalf("expected the entry to be shared, got %+v, %v, %v", entry, ok, err)
+	}
+
+	if err := other.InvalidateTables(ctx, "USER"); err != nil {
+		t.Fatalf("InvalidateTables() error = %v", err)
+	}
+	if _, ok, _ := cache.Get(ctx, "users"); ok {
+		t.Fatal("expected the entry of the invalidated table to be a miss")
+	}
+	if _, ok, _ := cache.Get(ctx, "orders"); !ok {
+		t.Fatal("expected the entry of another table to be kept")
+	}
+
+	_ = cache.Set(ctx, "users", &CacheEntry{Columns: []string{"id"}, Tables: []string{"user"}})
+	if _, ok, _ := other.Get(ctx, "users"); !ok {
+		t.Fatal("expected an entry stored after the invalidation to be a hit")
+	}
+	store.values["users"] = []byte{0xff}
+	if _, _, err := cache.Get(ctx, "users"); !errors.Is(err, errInvalidCacheEnvelope) {
+		t.Fatalf("expected errInvalidCacheEnvelope, got %v", err)
+	}
+}
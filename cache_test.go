@@ -0,0 +1,167 @@
+package juice
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+func TestCacheMiddleware_cache_test(t *testing.T) {
+	cache := NewMemoryCache(16)
+	engine := newStatementTestEngine(nil, &CacheMiddleware{Cache: cache})
+	queries := 0
+	query := func(statement shStatement, sql string, args ...any) []int {
+		t.Helper()
+		handler := newExecuteStatementHandler(sql, args, engine, nil).withQueryHandler(
+			func(context.Context, string, ...any) (jsql.Rows, error) {
+				queries++
+				return jsql.NewRowsBuffer([]string{"id"}, [][]any{{int64(queries)}}), nil
+			},
+		)
+		rows, err := handler.QueryContext(context.Background(), statement, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids, err := jsql.List[int](rows)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return ids
+	}
+	exec := func(statement shStatement, sql string) {
+		t.Helper()
+		handler := newExecuteStatementHandler(sql, nil, engine, nil).withExecHandler(
+			func(context.Context, string, ...any) (jsql.Result, error) { return nil, nil },
+		)
+		if _, err := handler.ExecContext(context.Background(), statement, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	cached := shStatement{name: "user.Find", attrs: map[string]string{useCacheAttribute: "true"}}
+	orders := shStatement{name: "order.Find", attrs: map[string]string{useCacheAttribute: "true"}}
+	find := func() int { return query(cached, "SELECT id FROM user WHERE id = ?", 1)[0] }
+	findOrder := func() int { return query(orders, "SELECT id FROM orders")[0] }
+
+	if first, second := find(), find(); first != second || queries != 1 {
+		t.Fatalf("expected the second query to be cached, got %d and %d after %d queries", first, second, queries)
+	}
+	if query(cached, "SELECT id FROM user WHERE id = ?", 2); queries != 2 {
+		t.Fatalf("expected other arguments to be another entry, got %d queries", queries)
+	}
+	if query(shStatement{name: "user.Find"}, "SELECT id FROM user WHERE id = ?", 1); queries != 3 {
+		t.Fatalf("expected a statement without useCache not to be cached, got %d queries", queries)
+	}
+	findOrder()
+
+	exec(shStatement{action: jsql.Update}, "UPDATE USER SET name = ? WHERE id = ?")
+	if find(); queries != 5 {
+		t.Fatalf("expected an update of the table to invalidate the entry, got %d queries", queries)
+	}
+	if findOrder(); queries != 5 {
+		t.Fatalf("expected the entries of other tables to be kept, got %d queries", queries)
+	}
+
+	exec(shStatement{action: jsql.Delete, attrs: map[string]string{flushCacheAttribute: "false"}}, "DELETE FROM orders")
+	if findOrder(); queries != 5 {
+		t.Fatalf("expected flushCache=false not to invalidate the entry, got %d queries", queries)
+	}
+	exec(shStatement{action: jsql.Update, attrs: map[string]string{flushCacheAttribute: "orders, user"}}, "CALL archive()")
+	if find(); findOrder() == 0 || queries != 7 {
+		t.Fatalf("expected the tables of flushCache to be invalidated, got %d queries", queries)
+	}
+}
+
+func TestMemoryCacheEviction_cache_test(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(1).(*memoryCache)
+	_ = cache.Set(ctx, "a", &CacheEntry{Tables: []string{"user"}})
+	_ = cache.Set(ctx, "b", &CacheEntry{Tables: []string{"orders"}})
+	if _, ok, _ := cache.Get(ctx, "a"); ok {
+		t.Fatal("expected the least recently used entry to be evicted")
+	}
+	if _, indexed := cache.tables["user"]; indexed || len(cache.tables["orders"]) != 1 {
+		t.Fatalf("expected the evicted entry to be removed from the index, got %v", cache.tables)
+	}
+}
+
+func TestCacheMiddlewareStaleWhileRevalidate_cache_test(t *testing.T) {
+	cache := NewMemoryCache(16)
+	engine := newStatementTestEngine(nil, &CacheMiddleware{Cache: cache})
+	statement := shStatement{name: "user.Find", attrs: map[string]string{
+		useCacheAttribute:             "true",
+		cacheTTLAttribute:             "1m",
+		staleWhileRevalidateAttribute: "1m",
+	}}
+	var queries atomic.Int64
+	refreshed := make(chan struct{}, 1)
+	release := make(chan struct{})
+	query := func() int {
+		t.Helper()
+		handler := newExecuteStatementHandler("SELECT id FROM user", nil, engine, nil).withQueryHandler(
+			func(ctx context.Context, _ string, _ ...any) (jsql.Rows, error) {
+				n := queries.Add(1)
+				if n > 1 {
+					// a refresh, which must not be canceled with the query.
+					<-release
+					if ctx.Err() != nil {
+						t.Errorf("expected the refresh context not to be canceled, got %v", ctx.Err())
+					}
+					defer func() { refreshed <- struct{}{} }()
+				}
+				return jsql.NewRowsBuffer([]string{"id"}, [][]any{{n}}), nil
+			},
+		)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		rows, err := handler.QueryContext(ctx, statement, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids, err := jsql.List[int](rows)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return ids[0]
+	}
+	age := func(age time.Duration) {
+		cache.(*memoryCache).entries.Range(func(_ string, entry *CacheEntry) bool {
+			entry.StoredAt = time.Now().Add(-age)
+			return true
+		})
+	}
+
+	if id := query(); id != 1 {
+		t.Fatalf("expected the first query to hit the database, got %d", id)
+	}
+	age(90 * time.Second)
+	// the stale result is returned at once, and refreshed once in the background.
+	if first, second := query(), query(); first != 1 || second != 1 {
+		t.Fatalf("expected the stale result, got %d and %d", first, second)
+	}
+	close(release)
+	<-refreshed
+	// the refresh stores its result after reading the rows.
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if id := query(); id == 2 {
+			break
+		}
+	}
+	if queries.Load() != 2 {
+		t.Fatalf("expected a single refresh, got %d queries", queries.Load())
+	}
+
+	age(3 * time.Minute)
+	if id := query(); id != 3 {
+		t.Fatalf("expected a result older than the stale window to be read again, got %d", id)
+	}
+
+	statement.attrs[cacheTTLAttribute] = "soon"
+	handler := newExecuteStatementHandler("SELECT id FROM user", nil, engine, nil)
+	if _, err := handler.QueryContext(context.Background(), statement, nil); err == nil {
+		t.Fatal("expected an error for an invalid cacheTTL")
+	}
+}
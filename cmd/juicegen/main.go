@@ -0,0 +1,58 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command juicegen generates the names of the statements of a juice configuration as Go code,
+// to be called from go:generate:
+//
+//	//go:generate go run github.com/go-juicedev/juice/cmd/juicegen -config juice.xml -package mappers -o statements_gen.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/go-juicedev/juice"
+)
+
+func main() {
+	config := flag.String("config", "juice.xml", "path of the juice configuration")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package of the generated file, defaults to the package of go:generate")
+	output := flag.String("o", "", "path of the generated file, defaults to the standard output")
+	flag.Parse()
+
+	if err := run(*config, *pkg, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "juicegen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(config, pkg, output string) error {
+	configuration, err := juice.NewXMLConfiguration(config)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err = juice.GenerateStatementNames(&buf, configuration, pkg); err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return os.WriteFile(output, buf.Bytes(), 0o644)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"strings"
+	"unicode"
+)
+
+var errGenerateIdentifierConflict = errors.New("juice: generated identifiers conflict")
+
+// GenerateStatementNames writes to w the source of a Go file of package pkg declaring the names of
+// the statements of configuration, so that the call sites do not look statements up by strings.
+// Every namespace is declared as a variable holding a field per statement:
+//
+//	engine.Object(mappers.User.GetByID).QueryContext(ctx, param)
+//
+// The fields are of type Statement, declared by the generated file, which implements StatementID.
+// The configuration must implement StatementLister, like the ones created by this package.
+// See the juicegen command to call it from go:generate.
+func GenerateStatementNames(w io.Writer, configuration Configuration, pkg string) error {
+	if !token.IsIdentifier(pkg) {
+		return fmt.Errorf("juice: invalid package name %q", pkg)
+	}
+	description, err := describeConfiguration(configuration)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by juicegen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	buf.WriteString("// Statement is the name of a statement of the configuration.\n")
+	buf.WriteString("type Statement string\n\n")
+	buf.WriteString("// StatementID returns the name of the statement.\n")
+	buf.WriteString("func (s Statement) StatementID() string { return string(s) }\n")
+
+	namespaces := make(map[string]string, len(description.Namespaces))
+	for _, namespace := range description.Namespaces {
+		identifier := exportedIdentifier(namespace)
+		if conflict, exists := namespaces[identifier]; exists || identifier == "Statement" {
+			return fmt.Errorf("%w: namespaces %q and %q are both named %s", errGenerateIdentifierConflict, conflict, namespace, identifier)
+		}
+		namespaces[identifier] = namespace
+
+		statements := make(map[string]string)
+		var fields []string
+		for _, statement := range description.Statements {
+			if statement.Namespace != namespace {
+				continue
+			}
+			field := exportedIdentifier(statement.ID)
+			if conflict, exists := statements[field]; exists {
+				if conflict == statement.Name {
+					// a databaseId variant of a statement already declared.
+					continue
+				}
+				return fmt.Errorf("%w: statements %q and %q are both named %s", errGenerateIdentifierConflict, conflict, statement.Name, field)
+			}
+			statements[field] = statement.Name
+			fields = append(fields, field)
+		}
+
+		fmt.Fprintf(&buf, "\n// %s holds the statements of the %s namespace.\n", identifier, namespace)
+		fmt.Fprintf(&buf, "var %s = struct {\n", identifier)
+		for _, field := range fields {
+			fmt.Fprintf(&buf, "\t%s Statement\n", field)
+		}
+		buf.WriteString("}{\n")
+		for _, field := range fields {
+			fmt.Fprintf(&buf, "\t%s: %q,\n", field, statements[field])
+		}
+		buf.WriteString("}\n")
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("juice: failed to format generated source: %w", err)
+	}
+	_, err = w.Write(source)
+	return err
+}
+
+// exportedIdentifier converts name to an exported Go identifier,
+// capitalizing the words separated by the characters which are neither letters nor digits:
+// "com.example.user" becomes ComExampleUser and "get_by_id" becomes GetByID.
+func exportedIdentifier(name string) string {
+	var builder strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if upper := strings.ToUpper(word); upper == "ID" || upper == "SQL" || upper == "URL" {
+			word = upper
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		builder.WriteString(string(runes))
+	}
+	identifier := builder.String()
+	if identifier == "" || !unicode.IsLetter([]rune(identifier)[0]) {
+		identifier = "X" + identifier
+	}
+	return identifier
+}
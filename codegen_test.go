@@ -0,0 +1,79 @@
+package juice
+
+import (
+	"errors"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGenerateStatementNames_codegen_test(t *testing.T) {
+	newConfiguration := func(mappers string) Configuration {
+		t.Helper()
+		fsys := fstest.MapFS{
+			"juice.xml": &fstest.MapFile{Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>sqlite.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>` + mappers + `</mappers>
+</configuration>`)},
+		}
+		configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return configuration
+	}
+
+	configuration := newConfiguration(`
+		<mapper namespace="com.example.user">
+			<select id="get_by_id">SELECT * FROM user WHERE id = #{id}</select>
+			<select id="Now">SELECT NOW()</select>
+			<select id="Now" databaseId="sqlite">SELECT datetime('now')</select>
+		</mapper>
+		<mapper namespace="order">
+			<delete id="Delete">DELETE FROM "order" WHERE id = #{id}</delete>
+		</mapper>`)
+	var output strings.Builder
+	if err := GenerateStatementNames(&output, configuration, "mappers"); err != nil {
+		t.Fatalf("GenerateStatementNames() error = %v", err)
+	}
+	source := output.String()
+	if _, err := parser.ParseFile(token.NewFileSet(), "statements_gen.go", source, 0); err != nil {
+		t.Fatalf("generated source does not parse: %v\n%s", err, source)
+	}
+	for _, want := range []string{
+		"// Code generated by juicegen. DO NOT EDIT.",
+		"package mappers",
+		"func (s Statement) StatementID() string",
+		"var ComExampleUser = struct {",
+		`GetByID: "com.example.user.get_by_id",`,
+		`Now:     "com.example.user.Now",`,
+		`Delete: "order.Delete",`,
+	} {
+		if !strings.Contains(source, want) {
+			t.Fatalf("expected the generated source to contain %q:\n%s", want, source)
+		}
+	}
+	if strings.Count(source, `"com.example.user.Now"`) != 1 {
+		t.Fatalf("expected the databaseId variants to be declared once:\n%s", source)
+	}
+
+	configuration = newConfiguration(`
+		<mapper namespace="user">
+			<select id="getByID">SELECT 1</select>
+			<select id="GetByID">SELECT 2</select>
+		</mapper>`)
+	if err := GenerateStatementNames(&output, configuration, "mappers"); !errors.Is(err, errGenerateIdentifierConflict) {
+		t.Fatalf("expected errGenerateIdentifierConflict, got %v", err)
+	}
+	if err := GenerateStatementNames(&output, configuration, "my-mappers"); err == nil {
+		t.Fatal("expected an error for an invalid package name")
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"path/filepath"
 	"reflect"
 
+	"github.com/go-juicedev/juice/eval"
 	"github.com/go-juicedev/juice/internal/rootfs"
 	xmlparser "github.com/go-juicedev/juice/parser/xml"
 )
@@ -50,6 +51,13 @@ type Configuration interface {
 	GetStatement(v any) (Statement, error)
 }
 
+// StatementLister is implemented by the configurations which can list their statements,
+// like the ones created by this package.
+type StatementLister interface {
+	// StatementNames returns the fully qualified names of the statements, sorted.
+	StatementNames() []string
+}
+
 // xmlConfiguration is the XML-backed implementation of Configuration.
 type xmlConfiguration struct {
 	// environments is a map of environments.
@@ -60,6 +68,49 @@ type xmlConfiguration struct {
 
 	// settings is a map of settings.
 	settings keyValueSettingProvider
+
+	// funcs holds the eval functions scoped to this configuration.
+	funcs *eval.FuncRegistry
+
+	// databaseIDs maps the driver names to the databaseId of the statements.
+	databaseIDs map[string]string
+}
+
+// EvalFuncRegistrar registers eval functions scoped to its owner
+// instead of the global builtins registered by eval.RegisterEvalFunc.
+// Engine and the configurations created by this package implement it.
+type EvalFuncRegistrar interface {
+	RegisterEvalFunc(name string, fn any) error
+}
+
+// evalFuncProvider provides the eval functions scoped to a configuration.
+type evalFuncProvider interface {
+	evalFuncs() *eval.FuncRegistry
+}
+
+// RegisterEvalFunc registers an eval function shared by all the engines created from this configuration.
+// It returns eval.ErrFuncConflict if the name is used by a builtin or already registered.
+func (c *xmlConfiguration) RegisterEvalFunc(name string, fn any) error {
+	if c.funcs == nil {
+		c.funcs = eval.NewFuncRegistry()
+	}
+	return c.funcs.Register(name, fn)
+}
+
+// evalFuncs returns the eval functions scoped to this configuration.
+func (c *xmlConfiguration) evalFuncs() *eval.FuncRegistry {
+	return c.funcs
+}
+
+var (
+	_ EvalFuncRegistrar = (*xmlConfiguration)(nil)
+	_ EvalFuncRegistrar = (*Engine)(nil)
+	_ StatementLister   = (*xmlConfiguration)(nil)
+)
+
+// StatementNames implements StatementLister.
+func (c *xmlConfiguration) StatementNames() []string {
+	return c.mappers.StatementNames()
 }
 
 func (c *xmlConfiguration) validate(ignoreEnv bool) error {
@@ -91,8 +142,17 @@ func (c xmlConfiguration) Settings() SettingProvider {
 
 // GetStatement returns the statement associated with the given value.
 func (c xmlConfiguration) GetStatement(v any) (Statement, error) {
+	id, err := statementID(v)
+	if err != nil {
+		return nil, err
+	}
+	return c.mappers.GetStatementByID(id)
+}
+
+// statementID returns the id of the statement associated with the given value.
+func statementID(v any) (string, error) {
 	if v == nil {
-		return nil, errors.New("nil statement query")
+		return "", errors.New("nil statement query")
 	}
 
 	var id string
@@ -113,15 +173,14 @@ func (c xmlConfiguration) GetStatement(v any) (Statement, error) {
 		case reflect.Struct:
 			id = rv.Type().PkgPath() + "." + rv.Type().Name()
 		default:
-			return nil, fmt.Errorf("cannot extract statement ID from value of type %T: must be string, StatementID() string interface, or struct/func", v)
+			return "", fmt.Errorf("cannot extract statement ID from value of type %T: must be string, StatementID() string interface, or struct/func", v)
 		}
 	}
 
 	if len(id) == 0 {
-		return nil, fmt.Errorf("cannot extract statement ID from value of type %T", v)
+		return "", fmt.Errorf("cannot extract statement ID from value of type %T", v)
 	}
-
-	return c.mappers.GetStatementByID(id)
+	return id, nil
 }
 
 func NewXMLConfiguration(filename string) (Configuration, error) {
@@ -17,24 +17,31 @@ limitations under the License.
 package juice
 
 import (
+	"database/sql"
 	"fmt"
 	gotoken "go/token"
 	"maps"
 	"strconv"
 	"strings"
 
+	"github.com/go-juicedev/juice/driver"
 	"github.com/go-juicedev/juice/eval"
 	"github.com/go-juicedev/juice/node"
 	configparser "github.com/go-juicedev/juice/parser"
+	"github.com/go-juicedev/juice/session/tx"
 	juicesql "github.com/go-juicedev/juice/sql"
 )
 
-func adaptSettings(source map[string]string) keyValueSettingProvider {
+func adaptSettings(source map[string]string, interpolator interpolator) (keyValueSettingProvider, error) {
 	settings := make(keyValueSettingProvider, len(source))
 	for name, value := range source {
-		settings[name] = StringValue(value)
+		expanded, err := interpolator.expand(value)
+		if err != nil {
+			return nil, fmt.Errorf("setting %s: %w", name, err)
+		}
+		settings[name] = StringValue(expanded)
 	}
-	return settings
+	return settings, nil
 }
 
 func resolveEnvironmentString(provider EnvValueProvider, value string) (string, error) {
@@ -55,7 +62,62 @@ func resolveEnvironmentInt(provider EnvValueProvider, value string) (int, error)
 	return strconv.Atoi(resolved)
 }
 
-func adaptEnvironments(source configparser.Environments) (*environments, error) {
+// resolveEnvironmentTxOptions returns the default transaction options of the
+// <transaction> element, or nil when the environment declares none.
+func resolveEnvironmentTxOptions(provider EnvValueProvider, isolation, readOnly string) (*sql.TxOptions, error) {
+	if isolation == "" && readOnly == "" {
+		return nil, nil
+	}
+	var options sql.TxOptions
+	resolved, err := resolveEnvironmentString(provider, isolation)
+	if err != nil {
+		return nil, err
+	}
+	if options.Isolation, err = tx.ParseIsolationLevel(resolved); err != nil {
+		return nil, err
+	}
+	if resolved, err = resolveEnvironmentString(provider, readOnly); err != nil {
+		return nil, err
+	}
+	if resolved != "" {
+		if options.ReadOnly, err = strconv.ParseBool(resolved); err != nil {
+			return nil, fmt.Errorf("invalid readOnly %q: %w", resolved, err)
+		}
+	}
+	return &options, nil
+}
+
+// secretPropertySuffix marks the environment properties holding a secret reference,
+// resolved by the SecretResolver of its scheme into the property without the suffix.
+const secretPropertySuffix = "Ref"
+
+// buildEnvironmentDataSource builds the data source name of the driver from the environment properties.
+func buildEnvironmentDataSource(provider EnvValueProvider, driverName string, properties map[string]string) (string, error) {
+	resolved := make(driver.DSNProperties, len(properties))
+	for name, value := range properties {
+		value, err := resolveEnvironmentString(provider, value)
+		if err != nil {
+			return "", err
+		}
+		if secretName, ok := strings.CutSuffix(name, secretPropertySuffix); ok && secretName != "" {
+			if _, exists := properties[secretName]; exists {
+				return "", fmt.Errorf("property %s conflicts with %s", name, secretName)
+			}
+			if value, err = resolveSecret(value); err != nil {
+				return "", fmt.Errorf("property %s: %w", name, err)
+			}
+			name = secretName
+		}
+		resolved[name] = value
+	}
+	drv, err := driver.Get(driverName)
+	if err != nil {
+		return "", err
+	}
+	return driver.BuildDSN(drv, resolved)
+}
+
+func adaptEnvironments(source configparser.Environments, interpolator interpolator) (*environments, error) {
 	if !source.Present {
 		return nil, nil
 	}
@@ -77,10 +139,11 @@ func adaptEnvironments(source configparser.Environments) (*environments, error)
 
 		environment := &Environment{attrs: maps.Clone(item.Attributes)}
 		environment.setAttr("id", item.ID)
-		provider, err := environment.provider()
+		envProvider, err := environment.provider()
 		if err != nil {
 			return nil, err
 		}
+		var provider EnvValueProvider = interpolatedEnvValueProvider{interpolator: interpolator, provider: envProvider}
 
 		if environment.Driver, err = resolveEnvironmentString(provider, item.Driver); err != nil {
 			return nil, err
@@ -88,6 +151,14 @@ func adaptEnvironments(source configparser.Environments) (*environments, error)
 		if environment.DataSource, err = resolveEnvironmentString(provider, item.DataSource); err != nil {
 			return nil, err
 		}
+		if len(item.Properties) > 0 {
+			if environment.DataSource != "" {
+				return nil, fmt.Errorf("environment %s: dataSource and property are mutually exclusive", item.ID)
+			}
+			if environment.DataSource, err = buildEnvironmentDataSource(provider, environment.Driver, item.Properties); err != nil {
+				return nil, fmt.Errorf("environment %s: %w", item.ID, err)
+			}
+		}
 		if environment.MaxIdleConnNum, err = resolveEnvironmentInt(provider, item.MaxIdleConns); err != nil {
 			return nil, err
 		}
@@ -100,6 +171,9 @@ func adaptEnvironments(source configparser.Environments) (*environments, error)
 		if environment.MaxIdleConnLifetime, err = resolveEnvironmentInt(provider, item.ConnMaxIdleLifetime); err != nil {
 			return nil, err
 		}
+		if environment.TxOptions, err = resolveEnvironmentTxOptions(provider, item.TxIsolation, item.TxReadOnly); err != nil {
+			return nil, fmt.Errorf("environment %s: transaction: %w", item.ID, err)
+		}
 		compiled.envs[item.ID] = environment
 	}
 	return compiled, nil
@@ -123,6 +197,18 @@ func splitOverrides(value string) []string {
 	}
 	return values
 }
+
+// splitList splits a comma separated list, dropping the blank items.
+func splitList(value string) []string {
+	var columns []string
+	for column := range strings.SplitSeq(value, ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
 func adaptNodeGroup(source []configparser.Node, mapper *Mapper) (node.Group, node.BindNodeGroup, error) {
 	nodes := make(node.Group, 0, len(source))
 	var bindings node.BindNodeGroup
@@ -154,7 +240,11 @@ func adaptIfNode(source configparser.IfNode, mapper *Mapper) (node.Node, error)
 		return nil, err
 	}
 	compiled := &node.ConditionNode{Nodes: nodes, BindNodes: bindings}
-	if err := compiled.Parse(source.Test); err != nil {
+	test := source.Test
+	if source.DatabaseID != "" {
+		test = databaseIDCondition(test, source.DatabaseID)
+	}
+	if err := compiled.Parse(test); err != nil {
 		return nil, err
 	}
 	return compiled, nil
@@ -169,7 +259,7 @@ func adaptForeachNode(source configparser.ForeachNode, mapper *Mapper) (node.Nod
 	if collection == "" {
 		collection = eval.DefaultParamKey()
 	}
-	return &node.ForeachNode{
+	compiled := &node.ForeachNode{
 		Collection: collection,
 		Nodes:      nodes,
 		Item:       source.Item,
@@ -178,7 +268,13 @@ func adaptForeachNode(source configparser.ForeachNode, mapper *Mapper) (node.Nod
 		Close:      source.Close,
 		Separator:  source.Separator,
 		BindNodes:  bindings,
-	}, nil
+	}
+	if source.Test != "" {
+		if err := compiled.Parse(source.Test); err != nil {
+			return nil, err
+		}
+	}
+	return compiled, nil
 }
 
 func adaptTrimNode(source configparser.TrimNode, mapper *Mapper) (node.Node, error) {
@@ -224,6 +320,83 @@ func adaptIncludeNode(source configparser.IncludeNode, mapper *Mapper) (node.Nod
 	return include.WithProperties(properties), nil
 }
 
+func adaptAliasNode(source configparser.AliasNode, mapper *Mapper) (node.Node, error) {
+	// the columns are resolved at load, with the namingStrategy setting: Engine.SetNamingStrategy comes too late.
+	var naming juicesql.NamingStrategy
+	if mapper != nil && mapper.mappers != nil {
+		naming = settingNamingStrategy(mapper.mappers.Configuration())
+	}
+	columns, err := juicesql.ModelColumns(source.Type, naming)
+	if err != nil {
+		return nil, fmt.Errorf("alias %s: %w", source.Type, err)
+	}
+	alias, err := node.NewAliasNode(columns, source.Table, source.Prefix)
+	if err != nil {
+		return nil, fmt.Errorf("alias %s: %w", source.Type, err)
+	}
+	return alias, nil
+}
+
+func adaptValuesNode(source configparser.ValuesNode) (node.Node, error) {
+	values, err := node.NewValuesNode(source.Collection, source.Item, source.Columns)
+	if err != nil {
+		return nil, fmt.Errorf("values %s: %w", source.Collection, err)
+	}
+	return values, nil
+}
+
+func adaptUpsertNode(source configparser.UpsertNode, mapper *Mapper) (node.Node, error) {
+	nodes, bindings, err := adaptNodeGroup(source.Children, mapper)
+	if err != nil {
+		return nil, err
+	}
+	return &node.UpsertNode{
+		Columns:   splitList(source.Columns),
+		Update:    splitList(source.Update),
+		Nodes:     nodes,
+		BindNodes: bindings,
+	}, nil
+}
+
+func adaptOrderByNode(source configparser.OrderByNode) (node.Node, error) {
+	orderBy, err := node.NewOrderByNode(source.Param, source.Allowed, source.Default)
+	if err != nil {
+		return nil, fmt.Errorf("orderBy %s: %w", source.Param, err)
+	}
+	return orderBy, nil
+}
+
+func adaptColumnsNode(source configparser.ColumnsNode) (node.Node, error) {
+	columns, err := node.NewColumnsNode(source.Param, source.Allowed, source.Default)
+	if err != nil {
+		return nil, fmt.Errorf("columns %s: %w", source.Param, err)
+	}
+	return columns, nil
+}
+
+func adaptWithNode(source configparser.WithNode, mapper *Mapper) (node.Node, error) {
+	compiled := &node.WithNode{}
+	if source.Recursive != "" {
+		recursive, err := strconv.ParseBool(source.Recursive)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recursive %q: %w", source.Recursive, err)
+		}
+		compiled.Recursive = recursive
+	}
+	for _, cte := range source.CTEs {
+		nodes, bindings, err := adaptNodeGroup(cte.Children, mapper)
+		if err != nil {
+			return nil, err
+		}
+		cteNode, err := node.NewCTENode(cte.Name, nodes, bindings)
+		if err != nil {
+			return nil, err
+		}
+		compiled.CTEs = append(compiled.CTEs, cteNode)
+	}
+	return compiled, nil
+}
+
 func adaptChooseNode(source configparser.ChooseNode, mapper *Mapper) (node.Node, error) {
 	compiled := &node.ChooseNode{}
 	for _, binding := range source.Bindings {
@@ -238,7 +411,7 @@ func adaptChooseNode(source configparser.ChooseNode, mapper *Mapper) (node.Node,
 		if err != nil {
 			return nil, err
 		}
-		whenNode := &node.ConditionNode{Nodes: nodes, BindNodes: bindings}
+		whenNode := &node.WhenNode{Nodes: nodes, BindNodes: bindings}
 		if err := whenNode.Parse(when.Test); err != nil {
 			return nil, err
 		}
@@ -272,6 +445,18 @@ func adaptNode(source configparser.Node, mapper *Mapper) (node.Node, error) {
 		return adaptSetNode(source, mapper)
 	case configparser.IncludeNode:
 		return adaptIncludeNode(source, mapper)
+	case configparser.AliasNode:
+		return adaptAliasNode(source, mapper)
+	case configparser.ValuesNode:
+		return adaptValuesNode(source)
+	case configparser.UpsertNode:
+		return adaptUpsertNode(source, mapper)
+	case configparser.OrderByNode:
+		return adaptOrderByNode(source)
+	case configparser.ColumnsNode:
+		return adaptColumnsNode(source)
+	case configparser.WithNode:
+		return adaptWithNode(source, mapper)
 	case configparser.BindNode:
 		return nil, fmt.Errorf("bind node must be compiled as part of a node group")
 	default:
@@ -279,36 +464,102 @@ func adaptNode(source configparser.Node, mapper *Mapper) (node.Node, error) {
 	}
 }
 
-func adaptMapper(mapper *Mapper, source configparser.Mapper) error {
+// adaptResultMap compiles the discriminator of source, whose cases name the models registered with sql.RegisterModel.
+func adaptResultMap(source configparser.ResultMap) (juicesql.ResultMap, error) {
+	cases := make(map[string]string, len(source.Discriminator.Cases))
+	for _, item := range source.Discriminator.Cases {
+		if _, exists := cases[item.Value]; exists {
+			return nil, fmt.Errorf("resultMap %s: duplicate case value %q", source.ID, item.Value)
+		}
+		cases[item.Value] = item.Type
+	}
+	resultMap, err := juicesql.NewDiscriminatorResultMap(source.Discriminator.Column, cases)
+	if err != nil {
+		return nil, fmt.Errorf("resultMap %s: %w", source.ID, err)
+	}
+	return resultMap, nil
+}
+
+// adaptMapper compiles the fragments, the resultMaps and the statements of source into mapper.
+// It returns the statements compiled eagerly, whose attributes are applied by adaptMappers.
+func adaptMapper(mapper *Mapper, source configparser.Mapper) ([]*mappedStatement, error) {
+	var compiled []*mappedStatement
 	for _, fragment := range source.Fragments {
 		nodes, bindNodes, err := adaptNodeGroup(fragment.Nodes, mapper)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if err := mapper.setSqlNode(&node.SQLNode{ID: fragment.ID, Nodes: nodes, BindNodes: bindNodes}); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	for _, statementDocument := range source.Statements {
-		if _, exists := mapper.statements[statementDocument.ID]; exists {
-			return fmt.Errorf("duplicate statement id: %s", statementDocument.ID)
-		}
-		nodes, bindNodes, err := adaptNodeGroup(statementDocument.Nodes, mapper)
+	for _, resultMapDocument := range source.ResultMaps {
+		resultMap, err := adaptResultMap(resultMapDocument)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if err := mapper.setResultMap(resultMapDocument.ID, resultMap); err != nil {
+			return nil, err
 		}
+	}
+
+	for _, statementDocument := range source.Statements {
 		statement := &mappedStatement{
-			mapper:    mapper,
-			action:    juicesql.Action(statementDocument.Action),
-			Nodes:     nodes,
-			bindNodes: bindNodes,
-			attrs:     maps.Clone(statementDocument.Attributes),
-			id:        statementDocument.ID,
+			mapper: mapper,
+			action: juicesql.Action(statementDocument.Action),
+			attrs:  maps.Clone(statementDocument.Attributes),
+			id:     statementDocument.ID,
 		}
 		statement.name = statement.lazyName()
-		mapper.statements[statement.id] = statement
+		if provider := statement.attrs[providerAttribute]; provider != "" {
+			if err := adaptProviderStatement(statement, provider, statementDocument.Nodes); err != nil {
+				return nil, err
+			}
+		} else if load := statementDocument.LoadNodes; load != nil {
+			// the body is compiled on first use, see mappedStatement.compile.
+			statement.lazy = &lazyStatement{load: load}
+		} else {
+			if err := compileStatementNodes(statement, statementDocument.Nodes); err != nil {
+				return nil, err
+			}
+			compiled = append(compiled, statement)
+		}
+		if err := mapper.setStatement(statement); err != nil {
+			return nil, err
+		}
+	}
+	return compiled, nil
+}
+
+// adaptStatementNodes compiles the nodes of statement and applies the rewrites of its attributes.
+func adaptStatementNodes(statement *mappedStatement, source []configparser.Node) error {
+	if err := compileStatementNodes(statement, source); err != nil {
+		return err
+	}
+	return applyStatementAttributes(statement)
+}
+
+// compileStatementNodes compiles the nodes of statement.
+func compileStatementNodes(statement *mappedStatement, source []configparser.Node) error {
+	nodes, bindNodes, err := adaptNodeGroup(source, statement.mapper)
+	if err != nil {
+		return err
 	}
+	statement.Nodes, statement.bindNodes, statement.source = nodes, bindNodes, source
+	return nil
+}
+
+// applyStatementAttributes applies the rewrites of the attributes of statement to its compiled nodes.
+// The rewrites walk the included fragments, so they are applied once every fragment is known.
+func applyStatementAttributes(statement *mappedStatement) error {
+	if err := applySoftDelete(statement); err != nil {
+		return err
+	}
+	if err := applyTenant(statement); err != nil {
+		return err
+	}
+	statement.resolveStatic()
 	return nil
 }
 
@@ -317,6 +568,8 @@ func adaptMappers(configuration Configuration, document *configparser.Document)
 		attrs: maps.Clone(document.MapperAttributes),
 		cfg:   configuration,
 	}
+	mappers := make([]*Mapper, 0, len(document.Mappers))
+	var compiledStatements []*mappedStatement
 	for _, mapperDocument := range document.Mappers {
 		mapper := &Mapper{
 			namespace:  mapperDocument.Namespace,
@@ -326,7 +579,22 @@ func adaptMappers(configuration Configuration, document *configparser.Document)
 		if err := compiled.setMapper(mapper.namespace, mapper); err != nil {
 			return nil, err
 		}
-		if err := adaptMapper(mapper, mapperDocument); err != nil {
+		statements, err := adaptMapper(mapper, mapperDocument)
+		if err != nil {
+			return nil, err
+		}
+		mappers = append(mappers, mapper)
+		compiledStatements = append(compiledStatements, statements...)
+	}
+	// the includes are resolved lazily, check them once every fragment is known.
+	if err := checkIncludeCycles(mappers, document.Mappers); err != nil {
+		return nil, err
+	}
+	if err := checkResultMaps(mappers); err != nil {
+		return nil, err
+	}
+	for _, statement := range compiledStatements {
+		if err := applyStatementAttributes(statement); err != nil {
 			return nil, err
 		}
 	}
@@ -338,11 +606,17 @@ func adaptConfigurationDocument(document *configparser.Document, ignoreEnv bool)
 		return nil, errConfigurationRequired
 	}
 
+	interpolator := interpolator{properties: document.Properties}
+	settings, err := adaptSettings(document.Settings, interpolator)
+	if err != nil {
+		return nil, err
+	}
 	configuration := &xmlConfiguration{
-		settings: adaptSettings(document.Settings),
+		settings:    settings,
+		databaseIDs: maps.Clone(document.DatabaseIDs),
 	}
 
-	environments, err := adaptEnvironments(document.Environments)
+	environments, err := adaptEnvironments(document.Environments, interpolator)
 	if err != nil {
 		return nil, err
 	}
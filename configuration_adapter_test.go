@@ -1,12 +1,14 @@
 package juice
 
 import (
+	"errors"
 	"strings"
 	"testing"
 	"testing/fstest"
 
 	"github.com/go-juicedev/juice/driver"
 	"github.com/go-juicedev/juice/eval"
+	jsql "github.com/go-juicedev/juice/sql"
 )
 
 func TestConfigurationAdapterBuildsDynamicStatement(t *testing.T) {
@@ -101,6 +103,390 @@ func TestConfigurationAdapterRejectsInvalidExpression(t *testing.T) {
 	}
 }
 
+func TestConfigurationAdapterIncludeProperties(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`
+<configuration>
+    <environments default="prod">
+        <environment id="prod"><driver>mysql</driver><dataSource>dsn</dataSource></environment>
+    </environments>
+    <mappers>
+        <mapper namespace="example.Mapper">
+            <sql id="byID">SELECT id FROM ${table} WHERE id = #{id}</sql>
+            <select id="User"><include refid="byID"><property name="table" value="users"/></include></select>
+            <select id="Order"><include refid="byID"><property name="table" value="orders"/></include></select>
+        </mapper>
+    </mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for id, want := range map[string]string{
+		"example.Mapper.User":  "SELECT id FROM users WHERE id = ?",
+		"example.Mapper.Order": "SELECT id FROM orders WHERE id = ?",
+	} {
+		statement, err := configuration.GetStatement(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the include properties win over the runtime parameter.
+		query, args, err := statement.Build(
+			driver.MySQLDriver{}.Translator(),
+			eval.NewGenericParam(eval.H{"table": "accounts", "id": 7}, ""),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if query = strings.TrimSpace(query); query != want {
+			t.Fatalf("%s: query = %q, want %q", id, query, want)
+		}
+		if len(args) != 1 || args[0] != 7 {
+			t.Fatalf("%s: unexpected args: %#v", id, args)
+		}
+	}
+}
+
+func TestConfigurationAdapterCrossNamespaceInclude(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`
+<configuration>
+    <environments default="prod">
+        <environment id="prod"><driver>mysql</driver><dataSource>dsn</dataSource></environment>
+    </environments>
+    <mappers>
+        <mapper namespace="example.UserMapper">
+            <select id="List">SELECT id FROM users <include refid="common.Pagination"/></select>
+        </mapper>
+        <mapper namespace="common">
+            <sql id="Limit">LIMIT #{limit}</sql>
+            <sql id="Pagination"><include refid="Limit"/> OFFSET #{offset}</sql>
+        </mapper>
+    </mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement, err := configuration.GetStatement("example.UserMapper.List")
+	if err != nil {
+		t.Fatal(err)
+	}
+	query, args, err := statement.Build(
+		driver.MySQLDriver{}.Translator(),
+		eval.NewGenericParam(eval.H{"limit": 10, "offset": 20}, ""),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query = strings.Join(strings.Fields(query), " "); query != "SELECT id FROM users LIMIT ? OFFSET ?" {
+		t.Fatalf("unexpected query: %q", query)
+	}
+	if len(args) != 2 || args[0] != 10 || args[1] != 20 {
+		t.Fatalf("unexpected args: %#v", args)
+	}
+}
+
+func TestConfigurationAdapterRejectsIncludeCycle(t *testing.T) {
+	tests := []struct {
+		name    string
+		mappers string
+		cycle   string
+	}{
+		{
+			name:    "self",
+			mappers: `<mapper namespace="a"><sql id="X">x <include refid="X"/></sql></mapper>`,
+			cycle:   "a.X -> a.X",
+		},
+		{
+			name: "cross namespace",
+			mappers: `
+        <mapper namespace="a"><sql id="X"><if test="true"><include refid="b.Y"/></if></sql></mapper>
+        <mapper namespace="b"><sql id="Y"><include refid="Z"/></sql><sql id="Z"><where><include refid="a.X"/></where></sql></mapper>`,
+			cycle: "a.X -> b.Y -> b.Z -> a.X",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"juice.xml": {Data: []byte(`
+<configuration>
+    <environments default="prod">
+        <environment id="prod"><driver>mysql</driver><dataSource>dsn</dataSource></environment>
+    </environments>
+    <mappers>` + tt.mappers + `</mappers>
+</configuration>`)},
+			}
+			_, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+			if !errors.Is(err, ErrIncludeCycle) {
+				t.Fatalf("expected ErrIncludeCycle, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.cycle) {
+				t.Fatalf("error %q does not contain %q", err, tt.cycle)
+			}
+		})
+	}
+}
+
+func TestConfigurationAdapterUpsert(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`
+<configuration>
+    <environments default="prod">
+        <environment id="prod"><driver>mysql</driver><dataSource>dsn</dataSource></environment>
+    </environments>
+    <mappers>
+        <mapper namespace="example.Mapper">
+            <insert id="Save">
+                INSERT INTO users (id, name) VALUES (#{id}, #{name})
+                <onConflict columns="id" update="name">
+                    <if test="touch">updated_at = NOW()</if>
+                </onConflict>
+            </insert>
+        </mapper>
+    </mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement, err := configuration.GetStatement("example.Mapper.Save")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		translator driver.Translator
+		want       string
+	}{
+		{driver.MySQLDriver{}.Translator(), "INSERT INTO users (id, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name), updated_at = NOW()"},
+		{driver.PostgresDriver{}.Translator(), "INSERT INTO users (id, name) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, updated_at = NOW()"},
+	} {
+		query, _, err := statement.Build(tt.translator, eval.NewGenericParam(eval.H{"id": 1, "name": "a", "touch": true}, ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if query = strings.Join(strings.Fields(query), " "); query != tt.want {
+			t.Fatalf("query = %q, want %q", query, tt.want)
+		}
+	}
+}
+
+func TestConfigurationAdapterAlias(t *testing.T) {
+	type author struct {
+		ID        int64  `column:"id"`
+		Name      string `column:"name"`
+		CreatedAt string
+	}
+	if err := jsql.RegisterModel[author]("models.Author"); err != nil {
+		t.Fatal(err)
+	}
+	newConfiguration := func(settings, statement string) (Configuration, error) {
+		fsys := fstest.MapFS{
+			"juice.xml": {Data: []byte(`
+<configuration>` + settings + `
+    <environments default="prod">
+        <environment id="prod"><driver>mysql</driver><dataSource>dsn</dataSource></environment>
+    </environments>
+    <mappers>
+        <mapper namespace="example.Mapper">` + statement + `</mapper>
+    </mappers>
+</configuration>`)},
+		}
+		return NewXMLConfigurationWithFS(fsys, "juice.xml")
+	}
+
+	configuration, err := newConfiguration("", `
+            <select id="List">
+                SELECT <alias type="models.Author"/>, <alias type="models.Author" table="a" prefix="author_"/> FROM author a
+            </select>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement, err := configuration.GetStatement("example.Mapper.List")
+	if err != nil {
+		t.Fatal(err)
+	}
+	query, _, err := statement.Build(driver.MySQLDriver{}.Translator(), eval.NewGenericParam(eval.H{}, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT id, name , a.id AS author_id, a.name AS author_name FROM author a"; strings.Join(strings.Fields(query), " ") != want {
+		t.Fatalf("query = %q, want %q", query, want)
+	}
+
+	_, err = newConfiguration("", `<select id="List">SELECT <alias type="models.Missing"/> FROM author</select>`)
+	if !errors.Is(err, jsql.ErrModelNotFound) {
+		t.Fatalf("expected ErrModelNotFound, got %v", err)
+	}
+
+	configuration, err = newConfiguration(`<settings><setting name="namingStrategy" value="snake"/></settings>`,
+		`<select id="List">SELECT <alias type="models.Author"/> FROM author</select>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement, err = configuration.GetStatement("example.Mapper.List")
+	if err != nil {
+		t.Fatal(err)
+	}
+	query, _, err = statement.Build(driver.MySQLDriver{}.Translator(), eval.NewGenericParam(eval.H{}, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SELECT id, name, created_at FROM author"; strings.Join(strings.Fields(query), " ") != want {
+		t.Fatalf("query = %q, want %q", query, want)
+	}
+}
+
+func TestConfigurationAdapterValues(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`
+<configuration>
+    <environments default="prod">
+        <environment id="prod"><driver>postgres</driver><dataSource>dsn</dataSource></environment>
+    </environments>
+    <mappers>
+        <mapper namespace="example.Mapper">
+            <insert id="Save">
+                INSERT INTO users <values collection="users" item="user" columns="name, createdAt:created_at"/>
+            </insert>
+        </mapper>
+    </mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement, err := configuration.GetStatement("example.Mapper.Save")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users := []eval.H{{"name": "a", "createdAt": 1}, {"name": "b", "createdAt": 2}}
+	query, args, err := statement.Build(driver.PostgresDriver{}.Translator(), eval.NewGenericParam(eval.H{"users": users}, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "INSERT INTO users (name, created_at) VALUES ($1, $2), ($3, $4)"; strings.Join(strings.Fields(query), " ") != want {
+		t.Fatalf("query = %q, want %q", query, want)
+	}
+	if len(args) != 4 || args[0] != "a" || args[3] != 2 {
+		t.Fatalf("unexpected args %v", args)
+	}
+}
+
+func TestConfigurationAdapterWith(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`
+<configuration>
+    <environments default="prod">
+        <environment id="prod"><driver>postgres</driver><dataSource>dsn</dataSource></environment>
+    </environments>
+    <mappers>
+        <mapper namespace="example.Mapper">
+            <sql id="subordinates">
+                SELECT e.id, e.manager_id FROM employees e JOIN subordinates s ON e.manager_id = s.id
+            </sql>
+            <select id="Subordinates">
+                <with recursive="true">
+                    <cte name="subordinates">
+                        SELECT id, manager_id FROM employees WHERE id = #{id}
+                        UNION ALL <include refid="subordinates"/>
+                    </cte>
+                    <cte name="role">
+                        <if test='role != ""'>SELECT id FROM employees WHERE role = #{role}</if>
+                    </cte>
+                </with>
+                SELECT id FROM subordinates
+            </select>
+        </mapper>
+    </mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement, err := configuration.GetStatement("example.Mapper.Subordinates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		param eval.H
+		want  string
+		args  int
+	}{
+		{eval.H{"id": 1, "role": ""}, "WITH RECURSIVE subordinates AS (SELECT id, manager_id FROM employees WHERE id = $1 " +
+			"UNION ALL SELECT e.id, e.manager_id FROM employees e JOIN subordinates s ON e.manager_id = s.id) SELECT id FROM subordinates", 1},
+		{eval.H{"id": 1, "role": "manager"}, "WITH RECURSIVE subordinates AS (SELECT id, manager_id FROM employees WHERE id = $1 " +
+			"UNION ALL SELECT e.id, e.manager_id FROM employees e JOIN subordinates s ON e.manager_id = s.id), " +
+			"role AS (SELECT id FROM employees WHERE role = $2) SELECT id FROM subordinates", 2},
+	} {
+		query, args, err := statement.Build(driver.PostgresDriver{}.Translator(), eval.NewGenericParam(tt.param, ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if query = strings.Join(strings.Fields(query), " "); query != tt.want || len(args) != tt.args {
+			t.Fatalf("query = %q %v, want %q", query, args, tt.want)
+		}
+	}
+
+	for _, mapper := range []string{
+		`<select id="Invalid"><with recursive="sometimes"><cte name="x">SELECT 1</cte></with>SELECT 1</select>`,
+		`<select id="Invalid"><with><cte name="x y">SELECT 1</cte></with>SELECT 1</select>`,
+		`<select id="Invalid"><with>SELECT 1</with>SELECT 1</select>`,
+	} {
+		fsys["juice.xml"] = &fstest.MapFile{Data: []byte(`<configuration><environments default="prod">` +
+			`<environment id="prod"><driver>postgres</driver><dataSource>dsn</dataSource></environment>` +
+			`</environments><mappers><mapper namespace="example.Mapper">` +
+			mapper + `</mapper></mappers></configuration>`)}
+		if _, err := NewXMLConfigurationWithFS(fsys, "juice.xml"); err == nil || strings.Contains(err.Error(), "environment") {
+			t.Errorf("expected an error for %s, got %v", mapper, err)
+		}
+	}
+}
+
+func TestConfigurationAdapterForeachTest(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`
+<configuration>
+    <environments default="prod">
+        <environment id="prod"><driver>mysql</driver><dataSource>dsn</dataSource></environment>
+    </environments>
+    <mappers>
+        <mapper namespace="example.Mapper">
+            <insert id="SaveAdults">
+                INSERT INTO users (id, age) VALUES
+                <foreach collection="users" item="user" separator="," test="user.age >= 18">
+                    (#{user.id}, #{user.age})
+                </foreach>
+            </insert>
+        </mapper>
+    </mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement, err := configuration.GetStatement("example.Mapper.SaveAdults")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users := []eval.H{{"id": 1, "age": 12}, {"id": 2, "age": 30}, {"id": 3, "age": 18}}
+	query, args, err := statement.Build(driver.MySQLDriver{}.Translator(), eval.NewGenericParam(eval.H{"users": users}, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query = strings.Join(strings.Fields(query), " "); query != "INSERT INTO users (id, age) VALUES (?, ?),(?, ?)" || len(args) != 4 {
+		t.Fatalf("query = %q %v", query, args)
+	}
+}
+
 func TestXMLConfigurationIgnoreEnvironmentSkipsEnvironmentParsing(t *testing.T) {
 	fsys := fstest.MapFS{
 		"juice.xml": {Data: []byte(`
@@ -123,3 +509,104 @@ func TestXMLConfigurationIgnoreEnvironmentSkipsEnvironmentParsing(t *testing.T)
 		t.Fatalf("expected environments to be ignored, got %#v", configuration.Environments())
 	}
 }
+
+type adapterPayment interface{ Kind() string }
+
+type adapterCard struct {
+	ID     int64  `column:"id"`
+	Number string `column:"number"`
+}
+
+func (*adapterCard) Kind() string { return "card" }
+
+type adapterBankAccount struct {
+	ID   int64  `column:"id"`
+	IBAN string `column:"iban"`
+}
+
+func (*adapterBankAccount) Kind() string { return "bank" }
+
+func TestConfigurationAdapterResultMap(t *testing.T) {
+	if err := jsql.RegisterModel[adapterCard]("models.AdapterCard"); err != nil {
+		t.Fatal(err)
+	}
+	if err := jsql.RegisterModel[adapterBankAccount]("models.AdapterBankAccount"); err != nil {
+		t.Fatal(err)
+	}
+	newConfiguration := func(mappers string) (Configuration, error) {
+		fsys := fstest.MapFS{
+			"juice.xml": {Data: []byte(`
+<configuration>
+    <environments default="prod">
+        <environment id="prod"><driver>mysql</driver><dataSource>dsn</dataSource></environment>
+    </environments>
+    <mappers>` + mappers + `</mappers>
+</configuration>`)},
+		}
+		return NewXMLConfigurationWithFS(fsys, "juice.xml")
+	}
+
+	configuration, err := newConfiguration(`
+        <mapper namespace="example.PaymentMapper">
+            <resultMap id="payment">
+                <discriminator column="type">
+                    <case value="1" type="models.AdapterCard"/>
+                    <case value="2" type="models.AdapterBankAccount"/>
+                </discriminator>
+            </resultMap>
+            <select id="List" resultMap="payment">SELECT id, type, number, iban FROM payments</select>
+            <select id="Count">SELECT COUNT(*) FROM payments</select>
+        </mapper>
+        <mapper namespace="example.OrderMapper">
+            <select id="Payments" resultMap="example.PaymentMapper.payment">SELECT id, type, number, iban FROM payments</select>
+        </mapper>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"example.PaymentMapper.List", "example.OrderMapper.Payments"} {
+		statement, err := configuration.GetStatement(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resultMap, err := statement.ResultMap()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", id, err)
+		}
+		rows := jsql.NewRowsBuffer([]string{"id", "type", "number", "iban"}, [][]any{
+			{int64(1), int64(1), "4242", nil},
+			{int64(2), int64(2), nil, "FR76"},
+		})
+		payments, err := jsql.BindWithResultMap[[]adapterPayment](rows, resultMap)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", id, err)
+		}
+		if len(payments) != 2 || payments[0].Kind() != "card" || payments[1].Kind() != "bank" {
+			t.Fatalf("%s: unexpected payments: %#v", id, payments)
+		}
+	}
+	statement, err := configuration.GetStatement("example.PaymentMapper.Count")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = statement.ResultMap(); !errors.Is(err, jsql.ErrResultMapNotSet) {
+		t.Fatalf("expected ErrResultMapNotSet, got %v", err)
+	}
+
+	_, err = newConfiguration(`
+        <mapper namespace="example.PaymentMapper">
+            <select id="List" resultMap="payment">SELECT * FROM payments</select>
+        </mapper>`)
+	if err == nil || !strings.Contains(err.Error(), `resultMap "payment" not found`) {
+		t.Fatalf("expected an error about the missing resultMap, got %v", err)
+	}
+
+	_, err = newConfiguration(`
+        <mapper namespace="example.PaymentMapper">
+            <resultMap id="payment">
+                <discriminator column="type"><case value="1" type="models.Missing"/></discriminator>
+            </resultMap>
+        </mapper>`)
+	if !errors.Is(err, jsql.ErrModelNotFound) {
+		t.Fatalf("expected ErrModelNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	unixpath "path"
+	"slices"
+	"sync"
+
+	"github.com/go-juicedev/juice/internal/rootfs"
+	configparser "github.com/go-juicedev/juice/parser"
+	xmlparser "github.com/go-juicedev/juice/parser/xml"
+)
+
+// configurationCacheVersion is the version of the format of the configuration caches.
+// It must be increased whenever the parsed document model changes.
+const configurationCacheVersion = 1
+
+// ErrConfigurationCacheStale is returned when a configuration cache was written by another version
+// of juice, for another configuration file, or when its source files have changed since.
+// The configuration should then be parsed from its sources and the cache written again.
+var ErrConfigurationCacheStale = errors.New("juice: configuration cache is stale")
+
+var errConfigurationNotCacheable = errors.New("juice: configuration is not cacheable")
+
+func init() {
+	gob.RegisterName("juice.parser.TextNode", configparser.TextNode{})
+	gob.RegisterName("juice.parser.IfNode", configparser.IfNode{})
+	gob.RegisterName("juice.parser.BindNode", configparser.BindNode{})
+	gob.RegisterName("juice.parser.ForeachNode", configparser.ForeachNode{})
+	gob.RegisterName("juice.parser.ChooseNode", configparser.ChooseNode{})
+	gob.RegisterName("juice.parser.TrimNode", configparser.TrimNode{})
+	gob.RegisterName("juice.parser.WhereNode", configparser.WhereNode{})
+	gob.RegisterName("juice.parser.SetNode", configparser.SetNode{})
+	gob.RegisterName("juice.parser.IncludeNode", configparser.IncludeNode{})
+	gob.RegisterName("juice.parser.AliasNode", configparser.AliasNode{})
+	gob.RegisterName("juice.parser.ValuesNode", configparser.ValuesNode{})
+	gob.RegisterName("juice.parser.UpsertNode", configparser.UpsertNode{})
+	gob.RegisterName("juice.parser.OrderByNode", configparser.OrderByNode{})
+	gob.RegisterName("juice.parser.ColumnsNode", configparser.ColumnsNode{})
+	gob.RegisterName("juice.parser.WithNode", configparser.WithNode{})
+}
+
+// configurationCache is the content of a configuration cache.
+type configurationCache struct {
+	Version  int
+	Path     string
+	Files    []cachedFile
+	Globs    []cachedGlob
+	Document *configparser.Document
+}
+
+// cachedFile is a source file of a cached configuration, with the hash of its content.
+type cachedFile struct {
+	Path string
+	Hash [sha256.Size]byte
+}
+
+// cachedGlob is a mapper pattern of a cached configuration, with the files it matched.
+type cachedGlob struct {
+	Pattern string
+	Matches []string
+}
+
+// WriteConfigurationCache parses the XML configuration filepath of fsys, with its mappers,
+// and writes it to w, so that it can be loaded by NewXMLConfigurationFromCache without
+// parsing XML, for example from a cache written at build time. Like for NewXMLConfigurationWithFS,
+// filepath must be a Unix-style path. The configurations with mappers loaded from an http url
+// cannot be cached, and the statements deferred by the lazyStatements setting are parsed.
+func WriteConfigurationCache(w io.Writer, fsys fs.FS, filepath string) error {
+	if filepath == "" {
+		return errConfigurationPathRequired
+	}
+	recorder := &recordingFS{FS: rootfs.New(fsys, unixpath.Dir(filepath))}
+	parser := &xmlparser.Parser{FS: recorder, Client: &http.Client{Transport: remoteMapperRefuser{}}}
+	document, err := parser.ParseFile(unixpath.Base(filepath))
+	if err != nil {
+		return err
+	}
+	// the mappers are resolved, the entries only point at them.
+	document.MapperEntries = nil
+	for i := range document.Mappers {
+		statements := document.Mappers[i].Statements
+		for j := range statements {
+			if load := statements[j].LoadNodes; load != nil {
+				if statements[j].Nodes, err = load(); err != nil {
+					return err
+				}
+				statements[j].LoadNodes = nil
+			}
+		}
+	}
+	cache := configurationCache{
+		Version:  configurationCacheVersion,
+		Path:     filepath,
+		Globs:    recorder.globs,
+		Document: document,
+	}
+	for _, path := range recorder.files {
+		hash, err := hashFile(recorder.FS, path)
+		if err != nil {
+			return err
+		}
+		cache.Files = append(cache.Files, cachedFile{Path: path, Hash: hash})
+	}
+	return gob.NewEncoder(w).Encode(cache)
+}
+
+// NewXMLConfigurationFromCache creates a configuration from a cache written by WriteConfigurationCache
+// for the XML configuration filepath of fsys. It returns ErrConfigurationCacheStale
+// when the cache does not match the source files of fsys anymore.
+func NewXMLConfigurationFromCache(r io.Reader, fsys fs.FS, filepath string) (Configuration, error) {
+	if filepath == "" {
+		return nil, errConfigurationPathRequired
+	}
+	var cache configurationCache
+	if err := gob.NewDecoder(r).Decode(&cache); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConfigurationCacheStale, err)
+	}
+	if cache.Version != configurationCacheVersion {
+		return nil, fmt.Errorf("%w: version %d, want %d", ErrConfigurationCacheStale, cache.Version, configurationCacheVersion)
+	}
+	if cache.Path != filepath {
+		return nil, fmt.Errorf("%w: written for %s", ErrConfigurationCacheStale, cache.Path)
+	}
+	root := rootfs.New(fsys, unixpath.Dir(filepath))
+	for _, file := range cache.Files {
+		hash, err := hashFile(root, file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrConfigurationCacheStale, err)
+		}
+		if hash != file.Hash {
+			return nil, fmt.Errorf("%w: %s has changed", ErrConfigurationCacheStale, file.Path)
+		}
+	}
+	for _, glob := range cache.Globs {
+		matches, err := fs.Glob(root, glob.Pattern)
+		if err != nil || !slices.Equal(matches, glob.Matches) {
+			return nil, fmt.Errorf("%w: the files matching %s have changed", ErrConfigurationCacheStale, glob.Pattern)
+		}
+	}
+	return adaptConfigurationDocument(cache.Document, false)
+}
+
+// remoteMapperRefuser is the http.RoundTripper refusing the remote mappers of a cached configuration,
+// whose changes could not be detected.
+type remoteMapperRefuser struct{}
+
+// RoundTrip implements http.RoundTripper.
+func (remoteMapperRefuser) RoundTrip(request *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("%w: mapper %s is remote", errConfigurationNotCacheable, request.URL)
+}
+
+// hashFile returns the hash of the content of the file path of fsys.
+func hashFile(fsys fs.FS, path string) ([sha256.Size]byte, error) {
+	content, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(content), nil
+}
+
+// recordingFS records the files opened and the patterns matched by the parser of a configuration.
+// It is safe for concurrent use, like by the parser of the mappers matched by a pattern.
+type recordingFS struct {
+	fs.FS
+	mu    sync.Mutex
+	files []string
+	globs []cachedGlob
+}
+
+// Open implements fs.FS.
+func (r *recordingFS) Open(name string) (fs.File, error) {
+	file, err := r.FS.Open(name)
+	if err == nil {
+		r.mu.Lock()
+		if !slices.Contains(r.files, name) {
+			r.files = append(r.files, name)
+		}
+		r.mu.Unlock()
+	}
+	return file, err
+}
+
+// Glob implements fs.GlobFS.
+func (r *recordingFS) Glob(pattern string) ([]string, error) {
+	matches, err := fs.Glob(r.FS, pattern)
+	if err == nil {
+		r.mu.Lock()
+		r.globs = append(r.globs, cachedGlob{Pattern: pattern, Matches: matches})
+		r.mu.Unlock()
+	}
+	return matches, err
+}
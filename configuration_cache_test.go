@@ -0,0 +1,104 @@
+package juice
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestConfigurationCache_configuration_cache_test(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config/juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>sqlite.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers pattern="mappers/*.xml">
+		<mapper resource="order.xml"/>
+	</mappers>
+	<settings>
+		<setting name="lazyStatements" value="true"/>
+	</settings>
+</configuration>`)},
+		"config/order.xml": {Data: []byte(`<mapper namespace="order">
+	<delete id="Delete">DELETE FROM "order" WHERE id = #{id}</delete>
+</mapper>`)},
+		"config/mappers/user.xml": {Data: []byte(`<mapper namespace="user">
+	<select id="Find">
+		SELECT * FROM user
+		<where>
+			<if test="id > 0">AND id = #{id}</if>
+			<choose>
+				<when test='name != ""'>AND name = #{name}</when>
+				<otherwise>AND status = 1</otherwise>
+			</choose>
+		</where>
+	</select>
+</mapper>`)},
+	}
+	writeCache := func() *bytes.Buffer {
+		t.Helper()
+		var cache bytes.Buffer
+		if err := WriteConfigurationCache(&cache, fsys, "config/juice.xml"); err != nil {
+			t.Fatalf("WriteConfigurationCache() error = %v", err)
+		}
+		return &cache
+	}
+	describe := func(configuration Configuration) *Description {
+		t.Helper()
+		description, err := describeConfiguration(configuration)
+		if err != nil {
+			t.Fatalf("describeConfiguration() error = %v", err)
+		}
+		return description
+	}
+
+	cache := writeCache()
+	cached, err := NewXMLConfigurationFromCache(bytes.NewReader(cache.Bytes()), fsys, "config/juice.xml")
+	if err != nil {
+		t.Fatalf("NewXMLConfigurationFromCache() error = %v", err)
+	}
+	parsed, err := NewXMLConfigurationWithFS(fsys, "config/juice.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := describe(cached), describe(parsed); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected the cached configuration to match the parsed one:\n got: %+v\nwant: %+v", got, want)
+	}
+	if cached.Settings().Get("lazyStatements").String() != "true" {
+		t.Fatal("expected the settings to be cached")
+	}
+
+	if _, err = NewXMLConfigurationFromCache(bytes.NewReader(cache.Bytes()), fsys, "juice.xml"); !errors.Is(err, ErrConfigurationCacheStale) {
+		t.Fatalf("expected a cache of another file to be stale, got %v", err)
+	}
+	if _, err = NewXMLConfigurationFromCache(bytes.NewReader([]byte("garbage")), fsys, "config/juice.xml"); !errors.Is(err, ErrConfigurationCacheStale) {
+		t.Fatalf("expected an undecodable cache to be stale, got %v", err)
+	}
+
+	fsys["config/order.xml"] = &fstest.MapFile{Data: []byte(`<mapper namespace="order">
+	<delete id="Delete">DELETE FROM "order" WHERE id = #{id} AND status = 0</delete>
+</mapper>`)}
+	if _, err = NewXMLConfigurationFromCache(bytes.NewReader(cache.Bytes()), fsys, "config/juice.xml"); !errors.Is(err, ErrConfigurationCacheStale) {
+		t.Fatalf("expected a changed mapper to make the cache stale, got %v", err)
+	}
+
+	cache = writeCache()
+	fsys["config/mappers/product.xml"] = &fstest.MapFile{Data: []byte(`<mapper namespace="product">
+	<select id="Find">SELECT * FROM product</select>
+</mapper>`)}
+	if _, err = NewXMLConfigurationFromCache(bytes.NewReader(cache.Bytes()), fsys, "config/juice.xml"); !errors.Is(err, ErrConfigurationCacheStale) {
+		t.Fatalf("expected a new file matching the pattern to make the cache stale, got %v", err)
+	}
+
+	fsys["config/juice.xml"] = &fstest.MapFile{Data: bytes.Replace(fsys["config/juice.xml"].Data,
+		[]byte(`<mapper resource="order.xml"/>`), []byte(`<mapper url="http://localhost/order.xml"/>`), 1)}
+	if err = WriteConfigurationCache(&bytes.Buffer{}, fsys, "config/juice.xml"); !errors.Is(err, errConfigurationNotCacheable) {
+		t.Fatalf("expected errConfigurationNotCacheable, got %v", err)
+	}
+}
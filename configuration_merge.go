@@ -0,0 +1,157 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// NamespaceConflictPolicy decides what MergeConfigurations does with a namespace
+// mapped by several configurations.
+type NamespaceConflictPolicy int
+
+const (
+	// NamespaceConflictError makes MergeConfigurations return ErrNamespaceConflict.
+	NamespaceConflictError NamespaceConflictPolicy = iota
+
+	// NamespaceConflictOverride uses the namespace of the last configuration mapping it.
+	NamespaceConflictOverride
+
+	// NamespaceConflictSkip uses the namespace of the first configuration mapping it.
+	NamespaceConflictSkip
+)
+
+var (
+	// ErrNamespaceConflict is returned by MergeConfigurations when several configurations
+	// map the same namespace with the NamespaceConflictError policy.
+	ErrNamespaceConflict = errors.New("juice: namespace conflict")
+
+	errMergeConfigurationRequired = errors.New("juice: at least one configuration is required to merge")
+	errMergeStatementListerNeeded = errors.New("juice: merged configurations must implement StatementLister")
+)
+
+// MergeConfigurations combines the statements of the configurations into a single one,
+// so that the modules of an application can each ship their own mappers:
+//
+//	cfg, err := juice.MergeConfigurations(juice.NamespaceConflictError, appConfiguration, pluginConfiguration)
+//
+// The configurations must implement StatementLister, like the ones created by this package.
+// A namespace mapped by several configurations is resolved with the policy.
+//
+// The environments, the settings and the databaseIds are the ones of the first configuration.
+// The eval functions scoped to the configurations are not merged, they can be registered
+// on the engine instead.
+func MergeConfigurations(policy NamespaceConflictPolicy, configurations ...Configuration) (Configuration, error) {
+	if len(configurations) == 0 || configurations[0] == nil {
+		return nil, errMergeConfigurationRequired
+	}
+	merged := &mergedConfiguration{
+		Configuration:  configurations[0],
+		configurations: slices.Clone(configurations),
+		namespaces:     make(map[string]int),
+	}
+	for index, configuration := range configurations {
+		lister, ok := configuration.(StatementLister)
+		if !ok {
+			return nil, fmt.Errorf("%w: configuration %d is %T", errMergeStatementListerNeeded, index, configuration)
+		}
+		// the namespaces of the configuration, in the order of its statements.
+		var namespaces []string
+		for _, name := range lister.StatementNames() {
+			if namespace := statementNamespace(name); !slices.Contains(namespaces, namespace) {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+		for _, namespace := range namespaces {
+			if _, exists := merged.namespaces[namespace]; exists {
+				switch policy {
+				case NamespaceConflictOverride:
+				case NamespaceConflictSkip:
+					continue
+				default:
+					return nil, fmt.Errorf("%w: %s is mapped by configuration %d and a previous one", ErrNamespaceConflict, namespace, index)
+				}
+			}
+			merged.namespaces[namespace] = index
+		}
+	}
+	return merged, nil
+}
+
+// statementNamespace returns the namespace of the statement name, which is written as namespace.id.
+func statementNamespace(name string) string {
+	if index := strings.LastIndex(name, "."); index > 0 {
+		return name[:index]
+	}
+	return name
+}
+
+// mergedConfiguration is the Configuration returned by MergeConfigurations.
+// The embedded configuration is the first one merged.
+type mergedConfiguration struct {
+	Configuration
+
+	// configurations are the merged configurations.
+	configurations []Configuration
+
+	// namespaces maps the namespaces to the index of the configuration mapping them.
+	namespaces map[string]int
+}
+
+var (
+	_ StatementLister    = (*mergedConfiguration)(nil)
+	_ databaseIDProvider = (*mergedConfiguration)(nil)
+)
+
+// GetStatement returns the statement associated with the given value
+// from the configuration mapping its namespace.
+func (c *mergedConfiguration) GetStatement(v any) (Statement, error) {
+	id, err := statementID(v)
+	if err != nil {
+		return nil, err
+	}
+	index, ok := c.namespaces[statementNamespace(id)]
+	if !ok {
+		return nil, fmt.Errorf("%w: statement '%s' not found in merged configurations", ErrNoStatementFound, id)
+	}
+	return c.configurations[index].GetStatement(id)
+}
+
+// StatementNames implements StatementLister.
+func (c *mergedConfiguration) StatementNames() []string {
+	var names []string
+	for index, configuration := range c.configurations {
+		for _, name := range configuration.(StatementLister).StatementNames() {
+			if c.namespaces[statementNamespace(name)] == index {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// databaseID implements databaseIDProvider with the first configuration.
+func (c *mergedConfiguration) databaseID(driverName string) string {
+	if provider, ok := c.Configuration.(databaseIDProvider); ok {
+		return provider.databaseID(driverName)
+	}
+	return driverName
+}
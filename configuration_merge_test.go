@@ -0,0 +1,98 @@
+package juice
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+)
+
+func newMergeTestConfiguration(t *testing.T, environment string, mappers ...string) Configuration {
+	t.Helper()
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(fmt.Sprintf(`
+<configuration>
+    <environments default="%[1]s">
+        <environment id="%[1]s"><driver>mysql</driver><dataSource>dsn</dataSource></environment>
+    </environments>
+    <mappers>%[2]s</mappers>
+</configuration>`, environment, strings.Join(mappers, "")))},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return configuration
+}
+
+func mergeTestQuery(t *testing.T, configuration Configuration, id string) string {
+	t.Helper()
+	statement, err := configuration.GetStatement(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query, _, err := statement.Build(driver.MySQLDriver{}.Translator(), eval.NewGenericParam(nil, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(query)
+}
+
+func TestMergeConfigurations(t *testing.T) {
+	app := newMergeTestConfiguration(t, "app",
+		`<mapper namespace="app.User"><select id="Find">SELECT 'app user'</select></mapper>`,
+		`<mapper namespace="shared.Audit"><select id="List">SELECT 'app audit'</select></mapper>`,
+	)
+	plugin := newMergeTestConfiguration(t, "plugin",
+		`<mapper namespace="plugin.Billing"><select id="Find">SELECT 'plugin billing'</select></mapper>`,
+		`<mapper namespace="shared.Audit"><select id="List">SELECT 'plugin audit'</select><select id="Count">SELECT 1</select></mapper>`,
+	)
+
+	if _, err := MergeConfigurations(NamespaceConflictError, app, plugin); !errors.Is(err, ErrNamespaceConflict) {
+		t.Fatalf("expected ErrNamespaceConflict, got %v", err)
+	}
+
+	skipped, err := MergeConfigurations(NamespaceConflictSkip, app, plugin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query := mergeTestQuery(t, skipped, "shared.Audit.List"); query != "SELECT 'app audit'" {
+		t.Fatalf("expected the first configuration to win, got %q", query)
+	}
+	if query := mergeTestQuery(t, skipped, "plugin.Billing.Find"); query != "SELECT 'plugin billing'" {
+		t.Fatalf("unexpected plugin query %q", query)
+	}
+	if _, err = skipped.GetStatement("shared.Audit.Count"); err == nil {
+		t.Fatal("expected the skipped namespace not to be merged")
+	}
+	if _, err = skipped.GetStatement("missing.Mapper.Find"); !errors.Is(err, ErrNoStatementFound) {
+		t.Fatalf("expected ErrNoStatementFound, got %v", err)
+	}
+	if env := skipped.Environments().Attribute("default"); env != "app" {
+		t.Fatalf("expected the environments of the first configuration, got %q", env)
+	}
+	expected := []string{"app.User.Find", "plugin.Billing.Find", "shared.Audit.List"}
+	if names := skipped.(StatementLister).StatementNames(); !slices.Equal(names, expected) {
+		t.Fatalf("expected statements %v, got %v", expected, names)
+	}
+
+	overridden, err := MergeConfigurations(NamespaceConflictOverride, app, plugin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query := mergeTestQuery(t, overridden, "shared.Audit.List"); query != "SELECT 'plugin audit'" {
+		t.Fatalf("expected the last configuration to win, got %q", query)
+	}
+	if query := mergeTestQuery(t, overridden, "app.User.Find"); query != "SELECT 'app user'" {
+		t.Fatalf("unexpected app query %q", query)
+	}
+
+	if _, err = MergeConfigurations(NamespaceConflictError); err == nil {
+		t.Fatal("expected an error without configurations")
+	}
+}
@@ -1,6 +1,7 @@
 package juice
 
 import (
+	"database/sql"
 	"embed"
 	"errors"
 	"strings"
@@ -301,3 +302,52 @@ func TestXMLConfigurationMethods_configuration_test(t *testing.T) {
 		t.Fatalf("expected empty statement id error, got %v", err)
 	}
 }
+
+func TestNewXMLConfigurationWithFSEnvironmentTransaction_configuration_test(t *testing.T) {
+	newConfiguration := func(transaction string) (Configuration, error) {
+		fsys := fstest.MapFS{
+			"juice.xml": {
+				Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>sqlite.db</dataSource>
+			<driver>sqlite3</driver>
+			` + transaction + `
+		</environment>
+	</environments>
+</configuration>`),
+			},
+		}
+		return NewXMLConfigurationWithFS(fsys, "juice.xml")
+	}
+
+	conf, err := newConfiguration(`<transaction isolation="serializable" readOnly="true"/>`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	env, err := conf.Environments().Use("prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if env.TxOptions == nil || env.TxOptions.Isolation != sql.LevelSerializable || !env.TxOptions.ReadOnly {
+		t.Fatalf("unexpected transaction options: %+v", env.TxOptions)
+	}
+	if got := lookupEnvironment(conf, "prod"); got != env {
+		t.Fatalf("expected the environment, got %+v", got)
+	}
+
+	if conf, err = newConfiguration(""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if env, _ = conf.Environments().Use("prod"); env.TxOptions != nil {
+		t.Fatalf("expected no transaction options, got %+v", env.TxOptions)
+	}
+
+	if _, err = newConfiguration(`<transaction isolation="chaos"/>`); err == nil || !strings.Contains(err.Error(), "unknown isolation level") {
+		t.Fatalf("expected unknown isolation level error, got %v", err)
+	}
+	if _, err = newConfiguration(`<transaction readOnly="maybe"/>`); err == nil || !strings.Contains(err.Error(), "invalid readOnly") {
+		t.Fatalf("expected invalid readOnly error, got %v", err)
+	}
+}
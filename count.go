@@ -0,0 +1,216 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/go-juicedev/juice/node"
+	"github.com/go-juicedev/juice/sql"
+)
+
+// ErrCountUnsupported is returned when the count query of a statement cannot be derived.
+var ErrCountUnsupported = errors.New("juice: cannot derive the count query")
+
+// countVariantSuffix is appended to the id of a statement to name its count variant.
+const countVariantSuffix = "#count"
+
+// CountVariant returns the statement counting the rows of the select statement,
+// derived from its node tree, see mappedStatement.CountVariant.
+// It returns ErrCountUnsupported if the statement is not built from a node tree, like raw SQL.
+func CountVariant(statement Statement) (Statement, error) {
+	counter, ok := statement.(interface{ CountVariant() (Statement, error) })
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrCountUnsupported, statement)
+	}
+	return counter.CountVariant()
+}
+
+// CountVariant returns the statement counting the rows of the select statement, for the totals of the pages.
+// Its node tree is the one of the statement without its ORDER BY clause and the clauses following it,
+// like LIMIT, whose select list is replaced by COUNT(*):
+//
+//	SELECT id, name FROM user <where>...</where> ORDER BY id -> SELECT COUNT(*) FROM user <where>...</where>
+//
+// Statements whose rows cannot be counted this way, like the ones with DISTINCT, GROUP BY, UNION
+// or a dynamic element outside of <where>, are wrapped in a subquery instead:
+//
+//	SELECT COUNT(*) FROM (SELECT status FROM user GROUP BY status) juice_count
+//
+// An ORDER BY clause nested in a dynamic element is kept in the subquery.
+// The count variant is named after the statement with the #count suffix, like "user.List#count".
+// Statements sharing their id with the variants of other databases must be resolved first,
+// like the Statement of an Executor.
+func (s *mappedStatement) CountVariant() (Statement, error) {
+	if s.action != sql.Select {
+		return nil, fmt.Errorf("%w: %s is not a select statement", ErrCountUnsupported, s.Name())
+	}
+	if err := s.compile(); err != nil {
+		return nil, err
+	}
+	count := s.withRootNode(countNodes(s.Nodes)).(*mappedStatement)
+	count.id, count.name, count.variants = s.id+countVariantSuffix, "", nil
+	count.resolveStatic()
+	return count, nil
+}
+
+// countNodes returns the nodes counting the rows of the select nodes.
+func countNodes(nodes node.Group) node.Group {
+	nodes = withoutOrderBy(nodes)
+	if canReplaceSelectList(nodes) {
+		first, _ := node.Text(nodes[0])
+		selectEnd, from := selectList(first)
+		counted := make(node.Group, len(nodes))
+		copy(counted, nodes)
+		counted[0] = node.NewTextNode(first[:selectEnd] + " COUNT(*) " + first[from:])
+		return counted
+	}
+	counted := make(node.Group, 0, len(nodes)+2)
+	counted = append(counted, node.NewTextNode("SELECT COUNT(*) FROM ("))
+	counted = append(counted, nodes...)
+	return append(counted, node.NewTextNode(") juice_count"))
+}
+
+// withoutOrderBy returns nodes without their top level ORDER BY clause and the nodes following it.
+// The clause is an <orderBy> element or found in the text nodes, outside of parentheses.
+func withoutOrderBy(nodes node.Group) node.Group {
+	depth := 0
+	for i, item := range nodes {
+		if _, ok := item.(*node.OrderByNode); ok && depth == 0 {
+			return nodes[:i:i]
+		}
+		text, ok := node.Text(item)
+		if !ok {
+			continue
+		}
+		if depth == 0 {
+			if start, _ := topLevelKeyword(text, 0, "ORDER BY"); start >= 0 {
+				stripped := make(node.Group, i, i+1)
+				copy(stripped, nodes[:i])
+				if text = strings.TrimRightFunc(text[:start], unicode.IsSpace); text != "" {
+					stripped = append(stripped, node.NewTextNode(text))
+				}
+				return stripped
+			}
+		}
+		depth += parenDepth(text)
+	}
+	return nodes
+}
+
+// canReplaceSelectList reports whether the select list of nodes can be replaced by COUNT(*),
+// that is when the statement is a plain SELECT ... FROM, whose select list has no function
+// nor parameter, and whose other nodes are text or <where> elements without grouping,
+// limit nor compound clauses.
+func canReplaceSelectList(nodes node.Group) bool {
+	if len(nodes) == 0 {
+		return false
+	}
+	first, _ := node.Text(nodes[0])
+	selectEnd, from := selectList(first)
+	if from < 0 || strings.ContainsAny(first[selectEnd:from], "(#$") {
+		return false
+	}
+	if word, _ := cutWord(first[selectEnd:from]); strings.EqualFold(word, "DISTINCT") || strings.EqualFold(word, "TOP") {
+		return false
+	}
+	for _, item := range nodes {
+		text, ok := node.Text(item)
+		if !ok {
+			if isWhereClause(item) {
+				continue
+			}
+			return false
+		}
+		if start, _ := topLevelKeyword(text, 0, "GROUP BY", "HAVING", "WINDOW", "UNION", "INTERSECT", "EXCEPT",
+			"LIMIT", "OFFSET", "FETCH"); start >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// selectList returns the end of the SELECT keyword starting text and the start of the FROM
+// keyword following the select list, or -1.
+func selectList(text string) (selectEnd, from int) {
+	selectEnd = matchKeyword(text, len(text)-len(strings.TrimLeftFunc(text, unicode.IsSpace)), "SELECT")
+	if selectEnd < 0 {
+		return -1, -1
+	}
+	from, _ = topLevelKeyword(text, selectEnd, "FROM")
+	return selectEnd, from
+}
+
+// parenDepth returns the number of parentheses opened and not closed in text, outside quotes.
+func parenDepth(text string) int {
+	depth := 0
+	for i := 0; i < len(text); {
+		if next := skipQuoted(text, i); next != i {
+			i = next
+			continue
+		}
+		switch text[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		i++
+	}
+	return depth
+}
+
+// Count returns the number of rows of the select statement with param, executed through manager,
+// like an Engine or a transaction, by its count variant, see CountVariant.
+//
+//	total, err := juice.Count(ctx, engine, "user.List", param)
+func Count(ctx context.Context, manager Manager, statement, param any) (int64, error) {
+	executor, ok := manager.(interface {
+		Statement(statement Statement) SQLRowsExecutor
+	})
+	if !ok {
+		return 0, ErrInvalidManager
+	}
+	object := manager.Object(statement)
+	if invalid, ok := isInvalidExecutor(object); ok {
+		return 0, invalid.err
+	}
+	variant, err := CountVariant(object.Statement())
+	if err != nil {
+		return 0, err
+	}
+	rows, err := executor.Statement(variant).QueryContext(ctx, param)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rows.Close() }()
+	if !rows.Next() {
+		if err = rows.Err(); err == nil {
+			err = fmt.Errorf("juice: %s returned no row", variant.Name())
+		}
+		return 0, err
+	}
+	var total int64
+	if err = rows.Scan(&total); err != nil {
+		return 0, err
+	}
+	return total, rows.Err()
+}
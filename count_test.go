@@ -0,0 +1,138 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	jdriver "github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+func newCountTestConfiguration(t *testing.T) Configuration {
+	t.Helper()
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="primary">
+		<environment id="primary">
+			<dataSource>primary.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>
+		<mapper namespace="user">
+			<select id="List">
+				SELECT id, name FROM user
+				<where>
+					<if test="status != 0">status = #{status}</if>
+				</where>
+				ORDER BY id DESC LIMIT 10
+			</select>
+			<select id="Grouped">SELECT status, COUNT(*) AS total FROM user GROUP BY status ORDER BY status</select>
+			<select id="Distinct">SELECT DISTINCT name FROM user WHERE id > #{id}</select>
+			<select id="Nested">SELECT * FROM (SELECT * FROM user ORDER BY id LIMIT 5) u ORDER BY id</select>
+			<select id="Sorted">
+				SELECT id FROM user
+				<if test='sort != ""'>ORDER BY ${sort}</if>
+			</select>
+			<select id="Ordered">
+				SELECT id FROM user WHERE status = #{status}
+				<orderBy param="sort" allowed="id, name"/>
+			</select>
+			<select id="Projected">
+				SELECT <columns param="fields" allowed="id, name"/> FROM user
+			</select>
+			<delete id="Delete">DELETE FROM user WHERE id = #{id}</delete>
+		</mapper>
+	</mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return configuration
+}
+
+func TestCountVariant_count_test(t *testing.T) {
+	configuration := newCountTestConfiguration(t)
+	tests := []struct {
+		id       string
+		param    eval.H
+		want     string
+		wantArgs []any
+	}{
+		{"user.List", eval.H{"status": 1}, "SELECT COUNT(*) FROM user WHERE status = ?", []any{1}},
+		{"user.List", eval.H{"status": 0}, "SELECT COUNT(*) FROM user", nil},
+		{"user.Grouped", nil, "SELECT COUNT(*) FROM ( SELECT status, COUNT(*) AS total FROM user GROUP BY status ) juice_count", nil},
+		{"user.Distinct", eval.H{"id": 2}, "SELECT COUNT(*) FROM ( SELECT DISTINCT name FROM user WHERE id > ? ) juice_count", []any{2}},
+		{"user.Ordered", eval.H{"status": 1, "sort": "-id"}, "SELECT COUNT(*) FROM user WHERE status = ?", []any{1}},
+		{"user.Projected", eval.H{"fields": "name"}, "SELECT COUNT(*) FROM ( SELECT name FROM user ) juice_count", nil},
+		{"user.Nested", nil, "SELECT COUNT(*) FROM (SELECT * FROM user ORDER BY id LIMIT 5) u", nil},
+		{"user.Sorted", eval.H{"sort": "id"}, "SELECT COUNT(*) FROM ( SELECT id FROM user ORDER BY id ) juice_count", nil},
+	}
+	for _, tt := range tests {
+		statement, err := configuration.GetStatement(tt.id)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.id, err)
+		}
+		count, err := CountVariant(statement)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.id, err)
+		}
+		if count.Name() != tt.id+"#count" || count.Action() != jsql.Select {
+			t.Errorf("%s: unexpected count variant %s %s", tt.id, count.Action(), count.Name())
+		}
+		query, args, err := count.Build(jdriver.SQLiteDriver{}.Translator(), eval.NewGenericParam(tt.param, ""))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.id, err)
+		}
+		if query = strings.Join(strings.Fields(query), " "); query != tt.want || len(args)+len(tt.wantArgs) > 0 && !reflect.DeepEqual(args, tt.wantArgs) {
+			t.Errorf("%s: got %q %v, want %q %v", tt.id, query, args, tt.want, tt.wantArgs)
+		}
+	}
+
+	statement, err := configuration.GetStatement("user.Delete")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = CountVariant(statement); !errors.Is(err, ErrCountUnsupported) {
+		t.Fatalf("expected ErrCountUnsupported, got %v", err)
+	}
+	if _, err = CountVariant(NewRawSQLStatement("SELECT 1", jsql.Select)); !errors.Is(err, ErrCountUnsupported) {
+		t.Fatalf("expected ErrCountUnsupported for raw SQL, got %v", err)
+	}
+}
+
+func TestCount_count_test(t *testing.T) {
+	middleware := &keysetTestMiddleware{rows: [][]any{{int64(1), "a"}, {int64(2), "b"}}}
+	engine := newStatementTestEngine(nil, middleware)
+	engine.configuration = newCountTestConfiguration(t)
+	engine.db = openStatementTestDB(t, &shSQLDriverState{})
+	ctx := context.Background()
+
+	total, err := Count(ctx, engine, "user.List", eval.H{"status": 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 2 {
+		t.Fatalf("expected 2, got %d", total)
+	}
+	if want := [][]any{{"SELECT COUNT(*) FROM user WHERE status = ?", 1}}; !reflect.DeepEqual(middleware.queries, want) {
+		t.Fatalf("unexpected queries %v", middleware.queries)
+	}
+	if _, err = Count(ctx, engine, "user.Missing", nil); err == nil {
+		t.Fatal("expected error for a missing statement")
+	}
+	if _, err = Count(ctx, &managerStub{}, "user.List", nil); !errors.Is(err, ErrInvalidManager) {
+		t.Fatalf("expected ErrInvalidManager, got %v", err)
+	}
+	if _, err = Count(ctx, engine.Tx(), "user.List", nil); err == nil {
+		t.Fatal("expected error for a transaction not begun")
+	}
+}
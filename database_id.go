@@ -0,0 +1,110 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// databaseIDAttribute marks a statement as the variant of its id for a database.
+// Statements sharing an id are resolved against the databaseId of the engine driver,
+// a statement without databaseId is used when no variant matches.
+const databaseIDAttribute = "databaseId"
+
+// databaseIDProvider is implemented by the configurations mapping driver names to databaseIds.
+type databaseIDProvider interface {
+	databaseID(driverName string) string
+}
+
+// databaseID returns the databaseId of the driver, the driver name itself if it is not mapped.
+func (c *xmlConfiguration) databaseID(driverName string) string {
+	if databaseID, ok := c.databaseIDs[driverName]; ok {
+		return databaseID
+	}
+	return driverName
+}
+
+// databaseID returns the databaseId of the driver of the engine, exposed to the statements as _databaseId.
+func (e *Engine) databaseID() string {
+	driverName := e.Driver().Name()
+	if provider, ok := e.configuration.(databaseIDProvider); ok {
+		return provider.databaseID(driverName)
+	}
+	return driverName
+}
+
+// forDatabase returns the variant of the statement for the databaseId.
+func (s *mappedStatement) forDatabase(databaseID string) (Statement, error) {
+	if variant, ok := s.variants[databaseID]; ok {
+		return variant, nil
+	}
+	if s.attrs[databaseIDAttribute] == "" {
+		return s, nil
+	}
+	return nil, fmt.Errorf("%w: statement '%s' has no variant for databaseId %q", ErrNoStatementFound, s.Name(), databaseID)
+}
+
+// resolveDatabaseStatement returns the variant of the statement for the databaseId of the engine.
+// Statements without variants are returned as they are.
+func resolveDatabaseStatement(statement Statement, engine *Engine) (Statement, error) {
+	mapped, ok := statement.(*mappedStatement)
+	if !ok || len(mapped.variants) == 0 {
+		return statement, nil
+	}
+	return mapped.forDatabase(engine.databaseID())
+}
+
+// setStatement registers the statement in the mapper.
+// Statements sharing an id must have distinct databaseIds, at most one of them has none.
+// The statement without databaseId, or the first one registered, holds the variants of the others.
+func (m *Mapper) setStatement(statement *mappedStatement) error {
+	databaseID := statement.attrs[databaseIDAttribute]
+	existing, exists := m.statements[statement.id]
+	if !exists {
+		if databaseID != "" {
+			statement.variants = map[string]*mappedStatement{databaseID: statement}
+		}
+		m.statements[statement.id] = statement
+		return nil
+	}
+	if databaseID == "" {
+		if existing.attrs[databaseIDAttribute] == "" {
+			return fmt.Errorf("duplicate statement id: %s", statement.id)
+		}
+		statement.variants, existing.variants = existing.variants, nil
+		m.statements[statement.id] = statement
+		return nil
+	}
+	if _, duplicated := existing.variants[databaseID]; duplicated {
+		return fmt.Errorf("duplicate statement id: %s with databaseId %s", statement.id, databaseID)
+	}
+	if existing.variants == nil {
+		existing.variants = make(map[string]*mappedStatement)
+	}
+	existing.variants[databaseID] = statement
+	return nil
+}
+
+// databaseIDCondition returns the test of an <if> element selected by databaseId.
+func databaseIDCondition(test, databaseID string) string {
+	condition := "_databaseId == " + strconv.Quote(databaseID)
+	if test == "" {
+		return condition
+	}
+	return "(" + test + ") && " + condition
+}
@@ -0,0 +1,82 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDatabaseIDStatements_database_id_test(t *testing.T) {
+	newConfiguration := func(mapper string) (Configuration, error) {
+		fsys := fstest.MapFS{
+			"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<databaseIdProvider>
+		<property name="sqlite3" value="lite"/>
+	</databaseIdProvider>
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>sqlite.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>` + mapper + `</mappers>
+</configuration>`)},
+		}
+		return NewXMLConfigurationWithFS(fsys, "juice.xml")
+	}
+
+	configuration, err := newConfiguration(`
+		<mapper namespace="user">
+			<select id="Find" databaseId="mysql">SELECT * FROM user LIMIT 1</select>
+			<select id="Find">SELECT * FROM user FETCH FIRST 1 ROWS ONLY</select>
+			<select id="Find" databaseId="lite">SELECT * FROM user <if databaseId="lite">LIMIT 1</if><if test="id > 0" databaseId="mysql">OFFSET 1</if></select>
+			<select id="Only" databaseId="mysql">SELECT 1</select>
+		</mapper>`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	engine := newStatementTestEngine(nil)
+	engine.configuration = configuration
+
+	statement, err := configuration.GetStatement("user.Find")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resolved, err := resolveDatabaseStatement(statement, engine)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved.Attribute("databaseId") != "lite" {
+		t.Fatalf("expected the lite variant, got %q", resolved.Attribute("databaseId"))
+	}
+	query, _, err := buildStatementQuery(context.Background(), resolved, engine, H{"id": 1})
+	if err != nil || strings.Join(strings.Fields(query), " ") != "SELECT * FROM user LIMIT 1" {
+		t.Fatalf("unexpected query %q err=%v", query, err)
+	}
+
+	// without mapping, the databaseId is the driver name and the default statement is used.
+	engine.configuration.(*xmlConfiguration).databaseIDs = nil
+	if resolved, err = resolveDatabaseStatement(statement, engine); err != nil || resolved.Attribute("databaseId") != "" {
+		t.Fatalf("expected the default statement, got %v err=%v", resolved, err)
+	}
+
+	statement, err = configuration.GetStatement("user.Only")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = resolveDatabaseStatement(statement, engine); !errors.Is(err, ErrNoStatementFound) {
+		t.Fatalf("expected ErrNoStatementFound, got %v", err)
+	}
+
+	for _, mapper := range []string{
+		`<mapper namespace="user"><select id="Find">SELECT 1</select><select id="Find">SELECT 2</select></mapper>`,
+		`<mapper namespace="user"><select id="Find" databaseId="lite">SELECT 1</select><select id="Find" databaseId="lite">SELECT 2</select></mapper>`,
+	} {
+		if _, err = newConfiguration(mapper); err == nil || !strings.Contains(err.Error(), "duplicate statement id") {
+			t.Errorf("expected a duplicate statement error, got %v", err)
+		}
+	}
+}
@@ -19,6 +19,7 @@ limitations under the License.
 package juice
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -161,6 +162,52 @@ func (m *DBManager) Registered() []string {
 	return slices.Clone(m.names)
 }
 
+// sourceOf returns the name of the source whose connection pool is db.
+func (m *DBManager) sourceOf(db *sql.DB) (name string, ok bool) {
+	m.conns.Range(func(key, value any) bool {
+		if value.(*conn).db == db {
+			name, ok = key.(string), true
+		}
+		return !ok
+	})
+	return name, ok
+}
+
+// Stats returns the statistics of the connection pools opened by the manager, keyed by source name.
+// Sources which have not been connected yet are not reported.
+func (m *DBManager) Stats() map[string]sql.DBStats {
+	stats := make(map[string]sql.DBStats)
+	m.conns.Range(func(key, value any) bool {
+		if c := value.(*conn); c.db != nil {
+			stats[key.(string)] = c.db.Stats()
+		}
+		return true
+	})
+	return stats
+}
+
+// HealthCheck connects to and pings every registered source concurrently.
+// It returns nil if all of them are reachable, otherwise the errors of the failing sources
+// joined together, each one prefixed with the name of its source.
+func (m *DBManager) HealthCheck(ctx context.Context) error {
+	names := m.Registered()
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Go(func() {
+			db, _, err := m.Get(name)
+			if err == nil {
+				err = db.PingContext(ctx)
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("juice: source %s: %w", name, err)
+			}
+		})
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
 // Close gracefully shuts down all managed database connections.
 // It ensures that all resources are properly released and prevents new connections
 // from being established. This method is idempotent and thread-safe.
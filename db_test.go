@@ -1,9 +1,11 @@
 package juice
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"sync/atomic"
 	"testing"
 
@@ -139,3 +141,34 @@ func TestNewRejectsConfigurationWithoutEnvironments(t *testing.T) {
 		t.Fatalf("New(invalidConfiguration{}) error = %v, want %v", err, errConfigurationEnvironmentsRequired)
 	}
 }
+
+func TestDBManagerStatsAndHealthCheck(t *testing.T) {
+	driverName := registerDBManagerTestDriver(t)
+	manager := &DBManager{}
+	if err := manager.Add("primary", Source{Driver: driverName, MaxOpenConns: 3}); err != nil {
+		t.Fatalf("Add(primary) error = %v", err)
+	}
+	if err := manager.Add("replica", Source{Driver: driverName}); err != nil {
+		t.Fatalf("Add(replica) error = %v", err)
+	}
+	defer func() { _ = manager.Close() }()
+
+	if stats := manager.Stats(); len(stats) != 0 {
+		t.Fatalf("Stats() = %v, want no opened source", stats)
+	}
+	if err := manager.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("HealthCheck() error = %v", err)
+	}
+	stats := manager.Stats()
+	if len(stats) != 2 || stats["primary"].MaxOpenConnections != 3 {
+		t.Fatalf("Stats() = %v, want primary and replica", stats)
+	}
+
+	if err := manager.Add("broken", Source{Driver: "juice_db_manager_test_unknown"}); err != nil {
+		t.Fatalf("Add(broken) error = %v", err)
+	}
+	err := manager.HealthCheck(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "source broken") || strings.Contains(err.Error(), "primary") {
+		t.Fatalf("HealthCheck() error = %v, want the broken source only", err)
+	}
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	configparser "github.com/go-juicedev/juice/parser"
+	"github.com/go-juicedev/juice/sql"
+)
+
+var errDescribeStatementListerNeeded = errors.New("juice: described configuration must implement StatementLister")
+
+// StatementDescription describes a statement loaded by the configuration of an engine.
+type StatementDescription struct {
+	// Name is the fully qualified name of the statement.
+	Name string
+
+	// Namespace is the namespace of the mapper of the statement.
+	Namespace string
+
+	// ID is the identifier of the statement within its namespace.
+	ID string
+
+	// Action is the action of the statement.
+	Action sql.Action
+
+	// Attributes holds the attributes of the statement, including the ones inherited
+	// from its mapper. It is nil for the statements which do not come from a mapper.
+	Attributes map[string]string
+
+	// SQL is the skeleton of the statement: its body before rendering,
+	// with the dynamic elements kept as markup, like
+	//
+	//	SELECT * FROM user <where><if test="id > 0">AND id = #{id}</if></where>
+	//
+	// It is empty for the statements without a body, like the ones with a provider.
+	SQL string
+}
+
+// Description describes the statements of the configuration of an engine.
+type Description struct {
+	// Namespaces holds the namespaces of the mappers, sorted.
+	Namespaces []string
+
+	// Statements holds the statements sorted by name. The statements sharing
+	// an id for several databaseIds are described once per databaseId.
+	Statements []StatementDescription
+}
+
+// StatementStats summarizes the statements of the configuration of an engine.
+type StatementStats struct {
+	// Namespaces is the number of namespaces.
+	Namespaces int
+
+	// Statements is the number of statements, counting every databaseId variant.
+	Statements int
+
+	// Actions is the number of statements per action.
+	Actions map[sql.Action]int
+}
+
+// statementDescriber is implemented by the statements which can describe themselves
+// beyond their metadata, like the statements of the mappers.
+type statementDescriber interface {
+	describe() ([]StatementDescription, error)
+}
+
+// Describe describes the statements loaded by the configuration of the engine,
+// for admin endpoints, documentation generation or debugging.
+// The configuration must implement StatementLister, like the ones created by this package.
+// The statements loaded with the lazyStatements setting are compiled to be described.
+func (e *Engine) Describe() (*Description, error) {
+	return describeConfiguration(e.GetConfiguration())
+}
+
+// describeConfiguration describes the statements of configuration, see Engine.Describe.
+func describeConfiguration(configuration Configuration) (*Description, error) {
+	lister, ok := configuration.(StatementLister)
+	if !ok {
+		return nil, fmt.Errorf("%w: configuration is %T", errDescribeStatementListerNeeded, configuration)
+	}
+	description := &Description{}
+	for _, name := range lister.StatementNames() {
+		statement, err := configuration.GetStatement(name)
+		if err != nil {
+			return nil, err
+		}
+		statements, err := describeStatement(statement)
+		if err != nil {
+			return nil, err
+		}
+		for _, statement := range statements {
+			if !slices.Contains(description.Namespaces, statement.Namespace) {
+				description.Namespaces = append(description.Namespaces, statement.Namespace)
+			}
+		}
+		description.Statements = append(description.Statements, statements...)
+	}
+	slices.Sort(description.Namespaces)
+	return description, nil
+}
+
+// StatementStats summarizes the statements loaded by the configuration of the engine, see Describe.
+func (e *Engine) StatementStats() (StatementStats, error) {
+	description, err := e.Describe()
+	if err != nil {
+		return StatementStats{}, err
+	}
+	stats := StatementStats{
+		Namespaces: len(description.Namespaces),
+		Statements: len(description.Statements),
+		Actions:    make(map[sql.Action]int),
+	}
+	for _, statement := range description.Statements {
+		stats.Actions[statement.Action]++
+	}
+	return stats, nil
+}
+
+// describeStatement describes statement, from its metadata unless it is a statementDescriber.
+func describeStatement(statement Statement) ([]StatementDescription, error) {
+	if describer, ok := statement.(statementDescriber); ok {
+		return describer.describe()
+	}
+	name, id := statement.Name(), statement.ID()
+	return []StatementDescription{{
+		Name:      name,
+		Namespace: strings.TrimSuffix(strings.TrimSuffix(name, id), "."),
+		ID:        id,
+		Action:    statement.Action(),
+	}}, nil
+}
+
+// describe implements statementDescriber, describing the statement and its databaseId variants.
+func (s *mappedStatement) describe() ([]StatementDescription, error) {
+	statements := []*mappedStatement{s}
+	for _, databaseID := range slices.Sorted(maps.Keys(s.variants)) {
+		if variant := s.variants[databaseID]; variant != s {
+			statements = append(statements, variant)
+		}
+	}
+	descriptions := make([]StatementDescription, 0, len(statements))
+	for _, statement := range statements {
+		if err := statement.compile(); err != nil {
+			return nil, err
+		}
+		attributes := maps.Clone(statement.mapper.attrs)
+		if attributes == nil {
+			attributes = make(map[string]string, len(statement.attrs))
+		}
+		maps.Copy(attributes, statement.attrs)
+		descriptions = append(descriptions, StatementDescription{
+			Name:       statement.Name(),
+			Namespace:  statement.mapper.namespace,
+			ID:         statement.id,
+			Action:     statement.action,
+			Attributes: attributes,
+			SQL:        skeleton(statement.source),
+		})
+	}
+	return descriptions, nil
+}
+
+// skeleton renders nodes as markup, with the runs of whitespace collapsed.
+func skeleton(nodes []configparser.Node) string {
+	var builder strings.Builder
+	writeSkeleton(&builder, nodes)
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+// writeSkeleton writes nodes as markup to builder.
+func writeSkeleton(builder *strings.Builder, nodes []configparser.Node) {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case configparser.TextNode:
+			builder.WriteString(n.Text)
+		case configparser.IfNode:
+			writeSkeletonElement(builder, "if", n.Children, "test", n.Test, "databaseId", n.DatabaseID)
+		case configparser.BindNode:
+			writeSkeletonElement(builder, "bind", nil, "name", n.Name, "value", n.Value)
+		case configparser.ForeachNode:
+			writeSkeletonElement(builder, "foreach", n.Children, "collection", n.Collection, "item", n.Item,
+				"index", n.Index, "open", n.Open, "close", n.Close, "separator", n.Separator, "test", n.Test)
+		case configparser.ChooseNode:
+			builder.WriteString("<choose>")
+			for _, binding := range n.Bindings {
+				writeSkeleton(builder, []configparser.Node{binding})
+			}
+			for _, when := range n.Whens {
+				writeSkeletonElement(builder, "when", when.Children, "test", when.Test)
+			}
+			if n.HasOtherwise {
+				writeSkeletonElement(builder, "otherwise", n.Otherwise)
+			}
+			builder.WriteString("</choose>")
+		case configparser.TrimNode:
+			writeSkeletonElement(builder, "trim", n.Children, "prefix", n.Prefix, "prefixOverrides", n.PrefixOverrides,
+				"suffix", n.Suffix, "suffixOverrides", n.SuffixOverrides)
+		case configparser.WhereNode:
+			writeSkeletonElement(builder, "where", n.Children)
+		case configparser.SetNode:
+			writeSkeletonElement(builder, "set", n.Children)
+		case configparser.IncludeNode:
+			if len(n.Properties) == 0 {
+				writeSkeletonElement(builder, "include", nil, "refid", n.RefID)
+				continue
+			}
+			builder.WriteString(`<include refid="` + skeletonAttributeValue(n.RefID) + `">`)
+			for _, name := range slices.Sorted(maps.Keys(n.Properties)) {
+				writeSkeletonElement(builder, "property", nil, "name", name, "value", n.Properties[name])
+			}
+			builder.WriteString("</include>")
+		case configparser.AliasNode:
+			writeSkeletonElement(builder, "alias", nil, "type", n.Type, "table", n.Table, "prefix", n.Prefix)
+		case configparser.ValuesNode:
+			writeSkeletonElement(builder, "values", nil, "collection", n.Collection, "item", n.Item, "columns", n.Columns)
+		case configparser.UpsertNode:
+			writeSkeletonElement(builder, "onConflict", n.Children, "columns", n.Columns, "update", n.Update)
+		case configparser.OrderByNode:
+			writeSkeletonElement(builder, "orderBy", nil, "param", n.Param, "allowed", n.Allowed, "default", n.Default)
+		case configparser.ColumnsNode:
+			writeSkeletonElement(builder, "columns", nil, "param", n.Param, "allowed", n.Allowed, "default", n.Default)
+		case configparser.WithNode:
+			if n.Recursive != "" {
+				builder.WriteString(`<with recursive="` + skeletonAttributeValue(n.Recursive) + `">`)
+			} else {
+				builder.WriteString("<with>")
+			}
+			for _, cte := range n.CTEs {
+				writeSkeletonElement(builder, "cte", cte.Children, "name", cte.Name)
+			}
+			builder.WriteString("</with>")
+		}
+	}
+}
+
+// writeSkeletonElement writes an element with its non-empty attributes, given as name and value pairs,
+// and its children. An element without children is self-closing.
+func writeSkeletonElement(builder *strings.Builder, element string, children []configparser.Node, attributes ...string) {
+	builder.WriteString("<" + element)
+	for i := 0; i+1 < len(attributes); i += 2 {
+		if attributes[i+1] != "" {
+			builder.WriteString(" " + attributes[i] + `="` + skeletonAttributeValue(attributes[i+1]) + `"`)
+		}
+	}
+	if len(children) == 0 {
+		builder.WriteString("/>")
+		return
+	}
+	builder.WriteString(">")
+	writeSkeleton(builder, children)
+	builder.WriteString("</" + element + ">")
+}
+
+// skeletonAttributeValue escapes the double quotes of an attribute value.
+func skeletonAttributeValue(value string) string {
+	return strings.ReplaceAll(value, `"`, "&quot;")
+}
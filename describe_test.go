@@ -0,0 +1,101 @@
+package juice
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-juicedev/juice/sql"
+)
+
+func TestEngineDescribe_describe_test(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": &fstest.MapFile{Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>sqlite.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>
+		<mapper namespace="user" cacheTTL="60">
+			<sql id="columns">id, name</sql>
+			<select id="Find" slowThreshold="200ms">
+				SELECT <include refid="columns"/> FROM user
+				<where>
+					<if test="id > 0">AND id = #{id}</if>
+					<foreach collection="names" item="name" open="AND name IN (" separator="," close=")">#{name}</foreach>
+				</where>
+			</select>
+			<select id="Now">SELECT NOW()</select>
+			<select id="Now" databaseId="sqlite">SELECT datetime('now')</select>
+		</mapper>
+		<mapper namespace="order">
+			<delete id="Delete">DELETE FROM "order" WHERE id = #{id}</delete>
+		</mapper>
+	</mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	engine := &Engine{configuration: configuration}
+
+	description, err := engine.Describe()
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if want := []string{"order", "user"}; !reflect.DeepEqual(description.Namespaces, want) {
+		t.Fatalf("expected namespaces %v, got %v", want, description.Namespaces)
+	}
+	var names []string
+	for _, statement := range description.Statements {
+		names = append(names, statement.Name)
+	}
+	if want := []string{"order.Delete", "user.Find", "user.Now", "user.Now"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("expected statements %v, got %v", want, names)
+	}
+
+	find := description.Statements[1]
+	if find.Namespace != "user" || find.ID != "Find" || find.Action != sql.Select {
+		t.Fatalf("unexpected statement description: %+v", find)
+	}
+	if find.Attributes["slowThreshold"] != "200ms" || find.Attributes["cacheTTL"] != "60" {
+		t.Fatalf("expected the attributes of the statement and of its mapper, got %v", find.Attributes)
+	}
+	want := `SELECT <include refid="columns"/> FROM user <where><if test="id > 0">AND id = #{id}</if>` +
+		`<foreach collection="names" item="name" open="AND name IN (" close=")" separator=",">#{name}</foreach></where>`
+	if find.SQL != want {
+		t.Fatalf("unexpected skeleton:\n got: %s\nwant: %s", find.SQL, want)
+	}
+	if now := description.Statements[3]; now.Attributes["databaseId"] != "sqlite" || now.SQL != "SELECT datetime('now')" {
+		t.Fatalf("expected the databaseId variant to be described, got %+v", now)
+	}
+
+	stats, err := engine.StatementStats()
+	if err != nil {
+		t.Fatalf("StatementStats() error = %v", err)
+	}
+	if stats.Namespaces != 2 || stats.Statements != 4 || stats.Actions[sql.Select] != 3 || stats.Actions[sql.Delete] != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	analyses, err := engine.AnalyzeStatements()
+	if err != nil {
+		t.Fatalf("AnalyzeStatements() error = %v", err)
+	}
+	if analysis := analyses["user.Find"]; !analysis.References("user") || len(analysis.WrittenTables) != 0 {
+		t.Fatalf("unexpected analysis of user.Find: %+v", analysis)
+	}
+	if analysis := analyses["order.Delete"]; !analysis.Writes("order") {
+		t.Fatalf("unexpected analysis of order.Delete: %+v", analysis)
+	}
+
+	engine.configuration = invalidConfiguration{}
+	if _, err = engine.Describe(); !errors.Is(err, errDescribeStatementListerNeeded) {
+		t.Fatalf("expected errDescribeStatementListerNeeded, got %v", err)
+	}
+}
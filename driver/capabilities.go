@@ -0,0 +1,218 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+// LimitStyle is the syntax of a dialect to limit the rows of a query.
+type LimitStyle int
+
+const (
+	// LimitOffset limits the rows with LIMIT n OFFSET m, like MySQL, PostgreSQL and SQLite.
+	LimitOffset LimitStyle = iota
+
+	// OffsetFetch limits the rows with OFFSET m ROWS FETCH NEXT n ROWS ONLY, like Oracle and SQL Server.
+	// SQL Server requires the query to have an ORDER BY clause.
+	OffsetFetch
+)
+
+// UpsertStyle is the syntax of a dialect to update the row an INSERT conflicts with.
+type UpsertStyle int
+
+const (
+	// UpsertUnsupported means that the dialect has no upsert clause for INSERT, like
+	// Oracle and SQL Server, which merge rows with a MERGE statement instead.
+	UpsertUnsupported UpsertStyle = iota
+
+	// OnConflict updates the row with ON CONFLICT (cols) DO UPDATE SET, like PostgreSQL and SQLite.
+	// The inserted values are referenced as EXCLUDED.col.
+	OnConflict
+
+	// OnDuplicateKey updates the row with ON DUPLICATE KEY UPDATE, like MySQL.
+	// The inserted values are referenced as VALUES(col).
+	OnDuplicateKey
+)
+
+// SavepointStyle is the syntax of a dialect to manage the savepoints of a transaction.
+type SavepointStyle int
+
+const (
+	// StandardSavepoint uses SAVEPOINT, ROLLBACK TO SAVEPOINT and RELEASE SAVEPOINT,
+	// like MySQL, PostgreSQL and SQLite.
+	StandardSavepoint SavepointStyle = iota
+
+	// SavepointWithoutRelease uses SAVEPOINT and ROLLBACK TO SAVEPOINT, like Oracle,
+	// whose savepoints are released with their transaction only.
+	SavepointWithoutRelease
+
+	// SaveTransaction uses SAVE TRANSACTION and ROLLBACK TRANSACTION, like SQL Server,
+	// whose savepoints are released with their transaction only.
+	SaveTransaction
+)
+
+// Capabilities describes the features of a dialect beyond its placeholders,
+// so that the features depending on them can degrade gracefully.
+type Capabilities struct {
+	// LimitStyle is the syntax used to limit the rows of a query.
+	LimitStyle LimitStyle
+
+	// Returning reports whether data-modifying statements can return rows with a RETURNING clause.
+	Returning bool
+
+	// LastInsertID reports whether sql.Result.LastInsertId reports the generated keys.
+	LastInsertID bool
+
+	// MultiRowValues reports whether INSERT statements accept several rows of VALUES.
+	MultiRowValues bool
+
+	// Explain is the prefix turning a query into a query returning its plan,
+	// or empty if the plan cannot be queried this way.
+	Explain string
+
+	// Upsert is the syntax used to update the row an INSERT conflicts with.
+	Upsert UpsertStyle
+
+	// MaxPlaceholders is the maximum number of placeholders of a statement, or zero if unknown.
+	MaxPlaceholders int
+
+	// RowValues reports whether row values can be compared, like (a, b) > (?, ?).
+	RowValues bool
+
+	// Savepoint is the syntax used to manage the savepoints of a transaction.
+	Savepoint SavepointStyle
+}
+
+// SavepointClause returns the statement creating the savepoint name.
+func (c Capabilities) SavepointClause(name string) string {
+	if c.Savepoint == SaveTransaction {
+		return "SAVE TRANSACTION " + name
+	}
+	return "SAVEPOINT " + name
+}
+
+// RollbackToSavepointClause returns the statement rolling the transaction back to the savepoint name.
+func (c Capabilities) RollbackToSavepointClause(name string) string {
+	if c.Savepoint == SaveTransaction {
+		return "ROLLBACK TRANSACTION " + name
+	}
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+// ReleaseSavepointClause returns the statement releasing the savepoint name,
+// or an empty string if the dialect cannot release a savepoint.
+func (c Capabilities) ReleaseSavepointClause(name string) string {
+	if c.Savepoint != StandardSavepoint {
+		return ""
+	}
+	return "RELEASE SAVEPOINT " + name
+}
+
+// LimitClause returns the clause limiting a query to limit rows after skipping offset rows.
+// limit and offset are SQL expressions, like placeholders. An empty offset skips no rows.
+func (c Capabilities) LimitClause(limit, offset string) string {
+	switch c.LimitStyle {
+	case OffsetFetch:
+		if offset == "" {
+			offset = "0"
+		}
+		return "OFFSET " + offset + " ROWS FETCH NEXT " + limit + " ROWS ONLY"
+	default:
+		if offset == "" {
+			return "LIMIT " + limit
+		}
+		return "LIMIT " + limit + " OFFSET " + offset
+	}
+}
+
+// CapabilitiesProvider is implemented by the drivers describing their Capabilities.
+type CapabilitiesProvider interface {
+	Capabilities() Capabilities
+}
+
+// defaultCapabilities are the capabilities assumed for the drivers which do not describe theirs.
+var defaultCapabilities = Capabilities{
+	LimitStyle:     LimitOffset,
+	LastInsertID:   true,
+	MultiRowValues: true,
+	Explain:        "EXPLAIN ",
+}
+
+// CapabilitiesOf returns the Capabilities of driver.
+// Drivers which do not implement CapabilitiesProvider are assumed to support
+// LIMIT n OFFSET m, LastInsertId, multiple rows of VALUES and EXPLAIN, but neither RETURNING,
+// upserts nor row value comparisons.
+func CapabilitiesOf(driver Driver) Capabilities {
+	if provider, ok := driver.(CapabilitiesProvider); ok {
+		return provider.Capabilities()
+	}
+	return defaultCapabilities
+}
+
+// Capabilities implements CapabilitiesProvider.
+func (d MySQLDriver) Capabilities() Capabilities {
+	return Capabilities{
+		LimitStyle:      LimitOffset,
+		LastInsertID:    true,
+		MultiRowValues:  true,
+		Explain:         "EXPLAIN ",
+		Upsert:          OnDuplicateKey,
+		MaxPlaceholders: 65535,
+		RowValues:       true,
+	}
+}
+
+// Capabilities implements CapabilitiesProvider.
+func (d PostgresDriver) Capabilities() Capabilities {
+	return Capabilities{
+		LimitStyle:      LimitOffset,
+		Returning:       true,
+		MultiRowValues:  true,
+		Explain:         "EXPLAIN ",
+		Upsert:          OnConflict,
+		MaxPlaceholders: 65535,
+		RowValues:       true,
+	}
+}
+
+// Capabilities implements CapabilitiesProvider.
+// RETURNING requires SQLite 3.35 or later, and ON CONFLICT DO UPDATE SQLite 3.24 or later.
+// MaxPlaceholders is the default SQLITE_MAX_VARIABLE_NUMBER since SQLite 3.32,
+// and row values require SQLite 3.15 or later.
+func (d SQLiteDriver) Capabilities() Capabilities {
+	return Capabilities{
+		LimitStyle:      LimitOffset,
+		Returning:       true,
+		LastInsertID:    true,
+		MultiRowValues:  true,
+		Explain:         "EXPLAIN QUERY PLAN ",
+		Upsert:          OnConflict,
+		MaxPlaceholders: 32766,
+		RowValues:       true,
+	}
+}
+
+// Capabilities implements CapabilitiesProvider.
+// OFFSET FETCH requires Oracle 12c or later.
+// EXPLAIN PLAN FOR stores the plan in PLAN_TABLE instead of returning it.
+func (o OracleDriver) Capabilities() Capabilities {
+	return Capabilities{LimitStyle: OffsetFetch, Savepoint: SavepointWithoutRelease}
+}
+
+// Capabilities implements CapabilitiesProvider.
+// Generated keys are returned with an OUTPUT clause, which is not supported as RETURNING.
+// Plans are returned by SET SHOWPLAN_ALL ON, which cannot prefix a query.
+func (d SQLServerDriver) Capabilities() Capabilities {
+	return Capabilities{LimitStyle: OffsetFetch, MultiRowValues: true, MaxPlaceholders: 2100, Savepoint: SaveTransaction}
+}
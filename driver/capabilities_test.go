@@ -0,0 +1,99 @@
+package driver
+
+import "testing"
+
+func TestCapabilitiesOf_capabilities_test(t *testing.T) {
+	if CapabilitiesOf(PostgresDriver{}).LastInsertID || !CapabilitiesOf(PostgresDriver{}).Returning {
+		t.Fatal("postgres returns generated keys with RETURNING only")
+	}
+	if !CapabilitiesOf(MySQLDriver{}).LastInsertID || CapabilitiesOf(MySQLDriver{}).Returning {
+		t.Fatal("mysql returns generated keys with LastInsertId only")
+	}
+	if CapabilitiesOf(OracleDriver{}).MultiRowValues {
+		t.Fatal("oracle does not support multiple rows of VALUES")
+	}
+	if !CapabilitiesOf(SQLiteDriver{}).RowValues || CapabilitiesOf(SQLServerDriver{}).RowValues {
+		t.Fatal("unexpected row value support")
+	}
+	if CapabilitiesOf(SQLiteDriver{}).Explain != "EXPLAIN QUERY PLAN " || CapabilitiesOf(SQLServerDriver{}).Explain != "" {
+		t.Fatal("unexpected explain prefixes")
+	}
+	if got := CapabilitiesOf(noDSNDriver{}); got != defaultCapabilities {
+		t.Fatalf("unexpected default capabilities %+v", got)
+	}
+}
+
+func TestCapabilitiesLimitClause_capabilities_test(t *testing.T) {
+	tests := []struct {
+		driver        Driver
+		limit, offset string
+		want          string
+	}{
+		{MySQLDriver{}, "?", "?", "LIMIT ? OFFSET ?"},
+		{SQLiteDriver{}, "10", "", "LIMIT 10"},
+		{OracleDriver{}, ":1", ":2", "OFFSET :2 ROWS FETCH NEXT :1 ROWS ONLY"},
+		{SQLServerDriver{}, "@p1", "", "OFFSET 0 ROWS FETCH NEXT @p1 ROWS ONLY"},
+	}
+	for _, tt := range tests {
+		if got := CapabilitiesOf(tt.driver).LimitClause(tt.limit, tt.offset); got != tt.want {
+			t.Errorf("%s: LimitClause(%q, %q) = %q, want %q", tt.driver.Name(), tt.limit, tt.offset, got, tt.want)
+		}
+	}
+}
+
+func TestMultiRowValuesOf_capabilities_test(t *testing.T) {
+	for _, driver := range []Driver{MySQLDriver{}, PostgresDriver{}, SQLiteDriver{}, OracleDriver{}, SQLServerDriver{}} {
+		if got, want := MultiRowValuesOf(driver.Translator()), CapabilitiesOf(driver).MultiRowValues; got != want {
+			t.Errorf("%s: MultiRowValuesOf() = %v, want %v", driver.Name(), got, want)
+		}
+	}
+	if !MultiRowValuesOf(TranslateFunc(func(string) string { return "?" })) {
+		t.Error("expected multiple rows of VALUES by default")
+	}
+}
+
+func TestUpsertStyleOf_capabilities_test(t *testing.T) {
+	tests := []struct {
+		driver Driver
+		want   UpsertStyle
+	}{
+		{MySQLDriver{}, OnDuplicateKey},
+		{PostgresDriver{}, OnConflict},
+		{SQLiteDriver{}, OnConflict},
+		{OracleDriver{}, UpsertUnsupported},
+		{SQLServerDriver{}, UpsertUnsupported},
+	}
+	for _, tt := range tests {
+		if got := UpsertStyleOf(tt.driver.Translator()); got != tt.want {
+			t.Errorf("%s: UpsertStyleOf() = %v, want %v", tt.driver.Name(), got, tt.want)
+		}
+	}
+	if got := UpsertStyleOf(TranslateFunc(func(string) string { return "?" })); got != UpsertUnsupported {
+		t.Errorf("UpsertStyleOf() of a plain translator = %v, want %v", got, UpsertUnsupported)
+	}
+}
+
+func TestSavepointClauses_capabilities_test(t *testing.T) {
+	tests := []struct {
+		driver                  Driver
+		save, rollback, release string
+	}{
+		{MySQLDriver{}, "SAVEPOINT sp", "ROLLBACK TO SAVEPOINT sp", "RELEASE SAVEPOINT sp"},
+		{PostgresDriver{}, "SAVEPOINT sp", "ROLLBACK TO SAVEPOINT sp", "RELEASE SAVEPOINT sp"},
+		{SQLiteDriver{}, "SAVEPOINT sp", "ROLLBACK TO SAVEPOINT sp", "RELEASE SAVEPOINT sp"},
+		{OracleDriver{}, "SAVEPOINT sp", "ROLLBACK TO SAVEPOINT sp", ""},
+		{SQLServerDriver{}, "SAVE TRANSACTION sp", "ROLLBACK TRANSACTION sp", ""},
+	}
+	for _, tt := range tests {
+		capabilities := CapabilitiesOf(tt.driver)
+		if got := capabilities.SavepointClause("sp"); got != tt.save {
+			t.Errorf("%s: SavepointClause() = %q, want %q", tt.driver.Name(), got, tt.save)
+		}
+		if got := capabilities.RollbackToSavepointClause("sp"); got != tt.rollback {
+			t.Errorf("%s: RollbackToSavepointClause() = %q, want %q", tt.driver.Name(), got, tt.rollback)
+		}
+		if got := capabilities.ReleaseSavepointClause("sp"); got != tt.release {
+			t.Errorf("%s: ReleaseSavepointClause() = %q, want %q", tt.driver.Name(), got, tt.release)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrDuplicateKey is wrapped by TranslateError into the errors of a unique or primary key violation.
+	ErrDuplicateKey = errors.New("driver: duplicate key")
+
+	// ErrForeignKeyViolation is wrapped by TranslateError into the errors of a foreign key violation.
+	ErrForeignKeyViolation = errors.New("driver: foreign key violation")
+
+	// ErrSerialization is wrapped by TranslateError into the errors of a serialization failure or a deadlock,
+	// which abort a transaction that may succeed when retried.
+	ErrSerialization = errors.New("driver: serialization failure")
+)
+
+// ErrorTranslator is implemented by the drivers recognizing the errors of their database,
+// usually by their vendor code. TranslateError returns the portable error the error is,
+// ErrDuplicateKey, ErrForeignKeyViolation or ErrSerialization, or nil.
+type ErrorTranslator interface {
+	TranslateError(err error) error
+}
+
+// TranslateError wraps err of driver into the portable error it is, so that it can be recognized
+// with errors.Is regardless of the database, and returns it unchanged otherwise:
+//
+//	if errors.Is(err, driver.ErrDuplicateKey) { ... }
+//
+// Drivers which do not implement ErrorTranslator recognize the SQLSTATE of the errors
+// reporting it with a SQLState method.
+func TranslateError(driver Driver, err error) error {
+	if err == nil || isTranslatedError(err) {
+		return err
+	}
+	var portable error
+	if translator, ok := driver.(ErrorTranslator); ok {
+		portable = translator.TranslateError(err)
+	} else {
+		portable = translateSQLState(err)
+	}
+	if portable == nil {
+		return err
+	}
+	return fmt.Errorf("%w: %w", portable, err)
+}
+
+// isTranslatedError reports whether err already wraps a portable error.
+func isTranslatedError(err error) bool {
+	return errors.Is(err, ErrDuplicateKey) || errors.Is(err, ErrForeignKeyViolation) || errors.Is(err, ErrSerialization)
+}
+
+// translateSQLState returns the portable error of the SQLSTATE of err, or an error it wraps,
+// like the errors of pgx and lib/pq.
+func translateSQLState(err error) error {
+	var stateErr interface{ SQLState() string }
+	if !errors.As(err, &stateErr) {
+		return nil
+	}
+	switch stateErr.SQLState() {
+	case "23505":
+		return ErrDuplicateKey
+	case "23503":
+		return ErrForeignKeyViolation
+	case "40001", "40P01":
+		return ErrSerialization
+	default:
+		return nil
+	}
+}
+
+// containsAny reports whether message contains one of the codes.
+func containsAny(message string, codes ...string) bool {
+	for _, code := range codes {
+		if strings.Contains(message, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// TranslateError implements ErrorTranslator.
+// The errors of go-sql-driver/mysql are recognized by their message, as they have no method to classify them:
+// 1062 is a duplicate entry, 1451 and 1452 foreign key violations, 1213 a deadlock and 1205 a lock wait timeout.
+func (d MySQLDriver) TranslateError(err error) error {
+	if portable := translateSQLState(err); portable != nil {
+		return portable
+	}
+	switch message := err.Error(); {
+	case containsAny(message, "Error 1062"):
+		return ErrDuplicateKey
+	case containsAny(message, "Error 1451", "Error 1452"):
+		return ErrForeignKeyViolation
+	case containsAny(message, "Error 1213", "Error 1205"):
+		return ErrSerialization
+	default:
+		return nil
+	}
+}
+
+// TranslateError implements ErrorTranslator.
+func (d PostgresDriver) TranslateError(err error) error {
+	return translateSQLState(err)
+}
+
+// TranslateError implements ErrorTranslator.
+// The constraint violations of SQLite are recognized by their message, shared by the drivers,
+// and a database locked by another connection is a serialization failure.
+func (d SQLiteDriver) TranslateError(err error) error {
+	switch message := err.Error(); {
+	case containsAny(message, "UNIQUE constraint failed", "PRIMARY KEY constraint failed"):
+		return ErrDuplicateKey
+	case containsAny(message, "FOREIGN KEY constraint failed"):
+		return ErrForeignKeyViolation
+	case containsAny(message, "database is locked"):
+		return ErrSerialization
+	default:
+		return nil
+	}
+}
+
+// TranslateError implements ErrorTranslator.
+// ORA-00001 is a unique constraint violation, ORA-02291 and ORA-02292 foreign key violations,
+// ORA-08177 a serialization failure and ORA-00060 a deadlock.
+func (o OracleDriver) TranslateError(err error) error {
+	switch message := err.Error(); {
+	case containsAny(message, "ORA-00001"):
+		return ErrDuplicateKey
+	case containsAny(message, "ORA-02291", "ORA-02292"):
+		return ErrForeignKeyViolation
+	case containsAny(message, "ORA-08177", "ORA-00060"):
+		return ErrSerialization
+	default:
+		return nil
+	}
+}
+
+// TranslateError implements ErrorTranslator.
+// Errors 2627 and 2601 are unique key violations, 547 a constraint conflict, which is a foreign key violation
+// when its message names the FOREIGN KEY constraint, 1205 a deadlock and 3960 a snapshot isolation update conflict, like with the SQLErrorNumber method of go-mssqldb.
+func (d SQLServerDriver) TranslateError(err error) error {
+	var numberErr interface{ SQLErrorNumber() int32 }
+	if !errors.As(err, &numberErr) {
+		return translateSQLState(err)
+	}
+	switch numberErr.SQLErrorNumber() {
+	case 2627, 2601:
+		return ErrDuplicateKey
+	case 547:
+		if strings.Contains(err.Error(), "FOREIGN KEY") {
+			return ErrForeignKeyViolation
+		}
+		return nil
+	case 1205, 3960:
+		return ErrSerialization
+	default:
+		return nil
+	}
+}
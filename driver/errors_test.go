@@ -0,0 +1,52 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestTranslateError_errors_test(t *testing.T) {
+	tests := []struct {
+		driver Driver
+		err    error
+		want   error
+	}{
+		{PostgresDriver{}, fmt.Errorf("insert: %w", sqlStateError("23505")), ErrDuplicateKey},
+		{PostgresDriver{}, sqlStateError("23503"), ErrForeignKeyViolation},
+		{PostgresDriver{}, sqlStateError("40001"), ErrSerialization},
+		{PostgresDriver{}, sqlStateError("42601"), nil},
+		{MySQLDriver{}, errors.New("Error 1062 (23000): Duplicate entry '1' for key 'PRIMARY'"), ErrDuplicateKey},
+		{MySQLDriver{}, errors.New("Error 1452 (23000): Cannot add or update a child row"), ErrForeignKeyViolation},
+		{MySQLDriver{}, errors.New("Error 1213 (40001): Deadlock found when trying to get lock"), ErrSerialization},
+		{SQLiteDriver{}, errors.New("UNIQUE constraint failed: user.email"), ErrDuplicateKey},
+		{SQLiteDriver{}, errors.New("FOREIGN KEY constraint failed"), ErrForeignKeyViolation},
+		{SQLiteDriver{}, errors.New("NOT NULL constraint failed: user.name"), nil},
+		{OracleDriver{}, errors.New("ORA-00001: unique constraint (APP.PK_USER) violated"), ErrDuplicateKey},
+		{OracleDriver{}, errors.New("ORA-02291: integrity constraint violated - parent key not found"), ErrForeignKeyViolation},
+		{SQLServerDriver{}, sqlServerError(2627), ErrDuplicateKey},
+		{SQLServerDriver{}, sqlServerError(1205), ErrSerialization},
+		{SQLServerDriver{}, sqlServerError(547), nil},
+		{noDSNDriver{}, sqlStateError("23505"), ErrDuplicateKey},
+		{noDSNDriver{}, errors.New("UNIQUE constraint failed: user.email"), nil},
+	}
+	for _, test := range tests {
+		got := TranslateError(test.driver, test.err)
+		if !errors.Is(got, test.err) {
+			t.Errorf("TranslateError(%s, %v) = %v, which does not wrap the error", test.driver.Name(), test.err, got)
+		}
+		for _, portable := range []error{ErrDuplicateKey, ErrForeignKeyViolation, ErrSerialization} {
+			if errors.Is(got, portable) != (portable == test.want) {
+				t.Errorf("TranslateError(%s, %v) = %v, want %v", test.driver.Name(), test.err, got, test.want)
+			}
+		}
+	}
+
+	translated := TranslateError(PostgresDriver{}, sqlStateError("23505"))
+	if again := TranslateError(PostgresDriver{}, translated); again != translated {
+		t.Errorf("expected a translated error to be returned as it is, got %v", again)
+	}
+	if TranslateError(PostgresDriver{}, nil) != nil {
+		t.Error("expected no error")
+	}
+}
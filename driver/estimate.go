@@ -0,0 +1,112 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RowCountEstimator is implemented by the drivers estimating the number of rows of a query
+// from its plan, which is much cheaper than counting them.
+type RowCountEstimator interface {
+	// EstimateQuery returns the query returning the plan of query.
+	EstimateQuery(query string) string
+
+	// ParseEstimate returns the estimated number of rows from the plan returned by the estimate query.
+	ParseEstimate(columns []string, rows [][]any) (int64, error)
+}
+
+// errNoEstimate is returned when a plan has no row estimate.
+var errNoEstimate = errors.New("driver: the plan has no row estimate")
+
+// EstimateQuery implements RowCountEstimator with EXPLAIN (FORMAT JSON).
+func (d PostgresDriver) EstimateQuery(query string) string {
+	return "EXPLAIN (FORMAT JSON) " + query
+}
+
+// ParseEstimate implements RowCountEstimator. The estimate is the Plan Rows of the root node of the plan.
+func (d PostgresDriver) ParseEstimate(_ []string, rows [][]any) (int64, error) {
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return 0, errNoEstimate
+	}
+	var plan []struct {
+		Plan struct {
+			Rows *float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(planText(rows[0][0])), &plan); err != nil {
+		return 0, fmt.Errorf("driver: invalid plan: %w", err)
+	}
+	if len(plan) == 0 || plan[0].Plan.Rows == nil {
+		return 0, errNoEstimate
+	}
+	return int64(math.Round(*plan[0].Plan.Rows)), nil
+}
+
+// EstimateQuery implements RowCountEstimator with EXPLAIN.
+func (d MySQLDriver) EstimateQuery(query string) string {
+	return "EXPLAIN " + query
+}
+
+// ParseEstimate implements RowCountEstimator. The tables of the plan are joined in nested loops,
+// so the estimate is the product of their rows filtered by the percentage of their filtered column.
+func (d MySQLDriver) ParseEstimate(columns []string, rows [][]any) (int64, error) {
+	rowsIndex := slices.Index(columns, "rows")
+	filteredIndex := slices.Index(columns, "filtered")
+	if rowsIndex < 0 || len(rows) == 0 {
+		return 0, errNoEstimate
+	}
+	estimate, found := 1.0, false
+	for _, row := range rows {
+		examined, err := strconv.ParseFloat(planText(row[rowsIndex]), 64)
+		if err != nil {
+			// the rows of the derived and the impossible tables are NULL.
+			continue
+		}
+		if filteredIndex >= 0 {
+			if filtered, err := strconv.ParseFloat(planText(row[filteredIndex]), 64); err == nil {
+				examined *= filtered / 100
+			}
+		}
+		estimate *= examined
+		found = true
+	}
+	if !found {
+		return 0, errNoEstimate
+	}
+	return int64(math.Round(estimate)), nil
+}
+
+// planText returns the text of a value of a plan.
+func planText(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return strings.TrimSpace(string(value))
+	case string:
+		return strings.TrimSpace(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,47 @@
+package driver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPostgresParseEstimate_estimate_test(t *testing.T) {
+	drv := PostgresDriver{}
+	if got := drv.EstimateQuery("SELECT * FROM users"); got != "EXPLAIN (FORMAT JSON) SELECT * FROM users" {
+		t.Fatalf("unexpected estimate query: %s", got)
+	}
+	plan := []byte(`[{"Plan": {"Node Type": "Seq Scan", "Plan Rows": 1234.6, "Plans": [{"Plan Rows": 10}]}}]`)
+	got, err := drv.ParseEstimate([]string{"QUERY PLAN"}, [][]any{{plan}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 1235 {
+		t.Fatalf("expected 1235, got %d", got)
+	}
+	if _, err = drv.ParseEstimate([]string{"QUERY PLAN"}, [][]any{{`[{"Plan": {}}]`}}); !errors.Is(err, errNoEstimate) {
+		t.Fatalf("expected errNoEstimate, got %v", err)
+	}
+	if _, err = drv.ParseEstimate([]string{"QUERY PLAN"}, [][]any{{"Seq Scan"}}); err == nil {
+		t.Fatal("expected error for a text plan")
+	}
+}
+
+func TestMySQLParseEstimate_estimate_test(t *testing.T) {
+	drv := MySQLDriver{}
+	columns := []string{"id", "select_type", "table", "rows", "filtered"}
+	rows := [][]any{
+		{int64(1), "SIMPLE", "users", []byte("1000"), []byte("10.00")},
+		{int64(1), "SIMPLE", "orders", int64(3), float64(100)},
+		{int64(2), "DERIVED", nil, nil, nil},
+	}
+	got, err := drv.ParseEstimate(columns, rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 300 {
+		t.Fatalf("expected 300, got %d", got)
+	}
+	if _, err = drv.ParseEstimate([]string{"id"}, [][]any{{int64(1)}}); !errors.Is(err, errNoEstimate) {
+		t.Fatalf("expected errNoEstimate, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import "context"
+
+// FetchSizer is implemented by the drivers which can hint how many rows the
+// database should send per round trip, like pgx through its query options.
+// database/sql has no portable way to do so, so the hint travels in the context
+// of the query.
+type FetchSizer interface {
+	// WithFetchSize returns a copy of ctx carrying the fetch size understood by
+	// the underlying database driver.
+	WithFetchSize(ctx context.Context, size int) context.Context
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// ScriptSplitter is implemented by the drivers splitting the SQL scripts of their dialect into statements,
+// which database/sql executes one at a time.
+type ScriptSplitter interface {
+	SplitScript(script string) []string
+}
+
+// SplitScript splits script into the statements of driver.
+// Drivers which do not implement ScriptSplitter split the statements on semicolons,
+// outside of quotes and comments.
+func SplitScript(driver Driver, script string) []string {
+	if splitter, ok := driver.(ScriptSplitter); ok {
+		return splitter.SplitScript(script)
+	}
+	return (&scriptScanner{delimiter: ";"}).split(script)
+}
+
+// AdvisoryLocker is implemented by the drivers of the databases with session level advisory locks.
+// It returns the statements acquiring and releasing the lock named name, which must be executed
+// on the same connection. The lock statement waits until the lock is acquired.
+type AdvisoryLocker interface {
+	AdvisoryLock(name string) (lock, unlock string)
+}
+
+// AdvisoryLockStatements returns the statements acquiring and releasing the advisory lock named name,
+// or empty statements if driver does not implement AdvisoryLocker.
+func AdvisoryLockStatements(driver Driver, name string) (lock, unlock string) {
+	if locker, ok := driver.(AdvisoryLocker); ok {
+		return locker.AdvisoryLock(name)
+	}
+	return "", ""
+}
+
+// SplitScript implements ScriptSplitter. It honours the DELIMITER directive of the mysql client,
+// used to define the stored programs whose bodies contain semicolons.
+func (d MySQLDriver) SplitScript(script string) []string {
+	return (&scriptScanner{delimiter: ";", backticks: true, delimiterDirective: true}).split(script)
+}
+
+// AdvisoryLock implements AdvisoryLocker with GET_LOCK, waiting without timeout.
+func (d MySQLDriver) AdvisoryLock(name string) (lock, unlock string) {
+	quoted := quoteString(name)
+	return "SELECT GET_LOCK(" + quoted + ", -1)", "SELECT RELEASE_LOCK(" + quoted + ")"
+}
+
+// SplitScript implements ScriptSplitter. The semicolons of dollar-quoted strings, like the bodies
+// of the functions, do not end the statements.
+func (d PostgresDriver) SplitScript(script string) []string {
+	return (&scriptScanner{delimiter: ";", dollarQuotes: true}).split(script)
+}
+
+// AdvisoryLock implements AdvisoryLocker with pg_advisory_lock, whose key is the hash of name.
+func (d PostgresDriver) AdvisoryLock(name string) (lock, unlock string) {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(name))
+	key := strconv.FormatInt(int64(hash.Sum64()), 10)
+	return "SELECT pg_advisory_lock(" + key + ")", "SELECT pg_advisory_unlock(" + key + ")"
+}
+
+// SplitScript implements ScriptSplitter. The batches are separated by the GO lines of sqlcmd,
+// each batch being executed as a single statement.
+func (d SQLServerDriver) SplitScript(script string) []string {
+	return splitOnLines(script, func(line string) bool { return strings.EqualFold(line, "GO") })
+}
+
+// AdvisoryLock implements AdvisoryLocker with sp_getapplock, owned by the session.
+func (d SQLServerDriver) AdvisoryLock(name string) (lock, unlock string) {
+	quoted := quoteString(name)
+	return "EXEC sp_getapplock @Resource = " + quoted + ", @LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = -1",
+		"EXEC sp_releaseapplock @Resource = " + quoted + ", @LockOwner = 'Session'"
+}
+
+// SplitScript implements ScriptSplitter. A script with / lines is split on them like in SQL*Plus,
+// so that the PL/SQL blocks keep their semicolons. Other scripts are split on semicolons.
+func (o OracleDriver) SplitScript(script string) []string {
+	isSlash := func(line string) bool { return line == "/" }
+	for line := range strings.Lines(script) {
+		if isSlash(strings.TrimSpace(line)) {
+			return splitOnLines(script, isSlash)
+		}
+	}
+	return (&scriptScanner{delimiter: ";"}).split(script)
+}
+
+// quoteString returns s as a SQL string literal.
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// splitOnLines splits script on the lines for which separator returns true, given their trimmed content.
+func splitOnLines(script string, separator func(line string) bool) []string {
+	var (
+		statements []string
+		current    strings.Builder
+	)
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+	for line := range strings.Lines(script) {
+		if separator(strings.TrimSpace(line)) {
+			flush()
+			continue
+		}
+		current.WriteString(line)
+	}
+	flush()
+	return statements
+}
+
+// scriptScanner splits a script on a delimiter outside of the quotes and comments.
+type scriptScanner struct {
+	delimiter string
+
+	// backticks enables the identifiers quoted with backticks and the backslash escapes of MySQL.
+	backticks bool
+
+	// dollarQuotes enables the $tag$ strings of PostgreSQL.
+	dollarQuotes bool
+
+	// delimiterDirective enables the DELIMITER lines of the mysql client.
+	delimiterDirective bool
+}
+
+// split returns the statements of script, without their delimiter.
+// The chunks holding only comments and spaces are dropped.
+func (s *scriptScanner) split(script string) []string {
+	var (
+		statements []string
+		start      int
+		content    bool
+	)
+	flush := func(end int) {
+		if content {
+			statements = append(statements, strings.TrimSpace(script[start:end]))
+		}
+		content = false
+	}
+	lineStart := true
+	for i := 0; i < len(script); {
+		if s.delimiterDirective && lineStart {
+			line, _, _ := strings.Cut(script[i:], "\n")
+			if fields := strings.Fields(line); len(fields) == 2 && strings.EqualFold(fields[0], "DELIMITER") {
+				flush(i)
+				s.delimiter = fields[1]
+				i += len(line)
+				start = i
+				continue
+			}
+		}
+		c := script[i]
+		lineStart = c == '\n'
+		switch {
+		case strings.HasPrefix(script[i:], s.delimiter):
+			flush(i)
+			i += len(s.delimiter)
+			start = i
+			continue
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			i = skipUntil(script, i+2, "\n")
+			lineStart = true
+			continue
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			i = skipUntil(script, i+2, "*/")
+			continue
+		case c == '\'' || c == '"' || (c == '`' && s.backticks):
+			i = skipQuoted(script, i+1, c, s.backticks)
+			content = true
+			continue
+		case c == '$' && s.dollarQuotes:
+			if tag, ok := dollarTag(script[i:]); ok {
+				i = skipUntil(script, i+len(tag), tag)
+				content = true
+				continue
+			}
+		}
+		if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			content = true
+		}
+		i++
+	}
+	flush(len(script))
+	return statements
+}
+
+// skipUntil returns the index after the first terminator of s from start, or len(s).
+func skipUntil(s string, start int, terminator string) int {
+	if index := strings.Index(s[start:], terminator); index >= 0 {
+		return start + index + len(terminator)
+	}
+	return len(s)
+}
+
+// skipQuoted returns the index after the quote closing the quoted text starting at start.
+// A doubled quote is an escaped quote, and so is a quote following a backslash when backslash is true.
+func skipQuoted(s string, start int, quote byte, backslash bool) int {
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if backslash {
+				i++
+			}
+		case quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// dollarTag returns the $tag$ opening a dollar-quoted string at the start of s.
+func dollarTag(s string) (string, bool) {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '$':
+			return s[:i+1], true
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 1 && c >= '0' && c <= '9':
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
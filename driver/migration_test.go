@@ -0,0 +1,78 @@
+package driver
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSplitScript_migration_test(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver Driver
+		script string
+		want   []string
+	}{
+		{
+			name:   "semicolons",
+			driver: SQLiteDriver{},
+			script: "CREATE TABLE t (v TEXT DEFAULT 'a;b'); -- done;\n/* x; */ INSERT INTO t VALUES ('it''s;');\n-- trailing comment\n",
+			want:   []string{"CREATE TABLE t (v TEXT DEFAULT 'a;b')", "-- done;\n/* x; */ INSERT INTO t VALUES ('it''s;')"},
+		},
+		{
+			name:   "postgres dollar quotes",
+			driver: PostgresDriver{},
+			script: "CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END; $body$ LANGUAGE plpgsql;\nSELECT 1;",
+			want:   []string{"CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END; $body$ LANGUAGE plpgsql", "SELECT 1"},
+		},
+		{
+			name:   "mysql delimiter",
+			driver: MySQLDriver{},
+			script: "DELIMITER //\nCREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END//\nDELIMITER ;\nSELECT 'a\\';b';",
+			want:   []string{"CREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END", "SELECT 'a\\';b'"},
+		},
+		{
+			name:   "sqlserver batches",
+			driver: SQLServerDriver{},
+			script: "CREATE TABLE t (id int);\nINSERT INTO t VALUES (1);\ngo\nCREATE VIEW v AS SELECT id FROM t\nGO\n",
+			want:   []string{"CREATE TABLE t (id int);\nINSERT INTO t VALUES (1);", "CREATE VIEW v AS SELECT id FROM t"},
+		},
+		{
+			name:   "oracle blocks",
+			driver: OracleDriver{},
+			script: "BEGIN\n  NULL;\nEND;\n/\nCREATE TABLE t (id NUMBER)\n/\n",
+			want:   []string{"BEGIN\n  NULL;\nEND;", "CREATE TABLE t (id NUMBER)"},
+		},
+		{
+			name:   "oracle statements",
+			driver: OracleDriver{},
+			script: "CREATE TABLE t (id NUMBER);\nCREATE INDEX i ON t (id);",
+			want:   []string{"CREATE TABLE t (id NUMBER)", "CREATE INDEX i ON t (id)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitScript(tt.driver, tt.script); !slices.Equal(got, tt.want) {
+				t.Fatalf("SplitScript() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdvisoryLockStatements_migration_test(t *testing.T) {
+	if lock, unlock := AdvisoryLockStatements(SQLiteDriver{}, "juice"); lock != "" || unlock != "" {
+		t.Fatalf("expected no lock for sqlite, got %q %q", lock, unlock)
+	}
+	lock, unlock := AdvisoryLockStatements(MySQLDriver{}, "it's")
+	if lock != "SELECT GET_LOCK('it''s', -1)" || unlock != "SELECT RELEASE_LOCK('it''s')" {
+		t.Fatalf("unexpected mysql lock: %q %q", lock, unlock)
+	}
+	lock, unlock = AdvisoryLockStatements(PostgresDriver{}, "juice")
+	key := strings.TrimSuffix(strings.TrimPrefix(lock, "SELECT pg_advisory_lock("), ")")
+	if key == lock || unlock != "SELECT pg_advisory_unlock("+key+")" {
+		t.Fatalf("unexpected postgres lock: %q %q", lock, unlock)
+	}
+	if lock, _ = AdvisoryLockStatements(SQLServerDriver{}, "juice"); !strings.Contains(lock, "sp_getapplock @Resource = 'juice'") {
+		t.Fatalf("unexpected sqlserver lock: %q", lock)
+	}
+}
@@ -19,9 +19,12 @@ package driver
 // MySQLDriver is a driver of MySQL.
 type MySQLDriver struct{}
 
+// mysqlLikeEscapeClause doubles the backslash, which is an escape character in MySQL string literals.
+const mysqlLikeEscapeClause = ` ESCAPE '\\'`
+
 // Translator returns a translator of SQL.
 func (d MySQLDriver) Translator() Translator {
-	return TranslateFunc(func(matched string) string { return "?" })
+	return newDialectTranslator(TranslateFunc(func(matched string) string { return "?" }), '`', mysqlLikeEscapeClause, d.Capabilities())
 }
 
 func (d MySQLDriver) Name() string {
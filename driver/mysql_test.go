@@ -9,3 +9,10 @@ func TestMySQLDriver_mysql_test(t *testing.T) {
 		t.Fatal("failed to translate")
 	}
 }
+
+func TestMySQLDriverQuoteIdentifier_mysql_test(t *testing.T) {
+	translator := MySQLDriver{}.Translator()
+	if got := QuoteIdentifier(translator, "db.user`s"); got != "`db`.`user``s`" {
+		t.Fatalf("unexpected quoted identifier: %s", got)
+	}
+}
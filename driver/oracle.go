@@ -24,10 +24,10 @@ type OracleDriver struct{}
 // Translator is a function to translate a matched string.
 func (o OracleDriver) Translator() Translator {
 	var i int
-	return TranslateFunc(func(matched string) string {
+	return newDialectTranslator(TranslateFunc(func(matched string) string {
 		i++
 		return ":" + strconv.Itoa(i)
-	})
+	}), '"', ansiLikeEscapeClause, o.Capabilities())
 }
 
 func (o OracleDriver) Name() string {
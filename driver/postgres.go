@@ -24,10 +24,10 @@ type PostgresDriver struct{}
 // Translator is a function to translate a matched string.
 func (d PostgresDriver) Translator() Translator {
 	var i int
-	return TranslateFunc(func(matched string) string {
+	return newDialectTranslator(TranslateFunc(func(matched string) string {
 		i++
 		return "$" + strconv.Itoa(i)
-	})
+	}), '"', ansiLikeEscapeClause, d.Capabilities())
 }
 
 func (d PostgresDriver) Name() string {
@@ -20,3 +20,13 @@ func TestPostgresDriver_postgres_test(t *testing.T) {
 		}
 	}
 }
+
+func TestPostgresDriverQuoteIdentifier_postgres_test(t *testing.T) {
+	translator := PostgresDriver{}.Translator()
+	if got := QuoteIdentifier(translator, "public.user"); got != `"public"."user"` {
+		t.Fatalf("unexpected quoted identifier: %s", got)
+	}
+	if got := QuoteIdentifier(TranslateFunc(func(string) string { return "?" }), "name"); got != `"name"` {
+		t.Fatalf("unexpected fallback quoted identifier: %s", got)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"errors"
+	"strings"
+)
+
+// RetryableErrorClassifier is implemented by the drivers recognizing the errors of their
+// database which abort a transaction that may succeed when retried as a whole,
+// like serialization failures and deadlocks.
+type RetryableErrorClassifier interface {
+	IsRetryableError(err error) bool
+}
+
+// IsRetryableError reports whether err aborted a transaction of driver that may succeed when retried.
+// Drivers which do not implement RetryableErrorClassifier recognize the SQLSTATE 40001
+// serialization failure and 40P01 deadlock, when the error reports them with a SQLState method.
+func IsRetryableError(driver Driver, err error) bool {
+	if err == nil {
+		return false
+	}
+	if classifier, ok := driver.(RetryableErrorClassifier); ok {
+		return classifier.IsRetryableError(err)
+	}
+	return hasRetryableSQLState(err)
+}
+
+// hasRetryableSQLState reports whether err, or an error it wraps, has the SQLSTATE
+// of a serialization failure or a deadlock, like the errors of pgx and lib/pq.
+func hasRetryableSQLState(err error) bool {
+	var stateErr interface{ SQLState() string }
+	if !errors.As(err, &stateErr) {
+		return false
+	}
+	switch stateErr.SQLState() {
+	case "40001", "40P01":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRetryableError implements RetryableErrorClassifier.
+// The errors of go-sql-driver/mysql are recognized by their message, as they have no method to classify them:
+// 1213 is a deadlock and 1205 a lock wait timeout.
+func (d MySQLDriver) IsRetryableError(err error) bool {
+	if hasRetryableSQLState(err) {
+		return true
+	}
+	message := err.Error()
+	return strings.Contains(message, "Error 1213") || strings.Contains(message, "Error 1205")
+}
+
+// IsRetryableError implements RetryableErrorClassifier.
+func (d PostgresDriver) IsRetryableError(err error) bool {
+	return hasRetryableSQLState(err)
+}
+
+// IsRetryableError implements RetryableErrorClassifier.
+// A database locked by another connection is reported as SQLITE_BUSY, whose message is "database is locked".
+func (d SQLiteDriver) IsRetryableError(err error) bool {
+	return strings.Contains(err.Error(), "database is locked")
+}
+
+// IsRetryableError implements RetryableErrorClassifier.
+// ORA-08177 is a serialization failure and ORA-00060 a deadlock.
+func (o OracleDriver) IsRetryableError(err error) bool {
+	message := err.Error()
+	return strings.Contains(message, "ORA-08177") || strings.Contains(message, "ORA-00060")
+}
+
+// IsRetryableError implements RetryableErrorClassifier.
+// Error 1205 chooses the transaction as a deadlock victim, like with the SQLErrorNumber method of go-mssqldb.
+func (d SQLServerDriver) IsRetryableError(err error) bool {
+	var numberErr interface{ SQLErrorNumber() int32 }
+	if errors.As(err, &numberErr) {
+		return numberErr.SQLErrorNumber() == 1205
+	}
+	return hasRetryableSQLState(err)
+}
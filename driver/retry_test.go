@@ -0,0 +1,43 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sql state " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+type sqlServerError int32
+
+func (e sqlServerError) Error() string         { return fmt.Sprintf("mssql: error %d", int32(e)) }
+func (e sqlServerError) SQLErrorNumber() int32 { return int32(e) }
+
+func TestIsRetryableError_retry_test(t *testing.T) {
+	tests := []struct {
+		driver Driver
+		err    error
+		want   bool
+	}{
+		{PostgresDriver{}, fmt.Errorf("commit: %w", sqlStateError("40001")), true},
+		{PostgresDriver{}, sqlStateError("40P01"), true},
+		{PostgresDriver{}, sqlStateError("23505"), false},
+		{MySQLDriver{}, errors.New("Error 1213 (40001): Deadlock found when trying to get lock"), true},
+		{MySQLDriver{}, errors.New("Error 1062 (23000): Duplicate entry"), false},
+		{SQLiteDriver{}, errors.New("database is locked"), true},
+		{OracleDriver{}, errors.New("ORA-08177: can't serialize access for this transaction"), true},
+		{SQLServerDriver{}, sqlServerError(1205), true},
+		{SQLServerDriver{}, sqlServerError(2627), false},
+		{noDSNDriver{}, sqlStateError("40001"), true},
+		{noDSNDriver{}, errors.New("database is locked"), false},
+		{PostgresDriver{}, nil, false},
+	}
+	for _, test := range tests {
+		if got := IsRetryableError(test.driver, test.err); got != test.want {
+			t.Errorf("IsRetryableError(%s, %v) = %v, want %v", test.driver.Name(), test.err, got, test.want)
+		}
+	}
+}
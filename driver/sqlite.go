@@ -21,7 +21,7 @@ type SQLiteDriver struct{}
 
 // Translator returns a translator of SQL.
 func (d SQLiteDriver) Translator() Translator {
-	return TranslateFunc(func(matched string) string { return "?" })
+	return newDialectTranslator(TranslateFunc(func(matched string) string { return "?" }), '"', ansiLikeEscapeClause, d.Capabilities())
 }
 
 func (d SQLiteDriver) Name() string {
@@ -9,3 +9,18 @@ func TestSQLiteDriver_sqlite_test(t *testing.T) {
 		t.Fatal("failed to translate")
 	}
 }
+
+func TestEscapeLike_sqlite_test(t *testing.T) {
+	if got := EscapeLike(`50%_a\b`); got != `50\%\_a\\b` {
+		t.Fatalf("unexpected escaped pattern: %s", got)
+	}
+	if got := LikeEscapeClause(SQLiteDriver{}.Translator()); got != ` ESCAPE '\'` {
+		t.Fatalf("unexpected escape clause: %s", got)
+	}
+	if got := LikeEscapeClause(MySQLDriver{}.Translator()); got != ` ESCAPE '\\'` {
+		t.Fatalf("unexpected mysql escape clause: %s", got)
+	}
+	if got := LikeEscapeClause(TranslateFunc(func(string) string { return "?" })); got != ` ESCAPE '\'` {
+		t.Fatalf("unexpected fallback escape clause: %s", got)
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import "strconv"
+
+// SQLServerDriver is a driver of Microsoft SQL Server.
+type SQLServerDriver struct{}
+
+// Translator returns a translator of SQL, which numbers the placeholders like @p1.
+func (d SQLServerDriver) Translator() Translator {
+	var i int
+	return newDialectTranslator(TranslateFunc(func(matched string) string {
+		i++
+		return "@p" + strconv.Itoa(i)
+	}), '"', ansiLikeEscapeClause, d.Capabilities())
+}
+
+func (d SQLServerDriver) Name() string {
+	return "sqlserver"
+}
+
+func (d SQLServerDriver) String() string {
+	return d.Name()
+}
+
+func init() {
+	Register("sqlserver", &SQLServerDriver{})
+}
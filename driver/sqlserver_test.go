@@ -0,0 +1,22 @@
+package driver
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestSQLServerDriver_sqlserver_test(t *testing.T) {
+	driver := SQLServerDriver{}
+	translator := driver.Translator()
+	for i := range 3 {
+		if got := translator.Translate("foo"); got != "@p"+strconv.Itoa(i+1) {
+			t.Fatalf("unexpected placeholder %q", got)
+		}
+	}
+	if got := QuoteIdentifier(driver.Translator(), "dbo.user"); got != `"dbo"."user"` {
+		t.Fatalf("unexpected quoted identifier: %s", got)
+	}
+	if registered, err := Get("sqlserver"); err != nil || registered.Name() != "sqlserver" {
+		t.Fatalf("expected sqlserver to be registered, got %v %v", registered, err)
+	}
+}
@@ -16,6 +16,8 @@ limitations under the License.
 
 package driver
 
+import "strings"
+
 // Translator is an interface for translating the matched string.
 type Translator interface {
 	Translate(matched string) string
@@ -28,3 +30,136 @@ type TranslateFunc func(matched string) string
 func (f TranslateFunc) Translate(matched string) string {
 	return f(matched)
 }
+
+// IdentifierQuoter quotes SQL identifiers, such as table and column names, for a dialect.
+// Translators returned by the builtin drivers implement it.
+type IdentifierQuoter interface {
+	QuoteIdentifier(name string) string
+}
+
+// dialectTranslator is a Translator which also knows how the dialect quotes identifiers,
+// escapes LIKE patterns, inserts rows and upserts rows, as described by its Capabilities.
+type dialectTranslator struct {
+	Translator
+	quote            byte
+	likeEscapeClause string
+	capabilities     Capabilities
+}
+
+// QuoteIdentifier implements the IdentifierQuoter interface.
+func (d dialectTranslator) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, d.quote)
+}
+
+// LikeEscapeClause implements the LikeEscaper interface.
+func (d dialectTranslator) LikeEscapeClause() string {
+	return d.likeEscapeClause
+}
+
+// MultiRowValues implements the MultiRowValuer interface.
+func (d dialectTranslator) MultiRowValues() bool {
+	return d.capabilities.MultiRowValues
+}
+
+// UpsertStyle implements the Upserter interface.
+func (d dialectTranslator) UpsertStyle() UpsertStyle {
+	return d.capabilities.Upsert
+}
+
+// newDialectTranslator returns a translator which quotes identifiers with quote,
+// uses likeEscapeClause for escaped LIKE patterns and inserts and upserts rows as described by capabilities.
+func newDialectTranslator(translator Translator, quote byte, likeEscapeClause string, capabilities Capabilities) Translator {
+	return dialectTranslator{Translator: translator, quote: quote, likeEscapeClause: likeEscapeClause, capabilities: capabilities}
+}
+
+// QuoteIdentifier quotes name with the dialect of translator.
+// Qualified names like schema.table are quoted part by part.
+// If translator does not implement IdentifierQuoter, ANSI double quotes are used.
+func QuoteIdentifier(translator Translator, name string) string {
+	if quoter, ok := translator.(IdentifierQuoter); ok {
+		return quoter.QuoteIdentifier(name)
+	}
+	return quoteIdentifier(name, '"')
+}
+
+// quoteIdentifier quotes each dot separated part of name, doubling the quote characters inside.
+func quoteIdentifier(name string, quote byte) string {
+	var builder strings.Builder
+	builder.Grow(len(name) + 2)
+	builder.WriteByte(quote)
+	for i := 0; i < len(name); i++ {
+		switch name[i] {
+		case '.':
+			builder.WriteByte(quote)
+			builder.WriteByte('.')
+			builder.WriteByte(quote)
+		case quote:
+			builder.WriteByte(quote)
+			builder.WriteByte(quote)
+		default:
+			builder.WriteByte(name[i])
+		}
+	}
+	builder.WriteByte(quote)
+	return builder.String()
+}
+
+// LikeEscaper tells how a dialect declares the escape character of a LIKE pattern.
+// Translators returned by the builtin drivers implement it.
+type LikeEscaper interface {
+	// LikeEscapeClause returns the clause appended after an escaped LIKE pattern,
+	// declaring backslash as the escape character, like ` ESCAPE '\'`.
+	LikeEscapeClause() string
+}
+
+// ansiLikeEscapeClause declares backslash as the escape character in standard SQL.
+const ansiLikeEscapeClause = ` ESCAPE '\'`
+
+// LikeEscapeClause returns the LIKE escape clause of translator.
+// If translator does not implement LikeEscaper, the standard SQL clause is used.
+func LikeEscapeClause(translator Translator) string {
+	if escaper, ok := translator.(LikeEscaper); ok {
+		return escaper.LikeEscapeClause()
+	}
+	return ansiLikeEscapeClause
+}
+
+// likeReplacer escapes the wildcards and the escape character of LIKE patterns.
+var likeReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike escapes %, _ and backslash in s with backslash,
+// so that s matches literally when embedded in a LIKE pattern.
+// The pattern must be followed by the clause returned by LikeEscapeClause.
+func EscapeLike(s string) string {
+	return likeReplacer.Replace(s)
+}
+
+// MultiRowValuer tells whether a dialect inserts several rows with a single VALUES clause.
+// Translators returned by the builtin drivers implement it.
+type MultiRowValuer interface {
+	MultiRowValues() bool
+}
+
+// MultiRowValuesOf reports whether the dialect of translator accepts several rows of VALUES.
+// If translator does not implement MultiRowValuer, they are assumed to be accepted, like with CapabilitiesOf.
+func MultiRowValuesOf(translator Translator) bool {
+	if valuer, ok := translator.(MultiRowValuer); ok {
+		return valuer.MultiRowValues()
+	}
+	return true
+}
+
+// Upserter tells how a dialect updates the row an INSERT conflicts with.
+// Translators returned by the builtin drivers implement it.
+type Upserter interface {
+	UpsertStyle() UpsertStyle
+}
+
+// UpsertStyleOf returns the UpsertStyle of translator.
+// If translator does not implement Upserter, upserts are unsupported.
+func UpsertStyleOf(translator Translator) UpsertStyle {
+	if upserter, ok := translator.(Upserter); ok {
+		return upserter.UpsertStyle()
+	}
+	return UpsertUnsupported
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	stdsql "database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-juicedev/juice/eval"
+	"github.com/go-juicedev/juice/session"
+	"github.com/go-juicedev/juice/sql"
+)
+
+// ErrDryRunUnsupported is returned when the statement handler of an executor cannot dry run statements.
+var ErrDryRunUnsupported = errors.New("juice: dry run is not supported by the statement handler")
+
+// errDryRun stops the execution chain of a dry run before the database.
+var errDryRun = errors.New("juice: dry run")
+
+// DryRunResult is the statement an executor would send to the database.
+type DryRunResult struct {
+	// Query is the final query, once rewritten by the middlewares.
+	Query string
+
+	// Args are the arguments of the query, in order.
+	Args []any
+
+	// DataSource is the name of the environment the statement would be executed on.
+	DataSource string
+}
+
+// dryRunner is implemented by the statement handlers which can dry run statements.
+type dryRunner interface {
+	dryRun(ctx context.Context, statement Statement, param eval.Param) (*DryRunResult, error)
+}
+
+// ensure dryRunMiddleware implements Middleware.
+var _ Middleware = (*dryRunMiddleware)(nil) // compile time check
+
+// dryRunMiddleware is the innermost middleware of a dry run.
+// It records the statement instead of executing it.
+type dryRunMiddleware struct {
+	result *DryRunResult
+}
+
+// record records the statement about to be executed through statementContext.
+func (m *dryRunMiddleware) record(statementContext *StatementContext, query string, args []any) {
+	m.result.Query, m.result.Args = query, args
+	m.result.DataSource = statementContext.Engine().dataSourceOf(statementContext.Session())
+}
+
+// QueryContext implements Middleware.
+func (m *dryRunMiddleware) QueryContext(statementContext *StatementContext, _ QueryHandler) QueryHandler {
+	return func(_ context.Context, query string, args ...any) (sql.Rows, error) {
+		m.record(statementContext, query, args)
+		return nil, errDryRun
+	}
+}
+
+// ExecContext implements Middleware.
+func (m *dryRunMiddleware) ExecContext(statementContext *StatementContext, _ ExecHandler) ExecHandler {
+	return func(_ context.Context, query string, args ...any) (sql.Result, error) {
+		m.record(statementContext, query, args)
+		return nil, errDryRun
+	}
+}
+
+// dryRun builds statement and runs it through the middlewares of engine, stopping before the database.
+// Batch statements are built as a single statement.
+func dryRun(ctx context.Context, engine *Engine, sess session.Session, statement Statement, param eval.Param) (*DryRunResult, error) {
+	query, args, err := buildStatementQuery(ctx, statement, engine, param)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DryRunResult{}
+	dryRunEngine := engine.clone()
+	dryRunEngine.middlewares = append(MiddlewareGroup{&dryRunMiddleware{result: result}}, engine.middlewares...)
+
+	statementHandler := newExecuteStatementHandler(query, args, dryRunEngine, sess)
+	if statement.Action() == sql.Select {
+		_, err = statementHandler.QueryContext(ctx, statement, param)
+	} else {
+		_, err = statementHandler.ExecContext(ctx, statement, param)
+	}
+	if !errors.Is(err, errDryRun) {
+		if err == nil {
+			err = fmt.Errorf("%w: the statement was answered by a middleware", ErrDryRunUnsupported)
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// dataSourceOf returns the name of the environment of sess.
+// Transactions and unknown sessions belong to the active environment of the engine.
+func (e *Engine) dataSourceOf(sess session.Session) string {
+	db, ok := sess.(*stdsql.DB)
+	if !ok || db == e.db || e.manager == nil {
+		return e.using
+	}
+	if name, ok := e.manager.sourceOf(db); ok {
+		return name
+	}
+	return e.using
+}
+
+// dryRun implements dryRunner.
+func (s *queryBuildStatementHandler) dryRun(ctx context.Context, statement Statement, param eval.Param) (*DryRunResult, error) {
+	return dryRun(ctx, s.engine, s.session, statement, param)
+}
+
+// dryRun implements dryRunner.
+func (b *batchStatementHandler) dryRun(ctx context.Context, statement Statement, param eval.Param) (*DryRunResult, error) {
+	return dryRun(ctx, b.engine, b.session, statement, param)
+}
@@ -0,0 +1,81 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDryRun_dry_run_test(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="primary">
+		<environment id="primary">
+			<dataSource>primary.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>
+		<mapper namespace="user">
+			<select id="Find">SELECT * FROM user WHERE id = #{id}</select>
+			<select id="FindReplica" dataSource="replica">SELECT * FROM user WHERE id = #{id}</select>
+			<insert id="Save">INSERT INTO user(name) VALUES (#{name})</insert>
+		</mapper>
+	</mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	driverName := registerDBManagerTestDriver(t)
+	manager := &DBManager{}
+	for _, name := range []string{"primary", "replica"} {
+		if err = manager.Add(name, Source{Driver: driverName}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	engine := newStatementTestEngine(nil, &TxSensitiveDataSourceSwitchMiddleware{})
+	engine.configuration, engine.manager, engine.using = configuration, manager, "primary"
+	if engine.db, engine.driver, err = manager.Get("primary"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		id    string
+		param H
+		want  DryRunResult
+	}{
+		{"user.Find", H{"id": 1}, DryRunResult{Query: "SELECT * FROM user WHERE id = ?", Args: []any{1}, DataSource: "primary"}},
+		{"user.FindReplica", H{"id": 2}, DryRunResult{Query: "SELECT * FROM user WHERE id = ?", Args: []any{2}, DataSource: "replica"}},
+		{"user.Save", H{"name": "a"}, DryRunResult{Query: "INSERT INTO user(name) VALUES (?)", Args: []any{"a"}, DataSource: "primary"}},
+	}
+	for _, tt := range tests {
+		got, err := engine.Object(tt.id).DryRun(ctx, tt.param)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.id, err)
+		}
+		if !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.id, *got, tt.want)
+		}
+	}
+
+	for name, stats := range engine.Stats() {
+		if stats.OpenConnections != 0 {
+			t.Errorf("expected no connection to %s, got %d", name, stats.OpenConnections)
+		}
+	}
+
+	if _, err = engine.Object("user.Missing").DryRun(ctx, nil); !errors.Is(err, ErrInvalidExecutor) {
+		t.Fatalf("expected ErrInvalidExecutor, got %v", err)
+	}
+	executor := NewSQLRowsExecutor(nil, newPreparedStatementHandler(nil, engine), engine.Driver())
+	if _, err = executor.DryRun(ctx, nil); !errors.Is(err, ErrDryRunUnsupported) {
+		t.Fatalf("expected ErrDryRunUnsupported, got %v", err)
+	}
+}
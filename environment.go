@@ -17,6 +17,7 @@ limitations under the License.
 package juice
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"iter"
@@ -46,6 +47,10 @@ type Environment struct {
 	// MaxIdleConnLifetime is a maximum lifetime of an idle connection.
 	MaxIdleConnLifetime int
 
+	// TxOptions are the default options of the transactions begun on the environment
+	// without explicit options, set by the <transaction> element. Nil means the driver defaults.
+	TxOptions *sql.TxOptions
+
 	// attrs is a map of attributes.
 	attrs map[string]string
 }
@@ -19,6 +19,7 @@ package juice
 import (
 	"errors"
 
+	"github.com/go-juicedev/juice/driver"
 	"github.com/go-juicedev/juice/sql"
 )
 
@@ -37,4 +38,18 @@ var (
 
 	// ErrNoManagerFoundInContext is returned when the context has no manager.
 	ErrNoManagerFoundInContext = errors.New("no manager found in context")
+
+	// ErrUnexpectedRowCount is returned when a statement affects another number of rows than expected.
+	ErrUnexpectedRowCount = errors.New("juice: unexpected row count")
+
+	// ErrDuplicateKey is wrapped into the errors of the statements violating a unique or primary key,
+	// whatever the database, see driver.TranslateError.
+	ErrDuplicateKey = driver.ErrDuplicateKey
+
+	// ErrForeignKeyViolation is wrapped into the errors of the statements violating a foreign key.
+	ErrForeignKeyViolation = driver.ErrForeignKeyViolation
+
+	// ErrSerialization is wrapped into the errors of the statements aborted by a serialization failure
+	// or a deadlock, which may succeed when their transaction is retried.
+	ErrSerialization = driver.ErrSerialization
 )
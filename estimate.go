@@ -0,0 +1,79 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	"github.com/go-juicedev/juice/sql"
+)
+
+// ErrEstimateUnsupported is returned by EstimateCount when the driver cannot estimate the rows of a query.
+var ErrEstimateUnsupported = errors.New("juice: row count estimation is not supported by the driver")
+
+// EstimateCount returns the number of rows the select statement v would return with param,
+// as estimated by the planner of the database instead of counting them, like with
+// EXPLAIN on PostgreSQL and MySQL. The estimate is cheap but approximate, which suits
+// the pagination deciding between an exact and an estimated total:
+//
+//	estimate, err := engine.EstimateCount(ctx, "user.Search", param)
+//	if err == nil && estimate > 10000 {
+//	    // show "about 10,000 results" instead of counting them
+//	}
+//
+// The statement is rewritten by the middlewares like when it is executed.
+// It returns ErrEstimateUnsupported if the driver does not implement driver.RowCountEstimator.
+func (e *Engine) EstimateCount(ctx context.Context, v any, param eval.Param) (int64, error) {
+	estimator, ok := e.Driver().(driver.RowCountEstimator)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrEstimateUnsupported, e.Driver().Name())
+	}
+	statement, err := e.GetConfiguration().GetStatement(v)
+	if err == nil {
+		statement, err = resolveDatabaseStatement(statement, e)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if statement.Action() != sql.Select {
+		return 0, fmt.Errorf("juice: cannot estimate the rows of %s statement %s", statement.Action(), statement.Name())
+	}
+	result, err := dryRun(ctx, e, e.DB(), statement, param)
+	if err != nil {
+		return 0, err
+	}
+	db := e.DB()
+	if result.DataSource != e.using && e.manager != nil {
+		if db, _, err = e.manager.Get(result.DataSource); err != nil {
+			return 0, err
+		}
+	}
+	rows, err := db.QueryContext(ctx, estimator.EstimateQuery(strings.TrimSpace(result.Query)), result.Args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate rows: %w", err)
+	}
+	columns, values, err := readPlan(rows)
+	if err != nil {
+		return 0, err
+	}
+	return estimator.ParseEstimate(columns, values)
+}
@@ -0,0 +1,80 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	jdriver "github.com/go-juicedev/juice/driver"
+)
+
+type estimateTestDriver struct {
+	jdriver.SQLiteDriver
+	queries []string
+}
+
+func (d *estimateTestDriver) EstimateQuery(query string) string {
+	d.queries = append(d.queries, query)
+	return "EXPLAIN " + query
+}
+
+func (d *estimateTestDriver) ParseEstimate(columns []string, _ [][]any) (int64, error) {
+	if !slices.Equal(columns, []string{"value"}) {
+		return 0, errors.New("unexpected plan")
+	}
+	return 42, nil
+}
+
+func TestEngineEstimateCount_estimate_test(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="primary">
+		<environment id="primary">
+			<dataSource>primary.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>
+		<mapper namespace="user">
+			<select id="Search">SELECT * FROM user WHERE name = #{name}</select>
+			<delete id="Delete">DELETE FROM user WHERE id = #{id}</delete>
+		</mapper>
+	</mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	engine := newStatementTestEngine(nil)
+	engine.configuration = configuration
+	engine.db = openStatementTestDB(t, &shSQLDriverState{})
+	ctx := context.Background()
+
+	if _, err = engine.EstimateCount(ctx, "user.Search", H{"name": "a"}); !errors.Is(err, ErrEstimateUnsupported) {
+		t.Fatalf("expected ErrEstimateUnsupported, got %v", err)
+	}
+
+	drv := &estimateTestDriver{}
+	engine.driver = drv
+	got, err := engine.EstimateCount(ctx, "user.Search", H{"name": "a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 42 {
+		t.Fatalf("expected 42, got %d", got)
+	}
+	if len(drv.queries) != 1 || drv.queries[0] != "SELECT * FROM user WHERE name = ?" {
+		t.Fatalf("unexpected estimated queries %q", drv.queries)
+	}
+
+	if _, err = engine.EstimateCount(ctx, "user.Delete", H{"id": 1}); err == nil {
+		t.Fatal("expected error for a delete statement")
+	}
+	if _, err = engine.EstimateCount(ctx, "user.Missing", nil); err == nil {
+		t.Fatal("expected error for a missing statement")
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eval
+
+import (
+	"sync/atomic"
+
+	"github.com/go-juicedev/juice/internal/container"
+)
+
+// DefaultCompileCacheSize is the number of compiled expressions kept by the default compiler.
+const DefaultCompileCacheSize = 1024
+
+// CompileCacheStats reports the usage of a CachedCompiler.
+type CompileCacheStats struct {
+	// Hits is the number of compilations served from the cache.
+	Hits uint64
+	// Misses is the number of compilations delegated to the underlying compiler.
+	Misses uint64
+	// Evictions is the number of expressions evicted to make room for new ones.
+	Evictions uint64
+	// Size is the number of expressions currently cached.
+	Size int
+	// Capacity is the maximum number of expressions cached.
+	Capacity int
+}
+
+// CachedCompiler is an ExprCompiler which caches the compiled expressions by their source.
+// Mappers usually repeat the same conditions (like `id != nil`) many times,
+// the cache makes them compiled only once.
+// Compiled expressions are immutable, so they are safe to be shared.
+// Failed compilations are not cached.
+type CachedCompiler struct {
+	compiler  ExprCompiler
+	cache     *container.LRU[string, Expression]
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+// Compile returns the cached expression of expr, compiling it on a miss.
+func (c *CachedCompiler) Compile(expr string) (Expression, error) {
+	if expression, ok := c.cache.Get(expr); ok {
+		c.hits.Add(1)
+		return expression, nil
+	}
+	c.misses.Add(1)
+	expression, err := c.compiler.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Set(expr, expression)
+	return expression, nil
+}
+
+// Stats returns the current usage of the cache.
+func (c *CachedCompiler) Stats() CompileCacheStats {
+	return CompileCacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Size:      c.cache.Len(),
+		Capacity:  c.cache.Cap(),
+	}
+}
+
+// Reset drops all the cached expressions and clears the counters.
+func (c *CachedCompiler) Reset() {
+	c.cache.Purge()
+	c.hits.Store(0)
+	c.misses.Store(0)
+	c.evictions.Store(0)
+}
+
+// NewCachedCompiler wraps compiler with an LRU cache holding at most capacity expressions.
+// It panics if compiler is nil or capacity is not positive.
+func NewCachedCompiler(compiler ExprCompiler, capacity int) *CachedCompiler {
+	if compiler == nil {
+		panic("compiler cannot be nil")
+	}
+	if capacity <= 0 {
+		panic("capacity must be positive")
+	}
+	cached := &CachedCompiler{compiler: compiler}
+	cached.cache = container.NewLRU[string, Expression](capacity, func(string, Expression) {
+		cached.evictions.Add(1)
+	})
+	return cached
+}
+
+// CompileStats returns the cache usage of the default compiler.
+// The second return value is false if the default compiler is not a CachedCompiler.
+func CompileStats() (CompileCacheStats, bool) {
+	cached, ok := defaultCompiler.(*CachedCompiler)
+	if !ok {
+		return CompileCacheStats{}, false
+	}
+	return cached.Stats(), true
+}
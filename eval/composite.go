@@ -0,0 +1,190 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eval
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"reflect"
+
+	"github.com/go-juicedev/juice/internal/reflectlite"
+)
+
+var errUnsupportedCompositeLit = errors.New("unsupported composite literal")
+
+// literalTypes are the named types which can be used in composite literals.
+var literalTypes = map[string]reflect.Type{
+	"bool":    reflect.TypeFor[bool](),
+	"string":  reflect.TypeFor[string](),
+	"int":     reflect.TypeFor[int](),
+	"int8":    reflect.TypeFor[int8](),
+	"int16":   reflect.TypeFor[int16](),
+	"int32":   reflect.TypeFor[int32](),
+	"int64":   reflect.TypeFor[int64](),
+	"uint":    reflect.TypeFor[uint](),
+	"uint8":   reflect.TypeFor[uint8](),
+	"uint16":  reflect.TypeFor[uint16](),
+	"uint32":  reflect.TypeFor[uint32](),
+	"uint64":  reflect.TypeFor[uint64](),
+	"float32": reflect.TypeFor[float32](),
+	"float64": reflect.TypeFor[float64](),
+	"byte":    reflect.TypeFor[byte](),
+	"rune":    reflect.TypeFor[rune](),
+	"any":     reflect.TypeFor[any](),
+}
+
+// resolveLiteralType resolves the type expression of a composite literal.
+// Only slices, arrays and maps of the types in literalTypes are supported.
+func resolveLiteralType(exp ast.Expr, params Parameter) (reflect.Type, error) {
+	switch exp := exp.(type) {
+	case *ast.Ident:
+		if typ, ok := literalTypes[exp.Name]; ok {
+			return typ, nil
+		}
+	case *ast.InterfaceType:
+		if exp.Methods == nil || len(exp.Methods.List) == 0 {
+			return literalTypes["any"], nil
+		}
+	case *ast.ParenExpr:
+		return resolveLiteralType(exp.X, params)
+	case *ast.ArrayType:
+		elem, err := resolveLiteralType(exp.Elt, params)
+		if err != nil {
+			return nil, err
+		}
+		if exp.Len == nil {
+			return reflect.SliceOf(elem), nil
+		}
+		length, err := eval(exp.Len, params)
+		if err != nil {
+			return nil, err
+		}
+		n, err := reflectValueToInt(length)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("%w: negative array length %d", errUnsupportedCompositeLit, n)
+		}
+		return reflect.ArrayOf(n, elem), nil
+	case *ast.MapType:
+		key, err := resolveLiteralType(exp.Key, params)
+		if err != nil {
+			return nil, err
+		}
+		if !key.Comparable() {
+			return nil, fmt.Errorf("%w: invalid map key type %s", errUnsupportedCompositeLit, key)
+		}
+		value, err := resolveLiteralType(exp.Value, params)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.MapOf(key, value), nil
+	}
+	return nil, fmt.Errorf("%w: unsupported type %T", errUnsupportedCompositeLit, exp)
+}
+
+// evalCompositeLit evaluates slice, array and map literals such as
+// []string{"A", "B"} or map[string]int{"a": 1}.
+func evalCompositeLit(exp *ast.CompositeLit, params Parameter) (reflect.Value, error) {
+	if exp.Type == nil {
+		return reflect.Value{}, fmt.Errorf("%w: missing type", errUnsupportedCompositeLit)
+	}
+	typ, err := resolveLiteralType(exp.Type, params)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return evalCompositeLitOf(typ, exp, params)
+}
+
+// evalCompositeLitOf evaluates the elements of exp as a value of typ.
+// The type of exp may be elided for nested literals, e.g. [][]int{{1, 2}}.
+func evalCompositeLitOf(typ reflect.Type, exp *ast.CompositeLit, params Parameter) (reflect.Value, error) {
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		var value reflect.Value
+		if typ.Kind() == reflect.Slice {
+			value = reflect.MakeSlice(typ, 0, len(exp.Elts))
+		} else {
+			if len(exp.Elts) > typ.Len() {
+				return reflect.Value{}, fmt.Errorf("%w: array index %d out of bounds", errUnsupportedCompositeLit, typ.Len())
+			}
+			value = reflect.New(typ).Elem()
+		}
+		for i, elt := range exp.Elts {
+			if _, ok := elt.(*ast.KeyValueExpr); ok {
+				return reflect.Value{}, fmt.Errorf("%w: indexed elements are not supported", errUnsupportedCompositeLit)
+			}
+			elem, err := evalLiteralElement(typ.Elem(), elt, params)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			if typ.Kind() == reflect.Slice {
+				value = reflect.Append(value, elem)
+			} else {
+				value.Index(i).Set(elem)
+			}
+		}
+		return value, nil
+	case reflect.Map:
+		value := reflect.MakeMapWithSize(typ, len(exp.Elts))
+		for _, elt := range exp.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				return reflect.Value{}, fmt.Errorf("%w: missing key in map literal", errUnsupportedCompositeLit)
+			}
+			key, err := evalLiteralElement(typ.Key(), kv.Key, params)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			elem, err := evalLiteralElement(typ.Elem(), kv.Value, params)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			value.SetMapIndex(key, elem)
+		}
+		return value, nil
+	default:
+		return reflect.Value{}, fmt.Errorf("%w: unsupported type %s", errUnsupportedCompositeLit, typ)
+	}
+}
+
+// evalLiteralElement evaluates an element of a composite literal and converts it to typ.
+func evalLiteralElement(typ reflect.Type, exp ast.Expr, params Parameter) (reflect.Value, error) {
+	if lit, ok := exp.(*ast.CompositeLit); ok && lit.Type == nil {
+		return evalCompositeLitOf(typ, lit, params)
+	}
+	value, err := eval(exp, params)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	value = reflectlite.Unwrap(value)
+	switch {
+	case !value.IsValid() || (reflectlite.IsNilable(value) && value.IsNil()):
+		if !reflectlite.IsNilable(reflect.Zero(typ)) {
+			return reflect.Value{}, fmt.Errorf("%w: cannot use nil as %s", errUnsupportedCompositeLit, typ)
+		}
+		return reflect.Zero(typ), nil
+	case value.Type().AssignableTo(typ):
+		return value, nil
+	case canConvertMapIndex(value.Type(), typ):
+		return value.Convert(typ), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("%w: cannot use %s as %s", errUnsupportedCompositeLit, value.Type(), typ)
+	}
+}
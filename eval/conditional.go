@@ -0,0 +1,251 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eval
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"reflect"
+
+	"github.com/go-juicedev/juice/internal/reflectlite"
+)
+
+const (
+	// iifFuncName is the name of the conditional builtin.
+	// The ternary operator `cond ? a : b` is rewritten to `iif(cond, a, b)`.
+	iifFuncName = "iif"
+
+	// elvisFuncName is the name of the elvis builtin.
+	// The elvis operator `a ?: b` is rewritten to `elvis(a, b)`.
+	elvisFuncName = "elvis"
+)
+
+var errInvalidConditionalExpr = errors.New("invalid conditional expression")
+
+// lazyBuiltin is a builtin function whose arguments are evaluated on demand.
+type lazyBuiltin func(args []ast.Expr, params Parameter) (reflect.Value, error)
+
+// getLazyBuiltin returns the builtin which must not evaluate all of its arguments.
+// Lazy builtins are resolved before any other function.
+func getLazyBuiltin(name string) (lazyBuiltin, bool) {
+	switch name {
+	case iifFuncName:
+		return evalIif, true
+	case elvisFuncName:
+		return evalElvis, true
+	default:
+		return nil, false
+	}
+}
+
+// evalIif evaluates iif(cond, a, b).
+// Only the branch selected by cond is evaluated.
+func evalIif(args []ast.Expr, params Parameter) (reflect.Value, error) {
+	if len(args) != 3 {
+		return reflect.Value{}, fmt.Errorf("%w: iif expects 3 arguments, got %d", errInvalidConditionalExpr, len(args))
+	}
+	cond, err := eval(args[0], params)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	cond = reflectlite.Unwrap(cond)
+	if cond.Kind() != reflect.Bool {
+		return reflect.Value{}, fmt.Errorf("%w: condition must be bool, got %s", errInvalidConditionalExpr, cond.Kind())
+	}
+	if cond.Bool() {
+		return eval(args[1], params)
+	}
+	return eval(args[2], params)
+}
+
+// evalElvis evaluates elvis(a, b).
+// It returns a unless a is nil or the zero value of its type, otherwise b.
+// b is only evaluated when it is returned.
+func evalElvis(args []ast.Expr, params Parameter) (reflect.Value, error) {
+	if len(args) != 2 {
+		return reflect.Value{}, fmt.Errorf("%w: elvis expects 2 arguments, got %d", errInvalidConditionalExpr, len(args))
+	}
+	value, err := eval(args[0], params)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if unwrapped := reflectlite.Unwrap(value); unwrapped.IsValid() && !unwrapped.IsZero() {
+		return value, nil
+	}
+	return eval(args[1], params)
+}
+
+// rewriteConditional rewrites the ternary operator `cond ? a : b` into `iif(cond, a, b)`
+// and the elvis operator `a ?: b` into `elvis(a, b)`, so that the expression can be
+// parsed by the Go parser.
+// Both operators have the lowest precedence and are right associative.
+func rewriteConditional(tokens []string) []string {
+	question := indexAtDepthZero(tokens, 0, "?")
+	if question < 0 {
+		return rewriteGroups(tokens)
+	}
+	cond := rewriteConditional(tokens[:question])
+
+	// elvis operator: a ?: b
+	if question+1 < len(tokens) && tokens[question+1] == ":" {
+		rest := rewriteConditional(tokens[question+2:])
+		return joinCall(elvisFuncName, cond, rest)
+	}
+
+	colon := matchingColon(tokens, question)
+	if colon < 0 {
+		// leave the expression as it is, the parser reports the syntax error.
+		return tokens
+	}
+	then := rewriteConditional(tokens[question+1 : colon])
+	otherwise := rewriteConditional(tokens[colon+1:])
+	return joinCall(iifFuncName, cond, then, otherwise)
+}
+
+// rewriteGroups rewrites the conditional operators inside the top-level brackets of tokens.
+// Each comma separated element of a group is rewritten independently, and an element
+// like `key: value` or `low: high` keeps its leading part untouched.
+func rewriteGroups(tokens []string) []string {
+	result := make([]string, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		result = append(result, tokens[i])
+		if !isOpenBracket(tokens[i]) {
+			continue
+		}
+		end := matchingBracket(tokens, i)
+		if end < 0 {
+			return append(result, tokens[i+1:]...)
+		}
+		start := i + 1
+		for start <= end {
+			comma := indexAtDepthZero(tokens[:end], start, ",")
+			if comma < 0 {
+				comma = end
+			}
+			result = append(result, rewriteElement(tokens[start:comma])...)
+			if comma < end {
+				result = append(result, ",")
+			}
+			start = comma + 1
+		}
+		result = append(result, tokens[end])
+		i = end
+	}
+	return result
+}
+
+// rewriteElement rewrites a single element of a bracket group.
+func rewriteElement(tokens []string) []string {
+	question := indexAtDepthZero(tokens, 0, "?")
+	if question < 0 {
+		return rewriteGroups(tokens)
+	}
+	colon := indexAtDepthZero(tokens, 0, ":")
+	if colon >= 0 && colon < question {
+		result := append(rewriteGroups(tokens[:colon]), ":")
+		return append(result, rewriteConditional(tokens[colon+1:])...)
+	}
+	return rewriteConditional(tokens)
+}
+
+// joinCall returns the tokens of the call name(args...).
+func joinCall(name string, args ...[]string) []string {
+	result := []string{name, "("}
+	for i, arg := range args {
+		if i > 0 {
+			result = append(result, ",")
+		}
+		result = append(result, arg...)
+	}
+	return append(result, ")")
+}
+
+// matchingColon returns the index of the colon matching the question mark at index question.
+func matchingColon(tokens []string, question int) int {
+	pending := 1
+	for i := question + 1; i < len(tokens); i++ {
+		i = skipGroup(tokens, i)
+		if i < 0 {
+			return -1
+		}
+		switch tokens[i] {
+		case "?":
+			if i+1 < len(tokens) && tokens[i+1] == ":" {
+				// skip the elvis operator
+				i++
+				continue
+			}
+			pending++
+		case ":":
+			pending--
+			if pending == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// indexAtDepthZero returns the index of the first token equal to target from start
+// which is not nested in any bracket.
+func indexAtDepthZero(tokens []string, start int, target string) int {
+	for i := start; i < len(tokens); i++ {
+		i = skipGroup(tokens, i)
+		if i < 0 {
+			return -1
+		}
+		if tokens[i] == target {
+			return i
+		}
+	}
+	return -1
+}
+
+// skipGroup returns the index of the closing bracket if tokens[i] opens a bracket group,
+// otherwise i itself. It returns -1 if the group is not closed.
+func skipGroup(tokens []string, i int) int {
+	if isOpenBracket(tokens[i]) {
+		return matchingBracket(tokens, i)
+	}
+	return i
+}
+
+// matchingBracket returns the index of the bracket closing the one at index open.
+func matchingBracket(tokens []string, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		switch {
+		case isOpenBracket(tokens[i]):
+			depth++
+		case isCloseBracket(tokens[i]):
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isOpenBracket(token string) bool {
+	return token == "(" || token == "[" || token == "{"
+}
+
+func isCloseBracket(token string) bool {
+	return token == ")" || token == "]" || token == "}"
+}
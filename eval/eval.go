@@ -102,7 +102,8 @@ func (e *goExpression) Execute(params Parameter) (Value, error) {
 }
 
 // defaultCompiler is the default expression compiler used by the package.
-var defaultCompiler ExprCompiler = &goExprCompiler{}
+// It caches the compiled expressions, see CompileStats for its usage.
+var defaultCompiler ExprCompiler = NewCachedCompiler(&goExprCompiler{}, DefaultCompileCacheSize)
 
 // WithCompiler sets the default expression compiler.
 // nil is not allowed.
@@ -148,6 +149,8 @@ func eval(exp ast.Expr, params Parameter) (reflect.Value, error) {
 		return evalStarExpr(exp, params)
 	case *ast.SliceExpr:
 		return evalSliceExpr(exp, params)
+	case *ast.CompositeLit:
+		return evalCompositeLit(exp, params)
 	default:
 		return reflect.Value{}, fmt.Errorf("unsupported expression: %T", exp)
 	}
@@ -380,6 +383,11 @@ func isNumericKind(kind reflect.Kind) bool {
 }
 
 func evalCallExpr(exp *ast.CallExpr, params Parameter) (reflect.Value, error) {
+	if ident, ok := exp.Fun.(*ast.Ident); ok {
+		if lazy, ok := getLazyBuiltin(ident.Name); ok {
+			return lazy(exp.Args, params)
+		}
+	}
 	fn, err := eval(exp.Fun, params)
 	if err != nil {
 		return reflect.Value{}, err
@@ -398,23 +406,41 @@ func evalCallExpr(exp *ast.CallExpr, params Parameter) (reflect.Value, error) {
 		return reflect.Value{}, err
 	}
 
-	if fnType.NumOut() != 2 {
-		return reflect.Value{}, fmt.Errorf("invalid number of return values: expected 2, got %d", fn.Type().NumOut())
-	}
-	if !fnType.Out(1).Implements(errType) {
-		return reflect.Value{}, fmt.Errorf("invalid second return value: expected error, got %s", fnType.Out(1))
-	}
+	return callFunc(fn, args)
+}
 
-	// Call the function and unwrap the conventional (value, error) result.
-	rets := fn.Call(args)
-	if len(rets) != 2 {
-		return reflect.Value{}, errors.New("invalid number of return values")
-	}
-	errRet := rets[1]
-	if !errRet.IsNil() {
-		return reflect.Value{}, errRet.Interface().(error)
+// callFunc calls fn with args and unwraps its result. The supported return shapes are:
+//   - no return value, which evaluates to nil
+//   - a single value, or a single error which evaluates to nil when it is nil
+//   - a value and an error
+func callFunc(fn reflect.Value, args []reflect.Value) (reflect.Value, error) {
+	fnType := fn.Type()
+	switch fnType.NumOut() {
+	case 0:
+		fn.Call(args)
+		return nilValue, nil
+	case 1:
+		rets := fn.Call(args)
+		if fnType.Out(0) == errType {
+			if err := rets[0]; !err.IsNil() {
+				return reflect.Value{}, err.Interface().(error)
+			}
+			return nilValue, nil
+		}
+		return rets[0], nil
+	case 2:
+		if !fnType.Out(1).Implements(errType) {
+			return reflect.Value{}, fmt.Errorf("invalid second return value: expected error, got %s", fnType.Out(1))
+		}
+		// Call the function and unwrap the conventional (value, error) result.
+		rets := fn.Call(args)
+		if errRet := rets[1]; !errRet.IsNil() {
+			return reflect.Value{}, errRet.Interface().(error)
+		}
+		return rets[0], nil
+	default:
+		return reflect.Value{}, fmt.Errorf("invalid number of return values: expected at most 2, got %d", fnType.NumOut())
 	}
-	return rets[0], nil
 }
 
 // prepareCallArgs prepares arguments for function call, handling variadic parameters and slice unpacking
@@ -606,7 +632,9 @@ func evalSelectorExpr(exp *ast.SelectorExpr, params Parameter) (reflect.Value, e
 	// try to find method from the type
 	if isExported && x.NumMethod() > 0 {
 		// use x directly, in case x is a pointer
-		result = x.MethodByName(fieldOrTagOrMethodName)
+		if method := x.MethodByName(fieldOrTagOrMethodName); method.IsValid() {
+			result = method
+		}
 	}
 
 	// we failed to find the field
@@ -662,18 +690,7 @@ func evalFunc(fn reflect.Value, exp *ast.BinaryExpr, params Parameter) (reflect.
 		}
 		args = append(args, arg)
 	}
-	out := fn.Call(args)
-	if len(out) != 2 {
-		return reflect.Value{}, fmt.Errorf("evalFunc: invalid number of return values: expected 2, got %d", len(out))
-	}
-	if !out[1].IsNil() {
-		// The second return value must implement error.
-		if ok := out[1].Type().Implements(errType); !ok {
-			return reflect.Value{}, errors.New("evalFunc: cannot convert return value to error")
-		}
-		return reflect.Value{}, out[1].Interface().(error)
-	}
-	return out[0], nil
+	return callFunc(fn, args)
 }
 
 // evalBinaryExpr evaluates a binary expression.
@@ -18,9 +18,16 @@ package eval
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval/expr"
+	"github.com/go-juicedev/juice/internal/container"
 )
 
 // return the length of the string or array
@@ -103,6 +110,46 @@ func slice(v any, start, count int) ([]any, error) {
 	return nil, errors.New("slice: invalid argument type")
 }
 
+// contains reports whether item is within collection.
+// For a string it reports whether item is a substring, for a slice or an array whether
+// it has an element equal to item and for a map whether item is one of its keys.
+func contains(collection, item any) (bool, error) {
+	if text, ok := collection.(string); ok {
+		substr, ok := item.(string)
+		if !ok {
+			return false, errors.New("contains: item must be a string")
+		}
+		return strings.Contains(text, substr), nil
+	}
+	itemValue := reflect.ValueOf(item)
+	equal := func(value reflect.Value) bool {
+		result, err := expr.EQLExprExecutor{}.Exec(
+			func() (reflect.Value, error) { return value, nil },
+			func() (reflect.Value, error) { return itemValue, nil },
+		)
+		return err == nil && result.Kind() == reflect.Bool && result.Bool()
+	}
+	rv := reflect.Indirect(reflect.ValueOf(collection))
+	switch rv.Kind() {
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < rv.Len(); i++ {
+			if equal(rv.Index(i)) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case reflect.Map:
+		for _, key := range rv.MapKeys() {
+			if equal(key) {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return false, errors.New("contains: invalid argument type")
+	}
+}
+
 // lower returns a copy of the string s with all Unicode letters mapped to their lower case.
 func lower(text string) (string, error) {
 	return strings.ToLower(text), nil
@@ -140,6 +187,67 @@ func replaceAll(text, old, new string) (string, error) {
 	return strings.ReplaceAll(text, old, new), nil
 }
 
+// startsWith reports whether the string s begins with prefix.
+func startsWith(text, prefix string) (bool, error) {
+	return strings.HasPrefix(text, prefix), nil
+}
+
+// endsWith reports whether the string s ends with suffix.
+func endsWith(text, suffix string) (bool, error) {
+	return strings.HasSuffix(text, suffix), nil
+}
+
+// trimSpace returns a slice of the string s, with all leading and trailing white space removed.
+func trimSpace(text string) (string, error) {
+	return strings.TrimSpace(text), nil
+}
+
+// escapeLike escapes %, _ and backslash in the string s with backslash for LIKE patterns.
+// The pattern must be followed by an ESCAPE clause declaring backslash as the escape character.
+func escapeLike(text string) (string, error) {
+	return driver.EscapeLike(text), nil
+}
+
+// maxCachedRegexps is the maximum number of compiled regular expressions kept by regexpCache.
+const maxCachedRegexps = 256
+
+// regexpCache caches the compiled regular expressions used by the regex builtins,
+// since the same patterns are evaluated again and again by mapper statements.
+// It is bounded, since patterns built from user input could grow it forever.
+var regexpCache = container.NewLRU[string, *regexp.Regexp](maxCachedRegexps, nil)
+
+// compileRegexp returns the compiled regular expression of pattern, using the cache when possible.
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexpCache.Get(pattern); ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexpCache.Set(pattern, re)
+	return re, nil
+}
+
+// matches reports whether the string s contains any match of the regular expression pattern.
+func matches(text, pattern string) (bool, error) {
+	re, err := compileRegexp(pattern)
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(text), nil
+}
+
+// regexReplace returns a copy of the string s, replacing matches of the regular expression pattern
+// with the replacement string repl. Inside repl, $ signs are interpreted as in regexp.Expand.
+func regexReplace(text, pattern, repl string) (string, error) {
+	re, err := compileRegexp(pattern)
+	if err != nil {
+		return "", err
+	}
+	return re.ReplaceAllString(text, repl), nil
+}
+
 // split returns a slice of strings after splitting the string s at each instance of sep.
 func split(text, sep string) ([]string, error) {
 	return strings.Split(text, sep), nil
@@ -157,20 +265,53 @@ func splitAfter(text, sep string) ([]string, error) {
 	return strings.SplitAfter(text, sep), nil
 }
 
+// dateLayouts are the layouts accepted by the date builtin, in order.
+var dateLayouts = []string{
+	time.DateOnly,
+	time.DateTime,
+	time.RFC3339,
+	time.RFC3339Nano,
+}
+
+// now returns the current local time.
+func now() (time.Time, error) {
+	return time.Now(), nil
+}
+
+// date parses a date like "2024-01-01", "2024-01-01 15:04:05" or a RFC3339 timestamp.
+// Dates without time zone are parsed in the local time zone.
+func date(value string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date: cannot parse %q", value)
+}
+
+// before reports whether the time instant a is before b.
+func before(a, b time.Time) (bool, error) {
+	return a.Before(b), nil
+}
+
+// after reports whether the time instant a is after b.
+func after(a, b time.Time) (bool, error) {
+	return a.After(b), nil
+}
+
+// addDays returns the time t plus n days.
+func addDays(t time.Time, n int) (time.Time, error) {
+	return t.AddDate(0, 0, n), nil
+}
+
 // RegisterEvalFunc registers a function for eval.
-// The function must be a function with one return value.
+// The function may return nothing, a single value, or a value and an error.
 // It is allowed to overwrite an already registered function, including built-in functions.
+// RegisterEvalFunc mutates the global builtins, use FuncRegistry to scope functions to an engine.
 func RegisterEvalFunc(name string, v any) error {
-	rv := reflect.Indirect(reflect.ValueOf(v))
-	if rv.Kind() != reflect.Func {
-		return errors.New("RegisterEvalFunc: v must be a function type")
-	}
-	if rv.Type().NumOut() != 2 {
-		return errors.New("RegisterEvalFunc: v must be a function with two return value")
-	}
-	// if last return value is error
-	if !rv.Type().Out(rv.Type().NumOut() - 1).Implements(errType) {
-		return errors.New("RegisterEvalFunc: v must be a function with an error return value")
+	rv, err := validateEvalFunc(v)
+	if err != nil {
+		return err
 	}
 	setBuiltin(name, rv)
 	return nil
@@ -226,6 +367,7 @@ func init() {
 	MustRegisterEvalFunc("substr", strSub)
 	MustRegisterEvalFunc("join", strJoin)
 	MustRegisterEvalFunc("slice", slice)
+	MustRegisterEvalFunc("contains", contains)
 	MustRegisterEvalFunc("lower", lower)
 	MustRegisterEvalFunc("upper", upper)
 	MustRegisterEvalFunc("trim", trim)
@@ -236,4 +378,15 @@ func init() {
 	MustRegisterEvalFunc("split", split)
 	MustRegisterEvalFunc("splitN", splitN)
 	MustRegisterEvalFunc("splitAfter", splitAfter)
+	MustRegisterEvalFunc("startsWith", startsWith)
+	MustRegisterEvalFunc("endsWith", endsWith)
+	MustRegisterEvalFunc("trimSpace", trimSpace)
+	MustRegisterEvalFunc("escapeLike", escapeLike)
+	MustRegisterEvalFunc("matches", matches)
+	MustRegisterEvalFunc("regexReplace", regexReplace)
+	MustRegisterEvalFunc("now", now)
+	MustRegisterEvalFunc("date", date)
+	MustRegisterEvalFunc("before", before)
+	MustRegisterEvalFunc("after", after)
+	MustRegisterEvalFunc("addDays", addDays)
 }
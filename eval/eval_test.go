@@ -9,6 +9,9 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/go-juicedev/juice/internal/reflectlite"
 )
 
 func testEval(expr string, v any) (result reflect.Value, err error) {
@@ -1822,7 +1825,7 @@ func TestRegisterEvalFuncErrors_eval_coverage_test(t *testing.T) {
 	}
 
 	// Wrong number of returns
-	err = RegisterEvalFunc("bad", func() int { return 0 })
+	err = RegisterEvalFunc("bad", func() (int, int, error) { return 0, 0, nil })
 	if err == nil {
 		t.Fatal("expected error for wrong return count")
 	}
@@ -2294,3 +2297,500 @@ func TestStructParameterMissingField_eval_coverage_test(t *testing.T) {
 		t.Fatal("expected false for missing tag")
 	}
 }
+
+func TestConditionalOperator_eval_test(t *testing.T) {
+	status := "PAUSED"
+	params := H{
+		"status": &status,
+		"empty":  (*string)(nil),
+		"age":    20,
+		"name":   "",
+		"ids":    []int{1, 2, 3},
+	}
+	tests := []struct {
+		expr string
+		want any
+	}{
+		{`status != nil ? status : "ACTIVE"`, "PAUSED"},
+		{`empty != nil ? empty : "ACTIVE"`, "ACTIVE"},
+		{`age >= 18 ? "adult" : "minor"`, "adult"},
+		{`age > 60 ? "senior" : age >= 18 ? "adult" : "minor"`, "adult"},
+		{`(age > 18 ? 1 : 2) + 1`, int64(2)},
+		{`age > 18 and name == "" ? "anonymous" : name`, "anonymous"},
+		{`len(age > 18 ? ids : ids[:1])`, 3},
+		{`ids[age > 18 ? 0 : 1]`, 1},
+		{`ids[(age > 18 ? 1 : 0):]`, []int{2, 3}},
+		{`name ?: "unknown"`, "unknown"},
+		{`status ?: "unknown"`, "PAUSED"},
+		{`empty ?: name ?: "unknown"`, "unknown"},
+		{`iif(age < 18, "minor", "adult")`, "adult"},
+		{`elvis(age, 0)`, 20},
+		// the branch not selected is not evaluated.
+		{`true ? "ok" : undefined`, "ok"},
+		{`"set" ?: undefined`, "set"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			result, err := Eval(tt.expr, params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			result = reflectlite.Unwrap(result)
+			if !reflect.DeepEqual(result.Interface(), tt.want) {
+				t.Errorf("got %#v, want %#v", result.Interface(), tt.want)
+			}
+		})
+	}
+
+	for _, expr := range []string{`1 ? 2 : 3`, `true ? 1`, `iif(true, 1)`, `elvis(1)`} {
+		if _, err := Eval(expr, nil); err == nil {
+			t.Errorf("expected error for %q", expr)
+		}
+	}
+}
+
+type callShapeUser struct {
+	First string
+	Last  string
+}
+
+func (u callShapeUser) FullName() string { return u.First + " " + u.Last }
+
+func (u *callShapeUser) Validate() error {
+	if u.First == "" {
+		return errors.New("first name required")
+	}
+	return nil
+}
+
+func (u *callShapeUser) Touch() {}
+
+func TestCallReturnShapes_eval_test(t *testing.T) {
+	user := &callShapeUser{First: "John", Last: "Doe"}
+	params := H{
+		"user":  user,
+		"upper": strings.ToUpper,
+		"noop":  func() {},
+		"many":  func() (int, int, error) { return 1, 2, nil },
+	}
+
+	result, err := Eval(`upper(user.First) == "JOHN"`, params)
+	if err != nil || !result.Bool() {
+		t.Fatalf("expected single return function call, got %v %v", result, err)
+	}
+
+	result, err = Eval(`user.FullName() == "John Doe"`, params)
+	if err != nil || !result.Bool() {
+		t.Fatalf("expected single return method call, got %v %v", result, err)
+	}
+
+	result, err = Eval(`user.Validate() == nil`, params)
+	if err != nil || !result.Bool() {
+		t.Fatalf("expected nil error result, got %v %v", result, err)
+	}
+
+	result, err = Eval(`noop() == nil`, params)
+	if err != nil || !result.Bool() {
+		t.Fatalf("expected zero return function to be nil, got %v %v", result, err)
+	}
+
+	result, err = Eval(`user.Touch() == nil`, params)
+	if err != nil || !result.Bool() {
+		t.Fatalf("expected zero return method to be nil, got %v %v", result, err)
+	}
+
+	if _, err = Eval(`user.Validate()`, H{"user": &callShapeUser{}}); err == nil || err.Error() != "first name required" {
+		t.Fatalf("expected returned error, got %v", err)
+	}
+
+	if _, err = Eval(`many()`, params); err == nil || !strings.Contains(err.Error(), "invalid number of return values") {
+		t.Fatalf("expected invalid number of return values, got %v", err)
+	}
+}
+
+func TestCompositeLit_eval_test(t *testing.T) {
+	params := H{"status": "B", "id": 2, "code": int8(3)}
+	tests := []struct {
+		expr string
+		want any
+	}{
+		{`[]string{"A", "B"}`, []string{"A", "B"}},
+		{`[]int{1, id, 3}`, []int{1, 2, 3}},
+		{`[2]int{1, 2}`, [2]int{1, 2}},
+		{`[]any{1, "a", nil}`, []any{int64(1), "a", nil}},
+		{`[]interface{}{true}`, []any{true}},
+		{`[][]int{{1}, {2, 3}}`, [][]int{{1}, {2, 3}}},
+		{`map[string]int{"a": 1, status: id}`, map[string]int{"a": 1, "B": 2}},
+		{`map[int][]string{1: {"x"}}`, map[int][]string{1: {"x"}}},
+		{`len([]int{1, 2, 3})`, 3},
+		{`[]string{"A", "B"}[1]`, "B"},
+		{`contains([]string{"A", "B"}, status)`, true},
+		{`contains([]int{1, 3}, id)`, false},
+		{`contains([]int{1, 3}, code)`, true},
+		{`contains(map[string]int{"B": 1}, status)`, true},
+		{`contains("ABC", status)`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			result, err := Eval(tt.expr, params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result.Interface(), tt.want) {
+				t.Errorf("got %#v, want %#v", result.Interface(), tt.want)
+			}
+		})
+	}
+
+	for _, expr := range []string{
+		`[]string{1}`,
+		`[]int{"a"}`,
+		`[]int{nil}`,
+		`[1]int{1, 2}`,
+		`[]int{0: 1}`,
+		`map[string]int{1}`,
+		`[]chan int{}`,
+		`[]User{}`,
+		`map[[]int]int{}`,
+		`contains(1, 2)`,
+	} {
+		if _, err := Eval(expr, params); err == nil {
+			t.Errorf("expected error for %q", expr)
+		}
+	}
+}
+
+func TestStringAndRegexBuiltins_eval_test(t *testing.T) {
+	params := H{"phone": "13800138000", "name": "  Alice  ", "file": "report.csv"}
+	tests := []struct {
+		expr string
+		want any
+	}{
+		{`matches(phone, "^1[0-9]{10}$")`, true},
+		{`matches("12345", "^1[0-9]{10}$")`, false},
+		{`regexReplace(phone, "^([0-9]{3})[0-9]{4}([0-9]{4})$", "$1****$2")`, "138****8000"},
+		{`startsWith(file, "report")`, true},
+		{`endsWith(file, ".csv")`, true},
+		{`endsWith(file, ".xls")`, false},
+		{`trimSpace(name)`, "Alice"},
+		{`upper(trimSpace(name))`, "ALICE"},
+		{`escapeLike("100%_off")`, `100\%\_off`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			result, err := Eval(tt.expr, params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result.Interface(), tt.want) {
+				t.Errorf("got %#v, want %#v", result.Interface(), tt.want)
+			}
+		})
+	}
+
+	if _, err := Eval(`matches(phone, "[")`, params); err == nil {
+		t.Error("expected invalid pattern error")
+	}
+	if _, err := Eval(`regexReplace(phone, "(", "")`, params); err == nil {
+		t.Error("expected invalid pattern error")
+	}
+
+	first, err := compileRegexp("^a+$")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, _ := compileRegexp("^a+$")
+	if first != second {
+		t.Error("expected compiled pattern to be cached")
+	}
+	for i := range maxCachedRegexps + 1 {
+		if _, err = compileRegexp(fmt.Sprintf("^%d$", i)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// keep the first pattern hot, it must survive the evictions.
+		if _, err = compileRegexp("^a+$"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if size := regexpCache.Len(); size > maxCachedRegexps {
+		t.Errorf("expected cache to be bounded, got %d items", size)
+	}
+	if third, _ := compileRegexp("^a+$"); third != first {
+		t.Error("expected recently used pattern to stay cached")
+	}
+	if _, ok := regexpCache.Get("^0$"); ok {
+		t.Error("expected least recently used pattern to be evicted")
+	}
+}
+
+func TestDateTimeBuiltins_eval_test(t *testing.T) {
+	createdAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.Local)
+	params := H{
+		"createdAt": createdAt,
+		"deletedAt": &createdAt,
+		"days":      7,
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`before(createdAt, date("2024-02-01"))`, true},
+		{`after(createdAt, date("2024-02-01"))`, false},
+		{`after(deletedAt, date("2024-01-15 09:59:59"))`, true},
+		{`before(date("2024-01-01T00:00:00Z"), now())`, true},
+		{`after(addDays(createdAt, days), date("2024-01-22"))`, true},
+		{`before(addDays(createdAt, -15), date("2024-01-01"))`, true},
+		{`deletedAt != nil and before(deletedAt, now())`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			result, err := Eval(tt.expr, params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Bool() != tt.want {
+				t.Errorf("got %v, want %v", result.Bool(), tt.want)
+			}
+		})
+	}
+
+	result, err := Eval(`addDays(date("2024-02-28"), 2)`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Interface().(time.Time).Format(time.DateOnly); got != "2024-03-01" {
+		t.Errorf("unexpected addDays result: %s", got)
+	}
+
+	if _, err = Eval(`date("01/02/2024")`, nil); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestFuncRegistry_eval_test(t *testing.T) {
+	registry := NewFuncRegistry()
+	if err := registry.Register("mask", func(s string) (string, error) {
+		return strings.Repeat("*", len(s)), nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := registry.Register("mask", func(s string) (string, error) { return s, nil }); !errors.Is(err, ErrFuncConflict) {
+		t.Fatalf("expected ErrFuncConflict for duplicate, got %v", err)
+	}
+	if err := registry.Register("len", func(s string) (int, error) { return 0, nil }); !errors.Is(err, ErrFuncConflict) {
+		t.Fatalf("expected ErrFuncConflict for builtin, got %v", err)
+	}
+	if err := registry.Register("iif", func(s string) (int, error) { return 0, nil }); !errors.Is(err, ErrFuncConflict) {
+		t.Fatalf("expected ErrFuncConflict for lazy builtin, got %v", err)
+	}
+	if err := registry.Register("bad", 1); err == nil {
+		t.Fatal("expected invalid function error")
+	}
+	if registry.Len() != 1 {
+		t.Fatalf("expected 1 function, got %d", registry.Len())
+	}
+
+	result, err := Eval(`mask(name)`, ParamGroup{H{"name": "secret"}, registry})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String() != "******" {
+		t.Fatalf("unexpected result: %q", result.String())
+	}
+
+	if _, ok := getBuiltin("mask"); ok {
+		t.Fatal("expected scoped function not to be registered globally")
+	}
+	if _, err = Eval(`mask(name)`, H{"name": "secret"}); err == nil {
+		t.Fatal("expected undefined function without registry")
+	}
+}
+
+func TestFuncRegistrySingleReturn_eval_test(t *testing.T) {
+	registry := NewFuncRegistry()
+	if err := registry.Register("shout", strings.ToUpper); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var called bool
+	if err := registry.Register("touch", func() { called = true }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := registry.Register("bad", func() (int, int) { return 0, 0 }); err == nil {
+		t.Fatal("expected error for non-error second return value")
+	}
+
+	result, err := Eval(`shout(name) == "SECRET"`, ParamGroup{H{"name": "secret"}, registry})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Bool() {
+		t.Fatal("expected single return function to be called")
+	}
+	if _, err = Eval(`touch()`, registry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Fatal("expected zero return function to be called")
+	}
+}
+
+func TestRegisterEvalFuncSingleReturn_eval_test(t *testing.T) {
+	if err := RegisterEvalFunc("single_return_lower", strings.ToLower); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := testEval(`single_return_lower("HI")`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.String() != "hi" {
+		t.Fatalf("expected 'hi', got %v", result.String())
+	}
+}
+
+type countingCompiler struct {
+	calls int
+}
+
+func (c *countingCompiler) Compile(expr string) (Expression, error) {
+	c.calls++
+	return (&goExprCompiler{}).Compile(expr)
+}
+
+func TestCachedCompiler_eval_test(t *testing.T) {
+	counter := &countingCompiler{}
+	compiler := NewCachedCompiler(counter, 2)
+
+	first, err := compiler.Compile("id != nil")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := compiler.Compile("id != nil")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first != second || counter.calls != 1 {
+		t.Fatalf("expected cached expression, compiled %d times", counter.calls)
+	}
+
+	// errors are not cached.
+	for range 2 {
+		if _, err = compiler.Compile("id !="); err == nil {
+			t.Fatal("expected syntax error")
+		}
+	}
+	if counter.calls != 3 {
+		t.Fatalf("expected failed expressions to be recompiled, compiled %d times", counter.calls)
+	}
+
+	_, _ = compiler.Compile("a > 1")
+	_, _ = compiler.Compile("b > 1")
+
+	stats := compiler.Stats()
+	want := CompileCacheStats{Hits: 1, Misses: 5, Evictions: 1, Size: 2, Capacity: 2}
+	if stats != want {
+		t.Fatalf("expected %+v, got %+v", want, stats)
+	}
+
+	compiler.Reset()
+	if stats = compiler.Stats(); stats.Hits != 0 || stats.Misses != 0 || stats.Size != 0 {
+		t.Fatalf("expected empty stats after reset, got %+v", stats)
+	}
+}
+
+func TestCompileStats_eval_test(t *testing.T) {
+	original := defaultCompiler
+	defer func() { defaultCompiler = original }()
+
+	WithCompiler(NewCachedCompiler(&goExprCompiler{}, 8))
+	for range 3 {
+		if _, err := Eval("1 + 2", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	stats, ok := CompileStats()
+	if !ok || stats.Hits != 2 || stats.Misses != 1 {
+		t.Fatalf("unexpected stats: %+v %v", stats, ok)
+	}
+
+	WithCompiler(&goExprCompiler{})
+	if _, ok = CompileStats(); ok {
+		t.Fatal("expected no stats for an uncached compiler")
+	}
+}
+
+func TestGenericParameterEmbeddedTags_eval_test(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `param:"created_by"`
+	}
+	type Base struct {
+		*Audit
+		ID int64 `param:"id"`
+	}
+	type User struct {
+		Base
+		Name string `param:"name"`
+	}
+
+	user := User{Base: Base{Audit: &Audit{CreatedBy: "admin"}, ID: 7}, Name: "eat"}
+	param := NewGenericParam(&user, "")
+	for name, want := range map[string]any{"id": int64(7), "created_by": "admin", "name": "eat", "CreatedBy": "admin", "ID": int64(7)} {
+		value, ok := param.Get(name)
+		if !ok || value.Interface() != want {
+			t.Errorf("%s: expected %v, got %v (ok: %v)", name, want, value, ok)
+		}
+	}
+
+	result, err := Eval(`created_by == "admin" && id > 0`, param)
+	if err != nil || !result.Bool() {
+		t.Fatalf("expected promoted fields in expressions, got %v err=%v", result, err)
+	}
+
+	// fields promoted from a nil embedded pointer are not accessible.
+	param = NewGenericParam(User{Name: "eat"}, "")
+	for _, name := range []string{"created_by", "CreatedBy"} {
+		if _, ok := param.Get(name); ok {
+			t.Errorf("%s: expected not found through nil embedded pointer", name)
+		}
+	}
+	if value, ok := param.Get("id"); !ok || value.Int() != 0 {
+		t.Errorf("id: expected 0, got %v (ok: %v)", value, ok)
+	}
+}
+
+func TestStructParameterEncryptedField_eval_coverage_test(t *testing.T) {
+	type user struct {
+		Name string  `param:"name"`
+		SSN  string  `param:"ssn" column:"ssn,encrypted"`
+		Card *string `param:"card" column:"card,encrypted"`
+	}
+	param := NewGenericParam([]user{{Name: "alice", SSN: "123-45-6789"}}, "")
+
+	// encrypted fields are only encrypted when they are bound, expressions see the plain value.
+	if value, ok := param.Get("0.ssn"); !ok || value.Interface() != "123-45-6789" {
+		t.Fatalf("ssn: expected the plain value, got %v (ok: %v)", value, ok)
+	}
+	named := NewGenericParam(user{SSN: "123-45-6789"}, "")
+	for _, expr := range []string{`ssn != ""`, `len(ssn) > 0`} {
+		result, err := Eval(expr, named)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", expr, err)
+		}
+		if !result.Bool() {
+			t.Fatalf("%s: expected true", expr)
+		}
+	}
+
+	for range 2 {
+		field, ok := StructField(param, "0.ssn")
+		if !ok || field.Tag.Get("column") != "ssn,encrypted" {
+			t.Fatalf("ssn: expected the tagged struct field, got %v (ok: %v)", field, ok)
+		}
+		param.(*GenericParameter).Clear()
+	}
+	if field, ok := StructField(ParamGroup{H{"other": 1}, named}, "card"); !ok || field.Name != "Card" {
+		t.Fatalf("card: expected the struct field through a group, got %v (ok: %v)", field, ok)
+	}
+	if _, ok := StructField(H{"ssn": "123"}, "ssn"); ok {
+		t.Fatal("expected no struct field for a map parameter")
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eval
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrFuncConflict is returned when a function is registered with the name
+// of a builtin or of a function already registered in the same registry.
+var ErrFuncConflict = errors.New("function name conflict")
+
+// validateEvalFunc returns the function value of v if it can be registered as an eval function.
+func validateEvalFunc(v any) (reflect.Value, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Func {
+		return reflect.Value{}, errors.New("RegisterEvalFunc: v must be a function type")
+	}
+	switch rv.Type().NumOut() {
+	case 0, 1:
+	case 2:
+		// the second return value must be an error
+		if !rv.Type().Out(1).Implements(errType) {
+			return reflect.Value{}, errors.New("RegisterEvalFunc: v must be a function with an error as the second return value")
+		}
+	default:
+		return reflect.Value{}, errors.New("RegisterEvalFunc: v must be a function with at most two return values")
+	}
+	return rv, nil
+}
+
+// FuncRegistry holds eval functions scoped to its owner, such as an engine or a configuration,
+// instead of the global builtins. It implements Parameter, so that its functions can be
+// resolved by name while evaluating expressions.
+//
+// Builtins are always resolved first, so a function can not be registered with the name of a builtin.
+type FuncRegistry struct {
+	mu    sync.RWMutex
+	funcs map[string]reflect.Value
+}
+
+// Register registers the function v with the given name.
+// The function must follow the same rules as RegisterEvalFunc.
+// It returns ErrFuncConflict if the name is used by a builtin or already registered.
+func (r *FuncRegistry) Register(name string, v any) error {
+	if _, ok := getBuiltin(name); ok {
+		return fmt.Errorf("%w: %s is a builtin function", ErrFuncConflict, name)
+	}
+	if _, ok := getLazyBuiltin(name); ok {
+		return fmt.Errorf("%w: %s is a builtin function", ErrFuncConflict, name)
+	}
+	rv, err := validateEvalFunc(v)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.funcs[name]; ok {
+		return fmt.Errorf("%w: %s is already registered", ErrFuncConflict, name)
+	}
+	if r.funcs == nil {
+		r.funcs = make(map[string]reflect.Value)
+	}
+	r.funcs[name] = rv
+	return nil
+}
+
+// Get implements Parameter.
+func (r *FuncRegistry) Get(name string) (reflect.Value, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fn, ok := r.funcs[name]
+	return fn, ok
+}
+
+// Len returns the number of registered functions.
+func (r *FuncRegistry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.funcs)
+}
+
+// NewFuncRegistry returns an empty FuncRegistry.
+func NewFuncRegistry() *FuncRegistry {
+	return &FuncRegistry{funcs: make(map[string]reflect.Value)}
+}
+
+var _ Parameter = (*FuncRegistry)(nil)
@@ -21,6 +21,7 @@ package eval
 import (
 	"go/scanner"
 	"go/token"
+	"slices"
 	"strings"
 )
 
@@ -53,7 +54,8 @@ type Lexer struct {
 
 // Tokenize processes the input and returns a string with converted operators.
 // It scans through all tokens, replacing logical operators while preserving
-// other tokens and maintaining proper spacing.
+// other tokens and maintaining proper spacing. The conditional operators
+// `cond ? a : b` and `a ?: b` are rewritten to iif(cond, a, b) and elvis(a, b).
 func (l *Lexer) Tokenize() string {
 	var tokens []string
 	for {
@@ -75,6 +77,15 @@ func (l *Lexer) Tokenize() string {
 		}
 	}
 
+	if slices.Contains(tokens, "?") {
+		// drop the automatically inserted semicolons before rewriting,
+		// they would end up inside the generated call otherwise.
+		for len(tokens) > 0 && (tokens[len(tokens)-1] == "\n" || tokens[len(tokens)-1] == ";") {
+			tokens = tokens[:len(tokens)-1]
+		}
+		tokens = rewriteConditional(tokens)
+	}
+
 	return strings.Join(tokens, " ")
 }
 
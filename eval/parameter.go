@@ -77,6 +77,22 @@ func (NoOPParameter) Get(_ string) (reflect.Value, bool) {
 
 var noOPParameter Parameter = NoOPParameter{}
 
+// structFieldParameter is implemented by the parameters resolving names to struct fields.
+type structFieldParameter interface {
+	// StructField returns the struct field the named parameter resolves to.
+	StructField(name string) (reflect.StructField, bool)
+}
+
+// StructField returns the struct field the named parameter of p resolves to,
+// so that the tags of the field can be honored when its value is bound.
+// It returns false if the parameter is not a field of a struct.
+func StructField(p Parameter, name string) (reflect.StructField, bool) {
+	if p, ok := p.(structFieldParameter); ok {
+		return p.StructField(name)
+	}
+	return reflect.StructField{}, false
+}
+
 // make sure that ParamGroup implements Parameter.
 var _ Parameter = (ParamGroup)(nil)
 
@@ -96,6 +112,19 @@ func (g ParamGroup) Get(name string) (reflect.Value, bool) {
 	return reflect.Value{}, false
 }
 
+// StructField implements structFieldParameter with the parameter resolving name.
+func (g ParamGroup) StructField(name string) (reflect.StructField, bool) {
+	for _, p := range g {
+		if p == nil {
+			continue
+		}
+		if _, ok := p.Get(name); ok {
+			return StructField(p, name)
+		}
+	}
+	return reflect.StructField{}, false
+}
+
 // make sure that structParameter implements Parameter.
 var _ Parameter = (*structParameter)(nil)
 
@@ -107,12 +136,32 @@ type structParameter struct {
 
 // Get implements Parameter.
 func (p *structParameter) Get(name string) (reflect.Value, bool) {
-	if len(name) == 0 {
+	indexes, ok := p.indexes(name)
+	if !ok {
 		return reflect.Value{}, false
 	}
+	// fields promoted from a nil embedded pointer are not accessible.
+	value, ok := reflectlite.FieldByIndex(p.Value, indexes)
+	return value, ok && value.IsValid()
+}
+
+// StructField implements structFieldParameter.
+func (p *structParameter) StructField(name string) (reflect.StructField, bool) {
+	indexes, ok := p.indexes(name)
+	if !ok {
+		return reflect.StructField{}, false
+	}
+	return p.Type().FieldByIndex(indexes), true
+}
+
+// indexes returns the field indexes of name, found by tag or by field name.
+func (p *structParameter) indexes(name string) ([]int, bool) {
+	if len(name) == 0 {
+		return nil, false
+	}
 	// Check type cache first
 	if indexes, ok := p.fieldIndexes[name]; ok {
-		return p.FieldByIndex(indexes), true
+		return indexes, true
 	}
 
 	// if isPublic it means that the name is exported
@@ -120,25 +169,23 @@ func (p *structParameter) Get(name string) (reflect.Value, bool) {
 	var indexes []int
 	if !isPublic {
 		var ok bool
-		// try to find the field by tag
+		// try to find the field by tag, including the fields promoted from embedded structs
 		indexes, ok = reflectlite.TypeFrom(p.Value.Type()).GetFieldIndexesFromTag(defaultParamKey, name)
 		if !ok {
-			return reflect.Value{}, false
+			return nil, false
 		}
 	} else {
 		// Find field index by name
 		field, ok := p.Type().FieldByName(name)
 		if !ok {
-			return reflect.Value{}, false
+			return nil, false
 		}
 		indexes = field.Index
 	}
 
 	// Cache the field index for future use
 	p.fieldIndexes[name] = indexes
-
-	value := p.FieldByIndex(indexes)
-	return value, value.IsValid()
+	return indexes, true
 }
 
 // make sure that mapParameter implements Parameter.
@@ -223,36 +270,7 @@ func (g *GenericParameter) get(name string) (reflect.Value, bool) {
 			}
 			param = mapParameter{Value: value}
 		case reflect.Struct:
-			// Initialize the three-level cache if not exists:
-			// Level 1: path position -> to handle different levels in the path (e.g., user.address.street)
-			// Level 2: concrete type -> to handle different struct types at the same position
-			// Level 3: field name -> to cache the actual field indexes
-			if g.structFieldIndex == nil {
-				g.structFieldIndex = make(map[int]map[reflect.Type]map[string][]int)
-			}
-
-			// Cache the type to avoid multiple calls to Type()
-			valueType := value.Type()
-
-			// Get or create the type-level cache for current path position
-			structFieldIndex, in := g.structFieldIndex[i]
-			if !in {
-				// Initialize with the current type to avoid another map lookup
-				structFieldIndex = map[reflect.Type]map[string][]int{
-					valueType: {},
-				}
-				g.structFieldIndex[i] = structFieldIndex
-			}
-			fieldIndexes := structFieldIndex[valueType]
-			if fieldIndexes == nil {
-				fieldIndexes = make(map[string][]int)
-				structFieldIndex[valueType] = fieldIndexes
-			}
-
-			// Create a new structParameter with its field cache pointing to
-			// the cached indexes for its specific type, ensuring different
-			// struct types don't share the same field index cache
-			param = &structParameter{Value: value, fieldIndexes: fieldIndexes}
+			param = g.structParameter(i, value)
 		case reflect.Slice, reflect.Array:
 			param = sliceParameter{Value: value}
 		default:
@@ -276,6 +294,59 @@ func (g *GenericParameter) get(name string) (reflect.Value, bool) {
 	return value, true
 }
 
+// structParameter wraps value, the struct at the position i of a path, into a structParameter.
+func (g *GenericParameter) structParameter(i int, value reflect.Value) *structParameter {
+	// Initialize the three-level cache if not exists:
+	// Level 1: path position -> to handle different levels in the path (e.g., user.address.street)
+	// Level 2: concrete type -> to handle different struct types at the same position
+	// Level 3: field name -> to cache the actual field indexes
+	if g.structFieldIndex == nil {
+		g.structFieldIndex = make(map[int]map[reflect.Type]map[string][]int)
+	}
+
+	// Cache the type to avoid multiple calls to Type()
+	valueType := value.Type()
+
+	// Get or create the type-level cache for current path position
+	structFieldIndex, in := g.structFieldIndex[i]
+	if !in {
+		// Initialize with the current type to avoid another map lookup
+		structFieldIndex = map[reflect.Type]map[string][]int{
+			valueType: {},
+		}
+		g.structFieldIndex[i] = structFieldIndex
+	}
+	fieldIndexes := structFieldIndex[valueType]
+	if fieldIndexes == nil {
+		fieldIndexes = make(map[string][]int)
+		structFieldIndex[valueType] = fieldIndexes
+	}
+
+	// Create a new structParameter with its field cache pointing to
+	// the cached indexes for its specific type, ensuring different
+	// struct types don't share the same field index cache
+	return &structParameter{Value: value, fieldIndexes: fieldIndexes}
+}
+
+// StructField implements structFieldParameter.
+// It resolves the parent of the last element of the path, which must be a struct.
+func (g *GenericParameter) StructField(name string) (reflect.StructField, bool) {
+	value, field := g.Value, name
+	if index := strings.LastIndexByte(name, '.'); index >= 0 {
+		field = name[index+1:]
+		var ok bool
+		if value, ok = g.Get(name[:index]); !ok {
+			return reflect.StructField{}, false
+		}
+	}
+	value = reflectlite.Unwrap(value)
+	if value.Kind() != reflect.Struct {
+		return reflect.StructField{}, false
+	}
+	// the position of the field in the path is the number of dots before it.
+	return g.structParameter(strings.Count(name, "."), value).StructField(field)
+}
+
 // Get implements Parameter and caches resolved parameter paths.
 func (g *GenericParameter) Get(name string) (value reflect.Value, exists bool) {
 	// Try the path cache first.
@@ -372,6 +443,18 @@ func (p *prefixPatternParameter) Get(name string) (value reflect.Value, exists b
 	return p.parameter.Get(name[dotIdx+1:])
 }
 
+// StructField implements structFieldParameter.
+func (p *prefixPatternParameter) StructField(name string) (reflect.StructField, bool) {
+	rest, ok := strings.CutPrefix(name, p.prefix+".")
+	if !ok {
+		return reflect.StructField{}, false
+	}
+	if p.parameter == nil {
+		p.parameter = NewGenericParam(p.param, "")
+	}
+	return StructField(p.parameter, rest)
+}
+
 // PrefixPatternParameter is a parameter that supports prefix pattern.
 // For example, if the prefix is "user", then it can get the value of "user.name" from the wrapped parameter.
 func PrefixPatternParameter(prefix string, param Param) Parameter {
@@ -408,6 +491,22 @@ func (p *ForeachParameter) Get(name string) (reflect.Value, bool) {
 	return p.Parent.Get(name)
 }
 
+// StructField implements structFieldParameter.
+func (p *ForeachParameter) StructField(name string) (reflect.StructField, bool) {
+	if name == p.Item || p.Index != "" && name == p.Index {
+		return reflect.StructField{}, false
+	}
+	if p.Item != "" && strings.HasPrefix(name, p.Item+".") {
+		p.itemParam.Value = p.ItemValue
+		return p.itemParam.StructField(name[len(p.Item)+1:])
+	}
+	if p.Index != "" && strings.HasPrefix(name, p.Index+".") {
+		p.indexParam.Value = p.IndexValue
+		return p.indexParam.StructField(name[len(p.Index)+1:])
+	}
+	return StructField(p.Parent, name)
+}
+
 func (p *ForeachParameter) Clear() {
 	p.itemParam.Clear()
 	p.indexParam.Clear()
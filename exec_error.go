@@ -0,0 +1,125 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/sql"
+)
+
+// ExecError is the error of a statement which failed in the database.
+// The errors returned by the driver are wrapped into an ExecError before
+// they reach the middlewares, so that they can be classified with errors.As:
+//
+//	var execErr *juice.ExecError
+//	if errors.As(err, &execErr) {
+//		log.Printf("%s failed on %s: %s", execErr.Statement, execErr.Driver, execErr.SQL)
+//	}
+type ExecError struct {
+	// Statement is the name of the failed statement.
+	Statement string
+
+	// Driver is the name of the driver of the database.
+	Driver string
+
+	// SQL is the executed query, whose string literals are redacted.
+	SQL string
+
+	// ArgCount is the number of arguments of the query, which are not kept.
+	ArgCount int
+
+	// Err is the error returned by the driver, wrapped into the portable error it is, if any,
+	// like ErrDuplicateKey, see driver.TranslateError.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ExecError) Error() string {
+	return fmt.Sprintf("juice: statement %s failed on %s: %v", e.Statement, e.Driver, e.Err)
+}
+
+// Unwrap returns the error returned by the driver, see Err.
+func (e *ExecError) Unwrap() error {
+	return e.Err
+}
+
+// newExecError wraps err, returned by the driver executing query, into an ExecError.
+// Errors already wrapped are returned as they are.
+func newExecError(statement Statement, drv driver.Driver, query string, args []any, err error) error {
+	if err == nil {
+		return nil
+	}
+	var wrapped *ExecError
+	if errors.As(err, &wrapped) {
+		return err
+	}
+	execErr := &ExecError{SQL: redactQuery(query), ArgCount: len(args), Err: err}
+	if statement != nil {
+		execErr.Statement = statement.Name()
+	}
+	if drv != nil {
+		execErr.Driver = drv.Name()
+		execErr.Err = driver.TranslateError(drv, err)
+	}
+	return execErr
+}
+
+// withExecError wraps the errors of next into ExecError.
+func withExecError(statement Statement, drv driver.Driver, next QueryHandler) QueryHandler {
+	return func(ctx context.Context, query string, args ...any) (sql.Rows, error) {
+		rows, err := next(ctx, query, args...)
+		return rows, newExecError(statement, drv, query, args, err)
+	}
+}
+
+// withExecErrorExec wraps the errors of next into ExecError.
+func withExecErrorExec(statement Statement, drv driver.Driver, next ExecHandler) ExecHandler {
+	return func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+		result, err := next(ctx, query, args...)
+		return result, newExecError(statement, drv, query, args, err)
+	}
+}
+
+// redactQuery replaces the string literals of query by '?',
+// since they may hold the values substituted into the query.
+func redactQuery(query string) string {
+	if !strings.Contains(query, "'") {
+		return query
+	}
+	var builder strings.Builder
+	builder.Grow(len(query))
+	for i := 0; i < len(query); {
+		switch next := skipQuoted(query, i); {
+		case next == i:
+			builder.WriteByte(query[i])
+			i++
+		case query[i] == '\'':
+			builder.WriteString("'?'")
+			i = next
+		default:
+			// quoted identifiers are kept.
+			builder.WriteString(query[i:next])
+			i = next
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,81 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+type execErrorObserver struct {
+	seen error
+}
+
+func (o *execErrorObserver) QueryContext(_ *StatementContext, next QueryHandler) QueryHandler {
+	return func(ctx context.Context, query string, args ...any) (jsql.Rows, error) {
+		rows, err := next(ctx, query, args...)
+		o.seen = err
+		return rows, err
+	}
+}
+
+func (o *execErrorObserver) ExecContext(_ *StatementContext, next ExecHandler) ExecHandler {
+	return func(ctx context.Context, query string, args ...any) (jsql.Result, error) {
+		result, err := next(ctx, query, args...)
+		o.seen = err
+		return result, err
+	}
+}
+
+func TestExecError_exec_error_test(t *testing.T) {
+	driverErr := errors.New("driver: syntax error")
+	observer := &execErrorObserver{}
+	engine := newStatementTestEngine(nil, observer)
+	handler := newExecuteStatementHandler("SELECT id FROM user WHERE name = 'alice' AND id = ?", []any{1}, engine, nil).withQueryHandler(
+		func(context.Context, string, ...any) (jsql.Rows, error) { return nil, driverErr },
+	).withExecHandler(
+		func(context.Context, string, ...any) (jsql.Result, error) { return nil, driverErr },
+	)
+
+	_, queryErr := handler.QueryContext(context.Background(), shStatement{name: "user.Find"}, nil)
+	_, execErr := handler.ExecContext(context.Background(), shStatement{name: "user.Find"}, nil)
+	for _, err := range []error{queryErr, execErr} {
+		var failure *ExecError
+		if !errors.As(err, &failure) || !errors.Is(err, driverErr) {
+			t.Fatalf("expected an ExecError wrapping the driver error, got %v", err)
+		}
+		if failure.Statement != "user.Find" || failure.Driver != "sqlite3" || failure.ArgCount != 1 ||
+			failure.SQL != "SELECT id FROM user WHERE name = '?' AND id = ?" {
+			t.Fatalf("unexpected ExecError: %+v", failure)
+		}
+		if !errors.As(observer.seen, &failure) {
+			t.Fatalf("expected the middleware to see the ExecError, got %v", observer.seen)
+		}
+	}
+}
+
+func TestExecErrorTranslation_exec_error_test(t *testing.T) {
+	driverErr := errors.New("UNIQUE constraint failed: user.email")
+	engine := newStatementTestEngine(nil)
+	handler := newExecuteStatementHandler("INSERT INTO user (email) VALUES (?)", []any{"a@b.c"}, engine, nil).withExecHandler(
+		func(context.Context, string, ...any) (jsql.Result, error) { return nil, driverErr },
+	)
+	_, err := handler.ExecContext(context.Background(), shStatement{name: "user.Create"}, nil)
+	if !errors.Is(err, ErrDuplicateKey) || !errors.Is(err, driverErr) || errors.Is(err, ErrForeignKeyViolation) {
+		t.Fatalf("expected ErrDuplicateKey wrapping the driver error, got %v", err)
+	}
+}
+
+func TestRedactQuery_exec_error_test(t *testing.T) {
+	tests := map[string]string{
+		"SELECT 1": "SELECT 1",
+		"SELECT * FROM t WHERE a = 'x''y' AND b = 'z'": "SELECT * FROM t WHERE a = '?' AND b = '?'",
+		`SELECT "na'me" FROM t WHERE a = 'it\'s'`:      `SELECT "na'me" FROM t WHERE a = '?'`,
+	}
+	for query, want := range tests {
+		if got := redactQuery(query); got != want {
+			t.Errorf("redactQuery(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
@@ -19,6 +19,7 @@ package juice
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/go-juicedev/juice/driver"
 	"github.com/go-juicedev/juice/eval"
@@ -41,6 +42,10 @@ type Executor[T any] interface {
 
 	// Driver returns the driver of the current Executor.
 	Driver() driver.Driver
+
+	// DryRun builds the statement and runs it through the middlewares without executing it,
+	// and returns the query, its arguments and the datasource it would be executed on.
+	DryRun(ctx context.Context, param eval.Param) (*DryRunResult, error)
 }
 
 // invalidExecutor stores an initialization error while satisfying SQLRowsExecutor.
@@ -64,6 +69,11 @@ func (b invalidExecutor) Statement() Statement { return nil }
 
 func (b invalidExecutor) Driver() driver.Driver { return nil }
 
+// DryRun implements the SQLRowsExecutor interface.
+func (b invalidExecutor) DryRun(_ context.Context, _ eval.Param) (*DryRunResult, error) {
+	return nil, b.err
+}
+
 // SQLRowsExecutor is an Executor specialized for SQL rows.
 type SQLRowsExecutor Executor[sql.Rows]
 
@@ -96,11 +106,18 @@ type sqlRowsExecutor struct {
 
 // QueryContext executes the query and returns the result.
 func (e *sqlRowsExecutor) QueryContext(ctx context.Context, param eval.Param) (sql.Rows, error) {
+	// insert or update statements may query the returned columns.
+	if err := beforeExec(ctx, e.Statement().Action(), param); err != nil {
+		return nil, err
+	}
 	return e.statementHandler.QueryContext(ctx, e.Statement(), param)
 }
 
 // ExecContext executes the query and returns the result.
 func (e *sqlRowsExecutor) ExecContext(ctx context.Context, param eval.Param) (sql.Result, error) {
+	if err := beforeExec(ctx, e.Statement().Action(), param); err != nil {
+		return nil, err
+	}
 	return e.statementHandler.ExecContext(ctx, e.Statement(), param)
 }
 
@@ -110,6 +127,16 @@ func (e *sqlRowsExecutor) Statement() Statement { return e.statement }
 // Driver returns the executor's driver.
 func (e *sqlRowsExecutor) Driver() driver.Driver { return e.driver }
 
+// DryRun returns the statement the executor would send to the database.
+// It returns ErrDryRunUnsupported if the statement handler cannot dry run statements.
+func (e *sqlRowsExecutor) DryRun(ctx context.Context, param eval.Param) (*DryRunResult, error) {
+	runner, ok := e.statementHandler.(dryRunner)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrDryRunUnsupported, e.statementHandler)
+	}
+	return runner.dryRun(ctx, e.Statement(), param)
+}
+
 func NewSQLRowsExecutor(statement Statement, statementHandler StatementHandler, driver driver.Driver) SQLRowsExecutor {
 	return &sqlRowsExecutor{
 		statement:        statement,
@@ -150,7 +177,10 @@ func (e *GenericExecutor[T]) QueryContext(ctx context.Context, p eval.Param) (re
 	}
 	defer func() { _ = rows.Close() }()
 
-	return sql.BindWithResultMap[T](rows, retMap)
+	if result, err = sql.BindWithResultMap[T](rows, retMap); err != nil {
+		return result, err
+	}
+	return result, afterScan(ctx, &result)
 }
 
 // ExecContext executes the query and returns the result.
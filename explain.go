@@ -0,0 +1,193 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/session"
+	"github.com/go-juicedev/juice/sql"
+)
+
+// ErrExplainUnsupported is returned when the driver cannot return the plan of a query.
+var ErrExplainUnsupported = errors.New("juice: explain is not supported by the driver")
+
+// explainContextKey is the context key asking ExplainMiddleware to explain a query.
+type explainContextKey struct{}
+
+// ContextWithExplain returns a copy of ctx asking ExplainMiddleware to explain
+// the queries executed with it, whatever their duration.
+func ContextWithExplain(ctx context.Context) context.Context {
+	return context.WithValue(ctx, explainContextKey{}, true)
+}
+
+// explainRequested reports whether ctx was returned by ContextWithExplain.
+func explainRequested(ctx context.Context) bool {
+	requested, _ := ctx.Value(explainContextKey{}).(bool)
+	return requested
+}
+
+// QueryPlan is the plan of a query collected by ExplainMiddleware.
+type QueryPlan struct {
+	// Statement is the name of the explained statement.
+	Statement string
+
+	// Query and Args are the explained query and its arguments.
+	Query string
+	Args  []any
+
+	// Spent is how long the query took to return its rows.
+	Spent time.Duration
+
+	// Columns and Rows are the plan returned by the database.
+	Columns []string
+	Rows    [][]any
+}
+
+// String formats the plan as tab separated rows under their column names.
+func (p QueryPlan) String() string {
+	var builder strings.Builder
+	builder.WriteString(strings.Join(p.Columns, "\t"))
+	for _, row := range p.Rows {
+		builder.WriteByte('\n')
+		for i, value := range row {
+			if i > 0 {
+				builder.WriteByte('\t')
+			}
+			fmt.Fprint(&builder, value)
+		}
+	}
+	return builder.String()
+}
+
+// ensure ExplainMiddleware implements Middleware.
+var _ Middleware = (*ExplainMiddleware)(nil) // compile time check
+
+// ExplainMiddleware explains the queries slower than Threshold, and the queries executed
+// with a context returned by ContextWithExplain, to help finding the missing indexes.
+// It executes the query again prefixed with the EXPLAIN syntax of the driver, on the same
+// datasource, once the query returned its rows.
+// Statements with explain="false" are never explained.
+type ExplainMiddleware struct {
+	NoopMiddleware
+
+	// Threshold is the duration from which a query is explained.
+	// Zero explains only the queries asking it through their context.
+	Threshold time.Duration
+
+	// Collect receives the plans, or the errors which prevented explaining a query.
+	// The plans are logged when Collect is nil.
+	Collect func(ctx context.Context, plan QueryPlan, err error)
+}
+
+// QueryContext implements Middleware.
+// Explaining a query never fails it: the errors are reported to Collect.
+func (m *ExplainMiddleware) QueryContext(statementContext *StatementContext, next QueryHandler) QueryHandler {
+	stmt := statementContext.Statement()
+	if stmt.Attribute("explain") == "false" {
+		return next
+	}
+	return func(ctx context.Context, query string, args ...any) (sql.Rows, error) {
+		start := time.Now()
+		rows, err := next(ctx, query, args...)
+		spent := time.Since(start)
+		if err != nil || !m.shouldExplain(ctx, spent) {
+			return rows, err
+		}
+		plan := QueryPlan{Statement: stmt.Name(), Query: query, Args: args, Spent: spent}
+		err = m.explain(ctx, statementContext, &plan)
+		m.collect(ctx, plan, err)
+		return rows, nil
+	}
+}
+
+// shouldExplain reports whether a query which took spent is explained.
+func (m *ExplainMiddleware) shouldExplain(ctx context.Context, spent time.Duration) bool {
+	if explainRequested(ctx) {
+		return true
+	}
+	return m.Threshold > 0 && spent >= m.Threshold
+}
+
+// explain queries the plan of plan.Query into plan.
+func (m *ExplainMiddleware) explain(ctx context.Context, statementContext *StatementContext, plan *QueryPlan) error {
+	engine := statementContext.Engine()
+	prefix := driver.CapabilitiesOf(engine.Driver()).Explain
+	if prefix == "" {
+		return fmt.Errorf("%w: %s", ErrExplainUnsupported, engine.Driver().Name())
+	}
+
+	var sess session.Session = statementContext.Session()
+	// the rows of the query keep the connection of a transaction busy.
+	if isInTransaction(sess) {
+		if db := engine.DB(); db != nil {
+			sess = db
+		}
+	}
+
+	rows, err := sess.QueryContext(ctx, prefix+strings.TrimSpace(plan.Query), plan.Args...)
+	if err != nil {
+		return fmt.Errorf("failed to explain query: %w", err)
+	}
+	plan.Columns, plan.Rows, err = readPlan(rows)
+	return err
+}
+
+// readPlan reads the columns and the rows of a plan, and closes rows.
+// The bytes of the plan are read as strings.
+func readPlan(rows sql.Rows) (columns []string, values [][]any, err error) {
+	defer func() { _ = rows.Close() }()
+
+	if columns, err = rows.Columns(); err != nil {
+		return nil, nil, fmt.Errorf("failed to get plan columns: %w", err)
+	}
+	for rows.Next() {
+		row := make([]any, len(columns))
+		dest := make([]any, len(row))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan plan: %w", err)
+		}
+		for i, value := range row {
+			if bytes, ok := value.([]byte); ok {
+				row[i] = string(bytes)
+			}
+		}
+		values = append(values, row)
+	}
+	return columns, values, rows.Err()
+}
+
+// collect hands the plan to Collect, or logs it.
+func (m *ExplainMiddleware) collect(ctx context.Context, plan QueryPlan, err error) {
+	if m.Collect != nil {
+		m.Collect(ctx, plan, err)
+		return
+	}
+	if err != nil {
+		logger.Printf("\x1b[33m[%s]\x1b[0m explain failed: %v", plan.Statement, err)
+		return
+	}
+	logger.Printf("\x1b[33m[%s]\x1b[0m time: \u001B[31m%v\u001B[0m plan:\n%s", plan.Statement, plan.Spent, plan)
+}
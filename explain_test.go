@@ -0,0 +1,84 @@
+package juice
+
+import (
+	"context"
+	stdsql "database/sql"
+	"errors"
+	"testing"
+
+	jdriver "github.com/go-juicedev/juice/driver"
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+type explainTestSession struct {
+	*stdsql.DB
+	queries []string
+}
+
+func (s *explainTestSession) QueryContext(ctx context.Context, query string, args ...any) (*stdsql.Rows, error) {
+	s.queries = append(s.queries, query)
+	return s.DB.QueryContext(ctx, query, args...)
+}
+
+func TestExplainMiddleware_explain_test(t *testing.T) {
+	sess := &explainTestSession{DB: openStatementTestDB(t, &shSQLDriverState{})}
+
+	type collected struct {
+		plan QueryPlan
+		err  error
+	}
+	var plans []collected
+	middleware := &ExplainMiddleware{Collect: func(_ context.Context, plan QueryPlan, err error) {
+		plans = append(plans, collected{plan: plan, err: err})
+	}}
+	engine := newStatementTestEngine(nil, middleware)
+
+	query := func(ctx context.Context, statement Statement) {
+		t.Helper()
+		handler := newExecuteStatementHandler(" SELECT * FROM user WHERE id = ?", []any{1}, engine, sess).withQueryHandler(
+			func(context.Context, string, ...any) (jsql.Rows, error) {
+				return jsql.NewRowsBuffer(nil, nil), nil
+			},
+		)
+		if _, err := handler.QueryContext(ctx, statement, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	query(context.Background(), shStatement{})
+	if len(plans) != 0 || len(sess.queries) != 0 {
+		t.Fatalf("expected fast queries not to be explained, got %v", sess.queries)
+	}
+
+	query(ContextWithExplain(context.Background()), shStatement{name: "user.Find"})
+	if len(sess.queries) != 1 || sess.queries[0] != "EXPLAIN QUERY PLAN SELECT * FROM user WHERE id = ?" {
+		t.Fatalf("unexpected explain queries %q", sess.queries)
+	}
+	if len(plans) != 1 || plans[0].err != nil || plans[0].plan.Statement != "user.Find" || plans[0].plan.Columns[0] != "value" {
+		t.Fatalf("unexpected plans %+v", plans)
+	}
+
+	middleware.Threshold = 1
+	query(context.Background(), shStatement{})
+	if len(plans) != 2 {
+		t.Fatalf("expected slow queries to be explained, got %d plans", len(plans))
+	}
+
+	query(ContextWithExplain(context.Background()), shStatement{attrs: map[string]string{"explain": "false"}})
+	if len(plans) != 2 {
+		t.Fatalf("expected explain=\"false\" to disable explain, got %d plans", len(plans))
+	}
+
+	engine.driver = jdriver.OracleDriver{}
+	query(context.Background(), shStatement{})
+	if len(plans) != 3 || !errors.Is(plans[2].err, ErrExplainUnsupported) {
+		t.Fatalf("expected ErrExplainUnsupported, got %+v", plans[len(plans)-1])
+	}
+}
+
+func TestQueryPlanString_explain_test(t *testing.T) {
+	plan := QueryPlan{Columns: []string{"id", "detail"}, Rows: [][]any{{2, "SCAN user"}, {3, "USE INDEX"}}}
+	if got := plan.String(); got != "id\tdetail\n2\tSCAN user\n3\tUSE INDEX" {
+		t.Fatalf("unexpected plan %q", got)
+	}
+}
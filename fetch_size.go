@@ -0,0 +1,66 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/sql"
+)
+
+// fetchSizeAttribute is the statement attribute hinting how many rows a query fetches at a time.
+const fetchSizeAttribute = "fetchSize"
+
+// ErrInvalidFetchSize is returned when the fetchSize attribute of a statement is not a positive integer.
+var ErrInvalidFetchSize = errors.New("juice: invalid fetch size")
+
+// FetchSize returns the fetch size of statement, or zero if it has none.
+// Middlewares may use it to tune how they consume the rows of the statement.
+func FetchSize(statement Statement) (int, error) {
+	value := statement.Attribute(fetchSizeAttribute)
+	if value == "" {
+		return 0, nil
+	}
+	size, err := strconv.Atoi(value)
+	if err != nil || size <= 0 {
+		return 0, fmt.Errorf("%w: %q of statement %s", ErrInvalidFetchSize, value, statement.ID())
+	}
+	return size, nil
+}
+
+// withFetchSize wraps next so that the query carries the fetch size hint of the driver
+// when it implements driver.FetchSizer, and its rows report size as their sql.SizeHint.
+func withFetchSize(drv driver.Driver, size int, next QueryHandler) QueryHandler {
+	if size <= 0 {
+		return next
+	}
+	fetchSizer, _ := drv.(driver.FetchSizer)
+	return func(ctx context.Context, query string, args ...any) (sql.Rows, error) {
+		if fetchSizer != nil {
+			ctx = fetchSizer.WithFetchSize(ctx, size)
+		}
+		rows, err := next(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		return sql.WithSizeHint(rows, size), nil
+	}
+}
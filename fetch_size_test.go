@@ -0,0 +1,85 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	jdriver "github.com/go-juicedev/juice/driver"
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+type fetchSizeContextKey struct{}
+
+type fetchSizeTestDriver struct {
+	jdriver.SQLiteDriver
+}
+
+func (fetchSizeTestDriver) WithFetchSize(ctx context.Context, size int) context.Context {
+	return context.WithValue(ctx, fetchSizeContextKey{}, size)
+}
+
+func TestFetchSize_fetch_size_test(t *testing.T) {
+	engine := newStatementTestEngine(nil)
+	engine.driver = fetchSizeTestDriver{}
+
+	var received any
+	handler := newExecuteStatementHandler("SELECT id FROM users", nil, engine, nil).withQueryHandler(
+		func(ctx context.Context, _ string, _ ...any) (jsql.Rows, error) {
+			received = ctx.Value(fetchSizeContextKey{})
+			return jsql.NewRowsBuffer([]string{"id"}, [][]any{{1}}), nil
+		},
+	)
+
+	statement := shStatement{attrs: map[string]string{"fetchSize": "500"}}
+	rows, err := handler.QueryContext(context.Background(), statement, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if received != 500 {
+		t.Fatalf("expected the driver to receive fetch size 500, got %v", received)
+	}
+	hinter, ok := rows.(jsql.SizeHinter)
+	if !ok || hinter.SizeHint() != 500 {
+		t.Fatalf("expected rows with size hint 500, got %T", rows)
+	}
+
+	received = nil
+	rows, err = handler.QueryContext(context.Background(), shStatement{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if received != nil {
+		t.Fatalf("expected no fetch size, got %v", received)
+	}
+	if _, ok := rows.(jsql.SizeHinter); ok {
+		t.Fatalf("expected rows without size hint")
+	}
+
+	for _, value := range []string{"0", "-1", "many"} {
+		statement := shStatement{attrs: map[string]string{"fetchSize": value}}
+		if _, err = handler.QueryContext(context.Background(), statement, nil); !errors.Is(err, ErrInvalidFetchSize) {
+			t.Fatalf("expected ErrInvalidFetchSize for %q, got %v", value, err)
+		}
+	}
+}
+
+func TestFetchSizeMiddleware_fetch_size_test(t *testing.T) {
+	var size int
+	middleware := shObserveMiddleware{queryFn: func(statementContext *StatementContext) {
+		size, _ = FetchSize(statementContext.Statement())
+	}}
+	engine := newStatementTestEngine(nil, middleware)
+	handler := newExecuteStatementHandler("SELECT 1", nil, engine, nil).withQueryHandler(
+		func(context.Context, string, ...any) (jsql.Rows, error) {
+			return jsql.NewRowsBuffer(nil, nil), nil
+		},
+	)
+	statement := shStatement{attrs: map[string]string{"fetchSize": "64"}}
+	if _, err := handler.QueryContext(context.Background(), statement, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if size != 64 {
+		t.Fatalf("expected middleware to see fetch size 64, got %d", size)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/sql"
+)
+
+const (
+	// maxRowsAttribute is the statement attribute and setting limiting the rows bound from a query.
+	maxRowsAttribute = "maxRows"
+
+	// maxRowsPolicyAttribute is the statement attribute and setting choosing what happens
+	// when a query returns more than maxRows rows: "error", the default, or "truncate".
+	maxRowsPolicyAttribute = "maxRowsPolicy"
+
+	// maxPlaceholdersAttribute is the statement attribute and setting limiting the placeholders of a statement.
+	maxPlaceholdersAttribute = "maxPlaceholders"
+)
+
+var (
+	// ErrMaxRowsExceeded is returned while iterating the rows of a query returning
+	// more rows than its maxRows limit with the error policy.
+	ErrMaxRowsExceeded = errors.New("juice: max rows exceeded")
+
+	// ErrTooManyPlaceholders is returned before executing a statement with more placeholders
+	// than its maxPlaceholders limit or the limit of the driver.
+	ErrTooManyPlaceholders = errors.New("juice: too many placeholders")
+)
+
+// withMaxRows wraps next so that its rows are limited by the maxRows option of statement.
+// The rows after the limit are skipped with the truncate policy, otherwise iterating them
+// stops with ErrMaxRowsExceeded. Each result set is limited on its own.
+func withMaxRows(statement Statement, engine *Engine, next QueryHandler) QueryHandler {
+	limit := statementOption(statement, engine, maxRowsAttribute).Int64()
+	if limit <= 0 {
+		return next
+	}
+	truncate := statementOption(statement, engine, maxRowsPolicyAttribute) == "truncate"
+	return func(ctx context.Context, query string, args ...any) (sql.Rows, error) {
+		rows, err := next(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		limited := &maxRows{Rows: rows, name: statement.Name(), limit: limit, truncate: truncate}
+		if resultSets, ok := rows.(sql.ResultSets); ok {
+			return &maxResultSets{maxRows: limited, resultSets: resultSets}, nil
+		}
+		return limited, nil
+	}
+}
+
+// maxRows is the sql.Rows returned by withMaxRows.
+type maxRows struct {
+	sql.Rows
+	name     string
+	limit    int64
+	count    int64
+	truncate bool
+	err      error
+}
+
+// Next implements sql.Rows.
+func (m *maxRows) Next() bool {
+	if m.err != nil || !m.Rows.Next() {
+		return false
+	}
+	if m.count++; m.count <= m.limit {
+		return true
+	}
+	if !m.truncate {
+		m.err = fmt.Errorf("%w: statement %s returned more than %d rows", ErrMaxRowsExceeded, m.name, m.limit)
+	}
+	return false
+}
+
+// Err implements sql.Rows.
+func (m *maxRows) Err() error {
+	if m.err != nil {
+		return m.err
+	}
+	return m.Rows.Err()
+}
+
+// checkMaxPlaceholders returns ErrTooManyPlaceholders if the statement has more arguments than
+// its maxPlaceholders option, or than driver.Capabilities.MaxPlaceholders without the option.
+func checkMaxPlaceholders(statement Statement, engine *Engine, args []any) error {
+	limit := statementOption(statement, engine, maxPlaceholdersAttribute).Int64()
+	if limit <= 0 {
+		limit = int64(driver.CapabilitiesOf(engine.Driver()).MaxPlaceholders)
+	}
+	if limit > 0 && int64(len(args)) > limit {
+		return fmt.Errorf("%w: statement %s has %d placeholders, the limit is %d", ErrTooManyPlaceholders, statement.Name(), len(args), limit)
+	}
+	return nil
+}
+
+// maxResultSets is the sql.ResultSets returned by withMaxRows for sql.ResultSets,
+// limiting each result set to maxRows rows.
+type maxResultSets struct {
+	*maxRows
+	resultSets sql.ResultSets
+}
+
+// NextResultSet implements sql.ResultSets.
+func (m *maxResultSets) NextResultSet() bool {
+	if m.err != nil {
+		return false
+	}
+	m.count = 0
+	return m.resultSets.NextResultSet()
+}
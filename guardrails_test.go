@@ -0,0 +1,80 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	jdriver "github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+func TestMaxRows_guardrails_test(t *testing.T) {
+	engine := newStatementTestEngine(nil)
+	query := func(statement Statement) ([]int, error) {
+		t.Helper()
+		handler := newExecuteStatementHandler("SELECT id FROM user", nil, engine, nil).withQueryHandler(
+			func(context.Context, string, ...any) (jsql.Rows, error) {
+				return jsql.NewRowsBuffer([]string{"id"}, [][]any{{1}, {2}, {3}}), nil
+			},
+		)
+		rows, err := handler.QueryContext(context.Background(), statement, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer func() { _ = rows.Close() }()
+		return jsql.List[int](rows)
+	}
+
+	if ids, err := query(shStatement{}); err != nil || len(ids) != 3 {
+		t.Fatalf("expected all the rows without maxRows, got %v, %v", ids, err)
+	}
+	if ids, err := query(shStatement{attrs: map[string]string{"maxRows": "3"}}); err != nil || len(ids) != 3 {
+		t.Fatalf("expected the rows within maxRows, got %v, %v", ids, err)
+	}
+	if _, err := query(shStatement{attrs: map[string]string{"maxRows": "2"}}); !errors.Is(err, ErrMaxRowsExceeded) {
+		t.Fatalf("expected ErrMaxRowsExceeded, got %v", err)
+	}
+
+	engine.configuration.(*xmlConfiguration).settings["maxRows"] = "2"
+	engine.configuration.(*xmlConfiguration).settings["maxRowsPolicy"] = "truncate"
+	if ids, err := query(shStatement{}); err != nil || len(ids) != 2 || ids[1] != 2 {
+		t.Fatalf("expected the rows to be truncated by the settings, got %v, %v", ids, err)
+	}
+}
+
+func TestMaxPlaceholders_guardrails_test(t *testing.T) {
+	engine := newStatementTestEngine(nil)
+	statement := func(placeholders int, attrs map[string]string) shStatement {
+		return shStatement{
+			attrs: attrs,
+			buildFn: func(jdriver.Translator, eval.Parameter) (string, []any, error) {
+				return "SELECT * FROM user WHERE id IN (...)", make([]any, placeholders), nil
+			},
+		}
+	}
+	build := func(statement Statement) error {
+		_, _, err := buildStatementQuery(context.Background(), statement, engine, nil)
+		return err
+	}
+
+	if err := build(statement(3, map[string]string{"maxPlaceholders": "3"})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := build(statement(4, map[string]string{"maxPlaceholders": "3"})); !errors.Is(err, ErrTooManyPlaceholders) {
+		t.Fatalf("expected ErrTooManyPlaceholders, got %v", err)
+	}
+
+	engine.driver = jdriver.SQLServerDriver{}
+	if err := build(statement(2100, nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := build(statement(2101, nil)); !errors.Is(err, ErrTooManyPlaceholders) {
+		t.Fatalf("expected the limit of the driver, got %v", err)
+	}
+	engine.configuration.(*xmlConfiguration).settings["maxPlaceholders"] = "5000"
+	if err := build(statement(2101, nil)); err != nil {
+		t.Fatalf("expected the setting to override the limit of the driver, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-juicedev/juice/driver"
+)
+
+// defaultTxRetryBackoff is the delay before the first retry of InTx when WithTxRetry sets none.
+const defaultTxRetryBackoff = 10 * time.Millisecond
+
+// maxTxRetryBackoff caps the delay between two attempts of InTx.
+const maxTxRetryBackoff = time.Second
+
+// InTxOption configures Engine.InTx.
+type InTxOption func(*inTxOptions)
+
+// inTxOptions are the options of Engine.InTx.
+type inTxOptions struct {
+	maxRetries int
+	backoff    time.Duration
+}
+
+// WithTxRetry retries the whole transaction of InTx up to maxRetries times when it fails with an error
+// that the driver reports as retryable, like a serialization failure or a deadlock, see driver.IsRetryableError.
+// The delay before the first retry is around backoff, doubled after each retry up to one second.
+func WithTxRetry(maxRetries int, backoff time.Duration) InTxOption {
+	return func(options *inTxOptions) {
+		options.maxRetries = maxRetries
+		options.backoff = backoff
+	}
+}
+
+// InTx executes fn in a transaction begun with opts, committed if fn returns nil and rolled back otherwise,
+// or if fn panics. A nil opts uses the default transaction options of the environment.
+// fn must not commit or roll back the transaction itself.
+//
+// Example:
+//
+//	err := engine.InTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable}, func(tx juice.TxManager) error {
+//	    _, err := juice.NewGenericManager[int64](tx).Object("AccountMapper.Debit").QueryContext(ctx, param)
+//	    return err
+//	}, juice.WithTxRetry(3, 20*time.Millisecond))
+//
+// With WithTxRetry, fn may be called several times, so it should not have side effects outside of the transaction.
+func (e *Engine) InTx(ctx context.Context, opts *sql.TxOptions, fn func(tx TxManager) error, options ...InTxOption) error {
+	var config inTxOptions
+	for _, option := range options {
+		option(&config)
+	}
+	backoff := config.backoff
+	if backoff <= 0 {
+		backoff = defaultTxRetryBackoff
+	}
+	for attempt := 0; ; attempt++ {
+		err := e.inTx(ctx, opts, fn)
+		if err == nil || attempt >= config.maxRetries || !driver.IsRetryableError(e.Driver(), err) {
+			return err
+		}
+		// wait between half and all of the backoff, so that the retried transactions spread out.
+		timer := time.NewTimer(backoff/2 + rand.N(backoff/2+1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, maxTxRetryBackoff)
+	}
+}
+
+// inTx executes one attempt of InTx.
+func (e *Engine) inTx(ctx context.Context, opts *sql.TxOptions, fn func(tx TxManager) error) (err error) {
+	tx := e.ContextTx(ctx, opts)
+	if err = tx.Begin(); err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+	if err = fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && !errors.Is(rollbackErr, sql.ErrTxDone) {
+			err = errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
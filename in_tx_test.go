@@ -0,0 +1,68 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEngine_InTx_in_tx_test(t *testing.T) {
+	state := &shSQLDriverState{}
+	engine := newStatementTestEngine(nil)
+	engine.db = openStatementTestDB(t, state)
+
+	if err := engine.InTx(t.Context(), nil, func(tx TxManager) error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state.beginCalls != 1 || state.commitCalls != 1 || state.rollbackCalls != 0 {
+		t.Fatalf("expected one committed transaction, got %+v", state)
+	}
+
+	// a retryable error of the sqlite driver is retried with WithTxRetry only
+	locked := errors.New("database is locked")
+	attempts := 0
+	fn := func(tx TxManager) error {
+		attempts++
+		if attempts < 3 {
+			return locked
+		}
+		return nil
+	}
+	*state = shSQLDriverState{}
+	if err := engine.InTx(t.Context(), nil, fn); !errors.Is(err, locked) || attempts != 1 {
+		t.Fatalf("expected no retry by default, got %v after %d attempts", err, attempts)
+	}
+	attempts = 0
+	if err := engine.InTx(t.Context(), nil, fn, WithTxRetry(3, time.Millisecond)); err != nil || attempts != 3 {
+		t.Fatalf("expected success after 3 attempts, got %v after %d attempts", err, attempts)
+	}
+	if state.rollbackCalls != 3 || state.commitCalls != 1 {
+		t.Fatalf("expected the failed attempts rolled back, got %+v", state)
+	}
+
+	attempts = 0
+	failed := errors.New("constraint failed")
+	if err := engine.InTx(t.Context(), nil, func(TxManager) error { attempts++; return failed }, WithTxRetry(3, time.Millisecond)); !errors.Is(err, failed) || attempts != 1 {
+		t.Fatalf("expected no retry of a non retryable error, got %v after %d attempts", err, attempts)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if err := engine.InTx(ctx, nil, func(TxManager) error { return locked }, WithTxRetry(3, time.Second)); !errors.Is(err, context.Canceled) && !errors.Is(err, locked) {
+		t.Fatalf("expected the retry to stop with the context, got %v", err)
+	}
+
+	*state = shSQLDriverState{}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected the panic to be propagated")
+			}
+		}()
+		_ = engine.InTx(t.Context(), nil, func(TxManager) error { panic("boom") })
+	}()
+	if state.rollbackCalls != 1 {
+		t.Fatalf("expected the transaction rolled back on panic, got %+v", state)
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-juicedev/juice/node"
+	configparser "github.com/go-juicedev/juice/parser"
+)
+
+// ErrIncludeCycle is returned when <sql> fragments include themselves,
+// directly or through other fragments.
+var ErrIncludeCycle = node.ErrIncludeCycle
+
+// includeFragment identifies a <sql> fragment of a mapper.
+type includeFragment struct {
+	mapper *Mapper
+	id     string
+}
+
+func (f includeFragment) String() string {
+	return f.mapper.namespace + "." + f.id
+}
+
+// resolveIncludeFragment resolves refID the way Mapper.GetSQLNodeByID does,
+// so that "id" refers to the fragments of mapper and "namespace.id" to the
+// fragments of the other mappers.
+func resolveIncludeFragment(mapper *Mapper, refID string) (includeFragment, bool) {
+	if strings.Contains(refID, ".") {
+		target, id, err := mapper.mappers.getMapperAndNodeID(refID)
+		if err != nil {
+			return includeFragment{}, false
+		}
+		mapper, refID = target, id
+	}
+	_, exists := mapper.sqlNodes[refID]
+	return includeFragment{mapper: mapper, id: refID}, exists
+}
+
+// walkIncludes calls fn with the refid of every include of nodes, including the nested ones.
+func walkIncludes(nodes []configparser.Node, fn func(refID string)) {
+	for _, source := range nodes {
+		switch source := source.(type) {
+		case configparser.IncludeNode:
+			fn(source.RefID)
+		case configparser.IfNode:
+			walkIncludes(source.Children, fn)
+		case configparser.ForeachNode:
+			walkIncludes(source.Children, fn)
+		case configparser.ChooseNode:
+			for _, when := range source.Whens {
+				walkIncludes(when.Children, fn)
+			}
+			walkIncludes(source.Otherwise, fn)
+		case configparser.TrimNode:
+			walkIncludes(source.Children, fn)
+		case configparser.WhereNode:
+			walkIncludes(source.Children, fn)
+		case configparser.SetNode:
+			walkIncludes(source.Children, fn)
+		case configparser.UpsertNode:
+			walkIncludes(source.Children, fn)
+		case configparser.WithNode:
+			for _, cte := range source.CTEs {
+				walkIncludes(cte.Children, fn)
+			}
+		}
+	}
+}
+
+// checkIncludeCycles rejects the fragments of sources which include themselves,
+// possibly across namespaces. mappers[i] is the mapper adapted from sources[i].
+// The references which can not be resolved are left to the include nodes,
+// which report them when they are rendered.
+func checkIncludeCycles(mappers []*Mapper, sources []configparser.Mapper) error {
+	var fragments []includeFragment
+	includes := make(map[includeFragment][]includeFragment)
+	for i, mapper := range mappers {
+		for _, fragment := range sources[i].Fragments {
+			from := includeFragment{mapper: mapper, id: fragment.ID}
+			fragments = append(fragments, from)
+			walkIncludes(fragment.Nodes, func(refID string) {
+				if to, ok := resolveIncludeFragment(mapper, refID); ok {
+					includes[from] = append(includes[from], to)
+				}
+			})
+		}
+	}
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+	states := make(map[includeFragment]int, len(fragments))
+	var path []includeFragment
+	var visit func(fragment includeFragment) error
+	visit = func(fragment includeFragment) error {
+		switch states[fragment] {
+		case visited:
+			return nil
+		case visiting:
+			var names []string
+			for i := len(path) - 1; i >= 0; i-- {
+				if path[i] == fragment {
+					for _, f := range path[i:] {
+						names = append(names, f.String())
+					}
+					break
+				}
+			}
+			names = append(names, fragment.String())
+			return fmt.Errorf("%w: %s", ErrIncludeCycle, strings.Join(names, " -> "))
+		}
+		states[fragment] = visiting
+		path = append(path, fragment)
+		for _, next := range includes[fragment] {
+			if err := visit(next); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		states[fragment] = visited
+		return nil
+	}
+	for _, fragment := range fragments {
+		if err := visit(fragment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
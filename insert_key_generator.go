@@ -113,7 +113,8 @@ type selectKeyGenerator interface {
 
 // findFieldIndexesFromProperties finds the field indexes in a struct type based on the provided key properties.
 // It returns a slice of indexes and a boolean indicating if the indexes were found.
-func findFieldIndexesFromProperties(t reflect.Type, keyProperties ...string) ([]int, bool) {
+// The tag names without tagged field are resolved with naming, if it is not nil.
+func findFieldIndexesFromProperties(t reflect.Type, naming sqllib.NamingStrategy, keyProperties ...string) ([]int, bool) {
 	t = reflectlite.IndirectType(t)
 	if t.Kind() != reflect.Struct {
 		return nil, false
@@ -136,6 +137,9 @@ func findFieldIndexesFromProperties(t reflect.Type, keyProperties ...string) ([]
 			indexes = append(indexes, structField.Index...)
 		} else {
 			fieldIndexes, ok := reflectlite.TypeFrom(t).GetFieldIndexesFromTag(sqllib.ColumnTagName(), keyProperty)
+			if !ok && naming != nil {
+				fieldIndexes, ok = fieldIndexByColumn(reflectlite.IndirectType(t), keyProperty, naming)
+			}
 			if !ok {
 				return nil, false
 			}
@@ -150,6 +154,7 @@ func findFieldIndexesFromProperties(t reflect.Type, keyProperties ...string) ([]
 type singleKeyGenerator struct {
 	id          int64
 	keyProperty string
+	naming      sqllib.NamingStrategy
 }
 
 // GenerateKeyTo generates a key for the given reflect.Value based on the key property and sets it to the id.
@@ -165,9 +170,9 @@ func (s singleKeyGenerator) GenerateKeyTo(v reflect.Value) error {
 	// find the field indexes based on the key property
 	var indexes []int
 	if len(s.keyProperty) > 0 {
-		indexes, _ = findFieldIndexesFromProperties(v.Type(), strings.Split(s.keyProperty, ".")...)
+		indexes, _ = findFieldIndexesFromProperties(v.Type(), s.naming, strings.Split(s.keyProperty, ".")...)
 	} else {
-		indexes, _ = findFieldIndexesFromProperties(v.Type())
+		indexes, _ = findFieldIndexesFromProperties(v.Type(), s.naming)
 	}
 	if len(indexes) == 0 {
 		return nil
@@ -185,6 +190,7 @@ func (s singleKeyGenerator) GenerateKeyTo(v reflect.Value) error {
 
 // batchKeyGenerator is a struct that holds an id, a key property, and a key increment for generating keys in batch.
 type batchKeyGenerator struct {
+	naming                        sqllib.NamingStrategy
 	keyProperty                   string
 	batchInsertIDGenerateStrategy string
 	id                            int64
@@ -216,9 +222,9 @@ func (s batchKeyGenerator) GenerateKeyTo(v reflect.Value) error {
 	// find the field indexes based on the key property
 	var indexes []int
 	if len(s.keyProperty) > 0 {
-		indexes, _ = findFieldIndexesFromProperties(elementType, strings.Split(s.keyProperty, ".")...)
+		indexes, _ = findFieldIndexesFromProperties(elementType, s.naming, strings.Split(s.keyProperty, ".")...)
 	} else {
-		indexes, _ = findFieldIndexesFromProperties(elementType)
+		indexes, _ = findFieldIndexesFromProperties(elementType, s.naming)
 	}
 	if len(indexes) == 0 {
 		return nil
@@ -0,0 +1,139 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lruEntry is the element stored in the list of an LRU.
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// LRU is a fixed size, concurrency safe, least recently used cache.
+type LRU[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[K]*list.Element
+	order    *list.List
+
+	// onEvict is called with the evicted entry when the cache is full.
+	onEvict func(key K, value V)
+}
+
+// Get returns the value of key and marks it as the most recently used.
+func (c *LRU[K, V]) Get(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.items[key]
+	if !ok {
+		return value, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry[K, V]).value, true
+}
+
+// Set adds or updates the value of key, evicting the least recently used entry if the cache is full.
+func (c *LRU[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	if element, ok := c.items[key]; ok {
+		element.Value.(*lruEntry[K, V]).value = value
+		c.order.MoveToFront(element)
+		c.mu.Unlock()
+		return
+	}
+	c.items[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+	var evicted *lruEntry[K, V]
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		evicted = oldest.Value.(*lruEntry[K, V])
+		delete(c.items, evicted.key)
+	}
+	c.mu.Unlock()
+
+	// call onEvict without holding the lock, it may use the cache.
+	if evicted != nil && c.onEvict != nil {
+		c.onEvict(evicted.key, evicted.value)
+	}
+}
+
+// Delete removes key from the cache and reports whether it was present.
+func (c *LRU[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	c.order.Remove(element)
+	delete(c.items, key)
+	return true
+}
+
+// Len returns the number of entries in the cache.
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Cap returns the maximum number of entries of the cache.
+func (c *LRU[K, V]) Cap() int {
+	return c.capacity
+}
+
+// Range calls fn for each entry from the most to the least recently used until fn returns false.
+func (c *LRU[K, V]) Range(fn func(key K, value V) bool) {
+	c.mu.Lock()
+	entries := make([]*lruEntry[K, V], 0, c.order.Len())
+	for element := c.order.Front(); element != nil; element = element.Next() {
+		entries = append(entries, element.Value.(*lruEntry[K, V]))
+	}
+	c.mu.Unlock()
+	for _, entry := range entries {
+		if !fn(entry.key, entry.value) {
+			return
+		}
+	}
+}
+
+// Purge removes all the entries from the cache.
+func (c *LRU[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.items)
+	c.order.Init()
+}
+
+// NewLRU creates an LRU holding at most capacity entries.
+// onEvict is optional and called with each entry evicted to make room for a new one.
+// It panics if capacity is not positive.
+func NewLRU[K comparable, V any](capacity int, onEvict func(key K, value V)) *LRU[K, V] {
+	if capacity <= 0 {
+		panic("container: LRU capacity must be positive")
+	}
+	return &LRU[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*list.Element, capacity),
+		order:    list.New(),
+		onEvict:  onEvict,
+	}
+}
@@ -0,0 +1,81 @@
+package container
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestLRU_Basic_lru_test(t *testing.T) {
+	var evicted []string
+	cache := NewLRU[string, int](2, func(key string, _ int) {
+		evicted = append(evicted, key)
+	})
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Fatalf("expected a=1, got %v %v", v, ok)
+	}
+
+	// b is the least recently used entry now.
+	cache.Set("c", 3)
+	if _, ok := cache.Get("b"); ok {
+		t.Fatal("expected b to be evicted")
+	}
+	if len(evicted) != 1 || evicted[0] != "b" {
+		t.Fatalf("unexpected evicted keys: %v", evicted)
+	}
+
+	cache.Set("a", 10)
+	if v, _ := cache.Get("a"); v != 10 {
+		t.Fatalf("expected a=10, got %v", v)
+	}
+	if cache.Len() != 2 || cache.Cap() != 2 {
+		t.Fatalf("unexpected len/cap: %d/%d", cache.Len(), cache.Cap())
+	}
+
+	var keys []string
+	cache.Range(func(key string, _ int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "c" {
+		t.Fatalf("unexpected range order: %v", keys)
+	}
+
+	if !cache.Delete("a") || cache.Delete("a") {
+		t.Fatal("unexpected delete result")
+	}
+	cache.Purge()
+	if cache.Len() != 0 {
+		t.Fatalf("expected empty cache, got %d", cache.Len())
+	}
+}
+
+func TestLRU_InvalidCapacity_lru_test(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for non-positive capacity")
+		}
+	}()
+	NewLRU[string, int](0, nil)
+}
+
+func TestLRU_Concurrent_lru_test(t *testing.T) {
+	cache := NewLRU[int, int](16, nil)
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for j := range 1000 {
+				cache.Set(j%32, id)
+				cache.Get(j % 32)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if cache.Len() > 16 {
+		t.Fatalf("expected at most 16 entries, got %d", cache.Len())
+	}
+}
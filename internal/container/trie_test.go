@@ -300,3 +300,25 @@ func BenchmarkTrie(b *testing.B) {
 		}
 	})
 }
+
+func TestTrie_All_trie_test(t *testing.T) {
+	trie := NewTrie[int]()
+	if got := trie.All(); len(got) != 0 {
+		t.Errorf("Expected no key-value pairs, got %v", got)
+	}
+
+	keys := map[string]int{"a": 1, "a.b": 2, "a.b.c": 3, "x.y": 4}
+	for key, value := range keys {
+		trie.Insert(key, value)
+	}
+
+	all := trie.All()
+	if len(all) != len(keys) {
+		t.Fatalf("Expected %d key-value pairs, got %d", len(keys), len(all))
+	}
+	for _, kv := range all {
+		if keys[kv.Key] != kv.Value {
+			t.Errorf("Unexpected key-value pair %s=%d", kv.Key, kv.Value)
+		}
+	}
+}
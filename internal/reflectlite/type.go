@@ -2,7 +2,9 @@ package reflectlite
 
 import (
 	"reflect"
+	"slices"
 	"strings"
+	"sync"
 )
 
 // IndirectType returns the underlying type if t is a pointer type.
@@ -104,29 +106,60 @@ func (t *Type) Indirect() Type {
 	return Type{Type: t.indirectType, indirectType: t.indirectType, indirectTypeSet: true}
 }
 
-// getFieldIndexesFromTagRecursive is the internal recursive implementation for GetFieldIndexesFromTag.
-// It searches for a field with the given tag name and value within the struct type.
-// If found, it returns the field's index path and true. Otherwise, nil and false.
-// It recursively searches embedded structs if the direct field does not have the tag
-// or if the field is an anonymous struct.
-func getFieldIndexesFromTagRecursive(typ reflect.Type, tagName, tagValue string) ([]int, bool) {
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-		// Check the tag on the current field.
-		if tag := field.Tag.Get(tagName); tag == tagValue {
-			return field.Index, true // Found the tag directly on this field.
-		}
+// fieldTagKey is the cache key of GetFieldIndexesFromTag.
+type fieldTagKey struct {
+	typ      reflect.Type
+	tagName  string
+	tagValue string
+}
+
+// fieldTagResult is the cached result of GetFieldIndexesFromTag.
+type fieldTagResult struct {
+	indexes []int
+	ok      bool
+}
 
-		// If the field is a struct, and it's either anonymous (embedded)
-		// or it does not have the searched tag itself (meaning the tag might be in a sub-field of this struct field),
-		// then recurse into this struct field.
-		if field.Type.Kind() == reflect.Struct && (field.Anonymous || field.Tag.Get(tagName) == "") {
-			if indexes, ok := getFieldIndexesFromTagRecursive(field.Type, tagName, tagValue); ok {
-				// Prepend current field's index to the indexes found in the nested struct.
-				// This correctly builds the path to the tagged field.
-				return append(field.Index, indexes...), true
+// fieldIndexesFromTagCache caches the results of GetFieldIndexesFromTag by fieldTagKey.
+var fieldIndexesFromTagCache sync.Map
+
+// findFieldIndexesFromTag searches for a field with the given tag name and value within the struct type.
+// If found, it returns the field's index path and true. Otherwise, nil and false.
+// The search goes level by level like the promotion of Go fields, so a shallower field wins over
+// a deeper one, and fields declared first win within the same level.
+// It descends into embedded structs, including embedded pointers to structs,
+// and into named struct fields which do not have the tag themselves.
+func findFieldIndexesFromTag(typ reflect.Type, tagName, tagValue string) ([]int, bool) {
+	type level struct {
+		typ   reflect.Type
+		index []int
+	}
+	current := []level{{typ: typ}}
+	// visited avoids endless loops on recursive embedding like `type Node struct{ *Node }`.
+	visited := make(map[reflect.Type]bool)
+	for len(current) > 0 {
+		var next []level
+		for _, item := range current {
+			if visited[item.typ] {
+				continue
+			}
+			visited[item.typ] = true
+			for i := 0; i < item.typ.NumField(); i++ {
+				field := item.typ.Field(i)
+				index := append(slices.Clip(item.index), i)
+				tag := field.Tag.Get(tagName)
+				if tag == tagValue {
+					return index, true // Found the tag directly on this field.
+				}
+				fieldType := field.Type
+				if field.Anonymous && fieldType.Kind() == reflect.Pointer {
+					fieldType = fieldType.Elem()
+				}
+				if fieldType.Kind() == reflect.Struct && (field.Anonymous || tag == "") {
+					next = append(next, level{typ: fieldType, index: index})
+				}
 			}
 		}
+		current = next
 	}
 	return nil, false // Tag not found in this type or any of its relevant sub-structs.
 }
@@ -135,6 +168,8 @@ func getFieldIndexesFromTagRecursive(typ reflect.Type, tagName, tagValue string)
 // that has a tag `tagName` with the value `tagValue`.
 // It returns the field's index path (e.g., []int{0, 1} for a field nested in the first field) and true if found.
 // Otherwise, it returns nil and false.
+// Fields promoted from embedded structs and embedded struct pointers are found as well,
+// use FieldByIndex to access them safely.
 // Results are cached to improve performance on subsequent calls with the same type and tag criteria.
 func (t *Type) GetFieldIndexesFromTag(tagName, tagValue string) ([]int, bool) {
 	// Use the (cached) indirect type for all operations.
@@ -143,7 +178,14 @@ func (t *Type) GetFieldIndexesFromTag(tagName, tagValue string) ([]int, bool) {
 		return nil, false
 	}
 
-	return getFieldIndexesFromTagRecursive(indirect.Type, tagName, tagValue)
+	key := fieldTagKey{typ: indirect.Type, tagName: tagName, tagValue: tagValue}
+	if cached, ok := fieldIndexesFromTagCache.Load(key); ok {
+		result := cached.(fieldTagResult)
+		return result.indexes, result.ok
+	}
+	indexes, ok := findFieldIndexesFromTag(indirect.Type, tagName, tagValue)
+	fieldIndexesFromTagCache.Store(key, fieldTagResult{indexes: indexes, ok: ok})
+	return indexes, ok
 }
 
 // TypeFrom returns a new Type wrapper for the given reflect.Type.
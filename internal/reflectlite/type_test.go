@@ -236,3 +236,42 @@ func TestTypeIdentify_MoreComplexTypes_type_test(t *testing.T) {
 		})
 	}
 }
+
+func TestType_GetFieldIndexesFromTag_EmbeddedPointer_type_test(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `tag:"created_by"`
+		Name      string `tag:"name"`
+	}
+	type Base struct {
+		*Audit
+		ID int64 `tag:"id"`
+	}
+	type Recursive struct {
+		*Recursive
+		Value string `tag:"value"`
+	}
+	type User struct {
+		Base
+		Name string `tag:"name"`
+	}
+
+	typ := TypeFrom(reflect.TypeFor[*User]())
+	if indexes, ok := typ.GetFieldIndexesFromTag("tag", "created_by"); !ok || !reflect.DeepEqual(indexes, []int{0, 0, 0}) {
+		t.Errorf("created_by: expected [0 0 0], got %v (ok: %v)", indexes, ok)
+	}
+	// the shallower field wins over the promoted one, even if declared later.
+	if indexes, ok := typ.GetFieldIndexesFromTag("tag", "name"); !ok || !reflect.DeepEqual(indexes, []int{1}) {
+		t.Errorf("name: expected [1], got %v (ok: %v)", indexes, ok)
+	}
+	if _, ok := typ.GetFieldIndexesFromTag("tag", "missing"); ok {
+		t.Error("missing: expected not found")
+	}
+
+	recursive := TypeFrom(reflect.TypeFor[Recursive]())
+	if indexes, ok := recursive.GetFieldIndexesFromTag("tag", "value"); !ok || !reflect.DeepEqual(indexes, []int{1}) {
+		t.Errorf("value: expected [1], got %v (ok: %v)", indexes, ok)
+	}
+	if _, ok := recursive.GetFieldIndexesFromTag("tag", "missing"); ok {
+		t.Error("recursive missing: expected not found")
+	}
+}
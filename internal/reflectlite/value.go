@@ -34,6 +34,17 @@ func init() {
 	}
 }
 
+// FieldByIndex returns the nested field of the struct value v corresponding to index.
+// Unlike reflect.Value.FieldByIndex, it reports false instead of panicking
+// when the path steps through a nil embedded struct pointer.
+func FieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	field, err := v.FieldByIndexErr(index)
+	if err != nil {
+		return reflect.Value{}, false
+	}
+	return field, true
+}
+
 // Unwrap continuously dereferences pointers and interfaces until a non-pointer/non-interface value is reached.
 // If the initial value is not a pointer or interface, it's returned directly.
 // This is useful for getting the underlying concrete value.
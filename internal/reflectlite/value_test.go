@@ -387,3 +387,20 @@ func TestUnwrap_Global_value_test(t *testing.T) {
 		})
 	}
 }
+
+func TestFieldByIndex_value_test(t *testing.T) {
+	type Inner struct {
+		Name string
+	}
+	type Outer struct {
+		*Inner
+	}
+
+	if _, ok := FieldByIndex(reflect.ValueOf(Outer{}), []int{0, 0}); ok {
+		t.Error("expected nil embedded pointer to be reported")
+	}
+	field, ok := FieldByIndex(reflect.ValueOf(Outer{Inner: &Inner{Name: "juice"}}), []int{0, 0})
+	if !ok || field.String() != "juice" {
+		t.Errorf("expected juice, got %v (ok: %v)", field, ok)
+	}
+}
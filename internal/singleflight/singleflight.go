@@ -0,0 +1,102 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package singleflight coalesces the concurrent calls sharing a key into one execution.
+package singleflight
+
+import "sync"
+
+// call is an execution of a Group, in flight or completed.
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+	// waiters is the number of the other callers waiting for the call.
+	waiters int
+}
+
+// Group coalesces the calls of the same key. The zero value is ready to use.
+type Group[V any] struct {
+	mu    sync.Mutex
+	calls map[string]*call[V]
+}
+
+// Do executes fn and returns its results, unless a call of key is in flight,
+// in which case it waits for it and returns its results. shared reports whether
+// the results were returned to several callers.
+func (g *Group[V]) Do(key string, fn func() (V, error)) (value V, err error, shared bool) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		c.waiters++
+		g.mu.Unlock()
+		<-c.done
+		return c.value, c.err, true
+	}
+	c := g.start(key)
+	g.mu.Unlock()
+
+	defer g.finish(key, c)
+	c.value, c.err = fn()
+	g.mu.Lock()
+	shared = c.waiters > 0
+	g.mu.Unlock()
+	return c.value, c.err, shared
+}
+
+// TryGo executes fn in a new goroutine and returns true, unless a call of key is in flight.
+func (g *Group[V]) TryGo(key string, fn func() (V, error)) bool {
+	g.mu.Lock()
+	if _, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		return false
+	}
+	c := g.start(key)
+	g.mu.Unlock()
+
+	go func() {
+		defer g.finish(key, c)
+		c.value, c.err = fn()
+	}()
+	return true
+}
+
+// Waiters returns the number of the callers waiting for the call of key in flight.
+func (g *Group[V]) Waiters(key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if c, ok := g.calls[key]; ok {
+		return c.waiters
+	}
+	return 0
+}
+
+// start registers a call of key. The caller must hold mu.
+func (g *Group[V]) start(key string) *call[V] {
+	if g.calls == nil {
+		g.calls = make(map[string]*call[V])
+	}
+	c := &call[V]{done: make(chan struct{})}
+	g.calls[key] = c
+	return c
+}
+
+// finish removes the call of key and releases its waiters, even if fn panicked.
+func (g *Group[V]) finish(key string, c *call[V]) {
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(c.done)
+}
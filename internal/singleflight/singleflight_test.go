@@ -0,0 +1,90 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package singleflight
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGroupDo(t *testing.T) {
+	var (
+		group   Group[int]
+		calls   atomic.Int32
+		started = make(chan struct{})
+		release = make(chan struct{})
+		wg      sync.WaitGroup
+	)
+	fn := func() (int, error) {
+		calls.Add(1)
+		close(started)
+		<-release
+		return 42, nil
+	}
+	results := make([]int, 3)
+	shared := make([]bool, 3)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _, shared[0] = group.Do("key", fn)
+	}()
+	<-started
+	for i := 1; i < len(results); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _, shared[i] = group.Do("key", func() (int, error) { return 0, nil })
+		}()
+	}
+	// wait for the waiters to join the call.
+	for group.Waiters("key") != len(results)-1 {
+		runtime.Gosched()
+	}
+	close(release)
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.Fatalf("expected one call, got %d", calls.Load())
+	}
+	for i, result := range results {
+		if result != 42 || !shared[i] {
+			t.Fatalf("caller %d: got %d, shared %v", i, result, shared[i])
+		}
+	}
+	if _, _, shared := group.Do("key", func() (int, error) { return 1, nil }); shared {
+		t.Fatal("expected a call after the completion not to be shared")
+	}
+}
+
+func TestGroupTryGo(t *testing.T) {
+	var group Group[struct{}]
+	release := make(chan struct{})
+	done := make(chan struct{})
+	if !group.TryGo("key", func() (struct{}, error) {
+		<-release
+		close(done)
+		return struct{}{}, nil
+	}) {
+		t.Fatal("expected the first call to start")
+	}
+	if group.TryGo("key", func() (struct{}, error) { return struct{}{}, nil }) {
+		t.Fatal("expected a call to be skipped while another one is in flight")
+	}
+	close(release)
+	<-done
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// maxInterpolationDepth limits the nesting of the references resolved by interpolator,
+// which also stops the cycles between configuration properties.
+const maxInterpolationDepth = 8
+
+// ErrInterpolation is returned when a reference of a configuration value can not be resolved.
+var ErrInterpolation = errors.New("juice: interpolation failed")
+
+// interpolator expands the references of the configuration values:
+//
+//	${env:NAME}            the value of the environment variable NAME
+//	${env:NAME:-default}   the value of NAME, or default if it is unset or empty
+//	${prop:name}           the value of the <property> name of the <properties> element
+//	${prop:name:-default}  the value of the property name, or default if it is not defined
+//
+// Resolved values and defaults are expanded too. Other ${...} are kept as they are,
+// so that the EnvValueProvider of an environment can still resolve them.
+type interpolator struct {
+	properties map[string]string
+}
+
+// expand returns value with its references resolved.
+func (i interpolator) expand(value string) (string, error) {
+	return i.expandDepth(value, "", 0)
+}
+
+// expandDepth expands value, which is the one of the reference source, or the configuration value if it is empty.
+// The errors only name the references, since the values may be secrets, like passwords.
+func (i interpolator) expandDepth(value, source string, depth int) (string, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+	if depth >= maxInterpolationDepth {
+		return "", fmt.Errorf("%w: references nested deeper than %d in %s", ErrInterpolation, maxInterpolationDepth, source)
+	}
+	var builder strings.Builder
+	for {
+		start := strings.Index(value, "${")
+		if start < 0 {
+			builder.WriteString(value)
+			return builder.String(), nil
+		}
+		end := closingBrace(value, start+2)
+		if end < 0 {
+			if source == "" {
+				return "", fmt.Errorf("%w: unclosed reference", ErrInterpolation)
+			}
+			return "", fmt.Errorf("%w: unclosed reference in %s", ErrInterpolation, source)
+		}
+		builder.WriteString(value[:start])
+		resolved, err := i.resolve(value[start:end+1], value[start+2:end], depth)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(resolved)
+		value = value[end+1:]
+	}
+}
+
+// resolve resolves the reference whose content is between the braces.
+func (i interpolator) resolve(reference, content string, depth int) (string, error) {
+	scheme, rest, ok := strings.Cut(content, ":")
+	if !ok || (scheme != "env" && scheme != "prop") {
+		return reference, nil
+	}
+	name, fallback, hasFallback := strings.Cut(rest, ":-")
+	var (
+		resolved string
+		found    bool
+	)
+	switch scheme {
+	case "env":
+		resolved, found = os.LookupEnv(name)
+		// like the shell, an empty variable takes the default too.
+		found = found && (resolved != "" || !hasFallback)
+	case "prop":
+		resolved, found = i.properties[name]
+	}
+	source := scheme + ":" + name
+	if !found {
+		if !hasFallback {
+			return "", fmt.Errorf("%w: %s is not defined", ErrInterpolation, source)
+		}
+		resolved = fallback
+	}
+	return i.expandDepth(resolved, source, depth+1)
+}
+
+// closingBrace returns the index of the brace closing the reference whose content starts at start, or -1.
+func closingBrace(value string, start int) int {
+	depth := 1
+	for index := start; index < len(value); index++ {
+		switch {
+		case strings.HasPrefix(value[index:], "${"):
+			depth++
+			index++
+		case value[index] == '}':
+			if depth--; depth == 0 {
+				return index
+			}
+		}
+	}
+	return -1
+}
+
+// interpolatedEnvValueProvider expands the references of a value before resolving it with provider.
+type interpolatedEnvValueProvider struct {
+	interpolator interpolator
+	provider     EnvValueProvider
+}
+
+// Get implements EnvValueProvider.
+func (p interpolatedEnvValueProvider) Get(key string) (string, error) {
+	expanded, err := p.interpolator.expand(key)
+	if err != nil {
+		return "", err
+	}
+	return p.provider.Get(expanded)
+}
@@ -0,0 +1,86 @@
+package juice
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestInterpolator_interpolate_test(t *testing.T) {
+	t.Setenv("JUICE_INTERPOLATE_HOST", "db.local")
+	t.Setenv("JUICE_INTERPOLATE_EMPTY", "")
+	i := interpolator{properties: map[string]string{
+		"host": "${env:JUICE_INTERPOLATE_HOST}",
+		"dsn":  "tcp(${prop:host}:${prop:port:-3306})",
+		"self": "${prop:self}",
+		"loop": "s3cr3t${prop:loop}",
+		"open": "s3cr3t${env:JUICE_INTERPOLATE_HOST",
+	}}
+	tests := map[string]string{
+		"plain":                                 "plain",
+		"${env:JUICE_INTERPOLATE_HOST}":         "db.local",
+		"${env:JUICE_INTERPOLATE_MISSING:-a}":   "a",
+		"${env:JUICE_INTERPOLATE_EMPTY:-b}":     "b",
+		"${env:JUICE_INTERPOLATE_EMPTY}":        "",
+		"root@${prop:dsn}/app":                  "root@tcp(db.local:3306)/app",
+		"${prop:missing:-${prop:host}}":         "db.local",
+		"${prop:missing:-a:-b}":                 "a:-b",
+		"${HOME} and ${other:thing}":            "${HOME} and ${other:thing}",
+		"${env:JUICE_INTERPOLATE_MISSING:-}end": "end",
+	}
+	for value, want := range tests {
+		if got, err := i.expand(value); err != nil || got != want {
+			t.Errorf("expand(%q) = %q, %v, want %q", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"${prop:missing}", "${env:JUICE_INTERPOLATE_MISSING}", "${prop:self}", "${env:JUICE_INTERPOLATE_HOST"} {
+		if _, err := i.expand(value); !errors.Is(err, ErrInterpolation) {
+			t.Errorf("expand(%q): expected ErrInterpolation, got %v", value, err)
+		}
+	}
+	// the errors name the references, not their values.
+	for value, want := range map[string]string{"${prop:loop}": "in prop:loop", "${prop:open}": "unclosed reference in prop:open"} {
+		_, err := i.expand(value)
+		if err == nil || !strings.Contains(err.Error(), want) || strings.Contains(err.Error(), "s3cr3t") {
+			t.Errorf("expand(%q): unexpected error %v", value, err)
+		}
+	}
+}
+
+func TestInterpolatedConfiguration_interpolate_test(t *testing.T) {
+	t.Setenv("JUICE_INTERPOLATE_DRIVER", "sqlite3")
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<properties>
+		<property name="dir" value="/var/lib"/>
+		<property name="replica" value="replica"/>
+	</properties>
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>${prop:dir}/${env:JUICE_INTERPOLATE_DB:-app}.db</dataSource>
+			<driver>${env:JUICE_INTERPOLATE_DRIVER}</driver>
+			<maxOpenConnNum>${prop:maxOpen:-4}</maxOpenConnNum>
+		</environment>
+	</environments>
+	<settings>
+		<setting name="selectDataSource" value="${prop:replica}"/>
+	</settings>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	environment, err := configuration.Environments().Use("prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if environment.DataSource != "/var/lib/app.db" || environment.Driver != "sqlite3" || environment.MaxOpenConnNum != 4 {
+		t.Fatalf("unexpected environment %+v", environment)
+	}
+	if got := configuration.Settings().Get("selectDataSource"); got != "replica" {
+		t.Fatalf("unexpected setting %q", got)
+	}
+}
@@ -22,6 +22,8 @@ import (
 	"io/fs"
 
 	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	sqllib "github.com/go-juicedev/juice/sql"
 )
 
 // Engine is the implementation of Manager interface and the core of juice.
@@ -39,10 +41,47 @@ type Engine struct {
 	// using is the active environment id.
 	using string
 
+	// environment is the active environment, nil if the configuration does not describe it.
+	environment *Environment
+
 	manager *DBManager
 
 	// middlewares intercept statement execution for logging, tracing, routing, and similar concerns.
 	middlewares MiddlewareGroup
+
+	// batchHook observes the batches of statements with a batchSize attribute.
+	batchHook BatchHook
+
+	// rowsHook observes the rows returned by the queries.
+	rowsHook RowsHook
+
+	// namingStrategy names the columns of the untagged struct fields, see SetNamingStrategy.
+	namingStrategy sqllib.NamingStrategy
+
+	// maskFunc masks the columns listed by the maskColumns attribute of the environment.
+	maskFunc sqllib.MaskFunc
+
+	// multiTxLog records the state transitions of the transactions of MultiTx.
+	multiTxLog MultiTxRecoveryLog
+
+	// funcs holds the eval functions scoped to this engine.
+	funcs *eval.FuncRegistry
+
+	// paramProcessors process the parameters of statements before they are built.
+	paramProcessors ParamProcessorGroup
+
+	// nodeInterceptors rewrite the node trees of statements before they are built.
+	nodeInterceptors NodeInterceptorGroup
+
+	// shardingStrategies maps the logical tables of sharded statements to their strategy.
+	shardingStrategies map[string]ShardingStrategy
+
+	// inflight tracks the statements being executed, shared by the cloned engines.
+	inflight *inflightTracker
+
+	// preparedStatements caches the prepared statements of the transaction the engine is bound to.
+	// It is nil outside of transactions caching their prepared statements.
+	preparedStatements *preparedStatementCache
 }
 
 // executor creates an SQLRowsExecutor for the mapped statement.
@@ -51,6 +90,9 @@ func (e *Engine) executor(v any) (SQLRowsExecutor, error) {
 	if err != nil {
 		return nil, err
 	}
+	if statement, err = resolveDatabaseStatement(statement, e); err != nil {
+		return nil, err
+	}
 	statementHandler := newBatchStatementHandler(e, e.DB())
 	return NewSQLRowsExecutor(statement, statementHandler, e.Driver()), nil
 }
@@ -64,13 +106,28 @@ func (e *Engine) Object(v any) SQLRowsExecutor {
 	return exe
 }
 
-// Tx returns a TxManager
+// Statement returns the executor of statement, built in code rather than declared in a mapper,
+// like the statements generated by tools. It is executed like the mapped statements,
+// with the middlewares and the batches of its batchSize attribute.
+func (e *Engine) Statement(statement Statement) SQLRowsExecutor {
+	if statement == nil {
+		return inValidExecutor(ErrNoStatementFound)
+	}
+	return NewSQLRowsExecutor(statement, newBatchStatementHandler(e, e.DB()), e.Driver())
+}
+
+// Tx returns a TxManager using the default transaction options of the environment.
 func (e *Engine) Tx() *BasicTxManager {
 	return e.ContextTx(context.Background(), nil)
 }
 
-// ContextTx returns a TxManager with the given context
+// ContextTx returns a TxManager with the given context.
+// A nil opt uses the default transaction options of the environment,
+// declared by its <transaction isolation="..." readOnly="..."/> element.
 func (e *Engine) ContextTx(ctx context.Context, opt *sql.TxOptions) *BasicTxManager {
+	if opt == nil {
+		opt = e.defaultTxOptions()
+	}
 	return &BasicTxManager{
 		basicTxManager: &basicTxManager{
 			engine: e,
@@ -80,6 +137,15 @@ func (e *Engine) ContextTx(ctx context.Context, opt *sql.TxOptions) *BasicTxMana
 	}
 }
 
+// defaultTxOptions returns a copy of the default transaction options of the environment, or nil.
+func (e *Engine) defaultTxOptions() *sql.TxOptions {
+	if e.environment == nil || e.environment.TxOptions == nil {
+		return nil
+	}
+	options := *e.environment.TxOptions
+	return &options
+}
+
 // GetConfiguration returns the configuration of the engine
 func (e *Engine) GetConfiguration() Configuration {
 	return e.configuration
@@ -90,14 +156,106 @@ func (e *Engine) Use(middleware Middleware) {
 	e.middlewares = append(e.middlewares, middleware)
 }
 
+// UseFor adds a middleware to the engine applied only to the statements whose
+// fully qualified name matches pattern, like user.* for a namespace.
+// See ScopedMiddleware for the pattern syntax.
+func (e *Engine) UseFor(pattern string, middleware Middleware) error {
+	scoped, err := ScopedMiddleware(pattern, middleware)
+	if err != nil {
+		return err
+	}
+	e.Use(scoped)
+	return nil
+}
+
+// UseParamProcessor adds a ParamProcessor to the engine.
+// Processors run in registration order before each statement is built.
+func (e *Engine) UseParamProcessor(processor ParamProcessor) {
+	e.paramProcessors = append(e.paramProcessors, processor)
+}
+
+// UseNodeInterceptor adds a NodeInterceptor to the engine.
+// Interceptors run in registration order before each mapped statement is built.
+func (e *Engine) UseNodeInterceptor(interceptor NodeInterceptor) {
+	e.nodeInterceptors = append(e.nodeInterceptors, interceptor)
+}
+
+// UseShardingStrategy registers the ShardingStrategy of the logical table,
+// used by the statements with shardBy and shardTable attributes.
+func (e *Engine) UseShardingStrategy(table string, strategy ShardingStrategy) {
+	if e.shardingStrategies == nil {
+		e.shardingStrategies = make(map[string]ShardingStrategy)
+	}
+	e.shardingStrategies[table] = strategy
+}
+
+// SetBatchHook sets the BatchHook used by batch statements of the engine.
+// It can be overridden per call with ContextWithBatchHook.
+func (e *Engine) SetBatchHook(hook BatchHook) {
+	e.batchHook = hook
+}
+
+// SetRowsHook sets the RowsHook observing the rows returned by the queries of the engine.
+func (e *Engine) SetRowsHook(hook RowsHook) {
+	e.rowsHook = hook
+}
+
+// RegisterEvalFunc registers an eval function scoped to this engine, so that it can be used in
+// the expressions of the statements executed by the engine without mutating the global builtins.
+// The function may return nothing, a single value, or a value and an error.
+// It returns eval.ErrFuncConflict if the name is used by a builtin or already registered.
+func (e *Engine) RegisterEvalFunc(name string, fn any) error {
+	if e.funcs == nil {
+		e.funcs = eval.NewFuncRegistry()
+	}
+	return e.funcs.Register(name, fn)
+}
+
+// evalFuncs returns the eval functions scoped to the engine and its configuration.
+// Functions of the engine take precedence over the ones of the configuration.
+// It returns nil if there is none.
+func (e *Engine) evalFuncs() eval.Parameter {
+	var funcs eval.ParamGroup
+	if e.funcs != nil && e.funcs.Len() > 0 {
+		funcs = append(funcs, e.funcs)
+	}
+	if provider, ok := e.configuration.(evalFuncProvider); ok {
+		if configurationFuncs := provider.evalFuncs(); configurationFuncs != nil && configurationFuncs.Len() > 0 {
+			funcs = append(funcs, configurationFuncs)
+		}
+	}
+	if len(funcs) == 0 {
+		return nil
+	}
+	return funcs
+}
+
 func (e *Engine) clone() *Engine {
 	return &Engine{
-		configuration: e.configuration,
-		manager:       e.manager,
-		middlewares:   e.middlewares,
+		configuration:      e.configuration,
+		manager:            e.manager,
+		middlewares:        e.middlewares,
+		batchHook:          e.batchHook,
+		rowsHook:           e.rowsHook,
+		namingStrategy:     e.namingStrategy,
+		multiTxLog:         e.multiTxLog,
+		maskFunc:           e.maskFunc,
+		funcs:              e.funcs,
+		paramProcessors:    e.paramProcessors,
+		nodeInterceptors:   e.nodeInterceptors,
+		shardingStrategies: e.shardingStrategies,
+		inflight:           e.inflight,
 	}
 }
 
+// withPreparedStatements returns a copy of the engine preparing its statements through statements.
+func (e *Engine) withPreparedStatements(statements *preparedStatementCache) *Engine {
+	engine := e.clone()
+	engine.db, engine.driver, engine.using, engine.environment = e.db, e.driver, e.using, e.environment
+	engine.preparedStatements = statements
+	return engine
+}
+
 // With creates a new Engine instance with the specified environment name.
 // If the requested environment name matches the current one, it returns the same engine.
 // Otherwise, it creates a cloned engine with the new database connection and driver.
@@ -113,6 +271,7 @@ func (e *Engine) With(name string) (*Engine, error) {
 	engine := e.clone()
 	engine.db, engine.driver = db, drv
 	engine.using = name
+	engine.environment = lookupEnvironment(e.configuration, name)
 	return engine, nil
 }
 
@@ -131,6 +290,23 @@ func (e *Engine) Driver() driver.Driver {
 	return e.driver
 }
 
+// Stats returns the connection pool statistics of every opened environment, keyed by environment id.
+func (e *Engine) Stats() map[string]sql.DBStats {
+	return e.manager.Stats()
+}
+
+// Ping verifies the connection to the database of the active environment.
+func (e *Engine) Ping(ctx context.Context) error {
+	return e.db.PingContext(ctx)
+}
+
+// HealthCheck verifies the connection to the database of every configured environment,
+// which makes it suitable for readiness probes.
+// It returns the errors of the unreachable environments joined together.
+func (e *Engine) HealthCheck(ctx context.Context) error {
+	return e.manager.HealthCheck(ctx)
+}
+
 // Close gracefully shuts down all managed database connections
 // all cloned engines share the same DBManager
 func (e *Engine) Close() error {
@@ -145,9 +321,25 @@ func (e *Engine) init() (err error) {
 	}
 	e.using = e.configuration.Environments().Attribute("default")
 	e.db, e.driver, err = e.manager.Get(e.using)
+	e.environment = lookupEnvironment(e.configuration, e.using)
 	return err
 }
 
+// lookupEnvironment returns the environment named name, or nil if configuration does not describe it.
+func lookupEnvironment(configuration Configuration, name string) *Environment {
+	provider := configuration.Environments()
+	if provider == nil {
+		return nil
+	}
+	environment, err := provider.Use(name)
+	if err != nil {
+		return nil
+	}
+	return environment
+}
+
+// Raw returns a Runner executing query, whose parameters are bound from the parameter of the
+// Runner methods by name, written #{name} like in the XML statements or :name.
 func (e *Engine) Raw(query string) Runner {
 	return NewRunner(query, e, e.DB())
 }
@@ -156,12 +348,16 @@ func (e *Engine) Raw(query string) Runner {
 func New(configuration Configuration) (*Engine, error) {
 	engine := &Engine{
 		configuration: configuration,
+		inflight:      &inflightTracker{},
 	}
 	if err := engine.init(); err != nil {
 		return nil, err
 	}
 	// add the default middlewares
 	engine.Use(&useGeneratedKeysMiddleware{})
+	engine.Use(&softDeleteMiddleware{})
+	engine.Use(&tenantMiddleware{})
+	engine.Use(&shardingMiddleware{})
 	return engine, nil
 }
 
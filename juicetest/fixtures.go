@@ -0,0 +1,378 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juicetest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-juicedev/juice"
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	"github.com/go-juicedev/juice/node"
+	"github.com/go-juicedev/juice/sql"
+)
+
+// FixtureDependencies is the file of a fixtures directory declaring the tables each table
+// references by foreign key, one table per line:
+//
+//	orders: users, products
+//	order_items: orders
+//
+// The referenced tables are loaded before, and emptied after, the tables referencing them.
+const FixtureDependencies = "dependencies.txt"
+
+// fixtureBatchSize is the number of rows inserted by each statement of LoadFixtures.
+const fixtureBatchSize = 100
+
+// fixture is the rows of a table read from a fixture file.
+type fixture struct {
+	table   string
+	columns []string
+	rows    []map[string]any
+}
+
+// LoadFixtures replaces the rows of the tables of the fixture files of the directory dir of fsys.
+//
+// The fixture of a table is the file named after it, either a CSV file whose first record
+// is the columns, like users.csv, or a YAML file holding a sequence of mappings, like users.yml:
+//
+//	# users.yml
+//	- id: 1
+//	  name: eat
+//	- id: 2
+//	  name: more
+//
+// The CSV fields \N and the YAML values null and ~ are inserted as NULL.
+// The tables are emptied in the reverse order of FixtureDependencies, then their rows are inserted
+// in batches by generated insert statements, executed with the middlewares of the engine.
+func LoadFixtures(ctx context.Context, engine *juice.Engine, fsys fs.FS, dir string) error {
+	fixtures, err := readFixtures(fsys, dir)
+	if err != nil {
+		return err
+	}
+	order, err := fixtureOrder(fsys, dir, fixtures)
+	if err != nil {
+		return err
+	}
+	for _, table := range slices.Backward(order) {
+		if _, err = engine.Raw("DELETE FROM "+table).Delete(ctx, nil); err != nil {
+			return fmt.Errorf("juicetest: empty %s: %w", table, err)
+		}
+	}
+	for _, table := range order {
+		fixture := fixtures[table]
+		if len(fixture.rows) == 0 {
+			continue
+		}
+		statement := fixtureStatement{table: table, columns: fixture.columns}
+		if _, err = engine.Statement(statement).ExecContext(ctx, fixture.rows); err != nil {
+			return fmt.Errorf("juicetest: load %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// readFixtures reads the fixture files of dir, keyed by table.
+func readFixtures(fsys fs.FS, dir string) (map[string]*fixture, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	fixtures := make(map[string]*fixture)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		extension := path.Ext(entry.Name())
+		var parse func(data []byte) (*fixture, error)
+		switch extension {
+		case ".csv":
+			parse = parseCSVFixture
+		case ".yml", ".yaml":
+			parse = parseYAMLFixture
+		default:
+			continue
+		}
+		table := strings.TrimSuffix(entry.Name(), extension)
+		if _, exists := fixtures[table]; exists {
+			return nil, fmt.Errorf("juicetest: several fixtures of table %s", table)
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		fixture, err := parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("juicetest: fixture %s: %w", entry.Name(), err)
+		}
+		fixture.table = table
+		fixtures[table] = fixture
+	}
+	return fixtures, nil
+}
+
+// parseCSVFixture parses a CSV fixture.
+func parseCSVFixture(data []byte) (*fixture, error) {
+	rows, err := sql.NewCSVRows(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	fixture := &fixture{columns: slices.Clone(columns)}
+	values := make([]any, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err = rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]any, len(columns))
+		for i, column := range columns {
+			row[column] = values[i]
+		}
+		fixture.rows = append(fixture.rows, row)
+	}
+	return fixture, rows.Err()
+}
+
+// parseYAMLFixture parses a YAML fixture, a sequence of mappings of scalars.
+// The columns are the keys of the mappings, the rows missing some of them insert NULL.
+func parseYAMLFixture(data []byte) (*fixture, error) {
+	fixture := &fixture{}
+	columns := make(map[string]struct{})
+	var row map[string]any
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for number := 1; scanner.Scan(); number++ {
+		line := stripYAMLComment(scanner.Text())
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		if item, ok := strings.CutPrefix(trimmed, "-"); ok && line[0] == '-' && (item == "" || item[0] == ' ') {
+			row = make(map[string]any)
+			fixture.rows = append(fixture.rows, row)
+			if trimmed = strings.TrimSpace(item); trimmed == "" {
+				continue
+			}
+		} else if row == nil || line[0] != ' ' {
+			return nil, fmt.Errorf("line %d: expected a sequence of mappings", number)
+		}
+		key, value, ok := strings.Cut(trimmed, ":")
+		if key = strings.TrimSpace(key); !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected key: value", number)
+		}
+		if _, exists := row[key]; exists {
+			return nil, fmt.Errorf("line %d: duplicate key %s", number, key)
+		}
+		scalar, err := parseYAMLScalar(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+		row[key] = scalar
+		columns[key] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	fixture.columns = slices.Sorted(maps.Keys(columns))
+	for _, row := range fixture.rows {
+		for _, column := range fixture.columns {
+			if _, exists := row[column]; !exists {
+				row[column] = nil
+			}
+		}
+	}
+	return fixture, nil
+}
+
+// stripYAMLComment removes the comment of line, starting with a # outside of quotes
+// at the start of the line or after a space.
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// parseYAMLScalar parses a plain or quoted YAML scalar into nil, a bool, an int64, a float64 or a string.
+func parseYAMLScalar(value string) (any, error) {
+	switch {
+	case value == "" || value == "~" || value == "null" || value == "Null" || value == "NULL":
+		return nil, nil
+	case value[0] == '"':
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid double-quoted string %s", value)
+		}
+		return unquoted, nil
+	case value[0] == '\'':
+		if len(value) < 2 || value[len(value)-1] != '\'' {
+			return nil, fmt.Errorf("invalid single-quoted string %s", value)
+		}
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'"), nil
+	}
+	switch value {
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if integer, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return integer, nil
+	}
+	if float, err := strconv.ParseFloat(value, 64); err == nil {
+		return float, nil
+	}
+	return value, nil
+}
+
+// fixtureOrder returns the tables of fixtures with the referenced tables first,
+// following FixtureDependencies, and in alphabetical order otherwise.
+func fixtureOrder(fsys fs.FS, dir string, fixtures map[string]*fixture) ([]string, error) {
+	dependencies := make(map[string][]string)
+	data, err := fs.ReadFile(fsys, path.Join(dir, FixtureDependencies))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for number, line := range strings.Split(string(data), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		table, referenced, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("juicetest: %s line %d: expected table: referenced tables", FixtureDependencies, number+1)
+		}
+		for reference := range strings.SplitSeq(referenced, ",") {
+			if reference = strings.TrimSpace(reference); reference != "" {
+				dependencies[strings.TrimSpace(table)] = append(dependencies[strings.TrimSpace(table)], reference)
+			}
+		}
+	}
+
+	var (
+		order    []string
+		visiting = make(map[string]bool)
+		visited  = make(map[string]bool)
+		visit    func(table string, chain []string) error
+	)
+	visit = func(table string, chain []string) error {
+		if visited[table] {
+			return nil
+		}
+		if visiting[table] {
+			return fmt.Errorf("juicetest: fixture dependency cycle: %s", strings.Join(append(chain, table), " -> "))
+		}
+		visiting[table] = true
+		for _, reference := range dependencies[table] {
+			if err := visit(reference, append(chain, table)); err != nil {
+				return err
+			}
+		}
+		visited[table] = true
+		if _, ok := fixtures[table]; ok {
+			order = append(order, table)
+		}
+		return nil
+	}
+	for _, table := range slices.Sorted(maps.Keys(fixtures)) {
+		if err := visit(table, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// fixtureStatement is the insert statement generated for the rows of a fixture,
+// executed in batches of fixtureBatchSize rows.
+type fixtureStatement struct {
+	table   string
+	columns []string
+}
+
+// ID implements juice.StatementMetadata.
+func (s fixtureStatement) ID() string { return "juicetest.fixtures." + s.table }
+
+// Name implements juice.StatementMetadata.
+func (s fixtureStatement) Name() string { return s.ID() }
+
+// Attribute implements juice.StatementMetadata.
+func (s fixtureStatement) Attribute(key string) string {
+	if key == "batchSize" {
+		return strconv.Itoa(fixtureBatchSize)
+	}
+	return ""
+}
+
+// Action implements juice.Statement.
+func (s fixtureStatement) Action() sql.Action { return sql.Insert }
+
+// ResultMap implements juice.Statement.
+func (s fixtureStatement) ResultMap() (sql.ResultMap, error) { return nil, sql.ErrResultMapNotSet }
+
+// Build implements juice.StatementBuilder. It renders the rows of the batch, the elements
+// of the slice parameter, in a single insert.
+func (s fixtureStatement) Build(translator driver.Translator, parameter eval.Parameter) (string, []any, error) {
+	var rows []any
+	for index := 0; ; index++ {
+		row, exists := parameter.Get(strconv.Itoa(index))
+		if !exists {
+			break
+		}
+		rows = append(rows, row.Interface())
+	}
+	values := make([]string, len(s.columns))
+	for i, column := range s.columns {
+		values[i] = "#{row." + column + "}"
+	}
+	root := node.Group{
+		node.NewTextNode("INSERT INTO " + s.table + " (" + strings.Join(s.columns, ", ") + ") VALUES"),
+		node.ForeachNode{
+			Collection: "rows",
+			Item:       "row",
+			Separator:  ", ",
+			Nodes:      []node.Node{node.NewTextNode("(" + strings.Join(values, ", ") + ")")},
+		},
+	}
+	return root.Accept(translator, eval.H{"rows": rows})
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juicetest
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadFixtures(t *testing.T) {
+	engine, mock := newMockEngine(t, t.Name())
+	fsys := fstest.MapFS{
+		"fixtures/users.yml": {Data: []byte(`# the users
+- id: 1
+  name: eat # inline comment
+- id: 2
+  name: "more #2"
+  email: ~
+`)},
+		"fixtures/orders.csv":       {Data: []byte("id,user_id,note\n10,1,\\N\n11,2,gift\n")},
+		"fixtures/dependencies.txt": {Data: []byte("orders: users\n")},
+		"fixtures/README.md":        {Data: []byte("ignored")},
+	}
+
+	mock.ExpectExec("DELETE FROM orders")
+	mock.ExpectExec("DELETE FROM users")
+	mock.ExpectExec("juicetest.fixtures.users").WithArgs(nil, int64(1), "eat", nil, int64(2), "more #2").WillReturnResult(0, 2)
+	mock.ExpectExec("juicetest.fixtures.orders").WithArgs("10", "1", nil, "11", "2", "gift").WillReturnResult(0, 2)
+
+	if err := LoadFixtures(t.Context(), engine, fsys, "fixtures"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fsys["fixtures/dependencies.txt"] = &fstest.MapFile{Data: []byte("orders: users\nusers: orders\n")}
+	if err := LoadFixtures(t.Context(), engine, fsys, "fixtures"); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("expected dependency cycle error, got %v", err)
+	}
+}
+
+func TestLoadFixturesError(t *testing.T) {
+	engine, mock := newMockEngine(t, t.Name())
+	fsys := fstest.MapFS{"fixtures/users.csv": {Data: []byte("id\n1\n")}}
+
+	failure := errors.New("constraint violation")
+	mock.ExpectExec("DELETE FROM users").WillReturnError(failure)
+	if err := LoadFixtures(t.Context(), engine, fsys, "fixtures"); !errors.Is(err, failure) {
+		t.Fatalf("expected the delete error, got %v", err)
+	}
+
+	fsys["fixtures/users.yml"] = &fstest.MapFile{Data: []byte("- id: 1\n")}
+	if err := LoadFixtures(t.Context(), engine, fsys, "fixtures"); err == nil || !strings.Contains(err.Error(), "several fixtures") {
+		t.Fatalf("expected duplicate table error, got %v", err)
+	}
+}
+
+func TestParseYAMLFixture(t *testing.T) {
+	fixture, err := parseYAMLFixture([]byte("---\n- id: 1\n  active: true\n  score: 1.5\n  quote: 'it''s'\n-\n  id: 2\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(fixture.columns, []string{"active", "id", "quote", "score"}) || len(fixture.rows) != 2 {
+		t.Fatalf("unexpected fixture %+v", fixture)
+	}
+	if row := fixture.rows[0]; row["active"] != true || row["score"] != 1.5 || row["quote"] != "it's" {
+		t.Fatalf("unexpected row %+v", row)
+	}
+	if row := fixture.rows[1]; row["id"] != int64(2) || row["active"] != nil {
+		t.Fatalf("unexpected row %+v", row)
+	}
+
+	for _, data := range []string{"id: 1\n", "- id 1\n", "- id: 1\n  id: 2\n", "- name: \"open\n"} {
+		if _, err = parseYAMLFixture([]byte(data)); err == nil {
+			t.Fatalf("expected error for %q", data)
+		}
+	}
+}
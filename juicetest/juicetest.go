@@ -0,0 +1,177 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package juicetest provides helpers to test the statements of juice configurations.
+//
+// Snapshot renders every statement of a configuration and compares it to a golden file,
+// which makes the refactors of mapper XML safe:
+//
+//	func TestStatements(t *testing.T) {
+//	    configuration, err := juice.NewXMLConfiguration("juice.xml")
+//	    if err != nil {
+//	        t.Fatal(err)
+//	    }
+//	    juicetest.Snapshot{
+//	        Configuration: configuration,
+//	        Driver:        driver.MySQLDriver{},
+//	        Fixtures:      map[string]any{"user.GetByID": juice.H{"id": 1}},
+//	    }.Run(t)
+//	}
+//
+// The golden files are written by running the tests with JUICE_UPDATE_GOLDEN=true.
+//
+// Mock is an in-memory database whose statements are programmed by statement name,
+// to test the code using juice without a database.
+package juicetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-juicedev/juice"
+	"github.com/go-juicedev/juice/driver"
+)
+
+// UpdateEnv is the environment variable which makes Snapshot write the golden files
+// instead of comparing them, when set to a true value.
+const UpdateEnv = "JUICE_UPDATE_GOLDEN"
+
+// DefaultDir is the directory of the golden files when Snapshot.Dir is empty.
+const DefaultDir = "testdata/golden"
+
+// ErrStatementsNotListed is returned when the configuration cannot list its statements.
+var ErrStatementsNotListed = errors.New("juicetest: configuration does not list its statements")
+
+// Snapshot renders the statements of a configuration and compares them to golden files.
+type Snapshot struct {
+	// Configuration holds the statements to render.
+	// It must implement juice.StatementLister, like the configurations created by juice.
+	Configuration juice.Configuration
+
+	// Driver renders the placeholders of the statements and selects their databaseId variant.
+	Driver driver.Driver
+
+	// Fixtures are the parameters of the statements, keyed by their fully qualified name.
+	// The statements without a fixture are rendered without parameter.
+	Fixtures map[string]any
+
+	// Dir is the directory of the golden files, DefaultDir if empty.
+	// The golden file of a statement is named after it, like user.GetByID.sql.
+	Dir string
+
+	// Update writes the golden files instead of comparing them.
+	// It is also enabled by the UpdateEnv environment variable.
+	Update bool
+}
+
+// Run checks every statement in a subtest named after it.
+func (s Snapshot) Run(t *testing.T) {
+	t.Helper()
+	lister, ok := s.Configuration.(juice.StatementLister)
+	if !ok {
+		t.Fatalf("%v: %T", ErrStatementsNotListed, s.Configuration)
+	}
+	for _, name := range lister.StatementNames() {
+		t.Run(name, func(t *testing.T) {
+			if err := s.Check(t.Context(), name); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// Check renders the statement name and compares it to its golden file,
+// or writes the golden file in update mode.
+// It returns an error showing the difference when they do not match.
+func (s Snapshot) Check(ctx context.Context, name string) error {
+	got, err := s.Render(ctx, name)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(s.dir(), name+".sql")
+	if s.update() {
+		if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, []byte(got), 0o644)
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("golden file of %s not found, run the tests with %s=true to write it: %w", name, UpdateEnv, err)
+		}
+		return err
+	}
+	if string(want) != got {
+		return fmt.Errorf("%s does not match %s (-want +got):\n%s", name, path, diff(string(want), got))
+	}
+	return nil
+}
+
+// Render renders the statement name with its fixture in the format of the golden files:
+// the query with one line per non-empty line of the statement, followed by its arguments.
+func (s Snapshot) Render(ctx context.Context, name string) (string, error) {
+	query, args, err := juice.RenderStatement(ctx, s.Configuration, s.Driver, name, s.Fixtures[name])
+	if err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	var builder strings.Builder
+	builder.WriteString("-- ")
+	builder.WriteString(name)
+	builder.WriteByte('\n')
+	for line := range strings.Lines(query) {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			builder.WriteString(strings.Join(fields, " "))
+			builder.WriteByte('\n')
+		}
+	}
+	builder.WriteString("-- args: ")
+	builder.WriteString(formatArgs(args))
+	builder.WriteByte('\n')
+	return builder.String(), nil
+}
+
+// dir returns the directory of the golden files.
+func (s Snapshot) dir() string {
+	if s.Dir == "" {
+		return DefaultDir
+	}
+	return s.Dir
+}
+
+// update reports whether the golden files are written.
+func (s Snapshot) update() bool {
+	if s.Update {
+		return true
+	}
+	update, _ := strconv.ParseBool(os.Getenv(UpdateEnv))
+	return update
+}
+
+// formatArgs formats args as Go values, so that their types are visible.
+func formatArgs(args []any) string {
+	values := make([]string, len(args))
+	for i, arg := range args {
+		values[i] = fmt.Sprintf("%#v", arg)
+	}
+	return "[" + strings.Join(values, ", ") + "]"
+}
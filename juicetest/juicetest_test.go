@@ -0,0 +1,107 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juicetest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-juicedev/juice"
+	"github.com/go-juicedev/juice/driver"
+)
+
+func newTestConfiguration(t *testing.T) juice.Configuration {
+	t.Helper()
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>sqlite.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>
+		<mapper namespace="user">
+			<select id="Find">
+				SELECT id, name FROM user
+				<where>
+					<if test="id > 0">AND id = #{id}</if>
+					<if test='name != ""'>AND name = #{name}</if>
+				</where>
+			</select>
+			<insert id="Save">INSERT INTO user(name) VALUES (#{name})</insert>
+		</mapper>
+	</mappers>
+</configuration>`)},
+	}
+	configuration, err := juice.NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return configuration
+}
+
+func TestSnapshot(t *testing.T) {
+	Snapshot{
+		Configuration: newTestConfiguration(t),
+		Driver:        driver.PostgresDriver{},
+		Fixtures: map[string]any{
+			"user.Find": juice.H{"id": 1, "name": "eat"},
+			"user.Save": juice.H{"name": "more"},
+		},
+	}.Run(t)
+}
+
+func TestSnapshotCheck(t *testing.T) {
+	snapshot := Snapshot{
+		Configuration: newTestConfiguration(t),
+		Driver:        driver.MySQLDriver{},
+		Fixtures:      map[string]any{"user.Find": juice.H{"id": 1, "name": ""}},
+		Dir:           t.TempDir(),
+	}
+	if err := snapshot.Check(t.Context(), "user.Find"); err == nil || !strings.Contains(err.Error(), UpdateEnv) {
+		t.Fatalf("expected a missing golden file error, got %v", err)
+	}
+
+	snapshot.Update = true
+	if err := snapshot.Check(t.Context(), "user.Find"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	golden, err := os.ReadFile(filepath.Join(snapshot.Dir, "user.Find.sql"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "-- user.Find\nSELECT id, name FROM user\nWHERE id = ?\n-- args: [1]\n"; string(golden) != want {
+		t.Fatalf("unexpected golden file %q", golden)
+	}
+
+	snapshot.Update = false
+	snapshot.Fixtures["user.Find"] = juice.H{"id": 1, "name": "eat"}
+	err = snapshot.Check(t.Context(), "user.Find")
+	if err == nil {
+		t.Fatal("expected a mismatch")
+	}
+	for _, line := range []string{"- WHERE id = ?", "+ WHERE id = ? AND name = ?", `+ -- args: [1, "eat"]`} {
+		if !strings.Contains(err.Error(), line) {
+			t.Errorf("expected the diff to contain %q, got:\n%v", line, err)
+		}
+	}
+}
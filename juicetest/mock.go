@@ -0,0 +1,254 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juicetest
+
+import (
+	"context"
+	stdsql "database/sql"
+	sqldriver "database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-juicedev/juice"
+)
+
+var (
+	// ErrUnexpectedStatement is returned by a Mock executing a statement it does not expect.
+	ErrUnexpectedStatement = errors.New("juicetest: unexpected statement")
+
+	// ErrArgsMismatch is returned by a Mock executing an expected statement with other arguments.
+	ErrArgsMismatch = errors.New("juicetest: arguments mismatch")
+
+	// ErrMockExists is returned when creating a mock for the dataSource of another mock.
+	ErrMockExists = errors.New("juicetest: mock already exists")
+
+	// ErrMockNotFound is returned when connecting to a dataSource without mock.
+	ErrMockNotFound = errors.New("juicetest: mock not found")
+)
+
+// mocks holds the opened mocks, keyed by dataSource.
+var mocks sync.Map
+
+// anyArg is the type of AnyArg.
+type anyArg struct{}
+
+// AnyArg matches any argument in Expectation.WithArgs.
+var AnyArg = anyArg{}
+
+// Mock is an in-memory database session whose statements are programmed with
+// expectations keyed by statement name, to test the code using juice without a database.
+//
+// A Mock is a session.Session. The engines whose environment uses the juicetest driver
+// with the dataSource of a mock execute their statements against it:
+//
+//	<environment id="prod">
+//	    <dataSource>users</dataSource>
+//	    <driver>juicetest</driver>
+//	</environment>
+//
+//	mock, err := juicetest.NewMock("users")
+//	...
+//	mock.ExpectQuery("user.GetByID").WithArgs(1).WillReturnRows([]string{"id", "name"}, []any{1, "eat"})
+type Mock struct {
+	*stdsql.DB
+	dataSource string
+
+	mu           sync.Mutex
+	expectations []*Expectation
+}
+
+// NewMock creates the mock of dataSource.
+// It returns ErrMockExists if dataSource already has an open mock.
+func NewMock(dataSource string) (*Mock, error) {
+	mock := &Mock{dataSource: dataSource}
+	if _, loaded := mocks.LoadOrStore(dataSource, mock); loaded {
+		return nil, fmt.Errorf("%w: %s", ErrMockExists, dataSource)
+	}
+	db, err := stdsql.Open(MockDriverName, dataSource)
+	if err != nil {
+		mocks.Delete(dataSource)
+		return nil, err
+	}
+	mock.DB = db
+	return mock, nil
+}
+
+// Close closes the mock, so that its dataSource can be mocked again.
+func (m *Mock) Close() error {
+	mocks.CompareAndDelete(m.dataSource, m)
+	return m.DB.Close()
+}
+
+// ExpectQuery expects a query of the statement, which is either the fully qualified
+// name of a mapped statement, like user.GetByID, or the query of a raw statement.
+func (m *Mock) ExpectQuery(statement string) *Expectation {
+	return m.expect(statement, true)
+}
+
+// ExpectExec expects the execution of the statement, which is either the fully qualified
+// name of a mapped statement, like user.Update, or the query of a raw statement.
+func (m *Mock) ExpectExec(statement string) *Expectation {
+	return m.expect(statement, false)
+}
+
+// expect registers an expectation of statement.
+func (m *Mock) expect(statement string, query bool) *Expectation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	expectation := &Expectation{statement: statement, query: query}
+	m.expectations = append(m.expectations, expectation)
+	return expectation
+}
+
+// ExpectationsWereMet returns an error listing the expectations which were not met.
+func (m *Mock) ExpectationsWereMet() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var unmet []string
+	for _, expectation := range m.expectations {
+		if !expectation.met {
+			unmet = append(unmet, expectation.String())
+		}
+	}
+	if len(unmet) == 0 {
+		return nil
+	}
+	return fmt.Errorf("juicetest: expectations were not met:\n\t%s", strings.Join(unmet, "\n\t"))
+}
+
+// match meets the first expectation of the statement of ctx whose arguments match, in the order they were registered.
+func (m *Mock) match(ctx context.Context, query bool, text string, args []sqldriver.NamedValue) (*Expectation, error) {
+	name := text
+	if statement, ok := juice.StatementFromContext(ctx); ok {
+		name = statement.Name()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var mismatch error
+	for _, expectation := range m.expectations {
+		if expectation.met || expectation.query != query {
+			continue
+		}
+		if expectation.statement != name && expectation.statement != text {
+			continue
+		}
+		if err := expectation.matchArgs(args); err != nil {
+			// the expectation stays unmet, another one of the statement may expect these arguments.
+			if mismatch == nil {
+				mismatch = fmt.Errorf("%w of %s: %w", ErrArgsMismatch, name, err)
+			}
+			continue
+		}
+		expectation.met = true
+		return expectation, expectation.err
+	}
+	if mismatch != nil {
+		return nil, mismatch
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnexpectedStatement, name)
+}
+
+// Expectation is a statement expected by a Mock, with what it returns.
+// An expectation is met once.
+type Expectation struct {
+	statement string
+	query     bool
+	args      []any
+	checkArgs bool
+
+	columns      []string
+	rows         [][]sqldriver.Value
+	lastInsertID int64
+	rowsAffected int64
+	err          error
+
+	met bool
+}
+
+// WithArgs expects the statement to be executed with args, in order.
+// AnyArg matches any argument. The arguments are not checked without WithArgs.
+func (e *Expectation) WithArgs(args ...any) *Expectation {
+	e.args, e.checkArgs = args, true
+	return e
+}
+
+// WillReturnRows makes the query return rows of columns.
+func (e *Expectation) WillReturnRows(columns []string, rows ...[]any) *Expectation {
+	e.columns = columns
+	e.rows = make([][]sqldriver.Value, len(rows))
+	for i, row := range rows {
+		e.rows[i] = make([]sqldriver.Value, len(row))
+		for j, value := range row {
+			converted, err := sqldriver.DefaultParameterConverter.ConvertValue(value)
+			if err != nil {
+				panic(fmt.Sprintf("juicetest: invalid value %v of row %d: %v", value, i, err))
+			}
+			e.rows[i][j] = converted
+		}
+	}
+	return e
+}
+
+// WillReturnResult makes the execution return lastInsertID and rowsAffected.
+func (e *Expectation) WillReturnResult(lastInsertID, rowsAffected int64) *Expectation {
+	e.lastInsertID, e.rowsAffected = lastInsertID, rowsAffected
+	return e
+}
+
+// WillReturnError makes the statement fail with err.
+func (e *Expectation) WillReturnError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// String describes the expectation.
+func (e *Expectation) String() string {
+	kind := "exec"
+	if e.query {
+		kind = "query"
+	}
+	if !e.checkArgs {
+		return kind + " " + e.statement
+	}
+	return fmt.Sprintf("%s %s with args %v", kind, e.statement, e.args)
+}
+
+// matchArgs checks the arguments received by the statement.
+func (e *Expectation) matchArgs(args []sqldriver.NamedValue) error {
+	if !e.checkArgs {
+		return nil
+	}
+	if len(args) != len(e.args) {
+		return fmt.Errorf("expected %d arguments, got %d", len(e.args), len(args))
+	}
+	for i, want := range e.args {
+		if want == AnyArg {
+			continue
+		}
+		want, err := sqldriver.DefaultParameterConverter.ConvertValue(want)
+		if err != nil {
+			return fmt.Errorf("invalid expected argument %d: %w", i, err)
+		}
+		if !reflect.DeepEqual(want, args[i].Value) {
+			return fmt.Errorf("argument %d: expected %#v, got %#v", i, want, args[i].Value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juicetest
+
+import (
+	"context"
+	stdsql "database/sql"
+	sqldriver "database/sql/driver"
+	"fmt"
+	"io"
+
+	"github.com/go-juicedev/juice/driver"
+)
+
+// MockDriverName is the name of the driver of the mocks, for the environments using them.
+const MockDriverName = "juicetest"
+
+func init() {
+	stdsql.Register(MockDriverName, mockDriver{})
+	driver.Register(MockDriverName, MockDriver{})
+}
+
+// MockDriver is the juice driver of the mocks. Its placeholders are ?.
+type MockDriver struct{}
+
+// Translator implements driver.Driver.
+func (MockDriver) Translator() driver.Translator {
+	return driver.TranslateFunc(func(_ string) string { return "?" })
+}
+
+// Name implements driver.Driver.
+func (MockDriver) Name() string { return MockDriverName }
+
+// mockDriver is the database/sql driver connecting to the mocks.
+type mockDriver struct{}
+
+// Open implements sqldriver.Driver.
+func (mockDriver) Open(dataSource string) (sqldriver.Conn, error) {
+	mock, ok := mocks.Load(dataSource)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMockNotFound, dataSource)
+	}
+	return &mockConn{mock: mock.(*Mock)}, nil
+}
+
+// mockConn is a connection to a mock.
+type mockConn struct {
+	mock *Mock
+}
+
+var (
+	_ sqldriver.QueryerContext     = (*mockConn)(nil)
+	_ sqldriver.ExecerContext      = (*mockConn)(nil)
+	_ sqldriver.ConnPrepareContext = (*mockConn)(nil)
+	_ sqldriver.ConnBeginTx        = (*mockConn)(nil)
+)
+
+// Prepare implements sqldriver.Conn.
+func (c *mockConn) Prepare(query string) (sqldriver.Stmt, error) {
+	return &mockStmt{mock: c.mock, query: query}, nil
+}
+
+// PrepareContext implements sqldriver.ConnPrepareContext.
+func (c *mockConn) PrepareContext(_ context.Context, query string) (sqldriver.Stmt, error) {
+	return c.Prepare(query)
+}
+
+// Close implements sqldriver.Conn.
+func (c *mockConn) Close() error { return nil }
+
+// Begin implements sqldriver.Conn.
+func (c *mockConn) Begin() (sqldriver.Tx, error) { return mockTx{}, nil }
+
+// BeginTx implements sqldriver.ConnBeginTx.
+func (c *mockConn) BeginTx(_ context.Context, _ sqldriver.TxOptions) (sqldriver.Tx, error) {
+	return mockTx{}, nil
+}
+
+// QueryContext implements sqldriver.QueryerContext.
+func (c *mockConn) QueryContext(ctx context.Context, query string, args []sqldriver.NamedValue) (sqldriver.Rows, error) {
+	return mockQuery(ctx, c.mock, query, args)
+}
+
+// ExecContext implements sqldriver.ExecerContext.
+func (c *mockConn) ExecContext(ctx context.Context, query string, args []sqldriver.NamedValue) (sqldriver.Result, error) {
+	return mockExec(ctx, c.mock, query, args)
+}
+
+// mockStmt is a prepared statement of a mock.
+type mockStmt struct {
+	mock  *Mock
+	query string
+}
+
+var (
+	_ sqldriver.StmtQueryContext = (*mockStmt)(nil)
+	_ sqldriver.StmtExecContext  = (*mockStmt)(nil)
+)
+
+// Close implements sqldriver.Stmt.
+func (s *mockStmt) Close() error { return nil }
+
+// NumInput implements sqldriver.Stmt.
+func (s *mockStmt) NumInput() int { return -1 }
+
+// Exec implements sqldriver.Stmt.
+func (s *mockStmt) Exec(args []sqldriver.Value) (sqldriver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+// Query implements sqldriver.Stmt.
+func (s *mockStmt) Query(args []sqldriver.Value) (sqldriver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+// ExecContext implements sqldriver.StmtExecContext.
+func (s *mockStmt) ExecContext(ctx context.Context, args []sqldriver.NamedValue) (sqldriver.Result, error) {
+	return mockExec(ctx, s.mock, s.query, args)
+}
+
+// QueryContext implements sqldriver.StmtQueryContext.
+func (s *mockStmt) QueryContext(ctx context.Context, args []sqldriver.NamedValue) (sqldriver.Rows, error) {
+	return mockQuery(ctx, s.mock, s.query, args)
+}
+
+// mockQuery returns the rows of the query expectation matching the query.
+func mockQuery(ctx context.Context, mock *Mock, query string, args []sqldriver.NamedValue) (sqldriver.Rows, error) {
+	expectation, err := mock.match(ctx, true, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &mockRows{columns: expectation.columns, rows: expectation.rows}, nil
+}
+
+// mockExec returns the result of the exec expectation matching the query.
+func mockExec(ctx context.Context, mock *Mock, query string, args []sqldriver.NamedValue) (sqldriver.Result, error) {
+	expectation, err := mock.match(ctx, false, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return mockResult{lastInsertID: expectation.lastInsertID, rowsAffected: expectation.rowsAffected}, nil
+}
+
+// namedValues converts the positional arguments of the legacy interfaces.
+func namedValues(args []sqldriver.Value) []sqldriver.NamedValue {
+	named := make([]sqldriver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = sqldriver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+// mockRows are the rows returned by an expectation.
+type mockRows struct {
+	columns []string
+	rows    [][]sqldriver.Value
+	index   int
+}
+
+// Columns implements sqldriver.Rows.
+func (r *mockRows) Columns() []string { return r.columns }
+
+// Close implements sqldriver.Rows.
+func (r *mockRows) Close() error { return nil }
+
+// Next implements sqldriver.Rows.
+func (r *mockRows) Next(dest []sqldriver.Value) error {
+	if r.index >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.index])
+	r.index++
+	return nil
+}
+
+// mockResult is the result returned by an expectation.
+type mockResult struct {
+	lastInsertID, rowsAffected int64
+}
+
+// LastInsertId implements sqldriver.Result.
+func (r mockResult) LastInsertId() (int64, error) { return r.lastInsertID, nil }
+
+// RowsAffected implements sqldriver.Result.
+func (r mockResult) RowsAffected() (int64, error) { return r.rowsAffected, nil }
+
+// mockTx is a transaction of a mock, which has no effect.
+type mockTx struct{}
+
+// Commit implements sqldriver.Tx.
+func (mockTx) Commit() error { return nil }
+
+// Rollback implements sqldriver.Tx.
+func (mockTx) Rollback() error { return nil }
@@ -0,0 +1,141 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juicetest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-juicedev/juice"
+)
+
+type mockUser struct {
+	ID   int64  `column:"id"`
+	Name string `column:"name"`
+}
+
+func newMockEngine(t *testing.T, dataSource string) (*juice.Engine, *Mock) {
+	t.Helper()
+	mock, err := NewMock(dataSource)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = mock.Close() })
+
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>` + dataSource + `</dataSource>
+			<driver>juicetest</driver>
+		</environment>
+	</environments>
+	<mappers>
+		<mapper namespace="user">
+			<select id="GetByID">SELECT id, name FROM user WHERE id = #{id}</select>
+			<update id="Rename">UPDATE user SET name = #{name} WHERE id = #{id}</update>
+		</mapper>
+	</mappers>
+</configuration>`)},
+	}
+	configuration, err := juice.NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	engine, err := juice.New(configuration)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return engine, mock
+}
+
+func TestMock(t *testing.T) {
+	engine, mock := newMockEngine(t, t.Name())
+	ctx := t.Context()
+
+	mock.ExpectQuery("user.GetByID").WithArgs(1).WillReturnRows([]string{"id", "name"}, []any{1, "eat"})
+	mock.ExpectExec("user.Rename").WithArgs("more", AnyArg).WillReturnResult(0, 1)
+	mock.ExpectQuery("SELECT COUNT(*) FROM user").WillReturnRows([]string{"count"}, []any{2})
+
+	user, err := juice.NewGenericManager[mockUser](engine).Object("user.GetByID").QueryContext(ctx, juice.H{"id": 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.ID != 1 || user.Name != "eat" {
+		t.Fatalf("unexpected user %+v", user)
+	}
+
+	if err = mock.ExpectationsWereMet(); err == nil || !strings.Contains(err.Error(), "exec user.Rename") {
+		t.Fatalf("expected user.Rename to be unmet, got %v", err)
+	}
+
+	result, err := engine.Object("user.Rename").ExecContext(ctx, juice.H{"id": 1, "name": "more"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if affected, _ := result.RowsAffected(); affected != 1 {
+		t.Fatalf("expected 1 row affected, got %d", affected)
+	}
+
+	rows, err := engine.Raw("SELECT COUNT(*) FROM user").Select(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = rows.Close()
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMockUnexpected(t *testing.T) {
+	engine, mock := newMockEngine(t, t.Name())
+	ctx := t.Context()
+
+	if _, err := engine.Object("user.Rename").ExecContext(ctx, juice.H{"id": 1, "name": "more"}); !errors.Is(err, ErrUnexpectedStatement) {
+		t.Fatalf("expected ErrUnexpectedStatement, got %v", err)
+	}
+
+	mock.ExpectExec("user.Rename").WithArgs("less", 1)
+	if _, err := engine.Object("user.Rename").ExecContext(ctx, juice.H{"id": 1, "name": "more"}); !errors.Is(err, ErrArgsMismatch) {
+		t.Fatalf("expected ErrArgsMismatch, got %v", err)
+	}
+	// the mismatched expectation is still expected.
+	if err := mock.ExpectationsWereMet(); err == nil || !strings.Contains(err.Error(), "exec user.Rename") {
+		t.Fatalf("expected user.Rename to be unmet, got %v", err)
+	}
+	mock.ExpectExec("user.Rename").WithArgs("more", 1)
+	if _, err := engine.Object("user.Rename").ExecContext(ctx, juice.H{"id": 1, "name": "more"}); err != nil {
+		t.Fatalf("expected the second expectation to match, got %v", err)
+	}
+	if _, err := engine.Object("user.Rename").ExecContext(ctx, juice.H{"id": 1, "name": "less"}); err != nil {
+		t.Fatalf("expected the first expectation to match, got %v", err)
+	}
+
+	failure := errors.New("connection reset")
+	mock.ExpectQuery("user.GetByID").WillReturnError(failure)
+	if _, err := engine.Object("user.GetByID").QueryContext(ctx, juice.H{"id": 1}); !errors.Is(err, failure) {
+		t.Fatalf("expected the programmed error, got %v", err)
+	}
+
+	if _, err := NewMock(t.Name()); !errors.Is(err, ErrMockExists) {
+		t.Fatalf("expected ErrMockExists, got %v", err)
+	}
+}
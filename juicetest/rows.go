@@ -0,0 +1,85 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juicetest
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/go-juicedev/juice"
+	"github.com/go-juicedev/juice/sql"
+)
+
+// RowsTracker is a juice.RowsHook recording the rows which are not released yet,
+// to detect the rows which are never closed.
+type RowsTracker struct {
+	mu   sync.Mutex
+	open map[sql.Rows]string
+}
+
+// RowsOpened implements juice.RowsHook.
+func (r *RowsTracker) RowsOpened(_ context.Context, statement juice.Statement, rows sql.Rows) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.open == nil {
+		r.open = make(map[sql.Rows]string)
+	}
+	r.open[rows] = statement.Name()
+}
+
+// RowsReleased implements juice.RowsHook.
+func (r *RowsTracker) RowsReleased(_ context.Context, _ juice.Statement, rows sql.Rows) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.open, rows)
+}
+
+// Unreleased returns the names of the statements whose rows are not released, sorted.
+func (r *RowsTracker) Unreleased() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.open))
+	for _, name := range r.open {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+var _ juice.RowsHook = (*RowsTracker)(nil)
+
+// TrackRows sets a RowsTracker as the juice.RowsHook of engine and makes t fail
+// at cleanup if some rows are not released:
+//
+//	func TestUserRepository(t *testing.T) {
+//	    engine := newEngine(t)
+//	    juicetest.TrackRows(t, engine)
+//	    ...
+//	}
+func TrackRows(t testing.TB, engine *juice.Engine) *RowsTracker {
+	t.Helper()
+	tracker := &RowsTracker{}
+	engine.SetRowsHook(tracker)
+	t.Cleanup(func() {
+		if unreleased := tracker.Unreleased(); len(unreleased) > 0 {
+			t.Errorf("juicetest: rows of %v are not closed", unreleased)
+		}
+	})
+	return tracker
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juicetest
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/go-juicedev/juice"
+)
+
+func TestTrackRows(t *testing.T) {
+	engine, mock := newMockEngine(t, t.Name())
+	tracker := TrackRows(t, engine)
+	ctx := t.Context()
+
+	mock.ExpectQuery("user.GetByID").WillReturnRows([]string{"id", "name"}, []any{1, "eat"})
+	mock.ExpectQuery("user.GetByID").WillReturnRows([]string{"id", "name"}, []any{1, "eat"})
+
+	if _, err := juice.NewGenericManager[mockUser](engine).Object("user.GetByID").QueryContext(ctx, juice.H{"id": 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if unreleased := tracker.Unreleased(); len(unreleased) != 0 {
+		t.Fatalf("expected the bound rows to be released, got %v", unreleased)
+	}
+
+	rows, err := engine.Object("user.GetByID").QueryContext(ctx, juice.H{"id": 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if unreleased := tracker.Unreleased(); !slices.Equal(unreleased, []string{"user.GetByID"}) {
+		t.Fatalf("expected the open rows to be tracked, got %v", unreleased)
+	}
+	_ = rows.Close()
+	if unreleased := tracker.Unreleased(); len(unreleased) != 0 {
+		t.Fatalf("expected the closed rows to be released, got %v", unreleased)
+	}
+}
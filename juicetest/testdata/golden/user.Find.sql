@@ -0,0 +1,4 @@
+-- user.Find
+SELECT id, name FROM user
+WHERE id = $1 AND name = $2
+-- args: [1, "eat"]
@@ -0,0 +1,3 @@
+-- user.Save
+INSERT INTO user(name) VALUES ($1)
+-- args: ["more"]
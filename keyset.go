@@ -0,0 +1,548 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	sqldriver "database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/sql"
+)
+
+var (
+	// ErrKeysetOrderMismatch is returned when the ORDER BY clause of a keyset query does not match its keys.
+	ErrKeysetOrderMismatch = errors.New("juice: ORDER BY does not match the keyset keys")
+
+	// ErrKeysetUnsupported is returned when a query cannot be paginated by keyset.
+	ErrKeysetUnsupported = errors.New("juice: unsupported keyset query")
+
+	// ErrInvalidCursor is returned when the cursor of a keyset page cannot be decoded.
+	ErrInvalidCursor = errors.New("juice: invalid keyset cursor")
+)
+
+// KeysetKey is a sort key of a keyset pagination.
+type KeysetKey struct {
+	// Column is the column sorting the rows as written in the ORDER BY clause, like "created_at" or "u.id".
+	// The rows must select it under its name without qualifier.
+	Column string
+
+	// Desc reports whether the rows are sorted in descending order.
+	Desc bool
+}
+
+// Keyset requests a page of a keyset pagination.
+type Keyset struct {
+	// Keys are the sort keys of the rows, which must identify them, like a creation time
+	// followed by the primary key. Their values must not be NULL.
+	Keys []KeysetKey
+
+	// Limit is the maximum number of rows of the page.
+	Limit int
+
+	// Cursor is the Next cursor of the previous page, or empty for the first page.
+	Cursor string
+
+	// Total reports whether the page counts the rows of all the pages with the count variant
+	// of the statement, see CountVariant.
+	Total bool
+}
+
+// KeysetPage is a page of a keyset pagination.
+type KeysetPage[T any] struct {
+	// Items are the rows of the page.
+	Items []T
+
+	// Next is the opaque cursor of the next page, or empty if the page is the last one.
+	Next string
+
+	// Total is the number of rows of all the pages, if requested by Keyset.Total.
+	Total int64
+}
+
+// QueryKeyset executes the select statement through manager, like an Engine or a transaction,
+// and returns the page of its rows following the cursor of keyset, bound to T.
+//
+// Unlike LIMIT and OFFSET, the rows before the page are not read: the statement must sort its rows
+// by the keys of keyset, and the query is rewritten to only match the rows after the last row of
+// the previous page, like (created_at, id) > (?, ?), and to fetch at most one row more than the limit,
+// which tells whether there is a next page.
+//
+//	<select id="List">
+//	    SELECT id, name, created_at FROM user
+//	    <where>
+//	        <if test="status != 0">status = #{status}</if>
+//	    </where>
+//	    ORDER BY created_at DESC, id DESC
+//	</select>
+//
+//	keyset := juice.Keyset{Keys: []juice.KeysetKey{{Column: "created_at", Desc: true}, {Column: "id", Desc: true}}, Limit: 20}
+//	page, err := juice.QueryKeyset[User](ctx, engine, "user.List", param, keyset)
+//	// ...
+//	keyset.Cursor = page.Next
+//
+// It returns ErrKeysetOrderMismatch if the ORDER BY clause of the query does not match the keys,
+// and ErrKeysetUnsupported if the query is already limited or is a compound query, like a UNION.
+func QueryKeyset[T any](ctx context.Context, manager Manager, statement, param any, keyset Keyset) (*KeysetPage[T], error) {
+	if len(keyset.Keys) == 0 || keyset.Limit <= 0 {
+		return nil, fmt.Errorf("%w: keys and a positive limit are required", ErrKeysetUnsupported)
+	}
+	values, err := decodeCursor(keyset.Cursor, len(keyset.Keys))
+	if err != nil {
+		return nil, err
+	}
+	query := &keysetQuery{keyset: keyset, values: values}
+	rows, err := manager.Object(statement).QueryContext(context.WithValue(ctx, keysetQueryKey{}, query), param)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	if !query.applied.Load() {
+		return nil, fmt.Errorf("%w: the statement was not executed by an engine", ErrKeysetUnsupported)
+	}
+
+	var (
+		indexes []int
+		last    []any
+		count   int
+	)
+	rows = sql.WithScanHook(rows, func(columns []string, dest []any) (err error) {
+		if indexes == nil {
+			if indexes, err = keysetIndexes(keyset.Keys, columns); err != nil {
+				return err
+			}
+		}
+		if count++; count == keyset.Limit {
+			last, err = keysetValues(indexes, dest)
+		}
+		return err
+	})
+	items, err := sql.List[T](rows)
+	if err != nil {
+		return nil, err
+	}
+	if err = afterScan(ctx, &items); err != nil {
+		return nil, err
+	}
+	page := &KeysetPage[T]{Items: items}
+	if len(items) > keyset.Limit {
+		page.Items = items[:keyset.Limit]
+		if page.Next, err = encodeCursor(last); err != nil {
+			return nil, err
+		}
+	}
+	if keyset.Total {
+		if page.Total, err = Count(ctx, manager, statement, param); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+type keysetQueryKey struct{}
+
+// keysetQuery is the keyset pagination of a query, carried by its context.
+type keysetQuery struct {
+	keyset Keyset
+
+	// values are the key values of the last row of the previous page, or nil for the first page.
+	values []any
+
+	// applied reports whether the query has been rewritten. The statements executed while
+	// reading its rows, like the nested queries, share its context but are not paginated.
+	applied atomic.Bool
+}
+
+// withKeyset wraps next so that the first query executed with the keyset pagination of ctx
+// is rewritten to only return the rows of its page, see QueryKeyset.
+func withKeyset(ctx context.Context, drv driver.Driver, next QueryHandler) QueryHandler {
+	keyset, ok := ctx.Value(keysetQueryKey{}).(*keysetQuery)
+	if !ok {
+		return next
+	}
+	return func(ctx context.Context, query string, args ...any) (sql.Rows, error) {
+		if !keyset.applied.CompareAndSwap(false, true) {
+			return next(ctx, query, args...)
+		}
+		query, args, err := rewriteKeysetQuery(query, args, keyset.keyset, keyset.values, drv)
+		if err != nil {
+			return nil, err
+		}
+		return next(ctx, query, args...)
+	}
+}
+
+// rewriteKeysetQuery checks that the ORDER BY clause of query matches the keys of keyset,
+// adds the predicate matching the rows after values, if any, and limits the query to
+// one row more than the limit of keyset.
+func rewriteKeysetQuery(query string, args []any, keyset Keyset, values []any, drv driver.Driver) (string, []any, error) {
+	unsupported := func(reason string) (string, []any, error) {
+		return "", nil, fmt.Errorf("%w: %s: %s", ErrKeysetUnsupported, reason, query)
+	}
+	if start, _ := topLevelKeyword(query, 0, "UNION", "INTERSECT", "EXCEPT"); start >= 0 {
+		return unsupported("compound query")
+	}
+	orderStart, orderEnd := topLevelKeyword(query, 0, "ORDER BY")
+	if orderStart < 0 {
+		return "", nil, fmt.Errorf("%w: missing ORDER BY: %s", ErrKeysetOrderMismatch, query)
+	}
+	// the limit goes before the locking clause, like FOR UPDATE.
+	orderListEnd := len(query)
+	if start, end := topLevelKeyword(query, orderEnd, "LIMIT", "OFFSET", "FETCH", "FOR"); start >= 0 {
+		if !strings.EqualFold(query[start:end], "FOR") {
+			return unsupported("the query is already limited")
+		}
+		orderListEnd = start
+	}
+	if err := checkKeysetOrder(query[orderEnd:orderListEnd], keyset.Keys); err != nil {
+		return "", nil, fmt.Errorf("%w: %s", err, query)
+	}
+
+	capabilities := driver.CapabilitiesOf(drv)
+	var builder strings.Builder
+	builder.Grow(len(query) + 64)
+	// rest is the start of the query written after the predicate, if any.
+	rest := 0
+	if values != nil {
+		// the predicate goes at the end of the WHERE clause, before GROUP BY, HAVING and WINDOW.
+		head := query[:orderStart]
+		whereStart, whereEnd := topLevelKeyword(head, 0, "WHERE")
+		clauseEnd := orderStart
+		if start, _ := topLevelKeyword(head, max(whereEnd, 0), "GROUP BY", "HAVING", "WINDOW"); start >= 0 {
+			clauseEnd = start
+		}
+		predicate, predicateArgs := keysetPredicate(keyset.Keys, values, drv.Translator(), len(args), capabilities.RowValues)
+		if whereStart >= 0 {
+			// the condition is parenthesized, since it may be a disjunction.
+			builder.WriteString(query[:whereEnd])
+			builder.WriteString(" (")
+			builder.WriteString(strings.TrimSpace(query[whereEnd:clauseEnd]))
+			builder.WriteString(") AND ")
+		} else {
+			builder.WriteString(strings.TrimRightFunc(query[:clauseEnd], unicode.IsSpace))
+			builder.WriteString(" WHERE ")
+		}
+		builder.WriteString(predicate)
+		builder.WriteByte(' ')
+		if predicateArgs.positional {
+			index := countOutsideQuotes(query[:clauseEnd], '?')
+			args = slices.Insert(slices.Clone(args), index, predicateArgs.values...)
+		} else {
+			args = append(slices.Clone(args), predicateArgs.values...)
+		}
+		rest = clauseEnd
+	}
+	builder.WriteString(strings.TrimRightFunc(query[rest:orderListEnd], unicode.IsSpace))
+	builder.WriteByte(' ')
+	builder.WriteString(capabilities.LimitClause(strconv.Itoa(keyset.Limit+1), ""))
+	if orderListEnd < len(query) {
+		builder.WriteByte(' ')
+		builder.WriteString(query[orderListEnd:])
+	}
+	return builder.String(), args, nil
+}
+
+// keysetArgs are the arguments of a keyset predicate.
+type keysetArgs struct {
+	values []any
+
+	// positional reports whether the arguments are bound by position,
+	// so that they must be inserted among the arguments of the query.
+	positional bool
+}
+
+// keysetPredicate returns the predicate matching the rows after values in the order of keys,
+// with its arguments. The placeholders of translator continue the numbering of the argCount
+// arguments of the query. The rows are compared as row values if rowValues is true and
+// the keys are sorted in the same direction, like (created_at, id) > (?, ?), otherwise as
+// created_at > ? OR (created_at = ? AND id > ?).
+func keysetPredicate(keys []KeysetKey, values []any, translator driver.Translator, argCount int, rowValues bool) (string, keysetArgs) {
+	operator := func(key KeysetKey) string {
+		if key.Desc {
+			return " < "
+		}
+		return " > "
+	}
+	sameDirection := !slices.ContainsFunc(keys, func(key KeysetKey) bool { return key.Desc != keys[0].Desc })
+	if rowValues && sameDirection && len(keys) > 1 {
+		placeholders, positional := appendedPlaceholders(translator, "keyset", argCount, len(keys))
+		columns := make([]string, len(keys))
+		for i, key := range keys {
+			columns[i] = key.Column
+		}
+		predicate := "(" + strings.Join(columns, ", ") + ")" + operator(keys[0]) + "(" + strings.Join(placeholders, ", ") + ")"
+		return predicate, keysetArgs{values: slices.Clone(values), positional: positional}
+	}
+	placeholders, positional := appendedPlaceholders(translator, "keyset", argCount, len(keys)*(len(keys)+1)/2)
+	var (
+		terms     []string
+		arguments []any
+	)
+	for i, key := range keys {
+		conditions := make([]string, 0, i+1)
+		for j := range i {
+			conditions = append(conditions, keys[j].Column+" = "+placeholders[0])
+			arguments = append(arguments, values[j])
+			placeholders = placeholders[1:]
+		}
+		conditions = append(conditions, key.Column+operator(key)+placeholders[0])
+		arguments = append(arguments, values[i])
+		placeholders = placeholders[1:]
+		term := strings.Join(conditions, " AND ")
+		if i > 0 {
+			term = "(" + term + ")"
+		}
+		terms = append(terms, term)
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", keysetArgs{values: arguments, positional: positional}
+}
+
+// checkKeysetOrder returns ErrKeysetOrderMismatch if the ORDER BY list does not sort by keys.
+func checkKeysetOrder(list string, keys []KeysetKey) error {
+	var items []string
+	start := 0
+	for i := 0; i < len(list); {
+		if next := skipQuoted(list, i); next != i {
+			i = next
+			continue
+		}
+		switch list[i] {
+		case '(':
+			if end := matchingParen(list, i); end > 0 {
+				i = end
+			}
+		case ',':
+			items = append(items, list[start:i])
+			start = i + 1
+		}
+		i++
+	}
+	items = append(items, list[start:])
+	mismatch := func() error {
+		expected := make([]string, len(keys))
+		for i, key := range keys {
+			expected[i] = key.Column
+			if key.Desc {
+				expected[i] += " DESC"
+			}
+		}
+		return fmt.Errorf("%w: ORDER BY %s, expected ORDER BY %s",
+			ErrKeysetOrderMismatch, strings.TrimSpace(list), strings.Join(expected, ", "))
+	}
+	if len(items) != len(keys) {
+		return mismatch()
+	}
+	for i, item := range items {
+		fields := strings.Fields(item)
+		// NULLS FIRST and NULLS LAST do not matter, since the keys are not NULL.
+		if n := len(fields); n > 2 && strings.EqualFold(fields[n-2], "NULLS") {
+			fields = fields[:n-2]
+		}
+		desc := false
+		if n := len(fields); n > 1 && (strings.EqualFold(fields[n-1], "ASC") || strings.EqualFold(fields[n-1], "DESC")) {
+			desc = strings.EqualFold(fields[n-1], "DESC")
+			fields = fields[:n-1]
+		}
+		if desc != keys[i].Desc || normalizeKeysetColumn(strings.Join(fields, "")) != normalizeKeysetColumn(keys[i].Column) {
+			return mismatch()
+		}
+	}
+	return nil
+}
+
+// normalizeKeysetColumn returns column in lower case without quotes nor whitespace.
+func normalizeKeysetColumn(column string) string {
+	return strings.ToLower(strings.Map(func(r rune) rune {
+		if r == '"' || r == '`' || r == '[' || r == ']' || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, column))
+}
+
+// keysetIndexes returns the indexes of the columns of keys among columns.
+func keysetIndexes(keys []KeysetKey, columns []string) ([]int, error) {
+	indexes := make([]int, len(keys))
+	for i, key := range keys {
+		name := normalizeKeysetColumn(key.Column)
+		if dot := strings.LastIndexByte(name, '.'); dot >= 0 {
+			name = name[dot+1:]
+		}
+		indexes[i] = slices.IndexFunc(columns, func(column string) bool { return strings.EqualFold(column, name) })
+		if indexes[i] < 0 {
+			return nil, fmt.Errorf("%w: the key %s is not selected", ErrKeysetUnsupported, key.Column)
+		}
+	}
+	return indexes, nil
+}
+
+// keysetValues returns the values scanned into the destinations at indexes.
+func keysetValues(indexes []int, dest []any) ([]any, error) {
+	values := make([]any, len(indexes))
+	for i, index := range indexes {
+		if index >= len(dest) {
+			return nil, fmt.Errorf("%w: the key column %d is not scanned", ErrKeysetUnsupported, index)
+		}
+		value, err := sqldriver.DefaultParameterConverter.ConvertValue(dest[index])
+		if err != nil {
+			return nil, fmt.Errorf("juice: keyset value: %w", err)
+		}
+		if bytes, ok := value.([]byte); ok {
+			// the destination may be reused by the next row.
+			value = slices.Clone(bytes)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// encodeCursor returns the opaque cursor of the key values of a row.
+func encodeCursor(values []any) (string, error) {
+	items := make([]string, len(values))
+	for i, value := range values {
+		switch value := value.(type) {
+		case nil:
+			items[i] = "n"
+		case int64:
+			items[i] = "i:" + strconv.FormatInt(value, 10)
+		case float64:
+			items[i] = "f:" + strconv.FormatFloat(value, 'g', -1, 64)
+		case bool:
+			items[i] = "b:" + strconv.FormatBool(value)
+		case string:
+			items[i] = "s:" + value
+		case []byte:
+			items[i] = "x:" + base64.StdEncoding.EncodeToString(value)
+		case time.Time:
+			items[i] = "t:" + value.Format(time.RFC3339Nano)
+		default:
+			return "", fmt.Errorf("juice: unsupported keyset value %T", value)
+		}
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor returns the count key values of cursor, or nil if cursor is empty.
+func decodeCursor(cursor string, count int) ([]any, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	invalid := func(err error) ([]any, error) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return invalid(err)
+	}
+	var items []string
+	if err = json.Unmarshal(data, &items); err != nil {
+		return invalid(err)
+	}
+	if len(items) != count {
+		return invalid(fmt.Errorf("%d values, expected %d", len(items), count))
+	}
+	values := make([]any, len(items))
+	for i, item := range items {
+		kind, text, _ := strings.Cut(item, ":")
+		switch kind {
+		case "n":
+		case "i":
+			values[i], err = strconv.ParseInt(text, 10, 64)
+		case "f":
+			values[i], err = strconv.ParseFloat(text, 64)
+		case "b":
+			values[i], err = strconv.ParseBool(text)
+		case "s":
+			values[i] = text
+		case "x":
+			values[i], err = base64.StdEncoding.DecodeString(text)
+		case "t":
+			values[i], err = time.Parse(time.RFC3339Nano, text)
+		default:
+			err = fmt.Errorf("unknown value kind %q", kind)
+		}
+		if err != nil {
+			return invalid(err)
+		}
+	}
+	return values, nil
+}
+
+// topLevelKeyword returns the start and the end of the first of keywords found in query from start,
+// outside quotes and parentheses, or -1. The words of a keyword, like ORDER BY, may be separated
+// by any whitespace.
+func topLevelKeyword(query string, start int, keywords ...string) (int, int) {
+	depth := 0
+	for i := start; i < len(query); {
+		if next := skipQuoted(query, i); next != i {
+			i = next
+			continue
+		}
+		switch c := query[i]; {
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && isNameStart(c) && (i == 0 || !isNamePart(query[i-1])):
+			for _, keyword := range keywords {
+				if end := matchKeyword(query, i, keyword); end >= 0 {
+					return i, end
+				}
+			}
+		}
+		i++
+	}
+	return -1, -1
+}
+
+// matchKeyword returns the end of keyword if query has it at start, or -1.
+func matchKeyword(query string, start int, keyword string) int {
+	i := start
+	for index, word := range strings.Fields(keyword) {
+		if index > 0 {
+			spaces := i
+			for i < len(query) && isSpace(rune(query[i])) {
+				i++
+			}
+			if i == spaces {
+				return -1
+			}
+		}
+		if len(query)-i < len(word) || !strings.EqualFold(query[i:i+len(word)], word) {
+			return -1
+		}
+		i += len(word)
+	}
+	if i < len(query) && isNamePart(query[i]) {
+		return -1
+	}
+	return i
+}
@@ -0,0 +1,234 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	jdriver "github.com/go-juicedev/juice/driver"
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+func TestRewriteKeysetQuery_keyset_test(t *testing.T) {
+	keys := []KeysetKey{{Column: "created_at", Desc: true}, {Column: "id", Desc: true}}
+	mixed := []KeysetKey{{Column: "u.name"}, {Column: "u.id", Desc: true}}
+	values := []any{"2026-01-01", int64(7)}
+	tests := []struct {
+		name     string
+		driver   jdriver.Driver
+		query    string
+		args     []any
+		keys     []KeysetKey
+		values   []any
+		want     string
+		wantArgs []any
+	}{
+		{
+			name:   "first page",
+			driver: jdriver.SQLiteDriver{},
+			query:  "SELECT * FROM user WHERE status = ? ORDER BY created_at DESC, id DESC",
+			args:   []any{1},
+			keys:   keys,
+			want:   "SELECT * FROM user WHERE status = ? ORDER BY created_at DESC, id DESC LIMIT 11",
+		},
+		{
+			name:     "row values after the where clause",
+			driver:   jdriver.SQLiteDriver{},
+			query:    "SELECT * FROM user WHERE status = ? OR name = ? ORDER BY created_at DESC, id DESC",
+			args:     []any{1, "a"},
+			keys:     keys,
+			values:   values,
+			want:     "SELECT * FROM user WHERE (status = ? OR name = ?) AND (created_at, id) < (?, ?) ORDER BY created_at DESC, id DESC LIMIT 11",
+			wantArgs: []any{1, "a", "2026-01-01", int64(7)},
+		},
+		{
+			name:     "positional arguments before the order",
+			driver:   jdriver.MySQLDriver{},
+			query:    "SELECT * FROM user GROUP BY id HAVING count(*) > ? ORDER BY `created_at` desc, id DESC FOR UPDATE",
+			args:     []any{2},
+			keys:     keys,
+			values:   values,
+			want:     "SELECT * FROM user WHERE (created_at, id) < (?, ?) GROUP BY id HAVING count(*) > ? ORDER BY `created_at` desc, id DESC LIMIT 11 FOR UPDATE",
+			wantArgs: []any{"2026-01-01", int64(7), 2},
+		},
+		{
+			name:     "numbered placeholders",
+			driver:   jdriver.PostgresDriver{},
+			query:    "SELECT * FROM (SELECT * FROM user ORDER BY id) u WHERE status = $1\nORDER BY created_at DESC, id DESC",
+			args:     []any{1},
+			keys:     keys,
+			values:   values,
+			want:     "SELECT * FROM (SELECT * FROM user ORDER BY id) u WHERE (status = $1) AND (created_at, id) < ($2, $3) ORDER BY created_at DESC, id DESC LIMIT 11",
+			wantArgs: []any{1, "2026-01-01", int64(7)},
+		},
+		{
+			name:     "mixed directions",
+			driver:   jdriver.SQLiteDriver{},
+			query:    "SELECT * FROM user u ORDER BY u.name ASC, u.id DESC",
+			keys:     mixed,
+			values:   []any{"a", int64(7)},
+			want:     "SELECT * FROM user u WHERE (u.name > ? OR (u.name = ? AND u.id < ?)) ORDER BY u.name ASC, u.id DESC LIMIT 11",
+			wantArgs: []any{"a", "a", int64(7)},
+		},
+		{
+			name:     "without row values",
+			driver:   jdriver.SQLServerDriver{},
+			query:    "SELECT * FROM [user] WHERE status = @p1 ORDER BY created_at DESC, id DESC",
+			args:     []any{1},
+			keys:     keys,
+			values:   values,
+			want:     "SELECT * FROM [user] WHERE (status = @p1) AND (created_at < @p2 OR (created_at = @p3 AND id < @p4)) ORDER BY created_at DESC, id DESC OFFSET 0 ROWS FETCH NEXT 11 ROWS ONLY",
+			wantArgs: []any{1, "2026-01-01", "2026-01-01", int64(7)},
+		},
+	}
+	for _, tt := range tests {
+		got, args, err := rewriteKeysetQuery(tt.query, tt.args, Keyset{Keys: tt.keys, Limit: 10}, tt.values, tt.driver)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+		if tt.wantArgs == nil {
+			tt.wantArgs = tt.args
+		}
+		if !reflect.DeepEqual(args, tt.wantArgs) {
+			t.Errorf("%s: got args %v, want %v", tt.name, args, tt.wantArgs)
+		}
+	}
+
+	errorTests := []struct {
+		query string
+		want  error
+	}{
+		{"SELECT * FROM user", ErrKeysetOrderMismatch},
+		{"SELECT * FROM user ORDER BY created_at DESC", ErrKeysetOrderMismatch},
+		{"SELECT * FROM user ORDER BY created_at, id", ErrKeysetOrderMismatch},
+		{"SELECT * FROM user ORDER BY id DESC, created_at DESC", ErrKeysetOrderMismatch},
+		{"SELECT * FROM user ORDER BY created_at DESC, id DESC LIMIT 5", ErrKeysetUnsupported},
+		{"SELECT * FROM a UNION SELECT * FROM b ORDER BY created_at DESC, id DESC", ErrKeysetUnsupported},
+	}
+	for _, tt := range errorTests {
+		if _, _, err := rewriteKeysetQuery(tt.query, nil, Keyset{Keys: keys, Limit: 10}, nil, jdriver.SQLiteDriver{}); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.query, tt.want, err)
+		}
+	}
+}
+
+func TestKeysetCursor_keyset_test(t *testing.T) {
+	values := []any{nil, int64(-7), 1.5, true, "a:b", []byte{0, 1}, time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)}
+	cursor, err := encodeCursor(values)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := decodeCursor(cursor, len(values))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, values) {
+		t.Fatalf("got %v, want %v", got, values)
+	}
+	if _, err = decodeCursor(cursor, 2); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+	if _, err = decodeCursor("!", 1); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+	if got, err = decodeCursor("", 2); got != nil || err != nil {
+		t.Fatalf("expected no values for an empty cursor, got %v, %v", got, err)
+	}
+}
+
+type keysetTestMiddleware struct {
+	queries [][]any
+	rows    [][]any
+}
+
+func (m *keysetTestMiddleware) QueryContext(_ *StatementContext, _ QueryHandler) QueryHandler {
+	return func(_ context.Context, query string, args ...any) (jsql.Rows, error) {
+		query = strings.Join(strings.Fields(query), " ")
+		m.queries = append(m.queries, append([]any{query}, args...))
+		if strings.HasPrefix(query, "SELECT COUNT(*)") {
+			return jsql.NewRowsBuffer([]string{"COUNT(*)"}, [][]any{{int64(len(m.rows))}}), nil
+		}
+		return jsql.NewRowsBuffer([]string{"id", "name"}, m.rows), nil
+	}
+}
+
+func (m *keysetTestMiddleware) ExecContext(_ *StatementContext, next ExecHandler) ExecHandler {
+	return next
+}
+
+func TestQueryKeyset_keyset_test(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="primary">
+		<environment id="primary">
+			<dataSource>primary.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>
+		<mapper namespace="user">
+			<select id="List">SELECT id, name FROM user ORDER BY id</select>
+		</mapper>
+	</mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	middleware := &keysetTestMiddleware{rows: [][]any{{int64(1), "a"}, {int64(2), "b"}, {int64(3), "c"}}}
+	engine := newStatementTestEngine(nil, middleware)
+	engine.configuration = configuration
+	engine.db = openStatementTestDB(t, &shSQLDriverState{})
+	ctx := context.Background()
+
+	type user struct {
+		ID   int64  `column:"id"`
+		Name string `column:"name"`
+	}
+	keyset := Keyset{Keys: []KeysetKey{{Column: "id"}}, Limit: 2}
+	page, err := QueryKeyset[user](ctx, engine, "user.List", nil, keyset)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Items) != 2 || page.Items[1].Name != "b" || page.Next == "" {
+		t.Fatalf("unexpected page %+v", page)
+	}
+
+	middleware.rows = [][]any{{int64(3), "c"}}
+	keyset.Cursor, keyset.Total = page.Next, true
+	if page, err = QueryKeyset[user](ctx, engine, "user.List", nil, keyset); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Items) != 1 || page.Next != "" || page.Total != 1 {
+		t.Fatalf("unexpected last page %+v", page)
+	}
+	want := [][]any{
+		{"SELECT id, name FROM user ORDER BY id LIMIT 3"},
+		{"SELECT id, name FROM user WHERE (id > ?) ORDER BY id LIMIT 3", int64(2)},
+		{"SELECT COUNT(*) FROM user"},
+	}
+	if !reflect.DeepEqual(middleware.queries, want) {
+		t.Fatalf("unexpected queries %q", middleware.queries)
+	}
+
+	keyset.Keys = []KeysetKey{{Column: "id"}, {Column: "name"}}
+	if _, err = QueryKeyset[user](ctx, engine, "user.List", nil, keyset); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+	keyset.Cursor = ""
+	if _, err = QueryKeyset[user](ctx, engine, "user.List", nil, keyset); !errors.Is(err, ErrKeysetOrderMismatch) {
+		t.Fatalf("expected ErrKeysetOrderMismatch, got %v", err)
+	}
+	if _, err = QueryKeyset[user](ctx, engine, "user.List", nil, Keyset{}); !errors.Is(err, ErrKeysetUnsupported) {
+		t.Fatalf("expected ErrKeysetUnsupported, got %v", err)
+	}
+}
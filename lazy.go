@@ -0,0 +1,93 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrLazyNotInitialized is returned when loading a Lazy which has no loader, like its zero value.
+var ErrLazyNotInitialized = errors.New("juice: lazy value has no loader")
+
+// Lazy is a value of type T loaded on first access, like the nested association or
+// collection of an entity, whose statement is executed only when it is needed:
+//
+//	type User struct {
+//	    ID     int64                `column:"id"`
+//	    Orders *juice.Lazy[[]Order]
+//	}
+//
+//	func (u *User) AfterScan(ctx context.Context) error {
+//	    manager, err := juice.ManagerFromContext(ctx)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    u.Orders = juice.LazySelect[[]Order](manager, "OrderMapper.ListByUser", juice.H{"userId": u.ID})
+//	    return nil
+//	}
+//
+//	orders, err := user.Orders.Load(ctx)
+//
+// The value is loaded once and kept, unless loading fails, in which case the next call loads it again.
+// A Lazy is safe for concurrent use.
+type Lazy[T any] struct {
+	mu     sync.Mutex
+	load   func(ctx context.Context) (T, error)
+	loaded bool
+	value  T
+}
+
+// NewLazy returns a Lazy loading its value with load.
+func NewLazy[T any](load func(ctx context.Context) (T, error)) *Lazy[T] {
+	return &Lazy[T]{load: load}
+}
+
+// LazySelect returns a Lazy loading its value by querying statement with param through manager.
+// The statement is resolved like with manager.Object when the value is loaded.
+func LazySelect[T any](manager Manager, statement, param any) *Lazy[T] {
+	return NewLazy(func(ctx context.Context) (T, error) {
+		return NewGenericManager[T](manager).Object(statement).QueryContext(ctx, param)
+	})
+}
+
+// Load returns the value, loading it on the first call.
+func (l *Lazy[T]) Load(ctx context.Context) (T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.loaded {
+		return l.value, nil
+	}
+	if l.load == nil {
+		var zero T
+		return zero, ErrLazyNotInitialized
+	}
+	value, err := l.load(ctx)
+	if err != nil {
+		return value, err
+	}
+	l.value, l.loaded = value, true
+	return value, nil
+}
+
+// Loaded reports whether the value has been loaded.
+func (l *Lazy[T]) Loaded() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.loaded
+}
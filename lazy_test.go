@@ -0,0 +1,71 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+func TestLazy_Load_lazy_test(t *testing.T) {
+	var calls int
+	fail := true
+	lazy := NewLazy(func(context.Context) (int, error) {
+		calls++
+		if fail {
+			return 0, errors.New("load failed")
+		}
+		return 42, nil
+	})
+
+	if _, err := lazy.Load(t.Context()); err == nil {
+		t.Fatal("expected the load error")
+	}
+	if lazy.Loaded() {
+		t.Fatal("expected a failed load not to be kept")
+	}
+
+	fail = false
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			if value, err := lazy.Load(t.Context()); err != nil || value != 42 {
+				t.Errorf("unexpected load result: %d, %v", value, err)
+			}
+		})
+	}
+	wg.Wait()
+	if calls != 2 || !lazy.Loaded() {
+		t.Fatalf("expected the value to be loaded once after the failure, got %d calls", calls)
+	}
+
+	var zero Lazy[int]
+	if _, err := zero.Load(t.Context()); !errors.Is(err, ErrLazyNotInitialized) {
+		t.Fatalf("expected ErrLazyNotInitialized, got %v", err)
+	}
+}
+
+func TestLazySelect_lazy_test(t *testing.T) {
+	executor := &sqlRowsExecutorStub{
+		queryRows: jsql.NewRowsBuffer([]string{"value"}, [][]any{{"o1"}, {"o2"}}),
+		stmt:      statementStub{},
+	}
+	mgr := &managerStub{object: executor}
+
+	lazy := LazySelect[[]string](mgr, "OrderMapper.ListByUser", H{"userId": 1})
+	if mgr.lastV != nil {
+		t.Fatal("expected the statement not to be resolved before loading")
+	}
+	orders, err := lazy.Load(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(orders) != 2 || orders[0] != "o1" || orders[1] != "o2" {
+		t.Fatalf("unexpected orders: %v", orders)
+	}
+	if mgr.lastV != "OrderMapper.ListByUser" {
+		t.Fatalf("unexpected statement: %v", mgr.lastV)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// leakDetectionSetting is the debug setting tracking the rows and the transactions which are
+// garbage collected without being closed, committed or rolled back.
+// Its value is the way leaks are reported: "log" or "panic". Any other value disables it.
+const leakDetectionSetting = "leakDetection"
+
+const (
+	leakDetectionLog   = "log"
+	leakDetectionPanic = "panic"
+)
+
+// ErrResourceLeak is reported when rows or a transaction are garbage collected
+// before being released, with the stack trace of where they were opened.
+var ErrResourceLeak = errors.New("juice: resource leak")
+
+// maxLeakStackDepth is the number of frames recorded for the creation stack of a tracked resource.
+const maxLeakStackDepth = 32
+
+// reportLeak reports err according to mode. It is a variable so that tests can observe leaks.
+var reportLeak = func(mode string, err error) {
+	if mode == leakDetectionPanic {
+		panic(err)
+	}
+	logger.Print(err)
+}
+
+// leakDetectionMode returns the leakDetection setting of engine,
+// or an empty string when leak detection is disabled.
+func leakDetectionMode(engine *Engine) string {
+	configuration := engine.GetConfiguration()
+	if configuration == nil {
+		return ""
+	}
+	switch mode := configuration.Settings().Get(leakDetectionSetting).String(); mode {
+	case leakDetectionLog, leakDetectionPanic:
+		return mode
+	default:
+		return ""
+	}
+}
+
+// leakTracker records where a resource was opened,
+// to report it when its owner is garbage collected before it is released.
+type leakTracker struct {
+	mode     string
+	resource string
+	leak     string
+	stack    []uintptr
+	released atomic.Bool
+}
+
+// trackLeak tracks the resource of owner when leak detection is enabled on engine,
+// and returns nil otherwise. leak describes how the resource leaked when it is reported.
+func trackLeak[T any](engine *Engine, owner *T, resource, leak string) *leakTracker {
+	mode := leakDetectionMode(engine)
+	if mode == "" {
+		return nil
+	}
+	var pcs [maxLeakStackDepth]uintptr
+	// skip runtime.Callers and trackLeak
+	n := runtime.Callers(2, pcs[:])
+	tracker := &leakTracker{
+		mode:     mode,
+		resource: resource,
+		leak:     leak,
+		stack:    pcs[:n:n],
+	}
+	runtime.AddCleanup(owner, (*leakTracker).check, tracker)
+	return tracker
+}
+
+// markReleased marks the resource as released. It is safe to call on a nil tracker.
+func (t *leakTracker) markReleased() {
+	if t != nil {
+		t.released.Store(true)
+	}
+}
+
+// markOpened marks the resource as opened again. It is safe to call on a nil tracker.
+func (t *leakTracker) markOpened() {
+	if t != nil {
+		t.released.Store(false)
+	}
+}
+
+// check reports the resource as leaked unless it was released.
+func (t *leakTracker) check() {
+	if t.released.Load() {
+		return
+	}
+	reportLeak(t.mode, fmt.Errorf("%w: %s %s, opened at:\n%s", ErrResourceLeak, t.resource, t.leak, t.stackTrace()))
+}
+
+// stackTrace formats the creation stack of the resource.
+func (t *leakTracker) stackTrace() string {
+	var builder strings.Builder
+	frames := runtime.CallersFrames(t.stack)
+	for more := len(t.stack) > 0; more; {
+		var frame runtime.Frame
+		frame, more = frames.Next()
+		fmt.Fprintf(&builder, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+	}
+	return builder.String()
+}
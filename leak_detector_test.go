@@ -0,0 +1,93 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+func TestLeakDetection_leak_detector_test(t *testing.T) {
+	leaks := make(chan error, 4)
+	original := reportLeak
+	reportLeak = func(mode string, err error) {
+		if mode != leakDetectionPanic {
+			t.Errorf("expected the panic mode, got %q", mode)
+		}
+		leaks <- err
+	}
+	t.Cleanup(func() { reportLeak = original })
+
+	// awaitLeak collects garbage until a leak is reported or the timeout elapses.
+	awaitLeak := func(timeout time.Duration) error {
+		deadline := time.After(timeout)
+		for {
+			runtime.GC()
+			select {
+			case err := <-leaks:
+				return err
+			case <-deadline:
+				return nil
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}
+
+	db := openStatementTestDB(t, &shSQLDriverState{})
+	engine := newStatementTestEngine(db)
+	engine.db = db
+	engine.configuration.(*xmlConfiguration).settings[leakDetectionSetting] = leakDetectionPanic
+	query := func(closed bool) {
+		handler := newExecuteStatementHandler("SELECT id FROM user", nil, engine, nil).withQueryHandler(
+			func(context.Context, string, ...any) (jsql.Rows, error) {
+				return jsql.NewRowsBuffer([]string{"id"}, [][]any{{1}}), nil
+			},
+		)
+		rows, err := handler.QueryContext(context.Background(), shStatement{name: "user.Find"}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if closed {
+			_ = rows.Close()
+		}
+	}
+
+	query(true)
+	if err := awaitLeak(100 * time.Millisecond); err != nil {
+		t.Fatalf("unexpected leak of closed rows: %v", err)
+	}
+	query(false)
+	err := awaitLeak(5 * time.Second)
+	if !errors.Is(err, ErrResourceLeak) || !strings.Contains(err.Error(), `rows of statement "user.Find" were not closed`) ||
+		!strings.Contains(err.Error(), "TestLeakDetection_leak_detector_test") {
+		t.Fatalf("expected the leak of the rows with their creation stack, got %v", err)
+	}
+
+	begin := func(committed bool) {
+		manager := engine.ContextTx(context.Background(), nil)
+		if err := manager.Begin(); err != nil {
+			t.Fatalf("Begin() error = %v", err)
+		}
+		if committed {
+			_ = manager.Commit()
+		}
+	}
+	begin(true)
+	if err = awaitLeak(100 * time.Millisecond); err != nil {
+		t.Fatalf("unexpected leak of a committed transaction: %v", err)
+	}
+	begin(false)
+	if err = awaitLeak(5 * time.Second); !errors.Is(err, ErrResourceLeak) || !strings.Contains(err.Error(), "transaction was not committed or rolled back") {
+		t.Fatalf("expected the leak of the transaction, got %v", err)
+	}
+
+	engine.configuration.(*xmlConfiguration).settings[leakDetectionSetting] = "false"
+	query(false)
+	if err = awaitLeak(100 * time.Millisecond); err != nil {
+		t.Fatalf("unexpected leak report with leak detection disabled: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/go-juicedev/juice/sql"
+)
+
+// AfterScanner is implemented by the entities which need to be post-processed
+// once scanned, for example to normalize fields, decrypt values or compute
+// derived fields.
+//
+// AfterScan is called for the results scanned by GenericExecutor.QueryContext,
+// GenericRunner, ExecReturning, QueryKeyset and BatchLoader and, when the result
+// is a slice, an array or a map, for each of its elements. The rows scanned by
+// the caller, like with an Iterator or sql.Bind, are not post-processed.
+// An error aborts the query and is returned to the caller.
+type AfterScanner interface {
+	AfterScan(ctx context.Context) error
+}
+
+// BeforeInserter is implemented by the parameters which need to be prepared before
+// being inserted, for example to fill the creation time.
+//
+// BeforeInsert is called before insert statements are executed for the parameter and,
+// when the parameter is a slice, an array or a map, for each of its elements.
+// An error aborts the statement and is returned to the caller.
+type BeforeInserter interface {
+	BeforeInsert(ctx context.Context) error
+}
+
+// BeforeUpdater is implemented by the parameters which need to be prepared before
+// being updated, for example to fill the modification time.
+//
+// BeforeUpdate is called like BeforeInserter.BeforeInsert but for update statements.
+type BeforeUpdater interface {
+	BeforeUpdate(ctx context.Context) error
+}
+
+// afterScan calls the AfterScanner hooks of the result pointed by dest.
+func afterScan(ctx context.Context, dest any) error {
+	return callHooks(reflect.ValueOf(dest), func(hook AfterScanner) error {
+		return hook.AfterScan(ctx)
+	})
+}
+
+// beforeExec calls the BeforeInserter or BeforeUpdater hooks of param,
+// depending on the action of the statement.
+func beforeExec(ctx context.Context, action sql.Action, param any) error {
+	switch action {
+	case sql.Insert:
+		return callHooks(reflect.ValueOf(param), func(hook BeforeInserter) error {
+			return hook.BeforeInsert(ctx)
+		})
+	case sql.Update:
+		return callHooks(reflect.ValueOf(param), func(hook BeforeUpdater) error {
+			return hook.BeforeUpdate(ctx)
+		})
+	default:
+		return nil
+	}
+}
+
+// callHooks calls call with value if it implements H, through its address when it
+// is addressable, or with each element of value if it is a slice, an array or a map.
+// The fields of the structs are not walked.
+func callHooks[H any](value reflect.Value, call func(H) error) error {
+	for value.Kind() == reflect.Interface || value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		if hook, ok := value.Interface().(H); ok {
+			return call(hook)
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return nil
+	}
+	if value.CanAddr() {
+		if hook, ok := value.Addr().Interface().(H); ok {
+			return call(hook)
+		}
+	}
+	if hook, ok := value.Interface().(H); ok {
+		return call(hook)
+	}
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		if !mayHaveHooks[H](value.Type().Elem()) {
+			return nil
+		}
+		for i := range value.Len() {
+			if err := callHooks(value.Index(i), call); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if !mayHaveHooks[H](value.Type().Elem()) {
+			return nil
+		}
+		for iter := value.MapRange(); iter.Next(); {
+			if err := callHooks(iter.Value(), call); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mayHaveHooks reports whether the values of type t may implement H,
+// so that the elements of large slices like []byte are not walked.
+func mayHaveHooks[H any](t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface, reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	default:
+		return reflect.PointerTo(t).Implements(reflect.TypeFor[H]())
+	}
+}
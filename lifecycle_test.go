@@ -0,0 +1,146 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-juicedev/juice/eval"
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+type lifecycleUser struct {
+	Name    string `column:"name"`
+	Upper   string
+	Created bool
+	Updated bool
+}
+
+func (u *lifecycleUser) AfterScan(context.Context) error {
+	if u.Name == "" {
+		return errors.New("empty name")
+	}
+	u.Upper = strings.ToUpper(u.Name)
+	return nil
+}
+
+func (u *lifecycleUser) BeforeInsert(context.Context) error {
+	u.Created = true
+	return nil
+}
+
+func (u *lifecycleUser) BeforeUpdate(context.Context) error {
+	u.Updated = true
+	return nil
+}
+
+type lifecycleStatementHandler struct {
+	rows [][]any
+}
+
+func (h lifecycleStatementHandler) QueryContext(context.Context, Statement, eval.Param) (jsql.Rows, error) {
+	return jsql.NewRowsBuffer([]string{"name"}, h.rows), nil
+}
+
+func (h lifecycleStatementHandler) ExecContext(context.Context, Statement, eval.Param) (jsql.Result, error) {
+	return jsql.NewReturningResult(1), nil
+}
+
+func TestAfterScanner_lifecycle_test(t *testing.T) {
+	ctx := context.Background()
+	handler := lifecycleStatementHandler{rows: [][]any{{"alice"}, {"bob"}}}
+
+	users, err := (&GenericExecutor[[]lifecycleUser]{SQLRowsExecutor: NewSQLRowsExecutor(shStatement{}, handler, nil)}).QueryContext(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(users) != 2 || users[0].Upper != "ALICE" || users[1].Upper != "BOB" {
+		t.Fatalf("expected AfterScan to be called for each user, got %+v", users)
+	}
+
+	pointers, err := (&GenericExecutor[[]*lifecycleUser]{SQLRowsExecutor: NewSQLRowsExecutor(shStatement{}, handler, nil)}).QueryContext(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pointers) != 2 || pointers[1].Upper != "BOB" {
+		t.Fatalf("expected AfterScan to be called for each user pointer, got %+v", pointers)
+	}
+
+	handler.rows = [][]any{{""}}
+	if _, err = (&GenericExecutor[*lifecycleUser]{SQLRowsExecutor: NewSQLRowsExecutor(shStatement{}, handler, nil)}).QueryContext(ctx, nil); err == nil || err.Error() != "empty name" {
+		t.Fatalf("expected the AfterScan error, got %v", err)
+	}
+}
+
+// lifecycleRunner is a Runner whose Select returns the rows of handler.
+type lifecycleRunner struct {
+	Runner
+	handler lifecycleStatementHandler
+}
+
+func (r lifecycleRunner) Select(ctx context.Context, param eval.Param) (jsql.Rows, error) {
+	return r.handler.QueryContext(ctx, nil, param)
+}
+
+func TestAfterScannerPaths_lifecycle_test(t *testing.T) {
+	ctx := context.Background()
+	handler := lifecycleStatementHandler{rows: [][]any{{"alice"}}}
+
+	runner := NewGenericRunner[lifecycleUser](lifecycleRunner{handler: handler})
+	user, err := runner.Bind(ctx, nil)
+	if err != nil || user.Upper != "ALICE" {
+		t.Fatalf("expected AfterScan to be called by Bind, got %+v, %v", user, err)
+	}
+	users, err := runner.List(ctx, nil)
+	if err != nil || len(users) != 1 || users[0].Upper != "ALICE" {
+		t.Fatalf("expected AfterScan to be called by List, got %+v, %v", users, err)
+	}
+	pointers, err := runner.List2(ctx, nil)
+	if err != nil || len(pointers) != 1 || pointers[0].Upper != "ALICE" {
+		t.Fatalf("expected AfterScan to be called by List2, got %+v, %v", pointers, err)
+	}
+
+	returned, _, err := ExecReturning[lifecycleUser](ctx, NewSQLRowsExecutor(shStatement{}, handler, nil), nil)
+	if err != nil || len(returned) != 1 || returned[0].Upper != "ALICE" {
+		t.Fatalf("expected AfterScan to be called by ExecReturning, got %+v, %v", returned, err)
+	}
+
+	empty := lifecycleRunner{handler: lifecycleStatementHandler{rows: [][]any{{""}}}}
+	if _, err = NewGenericRunner[lifecycleUser](empty).List(ctx, nil); err == nil || err.Error() != "empty name" {
+		t.Fatalf("expected the AfterScan error, got %v", err)
+	}
+}
+
+func TestBeforeExecHooks_lifecycle_test(t *testing.T) {
+	ctx := context.Background()
+	exec := func(action jsql.Action, param eval.Param) {
+		t.Helper()
+		executor := NewSQLRowsExecutor(shStatement{action: action}, lifecycleStatementHandler{}, nil)
+		if _, err := executor.ExecContext(ctx, param); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	user := &lifecycleUser{}
+	exec(jsql.Insert, user)
+	if !user.Created || user.Updated {
+		t.Fatalf("expected BeforeInsert only, got %+v", user)
+	}
+	exec(jsql.Update, H{"user": user})
+	if !user.Updated {
+		t.Fatalf("expected BeforeUpdate for a map value, got %+v", user)
+	}
+
+	batch := []lifecycleUser{{}, {}}
+	exec(jsql.Insert, batch)
+	if !batch[0].Created || !batch[1].Created {
+		t.Fatalf("expected BeforeInsert for each element, got %+v", batch)
+	}
+
+	deleted := &lifecycleUser{}
+	exec(jsql.Delete, deleted)
+	if deleted.Created || deleted.Updated {
+		t.Fatalf("expected no hook for delete statements, got %+v", deleted)
+	}
+}
@@ -75,16 +75,34 @@ type basicTxManager struct {
 	ctx context.Context
 	// engine is the database engine instance that handles database operations
 	engine *Engine
+
+	// statements caches the prepared statements of the transaction.
+	// It is nil when the preparedStatementCacheSize setting disables it, see txPreparedStatementCacheSize.
+	statements *preparedStatementCache
 }
 
 func (b *basicTxManager) Object(v any) SQLRowsExecutor {
 	statement, err := b.engine.GetConfiguration().GetStatement(v)
+	if err == nil {
+		statement, err = resolveDatabaseStatement(statement, b.engine)
+	}
 	if err != nil {
 		return inValidExecutor(err)
 	}
-	drv := b.engine.Driver()
-	statementHandler := newBatchStatementHandler(b.engine, b.Transaction)
-	return NewSQLRowsExecutor(statement, statementHandler, drv)
+	return b.Statement(statement)
+}
+
+// Statement returns the executor of statement in the transaction, see Engine.Statement.
+func (b *basicTxManager) Statement(statement Statement) SQLRowsExecutor {
+	if statement == nil {
+		return inValidExecutor(ErrNoStatementFound)
+	}
+	engine := b.engine
+	if b.statements != nil {
+		engine = engine.withPreparedStatements(b.statements)
+	}
+	statementHandler := newBatchStatementHandler(engine, b.Transaction)
+	return NewSQLRowsExecutor(statement, statementHandler, b.engine.Driver())
 }
 
 // BasicTxManager implements the TxManager interface providing basic
@@ -95,6 +113,9 @@ type BasicTxManager struct {
 	// txOptions configures the transaction behavior
 	// If nil, default database transaction options are used
 	txOptions *sql.TxOptions
+
+	// leak tracks the transaction when the leakDetection setting is enabled.
+	leak *leakTracker
 }
 
 // Object implements the Manager interface
@@ -105,6 +126,14 @@ func (t *BasicTxManager) Object(v any) SQLRowsExecutor {
 	return t.basicTxManager.Object(v)
 }
 
+// Statement returns the executor of statement in the transaction, see Engine.Statement.
+func (t *BasicTxManager) Statement(statement Statement) SQLRowsExecutor {
+	if t.Transaction == nil {
+		return inValidExecutor(tx.ErrTransactionNotBegun)
+	}
+	return t.basicTxManager.Statement(statement)
+}
+
 // Begin begins the transaction
 func (t *BasicTxManager) Begin() (err error) {
 	// If the transaction is already begun, return an error directly.
@@ -112,7 +141,24 @@ func (t *BasicTxManager) Begin() (err error) {
 		return tx.ErrTransactionAlreadyBegun
 	}
 	t.Transaction, err = t.engine.DB().BeginTx(t.ctx, t.txOptions)
-	return err
+	if err != nil {
+		return err
+	}
+	if size := txPreparedStatementCacheSize(t.engine); size > 0 {
+		t.statements = newPreparedStatementCache(t.Transaction, size)
+	}
+	t.leak = trackLeak(t.engine, t, "transaction", "was not committed or rolled back")
+	return nil
+}
+
+// closeStatements closes the prepared statements cached by the transaction.
+func (t *BasicTxManager) closeStatements() error {
+	if t.statements == nil {
+		return nil
+	}
+	statements := t.statements
+	t.statements = nil
+	return statements.Close()
 }
 
 // Commit commits the transaction
@@ -121,9 +167,14 @@ func (t *BasicTxManager) Commit() error {
 	if t.Transaction == nil {
 		return tx.ErrTransactionNotBegun
 	}
+	closeErr := t.closeStatements()
 	transaction := t.Transaction
 	t.Transaction = nil
-	return transaction.Commit()
+	t.leak.markReleased()
+	if err := transaction.Commit(); err != nil || closeErr == nil {
+		return err
+	}
+	return closeErr
 }
 
 // Rollback rollbacks the transaction
@@ -132,9 +183,14 @@ func (t *BasicTxManager) Rollback() error {
 	if t.Transaction == nil {
 		return tx.ErrTransactionNotBegun
 	}
+	closeErr := t.closeStatements()
 	transaction := t.Transaction
 	t.Transaction = nil
-	return transaction.Rollback()
+	t.leak.markReleased()
+	if err := transaction.Rollback(); err != nil || closeErr == nil {
+		return err
+	}
+	return closeErr
 }
 
 func (t *BasicTxManager) Raw(query string) Runner {
@@ -2,10 +2,12 @@ package juice
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/go-juicedev/juice/internal/container"
 	"github.com/go-juicedev/juice/node"
+	"github.com/go-juicedev/juice/sql"
 )
 
 // Mapper defines a set of statements.
@@ -14,6 +16,7 @@ type Mapper struct {
 	mappers    *Mappers
 	statements map[string]*mappedStatement
 	sqlNodes   map[string]*node.SQLNode
+	resultMaps map[string]sql.ResultMap
 	attrs      map[string]string
 }
 
@@ -33,6 +36,17 @@ func (m *Mapper) setSqlNode(n *node.SQLNode) error {
 	return nil
 }
 
+func (m *Mapper) setResultMap(id string, resultMap sql.ResultMap) error {
+	if m.resultMaps == nil {
+		m.resultMaps = make(map[string]sql.ResultMap)
+	}
+	if _, exists := m.resultMaps[id]; exists {
+		return fmt.Errorf("resultMap %s already exists", id)
+	}
+	m.resultMaps[id] = resultMap
+	return nil
+}
+
 // Attribute returns the attribute value by key.
 func (m *Mapper) Attribute(key string) string {
 	return m.attrs[key]
@@ -53,6 +67,18 @@ func (m *Mapper) GetSQLNodeByID(id string) (node.Node, error) {
 	return m.mappers.GetSQLNodeByID(id)
 }
 
+// GetResultMapByID returns the resultMap id of the mapper, or of another mapper when id is qualified by its namespace.
+func (m *Mapper) GetResultMapByID(id string) (sql.ResultMap, error) {
+	if !strings.Contains(id, ".") {
+		resultMap, exists := m.resultMaps[id]
+		if !exists {
+			return nil, fmt.Errorf("resultMap %q not found in mapper %q", id, m.namespace)
+		}
+		return resultMap, nil
+	}
+	return m.mappers.GetResultMapByID(id)
+}
+
 func (m *Mapper) GetStatementByID(id string) (Statement, bool) {
 	statement, exists := m.statements[id]
 	return statement, exists
@@ -126,6 +152,22 @@ func (m *Mappers) GetStatementByID(id string) (Statement, error) {
 	return statement, nil
 }
 
+// StatementNames returns the fully qualified names of the statements of all mappers, sorted.
+// The statements sharing an id for several databaseIds are named once.
+func (m *Mappers) StatementNames() []string {
+	if m == nil || m.mappers == nil {
+		return nil
+	}
+	var names []string
+	for _, kv := range m.mappers.All() {
+		for _, statement := range kv.Value.statements {
+			names = append(names, statement.Name())
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
 func (m *Mappers) GetSQLNodeByID(id string) (node.Node, error) {
 	mapper, sqlNodeID, err := m.getMapperAndNodeID(id)
 	if err != nil {
@@ -134,6 +176,15 @@ func (m *Mappers) GetSQLNodeByID(id string) (node.Node, error) {
 	return mapper.GetSQLNodeByID(sqlNodeID)
 }
 
+// GetResultMapByID returns a resultMap by id, in the format of "namespace.resultMapID".
+func (m *Mappers) GetResultMapByID(id string) (sql.ResultMap, error) {
+	mapper, resultMapID, err := m.getMapperAndNodeID(id)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.GetResultMapByID(resultMapID)
+}
+
 // Configuration represents a configuration of juice.
 func (m *Mappers) Configuration() Configuration {
 	return m.cfg
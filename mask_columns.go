@@ -0,0 +1,57 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+
+	"github.com/go-juicedev/juice/sql"
+)
+
+// maskColumnsAttribute is the environment attribute listing the columns masked in the query results,
+// so that the environments using production snapshots, like staging, do not expose personal data:
+//
+//	<environment id="staging" maskColumns="email, phone">
+const maskColumnsAttribute = "maskColumns"
+
+// SetMaskFunc sets the function masking the columns listed by the maskColumns attribute
+// of the environment, sql.MaskValue by default.
+func (e *Engine) SetMaskFunc(mask sql.MaskFunc) {
+	e.maskFunc = mask
+}
+
+// maskColumnsOf returns the columns masked in the query results of the active environment of engine.
+func maskColumnsOf(engine *Engine) []string {
+	if engine.environment == nil {
+		return nil
+	}
+	return splitList(engine.environment.Attr(maskColumnsAttribute))
+}
+
+// withColumnMask wraps next so that the values of columns in its rows are masked, see sql.WithColumnMask.
+func withColumnMask(columns []string, mask sql.MaskFunc, next QueryHandler) QueryHandler {
+	if len(columns) == 0 {
+		return next
+	}
+	return func(ctx context.Context, query string, args ...any) (sql.Rows, error) {
+		rows, err := next(ctx, query, args...)
+		if err != nil {
+			return rows, err
+		}
+		return sql.WithColumnMask(rows, columns, mask), nil
+	}
+}
@@ -0,0 +1,46 @@
+package juice
+
+import (
+	"context"
+	"testing"
+
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+func TestColumnMask_mask_columns_test(t *testing.T) {
+	engine := newStatementTestEngine(nil)
+	handler := newExecuteStatementHandler("SELECT id, email FROM users", nil, engine, nil).withQueryHandler(
+		func(context.Context, string, ...any) (jsql.Rows, error) {
+			return jsql.NewRowsBuffer([]string{"id", "email"}, [][]any{{1, "eat@example.com"}}), nil
+		},
+	)
+	type user struct {
+		ID    int    `column:"id"`
+		Email string `column:"email"`
+	}
+	bind := func() user {
+		rows, err := handler.QueryContext(context.Background(), shStatement{}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result, err := jsql.Bind[user](rows)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	if got := bind(); got.Email != "eat@example.com" {
+		t.Fatalf("expected the email unmasked without maskColumns, got %q", got.Email)
+	}
+
+	engine.environment = &Environment{attrs: map[string]string{"maskColumns": "email"}}
+	if got := bind(); got.Email != "e**@example.com" {
+		t.Fatalf("expected the email masked, got %q", got.Email)
+	}
+
+	engine.SetMaskFunc(func(column, value string) string { return column + " hidden" })
+	if got := bind(); got.Email != "email hidden" || got.ID != 1 {
+		t.Fatalf("expected the mask function to be used, got %+v", got)
+	}
+}
@@ -23,12 +23,14 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"path"
 	"reflect"
 	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/go-juicedev/juice/driver"
 	"github.com/go-juicedev/juice/eval"
 	"github.com/go-juicedev/juice/internal/reflectlite"
 	"github.com/go-juicedev/juice/session"
@@ -144,6 +146,48 @@ func (m MiddlewareGroup) ExecContext(ctx *StatementContext, next ExecHandler) Ex
 	return next
 }
 
+// ensure scopedMiddleware implements Middleware.
+var _ Middleware = (*scopedMiddleware)(nil) // compile time check
+
+// scopedMiddleware applies its middleware to the statements whose name matches its pattern.
+type scopedMiddleware struct {
+	pattern    string
+	middleware Middleware
+}
+
+// matches reports whether the middleware applies to the statement of ctx.
+func (m *scopedMiddleware) matches(ctx *StatementContext) bool {
+	matched, _ := path.Match(m.pattern, ctx.Statement().Name())
+	return matched
+}
+
+// QueryContext implements Middleware.
+func (m *scopedMiddleware) QueryContext(ctx *StatementContext, next QueryHandler) QueryHandler {
+	if !m.matches(ctx) {
+		return next
+	}
+	return m.middleware.QueryContext(ctx, next)
+}
+
+// ExecContext implements Middleware.
+func (m *scopedMiddleware) ExecContext(ctx *StatementContext, next ExecHandler) ExecHandler {
+	if !m.matches(ctx) {
+		return next
+	}
+	return m.middleware.ExecContext(ctx, next)
+}
+
+// ScopedMiddleware returns a middleware applying middleware only to the statements
+// whose fully qualified name matches pattern, with the syntax of path.Match:
+// user.* matches all the statements of the namespaces starting with user.
+// It returns path.ErrBadPattern if the pattern is malformed.
+func ScopedMiddleware(pattern string, middleware Middleware) (Middleware, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("%w: %q", err, pattern)
+	}
+	return &scopedMiddleware{pattern: pattern, middleware: middleware}, nil
+}
+
 // NoopMiddleware is a middleware that performs no operations.
 // It returns the original next handler.
 type NoopMiddleware struct{}
@@ -291,6 +335,10 @@ func (t TimeoutMiddleware) getTimeout(stmt Statement) (timeout int64) {
 // ensure useGeneratedKeysMiddleware implements Middleware
 var _ Middleware = (*useGeneratedKeysMiddleware)(nil) // compile time check
 
+// ErrGeneratedKeysUnsupported is returned when useGeneratedKeys is enabled for a driver
+// which does not report the generated keys through LastInsertId.
+var ErrGeneratedKeysUnsupported = errors.New("juice: useGeneratedKeys is not supported by the driver")
+
 // errStructPointerOrSliceArrayRequired is an error that the param is not a struct pointer or a slice array type.
 var errStructPointerOrSliceArrayRequired = errors.New(
 	"useGeneratedKeys is true, but the param is not a struct pointer or a slice array type",
@@ -323,7 +371,17 @@ func (m *useGeneratedKeysMiddleware) ExecContext(ctx *StatementContext, next Exe
 		return next
 	}
 
+	if drv := ctx.Engine().Driver(); !driver.CapabilitiesOf(drv).LastInsertID {
+		// fail before executing the statement, the keys could not be set after it.
+		err := fmt.Errorf("%w: %s", ErrGeneratedKeysUnsupported, drv.Name())
+		if driver.CapabilitiesOf(drv).Returning {
+			err = fmt.Errorf("%w, use a RETURNING clause with ExecReturning instead", err)
+		}
+		return func(context.Context, string, ...any) (sql.Result, error) { return nil, err }
+	}
+
 	param := ctx.Param()
+	naming := namingStrategyOf(ctx.Engine())
 
 	return func(ctx context.Context, query string, args ...any) (sql.Result, error) {
 		result, err := next(ctx, query, args...)
@@ -370,6 +428,7 @@ func (m *useGeneratedKeysMiddleware) ExecContext(ctx *StatementContext, next Exe
 			keyGenerator = &singleKeyGenerator{
 				keyProperty: keyProperty,
 				id:          id,
+				naming:      naming,
 			}
 		case reflect.Array, reflect.Slice:
 			// Use the configured key increment, or 1 when it is absent or invalid.
@@ -383,6 +442,7 @@ func (m *useGeneratedKeysMiddleware) ExecContext(ctx *StatementContext, next Exe
 				id:                            id,
 				keyIncrement:                  keyIncrement,
 				batchInsertIDGenerateStrategy: batchInsertIDStrategy,
+				naming:                        naming,
 			}
 		default:
 			return nil, errStructPointerOrSliceArrayRequired
@@ -433,8 +493,15 @@ func statementAffectsData(stmt Statement) bool {
 //
 // The middleware ensures that datasource switching only occurs outside of transactions
 // to maintain data consistency and connection stability.
+//
+// With a context returned by ContextWithReadYourWrites, the reads following a write of the context
+// stay on the primary datasource instead of a replica chosen by the selectDataSource setting or randomly.
 type TxSensitiveDataSourceSwitchMiddleware struct {
 	NoopMiddleware
+
+	// StickyWindow bounds how long the reads stay on the primary datasource after a write.
+	// Zero keeps them on the primary for the lifetime of the context.
+	StickyWindow time.Duration
 }
 
 // selectRandomDataSource randomly selects a datasource from all available sources.
@@ -520,14 +587,18 @@ func (t *TxSensitiveDataSourceSwitchMiddleware) switchDataSource(ctx *StatementC
 func (t *TxSensitiveDataSourceSwitchMiddleware) QueryContext(statementContext *StatementContext, next QueryHandler) QueryHandler {
 	stmt := statementContext.Statement()
 	dataSource := stmt.Attribute("dataSource")
+	// replica reports whether the datasource is a read replica chosen by the global rule or randomly,
+	// which is skipped after a write to read your writes.
+	replica := dataSource == RandomDataSource || dataSource == RandomSecondaryDataSource
 	if dataSource == "" {
 		// Statements such as PostgreSQL INSERT ... RETURNING may be declared
 		// as select statements but still affect data. They must not be routed
 		// through the global read-datasource rule.
 		if statementAffectsData(stmt) {
-			return next
+			return t.trackWrite(next)
 		}
 		dataSource = statementContext.Engine().GetConfiguration().Settings().Get("selectDataSource").String()
+		replica = true
 	}
 	if dataSource == "" {
 		return next
@@ -536,9 +607,36 @@ func (t *TxSensitiveDataSourceSwitchMiddleware) QueryContext(statementContext *S
 		if isInTransaction(statementContext.Session()) {
 			return next(ctx, query, args...)
 		}
+		if tracker, ok := writeTrackerFromContext(ctx); ok && replica && tracker.sticky(t.StickyWindow, time.Now()) {
+			return next(ctx, query, args...)
+		}
 		if err := t.switchDataSource(statementContext, dataSource); err != nil {
 			return nil, err
 		}
 		return next(ctx, query, args...)
 	}
 }
+
+// trackWrite records the writes of the select statements affecting data for read your writes.
+func (t *TxSensitiveDataSourceSwitchMiddleware) trackWrite(next QueryHandler) QueryHandler {
+	return func(ctx context.Context, query string, args ...any) (sql.Rows, error) {
+		rows, err := next(ctx, query, args...)
+		if tracker, ok := writeTrackerFromContext(ctx); ok && err == nil {
+			tracker.markWrite(time.Now())
+		}
+		return rows, err
+	}
+}
+
+// ExecContext implements Middleware.
+// ExecContext records the writes executed with a context returned by ContextWithReadYourWrites,
+// so that the following reads of the context stay on the primary datasource.
+func (t *TxSensitiveDataSourceSwitchMiddleware) ExecContext(_ *StatementContext, next ExecHandler) ExecHandler {
+	return func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+		result, err := next(ctx, query, args...)
+		if tracker, ok := writeTrackerFromContext(ctx); ok && err == nil {
+			tracker.markWrite(time.Now())
+		}
+		return result, err
+	}
+}
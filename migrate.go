@@ -0,0 +1,177 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-juicedev/juice/driver"
+)
+
+// migrationTable is the table recording the applied migrations, also the name of their advisory lock.
+const migrationTable = "juice_migrations"
+
+// migration is a .sql file of the migrations directory.
+type migration struct {
+	version int64
+	name    string
+	path    string
+}
+
+// Migrate applies the migrations of the directory dir of fsys which have not been applied yet.
+//
+// The migrations are the files named <version>_<name>.sql, applied in the order of their
+// integer version, like 0001_create_users.sql. Each migration runs in a transaction with the
+// insert of its version into the juice_migrations table, created if missing. Its statements
+// are split by driver.SplitScript following the dialect of the engine.
+//
+// When the driver implements driver.AdvisoryLocker, like MySQL, PostgreSQL and SQL Server,
+// the migrations are applied under an advisory lock, so that the instances of an application
+// starting together apply them once. Otherwise, concurrent runs may fail on the primary key
+// of the juice_migrations table.
+//
+// Note that some databases, like MySQL and Oracle, commit the DDL statements implicitly,
+// so that a failing migration may be partially applied.
+func (e *Engine) Migrate(ctx context.Context, fsys fs.FS, dir string) error {
+	migrations, err := readMigrations(fsys, dir)
+	if err != nil || len(migrations) == 0 {
+		return err
+	}
+	conn, err := e.DB().Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if lock, unlock := driver.AdvisoryLockStatements(e.Driver(), migrationTable); lock != "" {
+		if _, err = conn.ExecContext(ctx, lock); err != nil {
+			return fmt.Errorf("juice: lock migrations: %w", err)
+		}
+		// the lock is released with the context of the migrations, which may be canceled.
+		defer func() { _, _ = conn.ExecContext(context.WithoutCancel(ctx), unlock) }()
+	}
+
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return err
+	}
+	for _, migration := range migrations {
+		if _, ok := applied[migration.version]; ok {
+			continue
+		}
+		if err = e.applyMigration(ctx, conn, fsys, migration); err != nil {
+			return fmt.Errorf("juice: migration %s: %w", migration.path, err)
+		}
+	}
+	return nil
+}
+
+// readMigrations returns the migrations of dir ordered by version.
+func readMigrations(fsys fs.FS, dir string) ([]migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	var migrations []migration
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".sql")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		prefix, description, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil || version < 0 {
+			return nil, fmt.Errorf("juice: migration %s: the name must start with a version like 0001_", entry.Name())
+		}
+		migrations = append(migrations, migration{version: version, name: description, path: path.Join(dir, entry.Name())})
+	}
+	slices.SortFunc(migrations, func(a, b migration) int { return cmp.Compare(a.version, b.version) })
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].version == migrations[i-1].version {
+			return nil, fmt.Errorf("juice: migrations %s and %s have the same version", migrations[i-1].path, migrations[i].path)
+		}
+	}
+	return migrations, nil
+}
+
+// appliedMigrations returns the versions recorded in the migration table, creating it if missing.
+// The columns are text to be portable across the dialects.
+func appliedMigrations(ctx context.Context, conn *sql.Conn) (map[int64]struct{}, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version FROM "+migrationTable)
+	if err != nil {
+		create := "CREATE TABLE " + migrationTable + " (version VARCHAR(20) NOT NULL PRIMARY KEY, " +
+			"name VARCHAR(255) NOT NULL, applied_at VARCHAR(40) NOT NULL)"
+		if _, createErr := conn.ExecContext(ctx, create); createErr != nil {
+			return nil, fmt.Errorf("juice: create %s: %w", migrationTable, errors.Join(createErr, err))
+		}
+		return map[int64]struct{}{}, nil
+	}
+	defer func() { _ = rows.Close() }()
+	applied := make(map[int64]struct{})
+	for rows.Next() {
+		var value string
+		if err = rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		version, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("juice: invalid version %q in %s", value, migrationTable)
+		}
+		applied[version] = struct{}{}
+	}
+	return applied, rows.Err()
+}
+
+// applyMigration executes the statements of migration and records it, in a transaction.
+func (e *Engine) applyMigration(ctx context.Context, conn *sql.Conn, fsys fs.FS, migration migration) (err error) {
+	script, err := fs.ReadFile(fsys, migration.path)
+	if err != nil {
+		return err
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	for _, statement := range driver.SplitScript(e.Driver(), string(script)) {
+		if _, err = tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	translator := e.Driver().Translator()
+	insert := "INSERT INTO " + migrationTable + " (version, name, applied_at) VALUES (" +
+		translator.Translate("version") + ", " + translator.Translate("name") + ", " + translator.Translate("appliedAt") + ")"
+	appliedAt := time.Now().UTC().Format(time.RFC3339)
+	if _, err = tx.ExecContext(ctx, insert, strconv.FormatInt(migration.version, 10), migration.name, appliedAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
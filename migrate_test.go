@@ -0,0 +1,57 @@
+package juice
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestEngine_Migrate_migrate_test(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/0002_add_email.sql":    {Data: []byte("ALTER TABLE users ADD email TEXT;")},
+		"migrations/0001_create_users.sql": {Data: []byte("CREATE TABLE users (id INTEGER);\nCREATE INDEX users_id ON users (id);\n")},
+		"migrations/README.md":             {Data: []byte("not a migration")},
+	}
+	state := &shSQLDriverState{queryErr: errors.New("no such table: juice_migrations")}
+	engine := newStatementTestEngine(nil)
+	engine.db = openStatementTestDB(t, state)
+
+	if err := engine.Migrate(t.Context(), fsys, "migrations"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"CREATE TABLE juice_migrations (version VARCHAR(20) NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at VARCHAR(40) NOT NULL)",
+		"CREATE TABLE users (id INTEGER)",
+		"CREATE INDEX users_id ON users (id)",
+		"INSERT INTO juice_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+		"ALTER TABLE users ADD email TEXT",
+		"INSERT INTO juice_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+	}
+	if !slices.Equal(state.connExecQueries, want) {
+		t.Fatalf("unexpected statements:\n%s", strings.Join(state.connExecQueries, "\n"))
+	}
+	if state.commitCalls != 2 {
+		t.Fatalf("expected a transaction per migration, got %d commits", state.commitCalls)
+	}
+
+	// a failing statement rolls back its migration and stops
+	*state = shSQLDriverState{execErr: errors.New("syntax error")}
+	if err := engine.Migrate(t.Context(), fsys, "migrations"); err == nil || !strings.Contains(err.Error(), "0001_create_users.sql") {
+		t.Fatalf("expected migration error, got %v", err)
+	}
+	if state.rollbackCalls != 1 || state.commitCalls != 0 {
+		t.Fatalf("expected the migration rolled back, got %+v", state)
+	}
+
+	fsys["migrations/0001_duplicate.sql"] = &fstest.MapFile{Data: []byte("SELECT 1;")}
+	if err := engine.Migrate(t.Context(), fsys, "migrations"); err == nil || !strings.Contains(err.Error(), "same version") {
+		t.Fatalf("expected duplicate version error, got %v", err)
+	}
+	delete(fsys, "migrations/0001_duplicate.sql")
+	fsys["migrations/init.sql"] = &fstest.MapFile{Data: []byte("SELECT 1;")}
+	if err := engine.Migrate(t.Context(), fsys, "migrations"); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Fatalf("expected invalid name error, got %v", err)
+	}
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-juicedev/juice/session/tx"
+)
+
+// ErrMultiTxHeuristic is returned by MultiTxManager.Commit when some participants committed
+// and others did not. The environments are left inconsistent and must be recovered by the application,
+// from the MultiTxHeuristic record of the recovery log.
+var ErrMultiTxHeuristic = errors.New("juice: multi transaction partially committed")
+
+// MultiTxState is the state of a multi-environment transaction recorded in the recovery log.
+type MultiTxState int
+
+const (
+	// MultiTxPrepared is recorded once all the participants have begun and before the first commit.
+	// A prepared transaction without later record is in doubt: it may be committed on any subset of its participants.
+	MultiTxPrepared MultiTxState = iota + 1
+
+	// MultiTxCommitted is recorded once all the participants have committed.
+	MultiTxCommitted
+
+	// MultiTxRolledBack is recorded once all the participants have rolled back.
+	MultiTxRolledBack
+
+	// MultiTxHeuristic is recorded when some participants committed and the others rolled back.
+	MultiTxHeuristic
+)
+
+// String returns the name of the state.
+func (s MultiTxState) String() string {
+	switch s {
+	case MultiTxPrepared:
+		return "prepared"
+	case MultiTxCommitted:
+		return "committed"
+	case MultiTxRolledBack:
+		return "rolled back"
+	case MultiTxHeuristic:
+		return "heuristic"
+	default:
+		return fmt.Sprintf("MultiTxState(%d)", int(s))
+	}
+}
+
+// MultiTxRecord is a state transition of a multi-environment transaction.
+type MultiTxRecord struct {
+	// ID identifies the transaction across its records.
+	ID string
+
+	// State is the state the transaction entered.
+	State MultiTxState
+
+	// Environments are the participants, in commit order.
+	Environments []string
+
+	// Committed are the participants which committed, set for MultiTxCommitted and MultiTxHeuristic.
+	Committed []string
+
+	// Err is the error which made the transaction roll back or end up heuristic.
+	Err error
+}
+
+// MultiTxRecoveryLog records the state transitions of the multi-environment transactions,
+// so that an in-doubt or heuristic transaction can be found and repaired after a failure.
+// A durable log should write the record before returning.
+type MultiTxRecoveryLog interface {
+	Record(ctx context.Context, record MultiTxRecord) error
+}
+
+// MultiTxRecoveryLogFunc is a function implementing MultiTxRecoveryLog.
+type MultiTxRecoveryLogFunc func(ctx context.Context, record MultiTxRecord) error
+
+// Record implements MultiTxRecoveryLog.
+func (f MultiTxRecoveryLogFunc) Record(ctx context.Context, record MultiTxRecord) error {
+	return f(ctx, record)
+}
+
+// SetMultiTxRecoveryLog sets the log recording the state transitions of the transactions of MultiTx.
+func (e *Engine) SetMultiTxRecoveryLog(log MultiTxRecoveryLog) {
+	e.multiTxLog = log
+}
+
+// MultiTx returns a MultiTxManager spanning the environments envs, see ContextMultiTx.
+func (e *Engine) MultiTx(envs ...string) (*MultiTxManager, error) {
+	return e.ContextMultiTx(context.Background(), envs...)
+}
+
+// ContextMultiTx returns a MultiTxManager spanning the environments envs with the given context.
+// Each environment runs its own transaction, begun with its default transaction options.
+//
+// It is a best-effort two-phase commit, not XA: database/sql can not prepare a transaction,
+// so the environments can not vote before the commit. The guarantees are:
+//   - a failure before the first commit, including the failure to record MultiTxPrepared,
+//     rolls back every environment;
+//   - the environments commit in the order of envs, so a failing commit of the first one
+//     rolls back the others. Put first the environment most likely to refuse the commit,
+//     like the one checking deferred constraints;
+//   - a failing commit after the first one leaves the environments committed before it
+//     committed and rolls back the others. Commit returns ErrMultiTxHeuristic and the recovery
+//     log records MultiTxHeuristic, which the application must repair, like with a compensation.
+//
+// A crash during the commit leaves a MultiTxPrepared record without later record,
+// whose environments must be checked by the application.
+func (e *Engine) ContextMultiTx(ctx context.Context, envs ...string) (*MultiTxManager, error) {
+	if len(envs) == 0 {
+		return nil, errors.New("juice: multi transaction without environment")
+	}
+	manager := &MultiTxManager{
+		ctx:          ctx,
+		log:          e.multiTxLog,
+		environments: slices.Clone(envs),
+		participants: make(map[string]*BasicTxManager, len(envs)),
+	}
+	for _, env := range envs {
+		if _, exists := manager.participants[env]; exists {
+			return nil, fmt.Errorf("juice: duplicate multi transaction environment %s", env)
+		}
+		engine, err := e.With(env)
+		if err != nil {
+			return nil, err
+		}
+		manager.participants[env] = engine.ContextTx(ctx, nil)
+	}
+	return manager, nil
+}
+
+// MultiTxManager coordinates the transactions of several environments, committed or rolled back together.
+// It is not safe for concurrent use.
+type MultiTxManager struct {
+	ctx          context.Context
+	log          MultiTxRecoveryLog
+	id           string
+	environments []string
+	participants map[string]*BasicTxManager
+	begun        bool
+}
+
+// ID returns the identifier of the transaction in the recovery log, empty before Begin.
+func (m *MultiTxManager) ID() string {
+	return m.id
+}
+
+// Manager returns the manager executing the statements in the transaction of the environment env.
+// Its transaction is committed and rolled back by the MultiTxManager only.
+func (m *MultiTxManager) Manager(env string) (Manager, error) {
+	participant, ok := m.participants[env]
+	if !ok {
+		return nil, fmt.Errorf("juice: environment %s is not part of the multi transaction", env)
+	}
+	return participant, nil
+}
+
+// Begin begins the transactions of all the environments.
+// If one of them fails to begin, the ones already begun are rolled back.
+func (m *MultiTxManager) Begin() error {
+	if m.begun {
+		return tx.ErrTransactionAlreadyBegun
+	}
+	for index, env := range m.environments {
+		if err := m.participants[env].Begin(); err != nil {
+			return errors.Join(fmt.Errorf("environment %s: %w", env, err), m.rollback(m.environments[:index]))
+		}
+	}
+	m.id = strings.ToLower(rand.Text())
+	m.begun = true
+	return nil
+}
+
+// Commit commits the transactions of all the environments in order, see ContextMultiTx for the guarantees.
+func (m *MultiTxManager) Commit() error {
+	if !m.begun {
+		return tx.ErrTransactionNotBegun
+	}
+	m.begun = false
+	if err := m.record(MultiTxPrepared, nil, nil); err != nil {
+		err = fmt.Errorf("juice: record prepared multi transaction: %w", err)
+		return errors.Join(err, m.rollback(m.environments), m.record(MultiTxRolledBack, nil, err))
+	}
+	for index, env := range m.environments {
+		if err := m.participants[env].Commit(); err != nil {
+			err = fmt.Errorf("environment %s: %w", env, err)
+			rollbackErr := m.rollback(m.environments[index+1:])
+			if index == 0 {
+				return errors.Join(err, rollbackErr, m.record(MultiTxRolledBack, nil, err))
+			}
+			committed := m.environments[:index]
+			err = fmt.Errorf("%w: committed %s: %w", ErrMultiTxHeuristic, strings.Join(committed, ", "), err)
+			return errors.Join(err, rollbackErr, m.record(MultiTxHeuristic, committed, err))
+		}
+	}
+	// the outcome is decided, so a failure to record it only leaves the transaction in doubt in the log.
+	_ = m.record(MultiTxCommitted, m.environments, nil)
+	return nil
+}
+
+// Rollback rolls back the transactions of all the environments.
+func (m *MultiTxManager) Rollback() error {
+	if !m.begun {
+		return tx.ErrTransactionNotBegun
+	}
+	m.begun = false
+	err := m.rollback(m.environments)
+	return errors.Join(err, m.record(MultiTxRolledBack, nil, nil))
+}
+
+// rollback rolls back the transactions of the environments envs, joining their errors.
+func (m *MultiTxManager) rollback(envs []string) error {
+	var errs []error
+	for _, env := range envs {
+		if err := m.participants[env].Rollback(); err != nil {
+			errs = append(errs, fmt.Errorf("environment %s: %w", env, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// record writes a record of the transaction to the recovery log, if any.
+func (m *MultiTxManager) record(state MultiTxState, committed []string, err error) error {
+	if m.log == nil {
+		return nil
+	}
+	return m.log.Record(m.ctx, MultiTxRecord{
+		ID:           m.id,
+		State:        state,
+		Environments: slices.Clone(m.environments),
+		Committed:    slices.Clone(committed),
+		Err:          err,
+	})
+}
@@ -0,0 +1,132 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/go-juicedev/juice/session/tx"
+)
+
+func newMultiTxTestEngine(t *testing.T, states map[string]*shSQLDriverState) *Engine {
+	t.Helper()
+	engine := newStatementTestEngine(nil)
+	engine.configuration.(*xmlConfiguration).environments = &environments{envs: map[string]*Environment{}}
+	engine.manager = &DBManager{sources: map[string]Source{}}
+	for name, state := range states {
+		c := &conn{db: openStatementTestDB(t, state), drv: engine.driver}
+		c.once.Do(func() {})
+		engine.manager.sources[name] = Source{}
+		engine.manager.conns.Store(name, c)
+	}
+	return engine
+}
+
+func TestEngine_MultiTx_multi_tx_test(t *testing.T) {
+	a, b := &shSQLDriverState{}, &shSQLDriverState{}
+	engine := newMultiTxTestEngine(t, map[string]*shSQLDriverState{"a": a, "b": b})
+	var records []MultiTxRecord
+	engine.SetMultiTxRecoveryLog(MultiTxRecoveryLogFunc(func(_ context.Context, record MultiTxRecord) error {
+		records = append(records, record)
+		return nil
+	}))
+	states := func() []MultiTxState {
+		var states []MultiTxState
+		for _, record := range records {
+			states = append(states, record.State)
+		}
+		return states
+	}
+
+	if _, err := engine.MultiTx("a", "a"); err == nil {
+		t.Fatal("expected duplicate environment error")
+	}
+	if _, err := engine.MultiTx("a", "missing"); !errors.Is(err, ErrSourceNotFound) {
+		t.Fatalf("expected ErrSourceNotFound, got %v", err)
+	}
+
+	manager, err := engine.MultiTx("a", "b")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = manager.Commit(); !errors.Is(err, tx.ErrTransactionNotBegun) {
+		t.Fatalf("expected ErrTransactionNotBegun, got %v", err)
+	}
+	if err = manager.Begin(); err != nil {
+		t.Fatalf("unexpected Begin error: %v", err)
+	}
+	if _, err = manager.Manager("b"); err != nil {
+		t.Fatalf("unexpected Manager error: %v", err)
+	}
+	if _, err = manager.Manager("c"); err == nil {
+		t.Fatal("expected error for an environment outside of the transaction")
+	}
+	if err = manager.Commit(); err != nil {
+		t.Fatalf("unexpected Commit error: %v", err)
+	}
+	if a.commitCalls != 1 || b.commitCalls != 1 {
+		t.Fatalf("expected both committed, got %d and %d", a.commitCalls, b.commitCalls)
+	}
+	if got := states(); !slices.Equal(got, []MultiTxState{MultiTxPrepared, MultiTxCommitted}) {
+		t.Fatalf("unexpected records: %v", got)
+	}
+	if records[0].ID == "" || records[0].ID != records[1].ID || !slices.Equal(records[1].Committed, []string{"a", "b"}) {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+
+	// the failing commit of the first environment rolls back the others
+	*a, *b, records = shSQLDriverState{commitErr: errors.New("a failed")}, shSQLDriverState{}, nil
+	manager, _ = engine.MultiTx("a", "b")
+	if err = manager.Begin(); err != nil {
+		t.Fatalf("unexpected Begin error: %v", err)
+	}
+	if err = manager.Commit(); err == nil || errors.Is(err, ErrMultiTxHeuristic) {
+		t.Fatalf("expected rolled back commit error, got %v", err)
+	}
+	if b.commitCalls != 0 || b.rollbackCalls != 1 {
+		t.Fatalf("expected b rolled back, got %+v", b)
+	}
+	if got := states(); !slices.Equal(got, []MultiTxState{MultiTxPrepared, MultiTxRolledBack}) {
+		t.Fatalf("unexpected records: %v", got)
+	}
+
+	// the failing commit of a later environment is heuristic
+	*a, *b, records = shSQLDriverState{}, shSQLDriverState{commitErr: errors.New("b failed")}, nil
+	manager, _ = engine.MultiTx("a", "b")
+	if err = manager.Begin(); err != nil {
+		t.Fatalf("unexpected Begin error: %v", err)
+	}
+	if err = manager.Commit(); !errors.Is(err, ErrMultiTxHeuristic) {
+		t.Fatalf("expected ErrMultiTxHeuristic, got %v", err)
+	}
+	if got := states(); !slices.Equal(got, []MultiTxState{MultiTxPrepared, MultiTxHeuristic}) || !slices.Equal(records[1].Committed, []string{"a"}) {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+
+	// a failure to record the prepared state rolls back every environment
+	*a, *b, records = shSQLDriverState{}, shSQLDriverState{}, nil
+	logErr := errors.New("log unavailable")
+	engine.SetMultiTxRecoveryLog(MultiTxRecoveryLogFunc(func(context.Context, MultiTxRecord) error { return logErr }))
+	manager, _ = engine.MultiTx("a", "b")
+	if err = manager.Begin(); err != nil {
+		t.Fatalf("unexpected Begin error: %v", err)
+	}
+	if err = manager.Commit(); !errors.Is(err, logErr) {
+		t.Fatalf("expected log error, got %v", err)
+	}
+	if a.commitCalls != 0 || a.rollbackCalls != 1 || b.commitCalls != 0 || b.rollbackCalls != 1 {
+		t.Fatalf("expected both rolled back, got %+v and %+v", a, b)
+	}
+
+	// a failing Begin rolls back the environments already begun
+	*a, *b = shSQLDriverState{}, shSQLDriverState{beginErr: errors.New("b unavailable")}
+	engine.SetMultiTxRecoveryLog(nil)
+	manager, _ = engine.MultiTx("a", "b")
+	if err = manager.Begin(); err == nil {
+		t.Fatal("expected Begin error")
+	}
+	if a.rollbackCalls != 1 {
+		t.Fatalf("expected a rolled back, got %+v", a)
+	}
+}
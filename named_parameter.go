@@ -0,0 +1,94 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"strings"
+)
+
+// expandNamedParameters rewrites the :name parameters of a raw query into #{name},
+// so that raw queries bind them from the parameter like the XML statements.
+// Names start with a letter or an underscore and may be paths like :user.id.
+// The colons of string literals, quoted identifiers, comments, #{} and ${} expressions,
+// casts like ::int and assignments like := are kept.
+func expandNamedParameters(query string) string {
+	if !strings.Contains(query, ":") {
+		return query
+	}
+	var builder strings.Builder
+	builder.Grow(len(query) + 8)
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := skipQuoted(query, i)
+			builder.WriteString(query[i:end])
+			i = end
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			builder.WriteString(query[i : i+end])
+			i += end
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query)
+			} else {
+				end += i + 4
+			}
+			builder.WriteString(query[i:end])
+			i = end
+		case (c == '#' || c == '$') && strings.HasPrefix(query[i+1:], "{"):
+			end := strings.IndexByte(query[i:], '}')
+			if end < 0 {
+				end = len(query)
+			} else {
+				end += i + 1
+			}
+			builder.WriteString(query[i:end])
+			i = end
+		case c == ':' && i+1 < len(query) && query[i+1] == ':':
+			builder.WriteString("::")
+			i += 2
+		case c == ':' && i+1 < len(query) && isNameStart(query[i+1]):
+			end := i + 2
+			for end < len(query) && (isNamePart(query[end]) || query[end] == '.' && end+1 < len(query) && isNameStart(query[end+1])) {
+				end++
+			}
+			builder.WriteString("#{")
+			builder.WriteString(query[i+1 : end])
+			builder.WriteByte('}')
+			i = end
+		default:
+			builder.WriteByte(c)
+			i++
+		}
+	}
+	return builder.String()
+}
+
+// isNameStart reports whether c can start a parameter name.
+func isNameStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// isNamePart reports whether c can continue a parameter name.
+func isNamePart(c byte) bool {
+	return isNameStart(c) || '0' <= c && c <= '9'
+}
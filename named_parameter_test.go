@@ -0,0 +1,44 @@
+package juice
+
+import (
+	"testing"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+func TestExpandNamedParameters_named_parameter_test(t *testing.T) {
+	tests := map[string]string{
+		"SELECT * FROM users WHERE id = :id":                 "SELECT * FROM users WHERE id = #{id}",
+		"WHERE id = :user.id AND name = :name_1.":            "WHERE id = #{user.id} AND name = #{name_1}.",
+		"SELECT '12:30', \":col\", `:x` FROM t WHERE a = :a": "SELECT '12:30', \":col\", `:x` FROM t WHERE a = #{a}",
+		"SELECT 'it''s :not' WHERE b = :b":                   "SELECT 'it''s :not' WHERE b = #{b}",
+		"SELECT a::int, @v := 1, arr[1:2] FROM t":            "SELECT a::int, @v := 1, arr[1:2] FROM t",
+		"SELECT 1 -- :comment\nWHERE c = :c /* :block */":    "SELECT 1 -- :comment\nWHERE c = #{c} /* :block */",
+		"WHERE id = #{id} AND t = ${table} AND n = :n":       "WHERE id = #{id} AND t = ${table} AND n = #{n}",
+		"SELECT 1": "SELECT 1",
+	}
+	for query, want := range tests {
+		if got := expandNamedParameters(query); got != want {
+			t.Errorf("expandNamedParameters(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestRawSQLStatement_NamedParameters_named_parameter_test(t *testing.T) {
+	stmt := NewRawSQLStatement("SELECT * FROM users WHERE id = :id AND name = #{user.name} AND age > :user.age", jsql.Select)
+	query, args, err := stmt.Build(driver.TranslateFunc(func(string) string { return "?" }), eval.NewGenericParam(eval.H{
+		"id":   7,
+		"user": map[string]any{"name": "alice", "age": 18},
+	}, ""))
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+	if query != "SELECT * FROM users WHERE id = ? AND name = ? AND age > ?" {
+		t.Fatalf("unexpected query: %q", query)
+	}
+	if len(args) != 3 || args[0] != 7 || args[1] != "alice" || args[2] != 18 {
+		t.Fatalf("unexpected args: %v", args)
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/go-juicedev/juice/sql"
+)
+
+// namingStrategySetting is the setting naming the built-in naming strategy of the engine:
+// "snake", "lowerCamel" or "exact". Any other value leaves the untagged fields unmapped.
+const namingStrategySetting = "namingStrategy"
+
+// SetNamingStrategy sets the strategy naming the columns of the struct fields without column tag,
+// used to bind the rows of the queries and to write the generated keys back.
+// It takes precedence over the namingStrategy setting.
+func (e *Engine) SetNamingStrategy(strategy sql.NamingStrategy) {
+	e.namingStrategy = strategy
+}
+
+// namingStrategyOf returns the naming strategy of engine, or nil if it has none.
+func namingStrategyOf(engine *Engine) sql.NamingStrategy {
+	if engine.namingStrategy != nil {
+		return engine.namingStrategy
+	}
+	return settingNamingStrategy(engine.GetConfiguration())
+}
+
+// settingNamingStrategy returns the naming strategy of the namingStrategy setting of configuration,
+// or nil if it has none.
+func settingNamingStrategy(configuration Configuration) sql.NamingStrategy {
+	if configuration == nil {
+		return nil
+	}
+	strategy, _ := sql.LookupNamingStrategy(configuration.Settings().Get(namingStrategySetting).String())
+	return strategy
+}
+
+// withNamingStrategy wraps next so that its rows are bound with strategy,
+// see sql.WithNamingStrategy.
+func withNamingStrategy(strategy sql.NamingStrategy, next QueryHandler) QueryHandler {
+	if strategy == nil {
+		return next
+	}
+	return func(ctx context.Context, query string, args ...any) (sql.Rows, error) {
+		rows, err := next(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		return sql.WithNamingStrategy(rows, strategy), nil
+	}
+}
+
+// fieldIndexByColumn returns the index of the exported field of the struct type tp named for column by naming.
+func fieldIndexByColumn(tp reflect.Type, column string, naming sql.NamingStrategy) ([]int, bool) {
+	name := naming.ColumnToField(column)
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		if field.Name == name || naming.FieldToColumn(field.Name) == column {
+			return field.Index, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,76 @@
+package juice
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+type namingStrategyUser struct {
+	UserID   int64
+	FullName string
+}
+
+func TestNamingStrategy_QueryContext_naming_strategy_test(t *testing.T) {
+	engine := newStatementTestEngine(nil)
+	query := func() namingStrategyUser {
+		t.Helper()
+		h := newExecuteStatementHandler("SELECT user_id, full_name FROM users", nil, engine, nil)
+		rows, err := h.withQueryHandler(func(context.Context, string, ...any) (jsql.Rows, error) {
+			return jsql.NewRowsBuffer([]string{"user_id", "full_name"}, [][]any{{int64(1), "Alice"}}), nil
+		}).QueryContext(t.Context(), shStatement{}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer func() { _ = rows.Close() }()
+		user, err := jsql.Bind[namingStrategyUser](rows)
+		if err != nil {
+			t.Fatalf("unexpected bind error: %v", err)
+		}
+		return user
+	}
+
+	if user := query(); user.UserID != 0 {
+		t.Fatalf("expected no naming strategy by default, got %+v", user)
+	}
+
+	engine.configuration.(*xmlConfiguration).settings[namingStrategySetting] = "snake"
+	if user := query(); user.UserID != 1 || user.FullName != "Alice" {
+		t.Fatalf("expected the snake setting to name the columns, got %+v", user)
+	}
+
+	engine.SetNamingStrategy(jsql.ExactNaming)
+	if user := query(); user.UserID != 0 {
+		t.Fatalf("expected the engine strategy to take precedence over the setting, got %+v", user)
+	}
+}
+
+func TestNamingStrategy_GenerateKeyTo_naming_strategy_test(t *testing.T) {
+	user := &namingStrategyUser{}
+	generator := singleKeyGenerator{id: 7, keyProperty: "user_id"}
+	if err := generator.GenerateKeyTo(reflect.ValueOf(user)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.UserID != 0 {
+		t.Fatalf("expected no write back without naming strategy, got %d", user.UserID)
+	}
+
+	generator.naming = jsql.SnakeCaseNaming
+	if err := generator.GenerateKeyTo(reflect.ValueOf(user)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.UserID != 7 {
+		t.Fatalf("expected the key written to UserID, got %d", user.UserID)
+	}
+
+	users := []namingStrategyUser{{}, {}}
+	batch := batchKeyGenerator{id: 11, keyProperty: "user_id", keyIncrement: 1, naming: jsql.SnakeCaseNaming}
+	if err := batch.GenerateKeyTo(reflect.ValueOf(&users)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if users[0].UserID != 10 || users[1].UserID != 11 {
+		t.Fatalf("unexpected batch keys: %+v", users)
+	}
+}
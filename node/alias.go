@@ -0,0 +1,60 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NewAliasNode returns the node rendering the select list of columns, like the columns of
+// a registered model expanded by <alias type="models.User" table="u" prefix="user_"/>:
+//
+//	u.id AS user_id, u.name AS user_name
+//
+// Each column is qualified by table, if any, and aliased by itself after prefix, if any,
+// so that the columns of joined tables are bound without conflict.
+// The list is built once and rendered as plain text.
+func NewAliasNode(columns []string, table, prefix string) (Node, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("no column")
+	}
+	if table != "" && !identifierRegexp.MatchString(table) {
+		return nil, fmt.Errorf("invalid table %q", table)
+	}
+	if prefix != "" && (!identifierRegexp.MatchString(prefix) || strings.Contains(prefix, ".")) {
+		return nil, fmt.Errorf("invalid prefix %q", prefix)
+	}
+	var builder strings.Builder
+	for i, column := range columns {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		if table != "" {
+			builder.WriteString(table)
+			builder.WriteString(".")
+		}
+		builder.WriteString(column)
+		if prefix != "" {
+			builder.WriteString(" AS ")
+			builder.WriteString(prefix)
+			builder.WriteString(column)
+		}
+	}
+	return pureTextNode(builder.String()), nil
+}
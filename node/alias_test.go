@@ -0,0 +1,59 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"testing"
+
+	"github.com/go-juicedev/juice/eval"
+)
+
+func TestNewAliasNode_alias_test(t *testing.T) {
+	columns := []string{"id", "name"}
+	tests := []struct {
+		name   string
+		table  string
+		prefix string
+		want   string
+	}{
+		{name: "plain", want: "id, name"},
+		{name: "table", table: "u", want: "u.id, u.name"},
+		{name: "prefix", prefix: "user_", want: "id AS user_id, name AS user_name"},
+		{name: "table and prefix", table: "u", prefix: "user_", want: "u.id AS user_id, u.name AS user_name"},
+	}
+	for _, tt := range tests {
+		alias, err := NewAliasNode(columns, tt.table, tt.prefix)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !IsStatic(alias) {
+			t.Errorf("%s: expected a static node", tt.name)
+		}
+		query, args, err := alias.Accept(nil, eval.NewGenericParam(eval.H{}, ""))
+		if err != nil || query != tt.want || len(args) != 0 {
+			t.Errorf("%s: got %q %v %v, want %q", tt.name, query, args, err, tt.want)
+		}
+	}
+	for _, invalid := range []struct{ table, prefix string }{{table: "u; --"}, {prefix: "user."}, {prefix: "1"}} {
+		if _, err := NewAliasNode(columns, invalid.table, invalid.prefix); err == nil {
+			t.Errorf("expected error for table %q and prefix %q", invalid.table, invalid.prefix)
+		}
+	}
+	if _, err := NewAliasNode(nil, "", ""); err == nil {
+		t.Error("expected error without column")
+	}
+}
@@ -17,10 +17,16 @@ limitations under the License.
 package node
 
 import (
+	"errors"
+
 	"github.com/go-juicedev/juice/driver"
 	"github.com/go-juicedev/juice/eval"
 )
 
+// ErrNoChooseBranch is returned by a ChooseNode without otherwise whose
+// conditions all fail, when BuildOptions.StrictChoose is enabled.
+var ErrNoChooseBranch = errors.New("juice: no choose branch matched")
+
 // ChooseNode implements a switch-like conditional structure for SQL generation.
 // It evaluates multiple conditions in order and executes the first matching case,
 // with an optional default case (otherwise).
@@ -45,9 +51,10 @@ import (
 //
 // Behavior:
 //  1. Evaluates each <when> condition in order
-//  2. Executes SQL from first matching condition
+//  2. Executes SQL from first matching condition, even if it renders nothing
 //  3. If no conditions match, executes <otherwise> if present
-//  4. If no conditions match and no otherwise, returns empty result
+//  4. If no conditions match and no otherwise, returns empty result,
+//     or ErrNoChooseBranch if BuildOptions.StrictChoose is enabled
 //
 // Usage scenarios:
 //  1. Complex conditional logic in WHERE clauses
@@ -67,7 +74,7 @@ import (
 // Note: Similar to a switch statement in programming languages,
 // only the first matching condition is executed.
 type ChooseNode struct {
-	WhenNodes     []Node
+	WhenNodes     []*WhenNode
 	OtherwiseNode Node
 	BindNodes     BindNodeGroup
 }
@@ -77,13 +84,14 @@ func (c ChooseNode) Accept(translator driver.Translator, p eval.Parameter) (quer
 	p = c.BindNodes.ConvertParameter(p)
 
 	for _, node := range c.WhenNodes {
-		q, a, err := node.Accept(translator, p)
+		whenParameter := node.BindNodes.ConvertParameter(p)
+		matched, err := node.Match(whenParameter)
 		if err != nil {
 			return "", nil, err
 		}
-		// if one of when Nodes is true, return query and arguments
-		if len(q) > 0 {
-			return q, a, nil
+		// the first matched when is chosen, whatever it renders.
+		if matched {
+			return node.Nodes.Accept(translator, whenParameter)
 		}
 	}
 
@@ -91,6 +99,9 @@ func (c ChooseNode) Accept(translator driver.Translator, p eval.Parameter) (quer
 	if c.OtherwiseNode != nil {
 		return c.OtherwiseNode.Accept(translator, p)
 	}
+	if buildOptionsOf(translator).StrictChoose {
+		return "", nil, ErrNoChooseBranch
+	}
 	return "", nil, nil
 }
 
@@ -17,6 +17,7 @@ limitations under the License.
 package node
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -60,7 +61,7 @@ func TestChooseNode_Accept_choose_test(t *testing.T) {
 
 	tests := []struct {
 		name           string
-		whenNodes      []Node
+		whenNodes      []*WhenNode
 		otherwiseNode  Node
 		params         eval.Parameter
 		expectedQuery  string
@@ -70,7 +71,7 @@ func TestChooseNode_Accept_choose_test(t *testing.T) {
 	}{
 		{
 			name: "FirstWhenMatches",
-			whenNodes: []Node{
+			whenNodes: []*WhenNode{
 				newTestWhenNode("choice == 1", "Content for choice 1: #{name}", paramsWithChoice(1)),
 				newTestWhenNode("choice == 2", "Content for choice 2", paramsWithChoice(1)),
 			},
@@ -81,7 +82,7 @@ func TestChooseNode_Accept_choose_test(t *testing.T) {
 		},
 		{
 			name: "SecondWhenMatches",
-			whenNodes: []Node{
+			whenNodes: []*WhenNode{
 				newTestWhenNode("choice == 1", "Content for choice 1", paramsWithChoice(2)),
 				newTestWhenNode("choice == 2", "Content for choice 2: #{name}", paramsWithChoice(2)),
 			},
@@ -92,7 +93,7 @@ func TestChooseNode_Accept_choose_test(t *testing.T) {
 		},
 		{
 			name: "NoWhenMatches_OtherwiseExecutes",
-			whenNodes: []Node{
+			whenNodes: []*WhenNode{
 				newTestWhenNode("choice == 1", "Content for choice 1", paramsWithChoice(3)),
 				newTestWhenNode("choice == 2", "Content for choice 2", paramsWithChoice(3)),
 			},
@@ -103,7 +104,7 @@ func TestChooseNode_Accept_choose_test(t *testing.T) {
 		},
 		{
 			name: "NoWhenMatches_NoOtherwise",
-			whenNodes: []Node{
+			whenNodes: []*WhenNode{
 				newTestWhenNode("choice == 1", "Content for choice 1", paramsWithChoice(3)),
 				newTestWhenNode("choice == 2", "Content for choice 2", paramsWithChoice(3)),
 			},
@@ -112,7 +113,7 @@ func TestChooseNode_Accept_choose_test(t *testing.T) {
 		},
 		{
 			name: "WhenNodeItselfReturnsError",
-			whenNodes: []Node{
+			whenNodes: []*WhenNode{
 				newTestWhenNode("choice == 0", "Should not be chosen", paramsWithChoice(1)),
 				errorWhenNode,
 				newTestWhenNode("choice == 2", "Should also not be chosen", paramsWithChoice(1)),
@@ -123,7 +124,7 @@ func TestChooseNode_Accept_choose_test(t *testing.T) {
 		},
 		{
 			name: "OtherwiseNodeReturnsError",
-			whenNodes: []Node{
+			whenNodes: []*WhenNode{
 				newTestWhenNode("choice == 1", "Content for choice 1", paramsWithChoice(3)),
 				newTestWhenNode("choice == 2", "Content for choice 2", paramsWithChoice(3)),
 			},
@@ -134,23 +135,33 @@ func TestChooseNode_Accept_choose_test(t *testing.T) {
 		},
 		{
 			name:          "EmptyWhenNodesList_OtherwiseExecutes",
-			whenNodes:     []Node{},
+			whenNodes:     []*WhenNode{},
 			otherwiseNode: newTestOtherwiseNode("Only otherwise"),
 			params:        emptyParams,
 			expectedQuery: "Only otherwise",
 		},
 		{
 			name: "OneWhenNode_NoMatch_NoOtherwise",
-			whenNodes: []Node{
+			whenNodes: []*WhenNode{
 				newTestWhenNode("choice == 1", "Content for choice 1", paramsWithChoice(2)),
 			},
 			params:        paramsWithChoice(2),
 			expectedQuery: "",
 		},
+		{
+			name: "MatchedWhenRendersNothing_NoFallThrough",
+			whenNodes: []*WhenNode{
+				newTestWhenNode("choice == 1", "", paramsWithChoice(1)),
+				newTestWhenNode("choice > 0", "Content for positive choice", paramsWithChoice(1)),
+			},
+			otherwiseNode: newTestOtherwiseNode("Otherwise content"),
+			params:        paramsWithChoice(1),
+			expectedQuery: "",
+		},
 		{
 			name: "WhenNodeConditionParseError",
-			whenNodes: []Node{
-				func() Node {
+			whenNodes: []*WhenNode{
+				func() *WhenNode {
 					cn := &ConditionNode{Nodes: Group{NewTextNode("content")}}
 					err := cn.Parse("invalid condition syntax @#$")
 					if err == nil {
@@ -198,3 +209,27 @@ func TestChooseNode_Accept_choose_test(t *testing.T) {
 		})
 	}
 }
+
+func TestChooseNode_StrictChoose_choose_test(t *testing.T) {
+	when := &WhenNode{Nodes: Group{NewTextNode("AND id = #{id}")}}
+	if err := when.Parse("id > 0"); err != nil {
+		t.Fatal(err)
+	}
+	translator := WithBuildOptions(driver.MySQLDriver{}.Translator(), BuildOptions{StrictChoose: true})
+	params := eval.NewGenericParam(eval.H{"id": 0}, "")
+
+	node := ChooseNode{WhenNodes: []*WhenNode{when}}
+	if _, _, err := node.Accept(translator, params); !errors.Is(err, ErrNoChooseBranch) {
+		t.Fatalf("err = %v, want %v", err, ErrNoChooseBranch)
+	}
+
+	// an otherwise is always a match.
+	node.OtherwiseNode = &OtherwiseNode{Nodes: Group{NewTextNode("AND 1 = 1")}}
+	query, _, err := node.Accept(translator, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "AND 1 = 1" {
+		t.Errorf("query = %q, want %q", query, "AND 1 = 1")
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+)
+
+// ErrInvalidFields is returned when the fields parameter of a ColumnsNode names a field which is not allowed.
+var ErrInvalidFields = errors.New("juice: invalid fields")
+
+// ColumnsNode renders the select list of the fields of a parameter, like the sparse fieldset of an API,
+// whose fields are validated against an allow-list and mapped to their columns, so that the statement
+// selects only the requested columns without injecting SQL.
+//
+// Fields:
+//   - Param: The parameter holding the fields, a comma separated string or a slice of strings
+//   - Names: The allowed fields, in order
+//   - Columns: The allowed fields mapped to the columns they select
+//   - Default: The fields used when the parameter is missing or empty
+//
+// Example XML:
+//
+//	<select id="List">
+//	  SELECT <columns param="fields" allowed="id, name, email, createdAt:created_at" default="id,name"/> FROM users
+//	</select>
+//
+// A field mapped to another column is aliased by its name, so that the rows are bound as the field,
+// like "createdAt,id":
+//
+//	created_at AS createdAt, id
+//
+// Without fields nor default all the allowed fields are selected.
+type ColumnsNode struct {
+	Param   string
+	Names   []string
+	Columns map[string]string
+	Default string
+}
+
+// NewColumnsNode returns the ColumnsNode selecting the fields of param.
+// allowed is the comma separated list of the allowed fields, where a field selecting another column
+// is followed by a colon and its column, like "createdAt:created_at".
+// It returns ErrInvalidFields if the default fields are not allowed.
+func NewColumnsNode(param, allowed, defaultFields string) (*ColumnsNode, error) {
+	names, columns, err := parseAllowList(allowed)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: no allowed field", ErrInvalidFields)
+	}
+	for _, name := range names {
+		// the name of a field mapped to another column is its alias.
+		if columns[name] != name && (!identifierRegexp.MatchString(name) || strings.Contains(name, ".")) {
+			return nil, fmt.Errorf("invalid alias %q of %s", name, columns[name])
+		}
+	}
+	projection := &ColumnsNode{Param: param, Names: names, Columns: columns, Default: defaultFields}
+	if _, err = projection.selectList(defaultFields); err != nil {
+		return nil, fmt.Errorf("default: %w", err)
+	}
+	return projection, nil
+}
+
+// Accept implements Node interface.
+func (c *ColumnsNode) Accept(_ driver.Translator, p eval.Parameter) (query string, args []any, err error) {
+	expression := listParameter(p, c.Param)
+	if strings.TrimSpace(expression) == "" {
+		expression = c.Default
+	}
+	if strings.TrimSpace(expression) == "" {
+		expression = strings.Join(c.Names, ",")
+	}
+	query, err = c.selectList(expression)
+	return query, nil, err
+}
+
+// selectList returns the select list of the comma separated fields, each selected once.
+func (c *ColumnsNode) selectList(expression string) (string, error) {
+	var builder strings.Builder
+	selected := make(map[string]struct{})
+	for field := range strings.SplitSeq(expression, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		column, ok := c.Columns[field]
+		if !ok {
+			return "", fmt.Errorf("%w: field %q is not allowed", ErrInvalidFields, field)
+		}
+		if _, exists := selected[field]; exists {
+			continue
+		}
+		selected[field] = struct{}{}
+		if builder.Len() > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(column)
+		if column != field {
+			builder.WriteString(" AS ")
+			builder.WriteString(field)
+		}
+	}
+	return builder.String(), nil
+}
+
+var _ Node = (*ColumnsNode)(nil)
@@ -0,0 +1,76 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-juicedev/juice/eval"
+)
+
+func TestColumnsNode_Accept_columns_test(t *testing.T) {
+	columns, err := NewColumnsNode("fields", "id, name, u.email, createdAt:created_at", "id,name")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		param   eval.H
+		want    string
+		wantErr error
+	}{
+		{name: "default", param: eval.H{}, want: "id, name"},
+		{name: "empty", param: eval.H{"fields": ""}, want: "id, name"},
+		{name: "string", param: eval.H{"fields": "createdAt, u.email"}, want: "created_at AS createdAt, u.email"},
+		{name: "slice", param: eval.H{"fields": []string{"name", "id", "name"}}, want: "name, id"},
+		{name: "not allowed", param: eval.H{"fields": "password"}, wantErr: ErrInvalidFields},
+		{name: "column", param: eval.H{"fields": "created_at"}, wantErr: ErrInvalidFields},
+		{name: "injection", param: eval.H{"fields": "id FROM users; --"}, wantErr: ErrInvalidFields},
+	}
+	for _, tt := range tests {
+		query, args, err := columns.Accept(nil, eval.NewGenericParam(tt.param, ""))
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil || query != tt.want || len(args) != 0 {
+			t.Errorf("%s: got %q %v %v, want %q", tt.name, query, args, err, tt.want)
+		}
+	}
+
+	withoutDefault, err := NewColumnsNode("fields", "id, createdAt:created_at", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query, _, err := withoutDefault.Accept(nil, eval.NewGenericParam(eval.H{}, "")); err != nil || query != "id, created_at AS createdAt" {
+		t.Fatalf("expected all the allowed fields, got %q %v", query, err)
+	}
+}
+
+func TestNewColumnsNode_columns_test(t *testing.T) {
+	for _, allowed := range []string{"", "id, id", "total:count(*)", "u.total:total", "total amount:total"} {
+		if _, err := NewColumnsNode("fields", allowed, ""); err == nil {
+			t.Errorf("expected error for allowed %q", allowed)
+		}
+	}
+	if _, err := NewColumnsNode("fields", "id", "name"); !errors.Is(err, ErrInvalidFields) {
+		t.Fatalf("expected ErrInvalidFields for the default, got %v", err)
+	}
+}
@@ -73,11 +73,16 @@ func (c *ConditionNode) Accept(translator driver.Translator, p eval.Parameter) (
 //   - Floats: returns true if non-zero
 //   - String: returns true if non-empty
 func (c *ConditionNode) Match(p eval.Parameter) (bool, error) {
-	if c.expr == nil {
+	return matchExpression(c.expr, p)
+}
+
+// matchExpression evaluates expr with p and reports whether its result is not the zero value.
+func matchExpression(expr eval.Expression, p eval.Parameter) (bool, error) {
+	if expr == nil {
 		return false, ErrNilExpression
 	}
 
-	value, err := c.expr.Execute(p)
+	value, err := expr.Execute(p)
 	if err != nil {
 		return false, err
 	}
@@ -37,6 +37,7 @@ import (
 //   - Open: String to prepend before the iteration results
 //   - Close: String to append after the iteration results
 //   - Separator: String to insert between iterations
+//   - test: Optional expression filtering the items, see Parse
 //
 // Example XML:
 //
@@ -60,6 +61,11 @@ import (
 //     ID = #{ID}
 //     </foreach>
 //
+//  4. Filtered items:
+//     <foreach collection="users" item="user" separator="," test="user.Age > 18">
+//     #{user.ID}
+//     </foreach>
+//
 // Example results:
 //
 //	Input collection: [1, 2, 3]
@@ -74,6 +80,24 @@ type ForeachNode struct {
 	Close      string
 	Separator  string
 	BindNodes  BindNodeGroup
+	test       eval.Expression
+}
+
+// Parse compiles the test expression filtering the items, evaluated with the item and the index
+// of each iteration: the items failing it are skipped, together with their separator,
+// and nothing is rendered when every item is skipped.
+func (f *ForeachNode) Parse(test string) (err error) {
+	f.test, err = eval.Compile(test)
+	return err
+}
+
+// skip reports whether the item of the iteration p fails the test expression.
+func (f ForeachNode) skip(p eval.Parameter) (bool, error) {
+	if f.test == nil {
+		return false, nil
+	}
+	matched, err := matchExpression(f.test, p)
+	return !matched, err
 }
 
 // Accept accepts parameters and returns query and arguments.
@@ -123,8 +147,6 @@ func (f ForeachNode) acceptSlice(value reflect.Value, translator driver.Translat
 
 	builder.WriteString(f.Open)
 
-	end := sliceLength - 1
-
 	// Create and reuse foreachParameter outside the loop to avoid allocations per iteration
 	fp := eval.NewForeachParameter(p, f.Item, f.Index)
 
@@ -132,6 +154,8 @@ func (f ForeachNode) acceptSlice(value reflect.Value, translator driver.Translat
 	// to avoid the early reallocations as the slice grows.
 	args := make([]any, 0, sliceLength)
 
+	var rendered int
+
 	for i := range sliceLength {
 
 		fp.ItemValue = value.Index(i)
@@ -139,6 +163,18 @@ func (f ForeachNode) acceptSlice(value reflect.Value, translator driver.Translat
 			fp.IndexValue = reflectlite.FromInt(i)
 		}
 
+		if skip, err := f.skip(fp); err != nil {
+			return "", nil, err
+		} else if skip {
+			fp.Clear()
+			continue
+		}
+
+		if rendered > 0 {
+			builder.WriteString(f.Separator)
+		}
+		rendered++
+
 		for _, node := range f.Nodes {
 			q, a, err := node.Accept(translator, fp)
 			if err != nil {
@@ -152,12 +188,13 @@ func (f ForeachNode) acceptSlice(value reflect.Value, translator driver.Translat
 			}
 		}
 
-		if i < end {
-			builder.WriteString(f.Separator)
-		}
 		fp.Clear()
 	}
 
+	if rendered == 0 {
+		return "", nil, nil
+	}
+
 	builder.WriteString(f.Close)
 
 	return builder.String(), args, nil
@@ -175,9 +212,7 @@ func (f ForeachNode) acceptMap(value reflect.Value, translator driver.Translator
 
 	builder.WriteString(f.Open)
 
-	end := mapLength - 1
-
-	var index int
+	var rendered int
 
 	// Create and reuse foreachParameter outside the loop to avoid allocations per iteration
 	fp := eval.NewForeachParameter(p, f.Item, f.Index)
@@ -193,6 +228,18 @@ func (f ForeachNode) acceptMap(value reflect.Value, translator driver.Translator
 		fp.ItemValue = iter.Value()
 		fp.IndexValue = iter.Key()
 
+		if skip, err := f.skip(fp); err != nil {
+			return "", nil, err
+		} else if skip {
+			fp.Clear()
+			continue
+		}
+
+		if rendered > 0 {
+			builder.WriteString(f.Separator)
+		}
+		rendered++
+
 		for _, node := range f.Nodes {
 			q, a, err := node.Accept(translator, fp)
 			if err != nil {
@@ -206,13 +253,11 @@ func (f ForeachNode) acceptMap(value reflect.Value, translator driver.Translator
 			}
 		}
 
-		if index < end {
-			builder.WriteString(f.Separator)
-		}
-
 		fp.Clear()
+	}
 
-		index++
+	if rendered == 0 {
+		return "", nil, nil
 	}
 
 	builder.WriteString(f.Close)
@@ -403,3 +403,54 @@ func BenchmarkForeachNode_AcceptMap(b *testing.B) {
 		_, _, _ = node.Accept(drv.Translator(), params)
 	}
 }
+
+func TestForeachNode_Test_foreach_test(t *testing.T) {
+	type user struct {
+		ID  int
+		Age int
+	}
+	translator := driver.MySQLDriver{}.Translator()
+	node := &ForeachNode{
+		Nodes:      []Node{NewTextNode("#{user.ID}")},
+		Item:       "user",
+		Index:      "i",
+		Collection: "users",
+		Open:       "(",
+		Close:      ")",
+		Separator:  ", ",
+	}
+	if err := node.Parse("user.Age > 18"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		users     any
+		wantQuery string
+		wantArgs  []any
+	}{
+		{name: "slice", users: []user{{1, 12}, {2, 30}, {3, 15}, {4, 40}}, wantQuery: "(?, ?)", wantArgs: []any{2, 4}},
+		{name: "first and last skipped", users: []user{{1, 12}, {2, 30}, {3, 15}}, wantQuery: "(?)", wantArgs: []any{2}},
+		{name: "map", users: map[string]user{"a": {1, 12}, "b": {2, 30}}, wantQuery: "(?)", wantArgs: []any{2}},
+		{name: "all skipped", users: []user{{1, 12}, {3, 15}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := node.Accept(translator, eval.H{"users": tt.users})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if query != tt.wantQuery || fmt.Sprint(args) != fmt.Sprint(tt.wantArgs) {
+				t.Fatalf("got %q %v, want %q %v", query, args, tt.wantQuery, tt.wantArgs)
+			}
+		})
+	}
+
+	indexed := &ForeachNode{Nodes: []Node{NewTextNode("#{id}")}, Item: "id", Index: "i", Collection: "ids", Separator: ","}
+	if err := indexed.Parse("i % 2 == 0"); err != nil {
+		t.Fatal(err)
+	}
+	if query, args, err := indexed.Accept(translator, eval.H{"ids": []int{5, 6, 7}}); err != nil || query != "?,?" || fmt.Sprint(args) != "[5 7]" {
+		t.Fatalf("expected the index in the test, got %q %v %v", query, args, err)
+	}
+}
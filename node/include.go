@@ -17,10 +17,20 @@ limitations under the License.
 package node
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
 	"github.com/go-juicedev/juice/driver"
 	"github.com/go-juicedev/juice/eval"
 )
 
+// ErrIncludeCycle is returned when a SQL fragment includes itself,
+// directly or through other fragments.
+var ErrIncludeCycle = errors.New("juice: include cycle")
+
 type nodeManager interface {
 	GetSQLNodeByID(id string) (Node, error)
 }
@@ -62,32 +72,134 @@ type nodeManager interface {
 //  4. Standard filtering conditions
 //
 // Note: The refId must reference an existing SQL fragment defined with
-// the <sql> tag. The reference can be within the same mapper or from
-// another mapper if properly configured.
+// the <sql> tag. The reference can be within the same mapper, or of another
+// mapper when qualified with its namespace, like refid="common.Pagination".
 type IncludeNode struct {
 	sqlNode    Node
 	manager    nodeManager
 	refId      string
 	properties eval.Parameter
+
+	// once guards the lazy loading of resolved.
+	once     sync.Once
+	resolved Node
+	err      error
 }
 
 // Accept accepts parameters and returns query and arguments.
 func (i *IncludeNode) Accept(translator driver.Translator, p eval.Parameter) (query string, args []any, err error) {
-	if i.sqlNode == nil {
-		// lazy loading
-		// does it need to be thread safe?
-		sqlNode, err := i.manager.GetSQLNodeByID(i.refId)
-		if err != nil {
-			return "", nil, err
-		}
-		i.sqlNode = sqlNode
+	sqlNode, err := i.resolve()
+	if err != nil {
+		return "", nil, err
 	}
 
 	if i.properties != nil {
 		p = eval.ParamGroup{i.properties, p}
 	}
 
-	return i.sqlNode.Accept(translator, p)
+	return sqlNode.Accept(translator, p)
+}
+
+// resolve returns the referenced fragment, loading it on first use.
+// A loaded fragment is checked for include cycles before it is rendered,
+// so that a fragment including itself fails instead of recursing forever.
+func (i *IncludeNode) resolve() (Node, error) {
+	if i.sqlNode != nil {
+		return i.sqlNode, nil
+	}
+	i.once.Do(func() {
+		sqlNode, err := i.manager.GetSQLNodeByID(i.refId)
+		if err == nil {
+			err = checkIncludeCycle(sqlNode, []string{i.name()})
+		}
+		i.resolved, i.err = sqlNode, err
+	})
+	return i.resolved, i.err
+}
+
+// name returns the refId qualified with the namespace of its manager when it has one.
+func (i *IncludeNode) name() string {
+	if namespaced, ok := i.manager.(interface{ Namespace() string }); ok && !strings.Contains(i.refId, ".") {
+		return namespaced.Namespace() + "." + i.refId
+	}
+	return i.refId
+}
+
+// checkIncludeCycle walks n and the fragments it includes, path being the
+// names of the includes leading to n. The nested includes are looked up
+// without being loaded, since they load and check themselves on first use.
+func checkIncludeCycle(n Node, path []string) error {
+	if include, ok := n.(*IncludeNode); ok {
+		name := include.name()
+		if index := slices.Index(path, name); index >= 0 {
+			cycle := append(slices.Clone(path[index:]), name)
+			return fmt.Errorf("%w: %s", ErrIncludeCycle, strings.Join(cycle, " -> "))
+		}
+		sqlNode := include.sqlNode
+		if sqlNode == nil {
+			var err error
+			if sqlNode, err = include.manager.GetSQLNodeByID(include.refId); err != nil {
+				// reported by the include itself when it is rendered.
+				return nil
+			}
+		}
+		return checkIncludeCycle(sqlNode, append(path, name))
+	}
+	for _, child := range childNodes(n) {
+		if err := checkIncludeCycle(child, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// childNodes returns the nodes nested in n.
+func childNodes(n Node) []Node {
+	switch n := n.(type) {
+	case Group:
+		return n
+	case *SQLNode:
+		return n.Nodes
+	case SQLNode:
+		return n.Nodes
+	case *ConditionNode:
+		return n.Nodes
+	case *ForeachNode:
+		return n.Nodes
+	case *TrimNode:
+		return n.Nodes
+	case *WhereNode:
+		return n.Nodes
+	case *SetNode:
+		return n.Nodes
+	case *OtherwiseNode:
+		return n.Nodes
+	case *ChooseNode:
+		children := make([]Node, 0, len(n.WhenNodes)+1)
+		for _, when := range n.WhenNodes {
+			children = append(children, when)
+		}
+		if n.OtherwiseNode != nil {
+			children = append(children, n.OtherwiseNode)
+		}
+		return children
+	case *UpsertNode:
+		return n.Nodes
+	case *WithNode:
+		children := make([]Node, 0, len(n.CTEs))
+		for _, cte := range n.CTEs {
+			children = append(children, cte)
+		}
+		return children
+	case *CTENode:
+		return n.Nodes
+	case *SoftDeleteNode:
+		return []Node{n.Where}
+	case *WhereFilterNode:
+		return []Node{n.Where, n.Filter}
+	default:
+		return nil
+	}
 }
 
 func (i *IncludeNode) WithProperties(properties eval.Parameter) *IncludeNode {
@@ -129,6 +129,33 @@ func TestIncludeNode_Accept_include_test(t *testing.T) {
 		}
 	})
 
+	t.Run("Cycle", func(t *testing.T) {
+		manager := &mockNodeManager{nodes: make(map[string]Node)}
+		manager.nodes["a"] = &SQLNode{ID: "a", Nodes: Group{
+			NewTextNode("SELECT * FROM table"),
+			&WhereNode{Nodes: Group{NewIncludeNode(nil, manager, "b")}},
+		}}
+		manager.nodes["b"] = &SQLNode{ID: "b", Nodes: Group{NewIncludeNode(nil, manager, "a")}}
+		node := NewIncludeNode(nil, manager, "a")
+
+		_, _, err := node.Accept(translator, params)
+		if !errors.Is(err, ErrIncludeCycle) {
+			t.Fatalf("err = %v, want %v", err, ErrIncludeCycle)
+		}
+		if want := "juice: include cycle: a -> b -> a"; err.Error() != want {
+			t.Errorf("err = %q, want %q", err, want)
+		}
+
+		// the failed resolution is kept.
+		calls := manager.calls
+		if _, _, err = node.Accept(translator, params); !errors.Is(err, ErrIncludeCycle) {
+			t.Fatalf("err = %v, want %v", err, ErrIncludeCycle)
+		}
+		if manager.calls != calls {
+			t.Errorf("manager calls = %d, want %d", manager.calls, calls)
+		}
+	})
+
 	t.Run("PropertiesOverrideParentParameter", func(t *testing.T) {
 		innerNode := NewTextNode("SELECT ${columns} FROM ${table} WHERE ID = #{ID}")
 		manager := &mockNodeManager{}
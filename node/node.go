@@ -35,7 +35,8 @@ var (
 	//   - #{  age  }    -> matches (whitespace is ignored)
 	//   - #{}           -> doesn't match (requires identifier)
 	//   - #{123}        -> matches
-	paramRegex = regexp.MustCompile(`#{\s*(\w+(?:\.\w+)*)\s*}`)
+	//   - #{name:like}  -> matches, binds %name% with the wildcards escaped
+	paramRegex = regexp.MustCompile(`#{\s*(\w+(?:\.\w+)*)\s*(?::\s*(\w+)\s*)?}`)
 
 	// formatRegexp matches string interpolation placeholders using ${...} syntax.
 	// Unlike paramRegex, these are replaced directly in the SQL string.
@@ -46,7 +47,9 @@ var (
 	//   - ${  field  }  -> matches (whitespace is ignored)
 	//   - ${}           -> doesn't match (requires identifier)
 	//   - ${123}        -> matches
-	formatRegexp = regexp.MustCompile(`\${\s*(\w+(?:\.\w+)*)\s*}`)
+	//   - ${table:ident}        -> matches, the value must be an identifier and is quoted
+	//   - ${order:in(id,name)}  -> matches, the value must be one of the allow-list
+	formatRegexp = regexp.MustCompile(`\${\s*(\w+(?:\.\w+)*)\s*(?::([^}]*))?}`)
 )
 
 // Node is the fundamental interface for all SQL generation components.
@@ -148,6 +151,26 @@ func (g Group) Accept(translator driver.Translator, p eval.Parameter) (query str
 
 var _ Node = (Group)(nil)
 
+// IsStatic reports whether n renders the same query without args for every
+// translator and parameter, so that it can be rendered once and reused.
+// Only plain text and groups of plain text are static; any dynamic node,
+// placeholder or substitution makes the node dynamic.
+func IsStatic(n Node) bool {
+	switch n := n.(type) {
+	case pureTextNode:
+		return true
+	case Group:
+		for _, child := range n {
+			if !IsStatic(child) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
 // reflectValueToString converts reflect.Value to string
 func reflectValueToString(v reflect.Value) string {
 	v = reflectlite.Unwrap(v)
@@ -268,3 +268,22 @@ func (m *mockMapper) GetSQLNodeByID(id string) (*SQLNode, error) {
 var _ interface {
 	GetSQLNodeByID(id string) (*SQLNode, error)
 } = (*mockMapper)(nil)
+
+func TestIsStatic_node_test(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		node Node
+		want bool
+	}{
+		{"PureText", NewTextNode("SELECT * FROM users"), true},
+		{"Group", Group{NewTextNode("SELECT *"), Group{NewTextNode("FROM users")}}, true},
+		{"EmptyGroup", Group{}, true},
+		{"Placeholder", NewTextNode("SELECT * FROM users WHERE id = #{id}"), false},
+		{"Substitution", NewTextNode("SELECT * FROM ${table}"), false},
+		{"DynamicChild", Group{NewTextNode("SELECT * FROM users"), &WhereNode{}}, false},
+	} {
+		if got := IsStatic(tc.node); got != tc.want {
+			t.Errorf("%s: expected IsStatic %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import "github.com/go-juicedev/juice/driver"
+
+// BuildOptions controls the behaviors of nodes which are not decided by the dialect.
+// They are carried by the translator, see WithBuildOptions.
+type BuildOptions struct {
+	// ForbidRawSubstitution makes TextNode reject plain ${} substitutions.
+	// The ${name:ident} and ${name:in(...)} substitutions are still allowed.
+	ForbidRawSubstitution bool
+
+	// ExpandSlices makes TextNode expand a slice in #{} into a parenthesized list of placeholders,
+	// e.g. "id IN #{ids}" into "id IN (?, ?, ?)". Otherwise, the slice is bound as a single argument,
+	// e.g. for "id = ANY(#{ids})" with the array support of the driver.
+	ExpandSlices bool
+
+	// EmptySliceAsNull makes TextNode expand an empty slice in #{} into (NULL)
+	// instead of returning ErrEmptySliceParameter. It only applies with ExpandSlices.
+	EmptySliceAsNull bool
+
+	// StrictChoose makes a ChooseNode without otherwise return ErrNoChooseBranch
+	// when none of its conditions match, instead of rendering nothing.
+	StrictChoose bool
+}
+
+// buildOptionsProvider is implemented by translators carrying BuildOptions.
+type buildOptionsProvider interface {
+	buildOptions() BuildOptions
+}
+
+// buildOptionsOf returns the BuildOptions carried by translator.
+func buildOptionsOf(translator driver.Translator) BuildOptions {
+	if provider, ok := translator.(buildOptionsProvider); ok {
+		return provider.buildOptions()
+	}
+	return BuildOptions{}
+}
+
+// optionsTranslator carries BuildOptions and keeps the dialect of its translator.
+type optionsTranslator struct {
+	driver.Translator
+	options BuildOptions
+}
+
+func (o optionsTranslator) buildOptions() BuildOptions { return o.options }
+
+// QuoteIdentifier implements the driver.IdentifierQuoter interface.
+func (o optionsTranslator) QuoteIdentifier(name string) string {
+	return driver.QuoteIdentifier(o.Translator, name)
+}
+
+// LikeEscapeClause implements the driver.LikeEscaper interface.
+func (o optionsTranslator) LikeEscapeClause() string {
+	return driver.LikeEscapeClause(o.Translator)
+}
+
+// MultiRowValues implements the driver.MultiRowValuer interface.
+func (o optionsTranslator) MultiRowValues() bool {
+	return driver.MultiRowValuesOf(o.Translator)
+}
+
+// UpsertStyle implements the driver.Upserter interface.
+func (o optionsTranslator) UpsertStyle() driver.UpsertStyle {
+	return driver.UpsertStyleOf(o.Translator)
+}
+
+// WithBuildOptions returns a translator which makes nodes build SQL with options.
+// The options replace the ones already carried by translator.
+func WithBuildOptions(translator driver.Translator, options BuildOptions) driver.Translator {
+	if wrapped, ok := translator.(optionsTranslator); ok {
+		translator = wrapped.Translator
+	}
+	return optionsTranslator{Translator: translator, options: options}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-juicedev/juice/eval"
+)
+
+func TestOrderByNode_Accept_order_by_test(t *testing.T) {
+	orderBy, err := NewOrderByNode("sort", "name, createdAt:created_at, u.id", "-createdAt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		param   eval.H
+		want    string
+		wantErr error
+	}{
+		{name: "default", param: eval.H{}, want: "ORDER BY created_at DESC"},
+		{name: "empty", param: eval.H{"sort": " "}, want: "ORDER BY created_at DESC"},
+		{name: "prefixes", param: eval.H{"sort": "+name,-createdAt"}, want: "ORDER BY name, created_at DESC"},
+		{name: "directions", param: eval.H{"sort": "createdAt desc, u.id ASC"}, want: "ORDER BY created_at DESC, u.id"},
+		{name: "slice", param: eval.H{"sort": []string{"-name", "createdAt"}}, want: "ORDER BY name DESC, created_at"},
+		{name: "not allowed", param: eval.H{"sort": "created_at"}, wantErr: ErrInvalidSort},
+		{name: "injection", param: eval.H{"sort": "name; DROP TABLE users"}, wantErr: ErrInvalidSort},
+		{name: "unknown direction", param: eval.H{"sort": "name sideways"}, wantErr: ErrInvalidSort},
+	}
+	for _, tt := range tests {
+		query, args, err := orderBy.Accept(nil, eval.NewGenericParam(tt.param, ""))
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil || query != tt.want || len(args) != 0 {
+			t.Errorf("%s: got %q %v %v, want %q", tt.name, query, args, err, tt.want)
+		}
+	}
+
+	withoutDefault, err := NewOrderByNode("sort", "name", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query, _, err := withoutDefault.Accept(nil, eval.NewGenericParam(eval.H{}, "")); err != nil || query != "" {
+		t.Fatalf("expected no clause without sort, got %q %v", query, err)
+	}
+}
+
+func TestNewOrderByNode_order_by_test(t *testing.T) {
+	for _, allowed := range []string{"", "name, name", "name:lower(name)"} {
+		if _, err := NewOrderByNode("sort", allowed, ""); err == nil {
+			t.Errorf("expected error for allowed %q", allowed)
+		}
+	}
+	if _, err := NewOrderByNode("sort", "name", "-id"); !errors.Is(err, ErrInvalidSort) {
+		t.Fatalf("expected ErrInvalidSort for the default, got %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	sqldriver "database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	"github.com/go-juicedev/juice/internal/reflectlite"
+	"github.com/go-juicedev/juice/sql"
+)
+
+var (
+	// ErrInvalidPlaceholder is returned when a #{} placeholder has an unknown modifier.
+	ErrInvalidPlaceholder = errors.New("juice: invalid placeholder")
+
+	// ErrEmptySliceParameter is returned when an empty slice is expanded in a #{} placeholder,
+	// since "IN ()" is not valid SQL. Enable BuildOptions.EmptySliceAsNull to expand it into (NULL).
+	ErrEmptySliceParameter = errors.New("juice: empty slice parameter")
+
+	// ErrInvalidParameterValue is returned when the driver.Valuer of a #{} parameter fails.
+	ErrInvalidParameterValue = errors.New("juice: invalid parameter value")
+)
+
+// placeholderMode tells how the value of a #{} placeholder is bound.
+type placeholderMode uint8
+
+const (
+	// plainPlaceholder binds the value as it is, e.g. #{name}.
+	plainPlaceholder placeholderMode = iota
+	// likePlaceholder binds %value% with the wildcards of value escaped, e.g. #{name:like}.
+	likePlaceholder
+	// likePrefixPlaceholder binds value% with the wildcards of value escaped, e.g. #{name:likePrefix}.
+	likePrefixPlaceholder
+	// likeSuffixPlaceholder binds %value with the wildcards of value escaped, e.g. #{name:likeSuffix}.
+	likeSuffixPlaceholder
+)
+
+// placeholder is the parsed modifier of a #{} placeholder.
+type placeholder struct {
+	mode placeholderMode
+	// err is the error of parsing the modifier, reported when the node is accepted.
+	err error
+}
+
+// isLike reports whether the placeholder binds a LIKE pattern.
+func (p placeholder) isLike() bool {
+	return p.mode != plainPlaceholder
+}
+
+// bind returns the placeholder text and appends the bound arguments of value, the value of name in param, to args.
+// LIKE placeholders are followed by the ESCAPE clause of the dialect.
+// Slices are expanded into a parenthesized list of placeholders if BuildOptions.ExpandSlices is enabled, see expandSlice.
+// The fields tagged with the encrypted option of the column tag are encrypted, see sql.EncryptedField.
+func (p placeholder) bind(translator driver.Translator, param eval.Parameter, name string, value reflect.Value, args []any) (string, []any, error) {
+	if p.err != nil {
+		return "", nil, p.err
+	}
+	if !p.isLike() {
+		if field, ok := eval.StructField(param, name); ok {
+			value = sql.EncryptedField(field, value)
+		}
+		if buildOptionsOf(translator).ExpandSlices && isExpandableSlice(value) {
+			return expandSlice(translator, name, reflectlite.Unwrap(value), args)
+		}
+		arg, err := normalizeArg(value)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %s", err, name)
+		}
+		return translator.Translate(name), append(args, arg), nil
+	}
+	var arg any
+	// nil values are bound as they are, LIKE NULL matches nothing.
+	if unwrapped := reflectlite.Unwrap(value); !isNilValue(unwrapped) {
+		pattern := driver.EscapeLike(reflectValueToString(unwrapped))
+		switch p.mode {
+		case likePrefixPlaceholder:
+			arg = pattern + "%"
+		case likeSuffixPlaceholder:
+			arg = "%" + pattern
+		default:
+			arg = "%" + pattern + "%"
+		}
+	}
+	return translator.Translate(name) + driver.LikeEscapeClause(translator), append(args, arg), nil
+}
+
+// parsePlaceholder parses the modifier following the colon of a #{} placeholder.
+func parsePlaceholder(modifier string, hasModifier bool) placeholder {
+	if !hasModifier {
+		return placeholder{mode: plainPlaceholder}
+	}
+	switch modifier {
+	case "like":
+		return placeholder{mode: likePlaceholder}
+	case "likePrefix":
+		return placeholder{mode: likePrefixPlaceholder}
+	case "likeSuffix":
+		return placeholder{mode: likeSuffixPlaceholder}
+	default:
+		return placeholder{err: fmt.Errorf("%w: unknown modifier %q", ErrInvalidPlaceholder, modifier)}
+	}
+}
+
+// isNilValue reports whether value is invalid or a nil pointer or interface.
+func isNilValue(value reflect.Value) bool {
+	if !value.IsValid() {
+		return true
+	}
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
+
+// isExpandableSlice reports whether value is a slice or an array to be expanded into placeholders.
+// Byte slices and values implementing driver.Valuer, like the array types of database drivers,
+// are bound as a single argument.
+func isExpandableSlice(value reflect.Value) bool {
+	if !value.IsValid() {
+		return false
+	}
+	if value.CanInterface() {
+		if _, ok := value.Interface().(sqldriver.Valuer); ok {
+			return false
+		}
+	}
+	value = reflectlite.Unwrap(value)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		return value.Type().Elem().Kind() != reflect.Uint8
+	default:
+		return false
+	}
+}
+
+// expandSlice expands the elements of value into (?, ?, ?) with the placeholders of the dialect.
+// Nil and empty slices are expanded into (NULL) if BuildOptions.EmptySliceAsNull is enabled,
+// otherwise ErrEmptySliceParameter is returned.
+func expandSlice(translator driver.Translator, name string, value reflect.Value, args []any) (string, []any, error) {
+	length := value.Len()
+	if length == 0 {
+		if buildOptionsOf(translator).EmptySliceAsNull {
+			return "(NULL)", args, nil
+		}
+		return "", nil, fmt.Errorf("%w: %s", ErrEmptySliceParameter, name)
+	}
+	builder := getStringBuilder()
+	defer putStringBuilder(builder)
+	builder.WriteByte('(')
+	for i := range length {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		arg, err := normalizeArg(value.Index(i))
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %s[%d]", err, name, i)
+		}
+		builder.WriteString(translator.Translate(name))
+		args = append(args, arg)
+	}
+	builder.WriteByte(')')
+	return builder.String(), args, nil
+}
+
+// valuerType is the reflect.Type of driver.Valuer.
+var valuerType = reflect.TypeFor[sqldriver.Valuer]()
+
+// pointerReceiverValuer reports whether the pointer type implements driver.Valuer with a pointer receiver.
+func pointerReceiverValuer(typ reflect.Type) bool {
+	return typ.Implements(valuerType) && !typ.Elem().Implements(valuerType)
+}
+
+// normalizeArg converts value into the argument bound to a placeholder.
+// Nil pointers and interfaces become nil, values implementing driver.Valuer,
+// like sql.NullString, are converted by their Value method, and other pointers are dereferenced.
+// So the arguments seen by middlewares are the ones the database receives.
+func normalizeArg(value reflect.Value) (any, error) {
+	for value.IsValid() {
+		isNil := (value.Kind() == reflect.Interface || value.Kind() == reflect.Pointer) && value.IsNil()
+		// like database/sql, a nil pointer calls Value only if it is declared on the pointer receiver.
+		if isNil && (value.Kind() == reflect.Interface || !pointerReceiverValuer(value.Type())) {
+			return nil, nil
+		}
+		if value.Type().Implements(valuerType) && value.CanInterface() {
+			arg, err := value.Interface().(sqldriver.Valuer).Value()
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidParameterValue, err)
+			}
+			return arg, nil
+		}
+		switch value.Kind() {
+		case reflect.Interface, reflect.Pointer:
+			value = value.Elem()
+		default:
+			if !value.CanInterface() {
+				return nil, fmt.Errorf("%w: unexported field", ErrInvalidParameterValue)
+			}
+			return value.Interface(), nil
+		}
+	}
+	return nil, nil
+}
@@ -58,9 +58,13 @@ import (
 //	Case 2 (only status set):
 //	  UPDATE users SET status = ? WHERE ID = ?
 //
-// Note: The node automatically handles trailing commas and ensures
-// proper formatting of the SET clause regardless of which fields
-// are included dynamically.
+// Note: The node automatically handles the commas and ensures proper
+// formatting of the SET clause regardless of which fields are included
+// dynamically: the rendered clause is split on its top level commas and
+// joined again with ", ", so that doubled, leading or trailing commas are
+// all normalized. The fragments rendered by the child nodes are not split
+// themselves, so an expression like a CASE with conditional WHEN branches
+// may span several of them.
 type SetNode struct {
 	Nodes     Group
 	BindNodes BindNodeGroup
@@ -74,19 +78,57 @@ func (s SetNode) Accept(translator driver.Translator, p eval.Parameter) (query s
 	if err != nil {
 		return "", nil, err
 	}
-	if len(query) == 0 {
+	assignments := splitAssignments(query)
+	if len(assignments) == 0 {
 		return "", args, nil
 	}
 
-	// Remove trailing comma
-	query = strings.TrimSuffix(query, ",")
+	query = strings.Join(assignments, ", ")
 
 	// Ensure SET prefix if not present
-	if !strings.HasPrefix(query, "set ") && !strings.HasPrefix(query, "SET ") {
+	if !hasPrefixFold(query, "SET ") {
 		query = "SET " + query
 	}
 
 	return query, args, nil
 }
 
+// splitAssignments splits query on its commas, except the ones nested in parentheses or quotes,
+// and returns the non-blank assignments, trimmed.
+func splitAssignments(query string) []string {
+	var (
+		assignments []string
+		quote       byte
+		depth       int
+		start       int
+	)
+	appendAssignment := func(end int) {
+		if assignment := strings.TrimSpace(query[start:end]); assignment != "" {
+			assignments = append(assignments, assignment)
+		}
+		start = end + 1
+	}
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case c == ',' && depth == 0:
+			appendAssignment(i)
+		}
+	}
+	appendAssignment(len(query))
+	return assignments
+}
+
 var _ Node = (*SetNode)(nil)
@@ -139,6 +139,59 @@ func TestSetNode_Accept_Comprehensive_set_test(t *testing.T) {
 			expectedQuery: "SET name = ?, modified_at = NOW()",
 			expectedArgs:  []any{"Valid Name"},
 		},
+		{
+			name: "LeadingAndDoubledCommas",
+			nodes: Group{
+				NewTextNode(", ID = #{ID},,"),
+				NewTextNode(",name = #{name} ,"),
+			},
+			params:        eval.NewGenericParam(eval.H{"ID": 1, "name": "Test"}, ""),
+			expectedQuery: "SET ID = ?, name = ?",
+			expectedArgs:  []any{1, "Test"},
+		},
+		{
+			name: "OnlyCommas",
+			nodes: Group{
+				NewTextNode(","),
+				NewTextNode(" , "),
+			},
+			params:        emptyParams,
+			expectedQuery: "",
+		},
+		{
+			name: "NestedAndQuotedCommasKept",
+			nodes: Group{
+				NewTextNode("name = COALESCE(#{name}, name),"),
+				NewTextNode("note = 'a, b'"),
+			},
+			params:        eval.NewGenericParam(eval.H{"name": "Test"}, ""),
+			expectedQuery: "SET name = COALESCE(?, name), note = 'a, b'",
+			expectedArgs:  []any{"Test"},
+		},
+		{
+			name: "ParenthesizedExpressionAcrossNodes",
+			nodes: Group{
+				NewTextNode("status = (CASE"),
+				&IfNode{Nodes: Group{NewTextNode("WHEN id = #{ID} THEN 'on'")}, expr: parseExprNoError(t, "ID > 0")},
+				NewTextNode("ELSE status END),"),
+				NewTextNode("name = #{name}"),
+			},
+			params:        eval.NewGenericParam(eval.H{"ID": 1, "name": "Test"}, ""),
+			expectedQuery: "SET status = (CASE WHEN id = ? THEN 'on' ELSE status END), name = ?",
+			expectedArgs:  []any{1, "Test"},
+		},
+		{
+			name: "CaseExpressionAcrossForeach",
+			nodes: Group{
+				NewTextNode("status = CASE id"),
+				&ForeachNode{Nodes: []Node{NewTextNode("WHEN #{id} THEN 1")}, Item: "id", Collection: "ids", Separator: " "},
+				NewTextNode("END,"),
+				&IfNode{Nodes: Group{NewTextNode("name = #{name},")}, expr: parseExprNoError(t, `name != ""`)},
+			},
+			params:        eval.NewGenericParam(eval.H{"ids": []int{1, 2}, "name": "Test"}, ""),
+			expectedQuery: "SET status = CASE id WHEN ? THEN 1 WHEN ? THEN 1 END, name = ?",
+			expectedArgs:  []any{1, 2, "Test"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,112 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-juicedev/juice/driver"
+)
+
+var (
+	// ErrInvalidSubstitution is returned when a ${} substitution has an unknown modifier
+	// or its value is rejected by the modifier.
+	ErrInvalidSubstitution = errors.New("juice: invalid text substitution")
+
+	// ErrRawSubstitutionForbidden is returned when a plain ${} substitution is used
+	// with BuildOptions.ForbidRawSubstitution enabled.
+	ErrRawSubstitutionForbidden = errors.New("juice: raw text substitution is forbidden")
+)
+
+// identifierRegexp matches bare SQL identifiers, optionally qualified like schema.table.
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*(?:\.[A-Za-z_][A-Za-z0-9_$]*)*$`)
+
+// substitutionMode tells how the value of a ${} substitution is written into the query.
+type substitutionMode uint8
+
+const (
+	// rawSubstitution writes the value as it is, e.g. ${name}.
+	rawSubstitution substitutionMode = iota
+	// identSubstitution validates the value is a bare identifier and quotes it, e.g. ${name:ident}.
+	identSubstitution
+	// allowListSubstitution restricts the value to the allow-list, e.g. ${name:in(id,created_at)}.
+	allowListSubstitution
+)
+
+// substitution is the parsed modifier of a ${} substitution.
+type substitution struct {
+	mode    substitutionMode
+	allowed []string
+	// err is the error of parsing the modifier, reported when the node is accepted.
+	err error
+}
+
+// render converts value into the text written into the query.
+func (s substitution) render(translator driver.Translator, name string, value reflect.Value) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	text := reflectValueToString(value)
+	switch s.mode {
+	case identSubstitution:
+		if !identifierRegexp.MatchString(text) {
+			return "", fmt.Errorf("%w: value of %s is not an identifier: %q", ErrInvalidSubstitution, name, text)
+		}
+		return driver.QuoteIdentifier(translator, text), nil
+	case allowListSubstitution:
+		if !slices.Contains(s.allowed, text) {
+			return "", fmt.Errorf("%w: value of %s is not allowed: %q", ErrInvalidSubstitution, name, text)
+		}
+		return text, nil
+	default:
+		if buildOptionsOf(translator).ForbidRawSubstitution {
+			return "", fmt.Errorf("%w: ${%s}", ErrRawSubstitutionForbidden, name)
+		}
+		return text, nil
+	}
+}
+
+// parseSubstitution parses the modifier following the colon of a ${} substitution.
+// An empty modifier means a plain substitution.
+func parseSubstitution(modifier string, hasModifier bool) substitution {
+	modifier = strings.TrimSpace(modifier)
+	if !hasModifier {
+		return substitution{mode: rawSubstitution}
+	}
+	if modifier == "ident" {
+		return substitution{mode: identSubstitution}
+	}
+	if list, ok := strings.CutPrefix(modifier, "in("); ok {
+		if list, ok = strings.CutSuffix(list, ")"); ok {
+			var allowed []string
+			for item := range strings.SplitSeq(list, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					allowed = append(allowed, item)
+				}
+			}
+			if len(allowed) > 0 {
+				return substitution{mode: allowListSubstitution, allowed: allowed}
+			}
+		}
+	}
+	return substitution{err: fmt.Errorf("%w: unknown modifier %q", ErrInvalidSubstitution, modifier)}
+}
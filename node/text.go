@@ -44,6 +44,10 @@ type textToken struct {
 	name     string
 	isFormat bool // true for ${...}, false for #{...}
 	index    int
+	// substitution is the modifier of ${...}.
+	substitution substitution
+	// placeholder is the modifier of #{...}.
+	placeholder placeholder
 }
 
 // Accept accepts parameters and returns query and arguments.
@@ -74,10 +78,17 @@ func (c *TextNode) Accept(translator driver.Translator, p eval.Parameter) (query
 		}
 
 		if t.isFormat {
-			builder.WriteString(reflectValueToString(value))
+			text, err := t.substitution.render(translator, t.name, value)
+			if err != nil {
+				return "", nil, err
+			}
+			builder.WriteString(text)
 		} else {
-			builder.WriteString(translator.Translate(t.name))
-			args = append(args, value.Interface())
+			var text string
+			if text, args, err = t.placeholder.bind(translator, p, t.name, value, args); err != nil {
+				return "", nil, err
+			}
+			builder.WriteString(text)
 		}
 		lastIndex = t.index + len(t.match)
 	}
@@ -97,6 +108,23 @@ func NewTextNode(str string) Node {
 		return pureTextNode(str)
 	}
 
+	// the common `WHERE id = #{id}` case skips the token loop and the string builder.
+	if p := placeholder; len(p) == 1 && len(textSubstitution) == 0 {
+		return &singlePlaceholderNode{
+			prefix: str[:p[0][0]],
+			match:  str[p[0][0]:p[0][1]],
+			suffix: str[p[0][1]:],
+			name:   str[p[0][2]:p[0][3]],
+			// p[0][4] is -1 if there is no modifier.
+			placeholder: parsePlaceholder(substringAt(str, p[0][4], p[0][5]), p[0][4] >= 0),
+		}
+	}
+
+	return newTokenTextNode(str, placeholder, textSubstitution)
+}
+
+// newTokenTextNode creates a TextNode from the submatch indexes of its placeholders and substitutions.
+func newTokenTextNode(str string, placeholder, textSubstitution [][]int) *TextNode {
 	var tokens []textToken
 	for _, p := range placeholder {
 		tokens = append(tokens, textToken{
@@ -104,6 +132,8 @@ func NewTextNode(str string) Node {
 			name:     str[p[2]:p[3]],
 			isFormat: false,
 			index:    p[0],
+			// p[4] is -1 if there is no modifier.
+			placeholder: parsePlaceholder(substringAt(str, p[4], p[5]), p[4] >= 0),
 		})
 	}
 	for _, s := range textSubstitution {
@@ -112,6 +142,8 @@ func NewTextNode(str string) Node {
 			name:     str[s[2]:s[3]],
 			isFormat: true,
 			index:    s[0],
+			// s[4] is -1 if there is no modifier.
+			substitution: parseSubstitution(substringAt(str, s[4], s[5]), s[4] >= 0),
 		})
 	}
 
@@ -124,3 +156,51 @@ func NewTextNode(str string) Node {
 }
 
 var _ Node = (*TextNode)(nil)
+
+// singlePlaceholderNode is a TextNode with exactly one #{} placeholder and no substitution.
+// It renders the query with a single concatenation and an args slice sized for one argument.
+type singlePlaceholderNode struct {
+	prefix      string
+	match       string
+	suffix      string
+	name        string
+	placeholder placeholder
+}
+
+// Accept implements Node interface.
+func (s *singlePlaceholderNode) Accept(translator driver.Translator, p eval.Parameter) (query string, args []any, err error) {
+	value, exists := p.Get(s.name)
+	if !exists {
+		return "", nil, fmt.Errorf("parameter %s not found", s.name)
+	}
+	text, args, err := s.placeholder.bind(translator, p, s.name, value, make([]any, 0, 1))
+	if err != nil {
+		return "", nil, err
+	}
+	return s.prefix + text + s.suffix, args, nil
+}
+
+var _ Node = (*singlePlaceholderNode)(nil)
+
+// Text returns the source of n if it is a text node, with its #{} placeholders and ${} substitutions
+// not rendered, so that the statements can be rewritten before they are rendered.
+func Text(n Node) (string, bool) {
+	switch n := n.(type) {
+	case pureTextNode:
+		return string(n), true
+	case *TextNode:
+		return n.value, true
+	case *singlePlaceholderNode:
+		return n.prefix + n.match + n.suffix, true
+	default:
+		return "", false
+	}
+}
+
+// substringAt returns str[start:end], or an empty string if the submatch is absent.
+func substringAt(str string, start, end int) string {
+	if start < 0 {
+		return ""
+	}
+	return str[start:end]
+}
@@ -17,10 +17,16 @@ limitations under the License.
 package node
 
 import (
+	stdsql "database/sql"
+	sqldriver "database/sql/driver"
+	"errors"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/go-juicedev/juice/driver"
 	"github.com/go-juicedev/juice/eval"
+	"github.com/go-juicedev/juice/sql"
 )
 
 func TestPureTextNode_Accept_text_test(t *testing.T) {
@@ -249,3 +255,323 @@ func TestTextNode_Accept_text_test(t *testing.T) {
 		return
 	}
 }
+
+func TestTextNode_Substitution_text_test(t *testing.T) {
+	params := eval.NewGenericParam(eval.H{
+		"table":  "app.user",
+		"evil":   "user; DROP TABLE user",
+		"order":  "created_at",
+		"number": 1,
+	}, "")
+
+	tests := []struct {
+		name       string
+		translator driver.Translator
+		text       string
+		want       string
+		wantErr    error
+	}{
+		{name: "Raw", translator: driver.MySQLDriver{}.Translator(), text: "SELECT * FROM ${table}", want: "SELECT * FROM app.user"},
+		{name: "IdentMySQL", translator: driver.MySQLDriver{}.Translator(), text: "SELECT * FROM ${table:ident}", want: "SELECT * FROM `app`.`user`"},
+		{name: "IdentPostgres", translator: driver.PostgresDriver{}.Translator(), text: "SELECT * FROM ${ table : ident }", want: `SELECT * FROM "app"."user"`},
+		{name: "IdentRejected", translator: driver.MySQLDriver{}.Translator(), text: "SELECT * FROM ${evil:ident}", wantErr: ErrInvalidSubstitution},
+		{name: "IdentNumber", translator: driver.MySQLDriver{}.Translator(), text: "SELECT * FROM ${number:ident}", wantErr: ErrInvalidSubstitution},
+		{name: "AllowList", translator: driver.MySQLDriver{}.Translator(), text: "ORDER BY ${order:in(id, created_at)}", want: "ORDER BY created_at"},
+		{name: "AllowListRejected", translator: driver.MySQLDriver{}.Translator(), text: "ORDER BY ${evil:in(id,created_at)}", wantErr: ErrInvalidSubstitution},
+		{name: "UnknownModifier", translator: driver.MySQLDriver{}.Translator(), text: "ORDER BY ${order:raw}", wantErr: ErrInvalidSubstitution},
+		{name: "EmptyAllowList", translator: driver.MySQLDriver{}.Translator(), text: "ORDER BY ${order:in()}", wantErr: ErrInvalidSubstitution},
+		{name: "Forbidden", translator: WithBuildOptions(driver.MySQLDriver{}.Translator(), BuildOptions{ForbidRawSubstitution: true}), text: "SELECT * FROM ${table}", wantErr: ErrRawSubstitutionForbidden},
+		{name: "ForbiddenIdent", translator: WithBuildOptions(driver.MySQLDriver{}.Translator(), BuildOptions{ForbidRawSubstitution: true}), text: "SELECT * FROM ${table:ident} WHERE id = #{number}", want: "SELECT * FROM `app`.`user` WHERE id = ?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _, err := NewTextNode(tt.text).Accept(tt.translator, params)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if query != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, query)
+			}
+		})
+	}
+}
+
+func TestTextNode_LikePlaceholder_text_test(t *testing.T) {
+	var nilName *string
+	params := eval.NewGenericParam(eval.H{"keyword": "50%_off", "nothing": nilName}, "")
+
+	tests := []struct {
+		name       string
+		translator driver.Translator
+		text       string
+		wantQuery  string
+		wantArg    any
+		wantErr    error
+	}{
+		{name: "Contains", translator: driver.SQLiteDriver{}.Translator(), text: "name LIKE #{keyword:like}", wantQuery: `name LIKE ? ESCAPE '\'`, wantArg: `%50\%\_off%`},
+		{name: "Prefix", translator: driver.PostgresDriver{}.Translator(), text: "name LIKE #{ keyword : likePrefix }", wantQuery: `name LIKE $1 ESCAPE '\'`, wantArg: `50\%\_off%`},
+		{name: "Suffix", translator: driver.MySQLDriver{}.Translator(), text: "name LIKE #{keyword:likeSuffix}", wantQuery: `name LIKE ? ESCAPE '\\'`, wantArg: `%50\%\_off`},
+		{name: "Strict", translator: WithBuildOptions(driver.MySQLDriver{}.Translator(), BuildOptions{ForbidRawSubstitution: true}), text: "name LIKE #{keyword:like}", wantQuery: `name LIKE ? ESCAPE '\\'`, wantArg: `%50\%\_off%`},
+		{name: "Nil", translator: driver.SQLiteDriver{}.Translator(), text: "name LIKE #{nothing:like}", wantQuery: `name LIKE ? ESCAPE '\'`, wantArg: nil},
+		{name: "UnknownModifier", translator: driver.SQLiteDriver{}.Translator(), text: "name = #{keyword:unknown}", wantErr: ErrInvalidPlaceholder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := NewTextNode(tt.text).Accept(tt.translator, params)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if query != tt.wantQuery {
+				t.Fatalf("expected query %q, got %q", tt.wantQuery, query)
+			}
+			if len(args) != 1 || args[0] != tt.wantArg {
+				t.Fatalf("expected arg %v, got %v", tt.wantArg, args)
+			}
+		})
+	}
+}
+
+type textValuerSlice []int
+
+func (t textValuerSlice) Value() (sqldriver.Value, error) { return "{1,2}", nil }
+
+func TestTextNode_SliceExpansion_text_test(t *testing.T) {
+	ids := []int{1, 2, 3}
+	params := eval.NewGenericParam(eval.H{
+		"ids":    ids,
+		"ptr":    &ids,
+		"array":  [2]string{"a", "b"},
+		"empty":  []int{},
+		"bytes":  []byte("raw"),
+		"valuer": textValuerSlice{1, 2},
+	}, "")
+
+	expand := func(translator driver.Translator) driver.Translator {
+		return WithBuildOptions(translator, BuildOptions{ExpandSlices: true})
+	}
+
+	tests := []struct {
+		name       string
+		translator driver.Translator
+		text       string
+		wantQuery  string
+		wantArgs   []any
+		wantErr    error
+	}{
+		{name: "MySQL", translator: expand(driver.MySQLDriver{}.Translator()), text: "id IN #{ids}", wantQuery: "id IN (?, ?, ?)", wantArgs: []any{1, 2, 3}},
+		{name: "Postgres", translator: expand(driver.PostgresDriver{}.Translator()), text: "id IN #{ids} AND name IN #{array}", wantQuery: "id IN ($1, $2, $3) AND name IN ($4, $5)", wantArgs: []any{1, 2, 3, "a", "b"}},
+		{name: "Pointer", translator: expand(driver.MySQLDriver{}.Translator()), text: "id IN #{ptr}", wantQuery: "id IN (?, ?, ?)", wantArgs: []any{1, 2, 3}},
+		{name: "Bytes", translator: expand(driver.MySQLDriver{}.Translator()), text: "data = #{bytes}", wantQuery: "data = ?", wantArgs: []any{[]byte("raw")}},
+		{name: "Valuer", translator: expand(driver.PostgresDriver{}.Translator()), text: "id = ANY(#{valuer})", wantQuery: "id = ANY($1)", wantArgs: []any{"{1,2}"}},
+		{name: "Unexpanded", translator: driver.PostgresDriver{}.Translator(), text: "id = ANY(#{ids})", wantQuery: "id = ANY($1)", wantArgs: []any{ids}},
+		{name: "EmptyError", translator: expand(driver.MySQLDriver{}.Translator()), text: "id IN #{empty}", wantErr: ErrEmptySliceParameter},
+		{name: "EmptyNull", translator: WithBuildOptions(driver.MySQLDriver{}.Translator(), BuildOptions{ExpandSlices: true, EmptySliceAsNull: true}), text: "id IN #{empty} AND id IN #{ids}", wantQuery: "id IN (NULL) AND id IN (?, ?, ?)", wantArgs: []any{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := NewTextNode(tt.text).Accept(tt.translator, params)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if query != tt.wantQuery {
+				t.Fatalf("expected query %q, got %q", tt.wantQuery, query)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Fatalf("expected args %v, got %v", tt.wantArgs, args)
+			}
+		})
+	}
+}
+
+type textPtrValuer struct{ value string }
+
+func (t *textPtrValuer) Value() (sqldriver.Value, error) {
+	if t == nil {
+		return "default", nil
+	}
+	return t.value, nil
+}
+
+type textFailValuer struct{}
+
+func (textFailValuer) Value() (sqldriver.Value, error) { return nil, errors.New("boom") }
+
+func TestTextNode_ArgNormalization_text_test(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	var nilTime *time.Time
+	var nilPtrValuer *textPtrValuer
+	name := "juice"
+	params := eval.NewGenericParam(eval.H{
+		"nilTime":      nilTime,
+		"time":         &now,
+		"nullString":   stdsql.NullString{String: "a", Valid: true},
+		"nullInt":      stdsql.NullInt64{},
+		"ptrValuer":    &textPtrValuer{value: "v"},
+		"nilPtrValuer": nilPtrValuer,
+		"name":         &name,
+		"names":        []*string{&name, nil},
+		"fail":         textFailValuer{},
+	}, "")
+
+	text := "#{nilTime} #{time} #{nullString} #{nullInt} #{ptrValuer} #{nilPtrValuer} #{name} #{names}"
+	wantArgs := []any{nil, now, "a", nil, "v", "default", "juice", "juice", nil}
+
+	drivers := []driver.Driver{driver.MySQLDriver{}, driver.PostgresDriver{}, driver.SQLiteDriver{}, driver.OracleDriver{}}
+	for _, drv := range drivers {
+		t.Run(drv.Name(), func(t *testing.T) {
+			_, args, err := NewTextNode(text).Accept(WithBuildOptions(drv.Translator(), BuildOptions{ExpandSlices: true}), params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(args, wantArgs) {
+				t.Fatalf("expected args %#v, got %#v", wantArgs, args)
+			}
+		})
+	}
+
+	if _, _, err := NewTextNode("#{fail}").Accept(driver.MySQLDriver{}.Translator(), params); !errors.Is(err, ErrInvalidParameterValue) {
+		t.Fatalf("expected ErrInvalidParameterValue, got %v", err)
+	}
+}
+
+// textReverseCodec is a reversible FieldCodec to tell the encrypted arguments apart.
+type textReverseCodec struct{}
+
+func (textReverseCodec) Encrypt(plaintext []byte) ([]byte, error) {
+	ciphertext := make([]byte, len(plaintext))
+	for i, b := range plaintext {
+		ciphertext[len(plaintext)-1-i] = b
+	}
+	return ciphertext, nil
+}
+
+func (c textReverseCodec) Decrypt(ciphertext []byte) ([]byte, error) { return c.Encrypt(ciphertext) }
+
+func TestTextNode_EncryptedField_text_test(t *testing.T) {
+	if err := sql.RegisterFieldCodec("text_test", textReverseCodec{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	type user struct {
+		SSN  string `param:"ssn" column:"ssn,encrypted=text_test"`
+		Name string `param:"name"`
+	}
+	params := eval.NewGenericParam(user{SSN: "123", Name: "juice"}, "")
+
+	// the test expression sees the plain value, the placeholder binds the encrypted one.
+	node := &IfNode{Nodes: []Node{NewTextNode("WHERE ssn = #{ssn} AND name = #{name}")}}
+	if err := node.Parse(`ssn != "" && len(ssn) > 0`); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	query, args, err := node.Accept(driver.MySQLDriver{}.Translator(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query != "WHERE ssn = ? AND name = ?" || !reflect.DeepEqual(args, []any{[]byte("321"), "juice"}) {
+		t.Fatalf("expected the encrypted ssn, got %q %v", query, args)
+	}
+
+	// the items of a foreach are encrypted the same way.
+	foreach := &ForeachNode{Nodes: []Node{NewTextNode("#{item.ssn}")}, Item: "item", Collection: "users", Separator: ", "}
+	_, args, err = foreach.Accept(driver.MySQLDriver{}.Translator(), eval.H{"users": []user{{SSN: "12"}, {SSN: "34"}}})
+	if err != nil || !reflect.DeepEqual(args, []any{[]byte("21"), []byte("43")}) {
+		t.Fatalf("expected the encrypted items, got %v err=%v", args, err)
+	}
+
+	// a map parameter has no field tag and is bound as it is.
+	_, args, err = NewTextNode("#{ssn}").Accept(driver.MySQLDriver{}.Translator(), eval.NewGenericParam(eval.H{"ssn": "123"}, ""))
+	if err != nil || !reflect.DeepEqual(args, []any{"123"}) {
+		t.Fatalf("expected the plain ssn, got %v err=%v", args, err)
+	}
+}
+
+func TestSinglePlaceholderNode_text_test(t *testing.T) {
+	for _, tc := range []struct {
+		text  string
+		param eval.H
+		query string
+		args  []any
+	}{
+		{"WHERE id = #{id}", eval.H{"id": 1}, "WHERE id = $1", []any{1}},
+		{"#{id}", eval.H{"id": 1}, "$1", []any{1}},
+		{"name LIKE #{name:like}", eval.H{"name": "a%"}, `name LIKE $1 ESCAPE '\'`, []any{`%a\%%`}},
+		{"id IN #{ids}", eval.H{"ids": []int{1, 2}}, "id IN ($1, $2)", []any{1, 2}},
+	} {
+		textNode := NewTextNode(tc.text)
+		if _, ok := textNode.(*singlePlaceholderNode); !ok {
+			t.Fatalf("%s: expected singlePlaceholderNode, got %T", tc.text, textNode)
+		}
+		translator := WithBuildOptions(driver.PostgresDriver{}.Translator(), BuildOptions{ExpandSlices: true})
+		query, args, err := textNode.Accept(translator, eval.NewGenericParam(tc.param, ""))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.text, err)
+		}
+		if query != tc.query || !reflect.DeepEqual(args, tc.args) {
+			t.Errorf("%s: expected %q %v, got %q %v", tc.text, tc.query, tc.args, query, args)
+		}
+	}
+
+	translator := driver.MySQLDriver{}.Translator()
+	if _, _, err := NewTextNode("WHERE id = #{id}").Accept(translator, eval.NewGenericParam(eval.H{}, "")); err == nil {
+		t.Error("expected an error for a missing parameter")
+	}
+	if _, _, err := NewTextNode("WHERE id = #{id:unknown}").Accept(translator, eval.NewGenericParam(eval.H{"id": 1}, "")); !errors.Is(err, ErrInvalidPlaceholder) {
+		t.Errorf("expected ErrInvalidPlaceholder, got %v", err)
+	}
+}
+
+func TestText_text_test(t *testing.T) {
+	for _, text := range []string{"SELECT 1", "WHERE id = #{id:like}", "WHERE id = #{id} AND name = ${name}"} {
+		if got, ok := Text(NewTextNode(text)); !ok || got != text {
+			t.Errorf("Text(%q) = %q, %v", text, got, ok)
+		}
+	}
+	if _, ok := Text(&WhereNode{}); ok {
+		t.Error("expected no text for a where node")
+	}
+}
+
+func BenchmarkTextNode_SinglePlaceholder(b *testing.B) {
+	const text = "SELECT * FROM users WHERE id = #{id}"
+	translator := driver.MySQLDriver{}.Translator()
+	param := eval.NewGenericParam(eval.H{"id": 1}, "")
+
+	b.Run("Specialized", func(b *testing.B) {
+		textNode := NewTextNode(text)
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, _, err := textNode.Accept(translator, param); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Tokens", func(b *testing.B) {
+		textNode := newTokenTextNode(text, paramRegex.FindAllStringSubmatchIndex(text, -1), nil)
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, _, err := textNode.Accept(translator, param); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
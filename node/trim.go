@@ -76,10 +76,14 @@ func (t TrimNode) Accept(translator driver.Translator, p eval.Parameter) (query
 		return "", nil, nil
 	}
 
+	// The overrides are matched ignoring case and the surrounding whitespace,
+	// so that "AND |OR " also removes the "and" of "\n  and id = ?".
+	query = strings.TrimSpace(query)
+
 	// Handle prefix overrides before adding prefix
 	if len(t.PrefixOverrides) > 0 {
 		for _, prefix := range t.PrefixOverrides {
-			if strings.HasPrefix(query, prefix) {
+			if hasPrefixFold(query, prefix) {
 				query = query[len(prefix):]
 				break
 			}
@@ -89,13 +93,18 @@ func (t TrimNode) Accept(translator driver.Translator, p eval.Parameter) (query
 	// Handle suffix overrides before adding suffix
 	if len(t.SuffixOverrides) > 0 {
 		for _, suffix := range t.SuffixOverrides {
-			if strings.HasSuffix(query, suffix) {
+			if hasSuffixFold(query, suffix) {
 				query = query[:len(query)-len(suffix)]
 				break
 			}
 		}
 	}
 
+	// Nothing is left to wrap once the overrides are removed.
+	if strings.TrimSpace(query) == "" {
+		return "", args, nil
+	}
+
 	// Build final query with prefix and suffix
 	var builder = getStringBuilder()
 	defer putStringBuilder(builder)
@@ -160,6 +160,23 @@ func TestTrimNode_Accept_Comprehensive_trim_test(t *testing.T) {
 			params:          emptyParams,
 			expectedQuery:   "content,;",
 		},
+		{
+			name:            "OverridesIgnoreCaseAndWhitespace",
+			nodes:           Group{NewTextNode("\n  and ID = 1 or \n")},
+			prefix:          "WHERE ",
+			prefixOverrides: []string{"AND ", "OR "},
+			suffixOverrides: []string{" AND", " OR"},
+			params:          emptyParams,
+			expectedQuery:   "WHERE ID = 1",
+		},
+		{
+			name:            "NothingLeftAfterOverrides",
+			nodes:           Group{NewTextNode("AND ")},
+			prefix:          "WHERE ",
+			prefixOverrides: []string{"AND"},
+			params:          emptyParams,
+			expectedQuery:   "",
+		},
 		{
 			name:          "ContentGeneratedWithArgs",
 			nodes:         Group{NewTextNode("ID = #{ID}")},
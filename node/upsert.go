@@ -0,0 +1,127 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+)
+
+var (
+	// ErrUpsertUnsupported is returned by an UpsertNode rendered for a dialect without upsert clause.
+	ErrUpsertUnsupported = errors.New("juice: upsert is not supported by the dialect")
+
+	// ErrUpsertColumnsRequired is returned by an UpsertNode which needs its conflict columns
+	// to render the clause of the dialect.
+	ErrUpsertColumnsRequired = errors.New("juice: upsert requires the conflict columns")
+)
+
+// UpsertNode renders the clause of an INSERT updating the row it conflicts with,
+// in the syntax of the dialect of the translator, see driver.UpsertStyle.
+//
+// Fields:
+//   - Columns: The columns of the unique key the conflicts are detected on
+//   - Update: The columns updated with the inserted values
+//   - Nodes: Additional assignments, like "updated_at = NOW()"
+//
+// Example XML:
+//
+//	<insert id="save">
+//	  INSERT INTO users (id, name, email) VALUES (#{id}, #{name}, #{email})
+//	  <onConflict columns="id" update="name, email">
+//	    updated_at = NOW()
+//	  </onConflict>
+//	</insert>
+//
+// Example results:
+//
+//	PostgreSQL, SQLite:
+//	  ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, email = EXCLUDED.email, updated_at = NOW()
+//	MySQL:
+//	  ON DUPLICATE KEY UPDATE name = VALUES(name), email = VALUES(email), updated_at = NOW()
+//
+// Without assignments the conflicting row is kept as it is, with DO NOTHING,
+// or with a no-op update of the first conflict column for MySQL.
+// MySQL detects the conflicts on every unique key, so the columns are otherwise ignored.
+type UpsertNode struct {
+	Columns   []string
+	Update    []string
+	Nodes     Group
+	BindNodes BindNodeGroup
+}
+
+// Accept implements Node interface.
+func (u UpsertNode) Accept(translator driver.Translator, p eval.Parameter) (query string, args []any, err error) {
+	style := driver.UpsertStyleOf(translator)
+	if style == driver.UpsertUnsupported {
+		return "", nil, ErrUpsertUnsupported
+	}
+
+	p = u.BindNodes.ConvertParameter(p)
+
+	assignments := make([]string, 0, len(u.Update))
+	for _, column := range u.Update {
+		if style == driver.OnConflict {
+			assignments = append(assignments, column+" = EXCLUDED."+column)
+		} else {
+			assignments = append(assignments, column+" = VALUES("+column+")")
+		}
+	}
+	query, args, err = u.Nodes.Accept(translator, p)
+	if err != nil {
+		return "", nil, err
+	}
+	assignments = append(assignments, splitAssignments(query)...)
+
+	builder := getStringBuilder()
+	defer putStringBuilder(builder)
+
+	switch style {
+	case driver.OnConflict:
+		builder.WriteString("ON CONFLICT")
+		if len(u.Columns) > 0 {
+			builder.WriteString(" (")
+			builder.WriteString(strings.Join(u.Columns, ", "))
+			builder.WriteString(")")
+		}
+		if len(assignments) == 0 {
+			builder.WriteString(" DO NOTHING")
+			break
+		}
+		// the conflict target is required to update the conflicting row.
+		if len(u.Columns) == 0 {
+			return "", nil, ErrUpsertColumnsRequired
+		}
+		builder.WriteString(" DO UPDATE SET ")
+		builder.WriteString(strings.Join(assignments, ", "))
+	default:
+		if len(assignments) == 0 {
+			if len(u.Columns) == 0 {
+				return "", nil, ErrUpsertColumnsRequired
+			}
+			assignments = []string{u.Columns[0] + " = " + u.Columns[0]}
+		}
+		builder.WriteString("ON DUPLICATE KEY UPDATE ")
+		builder.WriteString(strings.Join(assignments, ", "))
+	}
+	return builder.String(), args, nil
+}
+
+var _ Node = (*UpsertNode)(nil)
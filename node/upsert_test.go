@@ -0,0 +1,112 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+)
+
+func TestUpsertNode_Accept_upsert_test(t *testing.T) {
+	params := eval.NewGenericParam(eval.H{"now": "2026-01-01"}, "")
+
+	tests := []struct {
+		name      string
+		node      UpsertNode
+		driver    driver.Driver
+		wantQuery string
+		wantArgs  []any
+		wantErr   error
+	}{
+		{
+			name:      "OnConflictUpdate",
+			node:      UpsertNode{Columns: []string{"id"}, Update: []string{"name", "email"}, Nodes: Group{NewTextNode("updated_at = #{now},")}},
+			driver:    driver.PostgresDriver{},
+			wantQuery: "ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, email = EXCLUDED.email, updated_at = $1",
+			wantArgs:  []any{"2026-01-01"},
+		},
+		{
+			name:      "OnConflictDoNothing",
+			node:      UpsertNode{Columns: []string{"tenant_id", "id"}},
+			driver:    driver.SQLiteDriver{},
+			wantQuery: "ON CONFLICT (tenant_id, id) DO NOTHING",
+		},
+		{
+			name:      "OnConflictDoNothingWithoutColumns",
+			node:      UpsertNode{},
+			driver:    driver.PostgresDriver{},
+			wantQuery: "ON CONFLICT DO NOTHING",
+		},
+		{
+			name:    "OnConflictUpdateWithoutColumns",
+			node:    UpsertNode{Update: []string{"name"}},
+			driver:  driver.PostgresDriver{},
+			wantErr: ErrUpsertColumnsRequired,
+		},
+		{
+			name:      "OnDuplicateKeyUpdate",
+			node:      UpsertNode{Columns: []string{"id"}, Update: []string{"name", "email"}, Nodes: Group{NewTextNode("updated_at = #{now}")}},
+			driver:    driver.MySQLDriver{},
+			wantQuery: "ON DUPLICATE KEY UPDATE name = VALUES(name), email = VALUES(email), updated_at = ?",
+			wantArgs:  []any{"2026-01-01"},
+		},
+		{
+			name:      "OnDuplicateKeyKeepRow",
+			node:      UpsertNode{Columns: []string{"id"}},
+			driver:    driver.MySQLDriver{},
+			wantQuery: "ON DUPLICATE KEY UPDATE id = id",
+		},
+		{
+			name:    "OnDuplicateKeyKeepRowWithoutColumns",
+			node:    UpsertNode{},
+			driver:  driver.MySQLDriver{},
+			wantErr: ErrUpsertColumnsRequired,
+		},
+		{
+			name:    "Unsupported",
+			node:    UpsertNode{Columns: []string{"id"}, Update: []string{"name"}},
+			driver:  driver.OracleDriver{},
+			wantErr: ErrUpsertUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the build options must not hide the dialect.
+			translator := WithBuildOptions(tt.driver.Translator(), BuildOptions{})
+			query, args, err := tt.node.Accept(translator, params)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("query = %q, want %q", query, tt.wantQuery)
+			}
+			if !equalArgs(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+)
+
+// ErrMultiRowValuesUnsupported is returned when a ValuesNode renders several rows
+// for a dialect which inserts a single row per VALUES clause, like Oracle.
+var ErrMultiRowValuesUnsupported = errors.New("juice: multiple rows of VALUES are not supported by the dialect")
+
+// ValuesNode renders the column list and the VALUES rows of an INSERT statement from a collection,
+// one row per item, so that every row binds its fields in the same column order.
+//
+// Fields:
+//   - Collection: The parameter holding the rows, a slice or array of items, or a single item
+//   - Item: The name of the current item, "item" by default
+//   - Columns: The columns of the rows, in order
+//
+// Example XML:
+//
+//	<insert id="BatchInsert" batchSize="500">
+//	  INSERT INTO users <values collection="users" item="user" columns="name, email, createdAt:created_at"/>
+//	</insert>
+//
+// A field followed by a colon and a column binds the field into that column, so that two users render:
+//
+//	(name, email, created_at) VALUES (?, ?, ?), (?, ?, ?)
+//
+// The rows are bound like #{user.name}, so that a batch statement whose parameter holds the collection
+// under a single key is split into batchSize rows per execution. The dialects inserting a single row
+// per VALUES clause, see driver.MultiRowValuesOf, need a batchSize of 1 to insert several items.
+type ValuesNode struct {
+	Collection string
+	Item       string
+	Columns    []string
+	row        Node
+}
+
+// NewValuesNode returns the ValuesNode of the rows of collection.
+// columns is the comma separated list of the fields of the items, where a field bound into another column
+// is followed by a colon and its column, like "createdAt:created_at".
+func NewValuesNode(collection, item, columns string) (*ValuesNode, error) {
+	if item == "" {
+		item = "item"
+	}
+	if !isParameterName(item) {
+		return nil, fmt.Errorf("invalid item %q", item)
+	}
+	names, mapped, err := parseAllowList(columns)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no column")
+	}
+	values := &ValuesNode{Collection: collection, Item: item, Columns: make([]string, 0, len(names))}
+	var row strings.Builder
+	row.WriteString("(")
+	for i, name := range names {
+		if !isParameterName(name) {
+			return nil, fmt.Errorf("invalid field %q", name)
+		}
+		if i > 0 {
+			row.WriteString(", ")
+		}
+		row.WriteString("#{" + item + "." + name + "}")
+		values.Columns = append(values.Columns, mapped[name])
+	}
+	row.WriteString(")")
+	values.row = NewTextNode(row.String())
+	return values, nil
+}
+
+// isParameterName reports whether name is a single name of a parameter, like in #{name}.
+func isParameterName(name string) bool {
+	return identifierRegexp.MatchString(name) && !strings.ContainsAny(name, ".$")
+}
+
+// Accept implements Node interface.
+func (v *ValuesNode) Accept(translator driver.Translator, p eval.Parameter) (query string, args []any, err error) {
+	if _, exists := p.Get(v.Item); exists {
+		return "", nil, fmt.Errorf("item %s already exists", v.Item)
+	}
+	value, exists := p.Get(v.Collection)
+	if !exists {
+		return "", nil, fmt.Errorf("collection %s not found", v.Collection)
+	}
+	items := value
+	for items.Kind() == reflect.Interface || items.Kind() == reflect.Pointer {
+		if items.IsNil() {
+			return "", nil, fmt.Errorf("collection %s is nil", v.Collection)
+		}
+		items = items.Elem()
+	}
+
+	builder := getStringBuilder()
+	defer putStringBuilder(builder)
+
+	builder.WriteString("(")
+	builder.WriteString(strings.Join(v.Columns, ", "))
+	builder.WriteString(") VALUES ")
+
+	fp := eval.NewForeachParameter(p, v.Item, "")
+	row := func(item reflect.Value) error {
+		fp.ItemValue = item
+		defer fp.Clear()
+		q, a, err := v.row.Accept(translator, fp)
+		if err != nil {
+			return err
+		}
+		builder.WriteString(q)
+		args = append(args, a...)
+		return nil
+	}
+
+	switch items.Kind() {
+	case reflect.Slice, reflect.Array:
+		if items.Len() == 0 {
+			return "", nil, fmt.Errorf("collection %s is empty", v.Collection)
+		}
+		if items.Len() > 1 && !driver.MultiRowValuesOf(translator) {
+			return "", nil, fmt.Errorf("%w: collection %s has %d items", ErrMultiRowValuesUnsupported, v.Collection, items.Len())
+		}
+		args = make([]any, 0, items.Len()*len(v.Columns))
+		for i := range items.Len() {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+			if err = row(items.Index(i)); err != nil {
+				return "", nil, err
+			}
+		}
+	default:
+		// a single item renders a single row.
+		if err = row(value); err != nil {
+			return "", nil, err
+		}
+	}
+	return builder.String(), args, nil
+}
+
+// parseAllowList returns the names of the comma separated allow-list, in order, and the columns they map to.
+// A name mapped to another column is followed by a colon and the column, like "createdAt:created_at".
+func parseAllowList(allowed string) (names []string, columns map[string]string, err error) {
+	columns = make(map[string]string)
+	for item := range strings.SplitSeq(allowed, ",") {
+		name, column, mapped := strings.Cut(item, ":")
+		name, column = strings.TrimSpace(name), strings.TrimSpace(column)
+		if !mapped {
+			column = name
+		}
+		if name == "" {
+			continue
+		}
+		if !identifierRegexp.MatchString(column) {
+			return nil, nil, fmt.Errorf("invalid column %q of %s", column, name)
+		}
+		if _, exists := columns[name]; exists {
+			return nil, nil, fmt.Errorf("duplicate name %s", name)
+		}
+		names = append(names, name)
+		columns[name] = column
+	}
+	return names, columns, nil
+}
+
+var _ Node = (*ValuesNode)(nil)
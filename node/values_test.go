@@ -0,0 +1,90 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+)
+
+func TestValuesNode_Accept_values_test(t *testing.T) {
+	type user struct {
+		Name  string `param:"name"`
+		Email string `param:"email"`
+	}
+	values, err := NewValuesNode("users", "user", "name, email:email_address")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	users := []user{{Name: "a", Email: "a@example.com"}, {Name: "b", Email: "b@example.com"}}
+
+	query, args, err := values.Accept(driver.PostgresDriver{}.Translator(), eval.NewGenericParam(eval.H{"users": users}, ""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "(name, email_address) VALUES ($1, $2), ($3, $4)"; query != want {
+		t.Errorf("got %q, want %q", query, want)
+	}
+	if want := []any{"a", "a@example.com", "b", "b@example.com"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("got args %v, want %v", args, want)
+	}
+
+	// a single item renders a single row.
+	query, args, err = values.Accept(driver.MySQLDriver{}.Translator(), eval.NewGenericParam(eval.H{"users": &users[1]}, ""))
+	if err != nil || query != "(name, email_address) VALUES (?, ?)" || !reflect.DeepEqual(args, []any{"b", "b@example.com"}) {
+		t.Errorf("got %q %v %v", query, args, err)
+	}
+
+	// a dialect inserting a single row per VALUES clause only renders a single item.
+	oracle := driver.OracleDriver{}.Translator()
+	if _, _, err = values.Accept(oracle, eval.NewGenericParam(eval.H{"users": users}, "")); !errors.Is(err, ErrMultiRowValuesUnsupported) {
+		t.Errorf("expected ErrMultiRowValuesUnsupported, got %v", err)
+	}
+	query, _, err = values.Accept(WithBuildOptions(oracle, BuildOptions{}), eval.NewGenericParam(eval.H{"users": users[:1]}, ""))
+	if err != nil || query != "(name, email_address) VALUES (:1, :2)" {
+		t.Errorf("got %q %v", query, err)
+	}
+
+	for name, param := range map[string]eval.H{
+		"missing": {},
+		"empty":   {"users": []user{}},
+		"item":    {"users": users, "user": users[0]},
+	} {
+		if _, _, err = values.Accept(driver.MySQLDriver{}.Translator(), eval.NewGenericParam(param, "")); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestNewValuesNode_values_test(t *testing.T) {
+	values, err := NewValuesNode("list", "", "id")
+	if err != nil || values.Item != "item" {
+		t.Fatalf("expected the default item, got %v %v", values, err)
+	}
+	for _, columns := range []string{"", "id, id", "id:count(*)", "user.id", "id; --"} {
+		if _, err := NewValuesNode("list", "item", columns); err == nil {
+			t.Errorf("expected error for columns %q", columns)
+		}
+	}
+	if _, err := NewValuesNode("list", "item.x", "id"); err == nil {
+		t.Error("expected error for an invalid item")
+	}
+}
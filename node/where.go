@@ -33,8 +33,9 @@ type WhereNode struct {
 // Accept processes the WHERE clause and its conditions.
 // It handles several special cases:
 //  1. Removes leading "AND" or "OR" from the first condition
-//  2. Ensures the clause starts with "WHERE" if not already present
-//  3. Properly handles spacing between conditions
+//  2. Removes dangling "AND" or "OR" from the last condition
+//  3. Ensures the clause starts with "WHERE" if not already present
+//  4. Collapses the runs of whitespace outside of quotes and comments
 //
 // Examples:
 //
@@ -42,6 +43,7 @@ type WhereNode struct {
 //	Input:  "OR name = ?"       -> Output: "WHERE name = ?"
 //	Input:  "WHERE age > ?"     -> Output: "WHERE age > ?"
 //	Input:  "status = ?"        -> Output: "WHERE status = ?"
+//	Input:  "status = ? OR"     -> Output: "WHERE status = ?"
 func (w WhereNode) Accept(translator driver.Translator, p eval.Parameter) (query string, args []any, err error) {
 	p = w.BindNodes.ConvertParameter(p)
 
@@ -53,19 +55,107 @@ func (w WhereNode) Accept(translator driver.Translator, p eval.Parameter) (query
 	if query == "" {
 		return "", args, nil
 	}
+
+	query = collapseSpaces(query)
+
 	// A space is required at the end; otherwise, it is meaningless.
-	switch {
-	case strings.HasPrefix(query, "and ") || strings.HasPrefix(query, "AND "):
-		query = query[4:]
-	case strings.HasPrefix(query, "or ") || strings.HasPrefix(query, "OR "):
-		query = query[3:]
+	keyword := "WHERE "
+	if hasPrefixFold(query, keyword) {
+		keyword, query = query[:len(keyword)], query[len(keyword):]
 	}
 
-	// A space is required at the end; otherwise, it is meaningless.
-	if !strings.HasPrefix(query, "where ") && !strings.HasPrefix(query, "WHERE ") {
-		query = "WHERE " + query
+	if query = trimConnectors(query); query == "" {
+		return "", args, nil
+	}
+	return keyword + query, args, nil
+}
+
+// trimConnectors removes the AND and OR left at both ends of the conditions
+// by the conditions which were not rendered.
+func trimConnectors(query string) string {
+	for {
+		trimmed := query
+		for _, connector := range [...]string{"AND", "OR"} {
+			if hasPrefixFold(trimmed, connector) && (len(trimmed) == len(connector) || isConnectorBoundary(trimmed[len(connector)])) {
+				trimmed = strings.TrimLeft(trimmed[len(connector):], " ")
+			}
+			if end := len(trimmed) - len(connector); end >= 0 && strings.EqualFold(trimmed[end:], connector) && (end == 0 || isConnectorBoundary(trimmed[end-1])) {
+				trimmed = strings.TrimRight(trimmed[:end], " ")
+			}
+		}
+		if trimmed == query {
+			return query
+		}
+		query = trimmed
+	}
+}
+
+// isConnectorBoundary reports whether c can separate AND or OR from a condition.
+func isConnectorBoundary(c byte) bool {
+	return c == ' ' || c == '(' || c == ')'
+}
+
+// hasPrefixFold is strings.HasPrefix ignoring case.
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// hasSuffixFold is strings.HasSuffix ignoring case.
+func hasSuffixFold(s, suffix string) bool {
+	return len(s) >= len(suffix) && strings.EqualFold(s[len(s)-len(suffix):], suffix)
+}
+
+// collapseSpaces trims query and replaces its runs of whitespace with a single space.
+// The quoted literals and identifiers are kept as they are, and so are the line
+// comments, whose newline ends them.
+func collapseSpaces(query string) string {
+	if !strings.Contains(query, "  ") && !strings.ContainsAny(query, "\t\n\r") {
+		return strings.TrimSpace(query)
+	}
+
+	builder := getStringBuilder()
+	defer putStringBuilder(builder)
+	builder.Grow(len(query))
+
+	var quote byte
+	space := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			builder.WriteByte(c)
+			if c == '\\' && i+1 < len(query) {
+				i++
+				builder.WriteByte(query[i])
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = builder.Len() > 0
+			continue
+		}
+
+		if space {
+			builder.WriteByte(' ')
+			space = false
+		}
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				builder.WriteString(query[i:])
+				return builder.String()
+			}
+			builder.WriteString(query[i : i+end+1])
+			i += end
+			continue
+		}
+		builder.WriteByte(c)
 	}
-	return
+	return builder.String()
 }
 
 var _ Node = (*WhereNode)(nil)
@@ -0,0 +1,108 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	"github.com/go-juicedev/juice/internal/reflectlite"
+)
+
+// IncludeDeletedParamKey is the parameter which disables the filter of SoftDeleteNode when it is true.
+const IncludeDeletedParamKey = "includeDeleted"
+
+// SoftDeleteNode wraps the WHERE clause of a query to exclude the logically deleted rows,
+// whose Column is not NULL.
+// The original conditions are parenthesized, so that OR conditions keep their meaning:
+//
+//	Input:  "WHERE a = ? OR b = ?" -> Output: "WHERE (a = ? OR b = ?) AND deleted_at IS NULL"
+//	Input:  ""                     -> Output: "WHERE deleted_at IS NULL"
+//
+// The filter is disabled when the includeDeleted parameter is true.
+type SoftDeleteNode struct {
+	// Where is the wrapped WHERE clause.
+	Where Node
+	// Column is the column holding the deletion time.
+	Column string
+}
+
+// Accept implements Node interface.
+func (s SoftDeleteNode) Accept(translator driver.Translator, p eval.Parameter) (query string, args []any, err error) {
+	return WhereFilterNode{Where: s.Where, Filter: softDeleteFilter(s.Column)}.Accept(translator, p)
+}
+
+// softDeleteFilter renders the condition excluding the rows whose column is not NULL,
+// unless the includeDeleted parameter is true.
+type softDeleteFilter string
+
+// Accept implements Node interface.
+func (s softDeleteFilter) Accept(_ driver.Translator, p eval.Parameter) (query string, args []any, err error) {
+	if includeDeleted(p) {
+		return "", nil, nil
+	}
+	return string(s) + " IS NULL", nil, nil
+}
+
+// includeDeleted reports whether the includeDeleted parameter is true.
+func includeDeleted(p eval.Parameter) bool {
+	value, ok := p.Get(IncludeDeletedParamKey)
+	if !ok {
+		return false
+	}
+	value = reflectlite.Unwrap(value)
+	return value.IsValid() && value.Kind() == reflect.Bool && value.Bool()
+}
+
+var _ Node = (*SoftDeleteNode)(nil)
+
+// WhereFilterNode wraps the WHERE clause of a statement and combines it with the condition
+// rendered by Filter using AND, like SoftDeleteNode does for logically deleted rows.
+// The original conditions are parenthesized, so that OR conditions keep their meaning.
+// If Filter renders nothing, the WHERE clause is kept as it is.
+type WhereFilterNode struct {
+	// Where is the wrapped WHERE clause.
+	Where Node
+	// Filter renders the condition combined with the WHERE clause.
+	Filter Node
+}
+
+// Accept implements Node interface.
+func (w WhereFilterNode) Accept(translator driver.Translator, p eval.Parameter) (query string, args []any, err error) {
+	query, args, err = w.Where.Accept(translator, p)
+	if err != nil {
+		return "", nil, err
+	}
+	condition, filterArgs, err := w.Filter.Accept(translator, p)
+	if err != nil {
+		return "", nil, err
+	}
+	if condition == "" {
+		return query, args, nil
+	}
+	args = append(args, filterArgs...)
+	if query == "" {
+		return "WHERE " + condition, args, nil
+	}
+	// WhereNode always renders a leading WHERE keyword.
+	conditions := strings.TrimSpace(query[len("WHERE "):])
+	return "WHERE (" + conditions + ") AND " + condition, args, nil
+}
+
+var _ Node = (*WhereFilterNode)(nil)
@@ -176,6 +176,87 @@ func TestWhereNode_Accept_Comprehensive_where_test(t *testing.T) {
 			params:      emptyParams,
 			expectError: true,
 		},
+		{
+			name: "TrailingOR",
+			nodes: Group{
+				NewTextNode("status = #{status} OR"),
+			},
+			params:        eval.NewGenericParam(eval.H{"status": 1}, ""),
+			expectedQuery: "WHERE status = ?",
+			expectedArgs:  []any{1},
+		},
+		{
+			name: "TrailingLowercaseAND_AfterParenthesis",
+			nodes: Group{
+				NewTextNode("(a = 1 OR b = 2) and"),
+			},
+			params:        emptyParams,
+			expectedQuery: "WHERE (a = 1 OR b = 2)",
+		},
+		{
+			name: "LeadingAndTrailingConnectors",
+			nodes: Group{
+				NewTextNode("AND"),
+				NewTextNode("OR status = #{status} AND"),
+				NewTextNode("OR"),
+			},
+			params:        eval.NewGenericParam(eval.H{"status": 1}, ""),
+			expectedQuery: "WHERE status = ?",
+			expectedArgs:  []any{1},
+		},
+		{
+			name: "OnlyConnectors",
+			nodes: Group{
+				NewTextNode("AND"),
+				NewTextNode("OR"),
+			},
+			params:        emptyParams,
+			expectedQuery: "",
+		},
+		{
+			name: "ConnectorPrefixOfColumn",
+			nodes: Group{
+				NewTextNode("ORDER_ID = #{id} AND ANDROID = 1 AND COLOR"),
+			},
+			params:        eval.NewGenericParam(eval.H{"id": 1}, ""),
+			expectedQuery: "WHERE ORDER_ID = ? AND ANDROID = 1 AND COLOR",
+			expectedArgs:  []any{1},
+		},
+		{
+			name: "WHEREFollowedByConnector",
+			nodes: Group{
+				NewTextNode("WHERE\n\tAND ID = #{ID}"),
+			},
+			params:        eval.NewGenericParam(eval.H{"ID": 1}, ""),
+			expectedQuery: "WHERE ID = ?",
+			expectedArgs:  []any{1},
+		},
+		{
+			name: "CollapsesWhitespace",
+			nodes: Group{
+				NewTextNode("\n    AND  status = #{status}\n\t"),
+				NewTextNode("AND   name = #{name}  "),
+			},
+			params:        eval.NewGenericParam(eval.H{"status": 1, "name": "a"}, ""),
+			expectedQuery: "WHERE status = ? AND name = ?",
+			expectedArgs:  []any{1, "a"},
+		},
+		{
+			name: "KeepsQuotedWhitespace",
+			nodes: Group{
+				NewTextNode("name = 'a  b'  AND note = 'it\\'s  ok'  AND  `odd  column` = \"x  y\""),
+			},
+			params:        emptyParams,
+			expectedQuery: "WHERE name = 'a  b' AND note = 'it\\'s  ok' AND `odd  column` = \"x  y\"",
+		},
+		{
+			name: "KeepsLineComment",
+			nodes: Group{
+				NewTextNode("status = 1 -- active  only\n   AND name = 'a'"),
+			},
+			params:        emptyParams,
+			expectedQuery: "WHERE status = 1 -- active  only\n AND name = 'a'",
+		},
 	}
 
 	for _, tt := range tests {
@@ -228,3 +309,30 @@ func TestWhereNode_Accept_Comprehensive_where_test(t *testing.T) {
 		})
 	}
 }
+
+func TestSoftDeleteNode_where_test(t *testing.T) {
+	drv := driver.MySQLDriver{}
+	where := &WhereNode{Nodes: Group{
+		NewTextNode("name = #{name}"),
+		NewTextNode("OR age = #{age}"),
+	}}
+	softDelete := SoftDeleteNode{Where: where, Column: "deleted_at"}
+
+	query, args, err := softDelete.Accept(drv.Translator(), eval.NewGenericParam(eval.H{"name": "a", "age": 1}, ""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query != "WHERE (name = ? OR age = ?) AND deleted_at IS NULL" || len(args) != 2 {
+		t.Fatalf("unexpected query %q args %v", query, args)
+	}
+
+	empty := SoftDeleteNode{Where: &WhereNode{}, Column: "deleted_at"}
+	if query, _, _ = empty.Accept(drv.Translator(), eval.NewGenericParam(eval.H{}, "")); query != "WHERE deleted_at IS NULL" {
+		t.Fatalf("unexpected query %q", query)
+	}
+
+	query, _, err = softDelete.Accept(drv.Translator(), eval.NewGenericParam(eval.H{"name": "a", "age": 1, "includeDeleted": true}, ""))
+	if err != nil || query != "WHERE name = ? OR age = ?" {
+		t.Fatalf("expected includeDeleted to disable the filter, got %q err=%v", query, err)
+	}
+}
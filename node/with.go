@@ -0,0 +1,119 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+)
+
+// WithNode renders the WITH clause of its common table expressions,
+// so that a statement can be composed from named queries, like reusable fragments.
+//
+// Fields:
+//   - Recursive: Whether the expressions can refer to themselves, with WITH RECURSIVE
+//   - CTEs: The common table expressions, in order
+//
+// Example XML:
+//
+//	<select id="Subordinates">
+//	  <with recursive="true">
+//	    <cte name="subordinates">
+//	      SELECT id, manager_id FROM employees WHERE id = #{id}
+//	      UNION ALL
+//	      SELECT e.id, e.manager_id FROM employees e JOIN subordinates s ON e.manager_id = s.id
+//	    </cte>
+//	  </with>
+//	  SELECT id FROM subordinates
+//	</select>
+//
+// Example result:
+//
+//	WITH RECURSIVE subordinates AS (SELECT id, manager_id FROM employees WHERE id = ? UNION ALL ...) SELECT id FROM subordinates
+//
+// The expressions rendered empty are skipped, and nothing is rendered without expression.
+type WithNode struct {
+	Recursive bool
+	CTEs      []*CTENode
+}
+
+// Accept implements Node interface.
+func (w WithNode) Accept(translator driver.Translator, p eval.Parameter) (query string, args []any, err error) {
+	builder := getStringBuilder()
+	defer putStringBuilder(builder)
+
+	for _, cte := range w.CTEs {
+		q, a, err := cte.Accept(translator, p)
+		if err != nil {
+			return "", nil, err
+		}
+		if q == "" {
+			continue
+		}
+		if builder.Len() == 0 {
+			builder.WriteString("WITH ")
+			if w.Recursive {
+				builder.WriteString("RECURSIVE ")
+			}
+		} else {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(q)
+		if len(a) > 0 {
+			args = append(args, a...)
+		}
+	}
+	return builder.String(), args, nil
+}
+
+// CTENode renders a common table expression of a WithNode, like "name AS (query)".
+type CTENode struct {
+	Name      string
+	Nodes     Group
+	BindNodes BindNodeGroup
+}
+
+// NewCTENode returns the CTENode of the query nodes named name, which must be an identifier.
+func NewCTENode(name string, nodes Group, bindNodes BindNodeGroup) (*CTENode, error) {
+	if !identifierRegexp.MatchString(name) || strings.Contains(name, ".") {
+		return nil, fmt.Errorf("invalid cte name %q", name)
+	}
+	return &CTENode{Name: name, Nodes: nodes, BindNodes: bindNodes}, nil
+}
+
+// Accept implements Node interface.
+// It renders nothing when its query is empty.
+func (c CTENode) Accept(translator driver.Translator, p eval.Parameter) (query string, args []any, err error) {
+	p = c.BindNodes.ConvertParameter(p)
+
+	query, args, err = c.Nodes.Accept(translator, p)
+	if err != nil {
+		return "", nil, err
+	}
+	if query = strings.TrimSpace(query); query == "" {
+		return "", args, nil
+	}
+	return c.Name + " AS (" + query + ")", args, nil
+}
+
+var (
+	_ Node = (*WithNode)(nil)
+	_ Node = (*CTENode)(nil)
+)
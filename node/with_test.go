@@ -0,0 +1,87 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+)
+
+func TestWithNode_Accept_with_test(t *testing.T) {
+	recent, err := NewCTENode("recent", Group{NewTextNode("SELECT id FROM users WHERE created_at > #{since}")}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition := &ConditionNode{Nodes: Group{NewTextNode("SELECT id FROM users WHERE role = #{role}")}}
+	if err = condition.Parse("role != nil"); err != nil {
+		t.Fatal(err)
+	}
+	admins, err := NewCTENode("admins", Group{condition}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		node      WithNode
+		param     eval.H
+		wantQuery string
+		wantArgs  []any
+	}{
+		{
+			name:      "All",
+			node:      WithNode{CTEs: []*CTENode{recent, admins}},
+			param:     eval.H{"since": 1, "role": "admin"},
+			wantQuery: "WITH recent AS (SELECT id FROM users WHERE created_at > $1), admins AS (SELECT id FROM users WHERE role = $2)",
+			wantArgs:  []any{1, "admin"},
+		},
+		{
+			name:      "SkipEmpty",
+			node:      WithNode{Recursive: true, CTEs: []*CTENode{admins, recent}},
+			param:     eval.H{"since": 1, "role": nil},
+			wantQuery: "WITH RECURSIVE recent AS (SELECT id FROM users WHERE created_at > $1)",
+			wantArgs:  []any{1},
+		},
+		{
+			name:  "Empty",
+			node:  WithNode{CTEs: []*CTENode{admins}},
+			param: eval.H{"role": nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := tt.node.Accept(driver.PostgresDriver{}.Translator(), eval.NewGenericParam(tt.param, ""))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if query != tt.wantQuery || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Fatalf("got %q %v, want %q %v", query, args, tt.wantQuery, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestNewCTENode_with_test(t *testing.T) {
+	for _, name := range []string{"", "a b", "s.recent", "x)"} {
+		if _, err := NewCTENode(name, nil, nil); err == nil {
+			t.Errorf("expected error for name %q", name)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+
+	"github.com/go-juicedev/juice/node"
+)
+
+// NodeInterceptor intercepts the node tree of a mapped statement before it is built.
+// Unlike Middleware, which sees the rendered SQL, it can rewrite the structure of the statement,
+// for example appending a LIMIT clause or wrapping a <where> element.
+//
+// The given root is shared by all the executions of the statement and must not be modified,
+// an interceptor returns root itself or a new tree built around it.
+// Raw SQL statements are not intercepted.
+type NodeInterceptor interface {
+	InterceptNode(ctx context.Context, engine *Engine, statement Statement, root node.Node) (node.Node, error)
+}
+
+// NodeInterceptorFunc is an adapter to allow the use of ordinary functions as NodeInterceptor.
+type NodeInterceptorFunc func(ctx context.Context, engine *Engine, statement Statement, root node.Node) (node.Node, error)
+
+// InterceptNode implements NodeInterceptor.
+func (f NodeInterceptorFunc) InterceptNode(ctx context.Context, engine *Engine, statement Statement, root node.Node) (node.Node, error) {
+	return f(ctx, engine, statement, root)
+}
+
+// NodeInterceptorGroup runs the interceptors in registration order,
+// each one receives the tree returned by the previous one.
+type NodeInterceptorGroup []NodeInterceptor
+
+// InterceptNode implements NodeInterceptor.
+func (g NodeInterceptorGroup) InterceptNode(ctx context.Context, engine *Engine, statement Statement, root node.Node) (node.Node, error) {
+	var err error
+	for _, interceptor := range g {
+		if root, err = interceptor.InterceptNode(ctx, engine, statement, root); err != nil {
+			return nil, err
+		}
+	}
+	return root, nil
+}
+
+// ensure NodeInterceptorGroup implements NodeInterceptor.
+var _ NodeInterceptor = NodeInterceptorGroup(nil)
+
+// nodeTree is implemented by the statements built from a node tree.
+type nodeTree interface {
+	// rootNode returns the root of the node tree of the statement.
+	rootNode() node.Node
+
+	// withRootNode returns a copy of the statement built from root.
+	withRootNode(root node.Node) Statement
+}
+
+// interceptStatement returns the statement built from the node tree rewritten by the interceptors of the engine.
+// Statements which are not built from a node tree are returned as they are.
+func interceptStatement(ctx context.Context, engine *Engine, statement Statement) (Statement, error) {
+	tree, ok := statement.(nodeTree)
+	if !ok || len(engine.nodeInterceptors) == 0 {
+		return statement, nil
+	}
+	root := tree.rootNode()
+	intercepted, err := engine.nodeInterceptors.InterceptNode(ctx, engine, statement, root)
+	if err != nil {
+		return nil, err
+	}
+	return tree.withRootNode(intercepted), nil
+}
@@ -0,0 +1,78 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-juicedev/juice/eval"
+	"github.com/go-juicedev/juice/node"
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+func TestNodeInterceptor_node_interceptor_test(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>sqlite.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>
+		<mapper namespace="user">
+			<select id="Find">SELECT * FROM user <where>id = #{id}</where></select>
+		</mapper>
+	</mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	statement, err := configuration.GetStatement("user.Find")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	engine := newStatementTestEngine(nil)
+	engine.UseNodeInterceptor(NodeInterceptorFunc(func(_ context.Context, _ *Engine, statement Statement, root node.Node) (node.Node, error) {
+		if statement.Action() != jsql.Select {
+			return root, nil
+		}
+		return node.Group{root, node.NewTextNode("LIMIT #{limit}")}, nil
+	}))
+	engine.UseNodeInterceptor(NodeInterceptorFunc(func(_ context.Context, _ *Engine, _ Statement, root node.Node) (node.Node, error) {
+		return node.Group{root, node.NewTextNode("-- intercepted")}, nil
+	}))
+
+	query, args, err := buildStatementQuery(context.Background(), statement, engine, H{"id": 1, "limit": 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query != "SELECT * FROM user WHERE id = ? LIMIT ? -- intercepted" || len(args) != 2 || args[1] != 10 {
+		t.Fatalf("unexpected query %q %v", query, args)
+	}
+
+	// the tree of the statement is kept as it is.
+	query, _, err = statement.Build(engine.Driver().Translator(), eval.NewGenericParam(H{"id": 1}, ""))
+	if err != nil || query != "SELECT * FROM user WHERE id = ?" {
+		t.Fatalf("unexpected query %q err=%v", query, err)
+	}
+
+	// raw statements are not intercepted.
+	raw := NewRawSQLStatement("SELECT 1", jsql.Select)
+	if query, _, err = buildStatementQuery(context.Background(), raw, engine, nil); err != nil || query != "SELECT 1" {
+		t.Fatalf("unexpected query %q err=%v", query, err)
+	}
+
+	errIntercept := errors.New("intercept")
+	engine.UseNodeInterceptor(NodeInterceptorFunc(func(context.Context, *Engine, Statement, node.Node) (node.Node, error) {
+		return nil, errIntercept
+	}))
+	if _, _, err = buildStatementQuery(context.Background(), statement, engine, H{"id": 1}); !errors.Is(err, errIntercept) {
+		t.Fatalf("expected errIntercept, got %v", err)
+	}
+}
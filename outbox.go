@@ -0,0 +1,232 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	sqllib "github.com/go-juicedev/juice/sql"
+)
+
+// defaultOutboxTable is the outbox table used when Outbox.Table is empty.
+const defaultOutboxTable = "outbox"
+
+// OutboxMessage is a message written to the outbox table.
+type OutboxMessage struct {
+	// Topic is the destination of the message.
+	Topic string
+
+	// Key identifies the message for the consumers, like a partition key.
+	Key string
+
+	// Headers are the metadata of the message, stored as a JSON object.
+	Headers map[string]string
+
+	// Payload is the body of the message. A []byte or string payload is stored as is,
+	// any other value is serialized by Outbox.Marshal.
+	Payload any
+}
+
+// OutboxRecord is a message read from the outbox table.
+type OutboxRecord struct {
+	ID        int64
+	Topic     string
+	Key       string
+	Headers   map[string]string
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+// Outbox writes the messages to publish in the transaction of the statements producing them,
+// so that a message is published if and only if the transaction commits,
+// and reads them back for the relay publishing them.
+//
+// The table must have the columns:
+//
+//	id           auto-generated integer key, ordering the messages
+//	topic        text
+//	msg_key      text
+//	headers      text, NULL when the message has no header
+//	payload      binary or text
+//	created_at   timestamp
+//	published_at timestamp, NULL until the message is published
+//
+// Example:
+//
+//	outbox := juice.Outbox{}
+//	err := engine.InTx(ctx, nil, func(tx juice.TxManager) error {
+//	    if _, err := tx.Object("OrderMapper.Create").ExecContext(ctx, order); err != nil {
+//	        return err
+//	    }
+//	    return outbox.Publish(ctx, tx, juice.OutboxMessage{Topic: "order.created", Key: order.ID, Payload: order})
+//	})
+type Outbox struct {
+	// Table is the outbox table, "outbox" if empty.
+	Table string
+
+	// Marshal serializes the payloads, json.Marshal if nil.
+	Marshal func(v any) ([]byte, error)
+}
+
+// ErrOutboxNotInTransaction is returned by Outbox.Publish when the manager has no transaction.
+var ErrOutboxNotInTransaction = errors.New("juice: outbox messages must be published in a transaction")
+
+// table returns the outbox table.
+func (o Outbox) table() string {
+	if o.Table == "" {
+		return defaultOutboxTable
+	}
+	return o.Table
+}
+
+// Publish inserts the messages into the outbox table in the transaction of tx,
+// which must have begun.
+func (o Outbox) Publish(ctx context.Context, tx TxManager, messages ...OutboxMessage) error {
+	manager, ok := tx.(*BasicTxManager)
+	if !ok || manager.Transaction == nil {
+		return ErrOutboxNotInTransaction
+	}
+	runner := manager.Raw("INSERT INTO " + o.table() + " (topic, msg_key, headers, payload, created_at) " +
+		"VALUES (#{topic}, #{key}, #{headers}, #{payload}, #{createdAt})")
+	for _, message := range messages {
+		param, err := o.messageParam(message)
+		if err != nil {
+			return err
+		}
+		if _, err = runner.Insert(ctx, param); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// messageParam returns the parameter inserting message.
+func (o Outbox) messageParam(message OutboxMessage) (eval.H, error) {
+	var payload []byte
+	switch value := message.Payload.(type) {
+	case []byte:
+		payload = value
+	case string:
+		payload = []byte(value)
+	default:
+		marshal := o.Marshal
+		if marshal == nil {
+			marshal = json.Marshal
+		}
+		var err error
+		if payload, err = marshal(value); err != nil {
+			return nil, fmt.Errorf("juice: marshal outbox payload of %s: %w", message.Topic, err)
+		}
+	}
+	var headers any
+	if len(message.Headers) > 0 {
+		encoded, err := json.Marshal(message.Headers)
+		if err != nil {
+			return nil, err
+		}
+		headers = string(encoded)
+	}
+	return eval.H{
+		"topic":     message.Topic,
+		"key":       message.Key,
+		"headers":   headers,
+		"payload":   payload,
+		"createdAt": time.Now(),
+	}, nil
+}
+
+// Poll returns at most limit unpublished messages, oldest first.
+// manager is an Engine or a BasicTxManager.
+func (o Outbox) Poll(ctx context.Context, manager Manager, limit int) ([]OutboxRecord, error) {
+	raw, drv, err := outboxManager(manager)
+	if err != nil {
+		return nil, err
+	}
+	query := "SELECT id, topic, msg_key, headers, payload, created_at FROM " + o.table() +
+		" WHERE published_at IS NULL ORDER BY id " + driver.CapabilitiesOf(drv).LimitClause("#{limit}", "")
+	rows, err := raw(query).Select(ctx, eval.H{"limit": limit})
+	if err != nil {
+		return nil, err
+	}
+	return scanOutboxRecords(rows)
+}
+
+// MarkPublished marks the messages ids as published, so that Poll does not return them anymore.
+// manager is an Engine or a BasicTxManager.
+func (o Outbox) MarkPublished(ctx context.Context, manager Manager, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	raw, _, err := outboxManager(manager)
+	if err != nil {
+		return err
+	}
+	param := eval.H{"publishedAt": time.Now()}
+	placeholders := make([]string, len(ids))
+	for index, id := range ids {
+		name := "id" + strconv.Itoa(index)
+		param[name] = id
+		placeholders[index] = "#{" + name + "}"
+	}
+	query := "UPDATE " + o.table() + " SET published_at = #{publishedAt} WHERE id IN (" + strings.Join(placeholders, ", ") + ")"
+	_, err = raw(query).Update(ctx, param)
+	return err
+}
+
+// outboxManager returns the function running raw queries with manager and its driver.
+func outboxManager(manager Manager) (func(query string) Runner, driver.Driver, error) {
+	switch manager := manager.(type) {
+	case *Engine:
+		return manager.Raw, manager.Driver(), nil
+	case *BasicTxManager:
+		return manager.Raw, manager.engine.Driver(), nil
+	default:
+		return nil, nil, ErrInvalidManager
+	}
+}
+
+// scanOutboxRecords reads the outbox records from rows and closes them.
+func scanOutboxRecords(rows sqllib.Rows) (records []OutboxRecord, err error) {
+	defer func() { err = errors.Join(err, rows.Close()) }()
+	for rows.Next() {
+		var (
+			record  OutboxRecord
+			key     sql.NullString
+			headers sql.NullString
+		)
+		if err = rows.Scan(&record.ID, &record.Topic, &key, &headers, &record.Payload, &record.CreatedAt); err != nil {
+			return nil, err
+		}
+		record.Key = key.String
+		if headers.Valid && headers.String != "" {
+			if err = json.Unmarshal([]byte(headers.String), &record.Headers); err != nil {
+				return nil, fmt.Errorf("juice: outbox message %d headers: %w", record.ID, err)
+			}
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
@@ -0,0 +1,91 @@
+package juice
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+func TestOutbox_outbox_test(t *testing.T) {
+	state := &shSQLDriverState{}
+	engine := newStatementTestEngine(nil)
+	engine.db = openStatementTestDB(t, state)
+	outbox := Outbox{}
+
+	if err := outbox.Publish(t.Context(), engine.Tx(), OutboxMessage{Topic: "order.created"}); !errors.Is(err, ErrOutboxNotInTransaction) {
+		t.Fatalf("expected ErrOutboxNotInTransaction, got %v", err)
+	}
+
+	err := engine.InTx(t.Context(), nil, func(tx TxManager) error {
+		return outbox.Publish(t.Context(), tx,
+			OutboxMessage{Topic: "order.created", Key: "1", Headers: map[string]string{"trace": "abc"}, Payload: map[string]int{"id": 1}},
+			OutboxMessage{Topic: "order.created", Key: "2", Payload: []byte("raw")},
+		)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(state.connExecQueries) != 2 || state.commitCalls != 1 {
+		t.Fatalf("expected two inserts in a committed transaction, got %+v", state)
+	}
+	if got := state.connExecQueries[0]; got != "INSERT INTO outbox (topic, msg_key, headers, payload, created_at) VALUES (?, ?, ?, ?, ?)" {
+		t.Fatalf("unexpected insert: %s", got)
+	}
+
+	marshalErr := errors.New("marshal failed")
+	failing := Outbox{Marshal: func(any) ([]byte, error) { return nil, marshalErr }}
+	err = engine.InTx(t.Context(), nil, func(tx TxManager) error {
+		return failing.Publish(t.Context(), tx, OutboxMessage{Topic: "order.created", Payload: struct{}{}})
+	})
+	if !errors.Is(err, marshalErr) {
+		t.Fatalf("expected marshal error, got %v", err)
+	}
+
+	state.connExecQueries = nil
+	if err = (Outbox{Table: "events"}).MarkPublished(t.Context(), engine, 3, 4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := state.connExecQueries; len(got) != 1 || got[0] != "UPDATE events SET published_at = ? WHERE id IN (?, ?)" {
+		t.Fatalf("unexpected update: %v", got)
+	}
+
+	records, err := outbox.Poll(t.Context(), engine, 10)
+	if err != nil || len(records) != 0 || state.connQueryCalls != 1 {
+		t.Fatalf("expected no record, got %v, %v", records, err)
+	}
+	if _, err = outbox.Poll(t.Context(), &managerStub{}, 10); !errors.Is(err, ErrInvalidManager) {
+		t.Fatalf("expected ErrInvalidManager, got %v", err)
+	}
+}
+
+func TestScanOutboxRecords_outbox_test(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := jsql.NewRowsBuffer(
+		[]string{"id", "topic", "msg_key", "headers", "payload", "created_at"},
+		[][]any{
+			{int64(1), "order.created", "1", `{"trace":"abc"}`, []byte(`{"id":1}`), createdAt},
+			{int64(2), "order.created", nil, nil, []byte("raw"), createdAt},
+		},
+	)
+	records, err := scanOutboxRecords(rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 2 || records[0].Headers["trace"] != "abc" || string(records[0].Payload) != `{"id":1}` || !records[0].CreatedAt.Equal(createdAt) {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+	if records[1].Key != "" || records[1].Headers != nil {
+		t.Fatalf("unexpected record: %+v", records[1])
+	}
+
+	rows = jsql.NewRowsBuffer(
+		[]string{"id", "topic", "msg_key", "headers", "payload", "created_at"},
+		[][]any{{int64(1), "order.created", "1", "{", []byte("raw"), createdAt}},
+	)
+	if _, err = scanOutboxRecords(rows); err == nil || !strings.Contains(err.Error(), "headers") {
+		t.Fatalf("expected headers error, got %v", err)
+	}
+}
@@ -1,28 +1,99 @@
 package juice
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/go-juicedev/juice/eval"
 )
 
 // H is an alias of eval.H.
 type H = eval.H
 
+// paramNamesAttribute is the statement attribute naming its positional parameters, like "id, status".
+const paramNamesAttribute = "paramNames"
+
+// ErrParamCountMismatch is returned when the positional parameters do not match the names of the statement.
+var ErrParamCountMismatch = errors.New("juice: parameter count mismatch")
+
+// Params returns the parameter made of name and value pairs, like the @Param
+// annotated arguments of a mapper method:
+//
+//	juice.Params("id", id, "status", status)
+//
+// It panics if a name is not a non-empty string or if a name has no value.
+func Params(pairs ...any) H {
+	if len(pairs)%2 != 0 {
+		panic("juice: Params: odd number of arguments, expected name and value pairs")
+	}
+	params := make(H, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		name, ok := pairs[i].(string)
+		if !ok || name == "" {
+			panic(fmt.Sprintf("juice: Params: argument %d is %#v, expected a parameter name", i, pairs[i]))
+		}
+		params[name] = pairs[i+1]
+	}
+	return params
+}
+
+// PositionalParams are the parameters bound by position to the names declared
+// by the paramNames attribute of the statement:
+//
+//	<select id="Find" paramNames="id, status">
+//	    SELECT * FROM users WHERE id = #{id} AND status = #{status}
+//	</select>
+//
+//	juice.Positional(1, "active")
+//
+// Without paramNames, they are a slice parameter.
+type PositionalParams []any
+
+// Positional returns values as PositionalParams.
+func Positional(values ...any) PositionalParams {
+	return values
+}
+
+// bindParamNames names the PositionalParams of param after the paramNames of statement.
+func bindParamNames(statement Statement, param eval.Param) (eval.Param, error) {
+	positional, ok := param.(PositionalParams)
+	if !ok {
+		return param, nil
+	}
+	names := splitList(statement.Attribute(paramNamesAttribute))
+	if len(names) == 0 {
+		return param, nil
+	}
+	if len(names) != len(positional) {
+		return nil, fmt.Errorf("%w: %s declares %d names, got %d values", ErrParamCountMismatch, statement.Name(), len(names), len(positional))
+	}
+	named := make(H, len(names))
+	for i, name := range names {
+		named[name] = positional[i]
+	}
+	return named, nil
+}
+
 // buildStatementParameters builds the statement parameters.
-func buildStatementParameters(param any, statement Statement, driverName string, _ Configuration) eval.Parameter {
-	// Configuration is reserved for future parameter-building options.
+// funcs resolves the eval functions scoped to the engine and its configuration, it may be nil.
+func buildStatementParameters(param any, statement Statement, databaseID string, funcs eval.Parameter) eval.Parameter {
 	parameter := eval.ParamGroup{
 		eval.NewGenericParam(param, statement.Attribute("paramName")),
 
 		// Internal parameters for transporting extra statement metadata.
 		// User-defined parameters may override them.
 		eval.H{
-			"_databaseId": driverName,
+			"_databaseId": databaseID,
 		},
 		// Compatibility alias for the original parameter.
 		// map[string]User{"foo": {Name: "bar"}} => _parameter.foo.name
 		// User{Name: "bar"} => _parameter.name
 		eval.PrefixPatternParameter("_parameter", param),
 	}
+	if funcs != nil {
+		// Scoped eval functions have the lowest priority.
+		parameter = append(parameter, funcs)
+	}
 
 	return parameter
 }
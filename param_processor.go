@@ -0,0 +1,58 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+
+	"github.com/go-juicedev/juice/eval"
+)
+
+// ParamProcessor processes the parameter of a statement before the statement is built.
+// Unlike Middleware, which sees the rendered SQL, it can change the values used to render it.
+// engine is the engine executing the statement, which gives access to the configuration.
+// It returns the parameter used to build the statement, usually the given one after in-place changes,
+// so that middlewares observe the same values through StatementContext.Param.
+type ParamProcessor interface {
+	ProcessParam(ctx context.Context, engine *Engine, statement Statement, param eval.Param) (eval.Param, error)
+}
+
+// ParamProcessorFunc is an adapter to allow the use of ordinary functions as ParamProcessor.
+type ParamProcessorFunc func(ctx context.Context, engine *Engine, statement Statement, param eval.Param) (eval.Param, error)
+
+// ProcessParam implements ParamProcessor.
+func (f ParamProcessorFunc) ProcessParam(ctx context.Context, engine *Engine, statement Statement, param eval.Param) (eval.Param, error) {
+	return f(ctx, engine, statement, param)
+}
+
+// ParamProcessorGroup runs the processors in registration order,
+// each one receives the parameter returned by the previous one.
+type ParamProcessorGroup []ParamProcessor
+
+// ProcessParam implements ParamProcessor.
+func (g ParamProcessorGroup) ProcessParam(ctx context.Context, engine *Engine, statement Statement, param eval.Param) (eval.Param, error) {
+	var err error
+	for _, processor := range g {
+		if param, err = processor.ProcessParam(ctx, engine, statement, param); err != nil {
+			return nil, err
+		}
+	}
+	return param, nil
+}
+
+// ensure ParamProcessorGroup implements ParamProcessor.
+var _ ParamProcessor = ParamProcessorGroup(nil)
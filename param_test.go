@@ -0,0 +1,57 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	jdriver "github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	"github.com/go-juicedev/juice/node"
+)
+
+func TestParams(t *testing.T) {
+	params := Params("id", 1, "status", "active")
+	if len(params) != 2 || params["id"] != 1 || params["status"] != "active" {
+		t.Fatalf("unexpected params: %#v", params)
+	}
+
+	for _, pairs := range [][]any{{"id"}, {1, 2}, {"", 1}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Params(%#v) did not panic", pairs)
+				}
+			}()
+			Params(pairs...)
+		}()
+	}
+}
+
+func TestBuildStatementQueryParamNames(t *testing.T) {
+	text := node.NewTextNode("SELECT * FROM users WHERE id = #{id} AND status = #{status}")
+	stmt := shStatement{
+		attrs: map[string]string{"paramNames": "id, status"},
+		buildFn: func(translator jdriver.Translator, parameter eval.Parameter) (string, []any, error) {
+			return text.Accept(translator, parameter)
+		},
+	}
+	engine := newStatementTestEngine(nil)
+
+	query, args, err := buildStatementQuery(context.Background(), stmt, engine, Positional(1, "active"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "SELECT * FROM users WHERE id = ? AND status = ?" || len(args) != 2 || args[0] != 1 || args[1] != "active" {
+		t.Fatalf("unexpected query %q args %v", query, args)
+	}
+
+	if _, _, err = buildStatementQuery(context.Background(), stmt, engine, Positional(1)); !errors.Is(err, ErrParamCountMismatch) {
+		t.Fatalf("expected ErrParamCountMismatch, got %v", err)
+	}
+
+	// the named parameters are not affected by paramNames.
+	if _, args, err = buildStatementQuery(context.Background(), stmt, engine, Params("id", 2, "status", "idle")); err != nil || args[0] != 2 || args[1] != "idle" {
+		t.Fatalf("unexpected args %v err=%v", args, err)
+	}
+}
@@ -18,6 +18,10 @@ package parser
 
 // Document is the format-independent representation of a Juice configuration.
 type Document struct {
+	// Properties are the named values referenced by the other values of the configuration.
+	Properties map[string]string
+	// DatabaseIDs maps the driver names to the databaseId of the statements, from <databaseIdProvider>.
+	DatabaseIDs      map[string]string
 	Settings         map[string]string
 	Environments     Environments
 	MapperAttributes map[string]string
@@ -42,7 +46,14 @@ type Environment struct {
 	MaxOpenConns        string
 	ConnMaxLifetime     string
 	ConnMaxIdleLifetime string
-	Attributes          map[string]string
+	// Properties are the structured connection properties used to build the data source name
+	// when DataSource is empty.
+	Properties map[string]string
+	// TxIsolation and TxReadOnly are the attributes of the <transaction> element
+	// holding the default options of the transactions begun on the environment.
+	TxIsolation string
+	TxReadOnly  string
+	Attributes  map[string]string
 }
 
 // MapperSource identifies mapper documents referenced by the configuration.
@@ -64,6 +75,7 @@ type Mapper struct {
 	Attributes map[string]string
 	Statements []Statement
 	Fragments  []Fragment
+	ResultMaps []ResultMap
 }
 
 // Fragment is a reusable SQL node group declared by a sql element.
@@ -72,6 +84,24 @@ type Fragment struct {
 	Nodes []Node
 }
 
+// ResultMap is a resultMap element, mapping the rows into the models chosen by its discriminator.
+type ResultMap struct {
+	ID            string
+	Discriminator Discriminator
+}
+
+// Discriminator is the discriminator element of a resultMap.
+type Discriminator struct {
+	Column string
+	Cases  []DiscriminatorCase
+}
+
+// DiscriminatorCase maps the rows whose discriminator column is Value into the model registered as Type.
+type DiscriminatorCase struct {
+	Value string
+	Type  string
+}
+
 // Action identifies the operation represented by a statement.
 type Action string
 
@@ -88,4 +118,11 @@ type Statement struct {
 	Action     Action
 	Attributes map[string]string
 	Nodes      []Node
+	// LoadNodes parses the nodes of a statement whose body is kept unparsed,
+	// see LazyStatementsSetting. It is nil when Nodes are already parsed.
+	LoadNodes func() ([]Node, error)
 }
+
+// LazyStatementsSetting is the setting deferring the parsing of the statement bodies
+// of the mappers loaded from a resource, an url or a pattern until their first use.
+const LazyStatementsSetting = "lazyStatements"
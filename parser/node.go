@@ -29,6 +29,12 @@ const (
 	WhereNodeKind
 	SetNodeKind
 	IncludeNodeKind
+	AliasNodeKind
+	ValuesNodeKind
+	UpsertNodeKind
+	OrderByNodeKind
+	ColumnsNodeKind
+	WithNodeKind
 )
 
 // Node is a format-independent dynamic SQL node.
@@ -43,8 +49,9 @@ type TextNode struct {
 func (TextNode) Kind() NodeKind { return TextNodeKind }
 
 type IfNode struct {
-	Test     string
-	Children []Node
+	Test       string
+	DatabaseID string
+	Children   []Node
 }
 
 func (IfNode) Kind() NodeKind { return IfNodeKind }
@@ -63,6 +70,7 @@ type ForeachNode struct {
 	Open       string
 	Close      string
 	Separator  string
+	Test       string
 	Children   []Node
 }
 
@@ -110,3 +118,68 @@ type IncludeNode struct {
 }
 
 func (IncludeNode) Kind() NodeKind { return IncludeNodeKind }
+
+// AliasNode is an <alias> element.
+// Type is the name of a registered model.
+type AliasNode struct {
+	Type   string
+	Table  string
+	Prefix string
+}
+
+func (AliasNode) Kind() NodeKind { return AliasNodeKind }
+
+// ValuesNode is a <values> element.
+// Columns is a comma separated list of item fields, optionally followed by a colon and their column.
+type ValuesNode struct {
+	Collection string
+	Item       string
+	Columns    string
+}
+
+func (ValuesNode) Kind() NodeKind { return ValuesNodeKind }
+
+// UpsertNode is an <onConflict> or <onDuplicateKey> element.
+// Columns and Update are comma separated lists of columns.
+type UpsertNode struct {
+	Columns  string
+	Update   string
+	Children []Node
+}
+
+func (UpsertNode) Kind() NodeKind { return UpsertNodeKind }
+
+// OrderByNode is an <orderBy> element.
+// Allowed is a comma separated list of fields, optionally followed by a colon and their column.
+type OrderByNode struct {
+	Param   string
+	Allowed string
+	Default string
+}
+
+func (OrderByNode) Kind() NodeKind { return OrderByNodeKind }
+
+// ColumnsNode is a <columns> element.
+// Allowed is a comma separated list of fields, optionally followed by a colon and their column.
+type ColumnsNode struct {
+	Param   string
+	Allowed string
+	Default string
+}
+
+func (ColumnsNode) Kind() NodeKind { return ColumnsNodeKind }
+
+// CTENode is a <cte> element of a <with> element.
+type CTENode struct {
+	Name     string
+	Children []Node
+}
+
+// WithNode is a <with> element.
+// Recursive is the raw value of its recursive attribute.
+type WithNode struct {
+	Recursive string
+	CTEs      []CTENode
+}
+
+func (WithNode) Kind() NodeKind { return WithNodeKind }
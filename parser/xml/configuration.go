@@ -37,6 +37,18 @@ func (p *Parser) parseConfiguration(decoder *stdxml.Decoder) (*parser.Document,
 		switch token := token.(type) {
 		case stdxml.StartElement:
 			switch token.Name.Local {
+			case "properties":
+				properties, err := parseProperties(decoder, "properties")
+				if err != nil {
+					return nil, err
+				}
+				document.Properties = properties
+			case "databaseIdProvider":
+				databaseIDs, err := parseProperties(decoder, "databaseIdProvider")
+				if err != nil {
+					return nil, err
+				}
+				document.DatabaseIDs = databaseIDs
 			case "settings":
 				settings, err := parseSettings(decoder)
 				if err != nil {
@@ -105,6 +117,37 @@ func parseSettings(decoder *stdxml.Decoder) (map[string]string, error) {
 	}
 }
 
+func parseProperties(decoder *stdxml.Decoder, end string) (map[string]string, error) {
+	properties := make(map[string]string)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch token := token.(type) {
+		case stdxml.StartElement:
+			if token.Name.Local != "property" {
+				return nil, wrap(token.Name.Local, fmt.Errorf("expected <property>"))
+			}
+			name, err := requiredAttribute(token, "name")
+			if err != nil {
+				return nil, wrap("property", err)
+			}
+			if _, exists := properties[name]; exists {
+				return nil, wrap("property", fmt.Errorf("duplicate property %q", name))
+			}
+			properties[name] = attribute(token, "value")
+			if err := skipElement(decoder, token); err != nil {
+				return nil, err
+			}
+		case stdxml.EndElement:
+			if token.Name.Local == end {
+				return properties, nil
+			}
+		}
+	}
+}
+
 func parseEnvironments(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.Environments, error) {
 	environments := parser.Environments{Default: attribute(start, "default"), Present: true}
 	for {
@@ -143,6 +186,20 @@ func parseEnvironment(decoder *stdxml.Decoder, start stdxml.StartElement) (parse
 		}
 		switch token := token.(type) {
 		case stdxml.StartElement:
+			if token.Name.Local == "property" {
+				if err := parseEnvironmentProperty(decoder, token, &environment); err != nil {
+					return parser.Environment{}, err
+				}
+				continue
+			}
+			if token.Name.Local == "transaction" {
+				environment.TxIsolation = attribute(token, "isolation")
+				environment.TxReadOnly = attribute(token, "readOnly")
+				if err := skipElement(decoder, token); err != nil {
+					return parser.Environment{}, err
+				}
+				continue
+			}
 			value, err := parseText(decoder, token.Name.Local)
 			if err != nil {
 				return parser.Environment{}, wrap(token.Name.Local, err)
@@ -171,6 +228,25 @@ func parseEnvironment(decoder *stdxml.Decoder, start stdxml.StartElement) (parse
 	}
 }
 
+func parseEnvironmentProperty(decoder *stdxml.Decoder, start stdxml.StartElement, environment *parser.Environment) error {
+	name, err := requiredAttribute(start, "name")
+	if err != nil {
+		return wrap("property", err)
+	}
+	value, err := requiredAttribute(start, "value")
+	if err != nil {
+		return wrap("property", err)
+	}
+	if environment.Properties == nil {
+		environment.Properties = make(map[string]string)
+	}
+	if _, exists := environment.Properties[name]; exists {
+		return wrap("property", fmt.Errorf("duplicate property %q", name))
+	}
+	environment.Properties[name] = value
+	return skipElement(decoder, start)
+}
+
 func parseMappers(decoder *stdxml.Decoder, start stdxml.StartElement, document *parser.Document) error {
 	document.MapperAttributes = attributes(start)
 	if pattern := attribute(start, "pattern"); pattern != "" {
@@ -209,7 +285,7 @@ func parseMappers(decoder *stdxml.Decoder, start stdxml.StartElement, document *
 				}
 				continue
 			}
-			mapperDocument, err := parseMapper(decoder, token)
+			mapperDocument, err := parseMapper(decoder, token, nil)
 			if err != nil {
 				return err
 			}
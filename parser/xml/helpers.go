@@ -41,6 +41,10 @@ func attributes(start stdxml.StartElement) map[string]string {
 	}
 	attrs := make(map[string]string, len(start.Attr))
 	for _, attr := range start.Attr {
+		// namespace declarations are not attributes of the element.
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
 		attrs[attr.Name.Local] = attr.Value
 	}
 	return attrs
@@ -23,7 +23,10 @@ import (
 	"io/fs"
 	"net/http"
 	"net/url"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-juicedev/juice/parser"
@@ -55,6 +58,8 @@ func (p *Parser) loadMapperSources(document *parser.Document) error {
 	if len(document.MapperEntries) == 0 {
 		return nil
 	}
+	// settings are declared after the mappers, so only the mapper sources can be lazy.
+	lazy, _ := strconv.ParseBool(document.Settings[parser.LazyStatementsSetting])
 	resolved := make([]parser.Mapper, 0, len(document.MapperEntries))
 	for _, entry := range document.MapperEntries {
 		if entry.Mapper != nil {
@@ -71,21 +76,19 @@ func (p *Parser) loadMapperSources(document *parser.Document) error {
 			if err != nil {
 				return fmt.Errorf("invalid mapper pattern %q: %w", source.Pattern, err)
 			}
-			for _, match := range matches {
-				mapperDocument, err := p.loadMapperResource(match)
-				if err != nil {
-					return err
-				}
-				resolved = append(resolved, mapperDocument)
+			mapperDocuments, err := p.loadMapperResources(matches, lazy)
+			if err != nil {
+				return err
 			}
+			resolved = append(resolved, mapperDocuments...)
 		case source.Resource != "":
-			mapperDocument, err := p.loadMapperResource(source.Resource)
+			mapperDocument, err := p.loadMapperResource(source.Resource, lazy)
 			if err != nil {
 				return err
 			}
 			resolved = append(resolved, mapperDocument)
 		case source.URL != "":
-			mapperDocument, err := p.loadMapperURL(source.URL)
+			mapperDocument, err := p.loadMapperURL(source.URL, lazy)
 			if err != nil {
 				return err
 			}
@@ -96,7 +99,7 @@ func (p *Parser) loadMapperSources(document *parser.Document) error {
 	return nil
 }
 
-func (p *Parser) loadMapperResource(resource string) (parser.Mapper, error) {
+func (p *Parser) loadMapperResource(resource string, lazy bool) (parser.Mapper, error) {
 	if p.FS == nil {
 		return parser.Mapper{}, errors.New("xml parser filesystem is required")
 	}
@@ -105,29 +108,73 @@ func (p *Parser) loadMapperResource(resource string) (parser.Mapper, error) {
 		return parser.Mapper{}, err
 	}
 	defer func() { _ = file.Close() }()
-	mapperDocument, err := ParseMapper(file)
+	mapperDocument, err := parseMapperDocument(file, lazy)
 	if err != nil {
 		return parser.Mapper{}, fmt.Errorf("failed to parse mapper %q: %w", resource, err)
 	}
 	return *mapperDocument, nil
 }
 
-func (p *Parser) loadMapperURL(rawURL string) (parser.Mapper, error) {
+// loadMapperResources parses resources concurrently with at most Parser.Concurrency workers.
+// The mappers are returned in the order of resources, and the error is the one of the first
+// failing resource in that order, so that the result does not depend on scheduling.
+func (p *Parser) loadMapperResources(resources []string, lazy bool) ([]parser.Mapper, error) {
+	workers := p.Concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(resources))
+
+	mappers := make([]parser.Mapper, len(resources))
+	errs := make([]error, len(resources))
+	if workers <= 1 {
+		for i, resource := range resources {
+			if mappers[i], errs[i] = p.loadMapperResource(resource, lazy); errs[i] != nil {
+				return nil, errs[i]
+			}
+		}
+		return mappers, nil
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for i := range indexes {
+				mappers[i], errs[i] = p.loadMapperResource(resources[i], lazy)
+			}
+		})
+	}
+	for i := range resources {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return mappers, nil
+}
+
+func (p *Parser) loadMapperURL(rawURL string, lazy bool) (parser.Mapper, error) {
 	parsedURL, err := url.Parse(rawURL)
 	if err != nil {
 		return parser.Mapper{}, err
 	}
 	switch parsedURL.Scheme {
 	case "file":
-		return p.loadMapperResource(strings.TrimPrefix(parsedURL.Path, "/"))
+		return p.loadMapperResource(strings.TrimPrefix(parsedURL.Path, "/"), lazy)
 	case "http", "https":
-		return p.loadRemoteMapper(rawURL)
+		return p.loadRemoteMapper(rawURL, lazy)
 	default:
 		return parser.Mapper{}, fmt.Errorf("invalid mapper URL scheme %q", parsedURL.Scheme)
 	}
 }
 
-func (p *Parser) loadRemoteMapper(rawURL string) (parser.Mapper, error) {
+func (p *Parser) loadRemoteMapper(rawURL string, lazy bool) (parser.Mapper, error) {
 	client := p.Client
 	if client == nil {
 		client = &http.Client{Timeout: 30 * time.Second}
@@ -141,7 +188,7 @@ func (p *Parser) loadRemoteMapper(rawURL string) (parser.Mapper, error) {
 		_, _ = io.Copy(io.Discard, response.Body)
 		return parser.Mapper{}, fmt.Errorf("%w: %s returned %s", ErrUnexpectedHTTPStatus, rawURL, response.Status)
 	}
-	mapperDocument, err := ParseMapper(response.Body)
+	mapperDocument, err := parseMapperDocument(response.Body, lazy)
 	if err != nil {
 		return parser.Mapper{}, fmt.Errorf("failed to parse mapper %q: %w", rawURL, err)
 	}
@@ -17,13 +17,18 @@ limitations under the License.
 package xml
 
 import (
+	"bytes"
 	stdxml "encoding/xml"
 	"fmt"
+	"strings"
 
 	"github.com/go-juicedev/juice/parser"
 )
 
-func parseMapper(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.Mapper, error) {
+// parseMapper parses the mapper element start.
+// When source is not nil, it is the document read by decoder and the bodies of the
+// statements are kept unparsed, see parseLazyStatement.
+func parseMapper(decoder *stdxml.Decoder, start stdxml.StartElement, source []byte) (parser.Mapper, error) {
 	namespace, err := requiredAttribute(start, "namespace")
 	if err != nil {
 		return parser.Mapper{}, wrap("mapper", err)
@@ -32,8 +37,11 @@ func parseMapper(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.Map
 		Namespace:  namespace,
 		Attributes: attributes(start),
 	}
-	statementIDs := make(map[string]struct{})
+	// statements sharing an id are the variants of distinct databaseIds.
+	type statementKey struct{ id, databaseID string }
+	statementIDs := make(map[statementKey]struct{})
 	fragmentIDs := make(map[string]struct{})
+	resultMapIDs := make(map[string]struct{})
 
 	for {
 		token, err := decoder.Token()
@@ -45,14 +53,20 @@ func parseMapper(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.Map
 			action := parser.Action(token.Name.Local)
 			switch action {
 			case parser.Select, parser.Insert, parser.Update, parser.Delete:
-				statement, err := parseStatement(decoder, token, action)
+				var statement parser.Statement
+				if source != nil {
+					statement, err = parseLazyStatement(decoder, token, action, source)
+				} else {
+					statement, err = parseStatement(decoder, token, action)
+				}
 				if err != nil {
 					return parser.Mapper{}, err
 				}
-				if _, exists := statementIDs[statement.ID]; exists {
+				key := statementKey{id: statement.ID, databaseID: statement.Attributes["databaseId"]}
+				if _, exists := statementIDs[key]; exists {
 					return parser.Mapper{}, wrap(token.Name.Local, fmt.Errorf("duplicate statement id %q", statement.ID))
 				}
-				statementIDs[statement.ID] = struct{}{}
+				statementIDs[key] = struct{}{}
 				mapperDocument.Statements = append(mapperDocument.Statements, statement)
 			case "sql":
 				fragment, err := parseFragment(decoder, token)
@@ -64,6 +78,16 @@ func parseMapper(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.Map
 				}
 				fragmentIDs[fragment.ID] = struct{}{}
 				mapperDocument.Fragments = append(mapperDocument.Fragments, fragment)
+			case "resultMap":
+				resultMap, err := parseResultMap(decoder, token)
+				if err != nil {
+					return parser.Mapper{}, err
+				}
+				if _, exists := resultMapIDs[resultMap.ID]; exists {
+					return parser.Mapper{}, wrap("resultMap", fmt.Errorf("duplicate resultMap id %q", resultMap.ID))
+				}
+				resultMapIDs[resultMap.ID] = struct{}{}
+				mapperDocument.ResultMaps = append(mapperDocument.ResultMaps, resultMap)
 			default:
 				return parser.Mapper{}, wrap(token.Name.Local, fmt.Errorf("unknown mapper element"))
 			}
@@ -92,6 +116,53 @@ func parseStatement(decoder *stdxml.Decoder, start stdxml.StartElement, action p
 	}, nil
 }
 
+// parseLazyStatement reads the statement element start without parsing its body.
+// The raw body is sliced from source and parsed by the LoadNodes of the statement.
+func parseLazyStatement(decoder *stdxml.Decoder, start stdxml.StartElement, action parser.Action, source []byte) (parser.Statement, error) {
+	id, err := requiredAttribute(start, "id")
+	if err != nil {
+		return parser.Statement{}, wrap(start.Name.Local, err)
+	}
+	name := start.Name.Local
+	begin := decoder.InputOffset()
+	var end int64
+	for depth := 0; ; {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err != nil {
+			return parser.Statement{}, elementReadError(name, err)
+		}
+		switch token.(type) {
+		case stdxml.StartElement:
+			depth++
+		case stdxml.EndElement:
+			depth--
+		}
+		if depth < 0 {
+			end = offset
+			break
+		}
+	}
+	// the body is re-wrapped into its element, so that it is parsed like an eager statement.
+	body := make([]byte, 0, int(end-begin)+2*len(name)+5)
+	body = append(append(append(body, '<'), name...), '>')
+	body = append(body, source[begin:end]...)
+	body = append(append(append(body, "</"...), name...), '>')
+
+	return parser.Statement{
+		ID:         id,
+		Action:     action,
+		Attributes: attributes(start),
+		LoadNodes: func() ([]parser.Node, error) {
+			decoder := stdxml.NewDecoder(bytes.NewReader(body))
+			if _, err := decoder.Token(); err != nil {
+				return nil, elementReadError(name, err)
+			}
+			return parseNodes(decoder, name, true)
+		},
+	}, nil
+}
+
 func parseFragment(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.Fragment, error) {
 	id, err := requiredAttribute(start, "id")
 	if err != nil {
@@ -103,3 +174,83 @@ func parseFragment(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.F
 	}
 	return parser.Fragment{ID: id, Nodes: nodes}, nil
 }
+
+// parseResultMap parses a resultMap element, made of a single discriminator.
+func parseResultMap(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.ResultMap, error) {
+	id, err := requiredAttribute(start, "id")
+	if err != nil {
+		return parser.ResultMap{}, wrap("resultMap", err)
+	}
+	resultMap := parser.ResultMap{ID: id}
+	var hasDiscriminator bool
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return parser.ResultMap{}, elementReadError("resultMap", err)
+		}
+		switch token := token.(type) {
+		case stdxml.CharData:
+			if strings.TrimSpace(string(token)) != "" {
+				return parser.ResultMap{}, wrap("resultMap", fmt.Errorf("text is not allowed directly inside resultMap"))
+			}
+		case stdxml.StartElement:
+			if token.Name.Local != "discriminator" {
+				return parser.ResultMap{}, wrap(token.Name.Local, fmt.Errorf("expected <discriminator>"))
+			}
+			if hasDiscriminator {
+				return parser.ResultMap{}, wrap("discriminator", fmt.Errorf("element may only appear once"))
+			}
+			if resultMap.Discriminator, err = parseDiscriminator(decoder, token); err != nil {
+				return parser.ResultMap{}, err
+			}
+			hasDiscriminator = true
+		case stdxml.EndElement:
+			if token.Name.Local == "resultMap" {
+				if !hasDiscriminator {
+					return parser.ResultMap{}, wrap("resultMap", fmt.Errorf("element <discriminator> is required"))
+				}
+				return resultMap, nil
+			}
+		}
+	}
+}
+
+func parseDiscriminator(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.Discriminator, error) {
+	column, err := requiredAttribute(start, "column")
+	if err != nil {
+		return parser.Discriminator{}, wrap("discriminator", err)
+	}
+	discriminator := parser.Discriminator{Column: column}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return parser.Discriminator{}, elementReadError("discriminator", err)
+		}
+		switch token := token.(type) {
+		case stdxml.CharData:
+			if strings.TrimSpace(string(token)) != "" {
+				return parser.Discriminator{}, wrap("discriminator", fmt.Errorf("text is not allowed directly inside discriminator"))
+			}
+		case stdxml.StartElement:
+			if token.Name.Local != "case" {
+				return parser.Discriminator{}, wrap(token.Name.Local, fmt.Errorf("expected <case>"))
+			}
+			value, err := requiredAttribute(token, "value")
+			if err != nil {
+				return parser.Discriminator{}, wrap("case", err)
+			}
+			tp, err := requiredAttribute(token, "type")
+			if err != nil {
+				return parser.Discriminator{}, wrap("case", err)
+			}
+			if err := skipElement(decoder, token); err != nil {
+				return parser.Discriminator{}, err
+			}
+			discriminator.Cases = append(discriminator.Cases, parser.DiscriminatorCase{Value: value, Type: tp})
+		case stdxml.EndElement:
+			if token.Name.Local == "discriminator" {
+				return discriminator, nil
+			}
+		}
+	}
+}
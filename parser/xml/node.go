@@ -75,21 +75,96 @@ func parseNode(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.Node,
 		return parser.SetNode{Children: children}, err
 	case "include":
 		return parseInclude(decoder, start)
+	case "alias":
+		return parseAlias(decoder, start)
+	case "values":
+		return parseValues(decoder, start)
+	case "onConflict", "onDuplicateKey":
+		children, err := parseNodes(decoder, start.Name.Local, false)
+		return parser.UpsertNode{
+			Columns:  attribute(start, "columns"),
+			Update:   attribute(start, "update"),
+			Children: children,
+		}, err
+	case "orderBy":
+		return parseOrderBy(decoder, start)
+	case "columns":
+		return parseColumns(decoder, start)
+	case "with":
+		return parseWith(decoder, start)
 	default:
 		return nil, wrap(start.Name.Local, fmt.Errorf("unknown dynamic SQL element"))
 	}
 }
 
 func parseIf(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.Node, error) {
-	test, err := requiredAttribute(start, "test")
-	if err != nil {
-		return nil, wrap("if", err)
+	// the test can be omitted when the element is only selected by databaseId.
+	databaseID := attribute(start, "databaseId")
+	test := attribute(start, "test")
+	if test == "" && databaseID == "" {
+		return nil, wrap("if", fmt.Errorf("attribute %q is required", "test"))
 	}
 	children, err := parseNodes(decoder, "if", false)
 	if err != nil {
 		return nil, err
 	}
-	return parser.IfNode{Test: test, Children: children}, nil
+	return parser.IfNode{Test: test, DatabaseID: databaseID, Children: children}, nil
+}
+
+func parseAlias(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.Node, error) {
+	tp, err := requiredAttribute(start, "type")
+	if err != nil {
+		return nil, wrap("alias", err)
+	}
+	if err := skipElement(decoder, start); err != nil {
+		return nil, err
+	}
+	return parser.AliasNode{Type: tp, Table: attribute(start, "table"), Prefix: attribute(start, "prefix")}, nil
+}
+
+func parseValues(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.Node, error) {
+	collection, err := requiredAttribute(start, "collection")
+	if err != nil {
+		return nil, wrap("values", err)
+	}
+	columns, err := requiredAttribute(start, "columns")
+	if err != nil {
+		return nil, wrap("values", err)
+	}
+	if err := skipElement(decoder, start); err != nil {
+		return nil, err
+	}
+	return parser.ValuesNode{Collection: collection, Item: attribute(start, "item"), Columns: columns}, nil
+}
+
+func parseOrderBy(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.Node, error) {
+	param, err := requiredAttribute(start, "param")
+	if err != nil {
+		return nil, wrap("orderBy", err)
+	}
+	allowed, err := requiredAttribute(start, "allowed")
+	if err != nil {
+		return nil, wrap("orderBy", err)
+	}
+	if err := skipElement(decoder, start); err != nil {
+		return nil, err
+	}
+	return parser.OrderByNode{Param: param, Allowed: allowed, Default: attribute(start, "default")}, nil
+}
+
+func parseColumns(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.Node, error) {
+	param, err := requiredAttribute(start, "param")
+	if err != nil {
+		return nil, wrap("columns", err)
+	}
+	allowed, err := requiredAttribute(start, "allowed")
+	if err != nil {
+		return nil, wrap("columns", err)
+	}
+	if err := skipElement(decoder, start); err != nil {
+		return nil, err
+	}
+	return parser.ColumnsNode{Param: param, Allowed: allowed, Default: attribute(start, "default")}, nil
 }
 
 func parseBind(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.Node, error) {
@@ -123,6 +198,7 @@ func parseForeach(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.No
 		Open:       attribute(start, "open"),
 		Close:      attribute(start, "close"),
 		Separator:  attribute(start, "separator"),
+		Test:       attribute(start, "test"),
 		Children:   children,
 	}, nil
 }
@@ -179,6 +255,42 @@ func parseChoose(decoder *stdxml.Decoder) (parser.Node, error) {
 	}
 }
 
+func parseWith(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.Node, error) {
+	with := parser.WithNode{Recursive: attribute(start, "recursive")}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch token := token.(type) {
+		case stdxml.CharData:
+			if strings.TrimSpace(string(token)) != "" {
+				return nil, wrap("with", fmt.Errorf("text is not allowed directly inside with"))
+			}
+		case stdxml.StartElement:
+			if token.Name.Local != "cte" {
+				return nil, wrap(token.Name.Local, fmt.Errorf("expected <cte>"))
+			}
+			name, err := requiredAttribute(token, "name")
+			if err != nil {
+				return nil, wrap("cte", err)
+			}
+			children, err := parseNodes(decoder, "cte", false)
+			if err != nil {
+				return nil, err
+			}
+			with.CTEs = append(with.CTEs, parser.CTENode{Name: name, Children: children})
+		case stdxml.EndElement:
+			if token.Name.Local == "with" {
+				if len(with.CTEs) == 0 {
+					return nil, wrap("with", fmt.Errorf("at least one <cte> is required"))
+				}
+				return with, nil
+			}
+		}
+	}
+}
+
 func parseTrim(decoder *stdxml.Decoder, start stdxml.StartElement) (parser.Node, error) {
 	children, err := parseNodes(decoder, "trim", false)
 	if err != nil {
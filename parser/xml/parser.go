@@ -17,6 +17,7 @@ limitations under the License.
 package xml
 
 import (
+	"bytes"
 	stdxml "encoding/xml"
 	"errors"
 	"fmt"
@@ -33,6 +34,10 @@ type Parser struct {
 	FS                fs.FS
 	Client            *http.Client
 	IgnoreEnvironment bool
+
+	// Concurrency bounds the number of mapper files matched by a pattern parsed at once.
+	// Zero means runtime.GOMAXPROCS(0).
+	Concurrency int
 }
 
 var _ parser.Parser = (*Parser)(nil)
@@ -63,6 +68,20 @@ func Parse(reader io.Reader) (*parser.Document, error) {
 }
 
 func ParseMapper(reader io.Reader) (*parser.Mapper, error) {
+	return parseMapperDocument(reader, false)
+}
+
+// parseMapperDocument parses a mapper document, keeping the bodies of its statements
+// unparsed until their first use when lazy is true.
+func parseMapperDocument(reader io.Reader, lazy bool) (*parser.Mapper, error) {
+	var source []byte
+	if lazy {
+		var err error
+		if source, err = io.ReadAll(reader); err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(source)
+	}
 	decoder := stdxml.NewDecoder(reader)
 	for {
 		token, err := decoder.Token()
@@ -79,7 +98,7 @@ func ParseMapper(reader io.Reader) (*parser.Mapper, error) {
 		if start.Name.Local != "mapper" {
 			return nil, wrap(start.Name.Local, ErrMapperRootElementNotFound)
 		}
-		mapperDocument, err := parseMapper(decoder, start)
+		mapperDocument, err := parseMapper(decoder, start, source)
 		if err != nil {
 			return nil, err
 		}
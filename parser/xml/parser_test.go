@@ -2,8 +2,10 @@ package xml_test
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 	"testing/fstest"
@@ -49,6 +51,7 @@ func TestParseConfigurationDocument(t *testing.T) {
             <driver>sqlite3</driver>
             <dataSource>app.db</dataSource>
             <maxOpenConnNum>20</maxOpenConnNum>
+            <transaction isolation="repeatable read" readOnly="true"/>
         </environment>
     </environments>
     <mappers pattern="mappers/*.xml">
@@ -73,6 +76,9 @@ func TestParseConfigurationDocument(t *testing.T) {
 	if environment.Driver != "sqlite3" || environment.DataSource != "app.db" || environment.MaxOpenConns != "20" {
 		t.Fatalf("unexpected environment: %#v", environment)
 	}
+	if environment.TxIsolation != "repeatable read" || environment.TxReadOnly != "true" {
+		t.Fatalf("unexpected environment transaction: %#v", environment)
+	}
 	if len(document.MapperSources) != 3 {
 		t.Fatalf("unexpected mapper sources: %#v", document.MapperSources)
 	}
@@ -93,7 +99,7 @@ func TestParseMapperDynamicNodes(t *testing.T) {
         from users
         <where>
             <if test="name != nil">name = #{name}</if>
-            <foreach collection="ids" item="id" open="(" close=")" separator=",">
+            <foreach collection="ids" item="id" open="(" close=")" separator="," test="id &gt; 0">
                 #{id}
             </foreach>
             <choose>
@@ -101,6 +107,7 @@ func TestParseMapperDynamicNodes(t *testing.T) {
                 <otherwise>active = 0</otherwise>
             </choose>
         </where>
+        <orderBy param="sort" allowed="name, createdAt:created_at" default="-createdAt"/>
     </select>
 </mapper>`))
 	if err != nil {
@@ -134,13 +141,117 @@ func TestParseMapperDynamicNodes(t *testing.T) {
 		t.Fatalf("unexpected if node: %#v", where.Children[0])
 	}
 	foreach, ok := where.Children[1].(parser.ForeachNode)
-	if !ok || foreach.Collection != "ids" || foreach.Item != "id" {
+	if !ok || foreach.Collection != "ids" || foreach.Item != "id" || foreach.Test != "id > 0" {
 		t.Fatalf("unexpected foreach node: %#v", where.Children[1])
 	}
 	choose, ok := where.Children[2].(parser.ChooseNode)
 	if !ok || len(choose.Whens) != 1 || len(choose.Otherwise) != 1 {
 		t.Fatalf("unexpected choose node: %#v", where.Children[2])
 	}
+	orderBy, ok := statement.Nodes[4].(parser.OrderByNode)
+	if !ok || orderBy.Param != "sort" || orderBy.Allowed != "name, createdAt:created_at" || orderBy.Default != "-createdAt" {
+		t.Fatalf("unexpected orderBy node: %#v", statement.Nodes[4])
+	}
+}
+
+func TestParseMapperAlias(t *testing.T) {
+	mapperDocument, err := xmlparser.ParseMapper(strings.NewReader(`
+<mapper namespace="example.UserMapper">
+    <select id="List">select <alias type="models.User" table="u" prefix="user_"/> from users u</select>
+</mapper>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement := mapperDocument.Statements[0]
+	alias, ok := statement.Nodes[1].(parser.AliasNode)
+	if !ok || alias.Type != "models.User" || alias.Table != "u" || alias.Prefix != "user_" {
+		t.Fatalf("unexpected alias node: %#v", statement.Nodes[1])
+	}
+
+	_, err = xmlparser.ParseMapper(strings.NewReader(`
+<mapper namespace="example.UserMapper">
+    <select id="List">select <alias table="u"/> from users u</select>
+</mapper>`))
+	if err == nil || !strings.Contains(err.Error(), "attribute \"type\" is required") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParseMapperValues(t *testing.T) {
+	mapperDocument, err := xmlparser.ParseMapper(strings.NewReader(`
+<mapper namespace="example.UserMapper">
+    <insert id="Save">insert into users <values collection="users" item="user" columns="name, createdAt:created_at"/></insert>
+</mapper>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement := mapperDocument.Statements[0]
+	values, ok := statement.Nodes[1].(parser.ValuesNode)
+	if !ok || values.Collection != "users" || values.Item != "user" || values.Columns != "name, createdAt:created_at" {
+		t.Fatalf("unexpected values node: %#v", statement.Nodes[1])
+	}
+
+	_, err = xmlparser.ParseMapper(strings.NewReader(`
+<mapper namespace="example.UserMapper">
+    <insert id="Save">insert into users <values collection="users"/></insert>
+</mapper>`))
+	if err == nil || !strings.Contains(err.Error(), "attribute \"columns\" is required") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParseMapperColumns(t *testing.T) {
+	mapperDocument, err := xmlparser.ParseMapper(strings.NewReader(`
+<mapper namespace="example.UserMapper">
+    <select id="List">select <columns param="fields" allowed="id, name, createdAt:created_at" default="id"/> from users</select>
+</mapper>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement := mapperDocument.Statements[0]
+	columns, ok := statement.Nodes[1].(parser.ColumnsNode)
+	if !ok || columns.Param != "fields" || columns.Allowed != "id, name, createdAt:created_at" || columns.Default != "id" {
+		t.Fatalf("unexpected columns node: %#v", statement.Nodes[1])
+	}
+
+	_, err = xmlparser.ParseMapper(strings.NewReader(`
+<mapper namespace="example.UserMapper">
+    <select id="List">select <columns param="fields"/> from users</select>
+</mapper>`))
+	if err == nil || !strings.Contains(err.Error(), "attribute \"allowed\" is required") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParseMapperWith(t *testing.T) {
+	mapperDocument, err := xmlparser.ParseMapper(strings.NewReader(`
+<mapper namespace="example.UserMapper">
+    <select id="List">
+        <with recursive="true">
+            <cte name="recent">select id from users where <if test="active">active = 1</if></cte>
+            <cte name="ids">select id from recent</cte>
+        </with>
+        select id from ids
+    </select>
+</mapper>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement := mapperDocument.Statements[0]
+	with, ok := statement.Nodes[0].(parser.WithNode)
+	if !ok || with.Recursive != "true" || len(with.CTEs) != 2 {
+		t.Fatalf("unexpected with node: %#v", statement.Nodes[0])
+	}
+	if with.CTEs[0].Name != "recent" || len(with.CTEs[0].Children) != 2 || with.CTEs[1].Name != "ids" {
+		t.Fatalf("unexpected ctes: %#v", with.CTEs)
+	}
+
+	for _, body := range []string{`<with>select 1</with>`, `<with><if test="true">x</if></with>`, `<with><cte>select 1</cte></with>`, `<with></with>`} {
+		_, err = xmlparser.ParseMapper(strings.NewReader(`<mapper namespace="example.UserMapper"><select id="List">` + body + `</select></mapper>`))
+		if err == nil {
+			t.Errorf("expected an error for %s", body)
+		}
+	}
 }
 
 func TestParseMapperRejectsMissingStatementID(t *testing.T) {
@@ -228,3 +339,134 @@ func TestParseMapperPreservesTrimText(t *testing.T) {
 		t.Fatalf("unexpected trim text: %#v", trim.Children[0])
 	}
 }
+
+func TestParserParseFileLoadsPatternConcurrently(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`<configuration><mappers pattern="mappers/*.xml"/></configuration>`)},
+	}
+	var namespaces []string
+	for i := range 40 {
+		namespace := fmt.Sprintf("m%02d", i)
+		namespaces = append(namespaces, namespace)
+		fsys["mappers/"+namespace+".xml"] = &fstest.MapFile{
+			Data: []byte(`<mapper namespace="` + namespace + `"><select id="One">select 1</select></mapper>`),
+		}
+	}
+
+	for _, concurrency := range []int{0, 1, 8} {
+		document, err := (&xmlparser.Parser{FS: fsys, Concurrency: concurrency}).ParseFile("juice.xml")
+		if err != nil {
+			t.Fatalf("concurrency %d: %v", concurrency, err)
+		}
+		if len(document.Mappers) != len(namespaces) {
+			t.Fatalf("concurrency %d: expected %d mappers, got %d", concurrency, len(namespaces), len(document.Mappers))
+		}
+		for i, mapper := range document.Mappers {
+			if mapper.Namespace != namespaces[i] {
+				t.Fatalf("concurrency %d: expected mapper %d to be %s, got %s", concurrency, i, namespaces[i], mapper.Namespace)
+			}
+		}
+	}
+
+	// the reported error is the one of the first failing file in match order.
+	fsys["mappers/m05.xml"] = &fstest.MapFile{Data: []byte(`<mapper namespace="m05"><select`)}
+	fsys["mappers/m30.xml"] = &fstest.MapFile{Data: []byte(`<mapper namespace="m30"><select`)}
+	for range 5 {
+		_, err := (&xmlparser.Parser{FS: fsys, Concurrency: 8}).ParseFile("juice.xml")
+		if err == nil || !strings.Contains(err.Error(), "mappers/m05.xml") {
+			t.Fatalf("expected the error of mappers/m05.xml, got %v", err)
+		}
+	}
+}
+
+func TestParserParseFileLazyStatements(t *testing.T) {
+	mapper := `<mapper namespace="user">
+	<select id="Find">SELECT * FROM user <where><if test="id > 0">id = #{id}</if></where></select>
+	<delete id="Clear"/>
+</mapper>`
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`
+<configuration>
+    <mappers>
+        <mapper resource="user.xml"/>
+		<mapper namespace="inline"><select id="One">select 1</select></mapper>
+    </mappers>
+    <settings>
+        <setting name="lazyStatements" value="true"/>
+    </settings>
+</configuration>`)},
+		"user.xml": {Data: []byte(mapper)},
+	}
+	document, err := (&xmlparser.Parser{FS: fsys}).ParseFile("juice.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lazy := document.Mappers[0].Statements
+	if len(lazy) != 2 || lazy[0].LoadNodes == nil || lazy[0].Nodes != nil {
+		t.Fatalf("expected lazy statements, got %#v", lazy)
+	}
+	if inline := document.Mappers[1].Statements[0]; inline.LoadNodes != nil || len(inline.Nodes) != 1 {
+		t.Fatalf("expected the inline mapper to be parsed eagerly, got %#v", inline)
+	}
+
+	eager, err := xmlparser.ParseMapper(strings.NewReader(mapper))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, statement := range lazy {
+		nodes, err := statement.LoadNodes()
+		if err != nil {
+			t.Fatalf("%s: %v", statement.ID, err)
+		}
+		if !reflect.DeepEqual(nodes, eager.Statements[i].Nodes) {
+			t.Fatalf("%s: expected %#v, got %#v", statement.ID, eager.Statements[i].Nodes, nodes)
+		}
+	}
+
+	// errors of the body are reported when it is loaded.
+	fsys["user.xml"] = &fstest.MapFile{Data: []byte(`<mapper namespace="user"><select id="Find"><unknown/></select></mapper>`)}
+	document, err = (&xmlparser.Parser{FS: fsys}).ParseFile("juice.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parseErr *xmlparser.ParseError
+	if _, err = document.Mappers[0].Statements[0].LoadNodes(); !errors.As(err, &parseErr) || parseErr.Element != "unknown" {
+		t.Fatalf("expected a parse error of <unknown>, got %v", err)
+	}
+}
+
+func TestParseMapperResultMap(t *testing.T) {
+	mapperDocument, err := xmlparser.ParseMapper(strings.NewReader(`
+<mapper namespace="example.PaymentMapper">
+    <resultMap id="payment">
+        <discriminator column="type">
+            <case value="1" type="models.Card"/>
+            <case value="2" type="models.BankAccount"/>
+        </discriminator>
+    </resultMap>
+    <select id="List" resultMap="payment">select * from payments</select>
+</mapper>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []parser.ResultMap{{ID: "payment", Discriminator: parser.Discriminator{Column: "type", Cases: []parser.DiscriminatorCase{
+		{Value: "1", Type: "models.Card"},
+		{Value: "2", Type: "models.BankAccount"},
+	}}}}
+	if !reflect.DeepEqual(mapperDocument.ResultMaps, want) {
+		t.Fatalf("unexpected resultMaps: %#v", mapperDocument.ResultMaps)
+	}
+
+	for source, message := range map[string]string{
+		`<resultMap id="payment"/>`: "element <discriminator> is required",
+		`<resultMap id="payment"><result column="id" property="ID"/></resultMap>`:                            "expected <discriminator>",
+		`<resultMap id="payment"><discriminator column="type"><case value="1"/></discriminator></resultMap>`: "attribute \"type\" is required",
+		`<resultMap id="payment"><discriminator column="type"><case value="1" type="models.Card"/></discriminator></resultMap>
+         <resultMap id="payment"><discriminator column="type"><case value="1" type="models.Card"/></discriminator></resultMap>`: "duplicate resultMap id",
+	} {
+		_, err = xmlparser.ParseMapper(strings.NewReader(`<mapper namespace="example.PaymentMapper">` + source + `</mapper>`))
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("expected an error containing %q, got %v", message, err)
+		}
+	}
+}
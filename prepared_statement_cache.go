@@ -0,0 +1,125 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	stdsql "database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/go-juicedev/juice/internal/container"
+	"github.com/go-juicedev/juice/session"
+)
+
+// preparedStatementCacheSizeSetting is the setting configuring how many prepared statements
+// a transaction keeps open. Every statement of a transaction is prepared once and reused
+// for the lifetime of the transaction, unless the setting is zero or negative.
+const preparedStatementCacheSizeSetting = "preparedStatementCacheSize"
+
+// defaultTxPreparedStatementCacheSize is the capacity of the statement cache of a transaction
+// when the preparedStatementCacheSize setting is not set, so that the statements repeated
+// in a loop inside a transaction reuse their prepared statement by default.
+// A statement run once in a transaction costs an extra prepare round trip;
+// set preparedStatementCacheSize to 0 to execute the statements directly instead.
+const defaultTxPreparedStatementCacheSize = 16
+
+// defaultPreparedStatementCacheSize is the capacity of the statement cache of a batch
+// when none is configured. A batch needs at most two statements:
+// one for the full batches and one for the remaining rows.
+const defaultPreparedStatementCacheSize = 2
+
+// preparedStatementCache is an LRU cache of the prepared statements of a session, keyed by SQL text.
+// Statements are closed when they are evicted and when the cache is closed.
+type preparedStatementCache struct {
+	// mu serializes the preparation of statements,
+	// so that a query is never prepared twice and leaked.
+	mu sync.Mutex
+
+	session    session.Session
+	statements *container.LRU[string, *stdsql.Stmt]
+}
+
+// prepare returns the prepared statement of query, preparing it on the session on a cache miss.
+func (c *preparedStatementCache) prepare(ctx context.Context, query string) (*stdsql.Stmt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if stmt, ok := c.statements.Get(query); ok {
+		return stmt, nil
+	}
+	stmt, err := c.session.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("prepare statement failed: %w", err)
+	}
+	c.statements.Set(query, stmt)
+	return stmt, nil
+}
+
+// Close closes all the cached statements and empties the cache.
+// Multiple errors are joined together.
+func (c *preparedStatementCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var errs []error
+	c.statements.Range(func(_ string, stmt *stdsql.Stmt) bool {
+		if err := stmt.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		return true
+	})
+	c.statements.Purge()
+	return errors.Join(errs...)
+}
+
+// newPreparedStatementCache creates a cache holding at most capacity statements of session.
+func newPreparedStatementCache(session session.Session, capacity int) *preparedStatementCache {
+	return &preparedStatementCache{
+		session: session,
+		statements: container.NewLRU(capacity, func(_ string, stmt *stdsql.Stmt) {
+			_ = stmt.Close()
+		}),
+	}
+}
+
+// preparedStatementCacheSize returns the preparedStatementCacheSize setting of engine,
+// or zero when it is not set or not a positive integer.
+func preparedStatementCacheSize(engine *Engine) int {
+	configuration := engine.GetConfiguration()
+	if configuration == nil {
+		return 0
+	}
+	size := configuration.Settings().Get(preparedStatementCacheSizeSetting).Int64()
+	if size <= 0 {
+		return 0
+	}
+	return int(size)
+}
+
+// txPreparedStatementCacheSize returns the capacity of the statement cache of a transaction of engine:
+// the preparedStatementCacheSize setting, or defaultTxPreparedStatementCacheSize when it is not set.
+// Zero disables the cache.
+func txPreparedStatementCacheSize(engine *Engine) int {
+	configuration := engine.GetConfiguration()
+	if configuration == nil {
+		return 0
+	}
+	if configuration.Settings().Get(preparedStatementCacheSizeSetting) == "" {
+		return defaultTxPreparedStatementCacheSize
+	}
+	return preparedStatementCacheSize(engine)
+}
@@ -0,0 +1,165 @@
+package juice
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPreparedStatementCache_prepared_statement_cache_test(t *testing.T) {
+	state := &shSQLDriverState{}
+	db := openStatementTestDB(t, state)
+	cache := newPreparedStatementCache(db, 1)
+	ctx := context.Background()
+
+	first, err := cache.prepare(ctx, "SELECT 1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again, err := cache.prepare(ctx, "SELECT 1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first != again || state.prepareCalls != 1 {
+		t.Fatalf("expected the cached statement to be reused, got %d prepares", state.prepareCalls)
+	}
+
+	if _, err = cache.prepare(ctx, "SELECT 2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state.prepareCalls != 2 || state.stmtCloseCalls != 1 {
+		t.Fatalf("expected the evicted statement to be closed, got %d prepares and %d closes", state.prepareCalls, state.stmtCloseCalls)
+	}
+
+	if err = cache.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if state.stmtCloseCalls != 2 || cache.statements.Len() != 0 {
+		t.Fatalf("expected all statements closed, got %d closes", state.stmtCloseCalls)
+	}
+}
+
+func TestPreparedStatementCacheTx_prepared_statement_cache_test(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<settings>
+		<setting name="preparedStatementCacheSize" value="2"/>
+	</settings>
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>sqlite.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>
+		<mapper namespace="user">
+			<select id="Find">SELECT * FROM user</select>
+			<select id="Count">SELECT COUNT(*) FROM user</select>
+			<update id="Touch">UPDATE user SET updated_at = 1</update>
+		</mapper>
+	</mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	state := &shSQLDriverState{}
+	db := openStatementTestDB(t, state)
+	engine := newStatementTestEngine(db)
+	engine.db, engine.configuration = db, configuration
+
+	txManager := engine.ContextTx(context.Background(), nil)
+	if err = txManager.Begin(); err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	query := func(id string) {
+		t.Helper()
+		rows, err := txManager.Object(id).QueryContext(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("unexpected query error: %v", err)
+		}
+		_ = rows.Close()
+	}
+	query("user.Find")
+	query("user.Find")
+	query("user.Count")
+	query("user.Find")
+	if state.prepareCalls != 2 || state.stmtQueryCalls != 4 || state.connQueryCalls != 0 {
+		t.Fatalf("expected 2 prepares and 4 statement queries, got %d and %d", state.prepareCalls, state.stmtQueryCalls)
+	}
+
+	// user.Count is the least recently used statement and gets evicted.
+	if _, err = txManager.Object("user.Touch").ExecContext(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected exec error: %v", err)
+	}
+	if state.prepareCalls != 3 || state.stmtCloseCalls != 1 {
+		t.Fatalf("expected one eviction, got %d prepares and %d closes", state.prepareCalls, state.stmtCloseCalls)
+	}
+
+	if err = txManager.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if state.stmtCloseCalls != 3 {
+		t.Fatalf("expected the cached statements closed with the transaction, got %d closes", state.stmtCloseCalls)
+	}
+}
+
+func TestPreparedStatementCacheTxDefault_prepared_statement_cache_test(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		settings string
+		prepares int
+	}{
+		{name: "default", prepares: 1},
+		{name: "disabled", settings: `<settings><setting name="preparedStatementCacheSize" value="0"/></settings>`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>` + tt.settings + `
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>sqlite.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>
+		<mapper namespace="user">
+			<update id="Touch">UPDATE user SET updated_at = 1</update>
+		</mapper>
+	</mappers>
+</configuration>`)},
+			}
+			configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			state := &shSQLDriverState{}
+			db := openStatementTestDB(t, state)
+			engine := newStatementTestEngine(db)
+			engine.db, engine.configuration = db, configuration
+
+			txManager := engine.ContextTx(context.Background(), nil)
+			if err = txManager.Begin(); err != nil {
+				t.Fatalf("Begin() error = %v", err)
+			}
+			for range 3 {
+				if _, err = txManager.Object("user.Touch").ExecContext(context.Background(), nil); err != nil {
+					t.Fatalf("unexpected exec error: %v", err)
+				}
+			}
+			if err = txManager.Commit(); err != nil {
+				t.Fatalf("Commit() error = %v", err)
+			}
+			if state.prepareCalls != tt.prepares || state.stmtCloseCalls != tt.prepares {
+				t.Fatalf("expected %d prepares closed with the transaction, got %d prepares and %d closes",
+					tt.prepares, state.prepareCalls, state.stmtCloseCalls)
+			}
+			if tt.prepares == 0 && state.connExecCalls != 3 {
+				t.Fatalf("expected 3 direct executions, got %d", state.connExecCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/go-juicedev/juice/sql"
+)
+
+// ErrQueryCanceled is returned when a statement fails because its context is canceled
+// or its deadline is exceeded, wrapping both the context error and the error of the driver.
+var ErrQueryCanceled = errors.New("juice: query canceled")
+
+// RowsHook observes the rows returned by the queries of an engine, for example to detect
+// in tests the rows which are never closed and keep their connection out of the pool.
+type RowsHook interface {
+	// RowsOpened is called when a query returns rows, or when they advance to another result set.
+	RowsOpened(ctx context.Context, statement Statement, rows sql.Rows)
+
+	// RowsReleased is called once the rows are closed, or once Next reports no more rows.
+	RowsReleased(ctx context.Context, statement Statement, rows sql.Rows)
+}
+
+// queryError returns err wrapped with ErrQueryCanceled if ctx is done, or err itself.
+func queryError(ctx context.Context, err error) error {
+	if err == nil || errors.Is(err, ErrQueryCanceled) {
+		return err
+	}
+	ctxErr := ctx.Err()
+	if ctxErr == nil {
+		return err
+	}
+	if errors.Is(err, ctxErr) {
+		return fmt.Errorf("%w: %w", ErrQueryCanceled, err)
+	}
+	return fmt.Errorf("%w: %w: %w", ErrQueryCanceled, ctxErr, err)
+}
+
+// closeRows closes the rows returned together with an error, unless they are nil,
+// like the nil *sql.Rows returned by database/sql on failure.
+func closeRows(rows sql.Rows) {
+	if rows == nil {
+		return
+	}
+	if value := reflect.ValueOf(rows); value.Kind() == reflect.Pointer && value.IsNil() {
+		return
+	}
+	_ = rows.Close()
+}
+
+// withQueryGuard wraps next so that its errors wrap ErrQueryCanceled once ctx is done,
+// that the rows returned with an error are closed, and that the rows are reported to
+// the RowsHook of the engine.
+func withQueryGuard(statement Statement, engine *Engine, next QueryHandler) QueryHandler {
+	return func(ctx context.Context, query string, args ...any) (sql.Rows, error) {
+		rows, err := next(ctx, query, args...)
+		if err != nil {
+			closeRows(rows)
+			return nil, queryError(ctx, err)
+		}
+		guarded := &guardedRows{Rows: rows, ctx: ctx, statement: statement, hook: engine.rowsHook}
+		guarded.leak = trackLeak(engine, guarded, fmt.Sprintf("rows of statement %q", statement.Name()), "were not closed")
+		guarded.open()
+		if resultSets, ok := rows.(sql.ResultSets); ok {
+			return &guardedResultSets{guardedRows: guarded, resultSets: resultSets}, nil
+		}
+		return guarded, nil
+	}
+}
+
+// guardedRows is the sql.Rows returned by withQueryGuard.
+type guardedRows struct {
+	sql.Rows
+	ctx       context.Context
+	statement Statement
+	hook      RowsHook
+	released  bool
+
+	// leak tracks the rows when the leakDetection setting is enabled.
+	leak *leakTracker
+}
+
+// open reports the rows to the hook as opened.
+func (g *guardedRows) open() {
+	g.released = false
+	g.leak.markOpened()
+	if g.hook != nil {
+		g.hook.RowsOpened(g.ctx, g.statement, g)
+	}
+}
+
+// release reports the rows to the hook as released, once.
+func (g *guardedRows) release() {
+	if g.released {
+		return
+	}
+	g.released = true
+	g.leak.markReleased()
+	if g.hook != nil {
+		g.hook.RowsReleased(g.ctx, g.statement, g)
+	}
+}
+
+// Next implements sql.Rows.
+func (g *guardedRows) Next() bool {
+	if g.Rows.Next() {
+		return true
+	}
+	g.release()
+	return false
+}
+
+// Scan implements sql.Rows.
+func (g *guardedRows) Scan(dest ...any) error {
+	return queryError(g.ctx, g.Rows.Scan(dest...))
+}
+
+// Err implements sql.Rows.
+func (g *guardedRows) Err() error {
+	return queryError(g.ctx, g.Rows.Err())
+}
+
+// Close implements sql.Rows.
+func (g *guardedRows) Close() error {
+	err := g.Rows.Close()
+	g.release()
+	return err
+}
+
+// guardedResultSets is the sql.ResultSets returned by withQueryGuard for sql.ResultSets.
+type guardedResultSets struct {
+	*guardedRows
+	resultSets sql.ResultSets
+}
+
+// NextResultSet implements sql.ResultSets.
+func (g *guardedResultSets) NextResultSet() bool {
+	if !g.resultSets.NextResultSet() {
+		return false
+	}
+	g.open()
+	return true
+}
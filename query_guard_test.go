@@ -0,0 +1,98 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+type queryGuardRows struct {
+	*jsql.RowsBuffer
+	err    error
+	closed bool
+}
+
+func (r *queryGuardRows) Err() error { return r.err }
+
+func (r *queryGuardRows) Close() error {
+	r.closed = true
+	return r.RowsBuffer.Close()
+}
+
+func TestQueryGuard_query_guard_test(t *testing.T) {
+	driverErr := errors.New("driver: connection reset")
+	failure := errors.New("middleware failure")
+	rows := &queryGuardRows{RowsBuffer: jsql.NewRowsBuffer([]string{"id"}, [][]any{{1}})}
+	engine := newStatementTestEngine(nil)
+	handler := func(err error) *executeStatementHandler {
+		return newExecuteStatementHandler("SELECT id FROM user", nil, engine, nil).withQueryHandler(
+			func(context.Context, string, ...any) (jsql.Rows, error) { return rows, err },
+		).withExecHandler(
+			func(context.Context, string, ...any) (jsql.Result, error) { return nil, err },
+		)
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := handler(driverErr).QueryContext(canceled, shStatement{}, nil); !errors.Is(err, ErrQueryCanceled) ||
+		!errors.Is(err, context.Canceled) || !errors.Is(err, driverErr) {
+		t.Fatalf("expected ErrQueryCanceled wrapping the errors, got %v", err)
+	}
+	if !rows.closed {
+		t.Fatal("expected the rows returned with an error to be closed")
+	}
+	if _, err := handler(driverErr).ExecContext(canceled, shStatement{}, nil); !errors.Is(err, ErrQueryCanceled) {
+		t.Fatalf("expected ErrQueryCanceled, got %v", err)
+	}
+	var execErr *ExecError
+	if _, err := handler(failure).ExecContext(context.Background(), shStatement{}, nil); errors.Is(err, ErrQueryCanceled) ||
+		!errors.As(err, &execErr) || execErr.Err != failure {
+		t.Fatalf("expected the error as it is without cancellation, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rows = &queryGuardRows{RowsBuffer: jsql.NewRowsBuffer([]string{"id"}, [][]any{{1}, {2}})}
+	guarded, err := handler(nil).QueryContext(ctx, shStatement{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cancel()
+	rows.err = context.Canceled
+	if _, err = jsql.List[int](guarded); !errors.Is(err, ErrQueryCanceled) {
+		t.Fatalf("expected a cancellation during the iteration to wrap ErrQueryCanceled, got %v", err)
+	}
+}
+
+type queryGuardHook struct {
+	opened, released int
+}
+
+func (h *queryGuardHook) RowsOpened(context.Context, Statement, jsql.Rows)   { h.opened++ }
+func (h *queryGuardHook) RowsReleased(context.Context, Statement, jsql.Rows) { h.released++ }
+
+func TestRowsHook_query_guard_test(t *testing.T) {
+	hook := &queryGuardHook{}
+	engine := newStatementTestEngine(nil)
+	engine.SetRowsHook(hook)
+	handler := newExecuteStatementHandler("SELECT id FROM user", nil, engine, nil).withQueryHandler(
+		func(context.Context, string, ...any) (jsql.Rows, error) {
+			return jsql.NewRowsBuffer([]string{"id"}, [][]any{{1}}), nil
+		},
+	)
+
+	rows, err := handler.QueryContext(context.Background(), shStatement{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hook.opened != 1 || hook.released != 0 {
+		t.Fatalf("expected opened rows, got %+v", hook)
+	}
+	for rows.Next() {
+	}
+	_ = rows.Close()
+	if hook.released != 1 {
+		t.Fatalf("expected the rows to be released once, got %+v", hook)
+	}
+}
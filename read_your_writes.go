@@ -0,0 +1,63 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// writeTracker records the last write executed with a context.
+type writeTracker struct {
+	// lastWrite is the unix nano time of the last write, zero if there is none.
+	lastWrite atomic.Int64
+}
+
+// markWrite records a write executed at now.
+func (w *writeTracker) markWrite(now time.Time) {
+	w.lastWrite.Store(now.UnixNano())
+}
+
+// sticky reports whether a read at now must stay on the primary datasource.
+// A window less than or equal to zero keeps the reads on the primary for the lifetime of the context.
+func (w *writeTracker) sticky(window time.Duration, now time.Time) bool {
+	lastWrite := w.lastWrite.Load()
+	if lastWrite == 0 {
+		return false
+	}
+	return window <= 0 || now.Sub(time.Unix(0, lastWrite)) < window
+}
+
+type writeTrackerKey struct{}
+
+// ContextWithReadYourWrites returns a context tracking the writes executed with it.
+// After a write, TxSensitiveDataSourceSwitchMiddleware keeps the reads executed with the context
+// on the primary datasource instead of routing them to a replica, so that they observe the write.
+// It is usually called once per request; contexts derived from it share the same tracking.
+func ContextWithReadYourWrites(ctx context.Context) context.Context {
+	if _, ok := writeTrackerFromContext(ctx); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, writeTrackerKey{}, &writeTracker{})
+}
+
+// writeTrackerFromContext returns the writeTracker installed by ContextWithReadYourWrites.
+func writeTrackerFromContext(ctx context.Context) (*writeTracker, bool) {
+	tracker, ok := ctx.Value(writeTrackerKey{}).(*writeTracker)
+	return tracker, ok
+}
@@ -0,0 +1,93 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+func TestWriteTracker_read_your_writes_test(t *testing.T) {
+	var tracker writeTracker
+	now := time.Now()
+	if tracker.sticky(0, now) {
+		t.Fatal("expected no stickiness before a write")
+	}
+	tracker.markWrite(now)
+	if !tracker.sticky(0, now.Add(time.Hour)) {
+		t.Fatal("expected stickiness for the lifetime of the context")
+	}
+	if !tracker.sticky(time.Second, now.Add(time.Millisecond)) || tracker.sticky(time.Second, now.Add(time.Second)) {
+		t.Fatal("expected stickiness within the window only")
+	}
+
+	ctx := ContextWithReadYourWrites(context.Background())
+	first, _ := writeTrackerFromContext(ctx)
+	second, _ := writeTrackerFromContext(ContextWithReadYourWrites(ctx))
+	if first == nil || first != second {
+		t.Fatal("expected derived contexts to share the tracker")
+	}
+}
+
+func TestReadYourWritesMiddleware_read_your_writes_test(t *testing.T) {
+	engine := newStatementTestEngine(nil)
+	engine.manager = &DBManager{}
+	engine.configuration.(*xmlConfiguration).settings["selectDataSource"] = "replica"
+	middleware := &TxSensitiveDataSourceSwitchMiddleware{}
+
+	read := func(ctx context.Context, stmt Statement) error {
+		handler := middleware.QueryContext(newStatementContext(ctx, engine, stmt, nil, nil), func(context.Context, string, ...any) (jsql.Rows, error) {
+			return nil, nil
+		})
+		_, err := handler(ctx, "SELECT 1")
+		return err
+	}
+	write := func(ctx context.Context, err error) error {
+		handler := middleware.ExecContext(newStatementContext(ctx, engine, shStatement{action: jsql.Update}, nil, nil), func(context.Context, string, ...any) (jsql.Result, error) {
+			return nil, err
+		})
+		_, err = handler(ctx, "UPDATE t SET a = 1")
+		return err
+	}
+	selectStmt := shStatement{action: jsql.Select}
+
+	// the replica is not registered, so switching to it fails.
+	ctx := ContextWithReadYourWrites(context.Background())
+	if err := read(ctx, selectStmt); err == nil {
+		t.Fatal("expected the read to be routed to the replica")
+	}
+	errWrite := errors.New("write")
+	if err := write(ctx, errWrite); !errors.Is(err, errWrite) {
+		t.Fatalf("expected errWrite, got %v", err)
+	}
+	if err := read(ctx, selectStmt); err == nil {
+		t.Fatal("expected failed writes to be ignored")
+	}
+	if err := write(ctx, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := read(ctx, selectStmt); err != nil {
+		t.Fatalf("expected the read to stay on the primary, got %v", err)
+	}
+
+	// explicitly named datasources are still honored.
+	if err := read(ctx, shStatement{action: jsql.Select, attrs: map[string]string{"dataSource": "archive"}}); err == nil {
+		t.Fatal("expected the read to be routed to the named datasource")
+	}
+
+	// contexts without tracking are routed as before.
+	if err := write(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := read(context.Background(), selectStmt); err == nil {
+		t.Fatal("expected the read to be routed to the replica")
+	}
+
+	middleware.StickyWindow = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	if err := read(ctx, selectStmt); err == nil {
+		t.Fatal("expected the read to be routed to the replica after the window")
+	}
+}
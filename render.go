@@ -0,0 +1,40 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+)
+
+// RenderStatement builds the statement v of configuration with param as the engines using drv
+// would, without connecting to a database, so the mappers can be tested on their own.
+// The middlewares are not applied, and the features configured on an engine, like
+// its sharding strategies or node interceptors, are not available.
+func RenderStatement(ctx context.Context, configuration Configuration, drv driver.Driver, v any, param eval.Param) (query string, args []any, err error) {
+	engine := &Engine{configuration: configuration, driver: drv}
+	statement, err := configuration.GetStatement(v)
+	if err != nil {
+		return "", nil, err
+	}
+	if statement, err = resolveDatabaseStatement(statement, engine); err != nil {
+		return "", nil, err
+	}
+	return buildStatementQuery(ctx, statement, engine, param)
+}
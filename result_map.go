@@ -0,0 +1,42 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"fmt"
+)
+
+// resultMapAttribute is the statement attribute naming the <resultMap> mapping its rows.
+// A resultMap of another mapper is named with its namespace, like "main.UserMapper.payment".
+const resultMapAttribute = "resultMap"
+
+// checkResultMaps returns an error if a statement of mappers names a resultMap which is not declared.
+// The resultMaps can be declared by any mapper, so they are checked once every mapper is compiled.
+func checkResultMaps(mappers []*Mapper) error {
+	for _, mapper := range mappers {
+		for _, statement := range mapper.statements {
+			id := statement.attrs[resultMapAttribute]
+			if id == "" {
+				continue
+			}
+			if _, err := mapper.GetResultMapByID(id); err != nil {
+				return fmt.Errorf("statement %s: %w", statement.Name(), err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+
+	"github.com/go-juicedev/juice/eval"
+	"github.com/go-juicedev/juice/sql"
+)
+
+// ExecReturning executes a data-modifying statement that returns rows, such as
+// PostgreSQL's INSERT ... RETURNING, and maps the returned rows into a slice of T.
+//
+// The statement is executed through QueryContext, so the returned sql.Result is
+// emulated: RowsAffected reports the number of returned rows, and LastInsertId
+// returns sql.ErrLastInsertIdNotSupported.
+//
+// Example:
+//
+//	users, result, err := juice.ExecReturning[User](ctx, engine.Object("InsertUsers"), users)
+func ExecReturning[T any](ctx context.Context, executor SQLRowsExecutor, param eval.Param) ([]T, sql.Result, error) {
+	rows, err := executor.QueryContext(ctx, param)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	result, err := sql.List[T](rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = afterScan(ctx, &result); err != nil {
+		return nil, nil, err
+	}
+	return result, sql.NewReturningResult(int64(len(result))), nil
+}
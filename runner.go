@@ -31,6 +31,11 @@ type Runner interface {
 	// Select executes a SELECT query and returns the result rows.
 	Select(ctx context.Context, param eval.Param) (sql.Rows, error)
 
+	// SelectMulti executes a query which may produce multiple result sets,
+	// such as a stored procedure call or a multi-statement query.
+	// Use ResultSets.NextResultSet to advance between result sets.
+	SelectMulti(ctx context.Context, param eval.Param) (sql.ResultSets, error)
+
 	// Insert executes an INSERT statement and returns the result.
 	// The result includes the last insert ID and number of rows affected.
 	Insert(ctx context.Context, param eval.Param) (sql.Result, error)
@@ -58,6 +63,12 @@ func (r *ErrorRunner) Select(_ context.Context, _ eval.Param) (sql.Rows, error)
 	return nil, r.error
 }
 
+// SelectMulti implements Runner.SelectMulti by returning the stored error.
+// It ignores the context and parameters, always returning nil for rows and the stored error.
+func (r *ErrorRunner) SelectMulti(_ context.Context, _ eval.Param) (sql.ResultSets, error) {
+	return nil, r.error
+}
+
 // Insert implements Runner.Insert by returning the stored error.
 // It ignores the context and parameters, always returning nil for result and the stored error.
 func (r *ErrorRunner) Insert(_ context.Context, _ eval.Param) (sql.Result, error) {
@@ -123,6 +134,22 @@ func (r *SQLRunner) Select(ctx context.Context, param eval.Param) (sql.Rows, err
 	return r.queryContext(ctx, param)
 }
 
+// SelectMulti executes a query which may produce multiple result sets.
+// It returns sql.ErrResultSetsNotSupported if the returned rows can not advance
+// to another result set.
+func (r *SQLRunner) SelectMulti(ctx context.Context, param eval.Param) (sql.ResultSets, error) {
+	rows, err := r.queryContext(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+	resultSets, err := sql.AsResultSets(rows)
+	if err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+	return resultSets, nil
+}
+
 // Insert executes an INSERT statement and returns the result.
 func (r *SQLRunner) Insert(ctx context.Context, param eval.Param) (sql.Result, error) {
 	return r.execContext(sql.Insert, ctx, param)
@@ -162,7 +189,10 @@ func (r *GenericRunner[T]) Bind(ctx context.Context, param eval.Param) (result T
 		return result, err
 	}
 	defer func() { _ = rows.Close() }()
-	return sql.Bind[T](rows)
+	if result, err = sql.Bind[T](rows); err != nil {
+		return result, err
+	}
+	return result, afterScan(ctx, &result)
 }
 
 // List binds the result of a SELECT query to a list of values of type T.
@@ -173,7 +203,10 @@ func (r *GenericRunner[T]) List(ctx context.Context, param eval.Param) (result [
 		return result, err
 	}
 	defer func() { _ = rows.Close() }()
-	return sql.List[T](rows)
+	if result, err = sql.List[T](rows); err != nil {
+		return result, err
+	}
+	return result, afterScan(ctx, &result)
 }
 
 // List2 binds the result of a SELECT query to a list of pointers to values of type T.
@@ -184,7 +217,10 @@ func (r *GenericRunner[T]) List2(ctx context.Context, param eval.Param) (result
 		return result, err
 	}
 	defer func() { _ = rows.Close() }()
-	return sql.List2[T](rows)
+	if result, err = sql.List2[T](rows); err != nil {
+		return result, err
+	}
+	return result, afterScan(ctx, &result)
 }
 
 // NewGenericRunner creates a new GenericRunner instance with the specified Runner.
@@ -45,6 +45,9 @@ func (s *sqlRowsExecutorStub) ExecContext(_ context.Context, _ eval.Param) (jsql
 
 func (s *sqlRowsExecutorStub) Statement() Statement { return s.stmt }
 func (s *sqlRowsExecutorStub) Driver() driver.Driver { return s.drv }
+func (s *sqlRowsExecutorStub) DryRun(_ context.Context, _ eval.Param) (*DryRunResult, error) {
+	return nil, ErrDryRunUnsupported
+}
 
 func TestErrorRunner_AllMethodsReturnSameError_runner_test(t *testing.T) {
 	want := errors.New("runner failed")
@@ -54,6 +57,10 @@ func TestErrorRunner_AllMethodsReturnSameError_runner_test(t *testing.T) {
 		t.Fatalf("select expected %v, got %v", want, err)
 	}
 
+	if _, err := r.SelectMulti(context.Background(), nil); !errors.Is(err, want) {
+		t.Fatalf("select multi expected %v, got %v", want, err)
+	}
+
 	if _, err := r.Insert(context.Background(), nil); !errors.Is(err, want) {
 		t.Fatalf("insert expected %v, got %v", want, err)
 	}
@@ -117,6 +124,14 @@ func (f runnerFunc) Select(ctx context.Context, param eval.Param) (jsql.Rows, er
 	return f(ctx, param)
 }
 
+func (f runnerFunc) SelectMulti(ctx context.Context, param eval.Param) (jsql.ResultSets, error) {
+	rows, err := f(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+	return jsql.AsResultSets(rows)
+}
+
 func (runnerFunc) Insert(_ context.Context, _ eval.Param) (jsql.Result, error) {
 	return resultStub{}, nil
 }
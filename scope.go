@@ -35,6 +35,7 @@ var ErrCommitOnSpecific = tx.ErrCommitOnSpecific
 // If the context does not carry an Engine, it will return ErrInvalidManager.
 // If the handler returns an error, the transaction is rolled back.
 // Otherwise, the transaction is committed.
+// The transaction starts with the default options of the environment, which opts override.
 // The ctx must should be created by ContextWithManager.
 // For example:
 //
@@ -65,6 +66,13 @@ func Transaction(ctx context.Context, handler func(ctx context.Context) error, o
 		return handler(ctx)
 	})
 
+	// the options of the environment are the defaults the given ones apply to.
+	if defaults := engine.defaultTxOptions(); defaults != nil {
+		opts = append([]tx.TransactionOptionFunc{
+			tx.WithIsolationLevel(defaults.Isolation),
+			tx.WithReadOnly(defaults.ReadOnly),
+		}, opts...)
+	}
 	return tx.AtomicContext(ctx, engine.DB(), handlerFunc, opts...)
 }
 
@@ -2,6 +2,8 @@ package juice
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"testing"
 
@@ -103,3 +105,44 @@ func TestTransactionUsesEngineFromContext_scope_test(t *testing.T) {
 		t.Fatalf("expected commit called twice, got %d", state.commitCalls)
 	}
 }
+
+func TestTransactionUsesEnvironmentTxOptions_scope_test(t *testing.T) {
+	state := &shSQLDriverState{}
+	engine := &Engine{
+		db:          openStatementTestDB(t, state),
+		environment: &Environment{TxOptions: &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}},
+	}
+	ctx := ContextWithManager(context.Background(), engine)
+
+	if err := Transaction(ctx, func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("unexpected Transaction error: %v", err)
+	}
+	if got := state.lastBeginOpts; got.Isolation != driver.IsolationLevel(sql.LevelSerializable) || !got.ReadOnly {
+		t.Fatalf("expected the environment options, got %+v", got)
+	}
+
+	// the given options override the defaults field by field
+	if err := Transaction(ctx, func(context.Context) error { return nil }, tx.WithReadOnly(false)); err != nil {
+		t.Fatalf("unexpected Transaction error: %v", err)
+	}
+	if got := state.lastBeginOpts; got.Isolation != driver.IsolationLevel(sql.LevelSerializable) || got.ReadOnly {
+		t.Fatalf("expected read-write serializable, got %+v", got)
+	}
+
+	// explicit options of ContextTx replace the defaults
+	manager := engine.ContextTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err := manager.Begin(); err != nil {
+		t.Fatalf("unexpected Begin error: %v", err)
+	}
+	_ = manager.Rollback()
+	if got := state.lastBeginOpts; got.Isolation != driver.IsolationLevel(sql.LevelReadCommitted) || got.ReadOnly {
+		t.Fatalf("expected read committed, got %+v", got)
+	}
+
+	if err := engine.InTx(ctx, nil, func(TxManager) error { return nil }); err != nil {
+		t.Fatalf("unexpected InTx error: %v", err)
+	}
+	if got := state.lastBeginOpts; got.Isolation != driver.IsolationLevel(sql.LevelSerializable) || !got.ReadOnly {
+		t.Fatalf("expected the environment options, got %+v", got)
+	}
+}
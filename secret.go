@@ -0,0 +1,112 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// SecretResolver resolves the reference of a secret, like a password, into its value.
+type SecretResolver interface {
+	ResolveSecret(ref string) (string, error)
+}
+
+// SecretResolverFunc is an adapter to allow the use of ordinary functions as SecretResolver.
+type SecretResolverFunc func(ref string) (string, error)
+
+// ResolveSecret implements SecretResolver.
+func (f SecretResolverFunc) ResolveSecret(ref string) (string, error) {
+	return f(ref)
+}
+
+var (
+	// secretResolvers is a map of secret resolvers keyed by scheme.
+	secretResolvers = map[string]SecretResolver{}
+
+	// secretResolverMu protects secretResolvers.
+	secretResolverMu sync.RWMutex
+)
+
+var (
+	// ErrSecretResolverNotFound is returned when the scheme of a secret reference has no resolver.
+	ErrSecretResolverNotFound = errors.New("juice: secret resolver not found")
+
+	// ErrSecretNotFound is returned when a secret reference can not be resolved.
+	ErrSecretNotFound = errors.New("juice: secret not found")
+
+	errSecretResolverSchemeEmpty = errors.New("juice: secret resolver scheme is empty")
+	errSecretResolverNil         = errors.New("juice: secret resolver is nil")
+)
+
+// RegisterSecretResolver registers the resolver of the secret references with the given scheme,
+// which are written as scheme:ref, for example vault:db/password.
+// It allows to override the builtin env and file resolvers.
+func RegisterSecretResolver(scheme string, resolver SecretResolver) error {
+	if len(scheme) == 0 {
+		return errSecretResolverSchemeEmpty
+	}
+	if resolver == nil {
+		return errSecretResolverNil
+	}
+	secretResolverMu.Lock()
+	defer secretResolverMu.Unlock()
+	secretResolvers[scheme] = resolver
+	return nil
+}
+
+// resolveSecret resolves a secret reference written as scheme:ref.
+func resolveSecret(reference string) (string, error) {
+	scheme, ref, ok := strings.Cut(reference, ":")
+	if !ok {
+		return "", fmt.Errorf("%w: invalid secret reference %q, expected scheme:ref", ErrSecretResolverNotFound, reference)
+	}
+	secretResolverMu.RLock()
+	resolver, exists := secretResolvers[scheme]
+	secretResolverMu.RUnlock()
+	if !exists {
+		return "", fmt.Errorf("%w: %s", ErrSecretResolverNotFound, scheme)
+	}
+	return resolver.ResolveSecret(ref)
+}
+
+// envSecretResolver resolves a secret from the environment variable named by the reference.
+func envSecretResolver(ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("%w: environment variable %s", ErrSecretNotFound, ref)
+	}
+	return value, nil
+}
+
+// fileSecretResolver resolves a secret from the content of the file at the reference path,
+// without its trailing newline, like the secrets mounted by container orchestrators.
+func fileSecretResolver(ref string) (string, error) {
+	content, err := os.ReadFile(ref)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrSecretNotFound, err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+func init() {
+	_ = RegisterSecretResolver("env", SecretResolverFunc(envSecretResolver))
+	_ = RegisterSecretResolver("file", SecretResolverFunc(fileSecretResolver))
+}
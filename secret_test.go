@@ -0,0 +1,92 @@
+package juice
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestResolveSecret_secret_test(t *testing.T) {
+	t.Setenv("JUICE_SECRET_TEST", "from-env")
+	path := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterSecretResolver("upper", SecretResolverFunc(func(ref string) (string, error) {
+		return strings.ToUpper(ref), nil
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	for reference, want := range map[string]string{
+		"env:JUICE_SECRET_TEST": "from-env",
+		"file:" + path:          "from-file",
+		"upper:secret":          "SECRET",
+	} {
+		if got, err := resolveSecret(reference); err != nil || got != want {
+			t.Errorf("resolveSecret(%q) = %q, %v, want %q", reference, got, err, want)
+		}
+	}
+
+	if _, err := resolveSecret("env:JUICE_SECRET_TEST_MISSING"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("expected ErrSecretNotFound, got %v", err)
+	}
+	for _, reference := range []string{"vault:db", "plain"} {
+		if _, err := resolveSecret(reference); !errors.Is(err, ErrSecretResolverNotFound) {
+			t.Errorf("resolveSecret(%q): expected ErrSecretResolverNotFound, got %v", reference, err)
+		}
+	}
+	if err := RegisterSecretResolver("", SecretResolverFunc(nil)); err == nil {
+		t.Error("expected an error for an empty scheme")
+	}
+}
+
+func TestEnvironmentProperties_secret_test(t *testing.T) {
+	t.Setenv("JUICE_SECRET_TEST_PASSWORD", "s3cret")
+	t.Setenv("JUICE_SECRET_TEST_HOST", "db.local")
+	newConfiguration := func(environment string) (Configuration, error) {
+		fsys := fstest.MapFS{
+			"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="prod">
+		<environment id="prod" provider="env">` + environment + `</environment>
+	</environments>
+</configuration>`)},
+		}
+		return NewXMLConfigurationWithFS(fsys, "juice.xml")
+	}
+
+	configuration, err := newConfiguration(`
+			<driver>mysql</driver>
+			<property name="host" value="${JUICE_SECRET_TEST_HOST}"/>
+			<property name="port" value="3306"/>
+			<property name="user" value="app"/>
+			<property name="passwordRef" value="env:JUICE_SECRET_TEST_PASSWORD"/>
+			<property name="database" value="shop"/>
+			<property name="parseTime" value="true"/>`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	environment, err := configuration.Environments().Use("prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if environment.DataSource != "app:s3cret@tcp(db.local:3306)/shop?parseTime=true" {
+		t.Fatalf("unexpected data source %q", environment.DataSource)
+	}
+
+	for environment, want := range map[string]string{
+		`<dataSource>app.db</dataSource><driver>sqlite3</driver><property name="database" value="app.db"/>`:       "mutually exclusive",
+		`<driver>mysql</driver><property name="password" value="a"/><property name="passwordRef" value="env:X"/>`: "conflicts",
+		`<driver>mysql</driver><property name="passwordRef" value="vault:db"/>`:                                   "secret resolver not found",
+		`<driver>sqlite3</driver><property name="host" value="a"/>`:                                               "data source property is required",
+		`<driver>mysql</driver><property name="host" value="a"/><property name="host" value="b"/>`:                "duplicate property",
+	} {
+		if _, err = newConfiguration(environment); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error containing %q, got %v", want, err)
+		}
+	}
+}
@@ -16,7 +16,11 @@ limitations under the License.
 
 package tx
 
-import "database/sql"
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
 
 // TransactionOptionFunc is a function to set the transaction options.
 // It mutates sql.TxOptions before a transaction is opened.
@@ -35,3 +39,31 @@ func WithReadOnly(readOnly bool) TransactionOptionFunc {
 		options.ReadOnly = readOnly
 	}
 }
+
+// isolationLevels maps the normalized isolation level names to their levels.
+var isolationLevels = map[string]sql.IsolationLevel{
+	"default":         sql.LevelDefault,
+	"readuncommitted": sql.LevelReadUncommitted,
+	"readcommitted":   sql.LevelReadCommitted,
+	"writecommitted":  sql.LevelWriteCommitted,
+	"repeatableread":  sql.LevelRepeatableRead,
+	"snapshot":        sql.LevelSnapshot,
+	"serializable":    sql.LevelSerializable,
+	"linearizable":    sql.LevelLinearizable,
+}
+
+// ParseIsolationLevel returns the isolation level named by name.
+// The name is case-insensitive and its words may be separated by spaces,
+// underscores or hyphens or not at all, like "Read Committed", "read_committed"
+// or "readCommitted". An empty name is sql.LevelDefault.
+func ParseIsolationLevel(name string) (sql.IsolationLevel, error) {
+	normalized := strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+	if normalized == "" {
+		return sql.LevelDefault, nil
+	}
+	level, ok := isolationLevels[normalized]
+	if !ok {
+		return sql.LevelDefault, fmt.Errorf("unknown isolation level %q", name)
+	}
+	return level, nil
+}
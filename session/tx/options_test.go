@@ -0,0 +1,30 @@
+package tx
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestParseIsolationLevel(t *testing.T) {
+	cases := map[string]sql.IsolationLevel{
+		"":                 sql.LevelDefault,
+		"default":          sql.LevelDefault,
+		"Read Committed":   sql.LevelReadCommitted,
+		"read_uncommitted": sql.LevelReadUncommitted,
+		"repeatableRead":   sql.LevelRepeatableRead,
+		"SERIALIZABLE":     sql.LevelSerializable,
+		"snapshot":         sql.LevelSnapshot,
+	}
+	for name, want := range cases {
+		got, err := ParseIsolationLevel(name)
+		if err != nil {
+			t.Fatalf("%q: %v", name, err)
+		}
+		if got != want {
+			t.Fatalf("%q: expected %v, got %v", name, want, got)
+		}
+	}
+	if _, err := ParseIsolationLevel("chaos"); err == nil {
+		t.Fatal("expected unknown isolation level error")
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+
+	"github.com/go-juicedev/juice/eval"
+	"github.com/go-juicedev/juice/internal/reflectlite"
+	"github.com/go-juicedev/juice/sql"
+)
+
+const (
+	// shardByAttribute names the parameter holding the sharding key of a statement.
+	shardByAttribute = "shardBy"
+
+	// shardTableAttribute names the logical table of a sharded statement,
+	// which selects the ShardingStrategy registered with Engine.UseShardingStrategy.
+	shardTableAttribute = "shardTable"
+)
+
+// ShardTableParamKey is the parameter holding the physical table of a sharded statement,
+// so that the statement can be written as SELECT * FROM ${table} WHERE user_id = #{user_id}.
+// It takes precedence over the user parameters.
+const ShardTableParamKey = "table"
+
+var (
+	// ErrShardingStrategyNotFound is returned when a sharded statement has no strategy registered for its table.
+	ErrShardingStrategyNotFound = errors.New("juice: sharding strategy not found")
+
+	// ErrShardingKeyNotFound is returned when the sharding key of a statement is missing from its parameter.
+	ErrShardingKeyNotFound = errors.New("juice: sharding key not found")
+
+	// ErrInvalidShardingKey is returned when a strategy can not map a sharding key to a shard.
+	ErrInvalidShardingKey = errors.New("juice: invalid sharding key")
+
+	// ErrShardInTransaction is returned when a statement executed in a transaction
+	// is routed to a shard of another datasource.
+	ErrShardInTransaction = errors.New("juice: shard datasource differs from the transaction one")
+)
+
+// Shard is the physical location of the rows of a sharding key.
+type Shard struct {
+	// Suffix is appended to the logical table, joined by an underscore, to name the physical table.
+	// An empty suffix keeps the logical table.
+	Suffix string
+
+	// DataSource is the environment id the statement is routed to.
+	// An empty datasource keeps the current one.
+	DataSource string
+}
+
+// ShardingStrategy maps the sharding key of a statement to its shard.
+// The key is the value of the parameter named by the shardBy attribute,
+// with pointers and interfaces dereferenced.
+type ShardingStrategy interface {
+	Shard(key any) (Shard, error)
+}
+
+// ShardingStrategyFunc is an adapter to allow the use of ordinary functions as ShardingStrategy.
+type ShardingStrategyFunc func(key any) (Shard, error)
+
+// Shard implements ShardingStrategy.
+func (f ShardingStrategyFunc) Shard(key any) (Shard, error) {
+	return f(key)
+}
+
+// HashShardingStrategy spreads the keys over a fixed number of shards.
+// Integer keys are mapped by their value modulo Shards, string keys by their FNV-1a hash.
+type HashShardingStrategy struct {
+	// Shards is the number of shards.
+	Shards int
+
+	// Format formats the index of the shard into its suffix, "%02d" by default.
+	Format string
+
+	// DataSources optionally routes the shard of index i to DataSources[i % len(DataSources)].
+	DataSources []string
+}
+
+// Shard implements ShardingStrategy.
+func (h HashShardingStrategy) Shard(key any) (Shard, error) {
+	if h.Shards <= 0 {
+		return Shard{}, fmt.Errorf("%w: hash sharding requires a positive number of shards", ErrInvalidShardingKey)
+	}
+	var index uint64
+	value := reflect.ValueOf(key)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := value.Int()
+		if i < 0 {
+			i = -i
+		}
+		index = uint64(i) % uint64(h.Shards)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		index = value.Uint() % uint64(h.Shards)
+	case reflect.String:
+		hash := fnv.New32a()
+		_, _ = hash.Write([]byte(value.String()))
+		index = uint64(hash.Sum32()) % uint64(h.Shards)
+	default:
+		return Shard{}, fmt.Errorf("%w: can not hash %T", ErrInvalidShardingKey, key)
+	}
+	format := h.Format
+	if format == "" {
+		format = "%02d"
+	}
+	shard := Shard{Suffix: fmt.Sprintf(format, index)}
+	if len(h.DataSources) > 0 {
+		shard.DataSource = h.DataSources[index%uint64(len(h.DataSources))]
+	}
+	return shard, nil
+}
+
+// ShardRange is a range of integer keys stored in the same shard.
+type ShardRange struct {
+	// Upper is the exclusive upper bound of the keys of the range,
+	// its lower bound is the upper bound of the previous range.
+	Upper int64
+
+	// Shard is the shard of the keys of the range.
+	Shard Shard
+}
+
+// RangeShardingStrategy maps integer keys to the shard of the range they fall in.
+// Ranges must be sorted by ascending upper bound. Keys above the last one are invalid.
+type RangeShardingStrategy []ShardRange
+
+// Shard implements ShardingStrategy.
+func (r RangeShardingStrategy) Shard(key any) (Shard, error) {
+	var i int64
+	value := reflect.ValueOf(key)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i = value.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := value.Uint()
+		if u > 1<<63-1 {
+			return Shard{}, fmt.Errorf("%w: %d is out of range", ErrInvalidShardingKey, u)
+		}
+		i = int64(u)
+	default:
+		return Shard{}, fmt.Errorf("%w: range sharding requires an integer key, got %T", ErrInvalidShardingKey, key)
+	}
+	index := sort.Search(len(r), func(n int) bool { return i < r[n].Upper })
+	if index == len(r) {
+		return Shard{}, fmt.Errorf("%w: %d is out of range", ErrInvalidShardingKey, i)
+	}
+	return r[index].Shard, nil
+}
+
+// shardOf returns the logical table and the shard of the statement for param.
+// ok is false if the statement is not sharded.
+func shardOf(engine *Engine, statement Statement, param eval.Param) (table string, shard Shard, ok bool, err error) {
+	key := statement.Attribute(shardByAttribute)
+	if key == "" {
+		return "", Shard{}, false, nil
+	}
+	table = statement.Attribute(shardTableAttribute)
+	strategy, exists := engine.shardingStrategies[table]
+	if !exists {
+		return "", Shard{}, false, fmt.Errorf("%w: table %q of %s", ErrShardingStrategyNotFound, table, statement.Name())
+	}
+	value, exists := eval.NewGenericParam(param, statement.Attribute("paramName")).Get(key)
+	if value = reflectlite.Unwrap(value); !exists || !value.IsValid() || reflectlite.IsNilable(value) && value.IsNil() {
+		return "", Shard{}, false, fmt.Errorf("%w: %s of %s", ErrShardingKeyNotFound, key, statement.Name())
+	}
+	if shard, err = strategy.Shard(value.Interface()); err != nil {
+		return "", Shard{}, false, fmt.Errorf("%s: %w", statement.Name(), err)
+	}
+	return table, shard, true, nil
+}
+
+// shardingParameter returns the parameter carrying the physical table of the statement for param.
+// It returns nil if the statement is not sharded.
+func shardingParameter(engine *Engine, statement Statement, param eval.Param) (eval.Parameter, error) {
+	table, shard, ok, err := shardOf(engine, statement, param)
+	if !ok || err != nil {
+		return nil, err
+	}
+	if shard.Suffix != "" {
+		table += "_" + shard.Suffix
+	}
+	return eval.H{ShardTableParamKey: table}, nil
+}
+
+// ensure shardingMiddleware implements Middleware.
+var _ Middleware = (*shardingMiddleware)(nil) // compile time check
+
+// shardingMiddleware routes the sharded statements to the datasource of their shard.
+// Statements executed in a transaction can not leave its datasource.
+type shardingMiddleware struct{}
+
+// route switches the session of ctx to the datasource of the shard of its statement.
+func (s *shardingMiddleware) route(ctx *StatementContext) error {
+	engine := ctx.Engine()
+	_, shard, ok, err := shardOf(engine, ctx.Statement(), ctx.Param())
+	if !ok || err != nil || shard.DataSource == "" || shard.DataSource == engine.EnvID() {
+		return err
+	}
+	if isInTransaction(ctx.Session()) {
+		return fmt.Errorf("%w: %s", ErrShardInTransaction, shard.DataSource)
+	}
+	shardEngine, err := engine.With(shard.DataSource)
+	if err != nil {
+		return err
+	}
+	ctx.WithSession(shardEngine.DB())
+	return nil
+}
+
+// QueryContext implements Middleware.
+func (s *shardingMiddleware) QueryContext(statementContext *StatementContext, next QueryHandler) QueryHandler {
+	if statementContext.Statement().Attribute(shardByAttribute) == "" {
+		return next
+	}
+	return func(ctx context.Context, query string, args ...any) (sql.Rows, error) {
+		if err := s.route(statementContext); err != nil {
+			return nil, err
+		}
+		return next(ctx, query, args...)
+	}
+}
+
+// ExecContext implements Middleware.
+func (s *shardingMiddleware) ExecContext(statementContext *StatementContext, next ExecHandler) ExecHandler {
+	if statementContext.Statement().Attribute(shardByAttribute) == "" {
+		return next
+	}
+	return func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+		if err := s.route(statementContext); err != nil {
+			return nil, err
+		}
+		return next(ctx, query, args...)
+	}
+}
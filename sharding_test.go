@@ -0,0 +1,137 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+func TestHashShardingStrategy_sharding_test(t *testing.T) {
+	strategy := HashShardingStrategy{Shards: 8, DataSources: []string{"a", "b"}}
+	tests := []struct {
+		key  any
+		want Shard
+	}{
+		{int64(15), Shard{Suffix: "07", DataSource: "b"}},
+		{-10, Shard{Suffix: "02", DataSource: "a"}},
+		{uint8(3), Shard{Suffix: "03", DataSource: "b"}},
+	}
+	for _, tt := range tests {
+		if got, err := strategy.Shard(tt.key); err != nil || got != tt.want {
+			t.Errorf("Shard(%v) = %v, %v, want %v", tt.key, got, err, tt.want)
+		}
+	}
+
+	first, err := HashShardingStrategy{Shards: 16, Format: "%x"}.Shard("alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, _ := HashShardingStrategy{Shards: 16, Format: "%x"}.Shard("alice")
+	if first != second || len(first.Suffix) != 1 || first.DataSource != "" {
+		t.Fatalf("unexpected string shards %v %v", first, second)
+	}
+
+	if _, err = strategy.Shard(1.5); !errors.Is(err, ErrInvalidShardingKey) {
+		t.Fatalf("expected ErrInvalidShardingKey, got %v", err)
+	}
+	if _, err = (HashShardingStrategy{}).Shard(1); !errors.Is(err, ErrInvalidShardingKey) {
+		t.Fatalf("expected ErrInvalidShardingKey, got %v", err)
+	}
+}
+
+func TestRangeShardingStrategy_sharding_test(t *testing.T) {
+	strategy := RangeShardingStrategy{
+		{Upper: 1000, Shard: Shard{Suffix: "0"}},
+		{Upper: 2000, Shard: Shard{Suffix: "1", DataSource: "archive"}},
+	}
+	for key, want := range map[any]Shard{-1: {Suffix: "0"}, 999: {Suffix: "0"}, uint(1000): {Suffix: "1", DataSource: "archive"}} {
+		if got, err := strategy.Shard(key); err != nil || got != want {
+			t.Errorf("Shard(%v) = %v, %v, want %v", key, got, err, want)
+		}
+	}
+	for _, key := range []any{2000, "1"} {
+		if _, err := strategy.Shard(key); !errors.Is(err, ErrInvalidShardingKey) {
+			t.Errorf("Shard(%v): expected ErrInvalidShardingKey, got %v", key, err)
+		}
+	}
+}
+
+func TestShardingStatement_sharding_test(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>sqlite.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>
+		<mapper namespace="order" shardTable="orders" shardBy="user_id">
+			<select id="Find">SELECT * FROM ${table} WHERE user_id = #{user_id}</select>
+		</mapper>
+	</mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	statement, err := configuration.GetStatement("order.Find")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	engine := newStatementTestEngine(nil)
+	if _, _, err = buildStatementQuery(context.Background(), statement, engine, H{"user_id": 7}); !errors.Is(err, ErrShardingStrategyNotFound) {
+		t.Fatalf("expected ErrShardingStrategyNotFound, got %v", err)
+	}
+	engine.UseShardingStrategy("orders", HashShardingStrategy{Shards: 16})
+
+	userID := 23
+	query, args, err := buildStatementQuery(context.Background(), statement, engine, H{"user_id": &userID, "table": "users"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query != "SELECT * FROM orders_07 WHERE user_id = ?" || !reflect.DeepEqual(args, []any{23}) {
+		t.Fatalf("unexpected query %q %v", query, args)
+	}
+	if _, _, err = buildStatementQuery(context.Background(), statement, engine, H{}); !errors.Is(err, ErrShardingKeyNotFound) {
+		t.Fatalf("expected ErrShardingKeyNotFound, got %v", err)
+	}
+}
+
+func TestShardingMiddleware_sharding_test(t *testing.T) {
+	engine := newStatementTestEngine(nil)
+	engine.UseShardingStrategy("orders", ShardingStrategyFunc(func(key any) (Shard, error) {
+		return Shard{Suffix: "01", DataSource: key.(string)}, nil
+	}))
+	tx, err := openStatementTestDB(t, &shSQLDriverState{}).Begin()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var called bool
+	next := func(context.Context, string, ...any) (jsql.Result, error) {
+		called = true
+		return nil, nil
+	}
+	stmt := shStatement{action: jsql.Insert, attrs: map[string]string{"shardBy": "ds", "shardTable": "orders"}}
+
+	// the shard of the current datasource is executed in the transaction.
+	handler := (&shardingMiddleware{}).ExecContext(newStatementContext(context.Background(), engine, stmt, H{"ds": engine.EnvID()}, tx), next)
+	if _, err = handler(context.Background(), "INSERT INTO orders_01 VALUES (1)"); err != nil || !called {
+		t.Fatalf("expected the statement to be executed, err=%v", err)
+	}
+
+	called = false
+	handler = (&shardingMiddleware{}).ExecContext(newStatementContext(context.Background(), engine, stmt, H{"ds": "other"}, tx), next)
+	if _, err = handler(context.Background(), "INSERT INTO orders_01 VALUES (1)"); !errors.Is(err, ErrShardInTransaction) || called {
+		t.Fatalf("expected ErrShardInTransaction, got %v", err)
+	}
+}
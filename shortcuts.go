@@ -19,11 +19,25 @@ package juice
 import (
 	"context"
 	"database/sql"
+	"fmt"
+
 	sqllib "github.com/go-juicedev/juice/sql"
 )
 
 // This file provides context-based database helper shortcuts.
 
+// Query executes statement with param through manager, like an Engine or a transaction,
+// and binds its rows to T: every row when T is a slice, or the single row otherwise.
+// Rows are closed after reading.
+//
+// Example:
+//
+//	users, err := juice.Query[[]User](ctx, engine, "UserMapper.ListByStatus", juice.H{"status": 1})
+//	user, err := juice.Query[User](ctx, engine, "UserMapper.GetByID", juice.H{"id": 1})
+func Query[T any](ctx context.Context, manager Manager, statement, param any) (T, error) {
+	return NewGenericManager[T](manager).Object(statement).QueryContext(ctx, param)
+}
+
 // QueryContext executes a query with the provided context and scans a single result into T.
 // (ctx must contain a Manager via ManagerFromContext)
 func QueryContext[T any](ctx context.Context, statement, param any) (result T, err error) {
@@ -31,8 +45,7 @@ func QueryContext[T any](ctx context.Context, statement, param any) (result T, e
 	if err != nil {
 		return result, err
 	}
-	executor := NewGenericManager[T](manager).Object(statement)
-	return executor.QueryContext(ctx, param)
+	return Query[T](ctx, manager, statement, param)
 }
 
 // ExecContext executes a statement that does not return rows and returns a sql.Result.
@@ -46,6 +59,45 @@ func ExecContext(ctx context.Context, statement, param any) (result sql.Result,
 	return executor.ExecContext(ctx, param)
 }
 
+// ExecAffected executes a statement that does not return rows and returns the number of rows it affected.
+// (ctx must contain a Manager via ManagerFromContext)
+func ExecAffected(ctx context.Context, statement, param any) (int64, error) {
+	_, affected, err := execAffected(ctx, statement, param)
+	return affected, err
+}
+
+// ExecExpectOne executes a statement that must affect exactly one row, like an update or a delete
+// by primary key. It returns ErrUnexpectedRowCount if it affected another number of rows.
+// (ctx must contain a Manager via ManagerFromContext)
+func ExecExpectOne(ctx context.Context, statement, param any) error {
+	stmt, affected, err := execAffected(ctx, statement, param)
+	if err != nil {
+		return err
+	}
+	if affected != 1 {
+		return fmt.Errorf("%w: %s affected %d rows, expected 1", ErrUnexpectedRowCount, stmt.Name(), affected)
+	}
+	return nil
+}
+
+// execAffected executes a statement that does not return rows and returns it with the number of rows it affected.
+func execAffected(ctx context.Context, statement, param any) (Statement, int64, error) {
+	manager, err := ManagerFromContext(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	executor := manager.Object(statement)
+	result, err := executor.ExecContext(ctx, param)
+	if err != nil {
+		return nil, 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, 0, err
+	}
+	return executor.Statement(), affected, nil
+}
+
 // QueryListContext executes a query and returns a slice of T. Rows are closed after reading.
 // (ctx must contain a Manager via ManagerFromContext)
 func QueryListContext[T any](ctx context.Context, statement, param any) (result []T, err error) {
@@ -104,3 +156,14 @@ func QueryIterContext[T any](ctx context.Context, statement, param any) (sqllib.
 		iterator(yield)
 	}, nil
 }
+
+// ExecReturningContext executes a statement that returns rows, such as INSERT ... RETURNING,
+// and returns both the returned rows as a slice of T and an emulated sql.Result.
+// (ctx must contain a Manager via ManagerFromContext)
+func ExecReturningContext[T any](ctx context.Context, statement, param any) (result []T, sqlResult sql.Result, err error) {
+	manager, err := ManagerFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ExecReturning[T](ctx, manager.Object(statement), param)
+}
@@ -24,6 +24,9 @@ func TestShortcuts_shortcuts_test(t *testing.T) {
 	if _, err := QueryIterContext[string](context.Background(), "stmt", nil); !errors.Is(err, ErrNoManagerFoundInContext) {
 		t.Fatalf("expected ErrNoManagerFoundInContext, got %v", err)
 	}
+	if _, _, err := ExecReturningContext[string](context.Background(), "stmt", nil); !errors.Is(err, ErrNoManagerFoundInContext) {
+		t.Fatalf("expected ErrNoManagerFoundInContext, got %v", err)
+	}
 
 	executor := &sqlRowsExecutorStub{
 		queryRows:  jsql.NewRowsBuffer([]string{"value"}, [][]any{{"one"}}),
@@ -79,6 +82,21 @@ func TestShortcuts_shortcuts_test(t *testing.T) {
 		t.Fatalf("unexpected iter items: %#v", got)
 	}
 
+	executor.queryRows = jsql.NewRowsBuffer([]string{"value"}, [][]any{{"r1"}, {"r2"}})
+	returned, result, err := ExecReturningContext[string](ctx, "stmt.returning", nil)
+	if err != nil {
+		t.Fatalf("unexpected ExecReturningContext error: %v", err)
+	}
+	if len(returned) != 2 || returned[0] != "r1" || returned[1] != "r2" {
+		t.Fatalf("unexpected ExecReturningContext items: %#v", returned)
+	}
+	if affected, _ := result.RowsAffected(); affected != 2 {
+		t.Fatalf("expected 2 rows affected, got %d", affected)
+	}
+	if _, err = result.LastInsertId(); !errors.Is(err, jsql.ErrLastInsertIdNotSupported) {
+		t.Fatalf("expected ErrLastInsertIdNotSupported, got %v", err)
+	}
+
 	queryErr := errors.New("query failed")
 	executor.queryErr = queryErr
 	if _, err = QueryListContext[string](ctx, "stmt.list.err", nil); !errors.Is(err, queryErr) {
@@ -90,6 +108,9 @@ func TestShortcuts_shortcuts_test(t *testing.T) {
 	if _, err = QueryIterContext[string](ctx, "stmt.iter.err", nil); !errors.Is(err, queryErr) {
 		t.Fatalf("expected query error, got %v", err)
 	}
+	if _, _, err = ExecReturningContext[string](ctx, "stmt.returning.err", nil); !errors.Is(err, queryErr) {
+		t.Fatalf("expected query error, got %v", err)
+	}
 	executor.queryErr = nil
 
 	execIterErr := errors.New("iter query failed")
@@ -100,3 +121,63 @@ func TestShortcuts_shortcuts_test(t *testing.T) {
 	executor.queryErr = nil
 }
 
+
+type affectedResultStub int64
+
+func (affectedResultStub) LastInsertId() (int64, error) { return 0, nil }
+func (r affectedResultStub) RowsAffected() (int64, error) { return int64(r), nil }
+
+func TestExecAffected_shortcuts_test(t *testing.T) {
+	if _, err := ExecAffected(context.Background(), "stmt", nil); !errors.Is(err, ErrNoManagerFoundInContext) {
+		t.Fatalf("expected ErrNoManagerFoundInContext, got %v", err)
+	}
+	if err := ExecExpectOne(context.Background(), "stmt", nil); !errors.Is(err, ErrNoManagerFoundInContext) {
+		t.Fatalf("expected ErrNoManagerFoundInContext, got %v", err)
+	}
+
+	executor := &sqlRowsExecutorStub{execResult: affectedResultStub(2), stmt: statementStub{}}
+	ctx := ContextWithManager(context.Background(), &managerStub{object: executor})
+
+	affected, err := ExecAffected(ctx, "stmt.exec", nil)
+	if err != nil || affected != 2 {
+		t.Fatalf("expected 2 rows affected, got %d err=%v", affected, err)
+	}
+	if err = ExecExpectOne(ctx, "stmt.exec", nil); !errors.Is(err, ErrUnexpectedRowCount) {
+		t.Fatalf("expected ErrUnexpectedRowCount, got %v", err)
+	}
+
+	executor.execResult = affectedResultStub(1)
+	if err = ExecExpectOne(ctx, "stmt.exec", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := errors.New("exec failed")
+	executor.execErr = want
+	if err = ExecExpectOne(ctx, "stmt.exec", nil); !errors.Is(err, want) {
+		t.Fatalf("expected %v, got %v", want, err)
+	}
+}
+
+func TestQuery_shortcuts_test(t *testing.T) {
+	executor := &sqlRowsExecutorStub{
+		queryRows: jsql.NewRowsBuffer([]string{"value"}, [][]any{{"one"}, {"two"}}),
+		stmt:      statementStub{},
+	}
+	mgr := &managerStub{object: executor}
+
+	values, err := Query[[]string](context.Background(), mgr, "stmt.list", H{"id": 1})
+	if err != nil {
+		t.Fatalf("unexpected Query error: %v", err)
+	}
+	if len(values) != 2 || values[0] != "one" || values[1] != "two" {
+		t.Fatalf("unexpected Query values: %#v", values)
+	}
+	if mgr.lastV != "stmt.list" {
+		t.Fatalf("unexpected statement: %v", mgr.lastV)
+	}
+
+	executor.queryRows = jsql.NewRowsBuffer([]string{"value"}, [][]any{{"one"}, {"two"}})
+	if _, err = Query[string](context.Background(), mgr, "stmt.one", nil); !errors.Is(err, jsql.ErrTooManyRows) {
+		t.Fatalf("expected ErrTooManyRows for a single value, got %v", err)
+	}
+}
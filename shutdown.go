@@ -0,0 +1,100 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrEngineShutdown is returned when a statement is executed after Engine.Shutdown has been called.
+var ErrEngineShutdown = errors.New("juice: engine is shutting down")
+
+// inflightTracker counts the statements being executed by an engine and its clones.
+// A nil tracker tracks nothing.
+type inflightTracker struct {
+	mu      sync.Mutex
+	count   int
+	closing bool
+	// idle is closed once closing and no statement is in flight.
+	idle chan struct{}
+}
+
+// acquire registers a statement execution.
+// It returns ErrEngineShutdown if the tracker is draining.
+func (t *inflightTracker) acquire() error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closing {
+		return ErrEngineShutdown
+	}
+	t.count++
+	return nil
+}
+
+// release unregisters a statement execution registered by acquire.
+func (t *inflightTracker) release() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.count--
+	if t.closing && t.count == 0 {
+		close(t.idle)
+	}
+}
+
+// drain stops accepting new statement executions and waits for the in-flight ones,
+// until ctx is done.
+func (t *inflightTracker) drain(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	if !t.closing {
+		t.closing = true
+		t.idle = make(chan struct{})
+		if t.count == 0 {
+			close(t.idle)
+		}
+	}
+	idle := t.idle
+	t.mu.Unlock()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown gracefully shuts down the engine and all its clones.
+// It stops accepting new statements, which fail with ErrEngineShutdown, waits for the statements
+// being executed until ctx is done, then closes all managed database connections.
+// If ctx is done first, the connections are closed anyway and the error of ctx is returned.
+//
+// Rows returned before Shutdown remain readable until the connections are closed,
+// and transactions must be finished before calling it.
+func (e *Engine) Shutdown(ctx context.Context) error {
+	drainErr := e.inflight.drain(ctx)
+	return errors.Join(drainErr, e.Close())
+}
@@ -0,0 +1,50 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+func TestEngineShutdown_shutdown_test(t *testing.T) {
+	engine := newStatementTestEngine(nil)
+	engine.manager = &DBManager{}
+	engine.inflight = &inflightTracker{}
+
+	started, finish := make(chan struct{}), make(chan struct{})
+	handler := newExecuteStatementHandler("UPDATE t SET a = 1", nil, engine.clone(), nil).withExecHandler(
+		func(context.Context, string, ...any) (jsql.Result, error) {
+			close(started)
+			<-finish
+			return nil, nil
+		},
+	)
+	executed := make(chan error, 1)
+	go func() {
+		_, err := handler.ExecContext(context.Background(), shStatement{action: jsql.Update}, nil)
+		executed <- err
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := engine.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	rejected := newExecuteStatementHandler("SELECT 1", nil, engine, nil)
+	if _, err := rejected.QueryContext(context.Background(), shStatement{action: jsql.Select}, nil); !errors.Is(err, ErrEngineShutdown) {
+		t.Fatalf("expected ErrEngineShutdown, got %v", err)
+	}
+
+	close(finish)
+	if err := <-executed; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := engine.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
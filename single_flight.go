@@ -0,0 +1,77 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-juicedev/juice/internal/singleflight"
+	"github.com/go-juicedev/juice/session"
+	"github.com/go-juicedev/juice/sql"
+)
+
+// singleFlightAttribute is the attribute of the select statements opting out of SingleFlightMiddleware
+// with "false", like the statements whose results must not be shared between callers.
+const singleFlightAttribute = "singleFlight"
+
+// SingleFlightMiddleware coalesces the concurrent executions of the same select statement with the same
+// query and arguments into one round trip to the database: the rows are read once and every caller
+// scans its own copy of them. It suits the hot queries, like the ones of dashboards or of the results
+// of a cache which just expired.
+//
+// The statements executed in a transaction are not coalesced. A caller waiting for the execution of
+// another one is not interrupted by the cancellation of its context, but when the execution fails
+// because the context of the other caller is canceled, it executes the statement on its own.
+type SingleFlightMiddleware struct {
+	NoopMiddleware
+
+	// group coalesces the executions by key.
+	group singleflight.Group[*CacheEntry]
+}
+
+// ensure SingleFlightMiddleware implements Middleware.
+var _ Middleware = (*SingleFlightMiddleware)(nil) // compile time check
+
+// QueryContext implements Middleware.
+func (m *SingleFlightMiddleware) QueryContext(ctx *StatementContext, next QueryHandler) QueryHandler {
+	statement := ctx.Statement()
+	if statement.Action() != sql.Select || statement.Attribute(singleFlightAttribute) == "false" {
+		return next
+	}
+	if _, inTransaction := ctx.Session().(session.Transaction); inTransaction {
+		return next
+	}
+	return func(c context.Context, query string, args ...any) (sql.Rows, error) {
+		entry, err, shared := m.group.Do(cacheKey(statement, query, args), func() (*CacheEntry, error) {
+			rows, err := next(c, query, args...)
+			if err != nil {
+				return nil, err
+			}
+			return bufferRows(rows)
+		})
+		if err != nil && shared && c.Err() == nil &&
+			(errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+			// the context of the caller executing the statement was canceled, not this one.
+			return next(c, query, args...)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return sql.NewRowsBuffer(entry.Columns, entry.Rows), nil
+	}
+}
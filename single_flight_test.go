@@ -0,0 +1,70 @@
+package juice
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+func TestSingleFlightMiddleware_single_flight_test(t *testing.T) {
+	middleware := &SingleFlightMiddleware{}
+	engine := newStatementTestEngine(nil, middleware)
+	statement := shStatement{name: "user.Count"}
+	var queries atomic.Int64
+	release := make(chan struct{})
+	handler := func() *executeStatementHandler {
+		return newExecuteStatementHandler("SELECT COUNT(*) FROM user", nil, engine, nil).withQueryHandler(
+			func(context.Context, string, ...any) (jsql.Rows, error) {
+				queries.Add(1)
+				<-release
+				return jsql.NewRowsBuffer([]string{"count"}, [][]any{{int64(42)}}), nil
+			},
+		)
+	}
+	query := func() (int, error) {
+		rows, err := handler().QueryContext(context.Background(), statement, nil)
+		if err != nil {
+			return 0, err
+		}
+		counts, err := jsql.List[int](rows)
+		if err != nil {
+			return 0, err
+		}
+		return counts[0], nil
+	}
+
+	const callers = 4
+	counts := make([]int, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			counts[i], errs[i] = query()
+		}()
+	}
+	key := cacheKey(statement, "SELECT COUNT(*) FROM user", nil)
+	for middleware.group.Waiters(key) != callers-1 {
+		runtime.Gosched()
+	}
+	close(release)
+	wg.Wait()
+	for i := range callers {
+		if errs[i] != nil || counts[i] != 42 {
+			t.Fatalf("caller %d: got %d, %v", i, counts[i], errs[i])
+		}
+	}
+	if queries.Load() != 1 {
+		t.Fatalf("expected one query, got %d", queries.Load())
+	}
+
+	statement.attrs = map[string]string{singleFlightAttribute: "false"}
+	if _, err := query(); err != nil || queries.Load() != 2 {
+		t.Fatalf("expected singleFlight=false to execute the query, got %d queries, %v", queries.Load(), err)
+	}
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	"github.com/go-juicedev/juice/node"
+	"github.com/go-juicedev/juice/sql"
+)
+
+// softDeleteAttribute names the column holding the deletion time of logically deleted rows.
+// It can be set on a statement or on its mapper.
+const softDeleteAttribute = "softDelete"
+
+var (
+	// ErrSoftDeleteWhereRequired is returned when the WHERE clause of a soft delete query is not a top level <where> element,
+	// like a WHERE written as plain text or rendered by an <if> or an <include>,
+	// which can not be combined with the filter of the logically deleted rows. Use a top level <where> element instead.
+	ErrSoftDeleteWhereRequired = errors.New("juice: softDelete select requires a top level <where> element")
+
+	// ErrSoftDeleteUnsupported is returned when a soft delete DELETE statement can not be rewritten.
+	ErrSoftDeleteUnsupported = errors.New("juice: unsupported softDelete statement")
+)
+
+// columnAttribute returns the column named by the attribute key of the statement.
+// A statement can opt out of the attribute of its mapper with key="false".
+func columnAttribute(statement Statement, key string) string {
+	column := statement.Attribute(key)
+	if column == "false" {
+		return ""
+	}
+	return column
+}
+
+// softDeleteColumn returns the softDelete column of the statement.
+func softDeleteColumn(statement Statement) string {
+	return columnAttribute(statement, softDeleteAttribute)
+}
+
+// applySoftDelete rewrites the node tree of a select statement with the softDelete attribute,
+// so that its top level <where> elements exclude the logically deleted rows.
+// The <where> elements of the subqueries, the nested and the included ones are kept,
+// a statement without top level <where> element gets a WHERE clause, see insertSoftDeleteWhere.
+// DELETE statements are rewritten by softDeleteMiddleware, since their SQL is only known when rendered,
+// the ones made of text only are checked at load.
+func applySoftDelete(statement *mappedStatement) error {
+	column := softDeleteColumn(statement)
+	if column == "" {
+		return nil
+	}
+	switch statement.Action() {
+	case sql.Select:
+	case sql.Delete:
+		// a DELETE statement made of text only fails at load, instead of when it is executed.
+		if len(statement.Nodes) != 1 {
+			return nil
+		}
+		if text, ok := node.Text(statement.Nodes[0]); ok {
+			if _, err := rewriteSoftDelete(text, column); err != nil {
+				return fmt.Errorf("%w: %s", err, statement.Name())
+			}
+		}
+		return nil
+	default:
+		return nil
+	}
+	var rewritten bool
+	nodes := slices.Clone(statement.Nodes)
+	depth := 0
+	for i, item := range nodes {
+		if text, ok := node.Text(item); ok {
+			depth = max(depth+parenDepth(text), 0)
+			continue
+		}
+		// the <where> elements in parentheses belong to subqueries.
+		if depth == 0 && isWhereClause(item) {
+			nodes[i] = &node.SoftDeleteNode{Where: item, Column: column}
+			rewritten = true
+		}
+	}
+	if !rewritten {
+		var err error
+		if nodes, err = insertSoftDeleteWhere(statement.Nodes, column); err != nil {
+			return fmt.Errorf("%w: %s", err, statement.Name())
+		}
+	}
+	statement.Nodes = nodes
+	return nil
+}
+
+// insertSoftDeleteWhere returns a copy of nodes with a WHERE clause excluding the logically deleted rows,
+// inserted before the first clause following the WHERE clause of a query, like ORDER BY, or at the end.
+// The clauses are looked up in the text of the top level nodes, outside quotes and parentheses.
+// The clauses rendered by the other elements, like <if> or <include>, are only known when rendered,
+// so the nodes from the first of them are wrapped by a softDeleteClauseNode.
+//
+//	SELECT * FROM user ORDER BY id -> SELECT * FROM user WHERE deleted_at IS NULL ORDER BY id
+func insertSoftDeleteWhere(nodes node.Group, column string) (node.Group, error) {
+	where := &node.SoftDeleteNode{Where: node.Group{}, Column: column}
+	depth := 0
+	for i, item := range nodes {
+		text, ok := node.Text(item)
+		if !ok {
+			if depth > 0 {
+				continue
+			}
+			if _, ok = item.(*node.OrderByNode); ok {
+				return slices.Insert(slices.Clone(nodes), i, node.Node(where)), nil
+			}
+			tail := &softDeleteClauseNode{Nodes: slices.Clone(nodes[i:]), Where: where}
+			return append(slices.Clone(nodes[:i]), tail), nil
+		}
+		index, keyword := indexQueryClause(text, depth)
+		if keyword == "WHERE" {
+			return nil, ErrSoftDeleteWhereRequired
+		}
+		if index >= 0 {
+			split := []node.Node{node.NewTextNode(text[:index]), where, node.NewTextNode(text[index:])}
+			return slices.Concat(nodes[:i], split, nodes[i+1:]), nil
+		}
+		depth = max(depth+parenDepth(text), 0)
+	}
+	return append(slices.Clone(nodes), where), nil
+}
+
+// softDeleteClauseNode renders Nodes with the WHERE clause excluding the logically deleted rows
+// inserted before the first clause following it, like insertSoftDeleteWhere does for the text nodes.
+type softDeleteClauseNode struct {
+	// Nodes are the nodes following the FROM clause of the query.
+	Nodes node.Group
+	// Where renders the WHERE clause, without arguments.
+	Where node.Node
+}
+
+// Accept implements node.Node.
+func (s *softDeleteClauseNode) Accept(translator driver.Translator, p eval.Parameter) (query string, args []any, err error) {
+	query, args, err = s.Nodes.Accept(translator, p)
+	if err != nil {
+		return "", nil, err
+	}
+	where, _, err := s.Where.Accept(translator, p)
+	if err != nil {
+		return "", nil, err
+	}
+	index, keyword := indexQueryClause(query, 0)
+	switch {
+	case keyword == "WHERE":
+		return "", nil, ErrSoftDeleteWhereRequired
+	case where == "":
+		return query, args, nil
+	case index < 0:
+		return strings.TrimSpace(query + " " + where), args, nil
+	default:
+		return strings.TrimSpace(query[:index]+" "+where) + " " + query[index:], args, nil
+	}
+}
+
+// indexQueryClause returns the index and the keyword of the first WHERE clause of text,
+// or of the first clause which follows it, like GROUP BY or ORDER BY.
+// The text starts at the parenthesis depth, the words in quotes, parentheses and placeholders are skipped.
+// It returns -1 if there is none.
+func indexQueryClause(text string, depth int) (int, string) {
+	for i := 0; i < len(text); {
+		if next := skipQuoted(text, i); next != i {
+			i = next
+			continue
+		}
+		switch c := text[i]; {
+		case c == '(' || c == '{':
+			depth++
+		case c == ')' || c == '}':
+			depth = max(depth-1, 0)
+		case depth == 0 && isKeywordStart(text, i):
+			end := i
+			for end < len(text) && isKeywordByte(text[end]) {
+				end++
+			}
+			switch keyword := strings.ToUpper(text[i:end]); keyword {
+			case "WHERE", "GROUP", "HAVING", "WINDOW", "ORDER", "LIMIT", "OFFSET", "FETCH", "FOR", "UNION", "INTERSECT", "EXCEPT":
+				return i, keyword
+			}
+			i = end
+			continue
+		}
+		i++
+	}
+	return -1, ""
+}
+
+// isKeywordStart reports whether a word starts at the index i of s.
+func isKeywordStart(s string, i int) bool {
+	return isKeywordByte(s[i]) && (i == 0 || !isKeywordByte(s[i-1]))
+}
+
+// isKeywordByte reports whether c can be part of a keyword or an identifier.
+func isKeywordByte(c byte) bool {
+	return c == '_' || c == '.' || c == '$' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// ensure softDeleteMiddleware implements Middleware.
+var _ Middleware = (*softDeleteMiddleware)(nil) // compile time check
+
+// softDeleteMiddleware rewrites the DELETE statements with the softDelete attribute
+// into UPDATE statements setting the deletion time.
+//
+//	DELETE FROM user WHERE id = ? -> UPDATE user SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?
+type softDeleteMiddleware struct{}
+
+// QueryContext implements Middleware.
+func (s *softDeleteMiddleware) QueryContext(_ *StatementContext, next QueryHandler) QueryHandler {
+	return next
+}
+
+// ExecContext implements Middleware.
+func (s *softDeleteMiddleware) ExecContext(ctx *StatementContext, next ExecHandler) ExecHandler {
+	stmt := ctx.Statement()
+	column := softDeleteColumn(stmt)
+	if column == "" || stmt.Action() != sql.Delete {
+		return next
+	}
+	return func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+		query, err := rewriteSoftDelete(query, column)
+		if err != nil {
+			return nil, err
+		}
+		return next(ctx, query, args...)
+	}
+}
+
+// rewriteSoftDelete rewrites "DELETE FROM table [alias] rest" into
+// "UPDATE table [alias] SET column = CURRENT_TIMESTAMP rest".
+func rewriteSoftDelete(query, column string) (string, error) {
+	rest, ok := cutKeyword(strings.TrimSpace(query), "DELETE")
+	if !ok {
+		return "", fmt.Errorf("%w: expected DELETE: %s", ErrSoftDeleteUnsupported, query)
+	}
+	if rest, ok = cutKeyword(rest, "FROM"); !ok {
+		return "", fmt.Errorf("%w: expected DELETE FROM: %s", ErrSoftDeleteUnsupported, query)
+	}
+	table, rest := cutWord(rest)
+	if table == "" {
+		return "", fmt.Errorf("%w: missing table: %s", ErrSoftDeleteUnsupported, query)
+	}
+	// keep the alias of the table, if any.
+	if word, afterAlias := cutWord(rest); word != "" && !isSoftDeleteClause(word) {
+		if strings.EqualFold(word, "AS") {
+			word, afterAlias = cutWord(afterAlias)
+			table += " AS"
+		}
+		table += " " + word
+		rest = afterAlias
+	}
+	if word, _ := cutWord(rest); strings.EqualFold(word, "USING") {
+		return "", fmt.Errorf("%w: DELETE USING: %s", ErrSoftDeleteUnsupported, query)
+	}
+	var builder strings.Builder
+	builder.Grow(len(query) + len(column) + 32)
+	builder.WriteString("UPDATE ")
+	builder.WriteString(table)
+	builder.WriteString(" SET ")
+	builder.WriteString(column)
+	builder.WriteString(" = CURRENT_TIMESTAMP")
+	if rest != "" {
+		builder.WriteByte(' ')
+		builder.WriteString(rest)
+	}
+	return builder.String(), nil
+}
+
+// isSoftDeleteClause reports whether word starts a clause following the table of a DELETE statement.
+func isSoftDeleteClause(word string) bool {
+	switch strings.ToUpper(word) {
+	case "WHERE", "ORDER", "LIMIT", "RETURNING", "USING":
+		return true
+	default:
+		return false
+	}
+}
+
+// cutKeyword cuts the case-insensitive keyword from the beginning of s.
+func cutKeyword(s, keyword string) (string, bool) {
+	word, rest := cutWord(s)
+	if !strings.EqualFold(word, keyword) {
+		return s, false
+	}
+	return rest, true
+}
+
+// cutWord cuts the first whitespace separated word of s, and returns it with the trimmed rest.
+func cutWord(s string) (word, rest string) {
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	end := strings.IndexFunc(s, unicode.IsSpace)
+	if end < 0 {
+		return s, ""
+	}
+	return s[:end], strings.TrimLeftFunc(s[end:], unicode.IsSpace)
+}
@@ -0,0 +1,236 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	jdriver "github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+func TestRewriteSoftDelete_soft_delete_test(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"DELETE FROM user WHERE id = ?", "UPDATE user SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?"},
+		{"  delete from user u where u.id = ?", "UPDATE user u SET deleted_at = CURRENT_TIMESTAMP where u.id = ?"},
+		{"DELETE FROM user AS u WHERE u.id = ?", "UPDATE user AS u SET deleted_at = CURRENT_TIMESTAMP WHERE u.id = ?"},
+		{"DELETE FROM user", "UPDATE user SET deleted_at = CURRENT_TIMESTAMP"},
+		{"DELETE FROM user\nWHERE id = $1 RETURNING id", "UPDATE user SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 RETURNING id"},
+	}
+	for _, tt := range tests {
+		got, err := rewriteSoftDelete(tt.query, "deleted_at")
+		if err != nil || got != tt.want {
+			t.Errorf("rewriteSoftDelete(%q) = %q, %v, want %q", tt.query, got, err, tt.want)
+		}
+	}
+
+	for _, query := range []string{"UPDATE user SET a = 1", "DELETE user", "DELETE FROM", "DELETE FROM a USING b WHERE a.id = b.id"} {
+		if _, err := rewriteSoftDelete(query, "deleted_at"); !errors.Is(err, ErrSoftDeleteUnsupported) {
+			t.Errorf("rewriteSoftDelete(%q): expected ErrSoftDeleteUnsupported, got %v", query, err)
+		}
+	}
+}
+
+func TestSoftDeleteMiddleware_soft_delete_test(t *testing.T) {
+	engine := newStatementTestEngine(nil)
+	var executed string
+	next := func(_ context.Context, query string, _ ...any) (jsql.Result, error) {
+		executed = query
+		return nil, nil
+	}
+	middleware := &softDeleteMiddleware{}
+
+	stmt := shStatement{action: jsql.Delete, attrs: map[string]string{"softDelete": "removed_at"}}
+	handler := middleware.ExecContext(newStatementContext(context.Background(), engine, stmt, nil, nil), next)
+	if _, err := handler(context.Background(), "DELETE FROM user WHERE id = ?", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if executed != "UPDATE user SET removed_at = CURRENT_TIMESTAMP WHERE id = ?" {
+		t.Fatalf("unexpected rewritten query %q", executed)
+	}
+
+	for _, attrs := range []map[string]string{{}, {"softDelete": "false"}} {
+		stmt = shStatement{action: jsql.Delete, attrs: attrs}
+		handler = middleware.ExecContext(newStatementContext(context.Background(), engine, stmt, nil, nil), next)
+		if _, err := handler(context.Background(), "DELETE FROM user"); err != nil || executed != "DELETE FROM user" {
+			t.Fatalf("expected query to be kept, got %q err=%v", executed, err)
+		}
+	}
+}
+
+func TestSoftDeleteConfiguration_soft_delete_test(t *testing.T) {
+	newConfiguration := func(mapper string) (Configuration, error) {
+		fsys := fstest.MapFS{
+			"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>sqlite.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>` + mapper + `</mappers>
+</configuration>`)},
+		}
+		return NewXMLConfigurationWithFS(fsys, "juice.xml")
+	}
+
+	configuration, err := newConfiguration(`
+		<mapper namespace="user" softDelete="deleted_at">
+			<select id="Find">SELECT * FROM user <where><if test="id > 0">AND id = #{id}</if></where> ORDER BY id</select>
+			<select id="Count" softDelete="false">SELECT COUNT(*) FROM user</select>
+		</mapper>`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	statement, err := configuration.GetStatement("user.Find")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	translator := jdriver.SQLiteDriver{}.Translator()
+	query, _, err := statement.Build(translator, eval.NewGenericParam(H{"id": 1}, ""))
+	if err != nil || query != "SELECT * FROM user WHERE (id = ?) AND deleted_at IS NULL  ORDER BY id" {
+		t.Fatalf("unexpected query %q err=%v", query, err)
+	}
+	query, _, err = statement.Build(translator, eval.NewGenericParam(H{"id": 0, "includeDeleted": true}, ""))
+	if err != nil || query != "SELECT * FROM user  ORDER BY id" {
+		t.Fatalf("unexpected query %q err=%v", query, err)
+	}
+
+	// a DELETE statement which can not be rewritten fails at load.
+	_, err = newConfiguration(`
+		<mapper namespace="user" softDelete="deleted_at">
+			<delete id="Purge">DELETE FROM user USING team WHERE user.team_id = team.id</delete>
+		</mapper>`)
+	if !errors.Is(err, ErrSoftDeleteUnsupported) {
+		t.Fatalf("expected ErrSoftDeleteUnsupported, got %v", err)
+	}
+
+	// a WHERE written as plain text can not be combined with the filter.
+	_, err = newConfiguration(`
+		<mapper namespace="user" softDelete="deleted_at">
+			<select id="Count">SELECT COUNT(*) FROM user WHERE id = 1</select>
+		</mapper>`)
+	if !errors.Is(err, ErrSoftDeleteWhereRequired) {
+		t.Fatalf("expected ErrSoftDeleteWhereRequired, got %v", err)
+	}
+}
+
+func TestSoftDeleteWithoutWhere_soft_delete_test(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>sqlite.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>
+		<mapper namespace="user" softDelete="deleted_at">
+			<select id="Count">SELECT COUNT(*) FROM user</select>
+			<select id="List">SELECT * FROM user u JOIN (SELECT id FROM team WHERE active = 1 ORDER BY id) t ON t.id = u.team_id ORDER BY u.id LIMIT #{limit}</select>
+			<select id="Sorted">SELECT * FROM user <orderBy param="sort" allowed="id, name" default="id"/></select>
+		</mapper>
+	</mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	translator := jdriver.SQLiteDriver{}.Translator()
+	tests := []struct {
+		id    string
+		param H
+		query string
+	}{
+		{"user.Count", H{}, "SELECT COUNT(*) FROM user WHERE deleted_at IS NULL"},
+		{"user.Count", H{"includeDeleted": true}, "SELECT COUNT(*) FROM user "},
+		{"user.List", H{"limit": 10}, "SELECT * FROM user u JOIN (SELECT id FROM team WHERE active = 1 ORDER BY id) t ON t.id = u.team_id WHERE deleted_at IS NULL ORDER BY u.id LIMIT ?"},
+		{"user.Sorted", H{}, "SELECT * FROM user WHERE deleted_at IS NULL ORDER BY id"},
+	}
+	for _, tt := range tests {
+		statement, err := configuration.GetStatement(tt.id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		query, _, err := statement.Build(translator, eval.NewGenericParam(tt.param, ""))
+		if err != nil || query != tt.query {
+			t.Errorf("%s: unexpected query %q err=%v, want %q", tt.id, query, err, tt.query)
+		}
+	}
+}
+
+func TestSoftDeleteNestedWhere_soft_delete_test(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>sqlite.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>
+		<mapper namespace="user" softDelete="deleted_at">
+			<sql id="byName"><where><if test='name != ""'>name = #{name}</if></where></sql>
+			<sql id="sorted">ORDER BY id LIMIT #{limit}</sql>
+			<select id="Subquery">SELECT * FROM user u JOIN (SELECT id FROM team <where>name = #{name}</where>) t ON t.id = u.team_id <where>u.active = 1</where></select>
+			<select id="SubqueryOnly">SELECT * FROM user u JOIN (SELECT id FROM team <where>name = #{name}</where>) t ON t.id = u.team_id</select>
+			<select id="Nested">SELECT * FROM user <if test='name != ""'><where>name = #{name}</where></if></select>
+			<select id="Included">SELECT * FROM user <include refid="byName"/></select>
+			<select id="IfOrderBy">SELECT * FROM user <if test='limit > 0'>ORDER BY id LIMIT #{limit}</if></select>
+			<select id="IncludeOrderBy">SELECT * FROM user <include refid="sorted"/></select>
+			<select id="Raw" softDelete="false">SELECT * FROM user <include refid="byName"/></select>
+		</mapper>
+	</mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	translator := jdriver.SQLiteDriver{}.Translator()
+	tests := []struct {
+		id    string
+		param H
+		query string
+		err   error
+	}{
+		// only the <where> of the outer query is rewritten.
+		{id: "user.Subquery", param: H{"name": "a"}, query: "SELECT * FROM user u JOIN (SELECT id FROM team WHERE name = ? ) t ON t.id = u.team_id WHERE (u.active = 1) AND deleted_at IS NULL"},
+		{id: "user.SubqueryOnly", param: H{"name": "a"}, query: "SELECT * FROM user u JOIN (SELECT id FROM team WHERE name = ? ) t ON t.id = u.team_id WHERE deleted_at IS NULL"},
+		{id: "user.Nested", param: H{"name": "a"}, err: ErrSoftDeleteWhereRequired},
+		{id: "user.Included", param: H{"name": "a"}, err: ErrSoftDeleteWhereRequired},
+		{id: "user.Included", param: H{"name": ""}, query: "SELECT * FROM user WHERE deleted_at IS NULL"},
+		// the WHERE clause is inserted before the clauses rendered by the child elements.
+		{id: "user.IfOrderBy", param: H{"limit": 10}, query: "SELECT * FROM user WHERE deleted_at IS NULL ORDER BY id LIMIT ?"},
+		{id: "user.IfOrderBy", param: H{"limit": 0}, query: "SELECT * FROM user WHERE deleted_at IS NULL"},
+		{id: "user.IncludeOrderBy", param: H{"limit": 10}, query: "SELECT * FROM user WHERE deleted_at IS NULL ORDER BY id LIMIT ?"},
+		{id: "user.IncludeOrderBy", param: H{"limit": 10, "includeDeleted": true}, query: "SELECT * FROM user ORDER BY id LIMIT ?"},
+		// the fragment shared with the other statements is kept as it is.
+		{id: "user.Raw", param: H{"name": "a"}, query: "SELECT * FROM user WHERE name = ?"},
+	}
+	for _, tt := range tests {
+		statement, err := configuration.GetStatement(tt.id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		query, _, err := statement.Build(translator, eval.NewGenericParam(tt.param, ""))
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("%s: expected %v, got %v", tt.id, tt.err, err)
+			}
+			continue
+		}
+		if err != nil || query != tt.query {
+			t.Errorf("%s: unexpected query %q err=%v, want %q", tt.id, query, err, tt.query)
+		}
+	}
+}
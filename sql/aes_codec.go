@@ -0,0 +1,148 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// KeyProvider provides the AES keys of a field codec, for example from a key management service.
+// The keys have an id stored with the values they encrypt, so that they can be rotated.
+type KeyProvider interface {
+	// CurrentKey returns the key used to encrypt the values and its id.
+	CurrentKey() (id string, key []byte, err error)
+
+	// Key returns the key with the given id, used to decrypt the values encrypted with it.
+	Key(id string) ([]byte, error)
+}
+
+// errKeyNotFound is returned by the static key provider for the unknown key ids.
+var errKeyNotFound = errors.New("key not found")
+
+// staticKeyProvider is the KeyProvider returned by NewStaticKeyProvider.
+type staticKeyProvider struct {
+	id  string
+	key []byte
+}
+
+// CurrentKey implements KeyProvider.
+func (s staticKeyProvider) CurrentKey() (string, []byte, error) {
+	return s.id, s.key, nil
+}
+
+// Key implements KeyProvider.
+func (s staticKeyProvider) Key(id string) ([]byte, error) {
+	if id != s.id {
+		return nil, fmt.Errorf("%w: %s", errKeyNotFound, id)
+	}
+	return s.key, nil
+}
+
+// NewStaticKeyProvider returns a KeyProvider with a single key.
+func NewStaticKeyProvider(id string, key []byte) KeyProvider {
+	return staticKeyProvider{id: id, key: key}
+}
+
+// aesFieldCodec is the FieldCodec returned by NewAESFieldCodec.
+type aesFieldCodec struct {
+	keys          KeyProvider
+	deterministic bool
+}
+
+// NewAESFieldCodec returns a FieldCodec encrypting the values with AES-GCM and
+// the keys of the provider, which must be 16, 24 or 32 bytes long.
+//
+// The values are encrypted with random nonces, unless deterministic is true:
+// the nonces are then derived from the values, so that equal values have equal
+// ciphertexts and the encrypted columns can be looked up by equality, at the cost
+// of revealing which values are equal.
+//
+// The ciphertext is made of the length of the key id, the key id, the nonce and the sealed value.
+func NewAESFieldCodec(keys KeyProvider, deterministic bool) FieldCodec {
+	return &aesFieldCodec{keys: keys, deterministic: deterministic}
+}
+
+// Encrypt implements FieldCodec.
+func (a *aesFieldCodec) Encrypt(plaintext []byte) ([]byte, error) {
+	id, key, err := a.keys.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	if len(id) > math.MaxUint8 {
+		return nil, fmt.Errorf("key id %q is longer than %d bytes", id, math.MaxUint8)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if a.deterministic {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(id))
+		mac.Write(plaintext)
+		copy(nonce, mac.Sum(nil))
+	} else if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	ciphertext := make([]byte, 0, 1+len(id)+len(nonce)+len(plaintext)+aead.Overhead())
+	ciphertext = append(ciphertext, byte(len(id)))
+	ciphertext = append(ciphertext, id...)
+	ciphertext = append(ciphertext, nonce...)
+	return aead.Seal(ciphertext, nonce, plaintext, []byte(id)), nil
+}
+
+// Decrypt implements FieldCodec.
+func (a *aesFieldCodec) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) == 0 || len(ciphertext) < 1+int(ciphertext[0]) {
+		return nil, ErrInvalidCiphertext
+	}
+	id := string(ciphertext[1 : 1+ciphertext[0]])
+	ciphertext = ciphertext[1+len(id):]
+	key, err := a.keys.Key(id)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCiphertext, err)
+	}
+	return plaintext, nil
+}
+
+// newGCM returns the AES-GCM cipher of key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
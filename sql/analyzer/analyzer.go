@@ -0,0 +1,281 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package analyzer extracts the tables and the columns referenced by SQL statements,
+// for impact analysis when a schema changes or to invalidate caches by table.
+//
+// The analysis is lexical: it does not validate queries and does not depend on a dialect,
+// so it can analyze rendered queries as well as the skeletons of juice statements,
+// whose markup, like <if test="id > 0">, is skipped. A column whose table is ambiguous,
+// like an unqualified column of a join, is reported without table.
+package analyzer
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Column is a column referenced by a query.
+type Column struct {
+	// Table is the table of the column, or empty when it is ambiguous.
+	Table string
+
+	// Name is the name of the column.
+	Name string
+}
+
+// Analysis holds the tables and the columns referenced by a query.
+type Analysis struct {
+	// Tables holds the tables read or written by the query, sorted.
+	Tables []string
+
+	// WrittenTables holds the tables inserted into, updated or deleted from by the query, sorted.
+	WrittenTables []string
+
+	// Columns holds the columns referenced by the query, sorted by table and name.
+	Columns []Column
+}
+
+// References reports whether the query references table, ignoring case.
+func (a *Analysis) References(table string) bool {
+	return slices.ContainsFunc(a.Tables, func(name string) bool { return strings.EqualFold(name, table) })
+}
+
+// Writes reports whether the query writes table, ignoring case.
+func (a *Analysis) Writes(table string) bool {
+	return slices.ContainsFunc(a.WrittenTables, func(name string) bool { return strings.EqualFold(name, table) })
+}
+
+// Merge returns the union of a and other, like the analysis of the variants of a statement.
+func (a *Analysis) Merge(other *Analysis) *Analysis {
+	merged := &Analysis{
+		Tables:        slices.Concat(a.Tables, other.Tables),
+		WrittenTables: slices.Concat(a.WrittenTables, other.WrittenTables),
+		Columns:       slices.Concat(a.Columns, other.Columns),
+	}
+	merged.normalize()
+	return merged
+}
+
+// normalize sorts the tables and the columns of the analysis and removes the duplicates.
+func (a *Analysis) normalize() {
+	slices.Sort(a.Tables)
+	a.Tables = slices.Compact(a.Tables)
+	slices.Sort(a.WrittenTables)
+	a.WrittenTables = slices.Compact(a.WrittenTables)
+	slices.SortFunc(a.Columns, func(x, y Column) int {
+		return cmp.Or(cmp.Compare(x.Table, y.Table), cmp.Compare(x.Name, y.Name))
+	})
+	a.Columns = slices.Compact(a.Columns)
+}
+
+// clause is the clause of a query being analyzed, telling how to interpret its identifiers.
+type clause uint8
+
+const (
+	// expressionClause holds expressions, whose identifiers are columns.
+	expressionClause clause = iota
+	// selectClause is the select list, whose identifiers are columns or their aliases.
+	selectClause
+	// fromClause holds the tables and their aliases.
+	fromClause
+	// columnListClause is the list of the columns of an insert.
+	columnListClause
+)
+
+// reference is a column referenced by a query, before its qualifier is resolved to a table.
+type reference struct {
+	qualifier string
+	name      string
+}
+
+// analyzer is the state of the analysis of a query.
+type analyzer struct {
+	tables     map[string]bool
+	written    map[string]bool
+	aliases    map[string]string
+	ctes       map[string]bool
+	references []reference
+}
+
+// Analyze returns the tables and the columns referenced by query.
+func Analyze(query string) *Analysis {
+	a := &analyzer{
+		tables:  make(map[string]bool),
+		written: make(map[string]bool),
+		aliases: make(map[string]string),
+		ctes:    make(map[string]bool),
+	}
+	a.analyze(tokenize(query))
+	return a.result()
+}
+
+// analyze walks tokens, recording the tables, the aliases and the column references.
+func (a *analyzer) analyze(tokens []token) {
+	var (
+		current     = expressionClause
+		scopes      []clause
+		expectTable bool
+		write       bool
+		lastTable   string
+		insertTable string
+	)
+	at := func(i int) token {
+		if i < 0 || i >= len(tokens) {
+			return token{kind: symbolToken}
+		}
+		return tokens[i]
+	}
+	for i, t := range tokens {
+		prev, next := at(i-1), at(i+1)
+		switch t.kind {
+		case keywordToken:
+			switch t.text {
+			case "SELECT":
+				current, expectTable = selectClause, false
+			case "FROM", "JOIN":
+				current, expectTable = fromClause, true
+			case "INTO":
+				current, expectTable, write = fromClause, true, true
+			case "DELETE":
+				write = true
+			case "UPDATE":
+				// ON DUPLICATE KEY UPDATE and ON CONFLICT DO UPDATE update columns.
+				if prev.kind == keywordToken && (prev.text == "KEY" || prev.text == "DO") {
+					current = expressionClause
+					continue
+				}
+				current, expectTable, write = fromClause, true, true
+			case "USING":
+				if next.text == "(" {
+					current = expressionClause
+				} else {
+					expectTable = true
+				}
+			case "SET", "WHERE", "ON", "HAVING", "BY", "RETURNING", "VALUES", "LIMIT", "OFFSET":
+				current, expectTable = expressionClause, false
+			}
+		case symbolToken:
+			switch t.text {
+			case "(":
+				scopes = append(scopes, current)
+				if expectTable {
+					// a subquery or a table function.
+					expectTable = false
+				} else if insertTable != "" && prev.kind == identifierToken && prev.text == insertTable {
+					current = columnListClause
+				}
+			case ")":
+				if len(scopes) > 0 {
+					current, scopes = scopes[len(scopes)-1], scopes[:len(scopes)-1]
+				}
+				lastTable = ""
+			case ",":
+				if current == fromClause {
+					expectTable = true
+				}
+			}
+		case identifierToken:
+			switch {
+			case prev.text == "::":
+				// a type cast.
+			case next.kind == keywordToken && next.text == "AS" && at(i+2).text == "(":
+				// name AS ( declares a common table expression.
+				a.ctes[t.text] = true
+			case prev.kind == keywordToken && prev.text == "AS":
+				if current == fromClause && lastTable != "" {
+					a.aliases[t.text] = lastTable
+				}
+			case expectTable:
+				if !a.ctes[t.text] {
+					a.tables[t.text] = true
+					if write {
+						a.written[t.text] = true
+					}
+				}
+				if write && at(i-1).text == "INTO" {
+					insertTable = t.text
+				}
+				lastTable, expectTable, write = t.text, false, false
+			case next.text == "(" && current != columnListClause:
+				// a function call.
+			case current == fromClause:
+				if lastTable != "" {
+					a.aliases[t.text] = lastTable
+				}
+			case current == selectClause && (prev.kind == identifierToken || prev.kind == valueToken ||
+				prev.text == ")" || prev.text == "END"):
+				// an alias without AS.
+			case current == columnListClause:
+				a.references = append(a.references, reference{qualifier: insertTable, name: t.parts[len(t.parts)-1]})
+			default:
+				a.reference(t, insertTable)
+			}
+		}
+	}
+}
+
+// reference records the column referenced by identifier t.
+// The excluded pseudo table of an upsert is resolved to insertTable.
+func (a *analyzer) reference(t token, insertTable string) {
+	name := t.parts[len(t.parts)-1]
+	if name == "*" {
+		return
+	}
+	qualifier := strings.Join(t.parts[:len(t.parts)-1], ".")
+	if strings.EqualFold(qualifier, "excluded") && insertTable != "" {
+		qualifier = insertTable
+	}
+	a.references = append(a.references, reference{qualifier: qualifier, name: name})
+}
+
+// result resolves the column references and returns the analysis.
+func (a *analyzer) result() *Analysis {
+	analysis := &Analysis{}
+	for table := range a.tables {
+		analysis.Tables = append(analysis.Tables, unquote(table))
+	}
+	for table := range a.written {
+		analysis.WrittenTables = append(analysis.WrittenTables, unquote(table))
+	}
+	for _, reference := range a.references {
+		table := reference.qualifier
+		if aliased, ok := a.aliases[table]; ok {
+			table = aliased
+		}
+		switch {
+		case a.ctes[table]:
+			continue
+		case table == "" && len(a.tables) == 1:
+			table = analysis.Tables[0]
+		default:
+			table = unquote(table)
+		}
+		analysis.Columns = append(analysis.Columns, Column{Table: table, Name: reference.name})
+	}
+	analysis.normalize()
+	return analysis
+}
+
+// unquote removes the quotes of the parts of a qualified identifier.
+func unquote(identifier string) string {
+	if !strings.ContainsAny(identifier, "\"`[") {
+		return identifier
+	}
+	t, _ := scanIdentifier(identifier, 0)
+	return strings.Join(t.parts, ".")
+}
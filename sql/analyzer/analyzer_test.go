@@ -0,0 +1,111 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		tables  []string
+		written []string
+		columns []Column
+	}{
+		{
+			name:    "select",
+			query:   "SELECT id, name AS username, COUNT(*) total FROM user WHERE status = ? AND created_at > $1 ORDER BY id",
+			tables:  []string{"user"},
+			columns: []Column{{"user", "created_at"}, {"user", "id"}, {"user", "name"}, {"user", "status"}},
+		},
+		{
+			name: "join with aliases",
+			query: `SELECT u.id, o.total FROM "user" u LEFT JOIN orders AS o ON o.user_id = u.id
+				WHERE u.id IN (SELECT user_id FROM vip) AND o.status = 'paid' -- paid only`,
+			tables:  []string{"orders", "user", "vip"},
+			columns: []Column{{"", "user_id"}, {"orders", "status"}, {"orders", "total"}, {"orders", "user_id"}, {"user", "id"}},
+		},
+		{
+			name:    "insert with upsert",
+			query:   "INSERT INTO user (id, name) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name",
+			tables:  []string{"user"},
+			written: []string{"user"},
+			columns: []Column{{"user", "id"}, {"user", "name"}},
+		},
+		{
+			name:    "update",
+			query:   "UPDATE `order` SET amount = amount * 2, updated_at = NOW() WHERE id = :id",
+			tables:  []string{"order"},
+			written: []string{"order"},
+			columns: []Column{{"order", "amount"}, {"order", "id"}, {"order", "updated_at"}},
+		},
+		{
+			name:    "delete",
+			query:   "DELETE FROM session WHERE expires_at::timestamp < now()",
+			tables:  []string{"session"},
+			written: []string{"session"},
+			columns: []Column{{"session", "expires_at"}},
+		},
+		{
+			name:    "common table expression",
+			query:   "WITH recent AS (SELECT user_id FROM login WHERE at > ?) SELECT r.user_id FROM recent r",
+			tables:  []string{"login"},
+			columns: []Column{{"login", "at"}, {"login", "user_id"}},
+		},
+		{
+			name: "skeleton",
+			query: `SELECT <include refid="columns"/> FROM user <where><if test="id > 0">AND id = #{id}</if>` +
+				`<foreach collection="names" item="name" open="AND name IN (" separator="," close=")">#{name}</foreach></where>`,
+			tables:  []string{"user"},
+			columns: []Column{{"user", "id"}},
+		},
+		{
+			name: "with skeleton",
+			query: `<with recursive="true"><cte name="recent">SELECT user_id FROM login WHERE at > #{at}</cte></with>` +
+				` SELECT r.user_id FROM recent r`,
+			tables:  []string{"login"},
+			columns: []Column{{"login", "at"}, {"login", "user_id"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := Analyze(tt.query)
+			if !reflect.DeepEqual(analysis.Tables, tt.tables) {
+				t.Errorf("Tables = %v, want %v", analysis.Tables, tt.tables)
+			}
+			if !reflect.DeepEqual(analysis.WrittenTables, tt.written) {
+				t.Errorf("WrittenTables = %v, want %v", analysis.WrittenTables, tt.written)
+			}
+			if !reflect.DeepEqual(analysis.Columns, tt.columns) {
+				t.Errorf("Columns = %v, want %v", analysis.Columns, tt.columns)
+			}
+		})
+	}
+}
+
+func TestAnalysisMerge(t *testing.T) {
+	merged := Analyze("SELECT id FROM user").Merge(Analyze("UPDATE User SET id = 1"))
+	if want := []string{"User", "user"}; !reflect.DeepEqual(merged.Tables, want) {
+		t.Fatalf("Tables = %v, want %v", merged.Tables, want)
+	}
+	if !merged.References("USER") || !merged.Writes("user") || merged.References("orders") {
+		t.Fatalf("unexpected references of %+v", merged)
+	}
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyzer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind is the kind of a SQL token.
+type tokenKind uint8
+
+const (
+	// identifierToken is a possibly qualified and quoted identifier, like "user".id.
+	identifierToken tokenKind = iota
+	// keywordToken is an unquoted reserved word, upper cased.
+	keywordToken
+	// valueToken is a literal or a parameter, like 'name', 42, ?, $1, :name or #{name}.
+	valueToken
+	// symbolToken is an operator or a punctuation, like ( or ::.
+	symbolToken
+)
+
+// token is a SQL token. The parts of an identifier are unquoted.
+type token struct {
+	kind  tokenKind
+	text  string
+	parts []string
+}
+
+// markupElements are the elements of the statement skeletons of juice,
+// which are skipped like whitespace.
+var markupElements = map[string]bool{
+	"if": true, "where": true, "set": true, "trim": true, "foreach": true, "choose": true,
+	"when": true, "otherwise": true, "bind": true, "include": true, "property": true,
+	"alias": true, "values": true, "onConflict": true, "onDuplicateKey": true, "orderBy": true, "columns": true,
+	"with": true, "cte": true,
+}
+
+// tokenize splits query into tokens, skipping whitespace, comments and markup.
+func tokenize(query string) []token {
+	var tokens []token
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(query[i:], "--"):
+			i = skipUntil(query, i, "\n")
+		case strings.HasPrefix(query[i:], "/*"):
+			i = skipUntil(query, i+2, "*/")
+		case c == '<' && isMarkup(query[i+1:]):
+			end := skipMarkup(query, i+1)
+			switch element := markupElement(query[i+1:]); {
+			case element == "where" || element == "set" || element == "with":
+				// <where>, <set> and <with> render the keyword they are named after.
+				tokens = append(tokens, token{kind: keywordToken, text: strings.ToUpper(element)})
+			case element == "cte":
+				// <cte name="x"> renders "x AS (" and </cte> the closing parenthesis.
+				if name := markupAttribute(query[i:end], "name"); name != "" {
+					tokens = append(tokens, token{kind: identifierToken, text: name},
+						token{kind: keywordToken, text: "AS"}, token{kind: symbolToken, text: "("})
+				}
+			case strings.HasPrefix(query[i+1:], "/cte"):
+				tokens = append(tokens, token{kind: symbolToken, text: ")"})
+			}
+			i = end
+		case (c == '#' || c == '$') && strings.HasPrefix(query[i+1:], "{"):
+			end := skipUntil(query, i, "}")
+			tokens = append(tokens, token{kind: valueToken, text: query[i:end]})
+			i = end
+		case c == '\'':
+			end := skipQuoted(query, i, '\'')
+			tokens = append(tokens, token{kind: valueToken, text: query[i:end]})
+			i = end
+		case c >= '0' && c <= '9' || c == '?' || c == '$':
+			end := i + 1
+			for end < len(query) && isIdentifierByte(query[end]) {
+				end++
+			}
+			tokens = append(tokens, token{kind: valueToken, text: query[i:end]})
+			i = end
+		case c == ':' && i+1 < len(query) && query[i+1] == ':':
+			tokens = append(tokens, token{kind: symbolToken, text: "::"})
+			i += 2
+		case c == ':' && i+1 < len(query) && isIdentifierStart(query[i+1:]):
+			end := i + 1
+			for end < len(query) && isIdentifierByte(query[end]) {
+				end++
+			}
+			tokens = append(tokens, token{kind: valueToken, text: query[i:end]})
+			i = end
+		case c == '"' || c == '`' || c == '[' || isIdentifierStart(query[i:]):
+			var t token
+			t, i = scanIdentifier(query, i)
+			tokens = append(tokens, t)
+		default:
+			_, size := utf8.DecodeRuneInString(query[i:])
+			tokens = append(tokens, token{kind: symbolToken, text: query[i : i+size]})
+			i += size
+		}
+	}
+	return tokens
+}
+
+// scanIdentifier scans the possibly qualified identifier or keyword starting at i.
+func scanIdentifier(query string, i int) (token, int) {
+	start := i
+	var parts []string
+	quoted := false
+	for {
+		var part string
+		switch c := query[i]; c {
+		case '"', '`', '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			end := skipQuoted(query, i, closing)
+			part = strings.Trim(query[i:end], string([]byte{c, closing}))
+			i, quoted = end, true
+		case '*':
+			part = "*"
+			i++
+		default:
+			end := i
+			for end < len(query) {
+				r, size := utf8.DecodeRuneInString(query[end:])
+				if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '$' {
+					break
+				}
+				end += size
+			}
+			part = query[i:end]
+			i = end
+		}
+		parts = append(parts, part)
+		if i+1 >= len(query) || query[i] != '.' || !(query[i+1] == '*' || query[i+1] == '"' ||
+			query[i+1] == '`' || query[i+1] == '[' || isIdentifierStart(query[i+1:])) {
+			break
+		}
+		i++
+	}
+	if len(parts) == 1 && !quoted {
+		if upper := strings.ToUpper(parts[0]); keywords[upper] {
+			return token{kind: keywordToken, text: upper}, i
+		}
+	}
+	return token{kind: identifierToken, text: query[start:i], parts: parts}, i
+}
+
+// isIdentifierStart reports whether s starts with the first character of an unquoted identifier.
+func isIdentifierStart(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsLetter(r) || r == '_'
+}
+
+// isIdentifierByte reports whether c can be part of an unquoted identifier or number.
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// isMarkup reports whether s, following a <, is a juice element or its end.
+func isMarkup(s string) bool {
+	return markupElements[markupElement(strings.TrimPrefix(s, "/"))]
+}
+
+// markupElement returns the name of the element s starts with, following a <.
+func markupElement(s string) string {
+	end := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) })
+	if end < 0 {
+		end = len(s)
+	}
+	return s[:end]
+}
+
+// markupAttribute returns the value of the attribute name of the element markup, or an empty string.
+func markupAttribute(markup, name string) string {
+	for i := 0; i < len(markup); i++ {
+		if !strings.HasPrefix(markup[i:], name+"=") || (i > 0 && markup[i-1] != ' ') {
+			continue
+		}
+		value := markup[i+len(name)+1:]
+		if value == "" || (value[0] != '"' && value[0] != '\'') {
+			return ""
+		}
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return value[1 : end+1]
+		}
+		return ""
+	}
+	return ""
+}
+
+// skipMarkup returns the index following the element starting at i, skipping its quoted attributes.
+func skipMarkup(query string, i int) int {
+	for i < len(query) {
+		switch query[i] {
+		case '"', '\'':
+			i = skipQuoted(query, i, query[i])
+		case '>':
+			return i + 1
+		default:
+			i++
+		}
+	}
+	return i
+}
+
+// skipQuoted returns the index following the quoted text starting at i,
+// where a doubled closing quote is an escaped quote.
+func skipQuoted(query string, i int, closing byte) int {
+	for i++; i < len(query); i++ {
+		if query[i] != closing {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == closing && closing != ']' {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return i
+}
+
+// skipUntil returns the index following the first end found from i, or the length of query.
+func skipUntil(query string, i int, end string) int {
+	if index := strings.Index(query[i:], end); index >= 0 {
+		return i + index + len(end)
+	}
+	return len(query)
+}
+
+// keywords are the reserved words which are not identifiers unless they are quoted.
+var keywords = map[string]bool{}
+
+func init() {
+	for keyword := range strings.FieldsSeq(`
+		ALL AND ANY AS ASC BETWEEN BY CASE CONFLICT CROSS DEFAULT DELETE DESC DISTINCT DO DUPLICATE
+		ELSE END ESCAPE EXCEPT EXISTS FALSE FETCH FIRST FOR FROM FULL GROUP HAVING IGNORE ILIKE IN INNER
+		INSERT INTERSECT INTO IS JOIN KEY LAST LATERAL LEFT LIKE LIMIT LOCK NATURAL NEXT NOT NOTHING NULL
+		NULLS OFFSET ON ONLY OR ORDER OUTER RECURSIVE RETURNING RIGHT ROW ROWS SELECT SET SHARE SOME THEN
+		TRUE UNION UPDATE USING VALUES WHEN WHERE WITH`) {
+		keywords[keyword] = true
+	}
+}
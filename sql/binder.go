@@ -18,13 +18,14 @@ package sql
 
 import (
 	"database/sql"
+	"fmt"
 	"reflect"
+	"slices"
 	"time"
 )
 
 var (
 	// scannerType is the reflect.Type of sql.Scanner
-	// nolint:unused
 	scannerType = reflect.TypeFor[sql.Scanner]()
 
 	// timeType is the reflect.Type of time.Time
@@ -146,3 +147,123 @@ func List2[T any](rows Rows) ([]*T, error) {
 	}
 	return result, nil
 }
+
+// BindMap converts Rows into a map keyed by the value of keyColumn.
+// The key column is scanned into K and, for struct values, also into the field
+// it maps to. For non-struct values the rows must have exactly one column besides keyColumn.
+// It returns ErrDuplicateKey when two rows share the same key.
+// Rows is not closed by this function.
+//
+// Example:
+//
+//	users, err := BindMap[int64, User](rows, "id")
+func BindMap[K comparable, V any](rows Rows, keyColumn string) (map[K]V, error) {
+	result := make(map[K]V, rowsCapacity(rows))
+	err := bindKeyed(rows, keyColumn, func(key K, value V) error {
+		if _, exists := result[key]; exists {
+			return fmt.Errorf("%w: %v", ErrDuplicateKey, key)
+		}
+		result[key] = value
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// BindGrouped converts Rows into a map of slices grouped by the value of keyColumn.
+// Rows sharing a key keep their original order inside the group.
+// Rows is not closed by this function.
+//
+// Example:
+//
+//	orders, err := BindGrouped[int64, Order](rows, "user_id")
+func BindGrouped[K comparable, V any](rows Rows, keyColumn string) (map[K][]V, error) {
+	result := make(map[K][]V)
+	err := bindKeyed(rows, keyColumn, func(key K, value V) error {
+		result[key] = append(result[key], value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// bindKeyed scans every row into a key of type K and a value of type V and hands them to collect.
+func bindKeyed[K comparable, V any](rows Rows, keyColumn string, collect func(K, V) error) error {
+	if rows == nil {
+		return ErrNilRows
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("failed to get columns: %w", err)
+	}
+	keyIndex := slices.Index(columns, keyColumn)
+	if keyIndex < 0 {
+		return fmt.Errorf("%w: %q", ErrKeyColumnNotFound, keyColumn)
+	}
+
+	valueType := reflect.TypeFor[V]()
+	elemType := valueType
+	if elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	isStruct := elemType.Kind() == reflect.Struct && elemType != timeType && !reflect.PointerTo(elemType).Implements(scannerType)
+
+	// non-struct values are scanned from the only column left besides the key.
+	valueColumns := columns
+	if !isStruct {
+		valueColumns = slices.Delete(slices.Clone(columns), keyIndex, keyIndex+1)
+		if len(valueColumns) != 1 {
+			return fmt.Errorf("expected exactly one value column besides %q, but got %d", keyColumn, len(valueColumns))
+		}
+	}
+
+	columnDest := newRowDestination(rows)
+	for rows.Next() {
+		newValue := reflect.New(elemType)
+		dest, err := columnDest.Destination(newValue, valueColumns)
+		if err != nil {
+			return fmt.Errorf("failed to get destination: %w", err)
+		}
+		var key K
+		if isStruct {
+			dest[keyIndex] = &keyScanner{key: &key, dest: dest[keyIndex]}
+		} else {
+			dest = slices.Insert(dest, keyIndex, any(&keyScanner{key: &key}))
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		if valueType.Kind() != reflect.Pointer {
+			newValue = newValue.Elem()
+		}
+		value, _ := reflect.TypeAssert[V](newValue)
+		if err = collect(key, value); err != nil {
+			return err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error occurred while iterating rows: %w", err)
+	}
+	return nil
+}
+
+// keyScanner scans the key column into the map key and forwards it to the original destination.
+type keyScanner struct {
+	key  any
+	dest any
+}
+
+// Scan implements sql.Scanner.
+func (k *keyScanner) Scan(src any) error {
+	if err := convertAssign(k.key, src); err != nil {
+		return err
+	}
+	if k.dest == nil {
+		return nil
+	}
+	return convertAssign(k.dest, src)
+}
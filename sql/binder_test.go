@@ -323,3 +323,101 @@ func TestIter_binder_test(t *testing.T) {
 		}
 	})
 }
+
+func TestBindMap_binder_test(t *testing.T) {
+	t.Run("Struct", func(t *testing.T) {
+		rows := &RowsBuffer{
+			ColumnsLine: []string{"id", "name"},
+			Data:        [][]any{{int64(1), "Alice"}, {int64(2), "Bob"}},
+		}
+		users, err := BindMap[int64, TestUser](rows, "id")
+		if err != nil {
+			t.Fatalf("BindMap failed: %v", err)
+		}
+		if len(users) != 2 || users[1].Name != "Alice" || users[2].ID != 2 {
+			t.Errorf("unexpected result: %+v", users)
+		}
+	})
+
+	t.Run("PointerStruct", func(t *testing.T) {
+		rows := &RowsBuffer{
+			ColumnsLine: []string{"id", "name"},
+			Data:        [][]any{{int64(1), "Alice"}},
+		}
+		users, err := BindMap[string, *TestUser](rows, "name")
+		if err != nil {
+			t.Fatalf("BindMap failed: %v", err)
+		}
+		if user := users["Alice"]; user == nil || user.ID != 1 {
+			t.Errorf("unexpected result: %+v", users)
+		}
+	})
+
+	t.Run("Scalar", func(t *testing.T) {
+		rows := &RowsBuffer{
+			ColumnsLine: []string{"name", "id"},
+			Data:        [][]any{{"Alice", int64(1)}, {"Bob", int64(2)}},
+		}
+		names, err := BindMap[int, string](rows, "id")
+		if err != nil {
+			t.Fatalf("BindMap failed: %v", err)
+		}
+		if names[1] != "Alice" || names[2] != "Bob" {
+			t.Errorf("unexpected result: %v", names)
+		}
+	})
+
+	t.Run("DuplicateKey", func(t *testing.T) {
+		rows := &RowsBuffer{
+			ColumnsLine: []string{"id", "name"},
+			Data:        [][]any{{int64(1), "Alice"}, {int64(1), "Bob"}},
+		}
+		if _, err := BindMap[int64, TestUser](rows, "id"); !errors.Is(err, ErrDuplicateKey) {
+			t.Errorf("expected ErrDuplicateKey, got %v", err)
+		}
+	})
+
+	t.Run("KeyColumnNotFound", func(t *testing.T) {
+		rows := &RowsBuffer{ColumnsLine: []string{"id", "name"}}
+		if _, err := BindMap[int64, TestUser](rows, "user_id"); !errors.Is(err, ErrKeyColumnNotFound) {
+			t.Errorf("expected ErrKeyColumnNotFound, got %v", err)
+		}
+	})
+
+	t.Run("ScalarTooManyColumns", func(t *testing.T) {
+		rows := &RowsBuffer{ColumnsLine: []string{"id", "name", "age"}}
+		if _, err := BindMap[int64, string](rows, "id"); err == nil {
+			t.Error("expected error for more than one value column")
+		}
+	})
+}
+
+func TestBindGrouped_binder_test(t *testing.T) {
+	type order struct {
+		ID     int    `column:"id"`
+		UserID int64  `column:"user_id"`
+		Item   string `column:"item"`
+	}
+	rows := &RowsBuffer{
+		ColumnsLine: []string{"id", "user_id", "item"},
+		Data: [][]any{
+			{1, int64(10), "apple"},
+			{2, int64(20), "pear"},
+			{3, int64(10), "plum"},
+		},
+	}
+	groups, err := BindGrouped[int64, order](rows, "user_id")
+	if err != nil {
+		t.Fatalf("BindGrouped failed: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+	first := groups[10]
+	if len(first) != 2 || first[0].Item != "apple" || first[1].Item != "plum" || first[1].UserID != 10 {
+		t.Errorf("unexpected group 10: %+v", first)
+	}
+	if len(groups[20]) != 1 || groups[20][0].ID != 2 {
+		t.Errorf("unexpected group 20: %+v", groups[20])
+	}
+}
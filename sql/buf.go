@@ -98,3 +98,32 @@ func NewRowsBuffer(columns []string, data [][]any) *RowsBuffer {
 		closed:      false,
 	}
 }
+
+// BufferRows reads all the rows of rows into a RowsBuffer, which can be kept, like in a cache,
+// and bound again. The values are the ones scanned into *any destinations.
+// Rows is not closed by this function.
+func BufferRows(rows Rows) (*RowsBuffer, error) {
+	if rows == nil {
+		return nil, ErrNilRows
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var data [][]any
+	for rows.Next() {
+		values := make([]any, len(columns))
+		dest := make([]any, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		data = append(data, values)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return NewRowsBuffer(columns, data), nil
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// FieldCodec encrypts the values of the struct fields tagged with the encrypted
+// option before they are sent to the database, and decrypts them once scanned:
+//
+//	type User struct {
+//	    SSN string `column:"ssn,encrypted" param:"ssn"`
+//	}
+//
+// The encrypted option uses the codec registered as DefaultFieldCodec, and
+// encrypted=name the codec registered with name.
+// The values are converted to bytes before being encrypted and the decrypted bytes
+// are assigned to the fields with the database/sql scan rules, so that the fields
+// are not limited to strings.
+//
+// The expressions of the statements, like the test of an if node, see the encrypted
+// values of the fields.
+type FieldCodec interface {
+	// Encrypt returns the ciphertext of plaintext.
+	Encrypt(plaintext []byte) ([]byte, error)
+
+	// Decrypt returns the plaintext of ciphertext.
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// DefaultFieldCodec is the name of the codec used by the fields tagged with the encrypted option without a name.
+const DefaultFieldCodec = "default"
+
+// encryptedOption is the option of the column tag of the encrypted fields.
+const encryptedOption = "encrypted"
+
+// columnPrefixOption is the option of the column tag of the nested struct fields whose columns have a prefix,
+// like `column:",columnPrefix=addr_"` to map the columns addr_id and addr_city into the fields id and city.
+const columnPrefixOption = "columnPrefix"
+
+var (
+	// fieldCodecs is a map of field codecs keyed by name.
+	fieldCodecs = map[string]FieldCodec{}
+
+	// fieldCodecMu protects fieldCodecs.
+	fieldCodecMu sync.RWMutex
+)
+
+var (
+	errFieldCodecNameEmpty = errors.New("field codec name is empty")
+	errFieldCodecNil       = errors.New("field codec is nil")
+)
+
+// RegisterFieldCodec registers the codec of the fields tagged with encrypted=name,
+// or with encrypted only when name is DefaultFieldCodec.
+func RegisterFieldCodec(name string, codec FieldCodec) error {
+	if len(name) == 0 {
+		return errFieldCodecNameEmpty
+	}
+	if codec == nil {
+		return errFieldCodecNil
+	}
+	fieldCodecMu.Lock()
+	defer fieldCodecMu.Unlock()
+	fieldCodecs[name] = codec
+	return nil
+}
+
+// fieldCodec returns the codec registered with name.
+func fieldCodec(name string) (FieldCodec, error) {
+	fieldCodecMu.RLock()
+	codec, exists := fieldCodecs[name]
+	fieldCodecMu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrFieldCodecNotFound, name)
+	}
+	return codec, nil
+}
+
+// parseColumnTag returns the column name of the tag and the name of its codec,
+// which is empty if the column is not encrypted.
+func parseColumnTag(tag string) (column, codec string) {
+	column, options, _ := strings.Cut(tag, ",")
+	for option := range strings.SplitSeq(options, ",") {
+		if option == encryptedOption {
+			codec = DefaultFieldCodec
+		} else if name, ok := strings.CutPrefix(option, encryptedOption+"="); ok {
+			codec = name
+		}
+	}
+	return column, codec
+}
+
+// parseColumnPrefix returns the value of the columnPrefix option of the tag, and whether it is set.
+func parseColumnPrefix(tag string) (prefix string, ok bool) {
+	_, options, _ := strings.Cut(tag, ",")
+	for option := range strings.SplitSeq(options, ",") {
+		if prefix, ok = strings.CutPrefix(option, columnPrefixOption+"="); ok {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+// fieldCodecName returns the name of the codec of field, or an empty string if it is not encrypted.
+func fieldCodecName(field reflect.StructField) string {
+	tag := field.Tag.Get(columnTagName)
+	if !strings.Contains(tag, ",") {
+		return ""
+	}
+	_, codec := parseColumnTag(tag)
+	return codec
+}
+
+// EncryptedField returns the value of field sent to the database: a driver.Valuer
+// encrypting value if the field is tagged with the encrypted option, or value itself.
+// Nil values are returned as they are, so that they are still sent as NULL.
+func EncryptedField(field reflect.StructField, value reflect.Value) reflect.Value {
+	codec := fieldCodecName(field)
+	if codec == "" {
+		return value
+	}
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+	default:
+	}
+	return reflect.ValueOf(EncryptedValue(codec, value.Interface()))
+}
+
+// EncryptedValue returns a driver.Valuer encrypting value with the codec registered
+// with name, for example to look up an encrypted column with a deterministic codec:
+//
+//	engine.Object("UserMapper.FindBySSN").QueryContext(ctx, juice.H{"ssn": sql.EncryptedValue(sql.DefaultFieldCodec, ssn)})
+func EncryptedValue(name string, value any) driver.Valuer {
+	return &encryptedValue{codec: name, value: value}
+}
+
+// encryptedValue is the driver.Valuer returned by EncryptedValue.
+type encryptedValue struct {
+	codec string
+	value any
+}
+
+// Value implements driver.Valuer.
+func (e *encryptedValue) Value() (driver.Value, error) {
+	value, err := driver.DefaultParameterConverter.ConvertValue(e.value)
+	if err != nil || value == nil {
+		return value, err
+	}
+	var plaintext []byte
+	if err = convertAssign(&plaintext, value); err != nil {
+		return nil, err
+	}
+	codec, err := fieldCodec(e.codec)
+	if err != nil {
+		return nil, err
+	}
+	return codec.Encrypt(plaintext)
+}
+
+// decryptScanner scans an encrypted column into dest.
+type decryptScanner struct {
+	codec string
+	dest  any
+}
+
+// Scan implements sql.Scanner.
+func (d *decryptScanner) Scan(src any) error {
+	var ciphertext []byte
+	switch value := src.(type) {
+	case nil:
+		return convertAssign(d.dest, nil)
+	case []byte:
+		ciphertext = value
+	case string:
+		ciphertext = []byte(value)
+	default:
+		return fmt.Errorf("%w: unexpected encrypted value of type %T", ErrInvalidCiphertext, src)
+	}
+	codec, err := fieldCodec(d.codec)
+	if err != nil {
+		return err
+	}
+	plaintext, err := codec.Decrypt(ciphertext)
+	if err != nil {
+		return err
+	}
+	return convertAssign(d.dest, plaintext)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type encryptedUser struct {
+	ID  int    `column:"id"`
+	SSN string `column:"ssn,encrypted=test"`
+	Age *int   `column:"age,encrypted=test"`
+}
+
+func TestParseColumnTag(t *testing.T) {
+	tests := []struct {
+		tag, column, codec string
+	}{
+		{"ssn", "ssn", ""},
+		{"ssn,encrypted", "ssn", DefaultFieldCodec},
+		{"ssn,omitempty,encrypted=pii", "ssn", "pii"},
+		{"-", "-", ""},
+	}
+	for _, tt := range tests {
+		if column, codec := parseColumnTag(tt.tag); column != tt.column || codec != tt.codec {
+			t.Errorf("parseColumnTag(%q) = %q, %q, expected %q, %q", tt.tag, column, codec, tt.column, tt.codec)
+		}
+	}
+}
+
+func TestAESFieldCodec(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	plaintext := []byte("123-45-6789")
+
+	random := NewAESFieldCodec(NewStaticKeyProvider("k1", key), false)
+	first, err := random.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, _ := random.Encrypt(plaintext)
+	if bytes.Equal(first, second) || bytes.Contains(first, plaintext) {
+		t.Fatalf("expected distinct opaque ciphertexts, got %x and %x", first, second)
+	}
+	if decrypted, err := random.Decrypt(first); err != nil || !bytes.Equal(decrypted, plaintext) {
+		t.Fatalf("expected %q, got %q, %v", plaintext, decrypted, err)
+	}
+
+	deterministic := NewAESFieldCodec(NewStaticKeyProvider("k1", key), true)
+	first, _ = deterministic.Encrypt(plaintext)
+	second, _ = deterministic.Encrypt(plaintext)
+	if !bytes.Equal(first, second) {
+		t.Fatalf("expected equal ciphertexts in deterministic mode, got %x and %x", first, second)
+	}
+	if decrypted, err := random.Decrypt(first); err != nil || !bytes.Equal(decrypted, plaintext) {
+		t.Fatalf("expected %q, got %q, %v", plaintext, decrypted, err)
+	}
+
+	first[len(first)-1] ^= 1
+	if _, err = deterministic.Decrypt(first); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Fatalf("expected ErrInvalidCiphertext for a tampered value, got %v", err)
+	}
+	rotated := NewAESFieldCodec(NewStaticKeyProvider("k2", key), false)
+	if _, err = rotated.Decrypt(second); err == nil {
+		t.Fatal("expected an error for an unknown key id")
+	}
+}
+
+func TestEncryptedFields(t *testing.T) {
+	codec := NewAESFieldCodec(NewStaticKeyProvider("k1", bytes.Repeat([]byte{2}, 16)), true)
+	if err := RegisterFieldCodec("test", codec); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ssn, err := EncryptedValue("test", "123-45-6789").Value()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	age, err := EncryptedValue("test", 42).Value()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if null, err := EncryptedValue("test", (*int)(nil)).Value(); null != nil || err != nil {
+		t.Fatalf("expected NULL to stay NULL, got %v, %v", null, err)
+	}
+
+	rows := NewRowsBuffer([]string{"id", "ssn", "age"}, [][]any{{1, ssn, age}, {2, string(ssn.([]byte)), nil}})
+	users, err := List[encryptedUser](rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(users) != 2 || users[0].SSN != "123-45-6789" || users[0].Age == nil || *users[0].Age != 42 {
+		t.Fatalf("expected decrypted fields, got %+v", users)
+	}
+	if users[1].SSN != "123-45-6789" || users[1].Age != nil {
+		t.Fatalf("expected decrypted fields, got %+v", users[1])
+	}
+
+	if _, err = EncryptedValue("missing", "value").Value(); !errors.Is(err, ErrFieldCodecNotFound) {
+		t.Fatalf("expected ErrFieldCodecNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// CSVNull is the field of a CSV record scanned as NULL, as written by the COPY
+// command of PostgreSQL and the SELECT INTO OUTFILE command of MySQL.
+const CSVNull = `\N`
+
+// CSVRows is a Rows reading a CSV stream, for tests and imports: its columns are the
+// first record and its rows the next ones, read one by one as they are scanned.
+// The fields are scanned like strings, so that they are converted into the destination
+// types with the database/sql rules, except CSVNull which is scanned as NULL.
+type CSVRows struct {
+	reader  *csv.Reader
+	columns []string
+	record  []string
+	err     error
+	closed  bool
+}
+
+// Ensure CSVRows implements Rows.
+var _ Rows = (*CSVRows)(nil)
+
+// NewCSVRows returns the CSVRows reading r, after reading its header record.
+func NewCSVRows(r io.Reader) (*CSVRows, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("csv rows: missing header record")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("csv rows: failed to read header record: %w", err)
+	}
+	return &CSVRows{reader: reader, columns: append([]string(nil), header...)}, nil
+}
+
+// Columns returns the fields of the header record.
+func (c *CSVRows) Columns() ([]string, error) {
+	if c.closed {
+		return nil, sql.ErrConnDone
+	}
+	return c.columns, nil
+}
+
+// Next reads the next record. It returns false at the end of the stream or on error,
+// like a record without as many fields as the header, which is returned by Err.
+func (c *CSVRows) Next() bool {
+	if c.closed || c.err != nil {
+		return false
+	}
+	record, err := c.reader.Read()
+	if err != nil {
+		c.record = nil
+		if !errors.Is(err, io.EOF) {
+			c.err = fmt.Errorf("csv rows: %w", err)
+		}
+		return false
+	}
+	c.record = record
+	return true
+}
+
+// Scan copies the fields of the current record into the values pointed at by dest.
+func (c *CSVRows) Scan(dest ...any) error {
+	if c.closed {
+		return sql.ErrConnDone
+	}
+	if c.record == nil {
+		return sql.ErrNoRows
+	}
+	if len(dest) != len(c.record) {
+		return fmt.Errorf("sql: expected %d destination arguments in Scan, not %d", len(c.record), len(dest))
+	}
+	for i, field := range c.record {
+		var src any = field
+		if field == CSVNull {
+			src = nil
+		}
+		if err := convertAssign(dest[i], src); err != nil {
+			return fmt.Errorf("sql: Scan error on column index %d, name %q: %w", i, c.columns[i], err)
+		}
+	}
+	return nil
+}
+
+// Close closes the rows. It does not close the underlying reader.
+func (c *CSVRows) Close() error {
+	c.closed = true
+	return nil
+}
+
+// Err returns the error encountered while reading the records, if any.
+func (c *CSVRows) Err() error {
+	return c.err
+}
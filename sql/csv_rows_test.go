@@ -0,0 +1,78 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+func TestCSVRows(t *testing.T) {
+	type user struct {
+		ID    int64          `column:"id"`
+		Name  string         `column:"name"`
+		Email sql.NullString `column:"email"`
+	}
+	rows, err := NewCSVRows(strings.NewReader("id,name,email\n1,Alice,alice@example.com\n2,\"Bob, Jr\",\\N\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	users, err := List[user](rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(users))
+	}
+	if users[0].ID != 1 || users[0].Name != "Alice" || users[0].Email.String != "alice@example.com" {
+		t.Errorf("unexpected users[0]: %+v", users[0])
+	}
+	if users[1].ID != 2 || users[1].Name != "Bob, Jr" || users[1].Email.Valid {
+		t.Errorf("unexpected users[1]: %+v", users[1])
+	}
+
+	rows, _ = NewCSVRows(strings.NewReader("id,name\n1,Alice\n2\n"))
+	if _, err = List[user](rows); err == nil || !strings.Contains(err.Error(), "wrong number of fields") {
+		t.Errorf("expected a field count error, got %v", err)
+	}
+
+	if _, err = NewCSVRows(strings.NewReader("")); err == nil {
+		t.Error("expected an error without header record")
+	}
+}
+
+func TestBufferRows(t *testing.T) {
+	rows, err := NewCSVRows(strings.NewReader("id,name\n1,Alice\n2,\\N\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buffer, err := BufferRows(rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(buffer.Data) != 2 || buffer.Data[0][1] != "Alice" || buffer.Data[1][1] != nil {
+		t.Fatalf("unexpected buffered data: %v", buffer.Data)
+	}
+	ids, err := List[int64](NewRowsBuffer(buffer.ColumnsLine[:1], [][]any{buffer.Data[0][:1], buffer.Data[1][:1]}))
+	if err != nil || len(ids) != 2 || ids[1] != 2 {
+		t.Fatalf("unexpected ids: %v, %v", ids, err)
+	}
+	if _, err = BufferRows(nil); err != ErrNilRows {
+		t.Fatalf("expected ErrNilRows, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-juicedev/juice/internal/container"
+)
+
+// destinationCacheDisabled disables the destination index cache
+// when JUICE_NO_DESTINATION_CACHE is "true".
+var destinationCacheDisabled, _ = strconv.ParseBool(os.Getenv("JUICE_NO_DESTINATION_CACHE"))
+
+// destinationCacheKey identifies the shape of a mapping: the struct type, the column set and the naming strategy.
+type destinationCacheKey struct {
+	tp reflect.Type
+
+	naming NamingStrategy
+
+	// columns are the result columns joined by a NUL byte, which can not appear in a column name.
+	columns string
+}
+
+// maxCachedDestinations is the maximum number of shapes kept by destinationCache.
+const maxCachedDestinations = 1024
+
+// destinationCache maps destinationCacheKey to the [][]int field indexes of the shape.
+// It is bounded, since ad hoc queries may select an unbounded number of column sets.
+// Cached indexes are shared by every rowDestination and must never be modified.
+var destinationCache = container.NewLRU[destinationCacheKey, [][]int](maxCachedDestinations, nil)
+
+// cachedIndexes returns the field indexes of tp for columns named by naming,
+// computing them with compute only the first time a shape is seen.
+// The shapes of the naming strategies which are not comparable are not cached.
+func cachedIndexes(tp reflect.Type, columns []string, naming NamingStrategy, compute func(reflect.Type, []string) [][]int) [][]int {
+	if destinationCacheDisabled || naming != nil && !reflect.TypeOf(naming).Comparable() {
+		return compute(tp, columns)
+	}
+	key := destinationCacheKey{tp: tp, naming: naming, columns: strings.Join(columns, "\x00")}
+	// As with the runtime func name cache, a concurrent miss may compute the same
+	// indexes twice, which is cheaper than synchronizing every lookup.
+	if indexes, ok := destinationCache.Get(key); ok {
+		return indexes
+	}
+	indexes := compute(tp, columns)
+	destinationCache.Set(key, indexes)
+	return indexes
+}
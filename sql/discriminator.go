@@ -0,0 +1,271 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// discriminator maps rows into the concrete types of an interface, chosen by the value of a column.
+type discriminator struct {
+	// iface is the interface type the concrete types implement,
+	// or the type they are assigned to for a DiscriminatorResultMap.
+	iface reflect.Type
+
+	// column is the name of the discriminator column.
+	column string
+
+	// factories create the concrete values keyed by discriminator value.
+	factories map[string]func() reflect.Value
+}
+
+var (
+	// discriminators is a map of discriminators keyed by interface type.
+	discriminators = map[reflect.Type]*discriminator{}
+
+	// discriminatorMu protects discriminators.
+	discriminatorMu sync.RWMutex
+)
+
+var (
+	errDiscriminatorNotInterface   = errors.New("discriminator type is not an interface")
+	errDiscriminatorColumnEmpty    = errors.New("discriminator column is empty")
+	errDiscriminatorFactoriesEmpty = errors.New("discriminator has no factory")
+	errDiscriminatorFactoryNil     = errors.New("discriminator factory is nil")
+	errDiscriminatorNotAssignable  = errors.New("discriminator model is not assignable")
+)
+
+// RegisterDiscriminator registers the factories of the concrete types of the interface T,
+// keyed by the value of column, so that rows can be mapped into T, like with List[T]:
+//
+//	err := RegisterDiscriminator[PaymentMethod]("type", map[string]func() PaymentMethod{
+//	    "card": func() PaymentMethod { return &Card{} },
+//	    "iban": func() PaymentMethod { return &BankAccount{} },
+//	})
+//
+// Each row is mapped into the value returned by the factory of its column value, which
+// must be a non-nil pointer. The column values are compared with their string form,
+// so that numeric discriminators are registered like "1".
+// Registering T again replaces its factories.
+func RegisterDiscriminator[T any](column string, factories map[string]func() T) error {
+	iface := reflect.TypeFor[T]()
+	if iface.Kind() != reflect.Interface {
+		return fmt.Errorf("%w: %s", errDiscriminatorNotInterface, iface)
+	}
+	if len(column) == 0 {
+		return errDiscriminatorColumnEmpty
+	}
+	if len(factories) == 0 {
+		return errDiscriminatorFactoriesEmpty
+	}
+	d := &discriminator{iface: iface, column: column, factories: make(map[string]func() reflect.Value, len(factories))}
+	for value, factory := range factories {
+		if factory == nil {
+			return fmt.Errorf("%w: %q", errDiscriminatorFactoryNil, value)
+		}
+		d.factories[value] = func() reflect.Value { return reflect.ValueOf(factory()) }
+	}
+	discriminatorMu.Lock()
+	defer discriminatorMu.Unlock()
+	discriminators[iface] = d
+	return nil
+}
+
+// lookupDiscriminator returns the discriminator registered for tp, if tp is an interface.
+func lookupDiscriminator(tp reflect.Type) (*discriminator, bool) {
+	if tp.Kind() != reflect.Interface {
+		return nil, false
+	}
+	discriminatorMu.RLock()
+	defer discriminatorMu.RUnlock()
+	d, ok := discriminators[tp]
+	return d, ok
+}
+
+// discriminatedRows scans rows into the concrete types chosen by a discriminator.
+type discriminatedRows struct {
+	discriminator *discriminator
+	rows          Rows
+	columns       []string
+
+	// index is the position of the discriminator column.
+	index int
+
+	// values are the values of the current row, scanned through dest.
+	values []any
+	dest   []any
+
+	// destinations are the column destinations of the concrete types.
+	destinations map[reflect.Type]*rowDestination
+}
+
+// newDiscriminatedRows returns the discriminatedRows of rows.
+func newDiscriminatedRows(d *discriminator, rows Rows) (*discriminatedRows, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns: %w", err)
+	}
+	index := slices.Index(columns, d.column)
+	if index < 0 {
+		return nil, fmt.Errorf("%w: %q of %s", ErrDiscriminatorColumnNotFound, d.column, d.iface)
+	}
+	r := &discriminatedRows{
+		discriminator: d,
+		rows:          rows,
+		columns:       columns,
+		index:         index,
+		values:        make([]any, len(columns)),
+		dest:          make([]any, len(columns)),
+		destinations:  make(map[reflect.Type]*rowDestination),
+	}
+	for i := range r.values {
+		r.dest[i] = &r.values[i]
+	}
+	return r, nil
+}
+
+// scan maps the current row into a new value of the concrete type chosen by its discriminator column.
+func (r *discriminatedRows) scan() (reflect.Value, error) {
+	clear(r.values)
+	if err := r.rows.Scan(r.dest...); err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to scan row: %w", err)
+	}
+	column := r.discriminator.column
+	if r.values[r.index] == nil {
+		return reflect.Value{}, fmt.Errorf("%w: NULL %q of %s", ErrUnknownDiscriminator, column, r.discriminator.iface)
+	}
+	var value string
+	if err := convertAssign(&value, r.values[r.index]); err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to scan discriminator column %q: %w", column, err)
+	}
+	factory, ok := r.discriminator.factories[value]
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("%w: %q of %q for %s", ErrUnknownDiscriminator, value, column, r.discriminator.iface)
+	}
+	rv := factory()
+	if !rv.IsValid() || rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return reflect.Value{}, fmt.Errorf("discriminator factory of %q must return a non-nil pointer", value)
+	}
+	destination, ok := r.destinations[rv.Type()]
+	if !ok {
+		destination = newRowDestination(r.rows)
+		r.destinations[rv.Type()] = destination
+	}
+	dest, err := destination.Destination(rv, r.columns)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to get destination: %w", err)
+	}
+	for i := range dest {
+		if err = convertAssign(dest[i], r.values[i]); err != nil {
+			return reflect.Value{}, fmt.Errorf("failed to scan column %q: %w", r.columns[i], err)
+		}
+	}
+	return rv, nil
+}
+
+// DiscriminatorResultMap is a ResultMap mapping each row into a new model chosen by the value of a column,
+// like the <discriminator> of a <resultMap>. It maps the rows into a slice, or the single row into a value,
+// whose type the pointers to its models are assignable to, like an interface they implement.
+type DiscriminatorResultMap struct {
+	column string
+
+	// models are the struct types of the models keyed by discriminator value.
+	models map[string]reflect.Type
+}
+
+// NewDiscriminatorResultMap returns the DiscriminatorResultMap mapping the rows whose column is value
+// into the model registered with RegisterModel under cases[value]:
+//
+//	resultMap, err := NewDiscriminatorResultMap("type", map[string]string{
+//	    "card": "models.Card",
+//	    "iban": "models.BankAccount",
+//	})
+//
+// Like with RegisterDiscriminator, the column values are compared with their string form.
+func NewDiscriminatorResultMap(column string, cases map[string]string) (*DiscriminatorResultMap, error) {
+	if len(column) == 0 {
+		return nil, errDiscriminatorColumnEmpty
+	}
+	if len(cases) == 0 {
+		return nil, errDiscriminatorFactoriesEmpty
+	}
+	m := &DiscriminatorResultMap{column: column, models: make(map[string]reflect.Type, len(cases))}
+	modelMu.RLock()
+	defer modelMu.RUnlock()
+	for value, name := range cases {
+		tp, ok := models[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrModelNotFound, name)
+		}
+		m.models[value] = tp
+	}
+	return m, nil
+}
+
+// MapTo implements ResultMap.
+func (m *DiscriminatorResultMap) MapTo(rv reflect.Value, rows Rows) error {
+	if rv.Kind() != reflect.Pointer {
+		return ErrPointerRequired
+	}
+	target := rv.Elem()
+	if target.Kind() != reflect.Slice {
+		d, err := m.discriminatorOf(target.Type())
+		if err != nil {
+			return err
+		}
+		if !rows.Next() {
+			if err = rows.Err(); err != nil {
+				return fmt.Errorf("error occurred while fetching row: %w", err)
+			}
+			return sql.ErrNoRows
+		}
+		return mapDiscriminatedRow(rv, rows, d)
+	}
+	d, err := m.discriminatorOf(target.Type().Elem())
+	if err != nil {
+		return err
+	}
+	values, err := MultiRowsResultMap{}.mapWithDiscriminator(rows, d)
+	if err != nil {
+		return err
+	}
+	if len(values) > 0 {
+		target.Set(reflect.Append(target, values...))
+	} else if !resultMapPreserveNilSlice {
+		target.Set(reflect.MakeSlice(target.Type(), 0, 0))
+	}
+	return nil
+}
+
+// discriminatorOf returns the discriminator mapping the rows into the models, assigned to values of tp.
+func (m *DiscriminatorResultMap) discriminatorOf(tp reflect.Type) (*discriminator, error) {
+	d := &discriminator{iface: tp, column: m.column, factories: make(map[string]func() reflect.Value, len(m.models))}
+	for value, model := range m.models {
+		if !reflect.PointerTo(model).AssignableTo(tp) {
+			return nil, fmt.Errorf("%w: *%s to %s", errDiscriminatorNotAssignable, model, tp)
+		}
+		d.factories[value] = func() reflect.Value { return reflect.New(model) }
+	}
+	return d, nil
+}
+
+var _ ResultMap = (*DiscriminatorResultMap)(nil)
@@ -0,0 +1,189 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"errors"
+	"testing"
+)
+
+type paymentMethod interface {
+	Kind() string
+}
+
+type cardPayment struct {
+	ID     int64  `column:"id"`
+	Number string `column:"number"`
+}
+
+func (*cardPayment) Kind() string { return "card" }
+
+type bankPayment struct {
+	ID   int64  `column:"id"`
+	IBAN string `column:"iban"`
+}
+
+func (*bankPayment) Kind() string { return "bank" }
+
+func registerPaymentMethods(t *testing.T) {
+	t.Helper()
+	err := RegisterDiscriminator[paymentMethod]("type", map[string]func() paymentMethod{
+		"1": func() paymentMethod { return &cardPayment{} },
+		"2": func() paymentMethod { return &bankPayment{} },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func newPaymentRows(data ...[]any) Rows {
+	return NewRowsBuffer([]string{"id", "type", "number", "iban"}, data)
+}
+
+func TestRegisterDiscriminator(t *testing.T) {
+	if err := RegisterDiscriminator[cardPayment]("type", map[string]func() cardPayment{"1": nil}); !errors.Is(err, errDiscriminatorNotInterface) {
+		t.Errorf("expected errDiscriminatorNotInterface, got %v", err)
+	}
+	if err := RegisterDiscriminator[paymentMethod]("", map[string]func() paymentMethod{}); !errors.Is(err, errDiscriminatorColumnEmpty) {
+		t.Errorf("expected errDiscriminatorColumnEmpty, got %v", err)
+	}
+	if err := RegisterDiscriminator[paymentMethod]("type", nil); !errors.Is(err, errDiscriminatorFactoriesEmpty) {
+		t.Errorf("expected errDiscriminatorFactoriesEmpty, got %v", err)
+	}
+	if err := RegisterDiscriminator[paymentMethod]("type", map[string]func() paymentMethod{"1": nil}); !errors.Is(err, errDiscriminatorFactoryNil) {
+		t.Errorf("expected errDiscriminatorFactoryNil, got %v", err)
+	}
+}
+
+func TestDiscriminator_List(t *testing.T) {
+	registerPaymentMethods(t)
+
+	methods, err := List[paymentMethod](newPaymentRows(
+		[]any{int64(1), int64(1), "4242", nil},
+		[]any{int64(2), int64(2), nil, "FR76"},
+		[]any{int64(3), []byte("1"), "5555", nil},
+	))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(methods) != 3 {
+		t.Fatalf("expected 3 methods, got %d", len(methods))
+	}
+	if card, ok := methods[0].(*cardPayment); !ok || card.ID != 1 || card.Number != "4242" {
+		t.Errorf("unexpected methods[0]: %#v", methods[0])
+	}
+	if bank, ok := methods[1].(*bankPayment); !ok || bank.ID != 2 || bank.IBAN != "FR76" {
+		t.Errorf("unexpected methods[1]: %#v", methods[1])
+	}
+	if card, ok := methods[2].(*cardPayment); !ok || card.Number != "5555" {
+		t.Errorf("unexpected methods[2]: %#v", methods[2])
+	}
+}
+
+func TestDiscriminator_BindAndIter(t *testing.T) {
+	registerPaymentMethods(t)
+
+	method, err := Bind[paymentMethod](newPaymentRows([]any{int64(2), "2", nil, "DE89"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bank, ok := method.(*bankPayment); !ok || bank.IBAN != "DE89" {
+		t.Errorf("unexpected method: %#v", method)
+	}
+
+	if _, err = Bind[paymentMethod](newPaymentRows([]any{int64(1), "1", "1", nil}, []any{int64(2), "2", nil, "DE89"})); !errors.Is(err, ErrTooManyRows) {
+		t.Errorf("expected ErrTooManyRows, got %v", err)
+	}
+
+	var kinds []string
+	err = ForEach(newPaymentRows([]any{int64(1), "1", "1", nil}, []any{int64(2), "2", nil, "DE89"}), func(method paymentMethod) error {
+		kinds = append(kinds, method.Kind())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(kinds) != 2 || kinds[0] != "card" || kinds[1] != "bank" {
+		t.Errorf("unexpected kinds: %v", kinds)
+	}
+}
+
+func TestDiscriminator_Errors(t *testing.T) {
+	registerPaymentMethods(t)
+
+	if _, err := List[paymentMethod](newPaymentRows([]any{int64(1), "3", nil, nil})); !errors.Is(err, ErrUnknownDiscriminator) {
+		t.Errorf("expected ErrUnknownDiscriminator, got %v", err)
+	}
+	if _, err := List[paymentMethod](newPaymentRows([]any{int64(1), nil, nil, nil})); !errors.Is(err, ErrUnknownDiscriminator) {
+		t.Errorf("expected ErrUnknownDiscriminator for NULL, got %v", err)
+	}
+	rows := NewRowsBuffer([]string{"id", "number"}, [][]any{{int64(1), "4242"}})
+	if _, err := List[paymentMethod](rows); !errors.Is(err, ErrDiscriminatorColumnNotFound) {
+		t.Errorf("expected ErrDiscriminatorColumnNotFound, got %v", err)
+	}
+}
+
+func TestDiscriminatorResultMap(t *testing.T) {
+	if err := RegisterModel[cardPayment]("models.CardPayment"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := RegisterModel[bankPayment]("models.BankPayment"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := NewDiscriminatorResultMap("", map[string]string{"1": "models.CardPayment"}); !errors.Is(err, errDiscriminatorColumnEmpty) {
+		t.Errorf("expected errDiscriminatorColumnEmpty, got %v", err)
+	}
+	if _, err := NewDiscriminatorResultMap("type", map[string]string{"1": "models.Missing"}); !errors.Is(err, ErrModelNotFound) {
+		t.Errorf("expected ErrModelNotFound, got %v", err)
+	}
+	resultMap, err := NewDiscriminatorResultMap("type", map[string]string{"1": "models.CardPayment", "2": "models.BankPayment"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	methods, err := BindWithResultMap[[]any](newPaymentRows(
+		[]any{int64(1), int64(1), "4242", nil},
+		[]any{int64(2), "2", nil, "FR76"},
+	), resultMap)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(methods) != 2 {
+		t.Fatalf("expected 2 methods, got %d", len(methods))
+	}
+	if card, ok := methods[0].(*cardPayment); !ok || card.ID != 1 || card.Number != "4242" {
+		t.Errorf("unexpected methods[0]: %#v", methods[0])
+	}
+	if bank, ok := methods[1].(*bankPayment); !ok || bank.ID != 2 || bank.IBAN != "FR76" {
+		t.Errorf("unexpected methods[1]: %#v", methods[1])
+	}
+
+	method, err := BindWithResultMap[paymentMethod](newPaymentRows([]any{int64(2), "2", nil, "DE89"}), resultMap)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bank, ok := method.(*bankPayment); !ok || bank.IBAN != "DE89" {
+		t.Errorf("unexpected method: %#v", method)
+	}
+
+	if _, err = BindWithResultMap[[]*cardPayment](newPaymentRows(), resultMap); !errors.Is(err, errDiscriminatorNotAssignable) {
+		t.Errorf("expected errDiscriminatorNotAssignable, got %v", err)
+	}
+	if _, err = BindWithResultMap[[]any](newPaymentRows([]any{int64(1), "3", nil, nil}), resultMap); !errors.Is(err, ErrUnknownDiscriminator) {
+		t.Errorf("expected ErrUnknownDiscriminator, got %v", err)
+	}
+}
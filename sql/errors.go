@@ -30,4 +30,28 @@ var (
 
 	// ErrPointerRequired is returned when the destination is not a pointer.
 	ErrPointerRequired = errors.New("destination must be a pointer")
+
+	// ErrLastInsertIdNotSupported is returned when the result can not report a last insert id.
+	ErrLastInsertIdNotSupported = errors.New("last insert id is not supported")
+
+	// ErrKeyColumnNotFound is returned when the key column is missing from the result columns.
+	ErrKeyColumnNotFound = errors.New("key column not found")
+
+	// ErrDuplicateKey is returned when BindMap meets the same key twice.
+	ErrDuplicateKey = errors.New("duplicate key")
+
+	// ErrColumnMismatch is returned in strict column mode when columns and struct fields do not match one to one.
+	ErrColumnMismatch = errors.New("columns do not match struct fields")
+
+	// ErrFieldCodecNotFound is returned when an encrypted field refers to a codec which is not registered.
+	ErrFieldCodecNotFound = errors.New("field codec not found")
+
+	// ErrInvalidCiphertext is returned when an encrypted value can not be decrypted.
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+
+	// ErrDiscriminatorColumnNotFound is returned when the discriminator column is missing from the result columns.
+	ErrDiscriminatorColumnNotFound = errors.New("discriminator column not found")
+
+	// ErrUnknownDiscriminator is returned when no factory is registered for the value of the discriminator column.
+	ErrUnknownDiscriminator = errors.New("unknown discriminator value")
 )
@@ -38,9 +38,26 @@ func Iter[T any](rows Rows) (Iterator[T], error) {
 		return nil, err
 	}
 
-	columnDest := &rowDestination{}
+	columnDest := newRowDestination(rows)
 	t := reflect.TypeFor[T]()
 
+	// interfaces with a discriminator are scanned into the concrete types it chooses
+	if d, ok := lookupDiscriminator(t); ok {
+		discriminated, err := newDiscriminatedRows(d, rows)
+		if err != nil {
+			return nil, err
+		}
+		return iterate(rows, func() (T, error) {
+			value, err := discriminated.scan()
+			if err != nil {
+				var zero T
+				return zero, err
+			}
+			result, _ := reflect.TypeAssert[T](value)
+			return result, nil
+		}), nil
+	}
+
 	var objectFactory func() T
 
 	isPtr := t.Kind() == reflect.Pointer
@@ -78,6 +95,11 @@ func Iter[T any](rows Rows) (Iterator[T], error) {
 		return t, nil
 	}
 
+	return iterate(rows, handler), nil
+}
+
+// iterate returns an iterator yielding the value scanned by handler for each row.
+func iterate[T any](rows Rows, handler func() (T, error)) Iterator[T] {
 	return func(yield func(T, error) bool) {
 		for rows.Next() {
 			value, err := handler()
@@ -90,5 +112,47 @@ func Iter[T any](rows Rows) (Iterator[T], error) {
 			var zero T
 			yield(zero, err)
 		}
-	}, nil
+	}
+}
+
+// ForEach scans rows one by one into T and calls fn for each of them
+// without materializing the whole result set. It stops at the first error
+// returned by scanning or by fn.
+// Rows is not closed by this function.
+func ForEach[T any](rows Rows, fn func(T) error) error {
+	if rows == nil {
+		return ErrNilRows
+	}
+	seq, err := Iter[T](rows)
+	if err != nil {
+		return err
+	}
+	for value, err := range seq {
+		if err != nil {
+			return err
+		}
+		if err = fn(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reduce folds rows into an accumulator of type R, starting from initial.
+// Each row is scanned into T and passed to fn together with the current accumulator.
+// It stops at the first error returned by scanning or by fn and returns the accumulator so far.
+// Rows is not closed by this function.
+//
+// Example:
+//
+//	total, err := Reduce(rows, 0, func(sum int, o Order) (int, error) {
+//	    return sum + o.Amount, nil
+//	})
+func Reduce[T, R any](rows Rows, initial R, fn func(R, T) (R, error)) (R, error) {
+	result := initial
+	err := ForEach(rows, func(value T) (err error) {
+		result, err = fn(result, value)
+		return err
+	})
+	return result, err
 }
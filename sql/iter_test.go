@@ -0,0 +1,83 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestForEach(t *testing.T) {
+	rows := NewRowsBuffer([]string{"id", "name"}, [][]any{{1, "Alice"}, {2, "Bob"}, {3, "Carol"}})
+	var names []string
+	err := ForEach(rows, func(user TestUser) error {
+		names = append(names, user.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEach failed: %v", err)
+	}
+	if len(names) != 3 || names[0] != "Alice" || names[2] != "Carol" {
+		t.Errorf("unexpected names: %v", names)
+	}
+
+	t.Run("StopOnError", func(t *testing.T) {
+		errStop := errors.New("stop")
+		rows := NewRowsBuffer([]string{"id", "name"}, [][]any{{1, "Alice"}, {2, "Bob"}})
+		var calls int
+		err := ForEach(rows, func(user *TestUser) error {
+			calls++
+			return errStop
+		})
+		if !errors.Is(err, errStop) || calls != 1 {
+			t.Errorf("expected errStop after one call, got %v after %d calls", err, calls)
+		}
+	})
+
+	t.Run("NilRows", func(t *testing.T) {
+		if err := ForEach(nil, func(TestUser) error { return nil }); !errors.Is(err, ErrNilRows) {
+			t.Errorf("expected ErrNilRows, got %v", err)
+		}
+	})
+}
+
+func TestReduce(t *testing.T) {
+	rows := NewRowsBuffer([]string{"amount"}, [][]any{{int64(3)}, {int64(4)}, {int64(5)}})
+	total, err := Reduce(rows, int64(0), func(sum int64, amount int64) (int64, error) {
+		return sum + amount, nil
+	})
+	if err != nil {
+		t.Fatalf("Reduce failed: %v", err)
+	}
+	if total != 12 {
+		t.Errorf("expected 12, got %d", total)
+	}
+
+	t.Run("StopOnError", func(t *testing.T) {
+		errStop := errors.New("stop")
+		rows := NewRowsBuffer([]string{"amount"}, [][]any{{int64(3)}, {int64(4)}})
+		total, err := Reduce(rows, int64(0), func(sum int64, amount int64) (int64, error) {
+			if amount == 4 {
+				return sum, errStop
+			}
+			return sum + amount, nil
+		})
+		if !errors.Is(err, errStop) || total != 3 {
+			t.Errorf("expected errStop with partial total 3, got %v and %d", err, total)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaskFunc returns the masked value of a text column.
+type MaskFunc func(column, value string) string
+
+// MaskValue is the default MaskFunc. An email keeps the first character of its local part
+// and its domain, like e***@example.com. Other values keep their last four characters
+// when they have more than eight, like a phone number, and are masked entirely otherwise.
+func MaskValue(_ string, value string) string {
+	if local, domain, ok := strings.Cut(value, "@"); ok && local != "" {
+		first, size := utf8.DecodeRuneInString(local)
+		return string(first) + strings.Repeat("*", utf8.RuneCountInString(local[size:])) + "@" + domain
+	}
+	runes := []rune(value)
+	keep := 0
+	if len(runes) > 8 {
+		keep = 4
+	}
+	return strings.Repeat("*", len(runes)-keep) + string(runes[len(runes)-keep:])
+}
+
+// WithColumnMask returns rows whose text values of columns are replaced by mask once scanned,
+// to hide the personal data of production snapshots in the other environments.
+// The columns are matched case-insensitively. Only the destinations holding text are masked:
+// strings, byte slices, sql.NullString and their pointers, and any holding a string or bytes.
+// A nil mask is MaskValue.
+func WithColumnMask(rows Rows, columns []string, mask MaskFunc) Rows {
+	if rows == nil || len(columns) == 0 {
+		return rows
+	}
+	if mask == nil {
+		mask = MaskValue
+	}
+	// keep the options outside, so that they stay visible on the returned value.
+	switch wrapped := rows.(type) {
+	case *optionRows:
+		return &optionRows{Rows: WithColumnMask(wrapped.Rows, columns, mask), rowsOptions: wrapped.rowsOptions}
+	case *optionResultSets:
+		masked := WithColumnMask(wrapped.ResultSets, columns, mask).(ResultSets)
+		return &optionResultSets{ResultSets: masked, rowsOptions: wrapped.rowsOptions}
+	}
+	masked := &maskedRows{Rows: rows, columns: columns, mask: mask}
+	if resultSets, ok := rows.(ResultSets); ok {
+		return &maskedResultSets{maskedRows: masked, resultSets: resultSets}
+	}
+	return masked
+}
+
+// maskedRows masks the values of its columns once scanned.
+type maskedRows struct {
+	Rows
+	columns []string
+	mask    MaskFunc
+
+	// names are the names of the masked columns by index, resolved on the first Scan.
+	names    []string
+	resolved bool
+}
+
+// Scan implements Rows.
+func (r *maskedRows) Scan(dest ...any) error {
+	if err := r.Rows.Scan(dest...); err != nil {
+		return err
+	}
+	if !r.resolved {
+		columns, err := r.Rows.Columns()
+		if err != nil {
+			return err
+		}
+		r.names = make([]string, len(columns))
+		for index, column := range columns {
+			for _, masked := range r.columns {
+				if strings.EqualFold(column, masked) {
+					r.names[index] = column
+				}
+			}
+		}
+		r.resolved = true
+	}
+	for index, name := range r.names {
+		if name != "" && index < len(dest) {
+			maskDestination(dest[index], name, r.mask)
+		}
+	}
+	return nil
+}
+
+// maskedResultSets is a maskedRows whose rows have several result sets,
+// whose masked columns are resolved again for each.
+type maskedResultSets struct {
+	*maskedRows
+	resultSets ResultSets
+}
+
+// NextResultSet implements ResultSets.
+func (r *maskedResultSets) NextResultSet() bool {
+	r.resolved = false
+	return r.resultSets.NextResultSet()
+}
+
+// nullStringType is the type of sql.NullString.
+var nullStringType = reflect.TypeFor[sql.NullString]()
+
+// maskDestination masks the text scanned into dest.
+func maskDestination(dest any, column string, mask MaskFunc) {
+	value := reflect.ValueOf(dest)
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		if value.Kind() == reflect.Interface {
+			// the values held by an interface are not addressable, so they are replaced.
+			switch held := value.Elem().Interface().(type) {
+			case string:
+				value.Set(reflect.ValueOf(mask(column, held)))
+			case []byte:
+				value.Set(reflect.ValueOf([]byte(mask(column, string(held)))))
+			}
+			return
+		}
+		value = value.Elem()
+	}
+	switch {
+	case !value.CanSet():
+	case value.Kind() == reflect.String:
+		value.SetString(mask(column, value.String()))
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+		if !value.IsNil() {
+			value.SetBytes([]byte(mask(column, string(value.Bytes()))))
+		}
+	case value.Type() == nullStringType:
+		if nullString := value.Addr().Interface().(*sql.NullString); nullString.Valid {
+			nullString.String = mask(column, nullString.String)
+		}
+	}
+}
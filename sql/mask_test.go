@@ -0,0 +1,72 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestMaskValue(t *testing.T) {
+	tests := map[string]string{
+		"eat@example.com": "e**@example.com",
+		"+8613812345678":  "**********5678",
+		"secret":          "******",
+		"":                "",
+	}
+	for value, want := range tests {
+		if got := MaskValue("column", value); got != want {
+			t.Fatalf("MaskValue(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestWithColumnMask(t *testing.T) {
+	type user struct {
+		ID    int            `column:"id"`
+		Email string         `column:"email"`
+		Phone *string        `column:"phone"`
+		Note  sql.NullString `column:"note"`
+		Raw   []byte         `column:"raw"`
+	}
+	rows := NewRowsBuffer(
+		[]string{"id", "email", "phone", "note", "raw"},
+		[][]any{{int64(1), "eat@example.com", "13812345678", "hello", []byte("bytes")}},
+	)
+	masked := WithColumnMask(WithStrictColumns(rows), []string{"EMAIL", "phone", "note", "raw", "id"}, nil)
+	if !isStrictColumns(masked) {
+		t.Fatal("expected the rows options to stay visible")
+	}
+	got, err := Bind[user](masked)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ID != 1 || got.Email != "e**@example.com" || *got.Phone != "*******5678" || got.Note.String != "*****" || string(got.Raw) != "*****" {
+		t.Fatalf("unexpected user: %+v", got)
+	}
+
+	// values scanned into any are masked too
+	rows = NewRowsBuffer([]string{"email"}, [][]any{{"eat@example.com"}})
+	masked = WithColumnMask(rows, []string{"email"}, func(string, string) string { return "hidden" })
+	var value any
+	if !masked.Next() {
+		t.Fatal("expected a row")
+	}
+	if err = masked.Scan(&value); err != nil || value != "hidden" {
+		t.Fatalf("expected hidden, got %v, %v", value, err)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	// models is a map of struct types keyed by their registered name.
+	models = map[string]reflect.Type{}
+
+	// modelMu protects models.
+	modelMu sync.RWMutex
+)
+
+// ErrModelNotFound is returned when no model is registered under a name.
+var ErrModelNotFound = errors.New("juice: model not found")
+
+var (
+	errModelNameEmpty = errors.New("model name is empty")
+	errModelNotStruct = errors.New("model type is not a struct")
+)
+
+// RegisterModel registers the struct type T under name, so that mappers can select its columns
+// without repeating them, like with <alias type="models.User"/>:
+//
+//	err := RegisterModel[User]("models.User")
+//
+// T may also be a pointer to a struct. Registering name again replaces its type.
+func RegisterModel[T any](name string) error {
+	if len(name) == 0 {
+		return errModelNameEmpty
+	}
+	tp := reflect.TypeFor[T]()
+	if tp.Kind() == reflect.Pointer {
+		tp = tp.Elem()
+	}
+	if tp.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %s", errModelNotStruct, tp)
+	}
+	modelMu.Lock()
+	defer modelMu.Unlock()
+	models[name] = tp
+	return nil
+}
+
+// ModelColumns returns the columns of the model registered under name, in field order.
+// The columns are those of the column tags, including the ones of the embedded and
+// prefixed nested structs; the fields without column tag are named by naming,
+// or skipped when naming is nil.
+func ModelColumns(name string, naming NamingStrategy) ([]string, error) {
+	modelMu.RLock()
+	tp, ok := models[name]
+	modelMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrModelNotFound, name)
+	}
+	return structColumns(tp, nil, nil, "", naming), nil
+}
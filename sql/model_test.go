@@ -0,0 +1,69 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+type modelAudit struct {
+	CreatedAt string `column:"created_at"`
+}
+
+type modelAddress struct {
+	City string `column:"city"`
+}
+
+type modelUser struct {
+	ID       int64  `column:"id"`
+	Name     string `column:"name"`
+	Password string `column:"-"`
+	Note     string
+	modelAudit
+	Address modelAddress `column:",columnPrefix=address_"`
+}
+
+func TestRegisterModel(t *testing.T) {
+	if err := RegisterModel[modelUser](""); !errors.Is(err, errModelNameEmpty) {
+		t.Errorf("expected errModelNameEmpty, got %v", err)
+	}
+	if err := RegisterModel[[]modelUser]("models.Users"); !errors.Is(err, errModelNotStruct) {
+		t.Errorf("expected errModelNotStruct, got %v", err)
+	}
+	if err := RegisterModel[*modelUser]("models.User"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	columns, err := ModelColumns("models.User", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"id", "name", "created_at", "address_city"}; !slices.Equal(columns, want) {
+		t.Errorf("expected %v, got %v", want, columns)
+	}
+	columns, err = ModelColumns("models.User", SnakeCaseNaming)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"id", "name", "note", "created_at", "address_city"}; !slices.Equal(columns, want) {
+		t.Errorf("expected %v with the untagged fields named, got %v", want, columns)
+	}
+	if _, err = ModelColumns("models.Missing", nil); !errors.Is(err, ErrModelNotFound) {
+		t.Errorf("expected ErrModelNotFound, got %v", err)
+	}
+}
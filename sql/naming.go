@@ -0,0 +1,155 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NamingStrategy resolves the column of the struct fields without column tag, and the field of a column.
+// It is used to bind rows into structs whose fields are not all tagged, see WithNamingStrategy.
+// The tagged fields keep their tag.
+type NamingStrategy interface {
+	// FieldToColumn returns the column of the field name.
+	FieldToColumn(field string) string
+
+	// ColumnToField returns the field name of the column.
+	ColumnToField(column string) string
+}
+
+var (
+	// SnakeCaseNaming maps the field UserID to the column user_id, and the column user_id to the field UserId.
+	SnakeCaseNaming NamingStrategy = snakeCaseNaming{}
+
+	// LowerCamelNaming maps the field UserID to the column userID, and the column userId to the field UserId.
+	LowerCamelNaming NamingStrategy = lowerCamelNaming{}
+
+	// ExactNaming maps the fields and the columns to the same names.
+	ExactNaming NamingStrategy = exactNaming{}
+)
+
+// namingStrategies are the built-in strategies keyed by name, see LookupNamingStrategy.
+var namingStrategies = map[string]NamingStrategy{
+	"snake":      SnakeCaseNaming,
+	"lowerCamel": LowerCamelNaming,
+	"exact":      ExactNaming,
+}
+
+// LookupNamingStrategy returns the built-in strategy named name: snake, lowerCamel or exact.
+func LookupNamingStrategy(name string) (NamingStrategy, bool) {
+	strategy, ok := namingStrategies[name]
+	return strategy, ok
+}
+
+// WithNamingStrategy returns rows binding the struct fields without column tag
+// to the columns named by strategy.
+func WithNamingStrategy(rows Rows, strategy NamingStrategy) Rows {
+	if rows == nil || strategy == nil {
+		return rows
+	}
+	return withRowsOptions(rows, func(options *rowsOptions) { options.naming = strategy })
+}
+
+// namingStrategyOf returns the naming strategy of rows, or nil if it has none.
+func namingStrategyOf(rows Rows) NamingStrategy {
+	naming, ok := rows.(interface{ NamingStrategy() NamingStrategy })
+	if !ok {
+		return nil
+	}
+	return naming.NamingStrategy()
+}
+
+// fieldColumn returns the column of field tagged with tag, named by naming if it is not tagged.
+func fieldColumn(field reflect.StructField, tag string, naming NamingStrategy) string {
+	if tag != "" || naming == nil || field.Anonymous || !field.IsExported() {
+		return tag
+	}
+	if _, ok := field.Tag.Lookup(columnTagName); ok {
+		return tag
+	}
+	return naming.FieldToColumn(field.Name)
+}
+
+type snakeCaseNaming struct{}
+
+// FieldToColumn implements NamingStrategy.
+// The runs of capitals are kept together, like HTTPServer mapped to http_server.
+func (snakeCaseNaming) FieldToColumn(field string) string {
+	runes := []rune(field)
+	var builder strings.Builder
+	builder.Grow(len(field) + 4)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) && runes[i-1] != '_' {
+				builder.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// ColumnToField implements NamingStrategy.
+func (snakeCaseNaming) ColumnToField(column string) string {
+	var builder strings.Builder
+	builder.Grow(len(column))
+	for part := range strings.SplitSeq(column, "_") {
+		builder.WriteString(upperFirst(part))
+	}
+	return builder.String()
+}
+
+type lowerCamelNaming struct{}
+
+// FieldToColumn implements NamingStrategy.
+// The leading run of capitals is lowered, like ID mapped to id and HTTPServer to httpServer.
+func (lowerCamelNaming) FieldToColumn(field string) string {
+	runes := []rune(field)
+	for i, r := range runes {
+		if !unicode.IsUpper(r) || i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(r)
+	}
+	return string(runes)
+}
+
+// ColumnToField implements NamingStrategy.
+func (lowerCamelNaming) ColumnToField(column string) string {
+	return upperFirst(column)
+}
+
+type exactNaming struct{}
+
+// FieldToColumn implements NamingStrategy.
+func (exactNaming) FieldToColumn(field string) string { return field }
+
+// ColumnToField implements NamingStrategy.
+func (exactNaming) ColumnToField(column string) string { return column }
+
+// upperFirst returns s with its first letter in upper case.
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"testing"
+)
+
+func TestNamingStrategies(t *testing.T) {
+	tests := []struct {
+		strategy NamingStrategy
+		field    string
+		column   string
+		back     string
+	}{
+		{SnakeCaseNaming, "UserID", "user_id", "UserId"},
+		{SnakeCaseNaming, "HTTPServer", "http_server", "HttpServer"},
+		{SnakeCaseNaming, "Address2", "address2", "Address2"},
+		{LowerCamelNaming, "UserID", "userID", "UserID"},
+		{LowerCamelNaming, "ID", "id", "Id"},
+		{LowerCamelNaming, "HTTPServer", "httpServer", "HttpServer"},
+		{ExactNaming, "UserID", "UserID", "UserID"},
+	}
+	for _, test := range tests {
+		if column := test.strategy.FieldToColumn(test.field); column != test.column {
+			t.Errorf("%T.FieldToColumn(%q) = %q, want %q", test.strategy, test.field, column, test.column)
+		}
+		if field := test.strategy.ColumnToField(test.column); field != test.back {
+			t.Errorf("%T.ColumnToField(%q) = %q, want %q", test.strategy, test.column, field, test.back)
+		}
+	}
+
+	if strategy, ok := LookupNamingStrategy("lowerCamel"); !ok || strategy != LowerCamelNaming {
+		t.Errorf("unexpected lowerCamel strategy: %v", strategy)
+	}
+	if _, ok := LookupNamingStrategy("kebab"); ok {
+		t.Error("expected no kebab strategy")
+	}
+}
+
+func TestWithNamingStrategy(t *testing.T) {
+	type account struct {
+		AccountID int64
+		FullName  string
+		Email     string `column:"mail"`
+		Ignored   string `column:"-"`
+		internal  string
+	}
+	columns := []string{"account_id", "full_name", "mail", "ignored", "internal"}
+	data := [][]any{{int64(1), "Alice", "alice@example.com", "x", "y"}}
+
+	accounts, err := List[account](NewRowsBuffer(columns, data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if accounts[0].AccountID != 0 || accounts[0].FullName != "" || accounts[0].Email != "alice@example.com" {
+		t.Fatalf("expected the tagged fields only without naming strategy, got %+v", accounts[0])
+	}
+
+	accounts, err = List[account](WithNamingStrategy(NewRowsBuffer(columns, data), SnakeCaseNaming))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := accounts[0]; got.AccountID != 1 || got.FullName != "Alice" || got.Email != "alice@example.com" || got.Ignored != "" || got.internal != "" {
+		t.Fatalf("unexpected account: %+v", got)
+	}
+
+	// strict mode sees the named fields as columns
+	rows := WithStrictColumns(WithNamingStrategy(NewRowsBuffer(columns[:3], [][]any{data[0][:3]}), SnakeCaseNaming))
+	if _, err = List[account](rows); err != nil {
+		t.Fatalf("unexpected strict error: %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// nestedStruct reports whether field is a struct, or pointer to struct, whose fields are mapped
+// as if they were fields of the outer struct, and returns the struct type and the prefix of its columns.
+// These are the untagged anonymous fields and the fields tagged with the columnPrefix option only.
+// Pointers to a struct of parents, the enclosing struct types, are not walked into, to stop on recursive types.
+func nestedStruct(field reflect.StructField, parents []reflect.Type) (reflect.Type, string, bool) {
+	tag := field.Tag.Get(columnTagName)
+	column, _ := parseColumnTag(tag)
+	prefix, prefixed := parseColumnPrefix(tag)
+	if column != "" || !field.Anonymous && !prefixed {
+		return nil, "", false
+	}
+	tp := field.Type
+	if tp.Kind() == reflect.Pointer {
+		tp = tp.Elem()
+		if slices.Contains(parents, tp) {
+			return nil, "", false
+		}
+	}
+	return tp, prefix, tp.Kind() == reflect.Struct
+}
+
+// fieldByIndex returns the nested field of rv by index,
+// allocating the embedded struct pointers it walks through when they are nil.
+func fieldByIndex(rv reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv
+}
+
+// nullableStruct is an embedded struct pointer of the destination, like the columns of a LEFT JOIN.
+// It is left nil when all the columns mapped into it are NULL.
+type nullableStruct struct {
+	// index is the field index of the pointer field.
+	index []int
+
+	// columns is the number of columns mapped into the struct.
+	columns int
+
+	// last is the position of the last column mapped into the struct.
+	last int
+
+	// field is the pointer field of the current row.
+	field reflect.Value
+
+	// nulls counts the NULL columns of the current row.
+	nulls int
+
+	// err is the first error assigning a NULL column of the current row.
+	// It is reported only if the struct is not left nil.
+	err error
+}
+
+// reset prepares the struct for the row rv, allocating its pointer.
+func (n *nullableStruct) reset(rv reflect.Value) error {
+	n.field = fieldByIndex(rv, n.index)
+	if !n.field.CanSet() {
+		return fmt.Errorf("embedded %s is unexported and cannot be allocated", n.field.Type())
+	}
+	if n.field.IsNil() {
+		n.field.Set(reflect.New(n.field.Type().Elem()))
+	}
+	n.nulls = 0
+	n.err = nil
+	return nil
+}
+
+// nullableScanner scans a column mapped into one or more nullable structs.
+type nullableScanner struct {
+	dest any
+
+	// column is the position of the column.
+	column int
+
+	// structs are the nullable structs the column is mapped into, outermost first.
+	structs []*nullableStruct
+}
+
+// Scan implements sql.Scanner.
+func (n *nullableScanner) Scan(src any) error {
+	if src != nil {
+		if err := convertAssign(n.dest, src); err != nil {
+			return err
+		}
+	} else {
+		for _, nullable := range n.structs {
+			nullable.nulls++
+		}
+		if err := convertAssign(n.dest, nil); err != nil {
+			if innermost := n.structs[len(n.structs)-1]; innermost.err == nil {
+				innermost.err = err
+			}
+		}
+	}
+	// complete the structs ending with this column, innermost first
+	for i := len(n.structs) - 1; i >= 0; i-- {
+		nullable := n.structs[i]
+		if nullable.last != n.column {
+			continue
+		}
+		if nullable.nulls == nullable.columns {
+			nullable.field.SetZero()
+			continue
+		}
+		if nullable.err != nil {
+			return nullable.err
+		}
+	}
+	return nil
+}
+
+// setNullables finds the columns mapped through embedded struct pointers of tp.
+func (s *rowDestination) setNullables(tp reflect.Type) {
+	structs := make(map[string]*nullableStruct)
+	for i, index := range s.indexes {
+		current := tp
+		var scanner *nullableScanner
+		for depth := 0; depth < len(index)-1; depth++ {
+			field := current.Field(index[depth])
+			current = field.Type
+			if current.Kind() != reflect.Pointer {
+				continue
+			}
+			current = current.Elem()
+			key := fmt.Sprint(index[:depth+1])
+			nullable, ok := structs[key]
+			if !ok {
+				nullable = &nullableStruct{index: index[:depth+1]}
+				structs[key] = nullable
+				s.nullables = append(s.nullables, nullable)
+			}
+			nullable.columns++
+			nullable.last = i
+			if scanner == nil {
+				scanner = &nullableScanner{column: i}
+			}
+			scanner.structs = append(scanner.structs, nullable)
+		}
+		if scanner == nil {
+			continue
+		}
+		if s.nullableScanners == nil {
+			s.nullableScanners = make([]*nullableScanner, len(s.indexes))
+		}
+		s.nullableScanners[i] = scanner
+	}
+}
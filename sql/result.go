@@ -67,3 +67,27 @@ func (r *BatchResult) LastInsertId() (int64, error) {
 func (r *BatchResult) RowsAffected() (int64, error) {
 	return r.totalRowsAffected, nil
 }
+
+// ReturningResult is an emulated sql.Result for statements whose affected rows
+// are returned to the caller, such as INSERT ... RETURNING on PostgreSQL.
+//
+// RowsAffected reports the number of returned rows. LastInsertId is not available,
+// the generated keys should be read from the returned rows instead.
+type ReturningResult struct {
+	rowsAffected int64
+}
+
+// LastInsertId always returns ErrLastInsertIdNotSupported.
+func (r ReturningResult) LastInsertId() (int64, error) {
+	return 0, ErrLastInsertIdNotSupported
+}
+
+// RowsAffected returns the number of rows returned by the statement.
+func (r ReturningResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+// NewReturningResult returns a ReturningResult which reports rowsAffected affected rows.
+func NewReturningResult(rowsAffected int64) ReturningResult {
+	return ReturningResult{rowsAffected: rowsAffected}
+}
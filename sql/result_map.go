@@ -58,6 +58,10 @@ func (SingleRowResultMap) MapTo(rv reflect.Value, rows Rows) error {
 		return sql.ErrNoRows
 	}
 
+	if d, ok := lookupDiscriminator(rv.Elem().Type()); ok {
+		return mapDiscriminatedRow(rv, rows, d)
+	}
+
 	if rowScanner, ok := rv.Interface().(RowScanner); ok {
 		if err := rowScanner.ScanRow(rows); err != nil {
 			return fmt.Errorf("failed to scan row using RowScanner: %w", err)
@@ -78,7 +82,7 @@ func (SingleRowResultMap) MapTo(rv reflect.Value, rows Rows) error {
 	}
 
 	// Create destination mapper
-	columnDest := &rowDestination{}
+	columnDest := newRowDestination(rows)
 
 	// Map columns to struct fields and create scan destinations
 	dest, err := columnDest.Destination(rv, columns)
@@ -104,6 +108,26 @@ func (SingleRowResultMap) MapTo(rv reflect.Value, rows Rows) error {
 	return nil
 }
 
+// mapDiscriminatedRow maps the current row into the interface rv points to, using the discriminator d.
+func mapDiscriminatedRow(rv reflect.Value, rows Rows, d *discriminator) error {
+	discriminated, err := newDiscriminatedRows(d, rows)
+	if err != nil {
+		return err
+	}
+	value, err := discriminated.scan()
+	if err != nil {
+		return err
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error occurred during row scanning: %w", err)
+	}
+	if rows.Next() {
+		return ErrTooManyRows
+	}
+	rv.Elem().Set(value)
+	return nil
+}
+
 // resultMapPreserveNilSlice is a flag that indicates whether to preserve nil slices in the result map.
 var resultMapPreserveNilSlice = os.Getenv("JUICE_RESULT_MAP_PRESERVE_NIL_SLICE") == "true"
 
@@ -124,20 +148,28 @@ func (m MultiRowsResultMap) MapTo(rv reflect.Value, rows Rows) error {
 	target := rv.Elem()
 
 	elementType := target.Type().Elem()
-	// get the element type and check if it's a pointer
-	isPointer, isElementImplementsScanner := m.resolveTypes(elementType)
 
-	// initialize element creator if not provided
-	if m.New == nil {
-		targetElementType := elementType
-		if isPointer {
-			targetElementType = targetElementType.Elem()
+	var values []reflect.Value
+	var err error
+	if d, ok := lookupDiscriminator(elementType); ok {
+		// interface elements are mapped into the concrete types chosen by the discriminator
+		values, err = m.mapWithDiscriminator(rows, d)
+	} else {
+		// get the element type and check if it's a pointer
+		isPointer, isElementImplementsScanner := m.resolveTypes(elementType)
+
+		// initialize element creator if not provided
+		if m.New == nil {
+			targetElementType := elementType
+			if isPointer {
+				targetElementType = targetElementType.Elem()
+			}
+			m.New = func() reflect.Value { return reflect.New(targetElementType) }
 		}
-		m.New = func() reflect.Value { return reflect.New(targetElementType) }
-	}
 
-	// map the rows to values
-	values, err := m.mapRows(rows, isPointer, isElementImplementsScanner)
+		// map the rows to values
+		values, err = m.mapRows(rows, isPointer, isElementImplementsScanner)
+	}
 	if err != nil {
 		return err
 	}
@@ -200,7 +232,7 @@ func (m MultiRowsResultMap) mapRows(rows Rows, isPointer bool, useScanner bool)
 // mapWithRowScanner maps rows using the RowScanner interface
 func (m MultiRowsResultMap) mapWithRowScanner(rows Rows, isPointer bool) ([]reflect.Value, error) {
 	// Pre-allocate slice with an initial capacity
-	values := make([]reflect.Value, 0, 8)
+	values := make([]reflect.Value, 0, rowsCapacity(rows))
 
 	for rows.Next() {
 		// Create a new instance. Since RowScanner is implemented with pointer receiver,
@@ -232,9 +264,9 @@ func (m MultiRowsResultMap) mapWithColumnDestination(rows Rows, isPointer bool)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get columns: %w", err)
 	}
-	columnDest := &rowDestination{}
+	columnDest := newRowDestination(rows)
 	// Pre-allocate slice with an initial capacity
-	values := make([]reflect.Value, 0, 8)
+	values := make([]reflect.Value, 0, rowsCapacity(rows))
 
 	for rows.Next() {
 		// Create a new instance and get its underlying value for column mapping
@@ -266,6 +298,26 @@ func (m MultiRowsResultMap) mapWithColumnDestination(rows Rows, isPointer bool)
 	return values, nil
 }
 
+// mapWithDiscriminator maps rows into the concrete types chosen by the discriminator d.
+func (m MultiRowsResultMap) mapWithDiscriminator(rows Rows, d *discriminator) ([]reflect.Value, error) {
+	discriminated, err := newDiscriminatedRows(d, rows)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]reflect.Value, 0, rowsCapacity(rows))
+	for rows.Next() {
+		value, err := discriminated.scan()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error occurred while iterating rows: %w", err)
+	}
+	return values, nil
+}
+
 // ColumnDestination builds scan destinations for a row.
 type ColumnDestination interface {
 	// Destination returns scan destinations for the given reflect.Value and columns.
@@ -309,6 +361,28 @@ type rowDestination struct {
 	// Before each use, it is reset (e.g., using clear or manually setting elements to nil)
 	// to ensure no stale pointers are left from previous scans.
 	dest []any
+
+	// strict makes the first mapping fail when a column has no field or a field has no column.
+	strict bool
+
+	// naming names the columns of the untagged fields, or is nil to map the tagged fields only.
+	naming NamingStrategy
+
+	// decrypters are the scan destinations of the encrypted fields, indexed like indexes.
+	// They are nil for the columns which are not encrypted, or if no column is encrypted.
+	decrypters []*decryptScanner
+
+	// nullables are the embedded struct pointers of the destination, outermost first.
+	nullables []*nullableStruct
+
+	// nullableScanners are the scan destinations of the columns mapped through nullables, indexed like indexes.
+	// They are nil for the other columns, or if the destination has no embedded struct pointer.
+	nullableScanners []*nullableScanner
+}
+
+// newRowDestination returns a rowDestination for rows, honoring their strict column mode.
+func newRowDestination(rows Rows) *rowDestination {
+	return &rowDestination{strict: isStrictColumns(rows), naming: namingStrategyOf(rows)}
 }
 
 // Destination returns scan destinations for the given reflect.Value and columns.
@@ -349,6 +423,11 @@ func (s *rowDestination) destinationForStruct(rv reflect.Value, columns []string
 	rv = reflect.Indirect(rv)
 	if len(s.indexes) == 0 {
 		s.setIndexes(rv, columns)
+		if s.strict {
+			if err := s.checkStrict(rv.Type(), columns); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// initialize dest if it's nil or clear it
@@ -358,23 +437,54 @@ func (s *rowDestination) destinationForStruct(rv reflect.Value, columns []string
 		clear(s.dest)
 	}
 
+	for _, nullable := range s.nullables {
+		if err := nullable.reset(rv); err != nil {
+			return nil, err
+		}
+	}
+
 	for i, indexes := range s.indexes {
 		if len(indexes) == 0 {
 			s.dest[i] = &s.sink
 		} else {
-			field := rv.FieldByIndex(indexes)
+			field := fieldByIndex(rv, indexes)
 			if !field.CanAddr() || !field.CanSet() {
 				return nil, fmt.Errorf("column %q maps to an unexported or unsettable field", columns[i])
 			}
 			s.dest[i] = field.Addr().Interface()
+			if s.decrypters != nil && s.decrypters[i] != nil {
+				s.decrypters[i].dest = s.dest[i]
+				s.dest[i] = s.decrypters[i]
+			}
+			if s.nullableScanners != nil && s.nullableScanners[i] != nil {
+				s.nullableScanners[i].dest = s.dest[i]
+				s.dest[i] = s.nullableScanners[i]
+			}
 		}
 	}
 	return s.dest, nil
 }
 
 // setIndexes maps result columns to struct field indexes.
+// The indexes are shared across calls through the destination index cache.
 func (s *rowDestination) setIndexes(rv reflect.Value, columns []string) {
-	tp := rv.Type()
+	s.indexes = cachedIndexes(rv.Type(), columns, s.naming, s.computeIndexes)
+	for i, indexes := range s.indexes {
+		if len(indexes) == 0 {
+			continue
+		}
+		if codec := fieldCodecName(rv.Type().FieldByIndex(indexes)); codec != "" {
+			if s.decrypters == nil {
+				s.decrypters = make([]*decryptScanner, len(s.indexes))
+			}
+			s.decrypters[i] = &decryptScanner{codec: codec}
+		}
+	}
+	s.setNullables(rv.Type())
+}
+
+// computeIndexes maps result columns to struct field indexes using reflection.
+func (s *rowDestination) computeIndexes(tp reflect.Type, columns []string) [][]int {
 	s.indexes = make([][]int, len(columns))
 
 	// columnIndex is a map to store the index of the column.
@@ -384,17 +494,22 @@ func (s *rowDestination) setIndexes(rv reflect.Value, columns []string) {
 	}
 
 	// walk into the struct
-	s.findFromStruct(tp, columnIndex, nil)
+	s.findFromStruct(tp, columnIndex, nil, nil, "")
+	return s.indexes
 }
 
 // findFromStruct finds matching field indexes in the struct type.
-func (s *rowDestination) findFromStruct(tp reflect.Type, columnIndex map[string]int, walk []int) {
+// Embedded struct pointers are walked into, unless their type is tp or one of its parents.
+// prefix is prepended to the column names of the fields, as set by the columnPrefix option of the nested structs.
+func (s *rowDestination) findFromStruct(tp reflect.Type, columnIndex map[string]int, walk []int, parents []reflect.Type, prefix string) {
 
 	// finished is a helper function to check if the indexes completed or not.
 	finished := func() bool {
 		return slices.IndexFunc(s.indexes, func(v []int) bool { return len(v) == 0 }) == -1
 	}
 
+	parents = append(parents, tp)
+
 	// walk into the struct
 	for i := 0; i < tp.NumField(); i++ {
 		// if we find all the columns destination, we can stop.
@@ -402,18 +517,19 @@ func (s *rowDestination) findFromStruct(tp reflect.Type, columnIndex map[string]
 			break
 		}
 		field := tp.Field(i)
-		tag := field.Tag.Get(columnTagName)
-		// if the tag is empty or "-", we can skip it.
-		if skip := tag == "" && !field.Anonymous || tag == "-"; skip {
+		// if the field is anonymous or has a column prefix and the type is struct or pointer to struct, we can walk into it.
+		if nested, nestedPrefix, deepScan := nestedStruct(field, parents); deepScan {
+			s.findFromStruct(nested, columnIndex, append(append([]int(nil), walk...), i), parents, prefix+nestedPrefix)
 			continue
 		}
-		// if the field is anonymous and the type is struct, we can walk into it.
-		if deepScan := field.Anonymous && field.Type.Kind() == reflect.Struct && len(tag) == 0; deepScan {
-			s.findFromStruct(field.Type, columnIndex, append(append([]int(nil), walk...), i))
+		tag, _ := parseColumnTag(field.Tag.Get(columnTagName))
+		tag = fieldColumn(field, tag, s.naming)
+		// if the tag is empty or "-", we can skip it.
+		if skip := tag == "" || tag == "-"; skip {
 			continue
 		}
 		// find the index of the column
-		index, ok := columnIndex[tag]
+		index, ok := columnIndex[prefix+tag]
 		if !ok {
 			continue
 		}
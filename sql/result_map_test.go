@@ -680,6 +680,190 @@ func BenchmarkRowDestination(b *testing.B) {
 	}
 }
 
+// BenchmarkRowDestination_NoCache measures the reflection cost the destination index cache avoids.
+func BenchmarkRowDestination_NoCache(b *testing.B) {
+	rv := reflect.ValueOf(&benchUser{})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d := &rowDestination{}
+		d.computeIndexes(rv.Elem().Type(), benchColumns)
+	}
+}
+
+func TestRowDestination_SharedIndexCache(t *testing.T) {
+	type cachedUser struct {
+		ID   int    `column:"id"`
+		Name string `column:"name"`
+	}
+	columns := []string{"name", "id", "unknown"}
+
+	first := &rowDestination{}
+	if _, err := first.Destination(reflect.ValueOf(&cachedUser{}), columns); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second := &rowDestination{}
+	if _, err := second.Destination(reflect.ValueOf(&cachedUser{}), columns); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first.indexes) != 3 || &first.indexes[0] != &second.indexes[0] {
+		t.Fatal("expected both destinations to share the cached indexes")
+	}
+
+	// a different column set is a different shape
+	third := &rowDestination{}
+	if _, err := third.Destination(reflect.ValueOf(&cachedUser{}), columns[:2]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(third.indexes) != 2 {
+		t.Fatalf("expected 2 indexes, got %d", len(third.indexes))
+	}
+}
+
+func TestMultiRowsResultMap_MapTo_EmbeddedPointer_result_map_test(t *testing.T) {
+	type Address struct {
+		City   string `column:"city"`
+		Street string `column:"street"`
+	}
+	type Location struct {
+		AddressID sql.NullInt64 `column:"address_id"`
+		*Address
+	}
+	type leftJoinUser struct {
+		ID int `column:"id"`
+		*Location
+	}
+	rows := &RowsBuffer{
+		ColumnsLine: []string{"id", "address_id", "city", "street"},
+		Data: [][]any{
+			{1, int64(10), "Paris", "Rue de Rivoli"},
+			{2, nil, nil, nil},
+			{3, int64(30), nil, nil},
+		},
+	}
+	var users []leftJoinUser
+	if err := (MultiRowsResultMap{}).MapTo(reflect.ValueOf(&users), rows); err != nil {
+		t.Fatalf("MapTo failed: %v", err)
+	}
+	if len(users) != 3 {
+		t.Fatalf("expected 3 users, got %d", len(users))
+	}
+	if users[0].Location == nil || users[0].Address == nil || users[0].AddressID.Int64 != 10 || users[0].City != "Paris" {
+		t.Errorf("unexpected users[0]: %+v", users[0])
+	}
+	if users[1].Location != nil {
+		t.Errorf("expected nil location for a row without join match, got %+v", users[1].Location)
+	}
+	if users[2].Location == nil || users[2].AddressID.Int64 != 30 || users[2].Address != nil {
+		t.Errorf("expected an allocated location without address, got %+v", users[2].Location)
+	}
+
+	// a NULL scanned into a non-nullable field is reported unless the struct is left nil
+	rows = &RowsBuffer{
+		ColumnsLine: []string{"id", "city", "street"},
+		Data:        [][]any{{1, "Paris", nil}},
+	}
+	type addressUser struct {
+		ID int `column:"id"`
+		*Address
+	}
+	var withAddress []addressUser
+	if err := (MultiRowsResultMap{}).MapTo(reflect.ValueOf(&withAddress), rows); err == nil {
+		t.Fatal("expected an error for a NULL street of a matched address")
+	}
+
+	// an unexported embedded pointer cannot be allocated
+	type address struct {
+		City string `column:"city"`
+	}
+	type unexportedUser struct {
+		ID int `column:"id"`
+		*address
+	}
+	rows = &RowsBuffer{
+		ColumnsLine: []string{"id", "city"},
+		Data:        [][]any{{1, "Paris"}},
+	}
+	var unexported []unexportedUser
+	if err := (MultiRowsResultMap{}).MapTo(reflect.ValueOf(&unexported), rows); err == nil {
+		t.Fatal("expected an error for an unexported embedded pointer")
+	}
+}
+
+func TestCheckStrict_EmbeddedPointer_result_map_test(t *testing.T) {
+	type Node struct {
+		Name string `column:"name"`
+		*Node
+	}
+	columns := structColumns(reflect.TypeOf(Node{}), nil, nil, "", nil)
+	if len(columns) != 1 || columns[0] != "name" {
+		t.Fatalf("unexpected columns: %v", columns)
+	}
+}
+
+func TestMultiRowsResultMap_MapTo_ColumnPrefix_result_map_test(t *testing.T) {
+	type Country struct {
+		Code string `column:"code"`
+	}
+	type Address struct {
+		ID      int     `column:"id"`
+		City    string  `column:"city"`
+		Country Country `column:",columnPrefix=country_"`
+	}
+	type user struct {
+		ID      int      `column:"id"`
+		Name    string   `column:"name"`
+		Address *Address `column:",columnPrefix=addr_"`
+		Billing Address  `column:",columnPrefix=bill_"`
+	}
+	columns := []string{"id", "name", "addr_id", "addr_city", "addr_country_code", "bill_id", "bill_city", "bill_country_code"}
+	rows := &RowsBuffer{
+		ColumnsLine: columns,
+		Data: [][]any{
+			{1, "Alice", 10, "Paris", "FR", 11, "Lyon", "FR"},
+			{2, "Bob", nil, nil, nil, 21, "Berlin", "DE"},
+		},
+	}
+	var users []user
+	if err := (MultiRowsResultMap{}).MapTo(reflect.ValueOf(&users), rows); err != nil {
+		t.Fatalf("MapTo failed: %v", err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(users))
+	}
+	if users[0].ID != 1 || users[0].Address == nil || users[0].Address.ID != 10 || users[0].Address.City != "Paris" || users[0].Address.Country.Code != "FR" {
+		t.Errorf("unexpected users[0]: %+v", users[0])
+	}
+	if users[0].Billing.ID != 11 || users[0].Billing.City != "Lyon" {
+		t.Errorf("unexpected users[0].Billing: %+v", users[0].Billing)
+	}
+	if users[1].Address != nil {
+		t.Errorf("expected nil address, got %+v", users[1].Address)
+	}
+	if users[1].Billing.Country.Code != "DE" {
+		t.Errorf("unexpected users[1].Billing: %+v", users[1].Billing)
+	}
+
+	if got := structColumns(reflect.TypeOf(user{}), nil, nil, "", nil); !reflect.DeepEqual(got, columns) {
+		t.Errorf("expected strict columns %v, got %v", columns, got)
+	}
+}
+
+func TestRowDestination_IndexCacheBounded(t *testing.T) {
+	type boundedUser struct {
+		ID int `column:"id"`
+	}
+	for i := range maxCachedDestinations + 1 {
+		d := &rowDestination{}
+		if _, err := d.Destination(reflect.ValueOf(&boundedUser{}), []string{"id", fmt.Sprintf("c%d", i)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if size := destinationCache.Len(); size > maxCachedDestinations {
+		t.Fatalf("expected destination cache to be bounded, got %d shapes", size)
+	}
+}
+
 // benchRow builds one row of data matching benchColumns.
 func benchRow() []any {
 	return []any{
@@ -0,0 +1,120 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrResultSetsNotSupported is returned when Rows cannot advance to another result set.
+	ErrResultSetsNotSupported = errors.New("juice: rows do not support multiple result sets")
+
+	// ErrNoMoreResultSets is returned when a binder expects another result set but none is left.
+	ErrNoMoreResultSets = errors.New("juice: no more result sets")
+)
+
+// ResultSets represents Rows that may contain more than one result set,
+// such as the result of a stored procedure or a multi-statement query.
+// *sql.Rows implements this interface.
+type ResultSets interface {
+	Rows
+
+	// NextResultSet prepares the next result set for reading. It reports whether
+	// there are further result sets, or false if there is no further result set
+	// or if there is an error advancing to it. Err should be consulted to
+	// distinguish between the two cases.
+	NextResultSet() bool
+}
+
+// Ensure *sql.Rows implements ResultSets.
+var _ ResultSets = (*sql.Rows)(nil)
+
+// AsResultSets returns rows as ResultSets.
+// It returns ErrResultSetsNotSupported when rows cannot advance to another result set.
+func AsResultSets(rows Rows) (ResultSets, error) {
+	if rows == nil {
+		return nil, ErrNilRows
+	}
+	resultSets, ok := rows.(ResultSets)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrResultSetsNotSupported, rows)
+	}
+	return resultSets, nil
+}
+
+// nextResultSet advances resultSets to the next result set.
+// It returns ErrNoMoreResultSets when there is no further result set.
+func nextResultSet(resultSets ResultSets) error {
+	if resultSets.NextResultSet() {
+		return nil
+	}
+	if err := resultSets.Err(); err != nil {
+		return fmt.Errorf("failed to advance result set: %w", err)
+	}
+	return ErrNoMoreResultSets
+}
+
+// BindMulti binds the first result set of rows to T1 and the second one to T2.
+// Rows is not closed by this function.
+//
+// Example:
+//
+//	rows, err := engine.Raw("CALL user_with_orders(#{id})").SelectMulti(ctx, juice.H{"id": 1})
+//	if err != nil {
+//	    return err
+//	}
+//	defer rows.Close()
+//
+//	user, orders, err := sql.BindMulti[User, []Order](rows)
+func BindMulti[T1, T2 any](rows Rows) (first T1, second T2, err error) {
+	resultSets, err := AsResultSets(rows)
+	if err != nil {
+		return first, second, err
+	}
+	if first, err = Bind[T1](resultSets); err != nil {
+		return first, second, fmt.Errorf("failed to bind result set 1: %w", err)
+	}
+	if err = nextResultSet(resultSets); err != nil {
+		return first, second, err
+	}
+	if second, err = Bind[T2](resultSets); err != nil {
+		return first, second, fmt.Errorf("failed to bind result set 2: %w", err)
+	}
+	return first, second, nil
+}
+
+// BindMulti3 binds the first three result sets of rows to T1, T2 and T3.
+// Rows is not closed by this function.
+func BindMulti3[T1, T2, T3 any](rows Rows) (first T1, second T2, third T3, err error) {
+	resultSets, err := AsResultSets(rows)
+	if err != nil {
+		return first, second, third, err
+	}
+	if first, second, err = BindMulti[T1, T2](resultSets); err != nil {
+		return first, second, third, err
+	}
+	if err = nextResultSet(resultSets); err != nil {
+		return first, second, third, err
+	}
+	if third, err = Bind[T3](resultSets); err != nil {
+		return first, second, third, fmt.Errorf("failed to bind result set 3: %w", err)
+	}
+	return first, second, third, nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"errors"
+	"testing"
+)
+
+type resultSetsBuffer struct {
+	*RowsBuffer
+	sets []*RowsBuffer
+}
+
+func (r *resultSetsBuffer) NextResultSet() bool {
+	if len(r.sets) == 0 {
+		return false
+	}
+	r.RowsBuffer, r.sets = r.sets[0], r.sets[1:]
+	return true
+}
+
+func newResultSetsBuffer(sets ...*RowsBuffer) *resultSetsBuffer {
+	return &resultSetsBuffer{RowsBuffer: sets[0], sets: sets[1:]}
+}
+
+func TestBindMulti_result_set_test(t *testing.T) {
+	type user struct {
+		ID   int    `column:"id"`
+		Name string `column:"name"`
+	}
+	type order struct {
+		ID     int `column:"id"`
+		Amount int `column:"amount"`
+	}
+	rows := newResultSetsBuffer(
+		NewRowsBuffer([]string{"id", "name"}, [][]any{{1, "alice"}}),
+		NewRowsBuffer([]string{"id", "amount"}, [][]any{{10, 100}, {11, 200}}),
+	)
+
+	u, orders, err := BindMulti[user, []order](rows)
+	if err != nil {
+		t.Fatalf("BindMulti error: %v", err)
+	}
+	if u.ID != 1 || u.Name != "alice" {
+		t.Errorf("unexpected user: %+v", u)
+	}
+	if len(orders) != 2 || orders[0].Amount != 100 || orders[1].Amount != 200 {
+		t.Errorf("unexpected orders: %+v", orders)
+	}
+}
+
+func TestBindMulti3_result_set_test(t *testing.T) {
+	rows := newResultSetsBuffer(
+		NewRowsBuffer([]string{"a"}, [][]any{{1}}),
+		NewRowsBuffer([]string{"b"}, [][]any{{"x"}, {"y"}}),
+		NewRowsBuffer([]string{"c"}, [][]any{{3}}),
+	)
+	a, b, c, err := BindMulti3[int, []string, int](rows)
+	if err != nil {
+		t.Fatalf("BindMulti3 error: %v", err)
+	}
+	if a != 1 || len(b) != 2 || b[1] != "y" || c != 3 {
+		t.Errorf("unexpected values: %v %v %v", a, b, c)
+	}
+}
+
+func TestBindMulti_NoMoreResultSets_result_set_test(t *testing.T) {
+	rows := newResultSetsBuffer(NewRowsBuffer([]string{"a"}, [][]any{{1}}))
+	if _, _, err := BindMulti[int, int](rows); !errors.Is(err, ErrNoMoreResultSets) {
+		t.Errorf("expected ErrNoMoreResultSets, got %v", err)
+	}
+}
+
+func TestBindMulti_NotSupported_result_set_test(t *testing.T) {
+	rows := NewRowsBuffer([]string{"a"}, [][]any{{1}})
+	if _, _, err := BindMulti[int, int](rows); !errors.Is(err, ErrResultSetsNotSupported) {
+		t.Errorf("expected ErrResultSetsNotSupported, got %v", err)
+	}
+	if _, err := AsResultSets(nil); !errors.Is(err, ErrNilRows) {
+		t.Errorf("expected ErrNilRows, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+// rowsOptions holds the binding hints attached to Rows.
+type rowsOptions struct {
+	// size is the expected number of rows, see SizeHinter.
+	size int
+
+	// strict reports whether columns must match the destination fields one to one.
+	strict bool
+
+	// naming names the columns of the untagged fields, see WithNamingStrategy.
+	naming NamingStrategy
+}
+
+// SizeHint implements SizeHinter.
+func (o rowsOptions) SizeHint() int { return o.size }
+
+// StrictColumns reports whether the rows are bound in strict column mode.
+func (o rowsOptions) StrictColumns() bool { return o.strict }
+
+// NamingStrategy returns the naming strategy of the rows, or nil if they have none.
+func (o rowsOptions) NamingStrategy() NamingStrategy { return o.naming }
+
+// optionRows attaches rowsOptions to Rows.
+type optionRows struct {
+	Rows
+	rowsOptions
+}
+
+// optionResultSets attaches rowsOptions to ResultSets,
+// keeping them usable with AsResultSets.
+type optionResultSets struct {
+	ResultSets
+	rowsOptions
+}
+
+// withRowsOptions returns rows carrying the options of rows updated by apply.
+// Rows which already carry options are re-wrapped instead of nested,
+// so that every hint stays visible on the returned value.
+func withRowsOptions(rows Rows, apply func(*rowsOptions)) Rows {
+	switch wrapped := rows.(type) {
+	case *optionRows:
+		options := wrapped.rowsOptions
+		apply(&options)
+		return &optionRows{Rows: wrapped.Rows, rowsOptions: options}
+	case *optionResultSets:
+		options := wrapped.rowsOptions
+		apply(&options)
+		return &optionResultSets{ResultSets: wrapped.ResultSets, rowsOptions: options}
+	}
+	var options rowsOptions
+	apply(&options)
+	if resultSets, ok := rows.(ResultSets); ok {
+		return &optionResultSets{ResultSets: resultSets, rowsOptions: options}
+	}
+	return &optionRows{Rows: rows, rowsOptions: options}
+}
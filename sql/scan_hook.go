@@ -0,0 +1,79 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+// ScanHook is called with the columns of the rows and the destinations of each row once scanned.
+// An error fails the Scan.
+type ScanHook func(columns []string, dest []any) error
+
+// WithScanHook returns rows calling hook after each successful Scan,
+// for example to read the values of some columns of the rows bound to a struct.
+func WithScanHook(rows Rows, hook ScanHook) Rows {
+	if rows == nil || hook == nil {
+		return rows
+	}
+	// keep the options outside, so that they stay visible on the returned value.
+	switch wrapped := rows.(type) {
+	case *optionRows:
+		return &optionRows{Rows: WithScanHook(wrapped.Rows, hook), rowsOptions: wrapped.rowsOptions}
+	case *optionResultSets:
+		hooked := WithScanHook(wrapped.ResultSets, hook).(ResultSets)
+		return &optionResultSets{ResultSets: hooked, rowsOptions: wrapped.rowsOptions}
+	}
+	hooked := &hookedRows{Rows: rows, hook: hook}
+	if resultSets, ok := rows.(ResultSets); ok {
+		return &hookedResultSets{hookedRows: hooked, resultSets: resultSets}
+	}
+	return hooked
+}
+
+// hookedRows calls its hook after each Scan.
+type hookedRows struct {
+	Rows
+	hook ScanHook
+
+	// columns are the columns of the current result set, resolved on the first Scan.
+	columns []string
+}
+
+// Scan implements Rows.
+func (r *hookedRows) Scan(dest ...any) error {
+	if err := r.Rows.Scan(dest...); err != nil {
+		return err
+	}
+	if r.columns == nil {
+		columns, err := r.Rows.Columns()
+		if err != nil {
+			return err
+		}
+		r.columns = columns
+	}
+	return r.hook(r.columns, dest)
+}
+
+// hookedResultSets is a hookedRows whose rows have several result sets,
+// whose columns are resolved again for each.
+type hookedResultSets struct {
+	*hookedRows
+	resultSets ResultSets
+}
+
+// NextResultSet implements ResultSets.
+func (r *hookedResultSets) NextResultSet() bool {
+	r.columns = nil
+	return r.resultSets.NextResultSet()
+}
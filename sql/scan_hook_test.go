@@ -0,0 +1,58 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithScanHook(t *testing.T) {
+	type user struct {
+		ID   int64  `column:"id"`
+		Name string `column:"name"`
+	}
+	rows := WithStrictColumns(NewRowsBuffer([]string{"id", "name"}, [][]any{{int64(1), "a"}, {int64(2), "b"}}))
+
+	var names []string
+	hooked := WithScanHook(rows, func(columns []string, dest []any) error {
+		if len(columns) != 2 || columns[1] != "name" {
+			t.Fatalf("unexpected columns %v", columns)
+		}
+		names = append(names, *dest[1].(*string))
+		return nil
+	})
+	if !isStrictColumns(hooked) {
+		t.Fatal("expected the options to stay visible")
+	}
+	users, err := List[user](hooked)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(users) != 2 || len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Fatalf("unexpected users %v and names %v", users, names)
+	}
+
+	want := errors.New("hook")
+	failed := WithScanHook(NewRowsBuffer([]string{"id"}, [][]any{{1}}), func([]string, []any) error { return want })
+	if _, err = List[int](failed); !errors.Is(err, want) {
+		t.Fatalf("expected the hook error, got %v", err)
+	}
+	if got := WithScanHook(rows, nil); got != rows {
+		t.Fatal("expected rows unchanged without hook")
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+const (
+	// defaultRowsCapacity is the initial capacity of the slices bound from Rows without a SizeHint.
+	defaultRowsCapacity = 8
+
+	// maxRowsCapacity bounds the initial capacity taken from a SizeHint,
+	// so that a large hint does not allocate more than a few batches upfront.
+	maxRowsCapacity = 1024
+)
+
+// SizeHinter is implemented by Rows which know how many rows they fetch at a time,
+// such as the rows of a statement with a fetch size.
+type SizeHinter interface {
+	// SizeHint returns the expected number of rows, or zero if unknown.
+	SizeHint() int
+}
+
+// WithSizeHint returns rows implementing SizeHinter with size.
+// Rows is returned as is when size is not positive.
+func WithSizeHint(rows Rows, size int) Rows {
+	if rows == nil || size <= 0 {
+		return rows
+	}
+	return withRowsOptions(rows, func(options *rowsOptions) { options.size = size })
+}
+
+// rowsCapacity returns the initial capacity of a slice bound from rows.
+func rowsCapacity(rows Rows) int {
+	hinter, ok := rows.(SizeHinter)
+	if !ok {
+		return defaultRowsCapacity
+	}
+	size := hinter.SizeHint()
+	if size <= 0 {
+		return defaultRowsCapacity
+	}
+	return min(size, maxRowsCapacity)
+}
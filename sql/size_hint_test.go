@@ -0,0 +1,73 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"testing"
+)
+
+func TestWithSizeHint(t *testing.T) {
+	rows := NewRowsBuffer([]string{"id"}, [][]any{{1}, {2}})
+
+	if got := WithSizeHint(rows, 0); got != Rows(rows) {
+		t.Fatalf("expected rows unchanged without a size")
+	}
+	if got := rowsCapacity(rows); got != defaultRowsCapacity {
+		t.Fatalf("expected capacity %d, got %d", defaultRowsCapacity, got)
+	}
+
+	hinted := WithSizeHint(rows, 100)
+	if got := hinted.(SizeHinter).SizeHint(); got != 100 {
+		t.Fatalf("expected size hint 100, got %d", got)
+	}
+	if got := rowsCapacity(hinted); got != 100 {
+		t.Fatalf("expected capacity 100, got %d", got)
+	}
+	if got := rowsCapacity(WithSizeHint(rows, 1<<20)); got != maxRowsCapacity {
+		t.Fatalf("expected capacity %d, got %d", maxRowsCapacity, got)
+	}
+	if _, err := AsResultSets(hinted); err == nil {
+		t.Fatalf("expected rows without result sets to stay so")
+	}
+
+	ids, err := List[int](hinted)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Fatalf("unexpected ids: %v", ids)
+	}
+}
+
+func TestWithSizeHintResultSets(t *testing.T) {
+	resultSets := newResultSetsBuffer(
+		NewRowsBuffer([]string{"id"}, [][]any{{1}}),
+		NewRowsBuffer([]string{"id"}, [][]any{{2}, {3}}),
+	)
+	hinted := WithSizeHint(resultSets, 16)
+	if _, ok := hinted.(SizeHinter); !ok {
+		t.Fatalf("expected SizeHinter")
+	}
+
+	first, second, err := BindMulti[[]int, []int](hinted)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first) != 1 || len(second) != 2 {
+		t.Fatalf("unexpected result sets: %v %v", first, second)
+	}
+}
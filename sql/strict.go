@@ -0,0 +1,101 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// strictColumns enables the strict column mode for all rows when JUICE_STRICT_COLUMNS is "true".
+var strictColumns = os.Getenv("JUICE_STRICT_COLUMNS") == "true"
+
+// WithStrictColumns returns rows bound in strict column mode.
+// In strict mode, binding rows into a struct fails with ErrColumnMismatch
+// when a column has no destination field or a tagged field has no matching column,
+// instead of silently discarding the column or leaving the field zero.
+func WithStrictColumns(rows Rows) Rows {
+	if rows == nil {
+		return nil
+	}
+	return withRowsOptions(rows, func(options *rowsOptions) { options.strict = true })
+}
+
+// isStrictColumns reports whether rows are bound in strict column mode.
+func isStrictColumns(rows Rows) bool {
+	if strictColumns {
+		return true
+	}
+	strict, ok := rows.(interface{ StrictColumns() bool })
+	return ok && strict.StrictColumns()
+}
+
+// checkStrict returns ErrColumnMismatch listing the columns without field
+// and the fields without column. It must be called after setIndexes.
+func (s *rowDestination) checkStrict(tp reflect.Type, columns []string) error {
+	var unmappedColumns []string
+	for i, indexes := range s.indexes {
+		if len(indexes) == 0 {
+			unmappedColumns = append(unmappedColumns, columns[i])
+		}
+	}
+
+	mapped := make(map[string]struct{}, len(columns))
+	for _, column := range columns {
+		mapped[column] = struct{}{}
+	}
+	var unmatchedFields []string
+	for _, column := range structColumns(tp, nil, nil, "", s.naming) {
+		if _, ok := mapped[column]; !ok {
+			unmatchedFields = append(unmatchedFields, column)
+		}
+	}
+
+	if len(unmappedColumns) == 0 && len(unmatchedFields) == 0 {
+		return nil
+	}
+	var details []string
+	if len(unmappedColumns) > 0 {
+		details = append(details, fmt.Sprintf("columns without field: %s", strings.Join(unmappedColumns, ", ")))
+	}
+	if len(unmatchedFields) > 0 {
+		details = append(details, fmt.Sprintf("fields without column: %s", strings.Join(unmatchedFields, ", ")))
+	}
+	return fmt.Errorf("%w: %s: %s", ErrColumnMismatch, tp, strings.Join(details, "; "))
+}
+
+// structColumns returns the column names of the tagged fields of tp,
+// following the same rules as findFromStruct.
+func structColumns(tp reflect.Type, columns []string, parents []reflect.Type, prefix string, naming NamingStrategy) []string {
+	parents = append(parents, tp)
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		if nested, nestedPrefix, deepScan := nestedStruct(field, parents); deepScan {
+			columns = structColumns(nested, columns, parents, prefix+nestedPrefix, naming)
+			continue
+		}
+		tag, _ := parseColumnTag(field.Tag.Get(columnTagName))
+		tag = fieldColumn(field, tag, naming)
+		if skip := tag == "" || tag == "-"; skip {
+			continue
+		}
+		columns = append(columns, prefix+tag)
+	}
+	return columns
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWithStrictColumns(t *testing.T) {
+	t.Run("Match", func(t *testing.T) {
+		rows := WithStrictColumns(NewRowsBuffer([]string{"id", "name"}, [][]any{{1, "Alice"}}))
+		users, err := List[TestUser](rows)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(users) != 1 || users[0].Name != "Alice" {
+			t.Errorf("unexpected users: %+v", users)
+		}
+	})
+
+	t.Run("UnmappedColumn", func(t *testing.T) {
+		rows := WithStrictColumns(NewRowsBuffer([]string{"id", "nmae"}, [][]any{{1, "Alice"}}))
+		_, err := Bind[TestUser](rows)
+		if !errors.Is(err, ErrColumnMismatch) {
+			t.Fatalf("expected ErrColumnMismatch, got %v", err)
+		}
+		message := err.Error()
+		if !strings.Contains(message, "columns without field: nmae") || !strings.Contains(message, "fields without column: name") {
+			t.Errorf("unexpected error message: %s", message)
+		}
+	})
+
+	t.Run("EmbeddedStruct", func(t *testing.T) {
+		type account struct {
+			TestUser
+			Email string `column:"email"`
+			Note  string `column:"-"`
+		}
+		rows := WithStrictColumns(NewRowsBuffer([]string{"id", "name"}, [][]any{{1, "Alice"}}))
+		_, err := Bind[account](rows)
+		if !errors.Is(err, ErrColumnMismatch) || !strings.Contains(err.Error(), "fields without column: email") {
+			t.Fatalf("expected missing email column, got %v", err)
+		}
+	})
+
+	t.Run("Iter", func(t *testing.T) {
+		rows := WithStrictColumns(NewRowsBuffer([]string{"id", "age"}, [][]any{{1, 20}}))
+		err := ForEach(rows, func(TestUser) error { return nil })
+		if !errors.Is(err, ErrColumnMismatch) {
+			t.Fatalf("expected ErrColumnMismatch, got %v", err)
+		}
+	})
+
+	t.Run("KeepsSizeHint", func(t *testing.T) {
+		rows := WithStrictColumns(WithSizeHint(NewRowsBuffer(nil, nil), 16))
+		if hinter, ok := rows.(SizeHinter); !ok || hinter.SizeHint() != 16 {
+			t.Fatalf("expected size hint 16 to survive, got %T", rows)
+		}
+		if !isStrictColumns(WithSizeHint(rows, 32)) {
+			t.Fatal("expected strict mode to survive a new size hint")
+		}
+	})
+
+	t.Run("NotStrict", func(t *testing.T) {
+		rows := NewRowsBuffer([]string{"id", "nmae"}, [][]any{{1, "Alice"}})
+		if _, err := Bind[TestUser](rows); err != nil {
+			t.Fatalf("unexpected error without strict mode: %v", err)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/go-juicedev/juice/sql"
+)
+
+// sqlCommentContextKey is the context key of the tags added by ContextWithSQLComment.
+type sqlCommentContextKey struct{}
+
+// ContextWithSQLComment returns a copy of ctx whose statements are commented by
+// SQLCommentMiddleware with the key and value pairs, like a trace id:
+//
+//	ctx = juice.ContextWithSQLComment(ctx, "traceparent", traceparent, "route", "/users")
+//
+// The pairs are added to the ones of ctx, replacing the values of the same keys.
+// It panics if a key has no value.
+func ContextWithSQLComment(ctx context.Context, pairs ...string) context.Context {
+	if len(pairs)%2 != 0 {
+		panic("juice: ContextWithSQLComment: odd number of arguments, expected key and value pairs")
+	}
+	tags := maps.Clone(sqlCommentTags(ctx))
+	if tags == nil {
+		tags = make(map[string]string, len(pairs)/2)
+	}
+	for i := 0; i < len(pairs); i += 2 {
+		tags[pairs[i]] = pairs[i+1]
+	}
+	return context.WithValue(ctx, sqlCommentContextKey{}, tags)
+}
+
+// sqlCommentTags returns the tags added to ctx by ContextWithSQLComment.
+func sqlCommentTags(ctx context.Context) map[string]string {
+	tags, _ := ctx.Value(sqlCommentContextKey{}).(map[string]string)
+	return tags
+}
+
+// sqlCommentStatementKey is the key of the statement name in the comments of SQLCommentMiddleware.
+const sqlCommentStatementKey = "statement"
+
+// ensure SQLCommentMiddleware implements Middleware.
+var _ Middleware = (*SQLCommentMiddleware)(nil) // compile time check
+
+// SQLCommentMiddleware prepends a comment to the executed queries, so that the
+// database administrators can correlate them with the application:
+//
+//	/*route='%2Fusers',statement='main.UserMapper.Find',traceparent='00-4bf9...-01'*/ SELECT ...
+//
+// The comment is made of the Tags, the name of the statement and the pairs
+// added with ContextWithSQLComment, which take precedence, in the sqlcommenter
+// format: the keys are sorted and the keys and values are URL encoded, so that
+// they can not end the comment.
+// Each distinct comment makes a distinct query for the prepared statement caches.
+type SQLCommentMiddleware struct {
+	// Tags are added to the comment of every query, like the application name.
+	Tags map[string]string
+}
+
+// QueryContext implements Middleware.
+func (m *SQLCommentMiddleware) QueryContext(statementContext *StatementContext, next QueryHandler) QueryHandler {
+	name := statementContext.Statement().Name()
+	return func(ctx context.Context, query string, args ...any) (sql.Rows, error) {
+		return next(ctx, m.comment(ctx, name)+query, args...)
+	}
+}
+
+// ExecContext implements Middleware.
+func (m *SQLCommentMiddleware) ExecContext(statementContext *StatementContext, next ExecHandler) ExecHandler {
+	name := statementContext.Statement().Name()
+	return func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+		return next(ctx, m.comment(ctx, name)+query, args...)
+	}
+}
+
+// comment returns the comment prepended to the query of the statement name executed with ctx.
+func (m *SQLCommentMiddleware) comment(ctx context.Context, name string) string {
+	tags := make(map[string]string, len(m.Tags)+1)
+	maps.Copy(tags, m.Tags)
+	if name != "" {
+		tags[sqlCommentStatementKey] = name
+	}
+	maps.Copy(tags, sqlCommentTags(ctx))
+	return formatSQLComment(tags)
+}
+
+// formatSQLComment formats tags as a sqlcommenter comment followed by a space,
+// or returns an empty string if there are no tags.
+func formatSQLComment(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	var builder strings.Builder
+	builder.WriteString("/*")
+	for i, key := range slices.Sorted(maps.Keys(tags)) {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteString(sqlCommentEscape(key))
+		builder.WriteString("='")
+		builder.WriteString(sqlCommentEscape(tags[key]))
+		builder.WriteByte('\'')
+	}
+	builder.WriteString("*/ ")
+	return builder.String()
+}
+
+// sqlCommentEscape URL encodes s, with %20 for the spaces.
+// The slashes and quotes are encoded too, so s can neither end the comment nor its value.
+func sqlCommentEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
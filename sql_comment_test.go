@@ -0,0 +1,61 @@
+package juice
+
+import (
+	"context"
+	"testing"
+
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+func TestSQLCommentMiddleware_sql_comment_test(t *testing.T) {
+	middleware := &SQLCommentMiddleware{Tags: map[string]string{"application": "billing", "route": "default"}}
+	engine := newStatementTestEngine(nil, middleware)
+
+	var queries []string
+	handler := newExecuteStatementHandler("SELECT 1", nil, engine, nil).withQueryHandler(
+		func(_ context.Context, query string, _ ...any) (jsql.Rows, error) {
+			queries = append(queries, query)
+			return jsql.NewRowsBuffer(nil, nil), nil
+		},
+	).withExecHandler(
+		func(_ context.Context, query string, _ ...any) (jsql.Result, error) {
+			queries = append(queries, query)
+			return nil, nil
+		},
+	)
+
+	ctx := ContextWithSQLComment(context.Background(), "traceparent", "00-4bf9-01", "route", "/users")
+	ctx = ContextWithSQLComment(ctx, "note", "*/ DROP TABLE user; /* it's")
+	if _, err := handler.QueryContext(ctx, shStatement{name: "main.UserMapper.Find"}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := handler.ExecContext(context.Background(), shStatement{}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"/*application='billing',note='%2A%2F%20DROP%20TABLE%20user%3B%20%2F%2A%20it%27s',route='%2Fusers'," +
+			"statement='main.UserMapper.Find',traceparent='00-4bf9-01'*/ SELECT 1",
+		"/*application='billing',route='default',statement='name'*/ SELECT 1",
+	}
+	if len(queries) != len(expected) {
+		t.Fatalf("expected %d queries, got %q", len(expected), queries)
+	}
+	for i := range expected {
+		if queries[i] != expected[i] {
+			t.Errorf("query %d: expected %q, got %q", i, expected[i], queries[i])
+		}
+	}
+}
+
+func TestFormatSQLComment_sql_comment_test(t *testing.T) {
+	if comment := formatSQLComment(nil); comment != "" {
+		t.Fatalf("expected no comment without tags, got %q", comment)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected ContextWithSQLComment to panic on a key without value")
+		}
+	}()
+	ContextWithSQLComment(context.Background(), "traceparent")
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	"github.com/go-juicedev/juice/node"
+	configparser "github.com/go-juicedev/juice/parser"
+	"github.com/go-juicedev/juice/sql"
+)
+
+// providerAttribute is the statement attribute naming the SQLProvider building its SQL.
+const providerAttribute = "provider"
+
+// SQLProvider builds the SQL of the statements declaring it with the provider attribute,
+// for the queries too complex to be written with the dynamic SQL elements:
+//
+//	<select id="Search" provider="users.Search" resultMap="user"/>
+//
+// The statements are executed like any other, with the middlewares and result mapping.
+// The soft delete and tenant filters are not applied to the provided SQL.
+type SQLProvider interface {
+	ProvideSQL(translator driver.Translator, param eval.Parameter) (query string, args []any, err error)
+}
+
+// SQLProviderFunc is an adapter to allow the use of ordinary functions as SQLProvider.
+// The placeholders of the query must be rendered with translator.
+type SQLProviderFunc func(translator driver.Translator, param eval.Parameter) (query string, args []any, err error)
+
+// ProvideSQL implements SQLProvider.
+func (f SQLProviderFunc) ProvideSQL(translator driver.Translator, param eval.Parameter) (query string, args []any, err error) {
+	return f(translator, param)
+}
+
+// SQLNodeProviderFunc is an SQLProvider building a node tree from the parameter,
+// which is rendered like the nodes of the statements written in XML.
+type SQLNodeProviderFunc func(param eval.Parameter) (node.Node, error)
+
+// ProvideSQL implements SQLProvider.
+func (f SQLNodeProviderFunc) ProvideSQL(translator driver.Translator, param eval.Parameter) (query string, args []any, err error) {
+	root, err := f(param)
+	if err != nil {
+		return "", nil, err
+	}
+	return root.Accept(translator, param)
+}
+
+var (
+	// sqlProviders is a map of SQL providers keyed by name.
+	sqlProviders = map[string]SQLProvider{}
+
+	// sqlProviderMu protects sqlProviders.
+	sqlProviderMu sync.RWMutex
+)
+
+var (
+	// ErrSQLProviderNotFound is returned when the provider of a statement is not registered.
+	ErrSQLProviderNotFound = errors.New("juice: sql provider not found")
+
+	errSQLProviderNameEmpty = errors.New("juice: sql provider name is empty")
+	errSQLProviderNil       = errors.New("juice: sql provider is nil")
+)
+
+// RegisterSQLProvider registers provider under name, like "users.Search",
+// which the statements reference with their provider attribute.
+// Providers are looked up when the statements are built, so they can be
+// registered before or after the configuration is loaded.
+func RegisterSQLProvider(name string, provider SQLProvider) error {
+	if len(name) == 0 {
+		return errSQLProviderNameEmpty
+	}
+	if provider == nil {
+		return errSQLProviderNil
+	}
+	sqlProviderMu.Lock()
+	defer sqlProviderMu.Unlock()
+	sqlProviders[name] = provider
+	return nil
+}
+
+// providerNode renders a statement with the SQLProvider registered under its name.
+type providerNode string
+
+// Accept implements node.Node.
+func (p providerNode) Accept(translator driver.Translator, param eval.Parameter) (query string, args []any, err error) {
+	sqlProviderMu.RLock()
+	provider, exists := sqlProviders[string(p)]
+	sqlProviderMu.RUnlock()
+	if !exists {
+		return "", nil, fmt.Errorf("%w: %s", ErrSQLProviderNotFound, string(p))
+	}
+	return provider.ProvideSQL(translator, param)
+}
+
+// adaptProviderStatement makes statement build its SQL with the provider named by its attribute.
+// The body of the statement must be empty, since it would never be rendered.
+// The softDelete and tenant filters added to the <where> elements of a statement can not be added
+// to the SQL of a provider, so a provider statement filtered by them, possibly through its mapper, is rejected;
+// set the attribute to "false" on the statement to opt out.
+func adaptProviderStatement(statement *mappedStatement, provider string, source []configparser.Node) error {
+	if len(source) > 0 {
+		return fmt.Errorf("statement %q: the body of a statement with %s %q must be empty", statement.id, providerAttribute, provider)
+	}
+	if softDeleteColumn(statement) != "" && statement.Action() == sql.Select {
+		return fmt.Errorf("statement %q: %s is not supported by a statement with %s %q", statement.id, softDeleteAttribute, providerAttribute, provider)
+	}
+	if tenantColumn(statement) != "" && statement.Action() != sql.Insert {
+		return fmt.Errorf("statement %q: %s is not supported by a statement with %s %q", statement.id, tenantAttribute, providerAttribute, provider)
+	}
+	statement.Nodes = node.Group{providerNode(provider)}
+	return nil
+}
@@ -0,0 +1,150 @@
+package juice
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	"github.com/go-juicedev/juice/node"
+)
+
+func registerTestSQLProvider(t *testing.T, name string, provider SQLProvider) {
+	t.Helper()
+	if err := RegisterSQLProvider(name, provider); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		sqlProviderMu.Lock()
+		delete(sqlProviders, name)
+		sqlProviderMu.Unlock()
+	})
+}
+
+func newSQLProviderTestConfiguration(t *testing.T, statements string) (Configuration, error) {
+	t.Helper()
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`
+<configuration>
+    <environments default="prod">
+        <environment id="prod"><driver>postgres</driver><dataSource>dsn</dataSource></environment>
+    </environments>
+    <mappers>
+        <mapper namespace="example.UserMapper">` + statements + `</mapper>
+    </mappers>
+</configuration>`)},
+	}
+	return NewXMLConfigurationWithFS(fsys, "juice.xml")
+}
+
+func TestSQLProviderBuildsStatement(t *testing.T) {
+	registerTestSQLProvider(t, "users.Find", SQLNodeProviderFunc(func(param eval.Parameter) (node.Node, error) {
+		query := "SELECT * FROM users WHERE id = #{id}"
+		if value, ok := param.Get("name"); ok && !value.IsZero() {
+			query += " AND name = #{name}"
+		}
+		return node.NewTextNode(query), nil
+	}))
+	registerTestSQLProvider(t, "users.Count", SQLProviderFunc(func(translator driver.Translator, param eval.Parameter) (string, []any, error) {
+		return "SELECT COUNT(*) FROM users WHERE status = " + translator.Translate("status"), []any{1}, nil
+	}))
+
+	configuration, err := newSQLProviderTestConfiguration(t, `
+            <select id="Find" provider="users.Find"/>
+            <select id="Count" provider="users.Count"/>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		id    string
+		param eval.H
+		query string
+		args  []any
+	}{
+		{"example.UserMapper.Find", eval.H{"id": 7}, "SELECT * FROM users WHERE id = $1", []any{7}},
+		{"example.UserMapper.Find", eval.H{"id": 7, "name": "a"}, "SELECT * FROM users WHERE id = $1 AND name = $2", []any{7, "a"}},
+		{"example.UserMapper.Count", nil, "SELECT COUNT(*) FROM users WHERE status = $1", []any{1}},
+	}
+	for _, tt := range tests {
+		statement, err := configuration.GetStatement(tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		query, args, err := statement.Build(driver.PostgresDriver{}.Translator(), eval.NewGenericParam(tt.param, ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if query != tt.query {
+			t.Errorf("query = %q, want %q", query, tt.query)
+		}
+		if len(args) != len(tt.args) || args[0] != tt.args[0] {
+			t.Errorf("args = %v, want %v", args, tt.args)
+		}
+	}
+}
+
+func TestSQLProviderNotFound(t *testing.T) {
+	configuration, err := newSQLProviderTestConfiguration(t, `<select id="Find" provider="users.Missing"/>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement, err := configuration.GetStatement("example.UserMapper.Find")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = statement.Build(driver.PostgresDriver{}.Translator(), eval.NewGenericParam(nil, "")); !errors.Is(err, ErrSQLProviderNotFound) {
+		t.Fatalf("expected ErrSQLProviderNotFound, got %v", err)
+	}
+}
+
+func TestSQLProviderRejectsBody(t *testing.T) {
+	_, err := newSQLProviderTestConfiguration(t, `<select id="Find" provider="users.Find">SELECT 1</select>`)
+	if err == nil || !strings.Contains(err.Error(), "must be empty") {
+		t.Fatalf("expected an error about the body, got %v", err)
+	}
+}
+
+func TestSQLProviderRejectsFilters(t *testing.T) {
+	_, err := newSQLProviderTestConfiguration(t, `<select id="Find" provider="users.Find" tenant="tenant_id"/>`)
+	if err == nil || !strings.Contains(err.Error(), "tenant is not supported") {
+		t.Fatalf("expected an error about the tenant, got %v", err)
+	}
+
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`
+<configuration>
+    <environments default="prod">
+        <environment id="prod"><driver>postgres</driver><dataSource>dsn</dataSource></environment>
+    </environments>
+    <mappers>
+        <mapper namespace="example.UserMapper" softDelete="deleted_at">
+            <select id="Find" provider="users.Find"/>
+        </mapper>
+    </mappers>
+</configuration>`)},
+	}
+	_, err = NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err == nil || !strings.Contains(err.Error(), "softDelete is not supported") {
+		t.Fatalf("expected an error about the inherited softDelete, got %v", err)
+	}
+
+	// the statements opting out of the tenant filter and the inserts, whose tenant column is injected, are accepted.
+	_, err = newSQLProviderTestConfiguration(t, `
+            <select id="Find" provider="users.Find" tenant="false"/>
+            <insert id="Save" provider="users.Save" tenant="tenant_id"/>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegisterSQLProviderValidates(t *testing.T) {
+	if err := RegisterSQLProvider("", SQLProviderFunc(nil)); !errors.Is(err, errSQLProviderNameEmpty) {
+		t.Fatalf("expected errSQLProviderNameEmpty, got %v", err)
+	}
+	if err := RegisterSQLProvider("users.Find", nil); !errors.Is(err, errSQLProviderNil) {
+		t.Fatalf("expected errSQLProviderNil, got %v", err)
+	}
+}
@@ -17,20 +17,37 @@ limitations under the License.
 package juice
 
 import (
+	"context"
 	"fmt"
 	"github.com/go-juicedev/juice/node"
 	"hash/fnv"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/go-juicedev/juice/driver"
 	"github.com/go-juicedev/juice/eval"
+	configparser "github.com/go-juicedev/juice/parser"
 	"github.com/go-juicedev/juice/sql"
 )
 
+// StatementMetadata describes a statement to the code handling it, like middlewares.
 type StatementMetadata interface {
+	// ID returns the identifier of the statement.
 	ID() string
+
+	// Name returns the name of the statement.
 	Name() string
+
+	// Attribute returns the value of the attribute key of the statement, or an empty string.
+	// Every attribute of a statement element is kept, including the ones juice does not know,
+	// so middlewares can be configured by custom attributes:
+	//
+	//	<mapper namespace="user" cacheTTL="60">
+	//	    <select id="GetByID" slowThreshold="200ms">...</select>
+	//	</mapper>
+	//
+	// The statements of a mapper inherit the attributes of the <mapper> element they do not set.
 	Attribute(key string) string
 }
 
@@ -45,6 +62,22 @@ type Statement interface {
 	StatementBuilder
 }
 
+// statementContextKey is the context key of the statement being executed.
+type statementContextKey struct{}
+
+// contextWithStatement returns a copy of ctx carrying statement.
+func contextWithStatement(ctx context.Context, statement StatementMetadata) context.Context {
+	return context.WithValue(ctx, statementContextKey{}, statement)
+}
+
+// StatementFromContext returns the statement executed with ctx.
+// The contexts received by the sessions carry their statement, so that the sessions,
+// like the mocks of juicetest, can tell which statement a query comes from.
+func StatementFromContext(ctx context.Context) (StatementMetadata, bool) {
+	statement, ok := ctx.Value(statementContextKey{}).(StatementMetadata)
+	return statement, ok
+}
+
 // mappedStatement represents a SQL statement produced from mapper configuration.
 type mappedStatement struct {
 	mapper    *Mapper
@@ -54,9 +87,46 @@ type mappedStatement struct {
 	attrs     map[string]string
 	name      string
 	id        string
+	// variants are the statements sharing the id, keyed by databaseId.
+	variants map[string]*mappedStatement
+	// static reports whether the statement renders staticQuery for every parameter.
+	static      bool
+	staticQuery string
+	// lazy compiles the nodes of the statement on first use when it is not nil.
+	lazy *lazyStatement
+	// source holds the parsed nodes of the statement, rendered by Engine.Describe.
+	source []configparser.Node
+}
+
+// lazyStatement holds the unparsed body of a statement loaded with the lazyStatements setting.
+type lazyStatement struct {
+	once sync.Once
+	load func() ([]configparser.Node, error)
+	err  error
+}
+
+// compile parses and compiles the body of a lazy statement once.
+// It is safe for concurrent use and returns the error of the compilation on every call.
+func (s *mappedStatement) compile() error {
+	lazy := s.lazy
+	if lazy == nil {
+		return nil
+	}
+	lazy.once.Do(func() {
+		nodes, err := lazy.load()
+		if err == nil {
+			err = adaptStatementNodes(s, nodes)
+		}
+		if err != nil {
+			lazy.err = fmt.Errorf("failed to compile statement %s: %w", s.Name(), err)
+		}
+		lazy.load = nil
+	})
+	return lazy.err
 }
 
-// Attribute returns the value of the attribute with the given key.
+// Attribute returns the value of the attribute with the given key,
+// falling back to the attribute of the mapper of the statement.
 func (s *mappedStatement) Attribute(key string) string {
 	value := s.attrs[key]
 	if value == "" {
@@ -103,26 +173,39 @@ func (s *mappedStatement) Action() sql.Action {
 	return s.action
 }
 
-// ResultMap returns the result mapping strategy for the statement.
+// ResultMap returns the resultMap named by the resultMap attribute of the statement,
+// declared by a <resultMap> element, or sql.ErrResultMapNotSet if the statement has none,
+// so that its rows are mapped according to their destination type.
 func (s *mappedStatement) ResultMap() (sql.ResultMap, error) {
-	// Design Decision: ResultMap is intentionally not implemented for mapped statements.
-	// Rationale:
-	//   1. Complexity: Full ResultMap implementation requires complex nested object mapping,
-	//      association handling, and discriminator logic similar to MyBatis.
-	//   2. Alternative: Users can achieve the same result using struct tags (column:"name")
-	//      which is more idiomatic in Go and provides compile-time type safety.
-	//   3. Usage: This feature is rarely needed in practice. Most use cases are covered by
-	//      simple struct field mapping via tags.
-	// If you need custom result mapping, consider implementing the sql.RowScanner interface
-	// on your struct type for full control over the scanning process.
-	return nil, sql.ErrResultMapNotSet
+	id := s.attrs[resultMapAttribute]
+	if id == "" {
+		return nil, sql.ErrResultMapNotSet
+	}
+	return s.mapper.GetResultMapByID(id)
+}
+
+// resolveStatic renders the statement once when it does not depend on its parameters,
+// so that Build can skip walking the node tree. It must be called after the nodes
+// of the statement are final.
+func (s *mappedStatement) resolveStatic() {
+	if len(s.bindNodes) > 0 || !node.IsStatic(s.Nodes) {
+		return
+	}
+	query, _, err := s.Nodes.Accept(nil, nil)
+	if err != nil {
+		return
+	}
+	s.static, s.staticQuery = true, query
 }
 
 // Build renders the mapped statement with the provided parameters.
 func (s *mappedStatement) Build(translator driver.Translator, parameter eval.Parameter) (query string, args []any, err error) {
-	parameter = s.bindNodes.ConvertParameter(parameter)
-	query, args, err = s.Nodes.Accept(translator, parameter)
-	if err != nil {
+	if err = s.compile(); err != nil {
+		return "", nil, err
+	}
+	if s.static {
+		query = s.staticQuery
+	} else if query, args, err = s.build(translator, parameter); err != nil {
 		return "", nil, err
 	}
 	if len(query) == 0 {
@@ -131,6 +214,31 @@ func (s *mappedStatement) Build(translator driver.Translator, parameter eval.Par
 	return query, args, nil
 }
 
+// build renders the node tree of the statement.
+func (s *mappedStatement) build(translator driver.Translator, parameter eval.Parameter) (query string, args []any, err error) {
+	parameter = s.bindNodes.ConvertParameter(parameter)
+	return s.Nodes.Accept(translator, parameter)
+}
+
+// rootNode implements nodeTree.
+// A lazy statement failing to compile has no nodes, its error is returned by Build.
+func (s *mappedStatement) rootNode() node.Node {
+	_ = s.compile()
+	return s.Nodes
+}
+
+// withRootNode implements nodeTree.
+func (s *mappedStatement) withRootNode(root node.Node) Statement {
+	statement := *s
+	statement.static, statement.staticQuery = false, ""
+	if group, ok := root.(node.Group); ok {
+		statement.Nodes = group
+	} else {
+		statement.Nodes = node.Group{root}
+	}
+	return &statement
+}
+
 // RawSQLStatement represents a raw SQL query with its parameters and action type.
 // It implements the Statement interface and provides methods for query execution.
 type RawSQLStatement struct {
@@ -180,8 +288,9 @@ func (s RawSQLStatement) ResultMap() (sql.ResultMap, error) {
 }
 
 // Build renders the raw SQL statement with the provided parameters.
+// Its :name parameters are bound like #{name}.
 func (s RawSQLStatement) Build(translator driver.Translator, parameter eval.Parameter) (query string, args []any, err error) {
-	query, args, err = node.NewTextNode(s.query).Accept(translator, parameter)
+	query, args, err = node.NewTextNode(expandNamedParameters(s.query)).Accept(translator, parameter)
 	if err != nil {
 		return "", nil, err
 	}
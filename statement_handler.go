@@ -24,9 +24,9 @@ import (
 	"reflect"
 	"strconv"
 
-	"github.com/go-juicedev/juice/driver"
 	"github.com/go-juicedev/juice/eval"
 	"github.com/go-juicedev/juice/internal/reflectlite"
+	"github.com/go-juicedev/juice/node"
 	"github.com/go-juicedev/juice/session"
 	"github.com/go-juicedev/juice/sql"
 )
@@ -67,6 +67,18 @@ func (s *executeStatementHandler) withExecHandler(execHandler ExecHandler) *exec
 
 // QueryContext executes a rendered SELECT query after composing middleware.
 func (s *executeStatementHandler) QueryContext(ctx context.Context, statement Statement, param eval.Param) (sql.Rows, error) {
+	if err := s.engine.inflight.acquire(); err != nil {
+		return nil, err
+	}
+	defer s.engine.inflight.release()
+
+	ctx = contextWithStatement(ctx, statement)
+
+	fetchSize, err := FetchSize(statement)
+	if err != nil {
+		return nil, err
+	}
+
 	statementContext := newStatementContext(
 		ctx,
 		s.engine,
@@ -84,13 +96,34 @@ func (s *executeStatementHandler) QueryContext(ctx context.Context, statement St
 		}
 	}
 
+	queryHandler = withExecError(statement, s.engine.driver, queryHandler)
+	queryHandler = withQueryGuard(statement, s.engine, queryHandler)
+	queryHandler = withMaxRows(statement, s.engine, queryHandler)
+	queryHandler = withFetchSize(s.engine.driver, fetchSize, queryHandler)
 	queryHandler = s.engine.middlewares.QueryContext(statementContext, queryHandler)
+	queryHandler = withKeyset(ctx, s.engine.driver, queryHandler)
+	queryHandler = withColumnMask(maskColumnsOf(s.engine), s.engine.maskFunc, queryHandler)
+	queryHandler = withStrictColumns(strictColumns(statement, s.engine), queryHandler)
+	queryHandler = withNamingStrategy(namingStrategyOf(s.engine), queryHandler)
 
-	return queryHandler(ctx, s.query, s.args...)
+	rows, err := queryHandler(ctx, s.query, s.args...)
+	if err != nil {
+		// a middleware may return the rows together with its error.
+		closeRows(rows)
+		return nil, queryError(ctx, err)
+	}
+	return rows, nil
 }
 
 // ExecContext executes a rendered non-query statement after composing middleware.
 func (s *executeStatementHandler) ExecContext(ctx context.Context, statement Statement, param eval.Param) (sql.Result, error) {
+	if err := s.engine.inflight.acquire(); err != nil {
+		return nil, err
+	}
+	defer s.engine.inflight.release()
+
+	ctx = contextWithStatement(ctx, statement)
+
 	statementContext := newStatementContext(
 		ctx,
 		s.engine,
@@ -108,9 +141,11 @@ func (s *executeStatementHandler) ExecContext(ctx context.Context, statement Sta
 		}
 	}
 
+	execHandler = withExecErrorExec(statement, s.engine.driver, execHandler)
 	execHandler = s.engine.middlewares.ExecContext(statementContext, execHandler)
 
-	return execHandler(ctx, s.query, s.args...)
+	result, err := execHandler(ctx, s.query, s.args...)
+	return result, queryError(ctx, err)
 }
 
 // newExecuteStatementHandler creates a handler for an already rendered SQL statement.
@@ -129,43 +164,99 @@ func newExecuteStatementHandler(
 }
 
 // buildStatementQuery renders the SQL query and arguments for a statement.
-func buildStatementQuery(statement Statement, cfg Configuration, driver driver.Driver, param eval.Param) (string, []any, error) {
-	parameter := buildStatementParameters(param, statement, driver.Name(), cfg)
-	return statement.Build(driver.Translator(), parameter)
+func buildStatementQuery(ctx context.Context, statement Statement, engine *Engine, param eval.Param) (string, []any, error) {
+	param, err := bindParamNames(statement, param)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(engine.paramProcessors) > 0 {
+		if param, err = engine.paramProcessors.ProcessParam(ctx, engine, statement, param); err != nil {
+			return "", nil, err
+		}
+	}
+	statement, err = interceptStatement(ctx, engine, statement)
+	if err != nil {
+		return "", nil, err
+	}
+	drv := engine.Driver()
+	parameter := buildStatementParameters(param, statement, engine.databaseID(), engine.evalFuncs())
+	tenant, err := tenantParameter(ctx, statement)
+	if err != nil {
+		return "", nil, err
+	}
+	if tenant != nil {
+		// the tenant can not be overridden by the user parameters.
+		parameter = eval.ParamGroup{tenant, parameter}
+	}
+	table, err := shardingParameter(engine, statement, param)
+	if err != nil {
+		return "", nil, err
+	}
+	if table != nil {
+		parameter = eval.ParamGroup{table, parameter}
+	}
+	translator := node.WithBuildOptions(drv.Translator(), buildOptions(statement, engine))
+	query, args, err := statement.Build(translator, parameter)
+	if err != nil {
+		return "", nil, err
+	}
+	if actionGuard(statement, engine) {
+		if err = checkStatementAction(statement, query); err != nil {
+			return "", nil, err
+		}
+	}
+	if err = checkMaxPlaceholders(statement, engine, args); err != nil {
+		return "", nil, err
+	}
+	return query, args, nil
+}
+
+// buildOptions returns the node.BuildOptions of the statement.
+// The attributes of the statement take precedence over the global settings.
+func buildOptions(statement Statement, engine *Engine) node.BuildOptions {
+	option := func(name string) StringValue {
+		return statementOption(statement, engine, name)
+	}
+	return node.BuildOptions{
+		ForbidRawSubstitution: option("forbidRawSubstitution").Bool(),
+		ExpandSlices:          option("expandSlices").Bool(),
+		EmptySliceAsNull:      option("emptySliceExpansion") == "null",
+		StrictChoose:          option("strictChoose").Bool(),
+	}
+}
+
+// statementOption returns the option of the statement with the given name:
+// its attribute, or the global setting when it has none.
+func statementOption(statement Statement, engine *Engine, name string) StringValue {
+	if attribute := statement.Attribute(name); attribute != "" {
+		return StringValue(attribute)
+	}
+	if configuration := engine.GetConfiguration(); configuration != nil {
+		return configuration.Settings().Get(name)
+	}
+	return ""
 }
 
 // preparedStatementHandler implements the StatementHandler interface.
-// It maintains a single prepared statement that can be reused if the query is the same.
-// When a different query is encountered, it closes the existing statement and creates a new one.
+// It prepares statements through an LRU cache keyed by SQL text, so that a query
+// is prepared once and reused as long as it stays in the cache.
+// The cache is shared with the transaction of the engine when it has one,
+// otherwise it is owned by the handler and closed with it.
 type preparedStatementHandler struct {
-	stmts     *stdsql.Stmt
-	lastQuery string
-	session   session.Session
-	engine    *Engine
+	statements *preparedStatementCache
+	owned      bool
+	session    session.Session
+	engine     *Engine
 }
 
-// getOrPrepare retrieves an existing prepared statement if the query matches,
-// otherwise closes the current statement (if any) and creates a new one.
+// getOrPrepare retrieves the cached prepared statement of query, preparing it if needed.
 func (s *preparedStatementHandler) getOrPrepare(ctx context.Context, query string) (*stdsql.Stmt, error) {
-	if s.stmts != nil && s.lastQuery == query {
-		return s.stmts, nil
-	}
-	// it means the prepared statement is not what we want
-	if s.stmts != nil {
-		_ = s.stmts.Close()
-	}
-	var err error
-	s.stmts, err = s.session.PrepareContext(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("prepare statement failed: %w", err)
-	}
-	s.lastQuery = query
-	return s.stmts, nil
+	return s.statements.prepare(ctx, query)
 }
 
 // QueryContext executes a query that returns rows.
 func (s *preparedStatementHandler) QueryContext(ctx context.Context, statement Statement, param eval.Param) (sql.Rows, error) {
-	query, args, err := buildStatementQuery(statement, s.engine.GetConfiguration(), s.engine.Driver(), param)
+	query, args, err := buildStatementQuery(ctx, statement, s.engine, param)
 	if err != nil {
 		return nil, err
 	}
@@ -191,7 +282,7 @@ func (s *preparedStatementHandler) QueryContext(ctx context.Context, statement S
 
 // ExecContext executes a query that doesn't return rows.
 func (s *preparedStatementHandler) ExecContext(ctx context.Context, statement Statement, param eval.Param) (result sql.Result, err error) {
-	query, args, err := buildStatementQuery(statement, s.engine.GetConfiguration(), s.engine.Driver(), param)
+	query, args, err := buildStatementQuery(ctx, statement, s.engine, param)
 	if err != nil {
 		return nil, err
 	}
@@ -217,11 +308,12 @@ func (s *preparedStatementHandler) ExecContext(ctx context.Context, statement St
 
 // Close closes all prepared statements in the pool and returns any error
 // that occurred during the process. Multiple errors are joined together.
+// Statements shared with a transaction are left open until the transaction ends.
 func (s *preparedStatementHandler) Close() error {
-	if s.stmts != nil {
-		return s.stmts.Close()
+	if !s.owned {
+		return nil
 	}
-	return nil
+	return s.statements.Close()
 }
 
 // newPreparedStatementHandler creates a new instance of preparedStatementHandler.
@@ -232,9 +324,19 @@ func newPreparedStatementHandler(
 	session session.Session,
 	engine *Engine,
 ) *preparedStatementHandler {
+	if statements := engine.preparedStatements; statements != nil && statements.session == session {
+		return &preparedStatementHandler{
+			statements: statements,
+			session:    session,
+			engine:     engine,
+		}
+	}
+	capacity := max(preparedStatementCacheSize(engine), defaultPreparedStatementCacheSize)
 	return &preparedStatementHandler{
-		session: session,
-		engine:  engine,
+		statements: newPreparedStatementCache(session, capacity),
+		owned:      true,
+		session:    session,
+		engine:     engine,
 	}
 }
 
@@ -251,7 +353,7 @@ type queryBuildStatementHandler struct {
 // processes the query through any configured middlewares, and then executes it using
 // the associated driver.
 func (s *queryBuildStatementHandler) QueryContext(ctx context.Context, statement Statement, param eval.Param) (sql.Rows, error) {
-	query, args, err := buildStatementQuery(statement, s.engine.GetConfiguration(), s.engine.Driver(), param)
+	query, args, err := buildStatementQuery(ctx, statement, s.engine, param)
 	if err != nil {
 		return nil, err
 	}
@@ -270,7 +372,7 @@ func (s *queryBuildStatementHandler) QueryContext(ctx context.Context, statement
 // within a context, and returns the result. Similar to QueryContext, it constructs
 // the SQL command, applies middlewares, and executes the command using the driver.
 func (s *queryBuildStatementHandler) ExecContext(ctx context.Context, statement Statement, param eval.Param) (sql.Result, error) {
-	query, args, err := buildStatementQuery(statement, s.engine.GetConfiguration(), s.engine.Driver(), param)
+	query, args, err := buildStatementQuery(ctx, statement, s.engine, param)
 	if err != nil {
 		return nil, err
 	}
@@ -313,7 +415,8 @@ var errInvalidParamType = errors.New("invalid param type")
 // Batch handlers detect it with errors.Is() and will:
 //  1. Collect the error using errors.Join()
 //  2. Continue to the next batch instead of stopping
-//  3. Return all collected errors at the end of batch processing
+//  3. Return all collected errors at the end of batch processing,
+//     together with the result accumulated from the successful batches
 //
 // This allows for resilient batch operations where individual batch failures
 // don't prevent the entire operation from completing. Middleware can use this
@@ -328,11 +431,36 @@ type sliceBatchStatementHandler struct {
 	batchSize int64
 }
 
-// QueryContext executes a query represented by the Statement object within a context,
-// and returns the resulting rows. It builds the query using the provided Param values,
-// processes the query through any configured middlewares, and then executes it using
-// the associated driver.
+// QueryContext executes the query in batches of batchSize elements and returns
+// the rows of all batches concatenated. Batches are queried lazily while the
+// returned rows are iterated, so only one batch result is held open at a time.
+// Batches failing with ErrBatchSkip are skipped and reported by Rows.Err.
 func (s *sliceBatchStatementHandler) QueryContext(ctx context.Context, statement Statement, param eval.Param) (sql.Rows, error) {
+	length := s.value.Len()
+	times := (length + int(s.batchSize) - 1) / int(s.batchSize)
+
+	hook := batchHookFromContext(ctx, s.engine)
+
+	if times <= 1 {
+		return queryBatch(ctx, hook, statement, 0, 1, func() (sql.Rows, error) {
+			return s.queryContext(ctx, statement, param)
+		})
+	}
+
+	preparedStmtHandler := newPreparedStatementHandler(s.session, s.engine)
+
+	open := func(index int) (sql.Rows, error) {
+		start := index * int(s.batchSize)
+		end := min((index+1)*int(s.batchSize), length)
+		batchParam := s.value.Slice(start, end).Interface()
+		return queryBatch(ctx, hook, statement, index, times, func() (sql.Rows, error) {
+			return preparedStmtHandler.QueryContext(ctx, statement, batchParam)
+		})
+	}
+	return newBatchRows(times, open, preparedStmtHandler.Close)
+}
+
+func (s *sliceBatchStatementHandler) queryContext(ctx context.Context, statement Statement, param eval.Param) (sql.Rows, error) {
 	statementHandler := newQueryBuildStatementHandler(s.engine, s.session)
 	return statementHandler.QueryContext(ctx, statement, param)
 }
@@ -349,8 +477,15 @@ func (s *sliceBatchStatementHandler) ExecContext(ctx context.Context, statement
 	}
 	times := (length + int(s.batchSize) - 1) / int(s.batchSize)
 
+	hook := batchHookFromContext(ctx, s.engine)
+
 	if times == 1 {
-		return s.execContext(ctx, statement, param)
+		exec := withBatchSavepoint(ctx, s.engine, s.session, statement, func(int) (sql.Result, error) {
+			return s.execContext(ctx, statement, param)
+		})
+		return execSingleBatch(ctx, hook, statement, func() (sql.Result, error) {
+			return exec(0)
+		})
 	}
 
 	// Create a PreparedStatementHandler for batch processing.
@@ -365,29 +500,15 @@ func (s *sliceBatchStatementHandler) ExecContext(ctx context.Context, statement
 	// Ensure all prepared statements are properly closed after use
 	defer func() { _ = preparedStmtHandler.Close() }()
 
-	var batchErrs error
-	aggregatedResult := &sql.BatchResult{}
-
-	// execute the statement in batches.
-	for i := range times {
-		start := i * int(s.batchSize)
-		end := min((i+1)*int(s.batchSize), length)
+	exec := withBatchSavepoint(ctx, s.engine, s.session, statement, func(index int) (sql.Result, error) {
+		start := index * int(s.batchSize)
+		end := min((index+1)*int(s.batchSize), length)
 		batchParam := s.value.Slice(start, end).Interface()
-		result, err := preparedStmtHandler.ExecContext(ctx, statement, batchParam)
-		if err != nil {
-			if errors.Is(err, ErrBatchSkip) {
-				batchErrs = errors.Join(batchErrs, err)
-				continue
-			}
-			return nil, err
-		}
-		aggregatedResult.AccumulateResult(result)
-	}
+		return preparedStmtHandler.ExecContext(ctx, statement, batchParam)
+	})
 
-	if batchErrs != nil {
-		return nil, batchErrs
-	}
-	return aggregatedResult, nil
+	// execute the statement in batches.
+	return execBatches(ctx, hook, statement, times, exec)
 }
 
 // newSliceBatchStatementHandler creates a new instance of sliceBatchStatementHandler.
@@ -415,11 +536,61 @@ type mapBatchStatementHandler struct {
 	batchSize int64
 }
 
-// QueryContext executes a query represented by the Statement object within a context,
-// and returns the resulting rows. It builds the query using the provided Param values,
-// processes the query through any configured middlewares, and then executes it using
-// the associated driver.
+// batchEntry returns the only key of the map and its slice or array value.
+func (s *mapBatchStatementHandler) batchEntry() (key reflect.Value, value reflect.Value, err error) {
+	mapKeys := s.value.MapKeys()
+	if len(mapKeys) != 1 {
+		return key, value, fmt.Errorf("%w: expected one key, got %d", errInvalidParamType, len(mapKeys))
+	}
+	key = mapKeys[0]
+	if key.Kind() != reflect.String {
+		return key, value, fmt.Errorf("%w: expected string key, got %s", errInvalidParamType, key.Kind())
+	}
+	value = reflectlite.Unpack(s.value.MapIndex(key))
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+	default:
+		return key, value, fmt.Errorf("%w: map value must be slice or array, got %s", errInvalidParamType, value.Kind())
+	}
+	return key, value, nil
+}
+
+// QueryContext executes the query in batches of batchSize elements of the map value
+// and returns the rows of all batches concatenated, like sliceBatchStatementHandler.QueryContext.
+// Unlike ExecContext, a map which can not be split into batches is queried as is.
 func (s *mapBatchStatementHandler) QueryContext(ctx context.Context, statement Statement, param eval.Param) (sql.Rows, error) {
+	keyValue, value, err := s.batchEntry()
+	if err != nil {
+		return s.queryContext(ctx, statement, param)
+	}
+	length := value.Len()
+	times := (length + int(s.batchSize) - 1) / int(s.batchSize)
+
+	hook := batchHookFromContext(ctx, s.engine)
+
+	if times <= 1 {
+		return queryBatch(ctx, hook, statement, 0, 1, func() (sql.Rows, error) {
+			return s.queryContext(ctx, statement, param)
+		})
+	}
+
+	preparedStmtHandler := newPreparedStatementHandler(s.session, s.engine)
+
+	batchParam := reflect.MakeMap(s.value.Type())
+	executionParam := batchParam.Interface()
+
+	open := func(index int) (sql.Rows, error) {
+		start := index * int(s.batchSize)
+		end := min((index+1)*int(s.batchSize), length)
+		batchParam.SetMapIndex(keyValue, value.Slice(start, end))
+		return queryBatch(ctx, hook, statement, index, times, func() (sql.Rows, error) {
+			return preparedStmtHandler.QueryContext(ctx, statement, executionParam)
+		})
+	}
+	return newBatchRows(times, open, preparedStmtHandler.Close)
+}
+
+func (s *mapBatchStatementHandler) queryContext(ctx context.Context, statement Statement, param eval.Param) (sql.Rows, error) {
 	statementHandler := newQueryBuildStatementHandler(s.engine, s.session)
 	return statementHandler.QueryContext(ctx, statement, param)
 }
@@ -430,20 +601,9 @@ func (s *mapBatchStatementHandler) execContext(ctx context.Context, statement St
 }
 
 func (s *mapBatchStatementHandler) ExecContext(ctx context.Context, statement Statement, param eval.Param) (sql.Result, error) {
-	mapKeys := s.value.MapKeys()
-	if len(mapKeys) != 1 {
-		return nil, fmt.Errorf("%w: expected one key, got %d", errInvalidParamType, len(mapKeys))
-	}
-	keyValue := mapKeys[0]
-	if keyValue.Kind() != reflect.String {
-		return nil, fmt.Errorf("%w: expected string key, got %s", errInvalidParamType, keyValue.Kind())
-	}
-	value := s.value.MapIndex(keyValue)
-	value = reflectlite.Unpack(value)
-	switch value.Kind() {
-	case reflect.Slice, reflect.Array:
-	default:
-		return nil, fmt.Errorf("%w: map value must be slice or array, got %s", errInvalidParamType, value.Kind())
+	keyValue, value, err := s.batchEntry()
+	if err != nil {
+		return nil, err
 	}
 	length := value.Len()
 	if length == 0 {
@@ -451,8 +611,15 @@ func (s *mapBatchStatementHandler) ExecContext(ctx context.Context, statement St
 	}
 	times := (length + int(s.batchSize) - 1) / int(s.batchSize)
 
+	hook := batchHookFromContext(ctx, s.engine)
+
 	if times == 1 {
-		return s.execContext(ctx, statement, param)
+		exec := withBatchSavepoint(ctx, s.engine, s.session, statement, func(int) (sql.Result, error) {
+			return s.execContext(ctx, statement, param)
+		})
+		return execSingleBatch(ctx, hook, statement, func() (sql.Result, error) {
+			return exec(0)
+		})
 	}
 
 	// Create a PreparedStatementHandler for batch processing.
@@ -470,29 +637,15 @@ func (s *mapBatchStatementHandler) ExecContext(ctx context.Context, statement St
 	batchParam := reflect.MakeMap(s.value.Type())
 	executionParam := batchParam.Interface()
 
-	var batchErrs error
-	aggregatedResult := &sql.BatchResult{}
-
-	// execute the statement in batches.
-	for i := range times {
-		start := i * int(s.batchSize)
-		end := min((i+1)*int(s.batchSize), length)
+	exec := withBatchSavepoint(ctx, s.engine, s.session, statement, func(index int) (sql.Result, error) {
+		start := index * int(s.batchSize)
+		end := min((index+1)*int(s.batchSize), length)
 		batchParam.SetMapIndex(keyValue, value.Slice(start, end))
-		result, err := preparedStmtHandler.ExecContext(ctx, statement, executionParam)
-		if err != nil {
-			if errors.Is(err, ErrBatchSkip) {
-				batchErrs = errors.Join(batchErrs, err)
-				continue
-			}
-			return nil, err
-		}
-		aggregatedResult.AccumulateResult(result)
-	}
+		return preparedStmtHandler.ExecContext(ctx, statement, executionParam)
+	})
 
-	if batchErrs != nil {
-		return nil, batchErrs
-	}
-	return aggregatedResult, nil
+	// execute the statement in batches.
+	return execBatches(ctx, hook, statement, times, exec)
 }
 
 // newMapBatchStatementHandler creates a new instance of mapBatchStatementHandler.
@@ -527,61 +680,102 @@ type batchStatementHandler struct {
 	session session.Session
 }
 
-// QueryContext executes a query represented by the Statement object within a context,
-// and returns the resulting rows. It builds the query using the provided Param values,
-// processes the query through any configured middlewares, and then executes it using
-// the associated driver.
-func (b *batchStatementHandler) QueryContext(ctx context.Context, statement Statement, param eval.Param) (sql.Rows, error) {
-	statementHandler := newQueryBuildStatementHandler(b.engine, b.session)
-	return statementHandler.QueryContext(ctx, statement, param)
-}
-
-// ExecContext executes a batch of SQL statements within a context. It handles
-// the execution of SQL statements in batches if the action is an Insert and a
-// batch size is specified. If the action is not an Insert or no batch size is
-// specified, it delegates to the execContext method.
-func (b *batchStatementHandler) ExecContext(ctx context.Context, statement Statement, param eval.Param) (result sql.Result, err error) {
+// batchSize returns the batch size configured by the batchSize attribute of the statement.
+// It returns 0 if no batch size is specified.
+func (b *batchStatementHandler) batchSize(statement Statement) (int64, error) {
 	batchSizeValue := statement.Attribute("batchSize")
 	if len(batchSizeValue) == 0 {
-		return b.execContext(ctx, statement, param)
+		return 0, nil
 	}
 	batchSize, err := strconv.ParseInt(batchSizeValue, 10, 64)
 	if err != nil {
-		return nil, errors.Join(err, fmt.Errorf("failed to parse batch size: %s", batchSizeValue))
+		return 0, errors.Join(err, fmt.Errorf("failed to parse batch size: %s", batchSizeValue))
 	}
 	if batchSize <= 0 {
-		return nil, errors.New("batch size must be greater than 0")
+		return 0, errors.New("batch size must be greater than 0")
 	}
+	return batchSize, nil
+}
 
-	var statementHandler StatementHandler
-
-	// ensure the param is a slice or array
+// batchHandler returns the batch statement handler for the given param.
+// It returns errSliceOrArrayRequired if the param is neither a slice, an array nor a map.
+func (b *batchStatementHandler) batchHandler(param eval.Param, batchSize int64) (StatementHandler, error) {
 	value := reflectlite.ValueOf(param)
 
 	switch value.IndirectType().Kind() {
 	case reflect.Slice, reflect.Array:
-		statementHandler = newSliceBatchStatementHandler(
+		return newSliceBatchStatementHandler(
 			b.engine,
 			b.session,
 			value.Unwrap().Value,
 			batchSize,
-		)
+		), nil
 	case reflect.Map:
-		statementHandler = newMapBatchStatementHandler(
+		return newMapBatchStatementHandler(
 			b.engine,
 			b.session,
 			value.Unwrap().Value,
 			batchSize,
-		)
+		), nil
 	default:
 		return nil, errSliceOrArrayRequired
 	}
+}
+
+// QueryContext executes a query represented by the Statement object within a context,
+// and returns the resulting rows. If a batch size is specified and the param is a slice,
+// an array or a map, the query is executed in batches and the rows are concatenated,
+// otherwise the query is executed once.
+func (b *batchStatementHandler) QueryContext(ctx context.Context, statement Statement, param eval.Param) (sql.Rows, error) {
+	batchSize, err := b.batchSize(statement)
+	if err != nil {
+		return nil, err
+	}
+	if batchSize == 0 || param == nil {
+		return b.queryContext(ctx, statement, param)
+	}
+	statementHandler, err := b.batchHandler(param, batchSize)
+	if err != nil {
+		// queries without a batchable param are executed as they are.
+		return b.queryContext(ctx, statement, param)
+	}
+	return statementHandler.QueryContext(ctx, statement, param)
+}
+
+func (b *batchStatementHandler) queryContext(ctx context.Context, statement Statement, param eval.Param) (sql.Rows, error) {
+	return b.singleHandler().QueryContext(ctx, statement, param)
+}
+
+// singleHandler returns the handler of the statements executed in one go.
+// Within a transaction caching prepared statements, they are prepared through its cache.
+func (b *batchStatementHandler) singleHandler() StatementHandler {
+	if statements := b.engine.preparedStatements; statements != nil && statements.session == b.session {
+		return newPreparedStatementHandler(b.session, b.engine)
+	}
+	return newQueryBuildStatementHandler(b.engine, b.session)
+}
+
+// ExecContext executes a batch of SQL statements within a context. It handles
+// the execution of SQL statements in batches if the action is an Insert and a
+// batch size is specified. If the action is not an Insert or no batch size is
+// specified, it delegates to the execContext method.
+func (b *batchStatementHandler) ExecContext(ctx context.Context, statement Statement, param eval.Param) (result sql.Result, err error) {
+	batchSize, err := b.batchSize(statement)
+	if err != nil {
+		return nil, err
+	}
+	if batchSize == 0 {
+		return b.execContext(ctx, statement, param)
+	}
+	statementHandler, err := b.batchHandler(param, batchSize)
+	if err != nil {
+		return nil, err
+	}
 	return statementHandler.ExecContext(ctx, statement, param)
 }
 
 func (b *batchStatementHandler) execContext(ctx context.Context, statement Statement, param eval.Param) (sql.Result, error) {
-	statementHandler := newQueryBuildStatementHandler(b.engine, b.session)
-	return statementHandler.ExecContext(ctx, statement, param)
+	return b.singleHandler().ExecContext(ctx, statement, param)
 }
 
 // newBatchStatementHandler creates a new instance of batchStatementHandler.
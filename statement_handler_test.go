@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"path"
 	"reflect"
 	"strings"
 	"sync/atomic"
@@ -14,6 +15,7 @@ import (
 
 	jdriver "github.com/go-juicedev/juice/driver"
 	"github.com/go-juicedev/juice/eval"
+	"github.com/go-juicedev/juice/node"
 	"github.com/go-juicedev/juice/session"
 	jsql "github.com/go-juicedev/juice/sql"
 )
@@ -29,6 +31,9 @@ type shSQLDriverState struct {
 	commitCalls    int
 	rollbackCalls  int
 
+	connExecQueries []string
+	lastBeginOpts   sqldriver.TxOptions
+
 	prepareErr  error
 	queryErr    error
 	execErr     error
@@ -69,16 +74,18 @@ func (c *shSQLConn) Begin() (sqldriver.Tx, error) {
 	return c.BeginTx(context.Background(), sqldriver.TxOptions{})
 }
 
-func (c *shSQLConn) BeginTx(_ context.Context, _ sqldriver.TxOptions) (sqldriver.Tx, error) {
+func (c *shSQLConn) BeginTx(_ context.Context, opts sqldriver.TxOptions) (sqldriver.Tx, error) {
 	c.state.beginCalls++
+	c.state.lastBeginOpts = opts
 	if c.state.beginErr != nil {
 		return nil, c.state.beginErr
 	}
 	return &shSQLTx{state: c.state}, nil
 }
 
-func (c *shSQLConn) ExecContext(_ context.Context, _ string, _ []sqldriver.NamedValue) (sqldriver.Result, error) {
+func (c *shSQLConn) ExecContext(_ context.Context, query string, _ []sqldriver.NamedValue) (sqldriver.Result, error) {
 	c.state.connExecCalls++
+	c.state.connExecQueries = append(c.state.connExecQueries, query)
 	if c.state.execErr != nil {
 		return nil, c.state.execErr
 	}
@@ -318,7 +325,7 @@ func TestBuildStatementQuery_statement_handler_test(t *testing.T) {
 		},
 	}
 
-	query, args, err := buildStatementQuery(stmt, nil, &jdriver.SQLiteDriver{}, map[string]any{"id": 7})
+	query, args, err := buildStatementQuery(context.Background(), stmt, newStatementTestEngine(nil), map[string]any{"id": 7})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -538,13 +545,16 @@ func TestPreparedStatementHandler_statement_handler_test(t *testing.T) {
 	if state.stmtExecCalls != 1 {
 		t.Fatalf("expected 1 stmt exec, got %d", state.stmtExecCalls)
 	}
-	if state.stmtCloseCalls < 1 {
-		t.Fatalf("expected stmt close called at least once, got %d", state.stmtCloseCalls)
+	if state.stmtCloseCalls != 0 {
+		t.Fatalf("expected cached statements to stay open, got %d closes", state.stmtCloseCalls)
 	}
 
 	if err = h.Close(); err != nil {
 		t.Fatalf("unexpected close error: %v", err)
 	}
+	if state.stmtCloseCalls != 2 {
+		t.Fatalf("expected both cached statements closed, got %d", state.stmtCloseCalls)
+	}
 
 	buildErr := errors.New("build failed")
 	errStmt := shStatement{buildFn: func(_ jdriver.Translator, _ eval.Parameter) (string, []any, error) {
@@ -761,3 +771,362 @@ func TestSliceMapAndBatchStatementHandlers_statement_handler_test(t *testing.T)
 		t.Fatalf("expected non-skip error from map batch, got %v", err)
 	}
 }
+
+func TestBatchStatementHandlerValues_statement_handler_test(t *testing.T) {
+	state := &shSQLDriverState{}
+	db := openStatementTestDB(t, state)
+
+	values, err := node.NewValuesNode("users", "user", "name, email")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var queries []string
+	var args [][]any
+	recorder := shExecRecorderMiddleware{record: func(query string, a []any) {
+		queries = append(queries, query)
+		args = append(args, a)
+	}}
+	engine := newStatementTestEngine(db, recorder)
+	stmt := shStatement{
+		action: jsql.Insert,
+		attrs:  map[string]string{"batchSize": "2"},
+		buildFn: func(translator jdriver.Translator, parameter eval.Parameter) (string, []any, error) {
+			query, args, err := values.Accept(translator, parameter)
+			return "INSERT INTO users " + query, args, err
+		},
+	}
+
+	users := []eval.H{{"name": "a", "email": "a@x"}, {"name": "b", "email": "b@x"}, {"name": "c", "email": "c@x"}}
+	if _, err = newBatchStatementHandler(engine, db).ExecContext(context.Background(), stmt, eval.H{"users": users}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantQueries := []string{
+		"INSERT INTO users (name, email) VALUES (?, ?), (?, ?)",
+		"INSERT INTO users (name, email) VALUES (?, ?)",
+	}
+	wantArgs := [][]any{{"a", "a@x", "b", "b@x"}, {"c", "c@x"}}
+	if !reflect.DeepEqual(queries, wantQueries) || !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("unexpected batches %q %v", queries, args)
+	}
+}
+
+type shExecRecorderMiddleware struct {
+	record func(query string, args []any)
+}
+
+func (m shExecRecorderMiddleware) QueryContext(_ *StatementContext, next QueryHandler) QueryHandler {
+	return next
+}
+
+func (m shExecRecorderMiddleware) ExecContext(_ *StatementContext, next ExecHandler) ExecHandler {
+	return func(ctx context.Context, query string, args ...any) (jsql.Result, error) {
+		m.record(query, args)
+		return next(ctx, query, args...)
+	}
+}
+
+type shQueryRowsMiddleware struct {
+	queryFn func(args []any) (jsql.Rows, error)
+}
+
+func (m shQueryRowsMiddleware) QueryContext(_ *StatementContext, _ QueryHandler) QueryHandler {
+	return func(_ context.Context, _ string, args ...any) (jsql.Rows, error) {
+		return m.queryFn(args)
+	}
+}
+
+func (m shQueryRowsMiddleware) ExecContext(_ *StatementContext, next ExecHandler) ExecHandler {
+	return next
+}
+
+func TestBatchStatementHandlerQueryChunks_statement_handler_test(t *testing.T) {
+	state := &shSQLDriverState{}
+	db := openStatementTestDB(t, state)
+	ctx := context.Background()
+
+	var queries [][]any
+	rowsMiddleware := shQueryRowsMiddleware{queryFn: func(args []any) (jsql.Rows, error) {
+		queries = append(queries, args)
+		data := make([][]any, 0, len(args))
+		for _, arg := range args {
+			data = append(data, []any{arg})
+		}
+		return jsql.NewRowsBuffer([]string{"id"}, data), nil
+	}}
+	engine := newStatementTestEngine(db, rowsMiddleware)
+
+	stmt := shStatement{
+		attrs: map[string]string{"batchSize": "2"},
+		buildFn: func(_ jdriver.Translator, parameter eval.Parameter) (string, []any, error) {
+			ids, ok := parameter.Get("ids")
+			if !ok {
+				ids, _ = parameter.Get("_parameter")
+			}
+			ids = reflect.ValueOf(ids.Interface())
+			args := make([]any, 0, ids.Len())
+			for i := range ids.Len() {
+				args = append(args, ids.Index(i).Interface())
+			}
+			return "SELECT id FROM t WHERE id IN (...)", args, nil
+		},
+	}
+
+	handler := newBatchStatementHandler(engine, db)
+
+	rows, err := handler.QueryContext(ctx, stmt, map[string][]int{"ids": {1, 2, 3, 4, 5}})
+	if err != nil {
+		t.Fatalf("unexpected batch map query error: %v", err)
+	}
+	ids, err := jsql.List[int](rows)
+	_ = rows.Close()
+	if err != nil {
+		t.Fatalf("unexpected list error: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []int{1, 2, 3, 4, 5}) {
+		t.Fatalf("unexpected ids: %v", ids)
+	}
+	if len(queries) != 3 {
+		t.Fatalf("expected 3 batch queries, got %d", len(queries))
+	}
+
+	queries = nil
+	rows, err = handler.QueryContext(ctx, stmt, []int{1, 2, 3})
+	if err != nil {
+		t.Fatalf("unexpected batch slice query error: %v", err)
+	}
+	ids, err = jsql.List[int](rows)
+	_ = rows.Close()
+	if err != nil {
+		t.Fatalf("unexpected list error: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []int{1, 2, 3}) || len(queries) != 2 {
+		t.Fatalf("unexpected slice batch result: ids=%v queries=%d", ids, len(queries))
+	}
+
+	queries = nil
+	rows, err = handler.QueryContext(ctx, stmt, map[string]any{"ids": []int{1, 2, 3}, "status": 1})
+	if err != nil {
+		t.Fatalf("unexpected unbatchable map query error: %v", err)
+	}
+	_ = rows.Close()
+	if len(queries) != 1 {
+		t.Fatalf("expected unbatchable map to be queried once, got %d", len(queries))
+	}
+
+	if _, err = handler.QueryContext(ctx, shStatement{attrs: map[string]string{"batchSize": "bad"}}, []int{1}); err == nil {
+		t.Fatalf("expected batch parse error")
+	}
+
+	skipErr := fmt.Errorf("skip this batch: %w", ErrBatchSkip)
+	var calls int
+	skipEngine := newStatementTestEngine(db, shQueryRowsMiddleware{queryFn: func(args []any) (jsql.Rows, error) {
+		calls++
+		if calls == 2 {
+			return nil, skipErr
+		}
+		return rowsMiddleware.queryFn(args)
+	}})
+	rows, err = newBatchStatementHandler(skipEngine, db).QueryContext(ctx, stmt, []int{1, 2, 3, 4, 5})
+	if err != nil {
+		t.Fatalf("unexpected skip query error: %v", err)
+	}
+	var got []int
+	for rows.Next() {
+		var id int
+		if err = rows.Scan(&id); err != nil {
+			t.Fatalf("unexpected scan error: %v", err)
+		}
+		got = append(got, id)
+	}
+	if !reflect.DeepEqual(got, []int{1, 2, 5}) {
+		t.Fatalf("unexpected ids after skip: %v", got)
+	}
+	if err = rows.Err(); !errors.Is(err, ErrBatchSkip) {
+		t.Fatalf("expected ErrBatchSkip from rows, got %v", err)
+	}
+	_ = rows.Close()
+
+	hardErr := errors.New("hard failure")
+	hardEngine := newStatementTestEngine(db, shQueryRowsMiddleware{queryFn: func(_ []any) (jsql.Rows, error) {
+		return nil, hardErr
+	}})
+	if _, err = newBatchStatementHandler(hardEngine, db).QueryContext(ctx, stmt, []int{1, 2, 3}); !errors.Is(err, hardErr) {
+		t.Fatalf("expected hard error, got %v", err)
+	}
+}
+
+func TestBuildStatementQueryScopedEvalFuncs_statement_handler_test(t *testing.T) {
+	stmt := shStatement{
+		buildFn: func(_ jdriver.Translator, parameter eval.Parameter) (string, []any, error) {
+			value, err := eval.Eval(`prefix(name)`, parameter)
+			if err != nil {
+				return "", nil, err
+			}
+			return "SELECT ?", []any{value.Interface()}, nil
+		},
+	}
+
+	configuration := &xmlConfiguration{settings: keyValueSettingProvider{}}
+	engine := newStatementTestEngine(nil)
+	engine.configuration = configuration
+
+	if _, _, err := buildStatementQuery(context.Background(), stmt, engine, H{"name": "a"}); err == nil {
+		t.Fatalf("expected undefined function error")
+	}
+
+	var registrar EvalFuncRegistrar = configuration
+	if err := registrar.RegisterEvalFunc("prefix", func(s string) (string, error) { return "cfg:" + s, nil }); err != nil {
+		t.Fatalf("unexpected configuration register error: %v", err)
+	}
+	_, args, err := buildStatementQuery(context.Background(), stmt, engine, H{"name": "a"})
+	if err != nil || args[0] != "cfg:a" {
+		t.Fatalf("expected configuration function, got %v err=%v", args, err)
+	}
+
+	if err = engine.RegisterEvalFunc("prefix", func(s string) (string, error) { return "engine:" + s, nil }); err != nil {
+		t.Fatalf("unexpected engine register error: %v", err)
+	}
+	_, args, err = buildStatementQuery(context.Background(), stmt, engine, H{"name": "a"})
+	if err != nil || args[0] != "engine:a" {
+		t.Fatalf("expected engine function to take precedence, got %v err=%v", args, err)
+	}
+
+	if engine.clone().funcs != engine.funcs {
+		t.Fatalf("expected clone to share eval functions")
+	}
+
+	if err = engine.RegisterEvalFunc("len", func(s string) (int, error) { return 0, nil }); !errors.Is(err, eval.ErrFuncConflict) {
+		t.Fatalf("expected eval.ErrFuncConflict, got %v", err)
+	}
+}
+
+func TestEngineRegisterEvalFuncSingleReturn_statement_handler_test(t *testing.T) {
+	stmt := shStatement{
+		buildFn: func(_ jdriver.Translator, parameter eval.Parameter) (string, []any, error) {
+			value, err := eval.Eval(`shout(name)`, parameter)
+			if err != nil {
+				return "", nil, err
+			}
+			return "SELECT ?", []any{value.Interface()}, nil
+		},
+	}
+	engine := newStatementTestEngine(nil)
+	engine.configuration = &xmlConfiguration{settings: keyValueSettingProvider{}}
+
+	if err := engine.RegisterEvalFunc("shout", strings.ToUpper); err != nil {
+		t.Fatalf("unexpected engine register error: %v", err)
+	}
+	_, args, err := buildStatementQuery(context.Background(), stmt, engine, H{"name": "a"})
+	if err != nil || args[0] != "A" {
+		t.Fatalf("expected single return engine function, got %v err=%v", args, err)
+	}
+}
+
+func TestBuildStatementQueryBuildOptions_statement_handler_test(t *testing.T) {
+	text := node.NewTextNode("SELECT * FROM ${table}")
+	stmt := shStatement{
+		attrs: map[string]string{},
+		buildFn: func(translator jdriver.Translator, parameter eval.Parameter) (string, []any, error) {
+			return text.Accept(translator, parameter)
+		},
+	}
+	engine := newStatementTestEngine(nil)
+
+	query, _, err := buildStatementQuery(context.Background(), stmt, engine, H{"table": "user"})
+	if err != nil || query != "SELECT * FROM user" {
+		t.Fatalf("expected raw substitution, got %q err=%v", query, err)
+	}
+
+	engine.configuration.(*xmlConfiguration).settings["forbidRawSubstitution"] = "true"
+	if _, _, err = buildStatementQuery(context.Background(), stmt, engine, H{"table": "user"}); !errors.Is(err, node.ErrRawSubstitutionForbidden) {
+		t.Fatalf("expected ErrRawSubstitutionForbidden, got %v", err)
+	}
+
+	// the statement attribute takes precedence over the setting.
+	stmt.attrs["forbidRawSubstitution"] = "false"
+	if _, _, err = buildStatementQuery(context.Background(), stmt, engine, H{"table": "user"}); err != nil {
+		t.Fatalf("expected statement attribute to allow raw substitution, got %v", err)
+	}
+
+	stmt.buildFn = func(translator jdriver.Translator, parameter eval.Parameter) (string, []any, error) {
+		return node.NewTextNode("id IN #{ids}").Accept(translator, parameter)
+	}
+	// slices are bound as a single argument unless their expansion is enabled.
+	if query, args, err := buildStatementQuery(context.Background(), stmt, engine, H{"ids": []int{1, 2}}); err != nil || query != "id IN ?" || len(args) != 1 {
+		t.Fatalf("expected the slice bound as one argument, got %q %v err=%v", query, args, err)
+	}
+	engine.configuration.(*xmlConfiguration).settings["expandSlices"] = "true"
+	if _, _, err = buildStatementQuery(context.Background(), stmt, engine, H{"ids": []int{}}); !errors.Is(err, node.ErrEmptySliceParameter) {
+		t.Fatalf("expected ErrEmptySliceParameter, got %v", err)
+	}
+	engine.configuration.(*xmlConfiguration).settings["emptySliceExpansion"] = "null"
+	if query, _, err = buildStatementQuery(context.Background(), stmt, engine, H{"ids": []int{}}); err != nil || query != "id IN (NULL)" {
+		t.Fatalf("expected (NULL) expansion, got %q err=%v", query, err)
+	}
+
+	when := &node.WhenNode{Nodes: node.Group{node.NewTextNode("id = #{id}")}}
+	if err = when.Parse("id > 0"); err != nil {
+		t.Fatal(err)
+	}
+	choose := node.ChooseNode{WhenNodes: []*node.WhenNode{when}}
+	stmt.buildFn = choose.Accept
+	if query, _, err = buildStatementQuery(context.Background(), stmt, engine, H{"id": 0}); err != nil || query != "" {
+		t.Fatalf("expected empty choose, got %q err=%v", query, err)
+	}
+	engine.configuration.(*xmlConfiguration).settings["strictChoose"] = "true"
+	if _, _, err = buildStatementQuery(context.Background(), stmt, engine, H{"id": 0}); !errors.Is(err, node.ErrNoChooseBranch) {
+		t.Fatalf("expected ErrNoChooseBranch, got %v", err)
+	}
+}
+
+func TestGeneratedKeysUnsupported_statement_handler_test(t *testing.T) {
+	engine := newStatementTestEngine(nil)
+	engine.driver = &jdriver.PostgresDriver{}
+	var executed bool
+	next := func(context.Context, string, ...any) (jsql.Result, error) {
+		executed = true
+		return nil, nil
+	}
+	stmt := shStatement{action: jsql.Insert, attrs: map[string]string{"useGeneratedKeys": "true"}}
+	handler := (&useGeneratedKeysMiddleware{}).ExecContext(newStatementContext(context.Background(), engine, stmt, nil, nil), next)
+	_, err := handler(context.Background(), "INSERT INTO user (name) VALUES ($1)", "a")
+	if !errors.Is(err, ErrGeneratedKeysUnsupported) || executed {
+		t.Fatalf("expected ErrGeneratedKeysUnsupported before execution, got %v executed=%v", err, executed)
+	}
+	if !strings.Contains(err.Error(), "RETURNING") {
+		t.Fatalf("expected RETURNING to be suggested, got %v", err)
+	}
+
+	// SQL Server has neither LastInsertId nor RETURNING.
+	engine.driver = &jdriver.SQLServerDriver{}
+	handler = (&useGeneratedKeysMiddleware{}).ExecContext(newStatementContext(context.Background(), engine, stmt, nil, nil), next)
+	_, err = handler(context.Background(), "INSERT INTO user (name) VALUES (@p1)", "a")
+	if !errors.Is(err, ErrGeneratedKeysUnsupported) || strings.Contains(err.Error(), "RETURNING") || executed {
+		t.Fatalf("expected ErrGeneratedKeysUnsupported without RETURNING, got %v executed=%v", err, executed)
+	}
+}
+
+func TestEngineUseFor_statement_handler_test(t *testing.T) {
+	engine := newStatementTestEngine(nil)
+	var seen []string
+	observe := shObserveMiddleware{
+		queryFn: func(ctx *StatementContext) { seen = append(seen, "query "+ctx.Statement().Name()) },
+		execFn:  func(ctx *StatementContext) { seen = append(seen, "exec "+ctx.Statement().Name()) },
+	}
+	if err := engine.UseFor("user.*", observe); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := engine.UseFor("order.[", observe); !errors.Is(err, path.ErrBadPattern) {
+		t.Fatalf("expected path.ErrBadPattern, got %v", err)
+	}
+
+	for _, name := range []string{"user.UserMapper.Find", "order.OrderMapper.Find", "users.UserMapper.Find"} {
+		statementContext := newStatementContext(context.Background(), engine, shStatement{name: name}, nil, nil)
+		engine.middlewares.QueryContext(statementContext, nil)
+		engine.middlewares.ExecContext(statementContext, nil)
+	}
+	expected := []string{"query user.UserMapper.Find", "exec user.UserMapper.Find"}
+	if !reflect.DeepEqual(seen, expected) {
+		t.Fatalf("expected %v, got %v", expected, seen)
+	}
+}
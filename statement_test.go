@@ -3,7 +3,9 @@ package juice
 import (
 	"errors"
 	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
 
 	"github.com/go-juicedev/juice/driver"
 	"github.com/go-juicedev/juice/eval"
@@ -144,3 +146,114 @@ func TestRawSQLStatement_BuildEmptyQuery_statement_test(t *testing.T) {
 		t.Fatalf("expected ErrEmptyQuery, got %v", err)
 	}
 }
+
+func TestMappedStatement_StaticBuild_statement_test(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>sqlite.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>
+		<mapper namespace="user">
+			<select id="All">SELECT * FROM user</select>
+			<select id="Find">SELECT * FROM user WHERE id = #{id}</select>
+			<select id="Recent">SELECT * FROM user <where><if test="since != nil">created_at > #{since}</if></where></select>
+			<select id="Active" softDelete="deleted_at">SELECT * FROM user <where>status = 1</where></select>
+		</mapper>
+	</mappers>
+</configuration>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for id, want := range map[string]bool{"user.All": true, "user.Find": false, "user.Recent": false, "user.Active": false} {
+		statement, err := configuration.GetStatement(id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := statement.(*mappedStatement).static; got != want {
+			t.Errorf("%s: expected static %v, got %v", id, want, got)
+		}
+	}
+
+	statement, _ := configuration.GetStatement("user.All")
+	query, args, err := statement.Build(nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected build error: %v", err)
+	}
+	if query != "SELECT * FROM user" || args != nil {
+		t.Fatalf("unexpected static build: %q %v", query, args)
+	}
+
+	// rewriting the node tree gives a statement which is rendered again.
+	rewritten := statement.(*mappedStatement).withRootNode(node.NewTextNode("SELECT id FROM user"))
+	if query, _, _ = rewritten.Build(nil, nil); query != "SELECT id FROM user" {
+		t.Fatalf("expected the rewritten statement to be rendered, got %q", query)
+	}
+}
+
+func TestMappedStatement_LazyCompile_statement_test(t *testing.T) {
+	fsys := fstest.MapFS{
+		"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>sqlite.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>
+		<mapper resource="user.xml"/>
+	</mappers>
+	<settings>
+		<setting name="lazyStatements" value="true"/>
+	</settings>
+</configuration>`)},
+		"user.xml": {Data: []byte(`<mapper namespace="user">
+	<select id="Find">SELECT * FROM user <where><if test="id > 0">id = #{id}</if></where></select>
+	<select id="Broken" softDelete="deleted_at">SELECT * FROM user WHERE id = 1</select>
+</mapper>`)},
+	}
+	configuration, err := NewXMLConfigurationWithFS(fsys, "juice.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	find, err := configuration.GetStatement("user.Find")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if statement := find.(*mappedStatement); statement.lazy == nil || statement.Nodes != nil {
+		t.Fatal("expected the statement body to be compiled on first use")
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			query, args, err := find.Build(driver.TranslateFunc(func(string) string { return "?" }), eval.NewGenericParam(eval.H{"id": 1}, ""))
+			if err != nil {
+				t.Errorf("unexpected build error: %v", err)
+				return
+			}
+			if query != "SELECT * FROM user WHERE id = ?" || len(args) != 1 {
+				t.Errorf("unexpected build result: %q %v", query, args)
+			}
+		})
+	}
+	wg.Wait()
+
+	// the errors of the body are reported by Build instead of at startup.
+	broken, err := configuration.GetStatement("user.Broken")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range 2 {
+		if _, _, err = broken.Build(nil, eval.H{}); err == nil || !strings.Contains(err.Error(), "user.Broken") {
+			t.Fatalf("expected the compile error of user.Broken, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+
+	"github.com/go-juicedev/juice/sql"
+)
+
+// strictColumnsAttribute is the statement attribute and setting enabling the strict column mode.
+const strictColumnsAttribute = "strictColumns"
+
+// strictColumns reports whether the rows of statement are bound in strict column mode.
+// The attribute of the statement takes precedence over the strictColumns setting.
+func strictColumns(statement Statement, engine *Engine) bool {
+	if attribute := statement.Attribute(strictColumnsAttribute); attribute != "" {
+		return StringValue(attribute).Bool()
+	}
+	configuration := engine.GetConfiguration()
+	if configuration == nil {
+		return false
+	}
+	return configuration.Settings().Get(strictColumnsAttribute).Bool()
+}
+
+// withStrictColumns wraps next so that its rows are bound in strict column mode,
+// see sql.WithStrictColumns.
+func withStrictColumns(strict bool, next QueryHandler) QueryHandler {
+	if !strict {
+		return next
+	}
+	return func(ctx context.Context, query string, args ...any) (sql.Rows, error) {
+		rows, err := next(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		return sql.WithStrictColumns(rows), nil
+	}
+}
@@ -0,0 +1,45 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+func TestStrictColumns_strict_columns_test(t *testing.T) {
+	engine := newStatementTestEngine(nil)
+	handler := newExecuteStatementHandler("SELECT id, nmae FROM users", nil, engine, nil).withQueryHandler(
+		func(context.Context, string, ...any) (jsql.Rows, error) {
+			return jsql.NewRowsBuffer([]string{"id", "nmae"}, [][]any{{1, "Alice"}}), nil
+		},
+	)
+	type user struct {
+		ID   int    `column:"id"`
+		Name string `column:"name"`
+	}
+	bind := func(statement Statement) error {
+		rows, err := handler.QueryContext(context.Background(), statement, nil)
+		if err != nil {
+			return err
+		}
+		_, err = jsql.Bind[user](rows)
+		return err
+	}
+
+	if err := bind(shStatement{}); err != nil {
+		t.Fatalf("unexpected error without strict mode: %v", err)
+	}
+	if err := bind(shStatement{attrs: map[string]string{"strictColumns": "true"}}); !errors.Is(err, jsql.ErrColumnMismatch) {
+		t.Fatalf("expected ErrColumnMismatch from the attribute, got %v", err)
+	}
+
+	engine.configuration = &xmlConfiguration{settings: keyValueSettingProvider{"strictColumns": "true"}}
+	if err := bind(shStatement{}); !errors.Is(err, jsql.ErrColumnMismatch) {
+		t.Fatalf("expected ErrColumnMismatch from the setting, got %v", err)
+	}
+	if err := bind(shStatement{attrs: map[string]string{"strictColumns": "false"}}); err != nil {
+		t.Fatalf("expected the attribute to override the setting, got %v", err)
+	}
+}
@@ -0,0 +1,338 @@
+/*
+Copyright 2026 eatmoreapple
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package juice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	"github.com/go-juicedev/juice/node"
+	"github.com/go-juicedev/juice/sql"
+)
+
+// tenantAttribute names the column holding the tenant of the rows.
+// It can be set on a statement or on its mapper.
+const tenantAttribute = "tenant"
+
+// TenantParamKey is the parameter holding the tenant of the context in the statements
+// with the tenant attribute. It takes precedence over the user parameters.
+const TenantParamKey = "__tenant"
+
+var (
+	// ErrTenantRequired is returned when a statement with the tenant attribute
+	// is executed with a context without tenant.
+	ErrTenantRequired = errors.New("juice: tenant is required")
+
+	// ErrTenantWhereRequired is returned when a tenant select, update or delete has no <where> element to filter.
+	ErrTenantWhereRequired = errors.New("juice: tenant statement requires a <where> element")
+
+	// ErrTenantUnsupported is returned when a tenant insert statement can not be rewritten.
+	ErrTenantUnsupported = errors.New("juice: unsupported tenant statement")
+)
+
+type tenantKey struct{}
+
+// ContextWithTenant returns a new context carrying the tenant of the current request.
+// Statements with the tenant attribute only read and write the rows of this tenant.
+func ContextWithTenant(ctx context.Context, tenant any) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant carried by ctx.
+func TenantFromContext(ctx context.Context) (any, bool) {
+	tenant := ctx.Value(tenantKey{})
+	return tenant, tenant != nil
+}
+
+// tenantColumn returns the tenant column of the statement.
+func tenantColumn(statement Statement) string {
+	return columnAttribute(statement, tenantAttribute)
+}
+
+// isWhereClause reports whether n renders a WHERE clause, possibly already filtered.
+func isWhereClause(n node.Node) bool {
+	switch n.(type) {
+	case *node.WhereNode, *node.SoftDeleteNode, *node.WhereFilterNode:
+		return true
+	default:
+		return false
+	}
+}
+
+// applyTenant rewrites the node tree of a select, update or delete statement with the tenant attribute,
+// so that its top level <where> elements only match the rows of the tenant.
+// INSERT statements are rewritten by tenantMiddleware, since their SQL is only known when rendered.
+func applyTenant(statement *mappedStatement) error {
+	column := tenantColumn(statement)
+	if column == "" || statement.Action() == sql.Insert {
+		return nil
+	}
+	filter := node.NewTextNode(column + " = #{" + TenantParamKey + "}")
+	var rewritten bool
+	for i, item := range statement.Nodes {
+		if isWhereClause(item) {
+			statement.Nodes[i] = &node.WhereFilterNode{Where: item, Filter: filter}
+			rewritten = true
+		}
+	}
+	if !rewritten {
+		return fmt.Errorf("%w: %s", ErrTenantWhereRequired, statement.Name())
+	}
+	return nil
+}
+
+// tenantParameter returns the parameter carrying the tenant of ctx for the statement.
+// It returns nil if the statement has no tenant attribute.
+func tenantParameter(ctx context.Context, statement Statement) (eval.Parameter, error) {
+	if tenantColumn(statement) == "" {
+		return nil, nil
+	}
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTenantRequired, statement.Name())
+	}
+	return eval.H{TenantParamKey: tenant}, nil
+}
+
+// ensure tenantMiddleware implements Middleware.
+var _ Middleware = (*tenantMiddleware)(nil) // compile time check
+
+// tenantMiddleware injects the tenant column into the INSERT statements with the tenant attribute.
+//
+//	INSERT INTO user (name) VALUES (?), (?) -> INSERT INTO user (name, tenant_id) VALUES (?, ?), (?, ?)
+//
+// Statements already listing the tenant column are kept as they are.
+type tenantMiddleware struct{}
+
+// QueryContext implements Middleware.
+func (t *tenantMiddleware) QueryContext(_ *StatementContext, next QueryHandler) QueryHandler {
+	return next
+}
+
+// ExecContext implements Middleware.
+func (t *tenantMiddleware) ExecContext(ctx *StatementContext, next ExecHandler) ExecHandler {
+	stmt := ctx.Statement()
+	column := tenantColumn(stmt)
+	if column == "" || stmt.Action() != sql.Insert {
+		return next
+	}
+	engine := ctx.Engine()
+	return func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+		tenant, ok := TenantFromContext(ctx)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTenantRequired, stmt.Name())
+		}
+		query, args, err := rewriteTenantInsert(query, args, column, tenant, engine.Driver().Translator())
+		if err != nil {
+			return nil, err
+		}
+		return next(ctx, query, args...)
+	}
+}
+
+// insertIntoRegexp matches the beginning of an INSERT statement.
+var insertIntoRegexp = regexp.MustCompile(`(?i)^\s*INSERT\s+INTO\s+`)
+
+// rewriteTenantInsert adds column to the column list of an INSERT ... VALUES statement,
+// and binds tenant to each row of values with the placeholders of translator.
+func rewriteTenantInsert(query string, args []any, column string, tenant any, translator driver.Translator) (string, []any, error) {
+	unsupported := func(reason string) (string, []any, error) {
+		return "", nil, fmt.Errorf("%w: %s: %s", ErrTenantUnsupported, reason, query)
+	}
+	location := insertIntoRegexp.FindStringIndex(query)
+	if location == nil {
+		return unsupported("expected INSERT INTO")
+	}
+	openColumns := indexOutsideQuotes(query, location[1], '(')
+	if openColumns < 0 || strings.ContainsFunc(strings.TrimSpace(query[location[1]:openColumns]), isSpace) {
+		return unsupported("expected a column list")
+	}
+	closeColumns := matchingParen(query, openColumns)
+	if closeColumns < 0 {
+		return unsupported("unbalanced parentheses")
+	}
+	if containsColumn(query[openColumns+1:closeColumns], column) {
+		return query, args, nil
+	}
+	values, ok := cutKeyword(query[closeColumns+1:], "VALUES")
+	if !ok {
+		return unsupported("expected VALUES")
+	}
+	// find the closing parenthesis of each row of values.
+	var rows []int
+	for position := len(query) - len(values); ; {
+		for position < len(query) && isSpace(rune(query[position])) {
+			position++
+		}
+		if position >= len(query) || query[position] != '(' {
+			break
+		}
+		closeRow := matchingParen(query, position)
+		if closeRow < 0 {
+			return unsupported("unbalanced parentheses")
+		}
+		rows = append(rows, closeRow)
+		position = closeRow + 1
+		for position < len(query) && isSpace(rune(query[position])) {
+			position++
+		}
+		if position >= len(query) || query[position] != ',' {
+			break
+		}
+		position++
+	}
+	if len(rows) == 0 {
+		return unsupported("expected rows of values")
+	}
+
+	placeholders, positional := appendedPlaceholders(translator, TenantParamKey, len(args), len(rows))
+	rewrittenArgs := slices.Clone(args)
+	var builder strings.Builder
+	builder.Grow(len(query) + len(column) + len(rows)*8)
+	builder.WriteString(query[:closeColumns])
+	builder.WriteString(", ")
+	builder.WriteString(column)
+	last := closeColumns
+	for i, closeRow := range rows {
+		builder.WriteString(query[last:closeRow])
+		builder.WriteString(", ")
+		builder.WriteString(placeholders[i])
+		last = closeRow
+		if positional {
+			// the tenant goes after the arguments of the placeholders before it.
+			index := countOutsideQuotes(query[:closeRow], '?') + i
+			rewrittenArgs = slices.Insert(rewrittenArgs, index, tenant)
+		} else {
+			rewrittenArgs = append(rewrittenArgs, tenant)
+		}
+	}
+	builder.WriteString(query[last:])
+	return builder.String(), rewrittenArgs, nil
+}
+
+// appendedPlaceholders returns count placeholders of the parameter name added to a query with argCount arguments.
+// positional reports whether the placeholders are bound by position, like "?",
+// otherwise they are numbered, like "$1", and continue the numbering of the query.
+func appendedPlaceholders(translator driver.Translator, name string, argCount, count int) (placeholders []string, positional bool) {
+	first := translator.Translate(name)
+	if first == "?" {
+		placeholders = make([]string, count)
+		for i := range placeholders {
+			placeholders[i] = first
+		}
+		return placeholders, true
+	}
+	// the first call numbers the first argument of the query, skip the existing ones.
+	placeholders = []string{first}
+	for range argCount + count - 1 {
+		placeholders = append(placeholders, translator.Translate(name))
+	}
+	return placeholders[argCount:], false
+}
+
+// containsColumn reports whether the comma separated column list contains column,
+// ignoring the case and the quotes of identifiers.
+func containsColumn(columns, column string) bool {
+	for item := range strings.SplitSeq(columns, ",") {
+		if strings.EqualFold(strings.Trim(strings.TrimSpace(item), "`\"[]"), column) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSpace reports whether r is an ASCII white space.
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' || r == '\v'
+}
+
+// skipQuoted returns the index after the quoted literal or identifier starting at start,
+// or start if there is no quote at start. Unterminated quotes extend to the end of s.
+func skipQuoted(s string, start int) int {
+	quote := s[start]
+	if quote != '\'' && quote != '"' && quote != '`' {
+		return start
+	}
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quote == '\'' {
+				i++
+			}
+		case quote:
+			// doubled quotes escape themselves.
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// indexOutsideQuotes returns the index of the first c in s from start which is not quoted, or -1.
+func indexOutsideQuotes(s string, start int, c byte) int {
+	for i := start; i < len(s); {
+		if next := skipQuoted(s, i); next != i {
+			i = next
+			continue
+		}
+		if s[i] == c {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+// countOutsideQuotes returns the number of c in s which are not quoted.
+func countOutsideQuotes(s string, c byte) int {
+	var count int
+	for i := indexOutsideQuotes(s, 0, c); i >= 0; i = indexOutsideQuotes(s, i+1, c) {
+		count++
+	}
+	return count
+}
+
+// matchingParen returns the index of the parenthesis closing the one at open, or -1.
+func matchingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); {
+		if next := skipQuoted(s, i); next != i {
+			i = next
+			continue
+		}
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+		i++
+	}
+	return -1
+}
@@ -0,0 +1,185 @@
+package juice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	jdriver "github.com/go-juicedev/juice/driver"
+	"github.com/go-juicedev/juice/eval"
+	jsql "github.com/go-juicedev/juice/sql"
+)
+
+func TestRewriteTenantInsert_tenant_test(t *testing.T) {
+	tests := []struct {
+		name       string
+		translator jdriver.Translator
+		query      string
+		args       []any
+		want       string
+		wantArgs   []any
+	}{
+		{
+			name:       "single row",
+			translator: jdriver.SQLiteDriver{}.Translator(),
+			query:      "INSERT INTO user (name, age) VALUES (?, ?)",
+			args:       []any{"a", 1},
+			want:       "INSERT INTO user (name, age, tenant_id) VALUES (?, ?, ?)",
+			wantArgs:   []any{"a", 1, "t1"},
+		},
+		{
+			name:       "multiple rows",
+			translator: jdriver.MySQLDriver{}.Translator(),
+			query:      "insert into `user`(name) values (?),\n(?) ON DUPLICATE KEY UPDATE name = VALUES(name)",
+			args:       []any{"a", "b"},
+			want:       "insert into `user`(name, tenant_id) values (?, ?),\n(?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name)",
+			wantArgs:   []any{"a", "t1", "b", "t1"},
+		},
+		{
+			name:       "quoted literals",
+			translator: jdriver.SQLiteDriver{}.Translator(),
+			query:      "INSERT INTO user (name, note) VALUES (?, 'a ) ?')",
+			args:       []any{"a"},
+			want:       "INSERT INTO user (name, note, tenant_id) VALUES (?, 'a ) ?', ?)",
+			wantArgs:   []any{"a", "t1"},
+		},
+		{
+			name:       "numbered placeholders",
+			translator: jdriver.PostgresDriver{}.Translator(),
+			query:      "INSERT INTO user (name) VALUES ($1), ($2) RETURNING id",
+			args:       []any{"a", "b"},
+			want:       "INSERT INTO user (name, tenant_id) VALUES ($1, $3), ($2, $4) RETURNING id",
+			wantArgs:   []any{"a", "b", "t1", "t1"},
+		},
+		{
+			name:       "column already listed",
+			translator: jdriver.SQLiteDriver{}.Translator(),
+			query:      `INSERT INTO user (name, "TENANT_ID") VALUES (?, ?)`,
+			args:       []any{"a", "t2"},
+			want:       `INSERT INTO user (name, "TENANT_ID") VALUES (?, ?)`,
+			wantArgs:   []any{"a", "t2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args, err := rewriteTenantInsert(tt.query, tt.args, "tenant_id", "t1", tt.translator)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Fatalf("got %q %v, want %q %v", got, args, tt.want, tt.wantArgs)
+			}
+		})
+	}
+
+	for _, query := range []string{
+		"UPDATE user SET a = 1",
+		"INSERT INTO user SELECT * FROM other",
+		"INSERT INTO user (name) SELECT name FROM other",
+		"INSERT INTO user (name VALUES (?)",
+	} {
+		if _, _, err := rewriteTenantInsert(query, nil, "tenant_id", "t1", jdriver.SQLiteDriver{}.Translator()); !errors.Is(err, ErrTenantUnsupported) {
+			t.Errorf("rewriteTenantInsert(%q): expected ErrTenantUnsupported, got %v", query, err)
+		}
+	}
+}
+
+func TestTenantMiddleware_tenant_test(t *testing.T) {
+	engine := newStatementTestEngine(nil)
+	var (
+		executed string
+		bound    []any
+	)
+	next := func(_ context.Context, query string, args ...any) (jsql.Result, error) {
+		executed, bound = query, args
+		return nil, nil
+	}
+	middleware := &tenantMiddleware{}
+
+	stmt := shStatement{action: jsql.Insert, attrs: map[string]string{"tenant": "tenant_id"}}
+	handler := middleware.ExecContext(newStatementContext(context.Background(), engine, stmt, nil, nil), next)
+	if _, err := handler(context.Background(), "INSERT INTO user (name) VALUES (?)", "a"); !errors.Is(err, ErrTenantRequired) {
+		t.Fatalf("expected ErrTenantRequired, got %v", err)
+	}
+	ctx := ContextWithTenant(context.Background(), 7)
+	if _, err := handler(ctx, "INSERT INTO user (name) VALUES (?)", "a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if executed != "INSERT INTO user (name, tenant_id) VALUES (?, ?)" || !reflect.DeepEqual(bound, []any{"a", 7}) {
+		t.Fatalf("unexpected rewritten query %q %v", executed, bound)
+	}
+
+	stmt = shStatement{action: jsql.Insert}
+	handler = middleware.ExecContext(newStatementContext(context.Background(), engine, stmt, nil, nil), next)
+	if _, err := handler(context.Background(), "INSERT INTO user (name) VALUES (?)", "a"); err != nil || executed != "INSERT INTO user (name) VALUES (?)" {
+		t.Fatalf("expected query to be kept, got %q err=%v", executed, err)
+	}
+}
+
+func TestTenantConfiguration_tenant_test(t *testing.T) {
+	newConfiguration := func(mapper string) (Configuration, error) {
+		fsys := fstest.MapFS{
+			"juice.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+	<environments default="prod">
+		<environment id="prod">
+			<dataSource>sqlite.db</dataSource>
+			<driver>sqlite3</driver>
+		</environment>
+	</environments>
+	<mappers>` + mapper + `</mappers>
+</configuration>`)},
+		}
+		return NewXMLConfigurationWithFS(fsys, "juice.xml")
+	}
+
+	configuration, err := newConfiguration(`
+		<mapper namespace="user" tenant="tenant_id" softDelete="deleted_at">
+			<select id="Find">SELECT * FROM user <where><if test="id > 0">AND id = #{id}</if></where></select>
+		</mapper>`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	statement, err := configuration.GetStatement("user.Find")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	engine := newStatementTestEngine(nil)
+	param := H{"id": 1, TenantParamKey: "spoofed"}
+	if _, _, err = buildStatementQuery(context.Background(), statement, engine, param); !errors.Is(err, ErrTenantRequired) {
+		t.Fatalf("expected ErrTenantRequired, got %v", err)
+	}
+	query, args, err := buildStatementQuery(ContextWithTenant(context.Background(), "t1"), statement, engine, param)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query != "SELECT * FROM user WHERE ((id = ?) AND deleted_at IS NULL) AND tenant_id = ?" || !reflect.DeepEqual(args, []any{1, "t1"}) {
+		t.Fatalf("unexpected query %q %v", query, args)
+	}
+
+	_, err = newConfiguration(`
+		<mapper namespace="user">
+			<delete id="Clear" tenant="tenant_id">DELETE FROM user</delete>
+		</mapper>`)
+	if !errors.Is(err, ErrTenantWhereRequired) {
+		t.Fatalf("expected ErrTenantWhereRequired, got %v", err)
+	}
+
+	configuration, err = newConfiguration(`
+		<mapper namespace="user">
+			<update id="Rename" tenant="tenant_id">UPDATE user SET name = #{name} <where>id = #{id}</where></update>
+		</mapper>`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if statement, err = configuration.GetStatement("user.Rename"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	query, args, err = statement.Build(jdriver.SQLiteDriver{}.Translator(), eval.ParamGroup{eval.H{TenantParamKey: 3}, eval.NewGenericParam(H{"id": 1, "name": "a"}, "")})
+	if err != nil || query != "UPDATE user SET name = ? WHERE (id = ?) AND tenant_id = ?" || !reflect.DeepEqual(args, []any{"a", 1, 3}) {
+		t.Fatalf("unexpected query %q %v err=%v", query, args, err)
+	}
+}